@@ -1,11 +1,12 @@
 #!/bin/bash
 # Lean gateway entrypoint for CNPG sidecar mode.
 # Handles args passed by the sidecar-injector plugin:
-#   --create-user, --start-pg, --pg-port, --cert-path, --key-file
+#   --create-user, --start-pg, --pg-port, --gateway-port, --cert-path, --key-file
 set -e
 
 CREATE_USER="true"
 PG_PORT="5432"
+GATEWAY_PORT=""
 CERT_PATH=""
 KEY_FILE=""
 # In CNPG clusters the superuser is always "postgres"
@@ -18,6 +19,7 @@ while [[ $# -gt 0 ]]; do
     --create-user) shift; CREATE_USER="$1"; shift;;
     --start-pg)    shift; shift;;  # ignored — PG managed by CNPG
     --pg-port)     shift; PG_PORT="$1"; shift;;
+    --gateway-port) shift; GATEWAY_PORT="$1"; shift;;
     --cert-path)   shift; CERT_PATH="$1"; shift;;
     --key-file)    shift; KEY_FILE="$1"; shift;;
     --owner)       shift; OWNER="$1"; shift;;
@@ -40,6 +42,14 @@ if ! [[ "$PG_PORT" =~ ^[0-9]+$ ]]; then
 fi
 jq --argjson port "$PG_PORT" '.PostgresPort = $port' "$CONFIG" > "$CONFIG.tmp" && mv "$CONFIG.tmp" "$CONFIG"
 
+if [ -n "$GATEWAY_PORT" ]; then
+  if ! [[ "$GATEWAY_PORT" =~ ^[0-9]+$ ]]; then
+    echo "ERROR: GATEWAY_PORT must be a number, got: $GATEWAY_PORT" >&2
+    exit 1
+  fi
+  jq --argjson port "$GATEWAY_PORT" '.GatewayListenPort = $port' "$CONFIG" > "$CONFIG.tmp" && mv "$CONFIG.tmp" "$CONFIG"
+fi
+
 if [ -n "$CERT_PATH" ] && [ -n "$KEY_FILE" ]; then
   jq --arg c "$CERT_PATH" --arg k "$KEY_FILE" \
     '.CertificateOptions = {"CertType":"PemFile","FilePath":$c,"KeyFilePath":$k}' \
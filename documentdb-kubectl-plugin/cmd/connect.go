@@ -0,0 +1,280 @@
+package cmd
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"os"
+	"os/exec"
+	"os/signal"
+	"path"
+	"strings"
+
+	"github.com/spf13/cobra"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/client-go/kubernetes"
+	"k8s.io/client-go/rest"
+	"k8s.io/client-go/tools/portforward"
+	"k8s.io/client-go/transport/spdy"
+
+	"github.com/documentdb/documentdb-operator/api/preview"
+)
+
+const (
+	defaultCredentialSecret = "documentdb-credentials"
+	defaultGatewayPort      = 10260
+)
+
+var (
+	portForwardFunc = forwardPort
+	runMongoshFunc  = runMongosh
+)
+
+type connectOptions struct {
+	documentDBName string
+	namespace      string
+	kubeContext    string
+	localPort      int
+	mongoshPath    string
+	tlsCAFile      string
+	mongoshArgs    []string
+}
+
+func newConnectCommand() *cobra.Command {
+	opts := &connectOptions{
+		namespace:   defaultDocumentDBNamespace,
+		mongoshPath: "mongosh",
+	}
+
+	cmd := &cobra.Command{
+		Use:   "connect [-- mongosh arguments]",
+		Short: "Open a mongosh shell on a DocumentDB deployment through a port-forward",
+		Long: `Port-forwards the gateway of the primary DocumentDB instance, reads the
+credentials Secret and launches mongosh against the forwarded port. mongosh
+prompts for the password, which is kept out of its command line.
+Arguments after -- are passed to mongosh.`,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			opts.mongoshArgs = args
+			if err := opts.complete(); err != nil {
+				return err
+			}
+			return opts.run(cmd.Context(), cmd)
+		},
+	}
+
+	cmd.Flags().StringVar(&opts.documentDBName, "documentdb", opts.documentDBName, "Name of the DocumentDB resource to connect to")
+	cmd.Flags().StringVarP(&opts.namespace, "namespace", "n", opts.namespace, "Namespace containing the DocumentDB resource")
+	cmd.Flags().StringVar(&opts.kubeContext, "context", opts.kubeContext, "Kubeconfig context to use (defaults to current context)")
+	cmd.Flags().IntVar(&opts.localPort, "local-port", 0, "Local port to forward the gateway to; 0 picks a free port")
+	cmd.Flags().StringVar(&opts.mongoshPath, "mongosh", opts.mongoshPath, "Path to the mongosh binary")
+	cmd.Flags().StringVar(&opts.tlsCAFile, "tls-ca-file", opts.tlsCAFile, "CA bundle to verify the gateway certificate with; without it the certificate is not verified")
+
+	_ = cmd.MarkFlagRequired("documentdb")
+
+	return cmd
+}
+
+func (o *connectOptions) complete() error {
+	o.documentDBName = strings.TrimSpace(o.documentDBName)
+	if o.documentDBName == "" {
+		return errors.New("--documentdb is required")
+	}
+	o.namespace = strings.TrimSpace(o.namespace)
+	if o.namespace == "" {
+		o.namespace = defaultDocumentDBNamespace
+	}
+	if o.localPort < 0 || o.localPort > 65535 {
+		return fmt.Errorf("--local-port must be between 0 and 65535, got %d", o.localPort)
+	}
+	if strings.TrimSpace(o.mongoshPath) == "" {
+		return errors.New("--mongosh must not be empty")
+	}
+	return nil
+}
+
+func (o *connectOptions) run(ctx context.Context, cmd *cobra.Command) error {
+	config, _, err := loadConfigFunc(o.kubeContext)
+	if err != nil {
+		return fmt.Errorf("failed to load kubeconfig: %w", err)
+	}
+
+	dynClient, err := dynamicClientForConfig(config)
+	if err != nil {
+		return fmt.Errorf("failed to create dynamic client: %w", err)
+	}
+	gvr := schema.GroupVersionResource{Group: documentDBGVRGroup, Version: documentDBGVRVersion, Resource: documentDBGVRResource}
+	unstructuredDoc, err := dynClient.Resource(gvr).Namespace(o.namespace).Get(ctx, o.documentDBName, metav1.GetOptions{})
+	if err != nil {
+		return fmt.Errorf("failed to get DocumentDB %q in namespace %q: %w", o.documentDBName, o.namespace, err)
+	}
+	var document preview.DocumentDB
+	if err := runtime.DefaultUnstructuredConverter.FromUnstructured(unstructuredDoc.Object, &document); err != nil {
+		return fmt.Errorf("failed to decode DocumentDB: %w", err)
+	}
+
+	clientset, err := kubernetesClientForConfig(config)
+	if err != nil {
+		return fmt.Errorf("failed to create kubernetes clientset: %w", err)
+	}
+
+	username, secretName, err := readCredentials(ctx, clientset, &document)
+	if err != nil {
+		return err
+	}
+	pod, err := findPrimaryPod(ctx, clientset, o.namespace, o.documentDBName)
+	if err != nil {
+		return err
+	}
+
+	gatewayPort := gatewayPortOf(&document)
+	localPort, stop, err := portForwardFunc(ctx, config, pod, o.localPort, gatewayPort, cmd.ErrOrStderr())
+	if err != nil {
+		return fmt.Errorf("failed to port-forward to pod %s: %w", pod.Name, err)
+	}
+	defer stop()
+
+	fmt.Fprintf(cmd.ErrOrStderr(), "Forwarding 127.0.0.1:%d to %s/%s:%d\n", localPort, o.namespace, pod.Name, gatewayPort)
+	fmt.Fprintf(cmd.ErrOrStderr(), "Enter the password of %s, read with: kubectl get secret %s -n %s -o jsonpath='{.data.password}' | base64 -d\n",
+		username, secretName, o.namespace)
+
+	return runMongoshFunc(ctx, o.mongoshPath, o.mongoshCommandArgs(localPort, username), cmd)
+}
+
+// mongoshCommandArgs returns the mongosh arguments for a connection to the
+// forwarded gateway port. The gateway certificate is not issued for 127.0.0.1,
+// so the hostname is never verified; the certificate chain is verified only
+// when a CA bundle is given. The password is left out, since the command line
+// of a process is visible to the other users of the host, for mongosh to
+// prompt for it.
+func (o *connectOptions) mongoshCommandArgs(localPort int, username string) []string {
+	query := url.Values{}
+	query.Set("directConnection", "true")
+	query.Set("authMechanism", "SCRAM-SHA-256")
+	query.Set("tls", "true")
+	if o.tlsCAFile != "" {
+		query.Set("tlsCAFile", o.tlsCAFile)
+		query.Set("tlsAllowInvalidHostnames", "true")
+	} else {
+		query.Set("tlsAllowInvalidCertificates", "true")
+	}
+	uri := url.URL{
+		Scheme:   "mongodb",
+		Host:     fmt.Sprintf("127.0.0.1:%d", localPort),
+		Path:     "/",
+		RawQuery: query.Encode(),
+	}
+
+	args := []string{uri.String(), "--username", username}
+	return append(args, o.mongoshArgs...)
+}
+
+// gatewayPortOf returns the port the gateway of document listens on: the one
+// of its spec, else the one reported for the local cluster in its status, else
+// the default of the operator.
+func gatewayPortOf(document *preview.DocumentDB) int {
+	if document.Spec.Gateway != nil && document.Spec.Gateway.Port != 0 {
+		return int(document.Spec.Gateway.Port)
+	}
+	for _, endpoint := range document.Status.Endpoints {
+		if endpoint.Local && endpoint.Port != 0 {
+			return int(endpoint.Port)
+		}
+	}
+	return defaultGatewayPort
+}
+
+// readCredentials returns the username of the credentials Secret of document
+// and the name of the Secret.
+func readCredentials(ctx context.Context, clientset kubernetes.Interface, document *preview.DocumentDB) (string, string, error) {
+	secretName := document.Spec.DocumentDbCredentialSecret
+	if secretName == "" {
+		secretName = defaultCredentialSecret
+	}
+	secret, err := clientset.CoreV1().Secrets(document.Namespace).Get(ctx, secretName, metav1.GetOptions{})
+	if err != nil {
+		return "", "", fmt.Errorf("failed to get credentials Secret %q: %w", secretName, err)
+	}
+	username := string(secret.Data["username"])
+	if username == "" || len(secret.Data["password"]) == 0 {
+		return "", "", fmt.Errorf("credentials Secret %q must contain username and password", secretName)
+	}
+	return username, secretName, nil
+}
+
+func findPrimaryPod(ctx context.Context, clientset kubernetes.Interface, namespace, documentName string) (*corev1.Pod, error) {
+	selector := fmt.Sprintf("app=%s,cnpg.io/instanceRole=primary", documentName)
+	pods, err := clientset.CoreV1().Pods(namespace).List(ctx, metav1.ListOptions{LabelSelector: selector})
+	if err != nil {
+		return nil, fmt.Errorf("failed to list pods: %w", err)
+	}
+	for idx := range pods.Items {
+		if pods.Items[idx].Status.Phase == corev1.PodRunning {
+			return &pods.Items[idx], nil
+		}
+	}
+	return nil, fmt.Errorf("no running primary pod found for DocumentDB %s/%s", namespace, documentName)
+}
+
+// forwardPort forwards localPort to remotePort of pod until the returned stop
+// function is called, and returns the local port in use.
+func forwardPort(ctx context.Context, config *rest.Config, pod *corev1.Pod, localPort, remotePort int, errOut io.Writer) (int, func(), error) {
+	transport, upgrader, err := spdy.RoundTripperFor(config)
+	if err != nil {
+		return 0, nil, err
+	}
+	serverURL, _, err := rest.DefaultServerUrlFor(config)
+	if err != nil {
+		return 0, nil, err
+	}
+	serverURL.Path = path.Join(serverURL.Path, "api", "v1", "namespaces", pod.Namespace, "pods", pod.Name, "portforward")
+	dialer := spdy.NewDialer(upgrader, &http.Client{Transport: transport}, http.MethodPost, serverURL)
+
+	stopCh := make(chan struct{})
+	readyCh := make(chan struct{})
+	forwarder, err := portforward.New(dialer, []string{fmt.Sprintf("%d:%d", localPort, remotePort)}, stopCh, readyCh, io.Discard, errOut)
+	if err != nil {
+		return 0, nil, err
+	}
+
+	errCh := make(chan error, 1)
+	go func() {
+		errCh <- forwarder.ForwardPorts()
+	}()
+
+	select {
+	case <-readyCh:
+	case err := <-errCh:
+		return 0, nil, err
+	case <-ctx.Done():
+		close(stopCh)
+		return 0, nil, ctx.Err()
+	}
+
+	ports, err := forwarder.GetPorts()
+	if err != nil {
+		close(stopCh)
+		return 0, nil, err
+	}
+	return int(ports[0].Local), func() { close(stopCh) }, nil
+}
+
+func runMongosh(ctx context.Context, mongoshPath string, args []string, cmd *cobra.Command) error {
+	// mongosh handles Ctrl+C itself; keep the port-forward alive meanwhile.
+	signal.Ignore(os.Interrupt)
+	defer signal.Reset(os.Interrupt)
+
+	mongosh := exec.CommandContext(ctx, mongoshPath, args...)
+	mongosh.Stdin = cmd.InOrStdin()
+	mongosh.Stdout = cmd.OutOrStdout()
+	mongosh.Stderr = cmd.ErrOrStderr()
+	if err := mongosh.Run(); err != nil {
+		return fmt.Errorf("mongosh: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,208 @@
+package cmd
+
+import (
+	"bytes"
+	"context"
+	"io"
+	"slices"
+	"strings"
+	"testing"
+
+	"github.com/spf13/cobra"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/client-go/dynamic"
+	"k8s.io/client-go/kubernetes"
+	kubefake "k8s.io/client-go/kubernetes/fake"
+	"k8s.io/client-go/rest"
+
+	"github.com/documentdb/documentdb-operator/api/preview"
+)
+
+func TestConnectRunForwardsPrimaryAndLaunchesMongosh(t *testing.T) {
+	prevLoad := loadConfigFunc
+	prevDynamic := dynamicClientForConfig
+	prevKube := kubernetesClientForConfig
+	prevForward := portForwardFunc
+	prevMongosh := runMongoshFunc
+	defer func() {
+		loadConfigFunc = prevLoad
+		dynamicClientForConfig = prevDynamic
+		kubernetesClientForConfig = prevKube
+		portForwardFunc = prevForward
+		runMongoshFunc = prevMongosh
+	}()
+
+	namespace := defaultDocumentDBNamespace
+	docName := "documentdb-sample"
+
+	doc := newDocument(docName, namespace, "", "Cluster in healthy state")
+	if err := unstructured.SetNestedField(doc.Object, "app-credentials", "spec", "documentDbCredentialSecret"); err != nil {
+		t.Fatalf("failed to set credential secret: %v", err)
+	}
+
+	secret := &corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{Name: "app-credentials", Namespace: namespace},
+		Data: map[string][]byte{
+			"username": []byte("admin"),
+			"password": []byte("p@ss"),
+		},
+	}
+	replica := &corev1.Pod{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      docName + "-2",
+			Namespace: namespace,
+			Labels:    map[string]string{"app": docName, "cnpg.io/instanceRole": "replica"},
+		},
+		Status: corev1.PodStatus{Phase: corev1.PodRunning},
+	}
+	primary := &corev1.Pod{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      docName + "-1",
+			Namespace: namespace,
+			Labels:    map[string]string{"app": docName, "cnpg.io/instanceRole": "primary"},
+		},
+		Status: corev1.PodStatus{Phase: corev1.PodRunning},
+	}
+
+	loadConfigFunc = func(string) (*rest.Config, string, error) {
+		return &rest.Config{Host: "cluster"}, "cluster", nil
+	}
+	dynamicClientForConfig = func(*rest.Config) (dynamic.Interface, error) {
+		return newFakeDynamicClient(doc), nil
+	}
+	kubernetesClientForConfig = func(*rest.Config) (kubernetes.Interface, error) {
+		return kubefake.NewSimpleClientset(secret, replica, primary), nil
+	}
+
+	var forwardedPod string
+	stopped := false
+	portForwardFunc = func(_ context.Context, _ *rest.Config, pod *corev1.Pod, localPort, remotePort int, _ io.Writer) (int, func(), error) {
+		forwardedPod = pod.Name
+		if localPort != 0 || remotePort != defaultGatewayPort {
+			t.Fatalf("unexpected ports %d:%d", localPort, remotePort)
+		}
+		return 40000, func() { stopped = true }, nil
+	}
+	var mongoshPath string
+	var mongoshArgs []string
+	runMongoshFunc = func(_ context.Context, path string, args []string, _ *cobra.Command) error {
+		mongoshPath = path
+		mongoshArgs = args
+		return nil
+	}
+
+	cmd := &cobra.Command{}
+	var stdout, stderr bytes.Buffer
+	cmd.SetOut(&stdout)
+	cmd.SetErr(&stderr)
+
+	opts := &connectOptions{
+		documentDBName: docName,
+		namespace:      namespace,
+		mongoshPath:    "mongosh",
+		mongoshArgs:    []string{"--quiet"},
+	}
+	if err := opts.run(context.Background(), cmd); err != nil {
+		t.Fatalf("run returned error: %v", err)
+	}
+
+	if forwardedPod != primary.Name {
+		t.Fatalf("expected port-forward to %s, got %s", primary.Name, forwardedPod)
+	}
+	if !stopped {
+		t.Fatal("expected the port-forward to be stopped")
+	}
+	if mongoshPath != "mongosh" {
+		t.Fatalf("expected mongosh path 'mongosh', got %q", mongoshPath)
+	}
+	wantArgs := []string{
+		"mongodb://127.0.0.1:40000/?authMechanism=SCRAM-SHA-256&directConnection=true&tls=true&tlsAllowInvalidCertificates=true",
+		"--username", "admin",
+		"--quiet",
+	}
+	if !slices.Equal(mongoshArgs, wantArgs) {
+		t.Fatalf("unexpected mongosh args:\n got: %q\nwant: %q", mongoshArgs, wantArgs)
+	}
+	if slices.Contains(mongoshArgs, "p@ss") {
+		t.Fatalf("expected the password to be kept out of the mongosh args, got %q", mongoshArgs)
+	}
+	if !strings.Contains(stderr.String(), "Forwarding 127.0.0.1:40000") {
+		t.Fatalf("expected forwarding notice, got %q", stderr.String())
+	}
+	if !strings.Contains(stderr.String(), "kubectl get secret app-credentials -n "+namespace) {
+		t.Fatalf("expected the command reading the password, got %q", stderr.String())
+	}
+}
+
+func TestConnectMongoshArgsWithCAFile(t *testing.T) {
+	t.Parallel()
+
+	o := &connectOptions{tlsCAFile: "/tmp/ca.crt"}
+	args := o.mongoshCommandArgs(10260, "admin")
+	if strings.Contains(args[0], "tlsAllowInvalidCertificates") {
+		t.Fatalf("expected certificate verification with a CA file, got %q", args[0])
+	}
+	if !strings.Contains(args[0], "tlsCAFile=%2Ftmp%2Fca.crt") || !strings.Contains(args[0], "tlsAllowInvalidHostnames=true") {
+		t.Fatalf("expected CA file and hostname options, got %q", args[0])
+	}
+}
+
+func TestGatewayPortOf(t *testing.T) {
+	t.Parallel()
+
+	localEndpoints := []preview.EndpointStatus{
+		{Cluster: "remote", Port: 10270},
+		{Cluster: "local", Local: true, Port: 10280},
+	}
+	tests := []struct {
+		name     string
+		document preview.DocumentDB
+		want     int
+	}{
+		{
+			name: "default without spec or status",
+			want: defaultGatewayPort,
+		},
+		{
+			name: "port of the local endpoint",
+			document: preview.DocumentDB{
+				Status: preview.DocumentDBStatus{Endpoints: localEndpoints},
+			},
+			want: 10280,
+		},
+		{
+			name: "port of the spec",
+			document: preview.DocumentDB{
+				Spec:   preview.DocumentDBSpec{Gateway: &preview.GatewaySpec{Port: 10290}},
+				Status: preview.DocumentDBStatus{Endpoints: localEndpoints},
+			},
+			want: 10290,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			if got := gatewayPortOf(&tt.document); got != tt.want {
+				t.Fatalf("gatewayPortOf() = %d, want %d", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestFindPrimaryPodRequiresRunningPrimary(t *testing.T) {
+	t.Parallel()
+
+	pending := &corev1.Pod{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "sample-1",
+			Namespace: "ns",
+			Labels:    map[string]string{"app": "sample", "cnpg.io/instanceRole": "primary"},
+		},
+		Status: corev1.PodStatus{Phase: corev1.PodPending},
+	}
+	if _, err := findPrimaryPod(context.Background(), kubefake.NewSimpleClientset(pending), "ns", "sample"); err == nil {
+		t.Fatal("expected error when the primary pod is not running")
+	}
+}
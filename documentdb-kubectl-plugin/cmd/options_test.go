@@ -128,3 +128,27 @@ func TestPromoteOptionsCompleteTrimsContexts(t *testing.T) {
 		t.Fatalf("expected targetContext trimmed to 'other', got %q", o.targetContext)
 	}
 }
+
+func TestConnectOptionsCompleteDefaults(t *testing.T) {
+	t.Parallel()
+
+	o := &connectOptions{documentDBName: " sample ", namespace: " ", mongoshPath: "mongosh"}
+	if err := o.complete(); err != nil {
+		t.Fatalf("complete returned error: %v", err)
+	}
+	if o.documentDBName != "sample" {
+		t.Fatalf("expected document name trimmed, got %q", o.documentDBName)
+	}
+	if o.namespace != defaultDocumentDBNamespace {
+		t.Fatalf("expected namespace default %q, got %q", defaultDocumentDBNamespace, o.namespace)
+	}
+}
+
+func TestConnectOptionsCompleteValidatesLocalPort(t *testing.T) {
+	t.Parallel()
+
+	o := &connectOptions{documentDBName: "sample", mongoshPath: "mongosh", localPort: 70000}
+	if err := o.complete(); err == nil {
+		t.Fatal("expected error for out of range local port")
+	}
+}
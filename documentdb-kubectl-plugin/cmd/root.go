@@ -27,4 +27,5 @@ func init() {
 	rootCmd.AddCommand(newPromoteCommand())
 	rootCmd.AddCommand(newStatusCommand())
 	rootCmd.AddCommand(newEventsCommand())
+	rootCmd.AddCommand(newConnectCommand())
 }
@@ -5,6 +5,7 @@ package config
 
 import (
 	"encoding/json"
+	"fmt"
 	"reflect"
 	"strconv"
 
@@ -16,42 +17,72 @@ import (
 )
 
 const (
-	labelsParameter                     = "labels"
-	annotationParameter                 = "annotations"
-	gatewayImageParameter               = "gatewayImage"
-	gatewayImagePullPolicyParameter     = "gatewayImagePullPolicy"
-	gatewayMemoryRequestParameter       = "gatewayMemoryRequest"
-	gatewayMemoryLimitParameter         = "gatewayMemoryLimit"
-	gatewayCPURequestParameter          = "gatewayCpuRequest"
-	gatewayCPULimitParameter            = "gatewayCpuLimit"
-	documentDbCredentialSecretParameter = "documentDbCredentialSecret"
-	otelCollectorImageParameter         = "otelCollectorImage"
-	otelConfigMapNameParameter          = "otelConfigMapName"
-	otelMemoryRequestParameter          = "otelMemoryRequest"
-	otelMemoryLimitParameter            = "otelMemoryLimit"
-	otelCPURequestParameter             = "otelCpuRequest"
-	otelCPULimitParameter               = "otelCpuLimit"
-	prometheusPortParameter             = "prometheusPort"
+	labelsParameter                       = "labels"
+	annotationParameter                   = "annotations"
+	gatewayImageParameter                 = "gatewayImage"
+	gatewayImagePullPolicyParameter       = "gatewayImagePullPolicy"
+	gatewayMemoryRequestParameter         = "gatewayMemoryRequest"
+	gatewayMemoryLimitParameter           = "gatewayMemoryLimit"
+	gatewayCPURequestParameter            = "gatewayCpuRequest"
+	gatewayCPULimitParameter              = "gatewayCpuLimit"
+	documentDbCredentialSecretParameter   = "documentDbCredentialSecret"
+	otelCollectorImageParameter           = "otelCollectorImage"
+	otelCollectorImagePullPolicyParameter = "otelCollectorImagePullPolicy"
+	otelConfigMapNameParameter            = "otelConfigMapName"
+	otelMemoryRequestParameter            = "otelMemoryRequest"
+	otelMemoryLimitParameter              = "otelMemoryLimit"
+	otelCPURequestParameter               = "otelCpuRequest"
+	otelCPULimitParameter                 = "otelCpuLimit"
+	prometheusPortParameter               = "prometheusPort"
+	gatewayRunAsNamespaceUIDParameter     = "gatewayRunAsNamespaceUID"
+	gatewaySecurityContextParameter       = "gatewaySecurityContext"
+	gatewayProbesParameter                = "gatewayProbes"
+	gatewayPortParameter                  = "gatewayPort"
 )
 
+// DefaultGatewayPort is the port the gateway listens on unless the
+// gatewayPort parameter sets another one.
+// NOTE: Keep in sync with operator/src/internal/utils/constants.go:DEFAULT_GATEWAY_PORT
+const DefaultGatewayPort = 10260
+
 // Configuration represents the plugin configuration parameters
 type Configuration struct {
-	Labels                     map[string]string
-	Annotations                map[string]string
-	GatewayImage               string
-	GatewayImagePullPolicy     corev1.PullPolicy
-	GatewayMemoryRequest       string
-	GatewayMemoryLimit         string
-	GatewayCPURequest          string
-	GatewayCPULimit            string
-	DocumentDbCredentialSecret string
-	OtelCollectorImage         string
-	OtelConfigMapName          string
-	OTelMemoryRequest          string
-	OTelMemoryLimit            string
-	OTelCPURequest             string
-	OTelCPULimit               string
-	PrometheusPort             int32
+	Labels                       map[string]string
+	Annotations                  map[string]string
+	GatewayImage                 string
+	GatewayImagePullPolicy       corev1.PullPolicy
+	GatewayMemoryRequest         string
+	GatewayMemoryLimit           string
+	GatewayCPURequest            string
+	GatewayCPULimit              string
+	DocumentDbCredentialSecret   string
+	OtelCollectorImage           string
+	OtelCollectorImagePullPolicy corev1.PullPolicy
+	OtelConfigMapName            string
+	OTelMemoryRequest            string
+	OTelMemoryLimit              string
+	OTelCPURequest               string
+	OTelCPULimit                 string
+	PrometheusPort               int32
+	// GatewayRunAsNamespaceUID leaves the gateway UID/GID unset so that
+	// OpenShift assigns them from the namespace range.
+	GatewayRunAsNamespaceUID bool
+	// GatewaySecurityContext holds the user overrides of the gateway
+	// SecurityContext: runAsUser, runAsGroup, seccompProfile and
+	// readOnlyRootFilesystem.
+	GatewaySecurityContext *corev1.SecurityContext
+	// GatewayProbes holds the timings of the liveness and readiness probes of
+	// the gateway container. The gateway has no probe unless one is set.
+	GatewayProbes *GatewayProbes
+	// GatewayPort is the port the gateway listens on and is probed at.
+	GatewayPort int32
+}
+
+// GatewayProbes are the timings of the gateway probes. Their handlers are
+// ignored: the gateway is probed on its TCP port.
+type GatewayProbes struct {
+	Liveness  *corev1.Probe `json:"liveness,omitempty"`
+	Readiness *corev1.Probe `json:"readiness,omitempty"`
 }
 
 // FromParameters builds a plugin configuration from the configuration parameters
@@ -108,23 +139,76 @@ func FromParameters(
 		}
 	}
 
+	var gatewayPort int32
+	if portStr := helper.Parameters[gatewayPortParameter]; portStr != "" {
+		p, err := strconv.ParseInt(portStr, 10, 32)
+		if err == nil && (p < 1 || p > 65535) {
+			err = fmt.Errorf("%d is out of range", p)
+		}
+		if err != nil {
+			validationErrors = append(
+				validationErrors,
+				validation.BuildErrorForParameter(helper, gatewayPortParameter, "invalid port number: "+err.Error()),
+			)
+		} else {
+			gatewayPort = int32(p)
+		}
+	}
+
+	var runAsNamespaceUID bool
+	if value := helper.Parameters[gatewayRunAsNamespaceUIDParameter]; value != "" {
+		parsed, err := strconv.ParseBool(value)
+		if err != nil {
+			validationErrors = append(
+				validationErrors,
+				validation.BuildErrorForParameter(helper, gatewayRunAsNamespaceUIDParameter, "invalid boolean: "+err.Error()),
+			)
+		}
+		runAsNamespaceUID = parsed
+	}
+
+	var gatewaySecurityContext *corev1.SecurityContext
+	if value := helper.Parameters[gatewaySecurityContextParameter]; value != "" {
+		if err := json.Unmarshal([]byte(value), &gatewaySecurityContext); err != nil {
+			validationErrors = append(
+				validationErrors,
+				validation.BuildErrorForParameter(helper, gatewaySecurityContextParameter, err.Error()),
+			)
+		}
+	}
+
+	var gatewayProbes *GatewayProbes
+	if value := helper.Parameters[gatewayProbesParameter]; value != "" {
+		if err := json.Unmarshal([]byte(value), &gatewayProbes); err != nil {
+			validationErrors = append(
+				validationErrors,
+				validation.BuildErrorForParameter(helper, gatewayProbesParameter, err.Error()),
+			)
+		}
+	}
+
 	configuration := &Configuration{
-		Labels:                     labels,
-		Annotations:                annotations,
-		GatewayImage:               gatewayImage,
-		GatewayImagePullPolicy:     pullPolicy,
-		GatewayMemoryRequest:       helper.Parameters[gatewayMemoryRequestParameter],
-		GatewayMemoryLimit:         helper.Parameters[gatewayMemoryLimitParameter],
-		GatewayCPURequest:          helper.Parameters[gatewayCPURequestParameter],
-		GatewayCPULimit:            helper.Parameters[gatewayCPULimitParameter],
-		DocumentDbCredentialSecret: credentialSecret,
-		OtelCollectorImage:         helper.Parameters[otelCollectorImageParameter],
-		OtelConfigMapName:          helper.Parameters[otelConfigMapNameParameter],
-		OTelMemoryRequest:          helper.Parameters[otelMemoryRequestParameter],
-		OTelMemoryLimit:            helper.Parameters[otelMemoryLimitParameter],
-		OTelCPURequest:             helper.Parameters[otelCPURequestParameter],
-		OTelCPULimit:               helper.Parameters[otelCPULimitParameter],
-		PrometheusPort:             prometheusPort,
+		Labels:                       labels,
+		Annotations:                  annotations,
+		GatewayImage:                 gatewayImage,
+		GatewayImagePullPolicy:       pullPolicy,
+		GatewayMemoryRequest:         helper.Parameters[gatewayMemoryRequestParameter],
+		GatewayMemoryLimit:           helper.Parameters[gatewayMemoryLimitParameter],
+		GatewayCPURequest:            helper.Parameters[gatewayCPURequestParameter],
+		GatewayCPULimit:              helper.Parameters[gatewayCPULimitParameter],
+		DocumentDbCredentialSecret:   credentialSecret,
+		OtelCollectorImage:           helper.Parameters[otelCollectorImageParameter],
+		OtelCollectorImagePullPolicy: parsePullPolicy(helper.Parameters[otelCollectorImagePullPolicyParameter]),
+		OtelConfigMapName:            helper.Parameters[otelConfigMapNameParameter],
+		OTelMemoryRequest:            helper.Parameters[otelMemoryRequestParameter],
+		OTelMemoryLimit:              helper.Parameters[otelMemoryLimitParameter],
+		OTelCPURequest:               helper.Parameters[otelCPURequestParameter],
+		OTelCPULimit:                 helper.Parameters[otelCPULimitParameter],
+		PrometheusPort:               prometheusPort,
+		GatewayRunAsNamespaceUID:     runAsNamespaceUID,
+		GatewaySecurityContext:       gatewaySecurityContext,
+		GatewayProbes:                gatewayProbes,
+		GatewayPort:                  gatewayPort,
 	}
 
 	configuration.applyDefaults()
@@ -192,6 +276,9 @@ func (config *Configuration) applyDefaults() {
 	if config.DocumentDbCredentialSecret == "" {
 		config.DocumentDbCredentialSecret = "documentdb-credentials"
 	}
+	if config.GatewayPort == 0 {
+		config.GatewayPort = DefaultGatewayPort
+	}
 }
 
 // parsePullPolicy converts a string to a corev1.PullPolicy.
@@ -231,10 +318,31 @@ func (config *Configuration) ToParameters() (map[string]string, error) {
 	setIfNotEmpty(gatewayCPURequestParameter, config.GatewayCPURequest)
 	setIfNotEmpty(gatewayCPULimitParameter, config.GatewayCPULimit)
 	result[documentDbCredentialSecretParameter] = config.DocumentDbCredentialSecret
+	setIfNotEmpty(otelCollectorImagePullPolicyParameter, string(config.OtelCollectorImagePullPolicy))
 	setIfNotEmpty(otelMemoryRequestParameter, config.OTelMemoryRequest)
 	setIfNotEmpty(otelMemoryLimitParameter, config.OTelMemoryLimit)
 	setIfNotEmpty(otelCPURequestParameter, config.OTelCPURequest)
 	setIfNotEmpty(otelCPULimitParameter, config.OTelCPULimit)
+	if config.GatewayRunAsNamespaceUID {
+		result[gatewayRunAsNamespaceUIDParameter] = "true"
+	}
+	if config.GatewaySecurityContext != nil {
+		serializedSecurityContext, err := json.Marshal(config.GatewaySecurityContext)
+		if err != nil {
+			return nil, err
+		}
+		result[gatewaySecurityContextParameter] = string(serializedSecurityContext)
+	}
+	if config.GatewayProbes != nil {
+		serializedProbes, err := json.Marshal(config.GatewayProbes)
+		if err != nil {
+			return nil, err
+		}
+		result[gatewayProbesParameter] = string(serializedProbes)
+	}
+	if config.GatewayPort != 0 && config.GatewayPort != DefaultGatewayPort {
+		result[gatewayPortParameter] = strconv.Itoa(int(config.GatewayPort))
+	}
 
 	return result, nil
 }
@@ -73,6 +73,19 @@ func TestFromParameters(t *testing.T) {
 		}
 	})
 
+	t.Run("otel collector pull policy from parameters", func(t *testing.T) {
+		helper := &common.Plugin{Parameters: map[string]string{
+			"otelCollectorImagePullPolicy": "IfNotPresent",
+		}}
+		config, errs := FromParameters(helper)
+		if len(errs) != 0 {
+			t.Fatalf("unexpected validation errors: %v", errs)
+		}
+		if config.OtelCollectorImagePullPolicy != corev1.PullIfNotPresent {
+			t.Errorf("OtelCollectorImagePullPolicy = %q, want IfNotPresent", config.OtelCollectorImagePullPolicy)
+		}
+	})
+
 	t.Run("defaults to IfNotPresent when not set", func(t *testing.T) {
 		helper := &common.Plugin{Parameters: map[string]string{}}
 		config, errs := FromParameters(helper)
@@ -120,19 +133,93 @@ func TestFromParameters(t *testing.T) {
 			t.Errorf("OTelCPURequest = %q, want 100m", config.OTelCPURequest)
 		}
 	})
+
+	t.Run("gateway namespace UID from parameters", func(t *testing.T) {
+		helper := &common.Plugin{Parameters: map[string]string{"gatewayRunAsNamespaceUID": "true"}}
+		config, errs := FromParameters(helper)
+		if len(errs) != 0 {
+			t.Fatalf("unexpected validation errors: %v", errs)
+		}
+		if !config.GatewayRunAsNamespaceUID {
+			t.Error("GatewayRunAsNamespaceUID = false, want true")
+		}
+	})
+
+	t.Run("gateway security context from parameters", func(t *testing.T) {
+		helper := &common.Plugin{Parameters: map[string]string{
+			"gatewaySecurityContext": `{"runAsUser":2000,"readOnlyRootFilesystem":true}`,
+		}}
+		config, errs := FromParameters(helper)
+		if len(errs) != 0 {
+			t.Fatalf("unexpected validation errors: %v", errs)
+		}
+		sc := config.GatewaySecurityContext
+		if sc == nil || sc.RunAsUser == nil || *sc.RunAsUser != 2000 || sc.ReadOnlyRootFilesystem == nil || !*sc.ReadOnlyRootFilesystem {
+			t.Errorf("GatewaySecurityContext = %+v, want runAsUser 2000 and a read-only root filesystem", sc)
+		}
+	})
+
+	t.Run("gateway probes from parameters", func(t *testing.T) {
+		helper := &common.Plugin{Parameters: map[string]string{
+			"gatewayProbes": `{"liveness":{"periodSeconds":30,"failureThreshold":6}}`,
+		}}
+		config, errs := FromParameters(helper)
+		if len(errs) != 0 {
+			t.Fatalf("unexpected validation errors: %v", errs)
+		}
+		probes := config.GatewayProbes
+		if probes == nil || probes.Liveness == nil || probes.Liveness.PeriodSeconds != 30 || probes.Readiness != nil {
+			t.Errorf("GatewayProbes = %+v, want only a liveness probe every 30s", probes)
+		}
+	})
+
+	t.Run("gateway port from parameters", func(t *testing.T) {
+		helper := &common.Plugin{Parameters: map[string]string{"gatewayPort": "27017"}}
+		config, errs := FromParameters(helper)
+		if len(errs) != 0 {
+			t.Fatalf("unexpected validation errors: %v", errs)
+		}
+		if config.GatewayPort != 27017 {
+			t.Errorf("GatewayPort = %d, want 27017", config.GatewayPort)
+		}
+	})
+
+	t.Run("gateway port defaults to 10260", func(t *testing.T) {
+		config, _ := FromParameters(&common.Plugin{Parameters: map[string]string{}})
+		if config.GatewayPort != DefaultGatewayPort {
+			t.Errorf("GatewayPort = %d, want %d", config.GatewayPort, DefaultGatewayPort)
+		}
+	})
+
+	t.Run("invalid gateway port", func(t *testing.T) {
+		for _, value := range []string{"gateway", "0", "70000"} {
+			helper := &common.Plugin{Parameters: map[string]string{"gatewayPort": value}}
+			if _, errs := FromParameters(helper); len(errs) != 1 {
+				t.Errorf("gatewayPort %q: expected 1 validation error, got %d", value, len(errs))
+			}
+		}
+	})
+
+	t.Run("invalid gateway namespace UID", func(t *testing.T) {
+		helper := &common.Plugin{Parameters: map[string]string{"gatewayRunAsNamespaceUID": "maybe"}}
+		if _, errs := FromParameters(helper); len(errs) != 1 {
+			t.Errorf("expected 1 validation error, got %d", len(errs))
+		}
+	})
 }
 
 func TestToParametersRoundTrip(t *testing.T) {
 	original := &Configuration{
-		GatewayImage:           "my-image:latest",
-		GatewayImagePullPolicy: corev1.PullNever,
-		GatewayMemoryRequest:   "768Mi",
-		GatewayMemoryLimit:     "3Gi",
-		GatewayCPURequest:      "500m",
-		GatewayCPULimit:        "2",
-		OTelMemoryRequest:      "64Mi",
-		OTelMemoryLimit:        "128Mi",
-		OTelCPURequest:         "100m",
+		GatewayImage:                 "my-image:latest",
+		GatewayImagePullPolicy:       corev1.PullNever,
+		GatewayMemoryRequest:         "768Mi",
+		GatewayMemoryLimit:           "3Gi",
+		GatewayCPURequest:            "500m",
+		GatewayCPULimit:              "2",
+		OTelMemoryRequest:            "64Mi",
+		OTelMemoryLimit:              "128Mi",
+		OTelCPURequest:               "100m",
+		OtelCollectorImagePullPolicy: corev1.PullNever,
 	}
 	original.applyDefaults()
 
@@ -149,6 +236,9 @@ func TestToParametersRoundTrip(t *testing.T) {
 	if restored.GatewayImagePullPolicy != original.GatewayImagePullPolicy {
 		t.Errorf("round-trip pull policy = %q, want %q", restored.GatewayImagePullPolicy, original.GatewayImagePullPolicy)
 	}
+	if restored.OtelCollectorImagePullPolicy != original.OtelCollectorImagePullPolicy {
+		t.Errorf("round-trip otel pull policy = %q, want %q", restored.OtelCollectorImagePullPolicy, original.OtelCollectorImagePullPolicy)
+	}
 	if restored.GatewayImage != original.GatewayImage {
 		t.Errorf("round-trip gateway image = %q, want %q", restored.GatewayImage, original.GatewayImage)
 	}
@@ -171,11 +171,15 @@ func (impl Implementation) reconcileMetadata(
 		ImagePullPolicy: configuration.GatewayImagePullPolicy,
 		Ports: []corev1.ContainerPort{
 			{
-				ContainerPort: 10260,
+				ContainerPort: configuration.GatewayPort,
 			},
 		},
 		Env:             envVars,
-		SecurityContext: gatewaySecurityContext(),
+		SecurityContext: gatewaySecurityContext(configuration),
+	}
+	if probes := configuration.GatewayProbes; probes != nil {
+		sidecar.LivenessProbe = gatewayProbe(probes.Liveness, configuration.GatewayPort)
+		sidecar.ReadinessProbe = gatewayProbe(probes.Readiness, configuration.GatewayPort)
 	}
 	if resources := buildResources(
 		configuration.GatewayCPURequest,
@@ -230,6 +234,11 @@ func (impl Implementation) reconcileMetadata(
 	} else {
 		args = append([]string{"--create-user", "true"}, args...)
 	}
+	if configuration.GatewayPort != config.DefaultGatewayPort {
+		// Only passed for another port, which needs a gateway image that
+		// accepts it; older images ignore unknown options
+		args = append(args, "--gateway-port", strconv.Itoa(int(configuration.GatewayPort)))
+	}
 	if hasTLSSecret {
 		// Pass cert and key via CLI args to align with emulator_entrypoint.sh interface
 		args = append(args, "--cert-path", "/tls/tls.crt", "--key-file", "/tls/tls.key")
@@ -271,6 +280,7 @@ func (impl Implementation) reconcileMetadata(
 		}
 
 		otelSidecar := newOtelCollectorSidecar(configuration.OtelCollectorImage, cluster.Name)
+		otelSidecar.ImagePullPolicy = configuration.OtelCollectorImagePullPolicy
 		if resources := buildResources(
 			configuration.OTelCPURequest,
 			configuration.OTelCPULimit,
@@ -491,13 +501,45 @@ func injectGatewayOTelEnv(pod *corev1.Pod) {
 // Collector sidecar.
 const otelCollectorContainerName = "otel-collector"
 
+// gatewayProbe returns a probe of the gateway TCP port with the timings of
+// timings, or nil when timings is nil.
+func gatewayProbe(timings *corev1.Probe, port int32) *corev1.Probe {
+	if timings == nil {
+		return nil
+	}
+	probe := timings.DeepCopy()
+	probe.ProbeHandler = corev1.ProbeHandler{
+		TCPSocket: &corev1.TCPSocketAction{Port: intstr.FromInt32(port)},
+	}
+	return probe
+}
+
 // gatewaySecurityContext returns the SecurityContext for the documentdb-gateway
 // sidecar: the shared PSA-restricted hardening plus an explicit UID/GID of
-// 1000, the non-root user the gateway image is built to run as.
-func gatewaySecurityContext() *corev1.SecurityContext {
+// 1000, the non-root user the gateway image is built to run as. With
+// GatewayRunAsNamespaceUID the UID/GID are left unset, as OpenShift's
+// restricted-v2 SCC only admits the UIDs of the namespace range. The fields of
+// the user-defined GatewaySecurityContext override both.
+func gatewaySecurityContext(configuration *config.Configuration) *corev1.SecurityContext {
 	sc := hardenedSecurityContext()
-	sc.RunAsUser = pointer.Int64(1000)
-	sc.RunAsGroup = pointer.Int64(1000)
+	if !configuration.GatewayRunAsNamespaceUID {
+		sc.RunAsUser = pointer.Int64(1000)
+		sc.RunAsGroup = pointer.Int64(1000)
+	}
+	if overrides := configuration.GatewaySecurityContext; overrides != nil {
+		if overrides.RunAsUser != nil {
+			sc.RunAsUser = overrides.RunAsUser
+		}
+		if overrides.RunAsGroup != nil {
+			sc.RunAsGroup = overrides.RunAsGroup
+		}
+		if overrides.SeccompProfile != nil {
+			sc.SeccompProfile = overrides.SeccompProfile
+		}
+		if overrides.ReadOnlyRootFilesystem != nil {
+			sc.ReadOnlyRootFilesystem = overrides.ReadOnlyRootFilesystem
+		}
+	}
 	return sc
 }
 
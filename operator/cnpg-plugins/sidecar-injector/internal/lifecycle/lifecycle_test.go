@@ -15,7 +15,9 @@ import (
 	corev1 "k8s.io/api/core/v1"
 	"k8s.io/apimachinery/pkg/api/resource"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/utils/pointer"
 
+	"github.com/documentdb/cnpg-i-sidecar-injector/internal/config"
 	pluginmetadata "github.com/documentdb/cnpg-i-sidecar-injector/pkg/metadata"
 )
 
@@ -207,6 +209,60 @@ func TestLifecycleHookInjectsContainerResourcesAndGoMemLimit(t *testing.T) {
 	assertEnvValue(t, otel.Env, "GOMEMLIMIT", "107374182")
 }
 
+// TestLifecycleHookGatewayPort asserts that the gatewayPort parameter moves
+// the container port, the probes and the listening port of the gateway.
+func TestLifecycleHookGatewayPort(t *testing.T) {
+	enabled := true
+	cluster := &apiv1.Cluster{
+		TypeMeta:   metav1.TypeMeta{APIVersion: apiv1.SchemeGroupVersion.String(), Kind: apiv1.ClusterKind},
+		ObjectMeta: metav1.ObjectMeta{Name: "cluster", Namespace: "default"},
+		Spec: apiv1.ClusterSpec{
+			Plugins: []apiv1.PluginConfiguration{{
+				Name:    pluginmetadata.PluginName,
+				Enabled: &enabled,
+				Parameters: map[string]string{
+					"gatewayPort":   "27017",
+					"gatewayProbes": `{"readiness":{"periodSeconds":10}}`,
+				},
+			}},
+		},
+		Status: apiv1.ClusterStatus{TargetPrimary: "cluster-1"},
+	}
+	pod := &corev1.Pod{
+		TypeMeta: metav1.TypeMeta{APIVersion: "v1", Kind: "Pod"},
+		ObjectMeta: metav1.ObjectMeta{
+			Name:        "cluster-1",
+			Namespace:   "default",
+			Labels:      map[string]string{"cnpg.io/cluster": "cluster"},
+			Annotations: map[string]string{"cnpg.io/operatorVersion": "test"},
+		},
+		Spec: corev1.PodSpec{Containers: []corev1.Container{{Name: "postgres"}}},
+	}
+
+	response, err := Implementation{}.LifecycleHook(context.Background(), &cnpglifecycle.OperatorLifecycleRequest{
+		OperationType:     &cnpglifecycle.OperatorOperationType{Type: cnpglifecycle.OperatorOperationType_TYPE_CREATE},
+		ClusterDefinition: mustMarshal(t, cluster),
+		ObjectDefinition:  mustMarshal(t, pod),
+	})
+	if err != nil {
+		t.Fatalf("LifecycleHook() error: %v", err)
+	}
+
+	gateway, ok := addedContainersFromPatch(t, response.JsonPatch)[gatewayContainerName]
+	if !ok {
+		t.Fatalf("gateway container missing from patch; patch=%s", string(response.JsonPatch))
+	}
+	if len(gateway.Ports) != 1 || gateway.Ports[0].ContainerPort != 27017 {
+		t.Errorf("gateway ports = %+v, want 27017", gateway.Ports)
+	}
+	if probe := gateway.ReadinessProbe; probe == nil || probe.TCPSocket == nil || probe.TCPSocket.Port.IntValue() != 27017 {
+		t.Errorf("gateway readiness probe = %+v, want a TCP check of port 27017", probe)
+	}
+	if args := strings.Join(gateway.Args, " "); !strings.Contains(args, "--gateway-port 27017") {
+		t.Errorf("gateway args = %q, want --gateway-port 27017", args)
+	}
+}
+
 func mustMarshal(t *testing.T, value any) []byte {
 	t.Helper()
 	data, err := json.Marshal(value)
@@ -336,7 +392,7 @@ func TestHardenedSecurityContext_NoForcedUID(t *testing.T) {
 // TestGatewaySecurityContext_PSARestrictedAsUID1000 asserts the gateway sidecar
 // is PSA restricted and pinned to the non-root UID/GID 1000 its image expects.
 func TestGatewaySecurityContext_PSARestrictedAsUID1000(t *testing.T) {
-	sc := gatewaySecurityContext()
+	sc := gatewaySecurityContext(&config.Configuration{})
 	assertPSARestricted(t, "gatewaySecurityContext", sc)
 	if sc.RunAsUser == nil || *sc.RunAsUser != 1000 {
 		t.Errorf("gateway must run as UID 1000, got %v", sc.RunAsUser)
@@ -346,6 +402,41 @@ func TestGatewaySecurityContext_PSARestrictedAsUID1000(t *testing.T) {
 	}
 }
 
+// TestGatewaySecurityContext_NamespaceUID asserts that on OpenShift the gateway
+// stays PSA restricted but leaves its UID/GID to the namespace range.
+func TestGatewaySecurityContext_NamespaceUID(t *testing.T) {
+	sc := gatewaySecurityContext(&config.Configuration{GatewayRunAsNamespaceUID: true})
+	assertPSARestricted(t, "gatewaySecurityContext", sc)
+	if sc.RunAsUser != nil || sc.RunAsGroup != nil {
+		t.Errorf("gateway must not pin a UID/GID, got %v/%v", sc.RunAsUser, sc.RunAsGroup)
+	}
+}
+
+// TestGatewaySecurityContext_Overrides asserts that the user-defined overrides
+// replace the gateway defaults and keep the remaining hardening.
+func TestGatewaySecurityContext_Overrides(t *testing.T) {
+	sc := gatewaySecurityContext(&config.Configuration{GatewaySecurityContext: &corev1.SecurityContext{
+		RunAsUser:              pointer.Int64(2000),
+		ReadOnlyRootFilesystem: pointer.Bool(true),
+		SeccompProfile: &corev1.SeccompProfile{
+			Type:             corev1.SeccompProfileTypeLocalhost,
+			LocalhostProfile: pointer.String("profiles/gateway.json"),
+		},
+	}})
+	if sc.AllowPrivilegeEscalation == nil || *sc.AllowPrivilegeEscalation {
+		t.Error("gateway must keep AllowPrivilegeEscalation false")
+	}
+	if *sc.RunAsUser != 2000 || *sc.RunAsGroup != 1000 {
+		t.Errorf("gateway must run as 2000/1000, got %d/%d", *sc.RunAsUser, *sc.RunAsGroup)
+	}
+	if sc.ReadOnlyRootFilesystem == nil || !*sc.ReadOnlyRootFilesystem {
+		t.Error("gateway root filesystem must be read-only")
+	}
+	if sc.SeccompProfile.Type != corev1.SeccompProfileTypeLocalhost {
+		t.Errorf("seccomp profile type = %s, want Localhost", sc.SeccompProfile.Type)
+	}
+}
+
 // TestNewOtelCollectorSidecar_Hardened is the injection-layer guard for the
 // otel-collector sidecar (which the e2e suite does not exercise unless
 // monitoring is enabled). It asserts the constructed container is PSA
@@ -365,3 +456,22 @@ func TestNewOtelCollectorSidecar_Hardened(t *testing.T) {
 		t.Errorf("otel-collector must not force a GID, got %d", *c.SecurityContext.RunAsGroup)
 	}
 }
+
+// TestGatewayProbe asserts that the gateway probes keep the configured
+// timings and always check the gateway TCP port.
+func TestGatewayProbe(t *testing.T) {
+	if probe := gatewayProbe(nil, config.DefaultGatewayPort); probe != nil {
+		t.Errorf("gatewayProbe(nil) = %+v, want nil", probe)
+	}
+	probe := gatewayProbe(&corev1.Probe{
+		ProbeHandler:     corev1.ProbeHandler{Exec: &corev1.ExecAction{Command: []string{"true"}}},
+		PeriodSeconds:    30,
+		FailureThreshold: 6,
+	}, 27017)
+	if probe.PeriodSeconds != 30 || probe.FailureThreshold != 6 {
+		t.Errorf("probe timings = %d/%d, want 30/6", probe.PeriodSeconds, probe.FailureThreshold)
+	}
+	if probe.Exec != nil || probe.TCPSocket == nil || probe.TCPSocket.Port.IntValue() != 27017 {
+		t.Errorf("probe handler = %+v, want a TCP check of port 27017", probe.ProbeHandler)
+	}
+}
@@ -1,3 +1,17 @@
 {{- define "documentdb-chart.name" -}}
 documentdb-operator
-{{- end -}}
\ No newline at end of file
+{{- end -}}
+
+{{/*
+Renders a pod or container securityContext. In OpenShift mode the pinned
+runAsUser, runAsGroup and fsGroup are dropped so that the restricted-v2 SCC
+assigns them from the namespace range.
+Usage: include "documentdb-chart.securityContext" (dict "root" $ "context" .)
+*/}}
+{{- define "documentdb-chart.securityContext" -}}
+{{- if .root.Values.openshift.enabled -}}
+{{- toYaml (omit .context "runAsUser" "runAsGroup" "fsGroup") -}}
+{{- else -}}
+{{- toYaml .context -}}
+{{- end -}}
+{{- end -}}
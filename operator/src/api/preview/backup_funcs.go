@@ -57,6 +57,11 @@ func (backup *Backup) UpdateStatus(cnpgBackup *cnpgv1.Backup, backupConfiguratio
 		needsUpdate = true
 	}
 
+	if retentionDays := backup.EffectiveRetentionDays(backupConfiguration); backup.Status.RetentionDays != retentionDays {
+		backup.Status.RetentionDays = retentionDays
+		needsUpdate = true
+	}
+
 	expirationTime := backup.CalculateExpirationTime(backupConfiguration)
 	if !areTimesEqual(backup.Status.ExpiredAt, expirationTime) {
 		backup.Status.ExpiredAt = expirationTime
@@ -66,20 +71,26 @@ func (backup *Backup) UpdateStatus(cnpgBackup *cnpgv1.Backup, backupConfiguratio
 	return needsUpdate
 }
 
+// EffectiveRetentionDays returns how many days the backup is retained: its
+// spec.retentionDays, or else the one of the backup configuration of its
+// cluster, or else 30.
+func (backup *Backup) EffectiveRetentionDays(backupConfiguration *BackupConfiguration) int {
+	if backup.Spec.RetentionDays != nil {
+		return *backup.Spec.RetentionDays
+	}
+	if backupConfiguration != nil {
+		return backupConfiguration.RetentionDays
+	}
+	return DefaultBackupRetentionDays
+}
+
 // CalculateExpirationTime calculates the expiration time of the backup based on retention policy.
 func (backup *Backup) CalculateExpirationTime(backupConfiguration *BackupConfiguration) *metav1.Time {
 	if !backup.Status.IsDone() {
 		return nil
 	}
 
-	retentionHours := 0
-	if backup.Spec.RetentionDays != nil {
-		retentionHours = *backup.Spec.RetentionDays * 24
-	} else if backupConfiguration != nil {
-		retentionHours = backupConfiguration.RetentionDays * 24
-	} else {
-		retentionHours = 30 * 24 // Default to 30 days
-	}
+	retentionHours := backup.EffectiveRetentionDays(backupConfiguration) * 24
 
 	// Determine the start time for retention calculation
 	// If backup completed, use StoppedAt;
@@ -141,3 +152,18 @@ func (backupList *BackupList) GetLastBackup() *Backup {
 	}
 	return lastBackup
 }
+
+// GetLastCompletedBackup returns the Backup in the list that completed most
+// recently, or nil if none has completed.
+func (backupList *BackupList) GetLastCompletedBackup() *Backup {
+	var lastBackup *Backup
+	for i, backup := range backupList.Items {
+		if backup.Status.Phase != cnpgv1.BackupPhaseCompleted || backup.Status.StoppedAt == nil {
+			continue
+		}
+		if lastBackup == nil || backup.Status.StoppedAt.After(lastBackup.Status.StoppedAt.Time) {
+			lastBackup = &backupList.Items[i]
+		}
+	}
+	return lastBackup
+}
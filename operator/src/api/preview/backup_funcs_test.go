@@ -89,6 +89,7 @@ var _ = Describe("Backup", func() {
 			Expect(backup.Status.StartedAt).To(Equal(&startedAt))
 			Expect(backup.Status.StoppedAt).To(Equal(&stoppedAt))
 			Expect(backup.Status.Message).To(Equal("none"))
+			Expect(backup.Status.RetentionDays).To(Equal(30))
 			// ExpiredAt should be StoppedAt + 30 days (default)
 			Expect(backup.Status.ExpiredAt).ToNot(BeNil())
 			Expect(backup.Status.ExpiredAt.Time.Equal(stoppedAt.Time.Add(30 * 24 * time.Hour))).To(BeTrue())
@@ -111,11 +112,12 @@ var _ = Describe("Backup", func() {
 			backup := &Backup{
 				Spec: BackupSpec{},
 				Status: BackupStatus{
-					Phase:     cnpgv1.BackupPhaseCompleted,
-					StartedAt: &startedAt,
-					StoppedAt: &stoppedAt,
-					Message:   "none",
-					ExpiredAt: &expiredAt,
+					Phase:         cnpgv1.BackupPhaseCompleted,
+					StartedAt:     &startedAt,
+					StoppedAt:     &stoppedAt,
+					Message:       "none",
+					ExpiredAt:     &expiredAt,
+					RetentionDays: 30,
 				},
 			}
 
@@ -188,6 +190,19 @@ var _ = Describe("Backup", func() {
 		})
 	})
 
+	Describe("EffectiveRetentionDays", func() {
+		It("prefers Spec.RetentionDays over the backup configuration", func() {
+			backup := &Backup{Spec: BackupSpec{RetentionDays: intPtr(2)}}
+			Expect(backup.EffectiveRetentionDays(&BackupConfiguration{RetentionDays: 3})).To(Equal(2))
+		})
+
+		It("falls back to the backup configuration, then to 30 days", func() {
+			backup := &Backup{}
+			Expect(backup.EffectiveRetentionDays(&BackupConfiguration{RetentionDays: 3})).To(Equal(3))
+			Expect(backup.EffectiveRetentionDays(nil)).To(Equal(30))
+		})
+	})
+
 	Describe("areTimesEqual", func() {
 		It("returns true for nil nil", func() {
 			Expect(areTimesEqual(nil, nil)).To(BeTrue())
@@ -361,4 +376,33 @@ var _ = Describe("BackupList", func() {
 			Expect(last).To(Equal(&backupList.Items[1]))
 		})
 	})
+
+	Describe("GetLastCompletedBackup", func() {
+		It("returns nil when no backup has completed", func() {
+			backupList := &BackupList{
+				Items: []Backup{
+					{ObjectMeta: metav1.ObjectMeta{Name: "running"}, Status: BackupStatus{Phase: cnpgv1.BackupPhaseRunning}},
+				},
+			}
+			Expect(backupList.GetLastCompletedBackup()).To(BeNil())
+		})
+
+		It("returns the completed backup with the latest StoppedAt", func() {
+			older := metav1.NewTime(time.Date(2025, 6, 1, 9, 0, 0, 0, time.UTC))
+			newer := metav1.NewTime(time.Date(2025, 6, 1, 11, 0, 0, 0, time.UTC))
+			newest := metav1.NewTime(time.Date(2025, 6, 1, 12, 0, 0, 0, time.UTC))
+
+			backupList := &BackupList{
+				Items: []Backup{
+					{ObjectMeta: metav1.ObjectMeta{Name: "b1"}, Status: BackupStatus{Phase: cnpgv1.BackupPhaseCompleted, StoppedAt: &older}},
+					{ObjectMeta: metav1.ObjectMeta{Name: "b2"}, Status: BackupStatus{Phase: cnpgv1.BackupPhaseCompleted, StoppedAt: &newer}},
+					{ObjectMeta: metav1.ObjectMeta{Name: "failed"}, Status: BackupStatus{Phase: cnpgv1.BackupPhaseFailed, StoppedAt: &newest}},
+				},
+			}
+
+			last := backupList.GetLastCompletedBackup()
+			Expect(last).ToNot(BeNil())
+			Expect(last.Name).To(Equal("b2"))
+		})
+	})
 })
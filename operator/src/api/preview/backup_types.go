@@ -18,6 +18,7 @@ type BackupSpec struct {
 
 	// RetentionDays specifies how many days the backup should be retained.
 	// If not specified, the default retention period from the cluster's backup retention policy will be used.
+	// +kubebuilder:validation:Minimum=1
 	// +optional
 	RetentionDays *int `json:"retentionDays,omitempty"`
 }
@@ -26,6 +27,10 @@ type BackupSpec struct {
 // for example backup won't run for a standby cluster in multi-region setup.
 const BackupPhaseSkipped cnpgv1.BackupPhase = "skipped"
 
+// DefaultBackupRetentionDays is the retention of the backups of a cluster
+// without spec.backup.
+const DefaultBackupRetentionDays = 30
+
 // BackupStatus defines the observed state of Backup.
 type BackupStatus struct {
 	// Phase represents the current phase of the backup operation.
@@ -43,6 +48,12 @@ type BackupStatus struct {
 	// +optional
 	ExpiredAt *metav1.Time `json:"expiredAt,omitempty"`
 
+	// RetentionDays is the number of days the backup is retained, from
+	// spec.retentionDays or the backup configuration of the cluster. ExpiredAt
+	// is computed from it once the backup is done.
+	// +optional
+	RetentionDays int `json:"retentionDays,omitempty"`
+
 	// Message contains additional information about the backup status.
 	// For failed backups, this contains the error message.
 	// For skipped backups, this explains why the backup was skipped.
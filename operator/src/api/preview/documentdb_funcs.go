@@ -3,11 +3,19 @@
 
 package preview
 
+import (
+	"slices"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+)
+
 // featureGateDefaults defines the default enabled/disabled state for each feature gate
 // when the user does not explicitly specify a value. To enable a feature gate by default
 // in a future version, simply change its value here — no CRD schema change is needed.
 var featureGateDefaults = map[string]bool{
 	FeatureGateChangeStreams: false,
+	FeatureGateVectorSearch:  false,
 	FeatureGateIOUring:       false,
 }
 
@@ -23,24 +31,105 @@ func IsFeatureGateEnabled(documentdb *DocumentDB, featureGate string) bool {
 }
 
 // IsPVRecoveryConfigured checks if PV recovery is configured for the DocumentDB instance.
+// A retained PV adopted following spec.bootstrap.adoptRetainedVolumes is
+// recovered from in the same way.
 func (d *DocumentDB) IsPVRecoveryConfigured() bool {
-	return d.Spec.Bootstrap != nil &&
-		d.Spec.Bootstrap.Recovery != nil &&
-		d.Spec.Bootstrap.Recovery.PersistentVolume != nil &&
-		d.Spec.Bootstrap.Recovery.PersistentVolume.Name != ""
+	return d.GetPVNameForRecovery() != ""
 }
 
-// GetPVNameForRecovery returns the PV name configured for recovery, or empty string if not configured.
+// GetPVNameForRecovery returns the PV name configured for recovery, or the
+// adopted one, or empty string if not configured.
 func (d *DocumentDB) GetPVNameForRecovery() string {
-	if !d.IsPVRecoveryConfigured() {
-		return ""
+	if d.Spec.Bootstrap != nil && d.Spec.Bootstrap.Recovery != nil && d.Spec.Bootstrap.Recovery.PersistentVolume != nil {
+		return d.Spec.Bootstrap.Recovery.PersistentVolume.Name
 	}
-	return d.Spec.Bootstrap.Recovery.PersistentVolume.Name
+	if d.Spec.Bootstrap != nil && d.Spec.Bootstrap.AdoptRetainedVolumes {
+		return d.Status.AdoptedVolume
+	}
+	return ""
+}
+
+// IsCloneConfigured checks if the DocumentDB instance is cloned from another DocumentDB.
+func (d *DocumentDB) IsCloneConfigured() bool {
+	return d.Spec.Bootstrap != nil && d.Spec.Bootstrap.Clone != nil
+}
+
+// GetCloneSource returns the namespaced name of the DocumentDB the instance is
+// cloned from, defaulting the namespace to its own. It returns an empty name
+// if clone is not configured.
+func (d *DocumentDB) GetCloneSource() types.NamespacedName {
+	if !d.IsCloneConfigured() {
+		return types.NamespacedName{}
+	}
+	source := d.Spec.Bootstrap.Clone.SourceRef
+	if source.Namespace == "" {
+		return types.NamespacedName{Name: source.Name, Namespace: d.Namespace}
+	}
+	return types.NamespacedName{Name: source.Name, Namespace: source.Namespace}
 }
 
 // ShouldWarnAboutRetainedPVs returns true if the reclaim policy is Retain (explicitly or by default).
 // Default is Retain, so warn unless explicitly set to Delete.
 func (d *DocumentDB) ShouldWarnAboutRetainedPVs() bool {
-	policy := d.Spec.Resource.Storage.PersistentVolumeReclaimPolicy
-	return policy == "" || policy == "Retain"
+	return d.EffectivePersistentVolumeReclaimPolicy() == "Retain"
+}
+
+// EffectivePersistentVolumeReclaimPolicy returns the reclaim policy of the
+// PersistentVolumes of the cluster, Retain unless set otherwise.
+func (d *DocumentDB) EffectivePersistentVolumeReclaimPolicy() string {
+	if policy := d.Spec.Resource.Storage.PersistentVolumeReclaimPolicy; policy != "" {
+		return policy
+	}
+	return "Retain"
+}
+
+// EffectiveBackupRetentionDays returns how many days the backups of the
+// cluster are retained when they do not set their own retention.
+func (d *DocumentDB) EffectiveBackupRetentionDays() int {
+	if d.Spec.Backup == nil {
+		return DefaultBackupRetentionDays
+	}
+	return d.Spec.Backup.RetentionDays
+}
+
+// GetInProgressOperation returns the in-progress operation of the given type, or nil.
+func (d *DocumentDB) GetInProgressOperation(opType OperationType) *InProgressOperation {
+	for i := range d.Status.InProgressOperations {
+		if d.Status.InProgressOperations[i].Type == opType {
+			return &d.Status.InProgressOperations[i]
+		}
+	}
+	return nil
+}
+
+// SetInProgressOperation records an operation of the given type as in progress.
+// StartedAt is preserved when the operation is already recorded with the same target.
+// Returns true if the status changed.
+func (d *DocumentDB) SetInProgressOperation(opType OperationType, target string, now metav1.Time) bool {
+	if op := d.GetInProgressOperation(opType); op != nil {
+		if op.Target == target {
+			return false
+		}
+		op.Target = target
+		op.StartedAt = now
+		return true
+	}
+	d.Status.InProgressOperations = append(d.Status.InProgressOperations, InProgressOperation{
+		Type:      opType,
+		Target:    target,
+		StartedAt: now,
+	})
+	return true
+}
+
+// ClearInProgressOperation removes the operation of the given type. Returns true if the status changed.
+func (d *DocumentDB) ClearInProgressOperation(opType OperationType) bool {
+	before := len(d.Status.InProgressOperations)
+	d.Status.InProgressOperations = slices.DeleteFunc(d.Status.InProgressOperations, func(op InProgressOperation) bool {
+		return op.Type == opType
+	})
+	if len(d.Status.InProgressOperations) == 0 {
+		d.Status.InProgressOperations = nil
+	}
+	return len(d.Status.InProgressOperations) != before
 }
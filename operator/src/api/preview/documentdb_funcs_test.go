@@ -4,8 +4,12 @@
 package preview
 
 import (
+	"time"
+
 	. "github.com/onsi/ginkgo/v2"
 	. "github.com/onsi/gomega"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
 )
 
 var _ = Describe("IsFeatureGateEnabled", func() {
@@ -102,6 +106,30 @@ var _ = Describe("IsFeatureGateEnabled", func() {
 })
 
 var _ = Describe("DocumentDB Methods", func() {
+	Describe("GetCloneSource", func() {
+		It("returns an empty name when clone is not configured", func() {
+			db := &DocumentDB{Spec: DocumentDBSpec{Bootstrap: &BootstrapConfiguration{}}}
+			Expect(db.IsCloneConfigured()).To(BeFalse())
+			Expect(db.GetCloneSource()).To(Equal(types.NamespacedName{}))
+		})
+
+		It("defaults the namespace to the namespace of the clone", func() {
+			db := &DocumentDB{
+				ObjectMeta: metav1.ObjectMeta{Name: "clone", Namespace: "staging"},
+				Spec: DocumentDBSpec{
+					Bootstrap: &BootstrapConfiguration{
+						Clone: &CloneConfiguration{SourceRef: CloneSourceReference{Name: "prod"}},
+					},
+				},
+			}
+			Expect(db.IsCloneConfigured()).To(BeTrue())
+			Expect(db.GetCloneSource()).To(Equal(types.NamespacedName{Name: "prod", Namespace: "staging"}))
+
+			db.Spec.Bootstrap.Clone.SourceRef.Namespace = "production"
+			Expect(db.GetCloneSource()).To(Equal(types.NamespacedName{Name: "prod", Namespace: "production"}))
+		})
+	})
+
 	Describe("IsPVRecoveryConfigured", func() {
 		It("returns false when bootstrap is nil", func() {
 			db := &DocumentDB{
@@ -194,6 +222,18 @@ var _ = Describe("DocumentDB Methods", func() {
 			}
 			Expect(db.GetPVNameForRecovery()).To(Equal("my-retained-pv"))
 		})
+
+		It("returns the adopted PV name with adoptRetainedVolumes", func() {
+			db := &DocumentDB{
+				Spec:   DocumentDBSpec{Bootstrap: &BootstrapConfiguration{AdoptRetainedVolumes: true}},
+				Status: DocumentDBStatus{AdoptedVolume: "adopted-pv"},
+			}
+			Expect(db.GetPVNameForRecovery()).To(Equal("adopted-pv"))
+			Expect(db.IsPVRecoveryConfigured()).To(BeTrue())
+
+			db.Spec.Bootstrap.AdoptRetainedVolumes = false
+			Expect(db.IsPVRecoveryConfigured()).To(BeFalse())
+		})
 	})
 
 	Describe("ShouldWarnAboutRetainedPVs", func() {
@@ -236,4 +276,69 @@ var _ = Describe("DocumentDB Methods", func() {
 			Expect(db.ShouldWarnAboutRetainedPVs()).To(BeFalse())
 		})
 	})
+
+	Describe("EffectiveBackupRetentionDays", func() {
+		It("defaults to 30 days without spec.backup", func() {
+			Expect((&DocumentDB{}).EffectiveBackupRetentionDays()).To(Equal(DefaultBackupRetentionDays))
+		})
+
+		It("returns the retention of spec.backup", func() {
+			db := &DocumentDB{Spec: DocumentDBSpec{Backup: &BackupConfiguration{RetentionDays: 7}}}
+			Expect(db.EffectiveBackupRetentionDays()).To(Equal(7))
+		})
+	})
+})
+
+var _ = Describe("InProgressOperations", func() {
+	var (
+		documentdb *DocumentDB
+		t0         metav1.Time
+		t1         metav1.Time
+	)
+
+	BeforeEach(func() {
+		documentdb = &DocumentDB{}
+		t0 = metav1.NewTime(time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC))
+		t1 = metav1.NewTime(t0.Add(time.Hour))
+	})
+
+	It("returns nil when no operation is recorded", func() {
+		Expect(documentdb.GetInProgressOperation(OperationExtensionUpgrade)).To(BeNil())
+	})
+
+	It("records a new operation", func() {
+		Expect(documentdb.SetInProgressOperation(OperationExtensionUpgrade, "0.111-0", t0)).To(BeTrue())
+		op := documentdb.GetInProgressOperation(OperationExtensionUpgrade)
+		Expect(op).NotTo(BeNil())
+		Expect(op.Target).To(Equal("0.111-0"))
+		Expect(op.StartedAt).To(Equal(t0))
+	})
+
+	It("keeps StartedAt when the same operation is recorded again", func() {
+		documentdb.SetInProgressOperation(OperationExtensionUpgrade, "0.111-0", t0)
+		Expect(documentdb.SetInProgressOperation(OperationExtensionUpgrade, "0.111-0", t1)).To(BeFalse())
+		Expect(documentdb.GetInProgressOperation(OperationExtensionUpgrade).StartedAt).To(Equal(t0))
+	})
+
+	It("restarts the operation when the target changes", func() {
+		documentdb.SetInProgressOperation(OperationExtensionUpgrade, "0.111-0", t0)
+		Expect(documentdb.SetInProgressOperation(OperationExtensionUpgrade, "0.112-0", t1)).To(BeTrue())
+		op := documentdb.GetInProgressOperation(OperationExtensionUpgrade)
+		Expect(op.Target).To(Equal("0.112-0"))
+		Expect(op.StartedAt).To(Equal(t1))
+		Expect(documentdb.Status.InProgressOperations).To(HaveLen(1))
+	})
+
+	It("clears only the requested operation", func() {
+		documentdb.SetInProgressOperation(OperationExtensionUpgrade, "0.111-0", t0)
+		documentdb.SetInProgressOperation(OperationDemotionTokenWait, "cluster-a", t0)
+
+		Expect(documentdb.ClearInProgressOperation(OperationExtensionUpgrade)).To(BeTrue())
+		Expect(documentdb.GetInProgressOperation(OperationExtensionUpgrade)).To(BeNil())
+		Expect(documentdb.GetInProgressOperation(OperationDemotionTokenWait)).NotTo(BeNil())
+
+		Expect(documentdb.ClearInProgressOperation(OperationExtensionUpgrade)).To(BeFalse())
+		Expect(documentdb.ClearInProgressOperation(OperationDemotionTokenWait)).To(BeTrue())
+		Expect(documentdb.Status.InProgressOperations).To(BeNil())
+	})
 })
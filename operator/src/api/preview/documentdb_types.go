@@ -14,6 +14,10 @@ const (
 	// FeatureGateChangeStreams enables change stream support by setting wal_level=logical.
 	FeatureGateChangeStreams = "ChangeStreams"
 
+	// FeatureGateVectorSearch enables the HNSW vector indexes and the
+	// pre-filtering of vector searches of the DocumentDB extension.
+	FeatureGateVectorSearch = "VectorSearch"
+
 	// FeatureGateIOUring enables PostgreSQL 18 asynchronous I/O via io_method=io_uring
 	// and relaxes the postgres container seccomp profile so the io_uring_setup/enter/register
 	// syscalls (stripped from the container runtime's default profile) are allowed.
@@ -24,19 +28,33 @@ const (
 
 // DocumentDBSpec defines the desired state of DocumentDB.
 // +kubebuilder:validation:XValidation:rule="!has(self.clusterReplication) || ((has(self.clusterReplication.disableTLS) && self.clusterReplication.disableTLS) || (has(self.tls) && has(self.tls.postgres) && has(self.tls.postgres.replicationTLSSecret) && has(self.tls.postgres.clientCASecret)))",message="when spec.clusterReplication is set, either spec.clusterReplication.disableTLS must be true or spec.tls.postgres.replicationTLSSecret and spec.tls.postgres.clientCASecret must be provided"
+// +kubebuilder:validation:XValidation:rule="has(self.classRef) || (has(self.instancesPerNode) && has(self.resource) && has(self.resource.storage) && has(self.resource.storage.pvcSize))",message="spec.instancesPerNode and spec.resource.storage.pvcSize are required unless spec.classRef is set"
+// +kubebuilder:validation:XValidation:rule="!(has(self.bootstrap) && has(self.bootstrap.pgBaseBackup) && has(self.clusterReplication))",message="spec.bootstrap.pgBaseBackup cannot be combined with spec.clusterReplication"
+// +kubebuilder:validation:XValidation:rule="!(has(self.migration) && (has(self.bootstrap) || has(self.clusterReplication)))",message="spec.migration cannot be combined with spec.bootstrap or spec.clusterReplication"
 type DocumentDBSpec struct {
-	// NodeCount is the number of nodes in the DocumentDB cluster. Must be 1.
+	// ClassRef selects a DocumentDBClusterClass and one of its sizes. Every field
+	// that the class defines and this spec leaves unset is taken from the class.
+	// +optional
+	ClassRef *ClusterClassReference `json:"classRef,omitempty"`
+
+	// NodeCount is the number of nodes in the DocumentDB cluster. Must be 1:
+	// multi-node (sharded) clusters are not supported yet.
 	// +kubebuilder:validation:Minimum=1
 	// +kubebuilder:validation:Maximum=1
-	NodeCount int `json:"nodeCount"`
+	// +kubebuilder:default=1
+	// +optional
+	NodeCount int `json:"nodeCount,omitempty"`
 
 	// InstancesPerNode is the number of DocumentDB instances per node. Range: 1-3.
+	// Required unless spec.classRef is set.
 	// +kubebuilder:validation:Minimum=1
 	// +kubebuilder:validation:Maximum=3
-	InstancesPerNode int `json:"instancesPerNode"`
+	// +optional
+	InstancesPerNode int `json:"instancesPerNode,omitempty"`
 
 	// Resource specifies the storage resources for DocumentDB.
-	Resource Resource `json:"resource"`
+	// +optional
+	Resource Resource `json:"resource,omitempty"`
 
 	// DocumentDBVersion specifies the version for all DocumentDB components (engine, gateway).
 	// When set, this overrides the default versions for image.documentDB and image.gateway.
@@ -55,6 +73,16 @@ type DocumentDBSpec struct {
 	// +optional
 	ImagePullSecrets []corev1.LocalObjectReference `json:"imagePullSecrets,omitempty"`
 
+	// ImagePullPolicy applies to every image of the cluster: PostgreSQL, the
+	// DocumentDB extension, the gateway and the helper pods and Jobs the
+	// operator runs. Set it to IfNotPresent or Never in air-gapped
+	// environments. When unset, the operator settings
+	// DOCUMENTDB_IMAGE_PULL_POLICY and GATEWAY_IMAGE_PULL_POLICY apply to the
+	// extension and gateway images, and the Kubernetes default to the others.
+	// +kubebuilder:validation:Enum=Always;Never;IfNotPresent
+	// +optional
+	ImagePullPolicy corev1.PullPolicy `json:"imagePullPolicy,omitempty"`
+
 	// DocumentDbCredentialSecret is the name of the Kubernetes Secret containing credentials
 	// for the DocumentDB gateway (expects keys `username` and `password`). If omitted,
 	// a default secret name `documentdb-credentials` is used.
@@ -71,7 +99,12 @@ type DocumentDBSpec struct {
 	// +optional
 	Postgres *PostgresSpec `json:"postgres,omitempty"`
 
-	// Plugins groups CNPG plugin configuration (sidecar injector name, WAL replica name).
+	// Gateway configures the gateway sidecar container.
+	// +optional
+	Gateway *GatewaySpec `json:"gateway,omitempty"`
+
+	// Plugins groups CNPG plugin configuration (sidecar injector name, WAL replica name,
+	// additional CNPG-I plugins).
 	// All fields are optional; defaults are preserved when omitted.
 	// +optional
 	Plugins *PluginsSpec `json:"plugins,omitempty"`
@@ -80,8 +113,18 @@ type DocumentDBSpec struct {
 	// This can be a LoadBalancer or ClusterIP service.
 	ExposeViaService ExposeViaService `json:"exposeViaService,omitempty"`
 
+	// AdditionalServices declares extra Services that CNPG manages next to
+	// its default -rw, -ro and -r Services, e.g. to route a client to the
+	// replicas. CNPG sets the selector of each one from its selectorType and
+	// always adds the PostgreSQL port; further ports, such as the gateway
+	// port, 10260 by default, go in serviceTemplate.spec.ports.
+	// +kubebuilder:validation:MaxItems=10
+	// +optional
+	AdditionalServices []cnpgv1.ManagedService `json:"additionalServices,omitempty"`
+
 	// Environment specifies the cloud environment for deployment
-	// This determines cloud-specific service annotations for LoadBalancer services
+	// This determines cloud-specific service annotations for LoadBalancer services.
+	// When unset, the cloud the operator detected from the nodes applies.
 	// +kubebuilder:validation:Enum=eks;aks;gke
 	Environment string `json:"environment,omitempty"`
 
@@ -101,6 +144,28 @@ type DocumentDBSpec struct {
 	// +optional
 	Backup *BackupConfiguration `json:"backup,omitempty"`
 
+	// Export schedules logical exports of the databases with mongodump, in
+	// addition to the physical backups.
+	// +optional
+	Export *ExportConfiguration `json:"export,omitempty"`
+
+	// Migration keeps the cluster in sync with another DocumentDB cluster
+	// through logical replication, until the clients of the source are cut
+	// over to it. It can only be set when the cluster is created.
+	// +optional
+	Migration *MigrationConfiguration `json:"migration,omitempty"`
+
+	// SelfHeal lets the operator remediate replicas that stay unhealthy,
+	// instead of waiting for an administrator to recreate them.
+	// +optional
+	SelfHeal *SelfHealConfiguration `json:"selfHeal,omitempty"`
+
+	// Eviction tells the cluster autoscaler and Karpenter which database pods
+	// they may evict to consolidate nodes, so that they drain the nodes of
+	// the replicas but leave the primary alone.
+	// +optional
+	Eviction *EvictionConfiguration `json:"eviction,omitempty"`
+
 	// FeatureGates enables or disables optional DocumentDB features.
 	// Keys are PascalCase feature names following the Kubernetes feature gate convention.
 	// Example: {"ChangeStreams": true}
@@ -108,12 +173,33 @@ type DocumentDBSpec struct {
 	// IMPORTANT: When adding a new feature gate, update ALL of the following:
 	// 1. Add a new FeatureGate* constant in documentdb_types.go
 	// 2. Add the key name to the XValidation CEL rule's allowed list below
-	// 3. Add a default entry in the featureGateDefaults map in documentdb_types.go
+	// 3. Add a default entry in the featureGateDefaults map in documentdb_funcs.go
+	// 4. Describe what it requires in the capabilities list in internal/utils/capabilities.go
 	//
 	// +optional
-	// +kubebuilder:validation:XValidation:rule="self.all(key, key in ['ChangeStreams', 'IOUring'])",message="unsupported feature gate key; allowed keys: ChangeStreams, IOUring"
+	// +kubebuilder:validation:XValidation:rule="self.all(key, key in ['ChangeStreams', 'VectorSearch', 'IOUring'])",message="unsupported feature gate key; allowed keys: ChangeStreams, VectorSearch, IOUring"
 	FeatureGates map[string]bool `json:"featureGates,omitempty"`
 
+	// ChangeStreams tunes the PostgreSQL settings behind change streams. It
+	// requires the ChangeStreams feature gate.
+	// +optional
+	ChangeStreams *ChangeStreamsConfiguration `json:"changeStreams,omitempty"`
+
+	// TTL tunes the background task of the DocumentDB extension that deletes
+	// the documents expired by TTL indexes.
+	// +optional
+	TTL *TTLConfiguration `json:"ttl,omitempty"`
+
+	// Quotas cap the storage of the databases of a cluster shared by several
+	// applications. The operator reports the usage of each database in
+	// status.quotas and records an event when one exceeds its quota; it does
+	// not block the writes.
+	// +listType=map
+	// +listMapKey=database
+	// +kubebuilder:validation:MaxItems=100
+	// +optional
+	Quotas []DatabaseQuota `json:"quotas,omitempty"`
+
 	// SchemaVersion controls the desired schema version for the DocumentDB extension.
 	//
 	// The operator never changes your database schema unless you ask:
@@ -142,11 +228,223 @@ type DocumentDBSpec struct {
 	// +optional
 	Affinity cnpgv1.AffinityConfiguration `json:"affinity,omitempty"`
 
+	// NodeMaintenanceWindow tells CNPG that nodes are being drained, passed
+	// through to the underlying CNPG Cluster. While inProgress is true, CNPG
+	// lets the pods of a node be evicted and, unless reusePVC is false,
+	// recreates them on their volumes once the node is back; the operator
+	// pauses spec.selfHeal meanwhile.
+	// +optional
+	NodeMaintenanceWindow *cnpgv1.NodeMaintenanceWindow `json:"nodeMaintenanceWindow,omitempty"`
+
+	// PrimaryUpdateStrategy controls how the primary is updated during a
+	// rolling update, once all the replicas run the new spec:
+	//   - "unsupervised" (default): CNPG updates it with primaryUpdateMethod.
+	//   - "supervised": CNPG waits, in the "Waiting for user action" phase,
+	//     for an administrator to switch over to a replica, for example with
+	//     a Switchover DocumentDBOpsRequest.
+	// +kubebuilder:validation:Enum=unsupervised;supervised
+	// +optional
+	PrimaryUpdateStrategy cnpgv1.PrimaryUpdateStrategy `json:"primaryUpdateStrategy,omitempty"`
+
+	// PrimaryUpdateMethod controls how an unsupervised update replaces the
+	// primary:
+	//   - "switchover" (default): a replica that already runs the new spec is
+	//     promoted first, and the former primary is then restarted.
+	//   - "restart": the primary is restarted in place, which keeps the
+	//     cluster unavailable for writes until it is back.
+	// +kubebuilder:validation:Enum=switchover;restart
+	// +optional
+	PrimaryUpdateMethod cnpgv1.PrimaryUpdateMethod `json:"primaryUpdateMethod,omitempty"`
+
 	// Monitoring configures observability via an OTel Collector sidecar.
 	// +optional
 	Monitoring *MonitoringSpec `json:"monitoring,omitempty"`
+
+	// DeletionProtection, when true, holds a deleted DocumentDB in Terminating
+	// state: the operator keeps its finalizer and does not tear down the cluster
+	// until this field is set back to false.
+	// +optional
+	DeletionProtection bool `json:"deletionProtection,omitempty"`
+
+	// DeletionPolicy controls what happens to the underlying CNPG Cluster when
+	// the DocumentDB is deleted:
+	//   - "Delete" (default): the CNPG Cluster and its PVCs are deleted with it.
+	//   - "Orphan": the CNPG Cluster is detached and left running with its PVCs,
+	//     so that the data can be salvaged manually.
+	// +kubebuilder:validation:Enum=Delete;Orphan
+	// +kubebuilder:default=Delete
+	// +optional
+	DeletionPolicy DeletionPolicy `json:"deletionPolicy,omitempty"`
+
+	// CostLabels are stamped onto every object the operator derives from this
+	// DocumentDB (the CNPG Cluster, its pods, PVCs and Services) so that
+	// chargeback tools such as Kubecost can attribute spend, e.g. per team.
+	// Labels set by the operator itself take precedence.
+	// +optional
+	CostLabels map[string]string `json:"costLabels,omitempty"`
+
+	// InheritedMetadata holds labels and annotations that are added to the
+	// database pods, PVCs and Services, e.g. for service mesh injection.
+	// Labels set by the operator and spec.costLabels take precedence.
+	// +optional
+	InheritedMetadata *InheritedMetadata `json:"inheritedMetadata,omitempty"`
+
+	// Istio adapts the database pods to the Istio sidecar injected in their
+	// namespace, so that it does not break replication or the startup of
+	// the gateway.
+	// +optional
+	Istio *IstioConfiguration `json:"istio,omitempty"`
+
+	// ServiceAccount customizes the dedicated ServiceAccount of the cluster,
+	// which runs the database pods and takes the backups, e.g. to bind it to a
+	// cloud identity with IRSA or Workload Identity.
+	// +optional
+	ServiceAccount *ServiceAccountSpec `json:"serviceAccount,omitempty"`
+
+	// SecurityContext overrides the security context of the database pods and
+	// of their gateway container, e.g. to meet a PodSecurity "restricted"
+	// policy with a specific UID range. Unset fields keep the operator defaults.
+	// +optional
+	SecurityContext *SecurityContextSpec `json:"securityContext,omitempty"`
+}
+
+// SecurityContextSpec overrides the security context of a DocumentDB cluster.
+// The fields apply to the PostgreSQL and gateway containers alike.
+type SecurityContextSpec struct {
+	// RunAsUser is the UID the containers run as. Cannot be combined with
+	// spec.postgres.uid.
+	// +kubebuilder:validation:Minimum=1
+	// +optional
+	RunAsUser *int64 `json:"runAsUser,omitempty"`
+
+	// RunAsGroup is the primary GID of the containers. Cannot be combined with
+	// spec.postgres.gid.
+	// +kubebuilder:validation:Minimum=0
+	// +optional
+	RunAsGroup *int64 `json:"runAsGroup,omitempty"`
+
+	// FSGroup owns the mounted volumes. Defaults to runAsGroup.
+	// +kubebuilder:validation:Minimum=0
+	// +optional
+	FSGroup *int64 `json:"fsGroup,omitempty"`
+
+	// SeccompProfile replaces the RuntimeDefault seccomp profile, including the
+	// profile set by the IOUring feature gate.
+	// +optional
+	SeccompProfile *corev1.SeccompProfile `json:"seccompProfile,omitempty"`
+
+	// ReadOnlyRootFilesystem mounts the root filesystem of the containers
+	// read-only. Defaults to true for PostgreSQL and false for the gateway.
+	// +optional
+	ReadOnlyRootFilesystem *bool `json:"readOnlyRootFilesystem,omitempty"`
+}
+
+// ServiceAccountSpec customizes the ServiceAccount of a DocumentDB cluster.
+type ServiceAccountSpec struct {
+	// Annotations are added to the ServiceAccount, e.g.
+	// eks.amazonaws.com/role-arn or azure.workload.identity/client-id.
+	// +optional
+	Annotations map[string]string `json:"annotations,omitempty"`
+
+	// Labels are added to the ServiceAccount.
+	// +optional
+	Labels map[string]string `json:"labels,omitempty"`
+
+	// ImagePullSecrets are added to the ServiceAccount, after those of
+	// spec.imagePullSecrets.
+	// +optional
+	ImagePullSecrets []corev1.LocalObjectReference `json:"imagePullSecrets,omitempty"`
+
+	// WorkloadIdentity binds the ServiceAccount to a cloud identity through
+	// the workload identity of the provider. Recoveries from an object store
+	// and exports without a credentials Secret then authenticate as that
+	// identity, without static access keys.
+	// +optional
+	WorkloadIdentity *WorkloadIdentitySpec `json:"workloadIdentity,omitempty"`
+}
+
+// WorkloadIdentityProvider is the cloud provider of a workload identity.
+// +kubebuilder:validation:Enum=AWS;Azure;GCP
+type WorkloadIdentityProvider string
+
+const (
+	// WorkloadIdentityAWS is an IAM role assumed through IAM Roles for
+	// Service Accounts (EKS).
+	WorkloadIdentityAWS WorkloadIdentityProvider = "AWS"
+	// WorkloadIdentityAzure is a managed identity or application federated
+	// through Azure Workload Identity (AKS).
+	WorkloadIdentityAzure WorkloadIdentityProvider = "Azure"
+	// WorkloadIdentityGCP is a Google service account impersonated through
+	// GKE Workload Identity.
+	WorkloadIdentityGCP WorkloadIdentityProvider = "GCP"
+)
+
+// WorkloadIdentitySpec selects the cloud identity of a DocumentDB cluster.
+// The identity must trust the ServiceAccount of the cluster, which is named
+// after the CNPG Cluster, in the namespace of the DocumentDB.
+type WorkloadIdentitySpec struct {
+	// Provider is the cloud provider of the identity.
+	Provider WorkloadIdentityProvider `json:"provider"`
+
+	// Identity is the ARN of the IAM role for AWS, the client ID of the
+	// managed identity or application for Azure, or the email of the
+	// Google service account for GCP.
+	// +kubebuilder:validation:MinLength=1
+	Identity string `json:"identity"`
 }
 
+// InheritedMetadata holds the user-defined metadata of the objects the CNPG
+// Cluster creates.
+type InheritedMetadata struct {
+	// Labels are added to the pods, PVCs and Services of the cluster.
+	// +optional
+	Labels map[string]string `json:"labels,omitempty"`
+
+	// Annotations are added to the pods, PVCs and Services of the cluster.
+	// +optional
+	Annotations map[string]string `json:"annotations,omitempty"`
+}
+
+// IstioConfiguration sets the Istio annotations of the database pods, which
+// Istio reads when it injects the sidecar.
+type IstioConfiguration struct {
+	// ExcludeDatabasePorts keeps the PostgreSQL port and the status port of
+	// the CNPG instance manager out of the sidecar, so that replication and
+	// the health checks of CNPG, which bring their own TLS, bypass it. It is
+	// ignored with the Istio cross-cloud networking strategy, which routes
+	// replication through the mesh. Defaults to true.
+	// +optional
+	ExcludeDatabasePorts *bool `json:"excludeDatabasePorts,omitempty"`
+
+	// HoldApplicationUntilProxyStarts starts the containers of the database
+	// pods once the sidecar is ready, so that their first connections do not
+	// fail. Defaults to true.
+	// +optional
+	HoldApplicationUntilProxyStarts *bool `json:"holdApplicationUntilProxyStarts,omitempty"`
+}
+
+// ClusterClassReference selects a DocumentDBClusterClass and one of its sizes.
+type ClusterClassReference struct {
+	// Name is the name of the DocumentDBClusterClass.
+	// +kubebuilder:validation:MinLength=1
+	Name string `json:"name"`
+
+	// Size is the name of one of the sizes of the class. Defaults to the
+	// class's defaultSize.
+	// +optional
+	Size string `json:"size,omitempty"`
+}
+
+// DeletionPolicy is the action taken on the CNPG Cluster when its DocumentDB is deleted.
+type DeletionPolicy string
+
+const (
+	// DeletionPolicyDelete deletes the CNPG Cluster together with the DocumentDB.
+	DeletionPolicyDelete DeletionPolicy = "Delete"
+	// DeletionPolicyOrphan leaves the CNPG Cluster behind when the DocumentDB is deleted.
+	DeletionPolicyOrphan DeletionPolicy = "Orphan"
+)
+
 // ImageSpec groups container image settings for the DocumentDB stack.
 // All fields are optional; the operator falls back to documentDBVersion,
 // environment variables, and built-in defaults in that order.
@@ -176,6 +474,7 @@ type ImageSpec struct {
 // All fields are optional.
 //
 // +kubebuilder:validation:XValidation:rule="has(self.uid) == has(self.gid)",message="uid and gid must be set together"
+// +kubebuilder:validation:XValidation:rule="!has(self.superuserSecret) || (has(self.enableSuperuserAccess) && self.enableSuperuserAccess)",message="superuserSecret requires enableSuperuserAccess"
 type PostgresSpec struct {
 	// UID is the numeric user ID under which the PostgreSQL server process runs.
 	// When set, GID must also be set.
@@ -205,6 +504,60 @@ type PostgresSpec struct {
 	// max_prepared_transactions) cannot be overridden.
 	// +optional
 	Parameters map[string]string `json:"parameters,omitempty"`
+
+	// Probes overrides the startup, liveness and readiness probes of the
+	// PostgreSQL container, passed through to the underlying CNPG Cluster, for
+	// example to allow more time on slow storage. Unset fields keep the CNPG
+	// defaults. See spec.timeouts.startDelay for the startup probe window.
+	// +optional
+	Probes *cnpgv1.ProbesConfiguration `json:"probes,omitempty"`
+
+	// EnableSuperuserAccess allows logging in as the postgres superuser with
+	// a password, taken from SuperuserSecret or from the secret CNPG
+	// generates. Disabled by default, in which case the postgres password is
+	// blank and the superuser can only connect from within the instances; the
+	// operator itself does not need it.
+	// +optional
+	EnableSuperuserAccess *bool `json:"enableSuperuserAccess,omitempty"`
+
+	// SuperuserSecret is a basic-auth Secret, in the namespace of the
+	// DocumentDB, holding the username (postgres) and password of the
+	// superuser. Requires EnableSuperuserAccess.
+	// +optional
+	SuperuserSecret *cnpgv1.LocalObjectReference `json:"superuserSecret,omitempty"`
+}
+
+// GatewaySpec configures the gateway sidecar container.
+type GatewaySpec struct {
+	// Port is the port the gateway listens on and the DocumentDB Service
+	// exposes. It must not be a port of PostgreSQL (5432), of the CNPG
+	// instance manager (8000 and 9187), of the sidecar or of the Prometheus
+	// exporter. Defaults to the gateway port of the operator settings,
+	// 10260 unless changed. Changing it restarts the instances.
+	// +kubebuilder:validation:Minimum=1
+	// +kubebuilder:validation:Maximum=65535
+	// +optional
+	Port int32 `json:"port,omitempty"`
+
+	// Probes adds liveness and readiness probes to the gateway container.
+	// +optional
+	Probes *GatewayProbes `json:"probes,omitempty"`
+}
+
+// GatewayProbes configures the probes of the gateway container. The gateway
+// has no probes by default; each probe that is set checks that the gateway
+// accepts TCP connections on its port, with the given delays, periods and
+// thresholds.
+type GatewayProbes struct {
+	// Liveness restarts the gateway container when it stops accepting
+	// connections.
+	// +optional
+	Liveness *cnpgv1.Probe `json:"liveness,omitempty"`
+
+	// Readiness marks the pod not ready while the gateway does not accept
+	// connections, which also removes the instance from the CNPG Services.
+	// +optional
+	Readiness *cnpgv1.Probe `json:"readiness,omitempty"`
 }
 
 // PluginsSpec groups CNPG plugin configuration.
@@ -219,17 +572,243 @@ type PluginsSpec struct {
 	// cross-cluster replication.
 	// +optional
 	WalReplicaName string `json:"walReplicaName,omitempty"`
+
+	// Additional lists further CNPG-I plugins to enable on the CNPG Cluster,
+	// with their parameters, e.g. the Barman Cloud plugin as WAL archiver.
+	// They are passed through as is, after the sidecar injector plugin, and
+	// must be installed alongside CNPG.
+	// +kubebuilder:validation:MaxItems=10
+	// +optional
+	Additional []cnpgv1.PluginConfiguration `json:"additional,omitempty"`
 }
 
 // BootstrapConfiguration defines how to bootstrap a DocumentDB cluster.
+// +kubebuilder:validation:XValidation:rule="[has(self.recovery), has(self.import), has(self.clone), has(self.pgBaseBackup)].filter(x, x).size() <= 1",message="only one of recovery, import, clone and pgBaseBackup can be specified"
+// +kubebuilder:validation:XValidation:rule="!(has(self.adoptRetainedVolumes) && self.adoptRetainedVolumes) || !(has(self.recovery) || has(self.import) || has(self.clone) || has(self.pgBaseBackup))",message="adoptRetainedVolumes cannot be combined with recovery, import, clone or pgBaseBackup"
 type BootstrapConfiguration struct {
 	// Recovery configures recovery from a backup.
 	// +optional
 	Recovery *RecoveryConfiguration `json:"recovery,omitempty"`
+
+	// Import copies the data of an existing database into the new cluster
+	// once it is healthy.
+	// +optional
+	Import *ImportConfiguration `json:"import,omitempty"`
+
+	// Clone provisions the new cluster from a volume snapshot backup of an
+	// existing DocumentDB cluster, taken by the operator.
+	// +optional
+	Clone *CloneConfiguration `json:"clone,omitempty"`
+
+	// PgBaseBackup seeds the new cluster with a physical copy of a running
+	// DocumentDB cluster of the same Kubernetes cluster, streamed with
+	// pg_basebackup.
+	// +optional
+	PgBaseBackup *PgBaseBackupConfiguration `json:"pgBaseBackup,omitempty"`
+
+	// InitScripts are run in order, once each, when the new cluster is
+	// healthy and any import has succeeded, e.g. to create indexes or load
+	// reference data. They can be combined with the other options.
+	// +kubebuilder:validation:MaxItems=50
+	// +optional
+	InitScripts []InitScript `json:"initScripts,omitempty"`
+
+	// AdoptRetainedVolumes recovers a new cluster from the retained
+	// PersistentVolumes of an earlier DocumentDB of the same name and
+	// namespace, when there are any: the operator picks the data volume of
+	// its last primary and recovers from it as with recovery.persistentVolume.
+	// Without such volumes the cluster is initialized empty.
+	// +optional
+	AdoptRetainedVolumes bool `json:"adoptRetainedVolumes,omitempty"`
+}
+
+// InitScriptLanguage is the language of an init script.
+// +kubebuilder:validation:Enum=SQL;JavaScript
+type InitScriptLanguage string
+
+const (
+	// InitScriptLanguageSQL scripts are run with psql on the primary, as the
+	// postgres superuser, in a single transaction.
+	InitScriptLanguageSQL InitScriptLanguage = "SQL"
+	// InitScriptLanguageJavaScript scripts are run with mongosh through the
+	// gateway, as the user of the connection string Secret.
+	InitScriptLanguageJavaScript InitScriptLanguage = "JavaScript"
+)
+
+// InitScript references a script run when the cluster is bootstrapped.
+type InitScript struct {
+	// ConfigMapKeyRef selects the key of a ConfigMap, in the namespace of
+	// the DocumentDB, that holds the script.
+	// +kubebuilder:validation:Required
+	ConfigMapKeyRef cnpgv1.ConfigMapKeySelector `json:"configMapKeyRef"`
+
+	// Language is the language of the script.
+	// +kubebuilder:default=SQL
+	// +optional
+	Language InitScriptLanguage `json:"language,omitempty"`
+}
+
+// PgBaseBackupConfiguration defines the running cluster a new cluster is
+// copied from with pg_basebackup. The copy authenticates as the
+// streaming_replica user of the source with a TLS client certificate.
+type PgBaseBackupConfiguration struct {
+	// SourceService is the read-write Service of the source cluster, such as
+	// "<source>-rw" for a source in the same namespace, or
+	// "<source>-rw.<namespace>.svc" for a source in another namespace.
+	// +kubebuilder:validation:MinLength=1
+	SourceService string `json:"sourceService"`
+
+	// ReplicationTLSSecret names a kubernetes.io/tls Secret, in the namespace
+	// of the DocumentDB, holding a client certificate of the streaming_replica
+	// user trusted by the source. Defaults to "<source>-replication", the
+	// Secret CloudNativePG issues for a source in the same namespace.
+	// +optional
+	ReplicationTLSSecret string `json:"replicationTLSSecret,omitempty"`
+
+	// ServerCASecret names a Secret, in the namespace of the DocumentDB, whose
+	// ca.crt key verifies the server certificate of the source. Defaults to
+	// "<source>-ca", the Secret CloudNativePG creates for a source in the
+	// same namespace.
+	// +optional
+	ServerCASecret string `json:"serverCASecret,omitempty"`
+}
+
+// CloneConfiguration defines the DocumentDB cluster a new cluster is cloned from.
+type CloneConfiguration struct {
+	// SourceRef references the DocumentDB cluster to clone.
+	// +kubebuilder:validation:Required
+	SourceRef CloneSourceReference `json:"sourceRef"`
+}
+
+// CloneSourceReference references a DocumentDB cluster, in the namespace of
+// the clone or in another one.
+type CloneSourceReference struct {
+	// Name is the name of the source DocumentDB cluster.
+	// +kubebuilder:validation:MinLength=1
+	Name string `json:"name"`
+
+	// Namespace is the namespace of the source DocumentDB cluster. Defaults
+	// to the namespace of the clone. A source in another namespace must
+	// allow it with the documentdb.io/allow-clone-to-namespaces annotation.
+	// +optional
+	Namespace string `json:"namespace,omitempty"`
+}
+
+// ExportConfiguration schedules logical exports of a DocumentDB cluster to an
+// object store.
+type ExportConfiguration struct {
+	// Schedule is the cron expression, in the syntax of Kubernetes CronJobs,
+	// of the exports, e.g. "0 2 * * *".
+	// +kubebuilder:validation:MinLength=1
+	Schedule string `json:"schedule"`
+
+	// Databases lists the databases to export. Defaults to every database.
+	// +kubebuilder:validation:MaxItems=100
+	// +listType=set
+	// +optional
+	Databases []string `json:"databases,omitempty"`
+
+	// Destination is the object store the exports are uploaded to.
+	// +kubebuilder:validation:Required
+	Destination ExportDestination `json:"destination"`
+
+	// Suspend stops scheduling new exports.
+	// +optional
+	Suspend bool `json:"suspend,omitempty"`
+}
+
+// ExportDestination is an S3-compatible object store.
+type ExportDestination struct {
+	// DestinationPath is the S3 URL under which each export is written to a
+	// folder named after its UTC start time, e.g. "s3://bucket/exports".
+	// +kubebuilder:validation:Pattern=`^s3://.+`
+	DestinationPath string `json:"destinationPath"`
+
+	// EndpointURL is the endpoint of an S3-compatible object store other than
+	// AWS S3, e.g. MinIO.
+	// +optional
+	EndpointURL string `json:"endpointURL,omitempty"`
+
+	// CredentialsSecret names a Secret whose keys are passed to the upload
+	// as environment variables: AWS_ACCESS_KEY_ID, AWS_SECRET_ACCESS_KEY and
+	// optionally AWS_SESSION_TOKEN and AWS_REGION. Without it the upload uses
+	// the identity of the cluster ServiceAccount (see spec.serviceAccount).
+	// +optional
+	CredentialsSecret *cnpgv1.LocalObjectReference `json:"credentialsSecret,omitempty"`
+}
+
+// SelfHealConfiguration defines when the operator remediates a replica that
+// is not ready. Only replicas are remediated, one at a time, and only while
+// the primary is healthy and CNPG is not switching over, failing over or
+// upgrading the cluster: CNPG already fails over from an unhealthy primary.
+// +kubebuilder:validation:XValidation:rule="!has(self.recreateAfterMinutes) || !has(self.recloneAfterMinutes) || self.recreateAfterMinutes == 0 || self.recloneAfterMinutes == 0 || self.recloneAfterMinutes > self.recreateAfterMinutes",message="recloneAfterMinutes must be longer than recreateAfterMinutes"
+type SelfHealConfiguration struct {
+	// RecreateAfterMinutes deletes the Pod of a replica that has not been
+	// ready for this many minutes, so that CNPG recreates it on the same
+	// volumes. 0 disables it.
+	// +kubebuilder:validation:Minimum=0
+	// +optional
+	RecreateAfterMinutes int32 `json:"recreateAfterMinutes,omitempty"`
+
+	// RecloneAfterMinutes deletes the Pod and the PVCs of a replica that has
+	// not been ready for this many minutes, so that CNPG replaces it with a
+	// new replica cloned from the primary. The time is counted across the
+	// recreations of the Pod, so it must be longer than recreateAfterMinutes
+	// when both are set. 0 disables it.
+	// +kubebuilder:validation:Minimum=0
+	// +optional
+	RecloneAfterMinutes int32 `json:"recloneAfterMinutes,omitempty"`
+
+	// CooldownMinutes is the minimum time between two remediations of the
+	// cluster, which gives a remediated replica the time to catch up before
+	// the next one. 0 uses the default of 30 minutes.
+	// +kubebuilder:validation:Minimum=0
+	// +optional
+	CooldownMinutes int32 `json:"cooldownMinutes,omitempty"`
+}
+
+// MigrationConfiguration defines the DocumentDB cluster a cluster migrates
+// from.
+type MigrationConfiguration struct {
+	// SourceRef references the DocumentDB cluster to migrate from, in the
+	// namespace of this cluster.
+	// +kubebuilder:validation:Required
+	SourceRef cnpgv1.LocalObjectReference `json:"sourceRef"`
+
+	// Cutover, once set to true, makes the source read-only, waits for this
+	// cluster to apply its last changes, and routes the Service of the
+	// source to this cluster. It cannot be unset.
+	// +optional
+	Cutover bool `json:"cutover,omitempty"`
+}
+
+// ImportConfiguration defines the source of the data imported into a new
+// DocumentDB cluster.
+type ImportConfiguration struct {
+	// MongoDB imports from a MongoDB-compatible server with mongodump and
+	// mongorestore.
+	// +kubebuilder:validation:Required
+	MongoDB *MongoDBImportConfiguration `json:"mongodb"`
+}
+
+// MongoDBImportConfiguration defines an import from a MongoDB-compatible server.
+type MongoDBImportConfiguration struct {
+	// SourceURISecret selects the key of a Secret, in the namespace of the
+	// DocumentDB, that holds the connection string of the source server.
+	// +kubebuilder:validation:Required
+	SourceURISecret cnpgv1.SecretKeySelector `json:"sourceURISecret"`
+
+	// Databases lists the databases to import. Defaults to every database
+	// except admin, config and local.
+	// +kubebuilder:validation:MaxItems=100
+	// +listType=set
+	// +optional
+	Databases []string `json:"databases,omitempty"`
 }
 
 // RecoveryConfiguration defines recovery settings for bootstrapping a DocumentDB cluster.
-// +kubebuilder:validation:XValidation:rule="!(has(self.backup) && size(self.backup.name) > 0 && has(self.persistentVolume) && size(self.persistentVolume.name) > 0)",message="cannot specify both backup and persistentVolume recovery at the same time"
+// +kubebuilder:validation:XValidation:rule="[has(self.backup) && size(self.backup.name) > 0, has(self.persistentVolume) && size(self.persistentVolume.name) > 0, has(self.objectStore)].filter(x, x).size() <= 1",message="only one of backup, persistentVolume and objectStore recovery can be specified"
+// +kubebuilder:validation:XValidation:rule="!has(self.recoveryTarget) || has(self.objectStore) || (has(self.backup) && size(self.backup.name) > 0)",message="recoveryTarget requires backup or objectStore recovery"
 type RecoveryConfiguration struct {
 	// Backup specifies the source backup to restore from.
 	// +optional
@@ -241,6 +820,48 @@ type RecoveryConfiguration struct {
 	// Cannot be used together with Backup.
 	// +optional
 	PersistentVolume *PVRecoveryConfiguration `json:"persistentVolume,omitempty"`
+
+	// ObjectStore specifies a Barman object store archive to restore from,
+	// e.g. the base backups and WAL archived by a cluster that no longer
+	// exists. Cannot be used together with Backup or PersistentVolume.
+	// +optional
+	ObjectStore *ObjectStoreRecoveryConfiguration `json:"objectStore,omitempty"`
+
+	// RecoveryTarget stops the recovery at a point in time, a named restore
+	// point, a transaction, an LSN, or as soon as the data is consistent,
+	// instead of at the end of the WAL. It is passed to CNPG as is, and
+	// needs Backup or ObjectStore.
+	// +optional
+	RecoveryTarget *cnpgv1.RecoveryTarget `json:"recoveryTarget,omitempty"`
+}
+
+// ObjectStoreRecoveryConfiguration defines a Barman object store archive to
+// recover from.
+type ObjectStoreRecoveryConfiguration struct {
+	// DestinationPath is the URL of the object store the archive was written
+	// to: "s3://bucket/path", "gs://bucket/path", or an Azure Blob Storage
+	// "https://account.blob.core.windows.net/container/path".
+	// +kubebuilder:validation:Pattern=`^(s3|gs|https)://.+`
+	DestinationPath string `json:"destinationPath"`
+
+	// ServerName is the folder of the archive under DestinationPath, by
+	// default the name of the CNPG Cluster that wrote it.
+	// +kubebuilder:validation:MinLength=1
+	ServerName string `json:"serverName"`
+
+	// EndpointURL is the endpoint of an S3-compatible object store other than
+	// AWS S3, e.g. MinIO.
+	// +optional
+	EndpointURL string `json:"endpointURL,omitempty"`
+
+	// CredentialsSecret names a Secret holding the credentials of the object
+	// store: AWS_ACCESS_KEY_ID and AWS_SECRET_ACCESS_KEY for S3,
+	// AZURE_STORAGE_CONNECTION_STRING for Azure Blob Storage, or
+	// GOOGLE_APPLICATION_CREDENTIALS for Google Cloud Storage. Without it the
+	// recovery uses the identity of the cluster ServiceAccount (see
+	// spec.serviceAccount).
+	// +optional
+	CredentialsSecret *cnpgv1.LocalObjectReference `json:"credentialsSecret,omitempty"`
 }
 
 // PVRecoveryConfiguration defines settings for recovering from a retained PersistentVolume.
@@ -260,11 +881,28 @@ type BackupConfiguration struct {
 	// +kubebuilder:default=30
 	// +optional
 	RetentionDays int `json:"retentionDays,omitempty"`
+
+	// Velero prepares the cluster for Velero backups of its namespace: the
+	// database pods get a pre-backup hook that runs a CHECKPOINT, and the
+	// temporary PVC of a PV recovery is excluded from the backups.
+	// +optional
+	Velero *VeleroConfiguration `json:"velero,omitempty"`
+}
+
+// VeleroConfiguration configures the Velero backup hooks of the database pods.
+type VeleroConfiguration struct {
+	// HookTimeoutSeconds is how long Velero waits for the CHECKPOINT before
+	// failing the backup of the pod. Defaults to 60.
+	// +kubebuilder:validation:Minimum=1
+	// +kubebuilder:validation:Maximum=3600
+	// +optional
+	HookTimeoutSeconds int32 `json:"hookTimeoutSeconds,omitempty"`
 }
 
 type Resource struct {
 	// Storage configuration for DocumentDB persistent volumes.
-	Storage StorageConfiguration `json:"storage"`
+	// +optional
+	Storage StorageConfiguration `json:"storage,omitempty"`
 
 	// Memory specifies the memory limit for each DocumentDB instance pod.
 	// This value is passed to the CNPG Cluster's spec.resources.limits.memory
@@ -325,6 +963,112 @@ type Resource struct {
 	// or otel.memory pins that dimension to request == limit (Guaranteed).
 	// +optional
 	OTel *ComponentResources `json:"otel,omitempty"`
+
+	// HugePages reserves huge pages for the PostgreSQL container, in addition
+	// to its memory, and makes PostgreSQL allocate its shared memory from them
+	// (huge_pages=on). The nodes must pre-allocate enough pages of the size.
+	// +optional
+	HugePages *HugePagesConfiguration `json:"hugePages,omitempty"`
+}
+
+// ChangeStreamsConfiguration tunes the logical decoding that change streams
+// read from. Unset fields keep the PostgreSQL defaults.
+type ChangeStreamsConfiguration struct {
+	// RetentionHours is how long a change stream can stay idle and still be
+	// resumed from its resume token. Replication slots idle for longer are
+	// invalidated (idle_replication_slot_timeout, PostgreSQL 18 and later).
+	// It also applies to the slots of the replicas.
+	// +kubebuilder:validation:Minimum=1
+	// +kubebuilder:validation:Maximum=8760
+	// +optional
+	RetentionHours int32 `json:"retentionHours,omitempty"`
+
+	// MaxCursors is the number of replication slots reserved for change
+	// streams, on top of the 10 the operator reserves for replication
+	// (max_replication_slots and max_wal_senders).
+	// +kubebuilder:validation:Minimum=1
+	// +kubebuilder:validation:Maximum=100
+	// +optional
+	MaxCursors int32 `json:"maxCursors,omitempty"`
+
+	// MaxRetainedWALSize caps the WAL kept on the PVC for change streams to
+	// resume from, e.g. "10Gi" (max_slot_wal_keep_size). A change stream
+	// that falls further behind can no longer be resumed. The storage of
+	// the cluster must have room for it.
+	// +kubebuilder:validation:Pattern=`^[0-9]+(Mi|Gi)$`
+	// +optional
+	MaxRetainedWALSize string `json:"maxRetainedWALSize,omitempty"`
+}
+
+// EvictionPolicy is whether the node autoscalers may evict a pod.
+// +kubebuilder:validation:Enum=Allow;Block
+type EvictionPolicy string
+
+const (
+	// EvictionPolicyAllow marks the pods safe to evict.
+	EvictionPolicyAllow EvictionPolicy = "Allow"
+	// EvictionPolicyBlock keeps the autoscalers from evicting the pods.
+	EvictionPolicyBlock EvictionPolicy = "Block"
+)
+
+// EvictionConfiguration sets the eviction policy of the primary and of the
+// replicas.
+type EvictionConfiguration struct {
+	// Primary is the eviction policy of the primary. Defaults to Block.
+	// +optional
+	Primary EvictionPolicy `json:"primary,omitempty"`
+
+	// Replicas is the eviction policy of the replicas. Defaults to Allow.
+	// +optional
+	Replicas EvictionPolicy `json:"replicas,omitempty"`
+}
+
+// DatabaseQuota caps the storage of a database.
+type DatabaseQuota struct {
+	// Database is the name of the database.
+	// +kubebuilder:validation:MinLength=1
+	// +kubebuilder:validation:MaxLength=64
+	Database string `json:"database"`
+
+	// MaxStorage is the storage the documents and indexes of the database
+	// may use, e.g. "50Gi".
+	// +kubebuilder:validation:Pattern=`^[0-9]+(Mi|Gi|Ti)$`
+	MaxStorage string `json:"maxStorage"`
+}
+
+// TTLConfiguration tunes the deletion of the documents expired by TTL indexes.
+type TTLConfiguration struct {
+	// IntervalSeconds is how often the TTL task runs, once a minute by
+	// default. The operator reschedules the task's pg_cron job on the
+	// primary; unsetting it keeps the last interval.
+	// +kubebuilder:validation:Minimum=1
+	// +kubebuilder:validation:Maximum=60
+	// +optional
+	IntervalSeconds int32 `json:"intervalSeconds,omitempty"`
+
+	// BatchSize is the maximum number of documents a run of the TTL task
+	// deletes per index (documentdb.maxTTLDeleteBatchSize).
+	// +kubebuilder:validation:Minimum=1
+	// +kubebuilder:validation:Maximum=1000000
+	// +optional
+	BatchSize int32 `json:"batchSize,omitempty"`
+}
+
+// HugePagesConfiguration sizes the huge pages of the PostgreSQL container.
+type HugePagesConfiguration struct {
+	// PageSize is the size of the huge pages, 2Mi or 1Gi.
+	// +kubebuilder:validation:Enum="2Mi";"1Gi"
+	// +kubebuilder:default="2Mi"
+	// +optional
+	PageSize string `json:"pageSize,omitempty"`
+
+	// Size is the amount of huge page memory requested for the container, a
+	// multiple of PageSize (e.g. "2Gi"). PostgreSQL's shared_buffers default
+	// to three quarters of it, leaving the rest to its other shared memory;
+	// a shared_buffers set in spec.postgres.parameters must leave that room
+	// too, or PostgreSQL does not start.
+	// +kubebuilder:validation:Pattern=`^[0-9]+(Mi|Gi)$`
+	Size string `json:"size"`
 }
 
 // ComponentResources overrides the CPU and/or memory allocated to an individual
@@ -344,10 +1088,13 @@ type ComponentResources struct {
 	CPU string `json:"cpu,omitempty"`
 }
 
+// +kubebuilder:validation:XValidation:rule="!has(oldSelf.wal) || has(self.wal)",message="WAL storage cannot be removed once set"
 type StorageConfiguration struct {
 	// PvcSize is the size of the persistent volume claim for DocumentDB storage (e.g., "10Gi").
+	// Required unless spec.classRef is set.
 	// +kubebuilder:validation:MinLength=1
-	PvcSize string `json:"pvcSize"`
+	// +optional
+	PvcSize string `json:"pvcSize,omitempty"`
 
 	// StorageClass specifies the storage class for DocumentDB persistent volumes.
 	// If not specified, the cluster's default storage class will be used.
@@ -370,19 +1117,87 @@ type StorageConfiguration struct {
 	// WARNING: Setting this to "Delete" means all data will be permanently lost when
 	// the DocumentDB cluster is deleted. This cannot be undone.
 	//
+	// The default applies when neither the DocumentDB nor its cluster class
+	// sets a policy.
+	//
 	// +kubebuilder:validation:Enum=Retain;Delete
-	// +kubebuilder:default=Retain
 	// +optional
 	PersistentVolumeReclaimPolicy string `json:"persistentVolumeReclaimPolicy,omitempty"`
+
+	// MountOptions configures the mount options the operator sets on the
+	// PersistentVolumes of the cluster. By default they get nodev, noexec and
+	// nosuid, except on the local and hostpath provisioners that do not
+	// support mount options.
+	// +optional
+	MountOptions *MountOptionsConfiguration `json:"mountOptions,omitempty"`
+
+	// WAL moves the write-ahead log, which change streams and replicas read
+	// from, to a dedicated volume, so that the WAL retained for a change
+	// stream that falls behind cannot fill the data volume. It can be added
+	// to an existing cluster, which restarts its instances, but not removed.
+	// +optional
+	WAL *WALStorageConfiguration `json:"wal,omitempty"`
 }
 
+// WALStorageConfiguration configures the volume of the write-ahead log.
+type WALStorageConfiguration struct {
+	// PvcSize is the size of the WAL persistent volume claim (e.g., "10Gi").
+	// It can only be increased.
+	// +kubebuilder:validation:MinLength=1
+	PvcSize string `json:"pvcSize"`
+
+	// StorageClass is the storage class of the WAL volume. Defaults to the
+	// storage class of the data volume.
+	// +kubebuilder:validation:XValidation:rule="self == oldSelf",message="WAL storage class cannot be changed"
+	// +optional
+	StorageClass string `json:"storageClass,omitempty"`
+}
+
+// MountOptionsConfiguration overrides the security mount options the operator
+// sets on PersistentVolumes, for CSI drivers that reject them.
+type MountOptionsConfiguration struct {
+	// Disabled stops the operator from setting mount options on the
+	// PersistentVolumes, and removes the nodev, noexec and nosuid options it
+	// set before unless their StorageClass sets them.
+	// +optional
+	Disabled bool `json:"disabled,omitempty"`
+
+	// Options replaces the default nodev, noexec and nosuid options.
+	// +optional
+	Options []string `json:"options,omitempty"`
+
+	// StorageClassOverrides sets the mount options of the PersistentVolumes
+	// of specific StorageClasses, in place of options. They are applied even
+	// on the provisioners the operator otherwise skips.
+	// +listType=map
+	// +listMapKey=storageClass
+	// +optional
+	StorageClassOverrides []StorageClassMountOptions `json:"storageClassOverrides,omitempty"`
+}
+
+// StorageClassMountOptions are the mount options of the PersistentVolumes of
+// a StorageClass.
+type StorageClassMountOptions struct {
+	// StorageClass is the name of the StorageClass.
+	// +kubebuilder:validation:MinLength=1
+	StorageClass string `json:"storageClass"`
+
+	// Options are the mount options to set. Empty sets none.
+	// +optional
+	Options []string `json:"options,omitempty"`
+}
+
+// +kubebuilder:validation:XValidation:rule="self.clusterList.exists(c, c.name == self.primary)",message="primary must be the name of a cluster in clusterList"
+// +kubebuilder:validation:XValidation:rule="!has(self.highAvailability) || !self.highAvailability || size(self.clusterList) >= 2",message="highAvailability requires another cluster in clusterList; the primary alone cannot reach its quorum of synchronous standbys"
 type ClusterReplication struct {
 	// CrossCloudNetworking determines which type of networking mechanics for the replication
 	// +kubebuilder:validation:Enum=AzureFleet;Istio;None
 	CrossCloudNetworkingStrategy string `json:"crossCloudNetworkingStrategy,omitempty"`
 	// Primary is the name of the primary cluster for replication.
+	// +kubebuilder:validation:MaxLength=253
 	Primary string `json:"primary"`
 	// ClusterList is the list of clusters participating in replication.
+	// +kubebuilder:validation:MaxItems=32
 	ClusterList []MemberCluster `json:"clusterList"`
 	// Whether or not to have replicas on the primary cluster.
 	HighAvailability bool `json:"highAvailability,omitempty"`
@@ -394,25 +1209,76 @@ type ClusterReplication struct {
 
 type MemberCluster struct {
 	// Name is the name of the member cluster.
+	// +kubebuilder:validation:MaxLength=253
 	Name string `json:"name"`
 	// EnvironmentOverride is the cloud environment of the member cluster.
-	// Will default to the global setting
+	// Will default to the global setting, then to the detected cloud
 	// +kubebuilder:validation:Enum=eks;aks;gke
 	EnvironmentOverride string `json:"environment,omitempty"`
 	// StorageClassOverride specifies the storage class for DocumentDB persistent volumes in this member cluster.
 	StorageClassOverride string `json:"storageClass,omitempty"`
+	// KubeconfigSecret is a key of a Secret in the namespace of the DocumentDB
+	// holding a kubeconfig for the member cluster. When set, the operator
+	// creates the DocumentDB of the member cluster from this one and keeps it
+	// in sync, so that a single DocumentDB describes the whole replication.
+	// Leave it unset for the cluster this DocumentDB is in.
+	// +optional
+	KubeconfigSecret *cnpgv1.SecretKeySelector `json:"kubeconfigSecret,omitempty"`
 }
 
 type ExposeViaService struct {
 	// ServiceType determines the type of service to expose for DocumentDB.
 	// +kubebuilder:validation:Enum=LoadBalancer;ClusterIP
 	ServiceType string `json:"serviceType"`
+
+	// Hostname is the DNS name external-dns publishes for the Service. Once
+	// it resolves, the connection string and the connection Secret use it
+	// instead of the address of the Service.
+	// +kubebuilder:validation:MaxLength=253
+	// +optional
+	Hostname string `json:"hostname,omitempty"`
 }
 
+// +kubebuilder:validation:XValidation:rule="!has(self.smartShutdownTimeout) || self.smartShutdownTimeout < (has(self.stopDelay) && self.stopDelay > 0 ? self.stopDelay : 30)",message="smartShutdownTimeout must be lower than stopDelay, which defaults to 30 seconds"
 type Timeouts struct {
+	// StopDelay is the time in seconds allowed for a PostgreSQL instance to shut
+	// down gracefully. 0 uses the operator default of 30 seconds.
 	// +kubebuilder:validation:Minimum=0
 	// +kubebuilder:validation:Maximum=1800
 	StopDelay int32 `json:"stopDelay,omitempty"`
+
+	// StartDelay is the time in seconds allowed for a PostgreSQL instance to
+	// start, including the replay of its pending WAL, before its startup probe
+	// fails and the pod is restarted. It also bounds pg_ctl start. Raise it for
+	// clusters that may recover a large WAL backlog. 0 uses the CNPG default of
+	// 3600 seconds.
+	// +kubebuilder:validation:Minimum=0
+	// +optional
+	StartDelay int32 `json:"startDelay,omitempty"`
+
+	// SmartShutdownTimeout is the part of stopDelay, in seconds, reserved for
+	// a smart shutdown of PostgreSQL, which waits for the clients to
+	// disconnect; a fast shutdown then uses the rest. It must be lower than
+	// stopDelay. Unset uses the CNPG default of 180 seconds, 0 goes straight to
+	// the fast shutdown.
+	// +kubebuilder:validation:Minimum=0
+	// +optional
+	SmartShutdownTimeout *int32 `json:"smartShutdownTimeout,omitempty"`
+
+	// SwitchoverDelay is the time in seconds allowed for the primary to shut
+	// down gracefully during a switchover, before the replica is promoted. 0
+	// uses the CNPG default of 3600 seconds.
+	// +kubebuilder:validation:Minimum=0
+	// +optional
+	SwitchoverDelay int32 `json:"switchoverDelay,omitempty"`
+
+	// FailoverDelay is the time in seconds to wait, once the primary is
+	// detected as unhealthy, before failing over to a replica. A delay rides
+	// out short outages at the cost of a longer unavailability when the
+	// primary is really lost. 0 fails over immediately.
+	// +kubebuilder:validation:Minimum=0
+	// +optional
+	FailoverDelay int32 `json:"failoverDelay,omitempty"`
 }
 
 // TLSConfiguration aggregates TLS settings across DocumentDB components.
@@ -480,6 +1346,21 @@ type MonitoringSpec struct {
 	// Exporter configures where metrics are sent.
 	// +optional
 	Exporter *ExporterSpec `json:"exporter,omitempty"`
+
+	// CollectionStats adds the number of documents and the size of each
+	// collection to the metrics, for capacity planning.
+	// +optional
+	CollectionStats *CollectionStatsSpec `json:"collectionStats,omitempty"`
+}
+
+// CollectionStatsSpec configures the per-collection metrics.
+type CollectionStatsSpec struct {
+	// IntervalMinutes is how often the statistics are collected. Defaults
+	// to 5.
+	// +kubebuilder:validation:Minimum=1
+	// +kubebuilder:validation:Maximum=1440
+	// +optional
+	IntervalMinutes int32 `json:"intervalMinutes,omitempty"`
 }
 
 // ExporterSpec configures metric export destinations.
@@ -509,14 +1390,39 @@ type PrometheusExporterSpec struct {
 	Port int32 `json:"port,omitempty"`
 }
 
+// NamespaceDefaultsStatus is the storage class, the Service type and the
+// backup retention a DocumentDB takes from the defaults of its namespace.
+type NamespaceDefaultsStatus struct {
+	// StorageClass is the default storage class.
+	// +optional
+	StorageClass string `json:"storageClass,omitempty"`
+
+	// ServiceType is the default type of spec.exposeViaService.
+	// +optional
+	ServiceType string `json:"serviceType,omitempty"`
+
+	// BackupRetentionDays is the default retention of the backups, 0 when
+	// the namespace sets none.
+	// +optional
+	BackupRetentionDays int `json:"backupRetentionDays,omitempty"`
+}
+
 // DocumentDBStatus defines the observed state of DocumentDB.
 type DocumentDBStatus struct {
 	// Status reflects the status field from the underlying CNPG Cluster.
-	Status           string `json:"status,omitempty"`
+	Status string `json:"status,omitempty"`
+	// ConnectionString is the connection string of the DocumentDB Service,
+	// with kubectl commands reading the credentials. For a ClusterIP Service
+	// without trusted TLS, it lists the gateways of the replicas as well.
 	ConnectionString string `json:"connectionString,omitempty"`
 	TargetPrimary    string `json:"targetPrimary,omitempty"`
 	LocalPrimary     string `json:"localPrimary,omitempty"`
 
+	// Hostname is spec.exposeViaService.hostname once it resolves, and the
+	// host of the connection string.
+	// +optional
+	Hostname string `json:"hostname,omitempty"`
+
 	// SchemaVersion is the currently installed schema version of the DocumentDB extension.
 	SchemaVersion string `json:"schemaVersion,omitempty"`
 
@@ -526,8 +1432,578 @@ type DocumentDBStatus struct {
 	// GatewayImage is the gateway sidecar image URI currently applied to the cluster.
 	GatewayImage string `json:"gatewayImage,omitempty"`
 
+	// CNPGVersion is the version of the CloudNativePG operator managing the
+	// cluster, when it can be detected from the operator image.
+	CNPGVersion string `json:"cnpgVersion,omitempty"`
+
+	// CNPGClusterName is the name of the local CNPG Cluster of the DocumentDB.
+	// +optional
+	CNPGClusterName string `json:"cnpgClusterName,omitempty"`
+
+	// BackupRetentionDays is the retention of the backups of the cluster that
+	// do not set their own, from spec.backup or its default.
+	// +optional
+	BackupRetentionDays int `json:"backupRetentionDays,omitempty"`
+
+	// PersistentVolumeReclaimPolicy is the reclaim policy applied to the
+	// PersistentVolumes of the cluster, from spec.resource.storage or its
+	// default.
+	// +optional
+	PersistentVolumeReclaimPolicy string `json:"persistentVolumeReclaimPolicy,omitempty"`
+
+	// NamespaceDefaults records the defaults of the namespace read when the
+	// cluster was first reconciled. They fill the fields that neither the
+	// spec nor the cluster class sets, and are kept when the defaults of the
+	// namespace change.
+	// +optional
+	NamespaceDefaults *NamespaceDefaultsStatus `json:"namespaceDefaults,omitempty"`
+
 	// TLS reports gateway TLS provisioning status (Phase 1).
 	TLS *TLSStatus `json:"tls,omitempty"`
+
+	// Bootstrap reports the progress of the creation of the first instance,
+	// e.g. of a recovery, until the cluster is healthy.
+	// +optional
+	Bootstrap *BootstrapStatus `json:"bootstrap,omitempty"`
+
+	// Import reports the progress of spec.bootstrap.import.
+	// +optional
+	Import *ImportStatus `json:"import,omitempty"`
+
+	// InitScripts reports the progress of spec.bootstrap.initScripts.
+	// +optional
+	InitScripts *InitScriptsStatus `json:"initScripts,omitempty"`
+
+	// Migration reports the progress of spec.migration.
+	// +optional
+	Migration *MigrationStatus `json:"migration,omitempty"`
+
+	// SelfHeal reports the last remediation made by spec.selfHeal.
+	// +optional
+	SelfHeal *SelfHealStatus `json:"selfHeal,omitempty"`
+
+	// StorageExpansion reports the expansion of the PVCs of the cluster
+	// while they are smaller than spec.resource.storage.pvcSize.
+	// +optional
+	StorageExpansion *StorageExpansionStatus `json:"storageExpansion,omitempty"`
+
+	// AdoptedVolume is the retained PersistentVolume the cluster was
+	// recovered from, following spec.bootstrap.adoptRetainedVolumes.
+	// +optional
+	AdoptedVolume string `json:"adoptedVolume,omitempty"`
+
+	// Instances reports each instance of the local CNPG Cluster.
+	// +listType=map
+	// +listMapKey=name
+	// +optional
+	Instances []InstanceStatus `json:"instances,omitempty"`
+
+	// Endpoints lists the gateway endpoint of each member cluster of
+	// spec.clusterReplication, with the traffic it accepts. Every member
+	// cluster exposes its own gateway, and reports its address here once
+	// spec.exposeViaService has one; the addresses of the other members are
+	// only known in their own copy of the DocumentDB.
+	// +listType=map
+	// +listMapKey=cluster
+	// +optional
+	Endpoints []EndpointStatus `json:"endpoints,omitempty"`
+
+	// Quotas reports the storage used by the databases of spec.quotas.
+	// +listType=map
+	// +listMapKey=database
+	// +optional
+	Quotas []DatabaseQuotaStatus `json:"quotas,omitempty"`
+
+	// WAL reports the usage of the WAL volume of the primary, when
+	// spec.resource.storage.wal is set.
+	// +optional
+	WAL *WALStatus `json:"wal,omitempty"`
+
+	// InProgressOperations lists multi-step operations the operator has started
+	// but not yet completed. An operator that is restarted or loses leadership
+	// mid-operation leaves the entry in place so the next leader resumes it.
+	// +listType=map
+	// +listMapKey=type
+	// +optional
+	InProgressOperations []InProgressOperation `json:"inProgressOperations,omitempty"`
+
+	// ReconcileFailures is the number of consecutive reconciles that failed.
+	// It is reset once a reconcile succeeds, so that a count that keeps
+	// growing points to a persistent problem rather than a transient one.
+	// +optional
+	ReconcileFailures int32 `json:"reconcileFailures,omitempty"`
+
+	// LastError is the error of the last failed reconcile. It is kept after
+	// the reconciles succeed again.
+	// +optional
+	LastError string `json:"lastError,omitempty"`
+
+	// LastErrorTime is the time of the last failed reconcile.
+	// +optional
+	LastErrorTime *metav1.Time `json:"lastErrorTime,omitempty"`
+
+	// ObservedGeneration is the generation of the DocumentDB last applied to
+	// its CNPG Cluster. The status reflects an older spec while it is below
+	// metadata.generation.
+	// +optional
+	ObservedGeneration int64 `json:"observedGeneration,omitempty"`
+
+	// Members reports the DocumentDBs the operator keeps in sync with this
+	// one on the member clusters of spec.clusterReplication that set a
+	// kubeconfigSecret.
+	// +listType=map
+	// +listMapKey=cluster
+	// +optional
+	Members []MemberDocumentDBStatus `json:"members,omitempty"`
+
+	// Conditions describe the observed state of the DocumentDB cluster.
+	// +listType=map
+	// +listMapKey=type
+	// +optional
+	Conditions []metav1.Condition `json:"conditions,omitempty"`
+}
+
+// MemberDocumentDBStatus reports the DocumentDB of a member cluster created
+// from the kubeconfigSecret of its entry in spec.clusterReplication.
+type MemberDocumentDBStatus struct {
+	// Cluster is the name of the member cluster.
+	Cluster string `json:"cluster"`
+
+	// KubeconfigSecret is the Secret the member cluster was last reached
+	// with. It is kept to delete the DocumentDB of the member cluster once
+	// the cluster leaves spec.clusterReplication.
+	KubeconfigSecret cnpgv1.SecretKeySelector `json:"kubeconfigSecret"`
+
+	// Synced is true when the spec of the DocumentDB of the member cluster
+	// matches this one.
+	Synced bool `json:"synced"`
+
+	// Generation is the metadata.generation of the DocumentDB of the member
+	// cluster once synced. A later one means it was changed there, and the
+	// change is reverted.
+	// +optional
+	Generation int64 `json:"generation,omitempty"`
+
+	// Status is the status of the DocumentDB of the member cluster.
+	// +optional
+	Status string `json:"status,omitempty"`
+
+	// Message describes why the DocumentDB of the member cluster could not
+	// be synced.
+	// +optional
+	Message string `json:"message,omitempty"`
+}
+
+// ImportPhase is the phase of the import of spec.bootstrap.import.
+type ImportPhase string
+
+const (
+	// ImportPhaseRunning is an import in progress.
+	ImportPhaseRunning ImportPhase = "Running"
+	// ImportPhaseSucceeded is an import that completed.
+	ImportPhaseSucceeded ImportPhase = "Succeeded"
+	// ImportPhaseFailed is an import that failed. Deleting its Job retries it.
+	ImportPhaseFailed ImportPhase = "Failed"
+)
+
+// ImportStatus reports the import of spec.bootstrap.import.
+type ImportStatus struct {
+	// Phase is the phase of the import.
+	Phase ImportPhase `json:"phase"`
+
+	// JobName is the name of the Job running the import.
+	JobName string `json:"jobName"`
+
+	// DatabasesImported is the number of databases imported so far. An
+	// import of every database counts as a single one.
+	// +optional
+	DatabasesImported int32 `json:"databasesImported,omitempty"`
+
+	// DatabasesTotal is the number of databases to import.
+	// +optional
+	DatabasesTotal int32 `json:"databasesTotal,omitempty"`
+
+	// StartedAt is the time the import started.
+	// +optional
+	StartedAt *metav1.Time `json:"startedAt,omitempty"`
+
+	// CompletedAt is the time the import succeeded or failed.
+	// +optional
+	CompletedAt *metav1.Time `json:"completedAt,omitempty"`
+
+	// Message describes why the import failed.
+	// +optional
+	Message string `json:"message,omitempty"`
+}
+
+// InitScriptsPhase is the phase of the init scripts of
+// spec.bootstrap.initScripts.
+type InitScriptsPhase string
+
+const (
+	// InitScriptsPhaseRunning init scripts are being run.
+	InitScriptsPhaseRunning InitScriptsPhase = "Running"
+	// InitScriptsPhaseSucceeded init scripts have all been run.
+	InitScriptsPhaseSucceeded InitScriptsPhase = "Succeeded"
+	// InitScriptsPhaseFailed init scripts stopped at a failed script, which
+	// is retried until it succeeds.
+	InitScriptsPhaseFailed InitScriptsPhase = "Failed"
+)
+
+// InitScriptsStatus reports the init scripts of spec.bootstrap.initScripts.
+type InitScriptsStatus struct {
+	// Phase is the phase of the init scripts.
+	Phase InitScriptsPhase `json:"phase"`
+
+	// Applied is the number of scripts that succeeded. The next script to
+	// run is the one at this index.
+	Applied int32 `json:"applied"`
+
+	// Total is the number of scripts.
+	Total int32 `json:"total"`
+
+	// StartedAt is the time the first script started.
+	// +optional
+	StartedAt *metav1.Time `json:"startedAt,omitempty"`
+
+	// CompletedAt is the time the last script succeeded.
+	// +optional
+	CompletedAt *metav1.Time `json:"completedAt,omitempty"`
+
+	// Message describes why the current script failed.
+	// +optional
+	Message string `json:"message,omitempty"`
+}
+
+// MigrationPhase is the phase of the migration of spec.migration.
+type MigrationPhase string
+
+const (
+	// MigrationPhaseSyncing is a migration that copied the schema of the
+	// source and replicates its changes.
+	MigrationPhaseSyncing MigrationPhase = "Syncing"
+	// MigrationPhaseCuttingOver is a migration waiting for the last changes
+	// of the read-only source.
+	MigrationPhaseCuttingOver MigrationPhase = "CuttingOver"
+	// MigrationPhaseCompleted is a migration whose source Service routes to
+	// this cluster.
+	MigrationPhaseCompleted MigrationPhase = "Completed"
+	// MigrationPhaseFailed is a migration that could not copy the schema of
+	// the source. Deleting and recreating the cluster retries it.
+	MigrationPhaseFailed MigrationPhase = "Failed"
+)
+
+// MigrationStatus reports the migration of spec.migration.
+type MigrationStatus struct {
+	// Phase is the phase of the migration.
+	Phase MigrationPhase `json:"phase"`
+
+	// LagBytes is the amount of WAL written by the source that this cluster
+	// has not applied yet.
+	// +optional
+	LagBytes *int64 `json:"lagBytes,omitempty"`
+
+	// StartedAt is the time the replication started.
+	// +optional
+	StartedAt *metav1.Time `json:"startedAt,omitempty"`
+
+	// CompletedAt is the time the cutover completed.
+	// +optional
+	CompletedAt *metav1.Time `json:"completedAt,omitempty"`
+
+	// Message describes why the migration failed or is not progressing.
+	// +optional
+	Message string `json:"message,omitempty"`
+}
+
+// SelfHealAction is a remediation of spec.selfHeal.
+type SelfHealAction string
+
+const (
+	// SelfHealActionRecreate is the deletion of the Pod of a replica.
+	SelfHealActionRecreate SelfHealAction = "Recreate"
+	// SelfHealActionReclone is the deletion of the Pod and the PVCs of a
+	// replica.
+	SelfHealActionReclone SelfHealAction = "Reclone"
+)
+
+// SelfHealStatus reports the last remediation of spec.selfHeal.
+type SelfHealStatus struct {
+	// Instance is the replica that was remediated.
+	Instance string `json:"instance"`
+
+	// Action is the remediation that was made.
+	// +kubebuilder:validation:Enum=Recreate;Reclone
+	Action SelfHealAction `json:"action"`
+
+	// Time is when the remediation was made.
+	Time metav1.Time `json:"time"`
+
+	// UnhealthySince is when the instance stopped being ready. It is cleared
+	// once the instance is healthy again, and carried over to the Pod that
+	// replaces a recreated one until then.
+	// +optional
+	UnhealthySince *metav1.Time `json:"unhealthySince,omitempty"`
+}
+
+// BootstrapPhase is the phase of the bootstrap of the first instance.
+type BootstrapPhase string
+
+const (
+	// BootstrapPhasePending is a bootstrap whose Job has not started yet.
+	BootstrapPhasePending BootstrapPhase = "Pending"
+	// BootstrapPhaseRunning is a bootstrap Job that creates the data
+	// directory: initdb, or a copy of the source followed by WAL replay.
+	BootstrapPhaseRunning BootstrapPhase = "Running"
+	// BootstrapPhaseStarting is a bootstrap whose Job completed, waiting for
+	// the first instance to be healthy.
+	BootstrapPhaseStarting BootstrapPhase = "Starting"
+	// BootstrapPhaseCompleted is a bootstrap whose cluster became healthy.
+	BootstrapPhaseCompleted BootstrapPhase = "Completed"
+	// BootstrapPhaseFailed is a bootstrap whose Job failed, or whose cluster
+	// is unrecoverable.
+	BootstrapPhaseFailed BootstrapPhase = "Failed"
+)
+
+// BootstrapStatus reports the creation of the first instance of the
+// cluster, whichever of spec.bootstrap it follows.
+type BootstrapStatus struct {
+	// Phase is the phase of the bootstrap.
+	Phase BootstrapPhase `json:"phase"`
+
+	// Method is how CNPG creates the data directory: initdb, full-recovery,
+	// snapshot-recovery or pgbasebackup.
+	// +optional
+	Method string `json:"method,omitempty"`
+
+	// JobName is the name of the CNPG Job creating the first instance.
+	// +optional
+	JobName string `json:"jobName,omitempty"`
+
+	// RestoredBytes is the size of the data directory written so far.
+	// +optional
+	RestoredBytes *int64 `json:"restoredBytes,omitempty"`
+
+	// ReplayedLSN is the last WAL location replayed, once the recovered
+	// instance is replaying WAL.
+	// +optional
+	ReplayedLSN string `json:"replayedLSN,omitempty"`
+
+	// StartedAt is the time the bootstrap Job started.
+	// +optional
+	StartedAt *metav1.Time `json:"startedAt,omitempty"`
+
+	// CompletedAt is the time the cluster became healthy.
+	// +optional
+	CompletedAt *metav1.Time `json:"completedAt,omitempty"`
+
+	// Message describes the current step of the bootstrap, or why it failed.
+	// +optional
+	Message string `json:"message,omitempty"`
+}
+
+// Instance roles reported in InstanceStatus.
+const (
+	InstanceRolePrimary = "primary"
+	InstanceRoleReplica = "replica"
+)
+
+// InstanceStatus describes an instance of the CNPG Cluster backing a DocumentDB.
+type InstanceStatus struct {
+	// Name is the name of the instance Pod.
+	Name string `json:"name"`
+
+	// Role is primary or replica.
+	// +kubebuilder:validation:Enum=primary;replica
+	Role string `json:"role"`
+
+	// Healthy is true when CNPG reports the instance as healthy.
+	Healthy bool `json:"healthy"`
+
+	// ExtensionVersion is the tag of the DocumentDB extension image the
+	// instance runs. It is empty for an image referenced by digest.
+	// +optional
+	ExtensionVersion string `json:"extensionVersion,omitempty"`
+
+	// RestartCount is the total number of container restarts of the instance Pod.
+	// +optional
+	RestartCount int32 `json:"restartCount,omitempty"`
+}
+
+// Endpoint roles reported in EndpointStatus.
+const (
+	EndpointRoleReadWrite = "readWrite"
+	EndpointRoleReadOnly  = "readOnly"
+)
+
+// EndpointStatus describes the gateway endpoint of a member cluster of a
+// replicated DocumentDB.
+type EndpointStatus struct {
+	// Cluster is the name of the member cluster.
+	Cluster string `json:"cluster"`
+
+	// Role is readWrite for the primary cluster and readOnly for the
+	// replica clusters, whose gateways reject writes.
+	// +kubebuilder:validation:Enum=readWrite;readOnly
+	Role string `json:"role"`
+
+	// Local is true for the member cluster this DocumentDB runs in.
+	// +optional
+	Local bool `json:"local,omitempty"`
+
+	// Host is the address of the DocumentDB Service of the member cluster.
+	// +optional
+	Host string `json:"host,omitempty"`
+
+	// Port is the gateway port of the DocumentDB Service.
+	// +optional
+	Port int32 `json:"port,omitempty"`
+}
+
+// WALStatus reports the usage of the WAL volume. Sizes are rounded up to
+// the mebibyte.
+type WALStatus struct {
+	// Used is the size of the WAL on the volume.
+	Used string `json:"used"`
+
+	// RetainedByChangeStreams is the WAL kept for the change stream that is
+	// furthest behind. It keeps growing while a change stream is not
+	// consumed, until spec.changeStreams.maxRetainedWALSize.
+	// +optional
+	RetainedByChangeStreams string `json:"retainedByChangeStreams,omitempty"`
+}
+
+// DatabaseQuotaStatus reports the storage used by a database with a quota.
+type DatabaseQuotaStatus struct {
+	// Database is the name of the database.
+	Database string `json:"database"`
+
+	// Used is the storage used by the documents and indexes of the
+	// database, rounded up to the mebibyte.
+	Used string `json:"used"`
+
+	// Exceeded is true while Used is over the quota.
+	// +optional
+	Exceeded bool `json:"exceeded,omitempty"`
+}
+
+// PVCExpansionPhase is the phase of the expansion of a PVC.
+type PVCExpansionPhase string
+
+const (
+	// PVCExpansionPhasePending is a PVC CNPG has not requested the new size
+	// for yet.
+	PVCExpansionPhasePending PVCExpansionPhase = "Pending"
+	// PVCExpansionPhaseResizing is a PVC whose volume the storage provider
+	// is expanding.
+	PVCExpansionPhaseResizing PVCExpansionPhase = "Resizing"
+	// PVCExpansionPhaseFileSystemResizePending is a PVC whose volume is
+	// expanded, waiting for its file system to be expanded on the node.
+	PVCExpansionPhaseFileSystemResizePending PVCExpansionPhase = "FileSystemResizePending"
+	// PVCExpansionPhaseFailed is a PVC that cannot be expanded, e.g. because
+	// its StorageClass does not allow volume expansion.
+	PVCExpansionPhaseFailed PVCExpansionPhase = "Failed"
+)
+
+// StorageExpansionStatus reports the expansion of the PVCs of a cluster.
+type StorageExpansionStatus struct {
+	// Size is the size the PVCs are expanded to.
+	Size string `json:"size"`
+
+	// PVCs lists the PVCs smaller than Size.
+	// +optional
+	PVCs []PVCExpansionStatus `json:"pvcs,omitempty"`
+}
+
+// PVCExpansionStatus reports the expansion of a PVC.
+type PVCExpansionStatus struct {
+	// Name is the name of the PVC.
+	Name string `json:"name"`
+
+	// Capacity is the current size of the volume.
+	// +optional
+	Capacity string `json:"capacity,omitempty"`
+
+	// Phase is the phase of the expansion.
+	Phase PVCExpansionPhase `json:"phase"`
+
+	// Message describes why the expansion failed or what it waits for.
+	// +optional
+	Message string `json:"message,omitempty"`
+}
+
+// Condition types reported in DocumentDBStatus.Conditions.
+const (
+	// ConditionCNPGClusterDrifted is True when the live CNPG Cluster differs from
+	// the spec the operator renders for the DocumentDB, e.g. after a manual edit.
+	ConditionCNPGClusterDrifted = "CNPGClusterDrifted"
+
+	// ConditionPreflightFailed is True when a prerequisite of the DocumentDB,
+	// such as the CloudNativePG operator or the storage class, is missing.
+	// Nothing is created for the DocumentDB until it turns False.
+	ConditionPreflightFailed = "PreflightFailed"
+
+	// ConditionReady is True when the CNPG Cluster of the DocumentDB is
+	// healthy. Its reason tells a cluster making progress towards it from
+	// one that needs manual intervention.
+	ConditionReady = "Ready"
+
+	// ConditionPlaced is reported by an operator running on a Fleet hub. It
+	// is True once the DocumentDB is available on the member clusters of its
+	// spec.clusterReplication.
+	ConditionPlaced = "Placed"
+
+	// ConditionMembersSynced is True when the DocumentDBs of the member
+	// clusters of spec.clusterReplication that set a kubeconfigSecret match
+	// this one.
+	ConditionMembersSynced = "MembersSynced"
+)
+
+// Condition reasons reported in DocumentDBStatus.Conditions.
+const (
+	ReasonSpecDrift   = "SpecDrift"
+	ReasonSpecChanged = "SpecChanged"
+	ReasonInSync      = "InSync"
+
+	ReasonPrerequisitesMissing = "PrerequisitesMissing"
+	ReasonPrerequisitesMet     = "PrerequisitesMet"
+
+	ReasonClusterHealthy     = "ClusterHealthy"
+	ReasonClusterProgressing = "ClusterProgressing"
+	ReasonClusterFailed      = "ClusterFailed"
+
+	ReasonPlacementAvailable = "PlacementAvailable"
+	ReasonPlacementPending   = "PlacementPending"
+	ReasonNoMemberClusters   = "NoMemberClusters"
+
+	ReasonMembersInSync    = "MembersInSync"
+	ReasonMemberSyncFailed = "MemberSyncFailed"
+)
+
+// OperationType identifies a multi-step operation tracked in status.
+// +kubebuilder:validation:Enum=ExtensionUpgrade;DemotionTokenWait
+type OperationType string
+
+const (
+	// OperationExtensionUpgrade is an ALTER EXTENSION documentdb UPDATE run.
+	OperationExtensionUpgrade OperationType = "ExtensionUpgrade"
+	// OperationDemotionTokenWait is the wait for the CNPG demotion token after
+	// a primary is demoted, and its publication to the new primary.
+	OperationDemotionTokenWait OperationType = "DemotionTokenWait"
+)
+
+// InProgressOperation records a started multi-step operation.
+type InProgressOperation struct {
+	// Type is the kind of operation.
+	Type OperationType `json:"type"`
+
+	// Target identifies what the operation converges to, e.g. the schema
+	// version of an extension upgrade or the CNPG cluster awaiting a token.
+	// +optional
+	Target string `json:"target,omitempty"`
+
+	// StartedAt is when the operation was first started.
+	StartedAt metav1.Time `json:"startedAt"`
 }
 
 // TLSStatus captures readiness and secret information.
@@ -537,6 +2013,7 @@ type TLSStatus struct {
 	Message    string `json:"message,omitempty"`
 }
 
+// +kubebuilder:printcolumn:name="Ready",type=string,JSONPath=".status.conditions[?(@.type==\"Ready\")].status",description="Whether the CNPG Cluster is healthy"
 // +kubebuilder:printcolumn:name="Status",type=string,JSONPath=".status.status",description="CNPG Cluster Status"
 // +kubebuilder:printcolumn:name="Connection String",type=string,JSONPath=".status.connectionString",description="DocumentDB Connection String"
 // +kubebuilder:resource:path=dbs,scope=Namespaced,singular=documentdb,shortName=documentdb
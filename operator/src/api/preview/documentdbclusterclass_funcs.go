@@ -0,0 +1,92 @@
+// Copyright (c) Microsoft Corporation.
+// Licensed under the MIT License.
+
+package preview
+
+import (
+	"fmt"
+	"reflect"
+)
+
+// GetSize returns the size with the given name, or the default size when name
+// is empty. A class with a single size uses it as its default.
+func (c *DocumentDBClusterClass) GetSize(name string) (*ClusterClassSize, error) {
+	if name == "" {
+		name = c.Spec.DefaultSize
+	}
+	if name == "" {
+		if len(c.Spec.Sizes) == 1 {
+			return &c.Spec.Sizes[0], nil
+		}
+		return nil, fmt.Errorf("DocumentDBClusterClass %s has no default size; set spec.classRef.size", c.Name)
+	}
+	for i := range c.Spec.Sizes {
+		if c.Spec.Sizes[i].Name == name {
+			return &c.Spec.Sizes[i], nil
+		}
+	}
+	return nil, fmt.Errorf("DocumentDBClusterClass %s has no size %q", c.Name, name)
+}
+
+// ApplyClusterClass fills the fields of the spec that are left unset with the
+// values of class and of the size selected by spec.classRef. Fields set on the
+// spec always take precedence over the class.
+func (s *DocumentDBSpec) ApplyClusterClass(class *DocumentDBClusterClass) error {
+	sizeName := ""
+	if s.ClassRef != nil {
+		sizeName = s.ClassRef.Size
+	}
+	size, err := class.GetSize(sizeName)
+	if err != nil {
+		return err
+	}
+
+	if s.InstancesPerNode == 0 {
+		s.InstancesPerNode = class.Spec.InstancesPerNode
+	}
+	if s.InstancesPerNode == 0 {
+		s.InstancesPerNode = 1
+	}
+
+	storage := &s.Resource.Storage
+	if storage.PvcSize == "" {
+		storage.PvcSize = size.PvcSize
+	}
+	if storage.StorageClass == "" {
+		storage.StorageClass = class.Spec.StorageClass
+	}
+	if storage.PersistentVolumeReclaimPolicy == "" {
+		storage.PersistentVolumeReclaimPolicy = class.Spec.PersistentVolumeReclaimPolicy
+	}
+	if s.Resource.Memory == "" {
+		s.Resource.Memory = size.Memory
+	}
+	if s.Resource.CPU == "" {
+		s.Resource.CPU = size.CPU
+	}
+
+	if s.DocumentDBVersion == "" {
+		s.DocumentDBVersion = class.Spec.DocumentDBVersion
+	}
+	if class.Spec.Image != nil {
+		if s.Image == nil {
+			s.Image = &ImageSpec{}
+		}
+		if s.Image.DocumentDB == "" {
+			s.Image.DocumentDB = class.Spec.Image.DocumentDB
+		}
+		if s.Image.Gateway == "" {
+			s.Image.Gateway = class.Spec.Image.Gateway
+		}
+		if s.Image.Postgres == "" {
+			s.Image.Postgres = class.Spec.Image.Postgres
+		}
+	}
+	if s.Backup == nil && class.Spec.Backup != nil {
+		s.Backup = class.Spec.Backup.DeepCopy()
+	}
+	if reflect.ValueOf(s.Affinity).IsZero() {
+		s.Affinity = *class.Spec.Affinity.DeepCopy()
+	}
+	return nil
+}
@@ -0,0 +1,126 @@
+// Copyright (c) Microsoft Corporation.
+// Licensed under the MIT License.
+
+package preview
+
+import (
+	cnpgv1 "github.com/cloudnative-pg/cloudnative-pg/api/v1"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+var _ = Describe("DocumentDBClusterClass", func() {
+	var class *DocumentDBClusterClass
+
+	BeforeEach(func() {
+		class = &DocumentDBClusterClass{
+			ObjectMeta: metav1.ObjectMeta{Name: "standard"},
+			Spec: DocumentDBClusterClassSpec{
+				Sizes: []ClusterClassSize{
+					{Name: "small", PvcSize: "10Gi", Memory: "2Gi", CPU: "1"},
+					{Name: "large", PvcSize: "100Gi", Memory: "16Gi", CPU: "8"},
+				},
+				DefaultSize:                   "small",
+				InstancesPerNode:              3,
+				DocumentDBVersion:             "0.112.0",
+				Image:                         &ImageSpec{Gateway: "example.com/gateway:1"},
+				StorageClass:                  "premium",
+				PersistentVolumeReclaimPolicy: "Delete",
+				Backup:                        &BackupConfiguration{RetentionDays: 7},
+				Affinity:                      cnpgv1.AffinityConfiguration{TopologyKey: "topology.kubernetes.io/zone"},
+			},
+		}
+	})
+
+	Describe("GetSize", func() {
+		It("returns the named size", func() {
+			size, err := class.GetSize("large")
+			Expect(err).ToNot(HaveOccurred())
+			Expect(size.PvcSize).To(Equal("100Gi"))
+		})
+
+		It("falls back to the default size", func() {
+			size, err := class.GetSize("")
+			Expect(err).ToNot(HaveOccurred())
+			Expect(size.Name).To(Equal("small"))
+		})
+
+		It("uses the only size of a class without a default", func() {
+			class.Spec.DefaultSize = ""
+			class.Spec.Sizes = class.Spec.Sizes[1:]
+			size, err := class.GetSize("")
+			Expect(err).ToNot(HaveOccurred())
+			Expect(size.Name).To(Equal("large"))
+		})
+
+		It("fails when no size can be selected", func() {
+			class.Spec.DefaultSize = ""
+			_, err := class.GetSize("")
+			Expect(err).To(HaveOccurred())
+		})
+
+		It("fails for an unknown size", func() {
+			_, err := class.GetSize("huge")
+			Expect(err).To(MatchError(ContainSubstring(`no size "huge"`)))
+		})
+	})
+
+	Describe("ApplyClusterClass", func() {
+		It("fills an empty spec from the class and the selected size", func() {
+			spec := DocumentDBSpec{ClassRef: &ClusterClassReference{Name: "standard", Size: "large"}}
+			Expect(spec.ApplyClusterClass(class)).To(Succeed())
+
+			Expect(spec.InstancesPerNode).To(Equal(3))
+			Expect(spec.Resource.Storage.PvcSize).To(Equal("100Gi"))
+			Expect(spec.Resource.Storage.StorageClass).To(Equal("premium"))
+			Expect(spec.Resource.Storage.PersistentVolumeReclaimPolicy).To(Equal("Delete"))
+			Expect(spec.Resource.Memory).To(Equal("16Gi"))
+			Expect(spec.Resource.CPU).To(Equal("8"))
+			Expect(spec.DocumentDBVersion).To(Equal("0.112.0"))
+			Expect(spec.Image.Gateway).To(Equal("example.com/gateway:1"))
+			Expect(spec.Backup.RetentionDays).To(Equal(7))
+			Expect(spec.Affinity.TopologyKey).To(Equal("topology.kubernetes.io/zone"))
+		})
+
+		It("keeps the fields set on the spec", func() {
+			spec := DocumentDBSpec{
+				ClassRef:         &ClusterClassReference{Name: "standard"},
+				InstancesPerNode: 1,
+				Resource: Resource{
+					Memory:  "4Gi",
+					Storage: StorageConfiguration{PvcSize: "20Gi"},
+				},
+				Image:  &ImageSpec{Gateway: "example.com/gateway:2"},
+				Backup: &BackupConfiguration{RetentionDays: 30},
+			}
+			Expect(spec.ApplyClusterClass(class)).To(Succeed())
+
+			Expect(spec.InstancesPerNode).To(Equal(1))
+			Expect(spec.Resource.Storage.PvcSize).To(Equal("20Gi"))
+			Expect(spec.Resource.Memory).To(Equal("4Gi"))
+			Expect(spec.Resource.CPU).To(Equal("1"))
+			Expect(spec.Image.Gateway).To(Equal("example.com/gateway:2"))
+			Expect(spec.Backup.RetentionDays).To(Equal(30))
+		})
+
+		It("does not share the backup configuration with the class", func() {
+			spec := DocumentDBSpec{ClassRef: &ClusterClassReference{Name: "standard"}}
+			Expect(spec.ApplyClusterClass(class)).To(Succeed())
+			spec.Backup.RetentionDays = 1
+			Expect(class.Spec.Backup.RetentionDays).To(Equal(7))
+		})
+
+		It("defaults instancesPerNode to 1 when the class does not set it", func() {
+			class.Spec.InstancesPerNode = 0
+			spec := DocumentDBSpec{ClassRef: &ClusterClassReference{Name: "standard"}}
+			Expect(spec.ApplyClusterClass(class)).To(Succeed())
+			Expect(spec.InstancesPerNode).To(Equal(1))
+		})
+
+		It("fails when the referenced size does not exist", func() {
+			spec := DocumentDBSpec{ClassRef: &ClusterClassReference{Name: "standard", Size: "huge"}}
+			Expect(spec.ApplyClusterClass(class)).ToNot(Succeed())
+		})
+	})
+})
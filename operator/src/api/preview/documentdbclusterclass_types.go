@@ -0,0 +1,106 @@
+// Copyright (c) Microsoft Corporation.
+// Licensed under the MIT License.
+
+package preview
+
+import (
+	cnpgv1 "github.com/cloudnative-pg/cloudnative-pg/api/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// DocumentDBClusterClassSpec defines a template of DocumentDB cluster settings.
+// DocumentDBs that reference the class through spec.classRef inherit every
+// field they leave unset.
+// +kubebuilder:validation:XValidation:rule="!has(self.defaultSize) || self.sizes.exists(s, s.name == self.defaultSize)",message="defaultSize must name one of the sizes"
+type DocumentDBClusterClassSpec struct {
+	// Sizes lists the t-shirt sizes offered by the class.
+	// +listType=map
+	// +listMapKey=name
+	// +kubebuilder:validation:MinItems=1
+	Sizes []ClusterClassSize `json:"sizes"`
+
+	// DefaultSize is the size used by DocumentDBs that do not pick one in
+	// spec.classRef.size. It may be omitted when the class has a single size.
+	// +optional
+	DefaultSize string `json:"defaultSize,omitempty"`
+
+	// InstancesPerNode is the number of DocumentDB instances per node. Range: 1-3.
+	// +kubebuilder:validation:Minimum=1
+	// +kubebuilder:validation:Maximum=3
+	// +kubebuilder:default=1
+	// +optional
+	InstancesPerNode int `json:"instancesPerNode,omitempty"`
+
+	// DocumentDBVersion specifies the version for all DocumentDB components (engine, gateway).
+	// +optional
+	DocumentDBVersion string `json:"documentDBVersion,omitempty"`
+
+	// Image groups container image settings for the DocumentDB stack.
+	// +optional
+	Image *ImageSpec `json:"image,omitempty"`
+
+	// StorageClass specifies the storage class for DocumentDB persistent volumes.
+	// +optional
+	StorageClass string `json:"storageClass,omitempty"`
+
+	// PersistentVolumeReclaimPolicy controls what happens to the PersistentVolume when
+	// the DocumentDB cluster is deleted. See spec.resource.storage.persistentVolumeReclaimPolicy
+	// of DocumentDB.
+	// +kubebuilder:validation:Enum=Retain;Delete
+	// +optional
+	PersistentVolumeReclaimPolicy string `json:"persistentVolumeReclaimPolicy,omitempty"`
+
+	// Backup configures backup settings for DocumentDB.
+	// +optional
+	Backup *BackupConfiguration `json:"backup,omitempty"`
+
+	// Affinity/Anti-affinity rules for Pods (cnpg passthrough)
+	// +optional
+	Affinity cnpgv1.AffinityConfiguration `json:"affinity,omitempty"`
+}
+
+// ClusterClassSize is a named set of resources offered by a DocumentDBClusterClass.
+type ClusterClassSize struct {
+	// Name identifies the size, e.g. "small" or "large".
+	// +kubebuilder:validation:MinLength=1
+	Name string `json:"name"`
+
+	// PvcSize is the size of the persistent volume claim for DocumentDB storage (e.g., "10Gi").
+	// +kubebuilder:validation:MinLength=1
+	PvcSize string `json:"pvcSize"`
+
+	// Memory specifies the memory limit for each DocumentDB instance pod.
+	// +optional
+	Memory string `json:"memory,omitempty"`
+
+	// CPU specifies the total CPU envelope for each DocumentDB instance pod.
+	// +optional
+	CPU string `json:"cpu,omitempty"`
+}
+
+// +kubebuilder:object:root=true
+// +kubebuilder:resource:path=documentdbclusterclasses,scope=Cluster,singular=documentdbclusterclass
+// +kubebuilder:printcolumn:name="Default Size",type=string,JSONPath=".spec.defaultSize",description="Size used when a DocumentDB does not pick one"
+// +kubebuilder:printcolumn:name="Version",type=string,JSONPath=".spec.documentDBVersion",description="DocumentDB version"
+// +kubebuilder:metadata:labels=app=documentdb-operator
+
+// DocumentDBClusterClass is a cluster-wide template of DocumentDB settings.
+type DocumentDBClusterClass struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	Spec DocumentDBClusterClassSpec `json:"spec"`
+}
+
+// +kubebuilder:object:root=true
+
+// DocumentDBClusterClassList contains a list of DocumentDBClusterClass.
+type DocumentDBClusterClassList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata,omitempty"`
+	Items           []DocumentDBClusterClass `json:"items"`
+}
+
+func init() {
+	SchemeBuilder.Register(&DocumentDBClusterClass{}, &DocumentDBClusterClassList{})
+}
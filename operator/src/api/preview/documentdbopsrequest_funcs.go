@@ -0,0 +1,15 @@
+// Copyright (c) Microsoft Corporation.
+// Licensed under the MIT License.
+
+package preview
+
+// IsDone returns true if the operation succeeded or failed.
+func (status *DocumentDBOpsRequestStatus) IsDone() bool {
+	return status.Phase == OpsRequestPhaseSucceeded || status.Phase == OpsRequestPhaseFailed
+}
+
+// IsActive returns true if the operation is running on its cluster. Only one
+// operation runs on a cluster at a time.
+func (status *DocumentDBOpsRequestStatus) IsActive() bool {
+	return status.Phase == OpsRequestPhaseRunning
+}
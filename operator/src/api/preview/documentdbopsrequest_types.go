@@ -0,0 +1,147 @@
+// Copyright (c) Microsoft Corporation.
+// Licensed under the MIT License.
+
+package preview
+
+import (
+	cnpgv1 "github.com/cloudnative-pg/cloudnative-pg/api/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// OpsRequestType is an administrative operation on a DocumentDB cluster.
+// +kubebuilder:validation:Enum=Compact;Reindex;RotateCredentials;Switchover;MinorUpgrade
+type OpsRequestType string
+
+const (
+	// OpsRequestCompact rewrites the tables of the cluster to return the space
+	// of deleted documents to the file system (VACUUM FULL). Each table is
+	// locked while it is rewritten.
+	OpsRequestCompact OpsRequestType = "Compact"
+	// OpsRequestReindex rebuilds the indexes of the cluster without blocking
+	// writes (REINDEX CONCURRENTLY).
+	OpsRequestReindex OpsRequestType = "Reindex"
+	// OpsRequestRotateCredentials sets a new random password for the gateway
+	// user, stores it in the credential Secret and restarts the instances.
+	OpsRequestRotateCredentials OpsRequestType = "RotateCredentials"
+	// OpsRequestSwitchover promotes a replica to primary.
+	OpsRequestSwitchover OpsRequestType = "Switchover"
+	// OpsRequestMinorUpgrade moves the cluster to a newer version of the same
+	// major version and waits for the instances to run it. The version is set
+	// in the documentdb.io/minor-upgrade-version annotation of the DocumentDB,
+	// which wins over spec.documentDBVersion while it is newer.
+	OpsRequestMinorUpgrade OpsRequestType = "MinorUpgrade"
+)
+
+// DocumentDBOpsRequestSpec defines an administrative operation on a DocumentDB cluster.
+// +kubebuilder:validation:XValidation:rule="oldSelf == self",message="DocumentDBOpsRequestSpec is immutable once set"
+// +kubebuilder:validation:XValidation:rule="self.type != 'MinorUpgrade' || has(self.minorUpgrade)",message="minorUpgrade is required for a MinorUpgrade"
+type DocumentDBOpsRequestSpec struct {
+	// Cluster is the DocumentDB cluster to operate on.
+	// The cluster must exist in the same namespace as the DocumentDBOpsRequest.
+	// +kubebuilder:validation:Required
+	Cluster cnpgv1.LocalObjectReference `json:"cluster"`
+
+	// Type is the operation to run.
+	// +kubebuilder:validation:Required
+	Type OpsRequestType `json:"type"`
+
+	// Switchover configures a Switchover.
+	// +optional
+	Switchover *SwitchoverOptions `json:"switchover,omitempty"`
+
+	// MinorUpgrade configures a MinorUpgrade.
+	// +optional
+	MinorUpgrade *MinorUpgradeOptions `json:"minorUpgrade,omitempty"`
+
+	// TimeoutMinutes bounds a Compact or a Reindex, which take as long as
+	// rewriting the tables or the indexes of the cluster. The statement is
+	// cancelled on the server when it runs longer, and the operation fails.
+	// 0 uses the default of 24 hours.
+	// +kubebuilder:validation:Minimum=0
+	// +optional
+	TimeoutMinutes int32 `json:"timeoutMinutes,omitempty"`
+}
+
+// SwitchoverOptions configures a Switchover operation.
+type SwitchoverOptions struct {
+	// TargetInstance is the name of the instance to promote. Defaults to the
+	// first healthy replica.
+	// +optional
+	TargetInstance string `json:"targetInstance,omitempty"`
+}
+
+// MinorUpgradeOptions configures a MinorUpgrade operation.
+type MinorUpgradeOptions struct {
+	// Version is the DocumentDB version to upgrade to, e.g. "0.111.0".
+	// +kubebuilder:validation:Pattern=`^\d+\.\d+\.\d+$`
+	Version string `json:"version"`
+}
+
+// OpsRequestPhase is the phase of a DocumentDBOpsRequest.
+type OpsRequestPhase string
+
+const (
+	// OpsRequestPhasePending is an operation waiting for another operation on
+	// the same cluster to complete.
+	OpsRequestPhasePending OpsRequestPhase = "Pending"
+	// OpsRequestPhaseRunning is an operation in progress.
+	OpsRequestPhaseRunning OpsRequestPhase = "Running"
+	// OpsRequestPhaseSucceeded is an operation that completed.
+	OpsRequestPhaseSucceeded OpsRequestPhase = "Succeeded"
+	// OpsRequestPhaseFailed is an operation that failed or was rejected.
+	OpsRequestPhaseFailed OpsRequestPhase = "Failed"
+)
+
+// DocumentDBOpsRequestStatus defines the observed state of DocumentDBOpsRequest.
+type DocumentDBOpsRequestStatus struct {
+	// Phase is the phase of the operation.
+	// +optional
+	Phase OpsRequestPhase `json:"phase,omitempty"`
+
+	// StartedAt is the time the operation started.
+	// +optional
+	StartedAt *metav1.Time `json:"startedAt,omitempty"`
+
+	// CompletedAt is the time the operation succeeded or failed.
+	// +optional
+	CompletedAt *metav1.Time `json:"completedAt,omitempty"`
+
+	// TargetInstance is the instance a Switchover promotes.
+	// +optional
+	TargetInstance string `json:"targetInstance,omitempty"`
+
+	// Message describes the progress of the operation, or why it failed.
+	// +optional
+	Message string `json:"message,omitempty"`
+}
+
+// +kubebuilder:object:root=true
+// +kubebuilder:subresource:status
+// +kubebuilder:resource:path=documentdbopsrequests,scope=Namespaced,singular=documentdbopsrequest
+// +kubebuilder:printcolumn:name="Cluster",type=string,JSONPath=".spec.cluster.name",description="Target DocumentDB cluster"
+// +kubebuilder:printcolumn:name="Type",type=string,JSONPath=".spec.type",description="Operation"
+// +kubebuilder:printcolumn:name="Phase",type=string,JSONPath=".status.phase",description="Operation phase"
+// +kubebuilder:printcolumn:name="StartedAt",type=string,JSONPath=".status.startedAt",description="Operation start time"
+// +kubebuilder:printcolumn:name="CompletedAt",type=string,JSONPath=".status.completedAt",description="Operation completion time"
+// +kubebuilder:printcolumn:name="Message",type=string,JSONPath=".status.message",description="Operation status message"
+// +kubebuilder:metadata:labels=app=documentdb-operator
+type DocumentDBOpsRequest struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	Spec   DocumentDBOpsRequestSpec   `json:"spec,omitempty"`
+	Status DocumentDBOpsRequestStatus `json:"status,omitempty"`
+}
+
+// +kubebuilder:object:root=true
+
+// DocumentDBOpsRequestList contains a list of DocumentDBOpsRequest.
+type DocumentDBOpsRequestList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata,omitempty"`
+	Items           []DocumentDBOpsRequest `json:"items"`
+}
+
+func init() {
+	SchemeBuilder.Register(&DocumentDBOpsRequest{}, &DocumentDBOpsRequestList{})
+}
@@ -22,6 +22,7 @@ type ScheduledBackupSpec struct {
 
 	// RetentionDays specifies how many days the backups should be retained.
 	// If not specified, the default retention period from the cluster's backup retention policy will be used.
+	// +kubebuilder:validation:Minimum=1
 	// +optional
 	RetentionDays *int `json:"retentionDays,omitempty"`
 }
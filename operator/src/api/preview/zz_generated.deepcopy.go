@@ -10,6 +10,7 @@ package preview
 import (
 	apiv1 "github.com/cloudnative-pg/cloudnative-pg/api/v1"
 	"k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/runtime"
 )
 
@@ -43,6 +44,11 @@ func (in *Backup) DeepCopyObject() runtime.Object {
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *BackupConfiguration) DeepCopyInto(out *BackupConfiguration) {
 	*out = *in
+	if in.Velero != nil {
+		in, out := &in.Velero, &out.Velero
+		*out = new(VeleroConfiguration)
+		**out = **in
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new BackupConfiguration.
@@ -143,6 +149,28 @@ func (in *BootstrapConfiguration) DeepCopyInto(out *BootstrapConfiguration) {
 		*out = new(RecoveryConfiguration)
 		(*in).DeepCopyInto(*out)
 	}
+	if in.Import != nil {
+		in, out := &in.Import, &out.Import
+		*out = new(ImportConfiguration)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Clone != nil {
+		in, out := &in.Clone, &out.Clone
+		*out = new(CloneConfiguration)
+		**out = **in
+	}
+	if in.PgBaseBackup != nil {
+		in, out := &in.PgBaseBackup, &out.PgBaseBackup
+		*out = new(PgBaseBackupConfiguration)
+		**out = **in
+	}
+	if in.InitScripts != nil {
+		in, out := &in.InitScripts, &out.InitScripts
+		*out = make([]InitScript, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new BootstrapConfiguration.
@@ -155,6 +183,34 @@ func (in *BootstrapConfiguration) DeepCopy() *BootstrapConfiguration {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *BootstrapStatus) DeepCopyInto(out *BootstrapStatus) {
+	*out = *in
+	if in.RestoredBytes != nil {
+		in, out := &in.RestoredBytes, &out.RestoredBytes
+		*out = new(int64)
+		**out = **in
+	}
+	if in.StartedAt != nil {
+		in, out := &in.StartedAt, &out.StartedAt
+		*out = (*in).DeepCopy()
+	}
+	if in.CompletedAt != nil {
+		in, out := &in.CompletedAt, &out.CompletedAt
+		*out = (*in).DeepCopy()
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new BootstrapStatus.
+func (in *BootstrapStatus) DeepCopy() *BootstrapStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(BootstrapStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *CertManagerTLS) DeepCopyInto(out *CertManagerTLS) {
 	*out = *in
@@ -176,13 +232,91 @@ func (in *CertManagerTLS) DeepCopy() *CertManagerTLS {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ChangeStreamsConfiguration) DeepCopyInto(out *ChangeStreamsConfiguration) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ChangeStreamsConfiguration.
+func (in *ChangeStreamsConfiguration) DeepCopy() *ChangeStreamsConfiguration {
+	if in == nil {
+		return nil
+	}
+	out := new(ChangeStreamsConfiguration)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *CloneConfiguration) DeepCopyInto(out *CloneConfiguration) {
+	*out = *in
+	out.SourceRef = in.SourceRef
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new CloneConfiguration.
+func (in *CloneConfiguration) DeepCopy() *CloneConfiguration {
+	if in == nil {
+		return nil
+	}
+	out := new(CloneConfiguration)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *CloneSourceReference) DeepCopyInto(out *CloneSourceReference) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new CloneSourceReference.
+func (in *CloneSourceReference) DeepCopy() *CloneSourceReference {
+	if in == nil {
+		return nil
+	}
+	out := new(CloneSourceReference)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ClusterClassReference) DeepCopyInto(out *ClusterClassReference) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ClusterClassReference.
+func (in *ClusterClassReference) DeepCopy() *ClusterClassReference {
+	if in == nil {
+		return nil
+	}
+	out := new(ClusterClassReference)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ClusterClassSize) DeepCopyInto(out *ClusterClassSize) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ClusterClassSize.
+func (in *ClusterClassSize) DeepCopy() *ClusterClassSize {
+	if in == nil {
+		return nil
+	}
+	out := new(ClusterClassSize)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *ClusterReplication) DeepCopyInto(out *ClusterReplication) {
 	*out = *in
 	if in.ClusterList != nil {
 		in, out := &in.ClusterList, &out.ClusterList
 		*out = make([]MemberCluster, len(*in))
-		copy(*out, *in)
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
 	}
 }
 
@@ -196,6 +330,21 @@ func (in *ClusterReplication) DeepCopy() *ClusterReplication {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *CollectionStatsSpec) DeepCopyInto(out *CollectionStatsSpec) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new CollectionStatsSpec.
+func (in *CollectionStatsSpec) DeepCopy() *CollectionStatsSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(CollectionStatsSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *ComponentResources) DeepCopyInto(out *ComponentResources) {
 	*out = *in
@@ -211,6 +360,36 @@ func (in *ComponentResources) DeepCopy() *ComponentResources {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *DatabaseQuota) DeepCopyInto(out *DatabaseQuota) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new DatabaseQuota.
+func (in *DatabaseQuota) DeepCopy() *DatabaseQuota {
+	if in == nil {
+		return nil
+	}
+	out := new(DatabaseQuota)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *DatabaseQuotaStatus) DeepCopyInto(out *DatabaseQuotaStatus) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new DatabaseQuotaStatus.
+func (in *DatabaseQuotaStatus) DeepCopy() *DatabaseQuotaStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(DatabaseQuotaStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *DocumentDB) DeepCopyInto(out *DocumentDB) {
 	*out = *in
@@ -239,31 +418,57 @@ func (in *DocumentDB) DeepCopyObject() runtime.Object {
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *DocumentDBList) DeepCopyInto(out *DocumentDBList) {
+func (in *DocumentDBClusterClass) DeepCopyInto(out *DocumentDBClusterClass) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
+	in.Spec.DeepCopyInto(&out.Spec)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new DocumentDBClusterClass.
+func (in *DocumentDBClusterClass) DeepCopy() *DocumentDBClusterClass {
+	if in == nil {
+		return nil
+	}
+	out := new(DocumentDBClusterClass)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *DocumentDBClusterClass) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *DocumentDBClusterClassList) DeepCopyInto(out *DocumentDBClusterClassList) {
 	*out = *in
 	out.TypeMeta = in.TypeMeta
 	in.ListMeta.DeepCopyInto(&out.ListMeta)
 	if in.Items != nil {
 		in, out := &in.Items, &out.Items
-		*out = make([]DocumentDB, len(*in))
+		*out = make([]DocumentDBClusterClass, len(*in))
 		for i := range *in {
 			(*in)[i].DeepCopyInto(&(*out)[i])
 		}
 	}
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new DocumentDBList.
-func (in *DocumentDBList) DeepCopy() *DocumentDBList {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new DocumentDBClusterClassList.
+func (in *DocumentDBClusterClassList) DeepCopy() *DocumentDBClusterClassList {
 	if in == nil {
 		return nil
 	}
-	out := new(DocumentDBList)
+	out := new(DocumentDBClusterClassList)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
-func (in *DocumentDBList) DeepCopyObject() runtime.Object {
+func (in *DocumentDBClusterClassList) DeepCopyObject() runtime.Object {
 	if c := in.DeepCopy(); c != nil {
 		return c
 	}
@@ -271,117 +476,523 @@ func (in *DocumentDBList) DeepCopyObject() runtime.Object {
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *DocumentDBSpec) DeepCopyInto(out *DocumentDBSpec) {
+func (in *DocumentDBClusterClassSpec) DeepCopyInto(out *DocumentDBClusterClassSpec) {
 	*out = *in
-	in.Resource.DeepCopyInto(&out.Resource)
+	if in.Sizes != nil {
+		in, out := &in.Sizes, &out.Sizes
+		*out = make([]ClusterClassSize, len(*in))
+		copy(*out, *in)
+	}
 	if in.Image != nil {
 		in, out := &in.Image, &out.Image
 		*out = new(ImageSpec)
 		**out = **in
 	}
-	if in.ImagePullSecrets != nil {
-		in, out := &in.ImagePullSecrets, &out.ImagePullSecrets
-		*out = make([]v1.LocalObjectReference, len(*in))
-		copy(*out, *in)
-	}
-	if in.ClusterReplication != nil {
-		in, out := &in.ClusterReplication, &out.ClusterReplication
-		*out = new(ClusterReplication)
-		(*in).DeepCopyInto(*out)
-	}
-	if in.Postgres != nil {
-		in, out := &in.Postgres, &out.Postgres
-		*out = new(PostgresSpec)
-		(*in).DeepCopyInto(*out)
-	}
-	if in.Plugins != nil {
-		in, out := &in.Plugins, &out.Plugins
-		*out = new(PluginsSpec)
-		**out = **in
-	}
-	out.ExposeViaService = in.ExposeViaService
-	out.Timeouts = in.Timeouts
-	if in.TLS != nil {
-		in, out := &in.TLS, &out.TLS
-		*out = new(TLSConfiguration)
-		(*in).DeepCopyInto(*out)
-	}
-	if in.Bootstrap != nil {
-		in, out := &in.Bootstrap, &out.Bootstrap
-		*out = new(BootstrapConfiguration)
-		(*in).DeepCopyInto(*out)
-	}
 	if in.Backup != nil {
 		in, out := &in.Backup, &out.Backup
 		*out = new(BackupConfiguration)
-		**out = **in
-	}
-	if in.FeatureGates != nil {
-		in, out := &in.FeatureGates, &out.FeatureGates
-		*out = make(map[string]bool, len(*in))
-		for key, val := range *in {
-			(*out)[key] = val
-		}
-	}
-	in.Affinity.DeepCopyInto(&out.Affinity)
-	if in.Monitoring != nil {
-		in, out := &in.Monitoring, &out.Monitoring
-		*out = new(MonitoringSpec)
 		(*in).DeepCopyInto(*out)
 	}
+	in.Affinity.DeepCopyInto(&out.Affinity)
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new DocumentDBSpec.
-func (in *DocumentDBSpec) DeepCopy() *DocumentDBSpec {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new DocumentDBClusterClassSpec.
+func (in *DocumentDBClusterClassSpec) DeepCopy() *DocumentDBClusterClassSpec {
 	if in == nil {
 		return nil
 	}
-	out := new(DocumentDBSpec)
+	out := new(DocumentDBClusterClassSpec)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *DocumentDBStatus) DeepCopyInto(out *DocumentDBStatus) {
+func (in *DocumentDBList) DeepCopyInto(out *DocumentDBList) {
 	*out = *in
-	if in.TLS != nil {
-		in, out := &in.TLS, &out.TLS
-		*out = new(TLSStatus)
-		**out = **in
+	out.TypeMeta = in.TypeMeta
+	in.ListMeta.DeepCopyInto(&out.ListMeta)
+	if in.Items != nil {
+		in, out := &in.Items, &out.Items
+		*out = make([]DocumentDB, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
 	}
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new DocumentDBStatus.
-func (in *DocumentDBStatus) DeepCopy() *DocumentDBStatus {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new DocumentDBList.
+func (in *DocumentDBList) DeepCopy() *DocumentDBList {
 	if in == nil {
 		return nil
 	}
-	out := new(DocumentDBStatus)
+	out := new(DocumentDBList)
 	in.DeepCopyInto(out)
 	return out
 }
 
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *DocumentDBList) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *ExporterSpec) DeepCopyInto(out *ExporterSpec) {
+func (in *DocumentDBOpsRequest) DeepCopyInto(out *DocumentDBOpsRequest) {
 	*out = *in
-	if in.OTLP != nil {
-		in, out := &in.OTLP, &out.OTLP
-		*out = new(OTLPExporterSpec)
-		**out = **in
-	}
-	if in.Prometheus != nil {
-		in, out := &in.Prometheus, &out.Prometheus
-		*out = new(PrometheusExporterSpec)
-		**out = **in
-	}
+	out.TypeMeta = in.TypeMeta
+	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
+	in.Spec.DeepCopyInto(&out.Spec)
+	in.Status.DeepCopyInto(&out.Status)
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ExporterSpec.
-func (in *ExporterSpec) DeepCopy() *ExporterSpec {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new DocumentDBOpsRequest.
+func (in *DocumentDBOpsRequest) DeepCopy() *DocumentDBOpsRequest {
 	if in == nil {
 		return nil
 	}
-	out := new(ExporterSpec)
+	out := new(DocumentDBOpsRequest)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *DocumentDBOpsRequest) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *DocumentDBOpsRequestList) DeepCopyInto(out *DocumentDBOpsRequestList) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ListMeta.DeepCopyInto(&out.ListMeta)
+	if in.Items != nil {
+		in, out := &in.Items, &out.Items
+		*out = make([]DocumentDBOpsRequest, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new DocumentDBOpsRequestList.
+func (in *DocumentDBOpsRequestList) DeepCopy() *DocumentDBOpsRequestList {
+	if in == nil {
+		return nil
+	}
+	out := new(DocumentDBOpsRequestList)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *DocumentDBOpsRequestList) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *DocumentDBOpsRequestSpec) DeepCopyInto(out *DocumentDBOpsRequestSpec) {
+	*out = *in
+	in.Cluster.DeepCopyInto(&out.Cluster)
+	if in.Switchover != nil {
+		in, out := &in.Switchover, &out.Switchover
+		*out = new(SwitchoverOptions)
+		**out = **in
+	}
+	if in.MinorUpgrade != nil {
+		in, out := &in.MinorUpgrade, &out.MinorUpgrade
+		*out = new(MinorUpgradeOptions)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new DocumentDBOpsRequestSpec.
+func (in *DocumentDBOpsRequestSpec) DeepCopy() *DocumentDBOpsRequestSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(DocumentDBOpsRequestSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *DocumentDBOpsRequestStatus) DeepCopyInto(out *DocumentDBOpsRequestStatus) {
+	*out = *in
+	if in.StartedAt != nil {
+		in, out := &in.StartedAt, &out.StartedAt
+		*out = (*in).DeepCopy()
+	}
+	if in.CompletedAt != nil {
+		in, out := &in.CompletedAt, &out.CompletedAt
+		*out = (*in).DeepCopy()
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new DocumentDBOpsRequestStatus.
+func (in *DocumentDBOpsRequestStatus) DeepCopy() *DocumentDBOpsRequestStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(DocumentDBOpsRequestStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *DocumentDBSpec) DeepCopyInto(out *DocumentDBSpec) {
+	*out = *in
+	if in.ClassRef != nil {
+		in, out := &in.ClassRef, &out.ClassRef
+		*out = new(ClusterClassReference)
+		**out = **in
+	}
+	in.Resource.DeepCopyInto(&out.Resource)
+	if in.Image != nil {
+		in, out := &in.Image, &out.Image
+		*out = new(ImageSpec)
+		**out = **in
+	}
+	if in.ImagePullSecrets != nil {
+		in, out := &in.ImagePullSecrets, &out.ImagePullSecrets
+		*out = make([]v1.LocalObjectReference, len(*in))
+		copy(*out, *in)
+	}
+	if in.ClusterReplication != nil {
+		in, out := &in.ClusterReplication, &out.ClusterReplication
+		*out = new(ClusterReplication)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Postgres != nil {
+		in, out := &in.Postgres, &out.Postgres
+		*out = new(PostgresSpec)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Gateway != nil {
+		in, out := &in.Gateway, &out.Gateway
+		*out = new(GatewaySpec)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Plugins != nil {
+		in, out := &in.Plugins, &out.Plugins
+		*out = new(PluginsSpec)
+		(*in).DeepCopyInto(*out)
+	}
+	out.ExposeViaService = in.ExposeViaService
+	if in.AdditionalServices != nil {
+		in, out := &in.AdditionalServices, &out.AdditionalServices
+		*out = make([]apiv1.ManagedService, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	in.Timeouts.DeepCopyInto(&out.Timeouts)
+	if in.TLS != nil {
+		in, out := &in.TLS, &out.TLS
+		*out = new(TLSConfiguration)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Bootstrap != nil {
+		in, out := &in.Bootstrap, &out.Bootstrap
+		*out = new(BootstrapConfiguration)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Backup != nil {
+		in, out := &in.Backup, &out.Backup
+		*out = new(BackupConfiguration)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Export != nil {
+		in, out := &in.Export, &out.Export
+		*out = new(ExportConfiguration)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Migration != nil {
+		in, out := &in.Migration, &out.Migration
+		*out = new(MigrationConfiguration)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.SelfHeal != nil {
+		in, out := &in.SelfHeal, &out.SelfHeal
+		*out = new(SelfHealConfiguration)
+		**out = **in
+	}
+	if in.Eviction != nil {
+		in, out := &in.Eviction, &out.Eviction
+		*out = new(EvictionConfiguration)
+		**out = **in
+	}
+	if in.FeatureGates != nil {
+		in, out := &in.FeatureGates, &out.FeatureGates
+		*out = make(map[string]bool, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
+	if in.ChangeStreams != nil {
+		in, out := &in.ChangeStreams, &out.ChangeStreams
+		*out = new(ChangeStreamsConfiguration)
+		**out = **in
+	}
+	if in.TTL != nil {
+		in, out := &in.TTL, &out.TTL
+		*out = new(TTLConfiguration)
+		**out = **in
+	}
+	if in.Quotas != nil {
+		in, out := &in.Quotas, &out.Quotas
+		*out = make([]DatabaseQuota, len(*in))
+		copy(*out, *in)
+	}
+	in.Affinity.DeepCopyInto(&out.Affinity)
+	if in.NodeMaintenanceWindow != nil {
+		in, out := &in.NodeMaintenanceWindow, &out.NodeMaintenanceWindow
+		*out = new(apiv1.NodeMaintenanceWindow)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Monitoring != nil {
+		in, out := &in.Monitoring, &out.Monitoring
+		*out = new(MonitoringSpec)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.CostLabels != nil {
+		in, out := &in.CostLabels, &out.CostLabels
+		*out = make(map[string]string, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
+	if in.InheritedMetadata != nil {
+		in, out := &in.InheritedMetadata, &out.InheritedMetadata
+		*out = new(InheritedMetadata)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Istio != nil {
+		in, out := &in.Istio, &out.Istio
+		*out = new(IstioConfiguration)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.ServiceAccount != nil {
+		in, out := &in.ServiceAccount, &out.ServiceAccount
+		*out = new(ServiceAccountSpec)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.SecurityContext != nil {
+		in, out := &in.SecurityContext, &out.SecurityContext
+		*out = new(SecurityContextSpec)
+		(*in).DeepCopyInto(*out)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new DocumentDBSpec.
+func (in *DocumentDBSpec) DeepCopy() *DocumentDBSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(DocumentDBSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *DocumentDBStatus) DeepCopyInto(out *DocumentDBStatus) {
+	*out = *in
+	if in.NamespaceDefaults != nil {
+		in, out := &in.NamespaceDefaults, &out.NamespaceDefaults
+		*out = new(NamespaceDefaultsStatus)
+		**out = **in
+	}
+	if in.TLS != nil {
+		in, out := &in.TLS, &out.TLS
+		*out = new(TLSStatus)
+		**out = **in
+	}
+	if in.Bootstrap != nil {
+		in, out := &in.Bootstrap, &out.Bootstrap
+		*out = new(BootstrapStatus)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Import != nil {
+		in, out := &in.Import, &out.Import
+		*out = new(ImportStatus)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.InitScripts != nil {
+		in, out := &in.InitScripts, &out.InitScripts
+		*out = new(InitScriptsStatus)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Migration != nil {
+		in, out := &in.Migration, &out.Migration
+		*out = new(MigrationStatus)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.SelfHeal != nil {
+		in, out := &in.SelfHeal, &out.SelfHeal
+		*out = new(SelfHealStatus)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.StorageExpansion != nil {
+		in, out := &in.StorageExpansion, &out.StorageExpansion
+		*out = new(StorageExpansionStatus)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Instances != nil {
+		in, out := &in.Instances, &out.Instances
+		*out = make([]InstanceStatus, len(*in))
+		copy(*out, *in)
+	}
+	if in.Endpoints != nil {
+		in, out := &in.Endpoints, &out.Endpoints
+		*out = make([]EndpointStatus, len(*in))
+		copy(*out, *in)
+	}
+	if in.Quotas != nil {
+		in, out := &in.Quotas, &out.Quotas
+		*out = make([]DatabaseQuotaStatus, len(*in))
+		copy(*out, *in)
+	}
+	if in.WAL != nil {
+		in, out := &in.WAL, &out.WAL
+		*out = new(WALStatus)
+		**out = **in
+	}
+	if in.InProgressOperations != nil {
+		in, out := &in.InProgressOperations, &out.InProgressOperations
+		*out = make([]InProgressOperation, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.LastErrorTime != nil {
+		in, out := &in.LastErrorTime, &out.LastErrorTime
+		*out = (*in).DeepCopy()
+	}
+	if in.Members != nil {
+		in, out := &in.Members, &out.Members
+		*out = make([]MemberDocumentDBStatus, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.Conditions != nil {
+		in, out := &in.Conditions, &out.Conditions
+		*out = make([]metav1.Condition, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new DocumentDBStatus.
+func (in *DocumentDBStatus) DeepCopy() *DocumentDBStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(DocumentDBStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *EndpointStatus) DeepCopyInto(out *EndpointStatus) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new EndpointStatus.
+func (in *EndpointStatus) DeepCopy() *EndpointStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(EndpointStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *EvictionConfiguration) DeepCopyInto(out *EvictionConfiguration) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new EvictionConfiguration.
+func (in *EvictionConfiguration) DeepCopy() *EvictionConfiguration {
+	if in == nil {
+		return nil
+	}
+	out := new(EvictionConfiguration)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ExportConfiguration) DeepCopyInto(out *ExportConfiguration) {
+	*out = *in
+	if in.Databases != nil {
+		in, out := &in.Databases, &out.Databases
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	in.Destination.DeepCopyInto(&out.Destination)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ExportConfiguration.
+func (in *ExportConfiguration) DeepCopy() *ExportConfiguration {
+	if in == nil {
+		return nil
+	}
+	out := new(ExportConfiguration)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ExportDestination) DeepCopyInto(out *ExportDestination) {
+	*out = *in
+	if in.CredentialsSecret != nil {
+		in, out := &in.CredentialsSecret, &out.CredentialsSecret
+		*out = new(apiv1.LocalObjectReference)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ExportDestination.
+func (in *ExportDestination) DeepCopy() *ExportDestination {
+	if in == nil {
+		return nil
+	}
+	out := new(ExportDestination)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ExporterSpec) DeepCopyInto(out *ExporterSpec) {
+	*out = *in
+	if in.OTLP != nil {
+		in, out := &in.OTLP, &out.OTLP
+		*out = new(OTLPExporterSpec)
+		**out = **in
+	}
+	if in.Prometheus != nil {
+		in, out := &in.Prometheus, &out.Prometheus
+		*out = new(PrometheusExporterSpec)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ExporterSpec.
+func (in *ExporterSpec) DeepCopy() *ExporterSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(ExporterSpec)
 	in.DeepCopyInto(out)
 	return out
 }
@@ -391,132 +1002,542 @@ func (in *ExposeViaService) DeepCopyInto(out *ExposeViaService) {
 	*out = *in
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ExposeViaService.
-func (in *ExposeViaService) DeepCopy() *ExposeViaService {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ExposeViaService.
+func (in *ExposeViaService) DeepCopy() *ExposeViaService {
+	if in == nil {
+		return nil
+	}
+	out := new(ExposeViaService)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *GatewayProbes) DeepCopyInto(out *GatewayProbes) {
+	*out = *in
+	if in.Liveness != nil {
+		in, out := &in.Liveness, &out.Liveness
+		*out = new(apiv1.Probe)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Readiness != nil {
+		in, out := &in.Readiness, &out.Readiness
+		*out = new(apiv1.Probe)
+		(*in).DeepCopyInto(*out)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new GatewayProbes.
+func (in *GatewayProbes) DeepCopy() *GatewayProbes {
+	if in == nil {
+		return nil
+	}
+	out := new(GatewayProbes)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *GatewaySpec) DeepCopyInto(out *GatewaySpec) {
+	*out = *in
+	if in.Probes != nil {
+		in, out := &in.Probes, &out.Probes
+		*out = new(GatewayProbes)
+		(*in).DeepCopyInto(*out)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new GatewaySpec.
+func (in *GatewaySpec) DeepCopy() *GatewaySpec {
+	if in == nil {
+		return nil
+	}
+	out := new(GatewaySpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *GatewayTLS) DeepCopyInto(out *GatewayTLS) {
+	*out = *in
+	if in.CertManager != nil {
+		in, out := &in.CertManager, &out.CertManager
+		*out = new(CertManagerTLS)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Provided != nil {
+		in, out := &in.Provided, &out.Provided
+		*out = new(ProvidedTLS)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new GatewayTLS.
+func (in *GatewayTLS) DeepCopy() *GatewayTLS {
+	if in == nil {
+		return nil
+	}
+	out := new(GatewayTLS)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *GlobalEndpointsTLS) DeepCopyInto(out *GlobalEndpointsTLS) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new GlobalEndpointsTLS.
+func (in *GlobalEndpointsTLS) DeepCopy() *GlobalEndpointsTLS {
 	if in == nil {
 		return nil
 	}
-	out := new(ExposeViaService)
+	out := new(GlobalEndpointsTLS)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *GatewayTLS) DeepCopyInto(out *GatewayTLS) {
+func (in *HugePagesConfiguration) DeepCopyInto(out *HugePagesConfiguration) {
 	*out = *in
-	if in.CertManager != nil {
-		in, out := &in.CertManager, &out.CertManager
-		*out = new(CertManagerTLS)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new HugePagesConfiguration.
+func (in *HugePagesConfiguration) DeepCopy() *HugePagesConfiguration {
+	if in == nil {
+		return nil
+	}
+	out := new(HugePagesConfiguration)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ImageSpec) DeepCopyInto(out *ImageSpec) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ImageSpec.
+func (in *ImageSpec) DeepCopy() *ImageSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(ImageSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ImportConfiguration) DeepCopyInto(out *ImportConfiguration) {
+	*out = *in
+	if in.MongoDB != nil {
+		in, out := &in.MongoDB, &out.MongoDB
+		*out = new(MongoDBImportConfiguration)
 		(*in).DeepCopyInto(*out)
 	}
-	if in.Provided != nil {
-		in, out := &in.Provided, &out.Provided
-		*out = new(ProvidedTLS)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ImportConfiguration.
+func (in *ImportConfiguration) DeepCopy() *ImportConfiguration {
+	if in == nil {
+		return nil
+	}
+	out := new(ImportConfiguration)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ImportStatus) DeepCopyInto(out *ImportStatus) {
+	*out = *in
+	if in.StartedAt != nil {
+		in, out := &in.StartedAt, &out.StartedAt
+		*out = (*in).DeepCopy()
+	}
+	if in.CompletedAt != nil {
+		in, out := &in.CompletedAt, &out.CompletedAt
+		*out = (*in).DeepCopy()
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ImportStatus.
+func (in *ImportStatus) DeepCopy() *ImportStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(ImportStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *InProgressOperation) DeepCopyInto(out *InProgressOperation) {
+	*out = *in
+	in.StartedAt.DeepCopyInto(&out.StartedAt)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new InProgressOperation.
+func (in *InProgressOperation) DeepCopy() *InProgressOperation {
+	if in == nil {
+		return nil
+	}
+	out := new(InProgressOperation)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *InheritedMetadata) DeepCopyInto(out *InheritedMetadata) {
+	*out = *in
+	if in.Labels != nil {
+		in, out := &in.Labels, &out.Labels
+		*out = make(map[string]string, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
+	if in.Annotations != nil {
+		in, out := &in.Annotations, &out.Annotations
+		*out = make(map[string]string, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new InheritedMetadata.
+func (in *InheritedMetadata) DeepCopy() *InheritedMetadata {
+	if in == nil {
+		return nil
+	}
+	out := new(InheritedMetadata)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *InitScript) DeepCopyInto(out *InitScript) {
+	*out = *in
+	in.ConfigMapKeyRef.DeepCopyInto(&out.ConfigMapKeyRef)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new InitScript.
+func (in *InitScript) DeepCopy() *InitScript {
+	if in == nil {
+		return nil
+	}
+	out := new(InitScript)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *InitScriptsStatus) DeepCopyInto(out *InitScriptsStatus) {
+	*out = *in
+	if in.StartedAt != nil {
+		in, out := &in.StartedAt, &out.StartedAt
+		*out = (*in).DeepCopy()
+	}
+	if in.CompletedAt != nil {
+		in, out := &in.CompletedAt, &out.CompletedAt
+		*out = (*in).DeepCopy()
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new InitScriptsStatus.
+func (in *InitScriptsStatus) DeepCopy() *InitScriptsStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(InitScriptsStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *InstanceStatus) DeepCopyInto(out *InstanceStatus) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new InstanceStatus.
+func (in *InstanceStatus) DeepCopy() *InstanceStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(InstanceStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *IssuerRef) DeepCopyInto(out *IssuerRef) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new IssuerRef.
+func (in *IssuerRef) DeepCopy() *IssuerRef {
+	if in == nil {
+		return nil
+	}
+	out := new(IssuerRef)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *IstioConfiguration) DeepCopyInto(out *IstioConfiguration) {
+	*out = *in
+	if in.ExcludeDatabasePorts != nil {
+		in, out := &in.ExcludeDatabasePorts, &out.ExcludeDatabasePorts
+		*out = new(bool)
+		**out = **in
+	}
+	if in.HoldApplicationUntilProxyStarts != nil {
+		in, out := &in.HoldApplicationUntilProxyStarts, &out.HoldApplicationUntilProxyStarts
+		*out = new(bool)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new IstioConfiguration.
+func (in *IstioConfiguration) DeepCopy() *IstioConfiguration {
+	if in == nil {
+		return nil
+	}
+	out := new(IstioConfiguration)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *MemberCluster) DeepCopyInto(out *MemberCluster) {
+	*out = *in
+	if in.KubeconfigSecret != nil {
+		in, out := &in.KubeconfigSecret, &out.KubeconfigSecret
+		*out = new(apiv1.SecretKeySelector)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new MemberCluster.
+func (in *MemberCluster) DeepCopy() *MemberCluster {
+	if in == nil {
+		return nil
+	}
+	out := new(MemberCluster)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *MemberDocumentDBStatus) DeepCopyInto(out *MemberDocumentDBStatus) {
+	*out = *in
+	in.KubeconfigSecret.DeepCopyInto(&out.KubeconfigSecret)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new MemberDocumentDBStatus.
+func (in *MemberDocumentDBStatus) DeepCopy() *MemberDocumentDBStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(MemberDocumentDBStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *MigrationConfiguration) DeepCopyInto(out *MigrationConfiguration) {
+	*out = *in
+	in.SourceRef.DeepCopyInto(&out.SourceRef)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new MigrationConfiguration.
+func (in *MigrationConfiguration) DeepCopy() *MigrationConfiguration {
+	if in == nil {
+		return nil
+	}
+	out := new(MigrationConfiguration)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *MigrationStatus) DeepCopyInto(out *MigrationStatus) {
+	*out = *in
+	if in.LagBytes != nil {
+		in, out := &in.LagBytes, &out.LagBytes
+		*out = new(int64)
 		**out = **in
 	}
+	if in.StartedAt != nil {
+		in, out := &in.StartedAt, &out.StartedAt
+		*out = (*in).DeepCopy()
+	}
+	if in.CompletedAt != nil {
+		in, out := &in.CompletedAt, &out.CompletedAt
+		*out = (*in).DeepCopy()
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new MigrationStatus.
+func (in *MigrationStatus) DeepCopy() *MigrationStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(MigrationStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *MinorUpgradeOptions) DeepCopyInto(out *MinorUpgradeOptions) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new MinorUpgradeOptions.
+func (in *MinorUpgradeOptions) DeepCopy() *MinorUpgradeOptions {
+	if in == nil {
+		return nil
+	}
+	out := new(MinorUpgradeOptions)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *MongoDBImportConfiguration) DeepCopyInto(out *MongoDBImportConfiguration) {
+	*out = *in
+	in.SourceURISecret.DeepCopyInto(&out.SourceURISecret)
+	if in.Databases != nil {
+		in, out := &in.Databases, &out.Databases
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new GatewayTLS.
-func (in *GatewayTLS) DeepCopy() *GatewayTLS {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new MongoDBImportConfiguration.
+func (in *MongoDBImportConfiguration) DeepCopy() *MongoDBImportConfiguration {
 	if in == nil {
 		return nil
 	}
-	out := new(GatewayTLS)
+	out := new(MongoDBImportConfiguration)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *GlobalEndpointsTLS) DeepCopyInto(out *GlobalEndpointsTLS) {
+func (in *MonitoringSpec) DeepCopyInto(out *MonitoringSpec) {
 	*out = *in
+	if in.Exporter != nil {
+		in, out := &in.Exporter, &out.Exporter
+		*out = new(ExporterSpec)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.CollectionStats != nil {
+		in, out := &in.CollectionStats, &out.CollectionStats
+		*out = new(CollectionStatsSpec)
+		**out = **in
+	}
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new GlobalEndpointsTLS.
-func (in *GlobalEndpointsTLS) DeepCopy() *GlobalEndpointsTLS {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new MonitoringSpec.
+func (in *MonitoringSpec) DeepCopy() *MonitoringSpec {
 	if in == nil {
 		return nil
 	}
-	out := new(GlobalEndpointsTLS)
+	out := new(MonitoringSpec)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *ImageSpec) DeepCopyInto(out *ImageSpec) {
+func (in *MountOptionsConfiguration) DeepCopyInto(out *MountOptionsConfiguration) {
 	*out = *in
+	if in.Options != nil {
+		in, out := &in.Options, &out.Options
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.StorageClassOverrides != nil {
+		in, out := &in.StorageClassOverrides, &out.StorageClassOverrides
+		*out = make([]StorageClassMountOptions, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ImageSpec.
-func (in *ImageSpec) DeepCopy() *ImageSpec {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new MountOptionsConfiguration.
+func (in *MountOptionsConfiguration) DeepCopy() *MountOptionsConfiguration {
 	if in == nil {
 		return nil
 	}
-	out := new(ImageSpec)
+	out := new(MountOptionsConfiguration)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *IssuerRef) DeepCopyInto(out *IssuerRef) {
+func (in *NamespaceDefaultsStatus) DeepCopyInto(out *NamespaceDefaultsStatus) {
 	*out = *in
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new IssuerRef.
-func (in *IssuerRef) DeepCopy() *IssuerRef {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new NamespaceDefaultsStatus.
+func (in *NamespaceDefaultsStatus) DeepCopy() *NamespaceDefaultsStatus {
 	if in == nil {
 		return nil
 	}
-	out := new(IssuerRef)
+	out := new(NamespaceDefaultsStatus)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *MemberCluster) DeepCopyInto(out *MemberCluster) {
+func (in *OTLPExporterSpec) DeepCopyInto(out *OTLPExporterSpec) {
 	*out = *in
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new MemberCluster.
-func (in *MemberCluster) DeepCopy() *MemberCluster {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new OTLPExporterSpec.
+func (in *OTLPExporterSpec) DeepCopy() *OTLPExporterSpec {
 	if in == nil {
 		return nil
 	}
-	out := new(MemberCluster)
+	out := new(OTLPExporterSpec)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *MonitoringSpec) DeepCopyInto(out *MonitoringSpec) {
+func (in *ObjectStoreRecoveryConfiguration) DeepCopyInto(out *ObjectStoreRecoveryConfiguration) {
 	*out = *in
-	if in.Exporter != nil {
-		in, out := &in.Exporter, &out.Exporter
-		*out = new(ExporterSpec)
-		(*in).DeepCopyInto(*out)
+	if in.CredentialsSecret != nil {
+		in, out := &in.CredentialsSecret, &out.CredentialsSecret
+		*out = new(apiv1.LocalObjectReference)
+		**out = **in
 	}
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new MonitoringSpec.
-func (in *MonitoringSpec) DeepCopy() *MonitoringSpec {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ObjectStoreRecoveryConfiguration.
+func (in *ObjectStoreRecoveryConfiguration) DeepCopy() *ObjectStoreRecoveryConfiguration {
 	if in == nil {
 		return nil
 	}
-	out := new(MonitoringSpec)
+	out := new(ObjectStoreRecoveryConfiguration)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *OTLPExporterSpec) DeepCopyInto(out *OTLPExporterSpec) {
+func (in *PVCExpansionStatus) DeepCopyInto(out *PVCExpansionStatus) {
 	*out = *in
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new OTLPExporterSpec.
-func (in *OTLPExporterSpec) DeepCopy() *OTLPExporterSpec {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new PVCExpansionStatus.
+func (in *PVCExpansionStatus) DeepCopy() *PVCExpansionStatus {
 	if in == nil {
 		return nil
 	}
-	out := new(OTLPExporterSpec)
+	out := new(PVCExpansionStatus)
 	in.DeepCopyInto(out)
 	return out
 }
@@ -536,9 +1557,31 @@ func (in *PVRecoveryConfiguration) DeepCopy() *PVRecoveryConfiguration {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *PgBaseBackupConfiguration) DeepCopyInto(out *PgBaseBackupConfiguration) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new PgBaseBackupConfiguration.
+func (in *PgBaseBackupConfiguration) DeepCopy() *PgBaseBackupConfiguration {
+	if in == nil {
+		return nil
+	}
+	out := new(PgBaseBackupConfiguration)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *PluginsSpec) DeepCopyInto(out *PluginsSpec) {
 	*out = *in
+	if in.Additional != nil {
+		in, out := &in.Additional, &out.Additional
+		*out = make([]apiv1.PluginConfiguration, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new PluginsSpec.
@@ -576,6 +1619,21 @@ func (in *PostgresSpec) DeepCopyInto(out *PostgresSpec) {
 			(*out)[key] = val
 		}
 	}
+	if in.Probes != nil {
+		in, out := &in.Probes, &out.Probes
+		*out = new(apiv1.ProbesConfiguration)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.EnableSuperuserAccess != nil {
+		in, out := &in.EnableSuperuserAccess, &out.EnableSuperuserAccess
+		*out = new(bool)
+		**out = **in
+	}
+	if in.SuperuserSecret != nil {
+		in, out := &in.SuperuserSecret, &out.SuperuserSecret
+		*out = new(apiv1.LocalObjectReference)
+		**out = **in
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new PostgresSpec.
@@ -627,6 +1685,16 @@ func (in *RecoveryConfiguration) DeepCopyInto(out *RecoveryConfiguration) {
 		*out = new(PVRecoveryConfiguration)
 		**out = **in
 	}
+	if in.ObjectStore != nil {
+		in, out := &in.ObjectStore, &out.ObjectStore
+		*out = new(ObjectStoreRecoveryConfiguration)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.RecoveryTarget != nil {
+		in, out := &in.RecoveryTarget, &out.RecoveryTarget
+		*out = new(apiv1.RecoveryTarget)
+		(*in).DeepCopyInto(*out)
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new RecoveryConfiguration.
@@ -642,7 +1710,7 @@ func (in *RecoveryConfiguration) DeepCopy() *RecoveryConfiguration {
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *Resource) DeepCopyInto(out *Resource) {
 	*out = *in
-	out.Storage = in.Storage
+	in.Storage.DeepCopyInto(&out.Storage)
 	if in.Gateway != nil {
 		in, out := &in.Gateway, &out.Gateway
 		*out = new(ComponentResources)
@@ -658,6 +1726,11 @@ func (in *Resource) DeepCopyInto(out *Resource) {
 		*out = new(ComponentResources)
 		**out = **in
 	}
+	if in.HugePages != nil {
+		in, out := &in.HugePages, &out.HugePages
+		*out = new(HugePagesConfiguration)
+		**out = **in
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new Resource.
@@ -773,9 +1846,153 @@ func (in *ScheduledBackupStatus) DeepCopy() *ScheduledBackupStatus {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *SecurityContextSpec) DeepCopyInto(out *SecurityContextSpec) {
+	*out = *in
+	if in.RunAsUser != nil {
+		in, out := &in.RunAsUser, &out.RunAsUser
+		*out = new(int64)
+		**out = **in
+	}
+	if in.RunAsGroup != nil {
+		in, out := &in.RunAsGroup, &out.RunAsGroup
+		*out = new(int64)
+		**out = **in
+	}
+	if in.FSGroup != nil {
+		in, out := &in.FSGroup, &out.FSGroup
+		*out = new(int64)
+		**out = **in
+	}
+	if in.SeccompProfile != nil {
+		in, out := &in.SeccompProfile, &out.SeccompProfile
+		*out = new(v1.SeccompProfile)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.ReadOnlyRootFilesystem != nil {
+		in, out := &in.ReadOnlyRootFilesystem, &out.ReadOnlyRootFilesystem
+		*out = new(bool)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new SecurityContextSpec.
+func (in *SecurityContextSpec) DeepCopy() *SecurityContextSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(SecurityContextSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *SelfHealConfiguration) DeepCopyInto(out *SelfHealConfiguration) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new SelfHealConfiguration.
+func (in *SelfHealConfiguration) DeepCopy() *SelfHealConfiguration {
+	if in == nil {
+		return nil
+	}
+	out := new(SelfHealConfiguration)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *SelfHealStatus) DeepCopyInto(out *SelfHealStatus) {
+	*out = *in
+	in.Time.DeepCopyInto(&out.Time)
+	if in.UnhealthySince != nil {
+		in, out := &in.UnhealthySince, &out.UnhealthySince
+		*out = (*in).DeepCopy()
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new SelfHealStatus.
+func (in *SelfHealStatus) DeepCopy() *SelfHealStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(SelfHealStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ServiceAccountSpec) DeepCopyInto(out *ServiceAccountSpec) {
+	*out = *in
+	if in.Annotations != nil {
+		in, out := &in.Annotations, &out.Annotations
+		*out = make(map[string]string, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
+	if in.Labels != nil {
+		in, out := &in.Labels, &out.Labels
+		*out = make(map[string]string, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
+	if in.ImagePullSecrets != nil {
+		in, out := &in.ImagePullSecrets, &out.ImagePullSecrets
+		*out = make([]v1.LocalObjectReference, len(*in))
+		copy(*out, *in)
+	}
+	if in.WorkloadIdentity != nil {
+		in, out := &in.WorkloadIdentity, &out.WorkloadIdentity
+		*out = new(WorkloadIdentitySpec)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ServiceAccountSpec.
+func (in *ServiceAccountSpec) DeepCopy() *ServiceAccountSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(ServiceAccountSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *StorageClassMountOptions) DeepCopyInto(out *StorageClassMountOptions) {
+	*out = *in
+	if in.Options != nil {
+		in, out := &in.Options, &out.Options
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new StorageClassMountOptions.
+func (in *StorageClassMountOptions) DeepCopy() *StorageClassMountOptions {
+	if in == nil {
+		return nil
+	}
+	out := new(StorageClassMountOptions)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *StorageConfiguration) DeepCopyInto(out *StorageConfiguration) {
 	*out = *in
+	if in.MountOptions != nil {
+		in, out := &in.MountOptions, &out.MountOptions
+		*out = new(MountOptionsConfiguration)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.WAL != nil {
+		in, out := &in.WAL, &out.WAL
+		*out = new(WALStorageConfiguration)
+		**out = **in
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new StorageConfiguration.
@@ -788,6 +2005,41 @@ func (in *StorageConfiguration) DeepCopy() *StorageConfiguration {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *StorageExpansionStatus) DeepCopyInto(out *StorageExpansionStatus) {
+	*out = *in
+	if in.PVCs != nil {
+		in, out := &in.PVCs, &out.PVCs
+		*out = make([]PVCExpansionStatus, len(*in))
+		copy(*out, *in)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new StorageExpansionStatus.
+func (in *StorageExpansionStatus) DeepCopy() *StorageExpansionStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(StorageExpansionStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *SwitchoverOptions) DeepCopyInto(out *SwitchoverOptions) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new SwitchoverOptions.
+func (in *SwitchoverOptions) DeepCopy() *SwitchoverOptions {
+	if in == nil {
+		return nil
+	}
+	out := new(SwitchoverOptions)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *TLSConfiguration) DeepCopyInto(out *TLSConfiguration) {
 	*out = *in
@@ -833,9 +2085,29 @@ func (in *TLSStatus) DeepCopy() *TLSStatus {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *TTLConfiguration) DeepCopyInto(out *TTLConfiguration) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new TTLConfiguration.
+func (in *TTLConfiguration) DeepCopy() *TTLConfiguration {
+	if in == nil {
+		return nil
+	}
+	out := new(TTLConfiguration)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *Timeouts) DeepCopyInto(out *Timeouts) {
 	*out = *in
+	if in.SmartShutdownTimeout != nil {
+		in, out := &in.SmartShutdownTimeout, &out.SmartShutdownTimeout
+		*out = new(int32)
+		**out = **in
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new Timeouts.
@@ -847,3 +2119,63 @@ func (in *Timeouts) DeepCopy() *Timeouts {
 	in.DeepCopyInto(out)
 	return out
 }
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *VeleroConfiguration) DeepCopyInto(out *VeleroConfiguration) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new VeleroConfiguration.
+func (in *VeleroConfiguration) DeepCopy() *VeleroConfiguration {
+	if in == nil {
+		return nil
+	}
+	out := new(VeleroConfiguration)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *WALStatus) DeepCopyInto(out *WALStatus) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new WALStatus.
+func (in *WALStatus) DeepCopy() *WALStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(WALStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *WALStorageConfiguration) DeepCopyInto(out *WALStorageConfiguration) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new WALStorageConfiguration.
+func (in *WALStorageConfiguration) DeepCopy() *WALStorageConfiguration {
+	if in == nil {
+		return nil
+	}
+	out := new(WALStorageConfiguration)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *WorkloadIdentitySpec) DeepCopyInto(out *WorkloadIdentitySpec) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new WorkloadIdentitySpec.
+func (in *WorkloadIdentitySpec) DeepCopy() *WorkloadIdentitySpec {
+	if in == nil {
+		return nil
+	}
+	out := new(WorkloadIdentitySpec)
+	in.DeepCopyInto(out)
+	return out
+}
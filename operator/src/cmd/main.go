@@ -4,10 +4,12 @@
 package main
 
 import (
+	"context"
 	"crypto/tls"
 	"flag"
 	"os"
 	"path/filepath"
+	"time"
 
 	// Import all Kubernetes client auth plugins (e.g. Azure, GCP, OIDC, etc.)
 	// to ensure that exec-entrypoint and run can make use of them.
@@ -19,8 +21,10 @@ import (
 	clientgoscheme "k8s.io/client-go/kubernetes/scheme"
 	ctrl "sigs.k8s.io/controller-runtime"
 	"sigs.k8s.io/controller-runtime/pkg/certwatcher"
+	"sigs.k8s.io/controller-runtime/pkg/event"
 	"sigs.k8s.io/controller-runtime/pkg/healthz"
 	"sigs.k8s.io/controller-runtime/pkg/log/zap"
+	"sigs.k8s.io/controller-runtime/pkg/metrics"
 	"sigs.k8s.io/controller-runtime/pkg/metrics/filters"
 	metricsserver "sigs.k8s.io/controller-runtime/pkg/metrics/server"
 	"sigs.k8s.io/controller-runtime/pkg/webhook"
@@ -29,6 +33,7 @@ import (
 	cnpgv1 "github.com/cloudnative-pg/cloudnative-pg/api/v1"
 	dbpreview "github.com/documentdb/documentdb-operator/api/preview"
 	"github.com/documentdb/documentdb-operator/internal/controller"
+	util "github.com/documentdb/documentdb-operator/internal/utils"
 	webhookhandler "github.com/documentdb/documentdb-operator/internal/webhook"
 	fleetv1alpha1 "go.goms.io/fleet-networking/api/v1alpha1"
 	// +kubebuilder:scaffold:imports
@@ -58,6 +63,12 @@ func main() {
 	var probeAddr string
 	var secureMetrics bool
 	var enableHTTP2 bool
+	var watchNamespaces string
+	var leaseDuration, renewDeadline, retryPeriod time.Duration
+	var gracefulShutdownTimeout time.Duration
+	var stuckReconcileThreshold time.Duration
+	var kubeAPIQPS float64
+	var kubeAPIBurst int
 	var tlsOpts []func(*tls.Config)
 	flag.StringVar(&metricsAddr, "metrics-bind-address", "0", "The address the metrics endpoint binds to. "+
 		"Use :8443 for HTTPS or :8080 for HTTP, or leave as 0 to disable the metrics service.")
@@ -65,6 +76,23 @@ func main() {
 	flag.BoolVar(&enableLeaderElection, "leader-elect", false,
 		"Enable leader election for controller manager. "+
 			"Enabling this will ensure there is only one active controller manager.")
+	flag.DurationVar(&leaseDuration, "leader-elect-lease-duration", 15*time.Second,
+		"The duration that non-leader candidates will wait to force acquire leadership.")
+	flag.DurationVar(&renewDeadline, "leader-elect-renew-deadline", 10*time.Second,
+		"The duration that the acting leader will retry refreshing leadership before giving up. "+
+			"Must be less than --leader-elect-lease-duration.")
+	flag.DurationVar(&retryPeriod, "leader-elect-retry-period", 2*time.Second,
+		"The duration leader election clients should wait between tries of actions.")
+	flag.DurationVar(&gracefulShutdownTimeout, "graceful-shutdown-timeout", 6*time.Minute,
+		"How long the operator waits on shutdown for in-flight reconciles and background operations "+
+			"(such as extension upgrades) to finish before exiting. Keep it below the pod's terminationGracePeriodSeconds.")
+	flag.DurationVar(&stuckReconcileThreshold, "stuck-reconcile-threshold", 30*time.Minute,
+		"How long a single reconcile may run before the liveness probe fails and the operator is restarted. "+
+			"0 disables the check.")
+	flag.Float64Var(&kubeAPIQPS, "kube-api-qps", 50,
+		"Maximum queries per second from the operator to the Kubernetes API server.")
+	flag.IntVar(&kubeAPIBurst, "kube-api-burst", 100,
+		"Maximum burst of queries from the operator to the Kubernetes API server.")
 	flag.BoolVar(&secureMetrics, "metrics-secure", true,
 		"If set, the metrics endpoint is served securely via HTTPS. Use --metrics-secure=false to use HTTP instead.")
 	flag.StringVar(&webhookCertPath, "webhook-cert-path", "", "The directory that contains the webhook certificate.")
@@ -76,6 +104,9 @@ func main() {
 	flag.StringVar(&metricsCertKey, "metrics-cert-key", "tls.key", "The name of the metrics server key file.")
 	flag.BoolVar(&enableHTTP2, "enable-http2", false,
 		"If set, HTTP/2 will be enabled for the metrics and webhook servers")
+	flag.StringVar(&watchNamespaces, "watch-namespaces", "",
+		"Comma-separated list of namespaces the operator reconciles DocumentDB resources in. "+
+			"Empty (the default) watches all namespaces.")
 	opts := zap.Options{
 		Development: true,
 	}
@@ -173,30 +204,82 @@ func main() {
 		})
 	}
 
-	mgr, err := ctrl.NewManager(ctrl.GetConfigOrDie(), ctrl.Options{
+	watchNamespaceList := util.ParseWatchNamespaces(watchNamespaces)
+	if len(watchNamespaceList) > 0 {
+		setupLog.Info("Restricting the operator to namespaces", "namespaces", watchNamespaceList)
+	}
+
+	if enableLeaderElection && renewDeadline >= leaseDuration {
+		setupLog.Error(nil, "--leader-elect-renew-deadline must be less than --leader-elect-lease-duration",
+			"renewDeadline", renewDeadline, "leaseDuration", leaseDuration)
+		os.Exit(1)
+	}
+
+	// The client-go defaults (5 QPS / 10 burst, or controller-runtime's 20 / 30)
+	// throttle reconciliation on fleets with many DocumentDB objects. The rest
+	// config is shared by the manager clients and the exec clientset.
+	restConfig := ctrl.GetConfigOrDie()
+	restConfig.QPS = float32(kubeAPIQPS)
+	restConfig.Burst = kubeAPIBurst
+
+	mgr, err := ctrl.NewManager(restConfig, ctrl.Options{
 		Scheme:                 scheme,
+		Cache:                  util.CNPGPodsCacheOptions(util.WatchNamespacesCacheOptions(watchNamespaceList)),
+		Client:                 util.UncachedSecretsClientOptions(),
 		Metrics:                metricsServerOptions,
 		WebhookServer:          webhookServer,
 		HealthProbeBindAddress: probeAddr,
 		LeaderElection:         enableLeaderElection,
 		LeaderElectionID:       "187ffea8.microsoft.com",
-		// LeaderElectionReleaseOnCancel defines if the leader should step down voluntarily
-		// when the Manager ends. This requires the binary to immediately end when the
-		// Manager is stopped, otherwise, this setting is unsafe. Setting this significantly
-		// speeds up voluntary leader transitions as the new leader don't have to wait
-		// LeaseDuration time first.
-		//
-		// In the default scaffold provided, the program ends immediately after
-		// the manager stops, so would be fine to enable this option. However,
-		// if you are doing or is intended to do any operation such as perform cleanups
-		// after the manager stops then its usage might be unsafe.
-		// LeaderElectionReleaseOnCancel: true,
+		LeaseDuration:          &leaseDuration,
+		RenewDeadline:          &renewDeadline,
+		RetryPeriod:            &retryPeriod,
+		// On shutdown the manager stops handing out new reconciles, waits up to
+		// GracefulShutdownTimeout for in-flight reconciles and background
+		// operations (which persist their progress in DocumentDB status) to stop,
+		// and only then releases the leader lease. The process exits right after
+		// the manager stops, so releasing the lease early is safe and lets the
+		// next leader resume without waiting for the lease to expire.
+		GracefulShutdownTimeout:       &gracefulShutdownTimeout,
+		LeaderElectionReleaseOnCancel: true,
 	})
 	if err != nil {
 		setupLog.Error(err, "unable to start manager")
 		os.Exit(1)
 	}
 
+	// Operator settings can be hot-reloaded from a ConfigMap in the operator
+	// namespace. Load it once up front so the first reconciles already use it.
+	var operatorConfigEvents chan event.GenericEvent
+	if operatorNamespace := util.OperatorNamespace(); operatorNamespace != "" {
+		operatorConfigMapName := util.OperatorConfigMapName()
+		if err := util.LoadOperatorSettings(context.Background(), mgr.GetAPIReader(), operatorNamespace, operatorConfigMapName); err != nil {
+			setupLog.Error(err, "unable to load operator settings", "configMap", operatorConfigMapName)
+			os.Exit(1)
+		}
+		operatorConfigEvents = make(chan event.GenericEvent, 64)
+		if err = (&controller.OperatorConfigReconciler{
+			Client:           mgr.GetClient(),
+			Namespace:        operatorNamespace,
+			Name:             operatorConfigMapName,
+			DocumentDBEvents: operatorConfigEvents,
+		}).SetupWithManager(mgr); err != nil {
+			setupLog.Error(err, "unable to create controller", "controller", "OperatorConfig")
+			os.Exit(1)
+		}
+	} else {
+		setupLog.Info("OPERATOR_NAMESPACE is not set; operator settings are read from the environment only")
+	}
+
+	// The detected cloud picks the LoadBalancer annotations of the DocumentDBs
+	// without spec.environment, and OpenShift the restricted rendering.
+	if environment, err := util.DetectClusterEnvironment(context.Background(), mgr.GetAPIReader()); err != nil {
+		setupLog.Error(err, "unable to detect the cluster environment; set spec.environment and DOCUMENTDB_OPENSHIFT explicitly")
+	} else {
+		util.SetDetectedClusterEnvironment(environment)
+		setupLog.Info("Detected the cluster environment", "cloud", environment.Cloud, "openShift", environment.OpenShift)
+	}
+
 	if err = (&controller.CertificateReconciler{
 		Client: mgr.GetClient(),
 		Scheme: mgr.GetScheme(),
@@ -212,11 +295,18 @@ func main() {
 		os.Exit(1)
 	}
 
+	// Commands run in the database pods share the limits of one runner
+	podExec := util.NewPodExecRunner(mgr.GetConfig(), clientset)
+
 	if err = (&controller.DocumentDBReconciler{
 		Client:    mgr.GetClient(),
 		Scheme:    mgr.GetScheme(),
 		Config:    mgr.GetConfig(),
 		Clientset: clientset,
+		PodExec:   podExec,
+		Recorder:  mgr.GetEventRecorderFor("documentdb-controller"),
+
+		OperatorConfigEvents: operatorConfigEvents,
 	}).SetupWithManager(mgr); err != nil {
 		setupLog.Error(err, "unable to create controller", "controller", "DocumentDB")
 		os.Exit(1)
@@ -240,13 +330,41 @@ func main() {
 		os.Exit(1)
 	}
 
+	if err = (&controller.OpsRequestReconciler{
+		Client:    mgr.GetClient(),
+		Scheme:    mgr.GetScheme(),
+		Config:    mgr.GetConfig(),
+		Clientset: clientset,
+		PodExec:   podExec,
+		Recorder:  mgr.GetEventRecorderFor("opsrequest-controller"),
+	}).SetupWithManager(mgr); err != nil {
+		setupLog.Error(err, "unable to create controller", "controller", "OpsRequest")
+		os.Exit(1)
+	}
+
 	if err = (&controller.PersistentVolumeReconciler{
-		Client: mgr.GetClient(),
+		Client:          mgr.GetClient(),
+		WatchNamespaces: watchNamespaceList,
+		Recorder:        mgr.GetEventRecorderFor("pv-controller"),
 	}).SetupWithManager(mgr); err != nil {
 		setupLog.Error(err, "unable to create controller", "controller", "PersistentVolume")
 		os.Exit(1)
 	}
 
+	if err = (&controller.ReleasedPVReconciler{
+		Client:          mgr.GetClient(),
+		WatchNamespaces: watchNamespaceList,
+		Recorder:        mgr.GetEventRecorderFor("released-pv-janitor"),
+	}).SetupWithManager(mgr); err != nil {
+		setupLog.Error(err, "unable to create controller", "controller", "ReleasedPV")
+		os.Exit(1)
+	}
+
+	metrics.Registry.MustRegister(&controller.RetainedVolumeCollector{
+		Reader:          mgr.GetClient(),
+		WatchNamespaces: watchNamespaceList,
+	})
+
 	// +kubebuilder:scaffold:builder
 
 	// Register the DocumentDB validating webhook
@@ -255,6 +373,12 @@ func main() {
 		os.Exit(1)
 	}
 
+	// Register the DocumentDBClusterClass validating webhook
+	if err = (&webhookhandler.DocumentDBClusterClassValidator{}).SetupWebhookWithManager(mgr); err != nil {
+		setupLog.Error(err, "unable to create webhook", "webhook", "DocumentDBClusterClass")
+		os.Exit(1)
+	}
+
 	if metricsCertWatcher != nil {
 		setupLog.Info("Adding metrics certificate watcher to manager")
 		if err := mgr.Add(metricsCertWatcher); err != nil {
@@ -275,10 +399,24 @@ func main() {
 		setupLog.Error(err, "unable to set up health check")
 		os.Exit(1)
 	}
+	// Restart the operator when a reconcile is wedged, for example on a call
+	// that never times out, rather than leaving its controller stalled.
+	if err := mgr.AddHealthzCheck("reconcile", controller.ReconcileHealthChecker(stuckReconcileThreshold)); err != nil {
+		setupLog.Error(err, "unable to set up reconcile health check")
+		os.Exit(1)
+	}
+	if err := mgr.AddHealthzCheck("webhook", webhookServer.StartedChecker()); err != nil {
+		setupLog.Error(err, "unable to set up webhook health check")
+		os.Exit(1)
+	}
 	if err := mgr.AddReadyzCheck("readyz", healthz.Ping); err != nil {
 		setupLog.Error(err, "unable to set up ready check")
 		os.Exit(1)
 	}
+	if err := mgr.AddReadyzCheck("informers", controller.CacheSyncChecker(mgr.GetCache())); err != nil {
+		setupLog.Error(err, "unable to set up informer ready check")
+		os.Exit(1)
+	}
 	// Gate readiness on webhook server startup so the Service has no ready
 	// endpoints until the TLS cert is loaded. This prevents the API server
 	// from routing admission requests to an operator that cannot serve them
@@ -5,8 +5,12 @@ package cnpg
 
 import (
 	"cmp"
+	"encoding/json"
 	"fmt"
-	"os"
+	"maps"
+	"slices"
+	"strconv"
+	"strings"
 
 	cnpgv1 "github.com/cloudnative-pg/cloudnative-pg/api/v1"
 	"github.com/go-logr/logr"
@@ -33,10 +37,7 @@ func GetCnpgClusterSpec(req ctrl.Request, documentdb *dbpreview.DocumentDB, docu
 	gatewayImage := util.GetGatewayImageForDocumentDB(documentdb)
 	log.Info("Creating CNPG cluster with gateway image", "gatewayImage", gatewayImage, "documentdbName", documentdb.Name, "specGatewayImage", imageGateway(documentdb))
 
-	credentialSecretName := documentdb.Spec.DocumentDbCredentialSecret
-	if credentialSecretName == "" {
-		credentialSecretName = util.DEFAULT_DOCUMENTDB_CREDENTIALS_SECRET
-	}
+	credentialSecretName := util.CredentialSecretName(documentdb)
 
 	// Configure storage class - use specified storage class or nil for default
 	var storageClassPointer *string
@@ -44,19 +45,18 @@ func GetCnpgClusterSpec(req ctrl.Request, documentdb *dbpreview.DocumentDB, docu
 		storageClassPointer = &storageClass
 	}
 
-	// Set ImageVolumeSource.PullPolicy for the extension image when configured.
-	// This addresses the fact that ImageVolume sources DO support pull policies
-	// (via corev1.ImageVolumeSource.PullPolicy), unlike regular container images
-	// which only support pull policies on container specs.
-	extensionImageSource := corev1.ImageVolumeSource{Reference: documentdbImage}
-	if pullPolicy := parsePullPolicy(os.Getenv(util.DOCUMENTDB_IMAGE_PULL_POLICY_ENV)); pullPolicy != "" {
-		extensionImageSource.PullPolicy = pullPolicy
+	// Image volumes support pull policies through ImageVolumeSource.PullPolicy,
+	// like the containers do.
+	extensionImageSource := corev1.ImageVolumeSource{
+		Reference:  documentdbImage,
+		PullPolicy: util.GetImagePullPolicy(documentdb, util.DOCUMENTDB_IMAGE_PULL_POLICY_ENV),
 	}
 
 	return &cnpgv1.Cluster{
 		ObjectMeta: metav1.ObjectMeta{
 			Name:      req.Name,
 			Namespace: req.Namespace,
+			Labels:    util.ChildLabels(documentdb, map[string]string{util.LABEL_DOCUMENTDB_NAME: documentdb.Name}),
 			OwnerReferences: []metav1.OwnerReference{
 				{
 					APIVersion:         documentdb.APIVersion,
@@ -70,27 +70,45 @@ func GetCnpgClusterSpec(req ctrl.Request, documentdb *dbpreview.DocumentDB, docu
 		},
 		Spec: func() cnpgv1.ClusterSpec {
 			spec := cnpgv1.ClusterSpec{
-				Instances:           documentdb.Spec.InstancesPerNode,
-				ImageName:           imagePostgres(documentdb),
-				ImagePullSecrets:    toCNPGImagePullSecrets(documentdb.Spec.ImagePullSecrets),
-				PrimaryUpdateMethod: cnpgv1.PrimaryUpdateMethodSwitchover,
+				Instances:             documentdb.Spec.InstancesPerNode,
+				ImageName:             imagePostgres(documentdb),
+				ImagePullSecrets:      toCNPGImagePullSecrets(imagePullSecrets(documentdb)),
+				ImagePullPolicy:       documentdb.Spec.ImagePullPolicy,
+				PrimaryUpdateStrategy: cmp.Or(documentdb.Spec.PrimaryUpdateStrategy, cnpgv1.PrimaryUpdateStrategyUnsupervised),
+				PrimaryUpdateMethod:   cmp.Or(documentdb.Spec.PrimaryUpdateMethod, cnpgv1.PrimaryUpdateMethodSwitchover),
 				StorageConfiguration: cnpgv1.StorageConfiguration{
 					StorageClass: storageClassPointer, // Use configured storage class or default
 					Size:         documentdb.Spec.Resource.Storage.PvcSize,
 				},
-				InheritedMetadata: getInheritedMetadataLabels(documentdb.Name),
+				WalStorage:        walStorage(documentdb, storageClassPointer),
+				InheritedMetadata: buildInheritedMetadata(documentdb),
 				Plugins: func() []cnpgv1.PluginConfiguration {
 					params := map[string]string{
 						"gatewayImage":               gatewayImage,
 						"documentDbCredentialSecret": credentialSecretName,
 					}
-					if pullPolicy := os.Getenv(util.GATEWAY_IMAGE_PULL_POLICY_ENV); pullPolicy != "" {
-						params["gatewayImagePullPolicy"] = pullPolicy
-					}
+					addPluginParamIfSet(params, util.PLUGIN_PARAM_GATEWAY_IMAGE_PULL_POLICY,
+						string(util.GetImagePullPolicy(documentdb, util.GATEWAY_IMAGE_PULL_POLICY_ENV)))
 					addPluginParamIfSet(params, util.PLUGIN_PARAM_GATEWAY_MEMORY_REQUEST, split.Gateway.MemoryRequest)
 					addPluginParamIfSet(params, util.PLUGIN_PARAM_GATEWAY_MEMORY_LIMIT, split.Gateway.MemoryLimit)
 					addPluginParamIfSet(params, util.PLUGIN_PARAM_GATEWAY_CPU_REQUEST, split.Gateway.CPURequest)
 					addPluginParamIfSet(params, util.PLUGIN_PARAM_GATEWAY_CPU_LIMIT, split.Gateway.CPULimit)
+					if util.IsOpenShift() {
+						params[util.PLUGIN_PARAM_GATEWAY_RUN_AS_NAMESPACE_UID] = "true"
+					}
+					if gatewaySecurityContext, err := gatewaySecurityContextParam(documentdb); err == nil {
+						addPluginParamIfSet(params, util.PLUGIN_PARAM_GATEWAY_SECURITY_CONTEXT, gatewaySecurityContext)
+					} else {
+						log.Error(err, "Failed to serialize the gateway security context")
+					}
+					if gatewayProbes, err := gatewayProbesParam(documentdb); err == nil {
+						addPluginParamIfSet(params, util.PLUGIN_PARAM_GATEWAY_PROBES, gatewayProbes)
+					} else {
+						log.Error(err, "Failed to serialize the gateway probes")
+					}
+					if gatewayPort := util.GetGatewayPort(documentdb); gatewayPort != util.DEFAULT_GATEWAY_PORT {
+						params[util.PLUGIN_PARAM_GATEWAY_PORT] = strconv.Itoa(int(gatewayPort))
+					}
 					// If TLS is ready, surface secret name to plugin so it can mount certs.
 					if documentdb.Status.TLS != nil && documentdb.Status.TLS.Ready && documentdb.Status.TLS.SecretName != "" {
 						params["gatewayTLSSecret"] = documentdb.Status.TLS.SecretName
@@ -99,7 +117,8 @@ func GetCnpgClusterSpec(req ctrl.Request, documentdb *dbpreview.DocumentDB, docu
 					// Sidecar is only injected when monitoring is enabled.
 					// Config hash triggers operator-initiated rolling restart on config changes.
 					if split.MonitoringEnabled {
-						params["otelCollectorImage"] = util.DEFAULT_OTEL_COLLECTOR_IMAGE
+						params["otelCollectorImage"] = util.GetOtelCollectorImage()
+						addPluginParamIfSet(params, util.PLUGIN_PARAM_OTEL_IMAGE_PULL_POLICY, string(documentdb.Spec.ImagePullPolicy))
 						params["otelConfigMapName"] = otelcfg.ConfigMapName(documentdb.Name)
 						addPluginParamIfSet(params, util.PLUGIN_PARAM_OTEL_MEMORY_REQUEST, split.OTel.MemoryRequest)
 						addPluginParamIfSet(params, util.PLUGIN_PARAM_OTEL_MEMORY_LIMIT, split.OTel.MemoryLimit)
@@ -117,14 +136,15 @@ func GetCnpgClusterSpec(req ctrl.Request, documentdb *dbpreview.DocumentDB, docu
 							log.Error(err, "Failed to generate OTel config hash; config changes may not trigger rolling restart")
 						}
 					}
-					return []cnpgv1.PluginConfiguration{{
+					return append([]cnpgv1.PluginConfiguration{{
 						Name:       sidecarPluginName,
 						Enabled:    pointer.Bool(true),
 						Parameters: params,
-					}}
+					}}, additionalPlugins(documentdb)...)
 				}(),
 				PostgresConfiguration: buildPostgresConfiguration(documentdb, extensionImageSource, split.PostgresMemoryBytes),
 				Bootstrap:             getBootstrapConfiguration(documentdb, isPrimaryRegion, log),
+				ExternalClusters:      getExternalClusters(documentdb, isPrimaryRegion),
 				LogLevel:              cmp.Or(documentdb.Spec.LogLevel, "info"),
 				Certificates:          postgresCertificates(documentdb),
 				Backup: &cnpgv1.BackupConfiguration{
@@ -133,18 +153,92 @@ func GetCnpgClusterSpec(req ctrl.Request, documentdb *dbpreview.DocumentDB, docu
 					},
 					Target: cnpgv1.BackupTarget("primary"),
 				},
-				Affinity:  documentdb.Spec.Affinity,
-				Resources: buildResourceRequirements(split.Postgres),
+				Affinity:               documentdb.Spec.Affinity,
+				Resources:              buildResourceRequirements(split.Postgres),
+				ServiceAccountTemplate: serviceAccountTemplate(documentdb),
 			}
+			applyHugePages(&spec.Resources, documentdb)
 			spec.MaxStopDelay = getMaxStopDelayOrDefault(documentdb)
-			applyPostgresProcessIdentity(&spec, documentdb)
-			applyIOUringSeccomp(&spec, documentdb)
+			spec.MaxStartDelay = getMaxStartDelayOrDefault(documentdb)
+			spec.SmartShutdownTimeout = pointer.Int32(getSmartShutdownTimeoutOrDefault(documentdb))
+			spec.MaxSwitchoverDelay = getMaxSwitchoverDelayOrDefault(documentdb)
+			spec.FailoverDelay = documentdb.Spec.Timeouts.FailoverDelay
+			spec.NodeMaintenanceWindow = nodeMaintenanceWindow(documentdb)
+			spec.Managed = managedConfiguration(documentdb)
+			spec.EnableSuperuserAccess = pointer.Bool(false)
+			if pg := documentdb.Spec.Postgres; pg != nil {
+				spec.Probes = pg.Probes.DeepCopy()
+				if pg.EnableSuperuserAccess != nil {
+					spec.EnableSuperuserAccess = pointer.Bool(*pg.EnableSuperuserAccess)
+				}
+				spec.SuperuserSecret = pg.SuperuserSecret.DeepCopy()
+			}
+			// Under OpenShift the restricted-v2 SCC assigns the UID and GID from
+			// the namespace range and only admits the RuntimeDefault seccomp
+			// profile; CNPG detects the SCCs and leaves its own pod security
+			// context unset. The webhook rejects the fields skipped here.
+			if !util.IsOpenShift() {
+				applyPostgresProcessIdentity(&spec, documentdb)
+				applyIOUringSeccomp(&spec, documentdb)
+			}
+			applySecurityContext(&spec, documentdb)
 
 			return spec
 		}(),
 	}
 }
 
+// nodeMaintenanceWindow returns a copy of spec.nodeMaintenanceWindow with
+// reusePVC defaulted as the CNPG API server would, so that an unset value
+// does not show up as drift on every sync.
+func nodeMaintenanceWindow(documentdb *dbpreview.DocumentDB) *cnpgv1.NodeMaintenanceWindow {
+	window := documentdb.Spec.NodeMaintenanceWindow.DeepCopy()
+	if window != nil && window.ReusePVC == nil {
+		window.ReusePVC = pointer.Bool(true)
+	}
+	return window
+}
+
+// walStorage returns the WAL volume of spec.resource.storage.wal, in the
+// storage class of the data volume unless it sets its own, or nil when the
+// WAL stays on the data volume.
+func walStorage(documentdb *dbpreview.DocumentDB, dataStorageClass *string) *cnpgv1.StorageConfiguration {
+	wal := documentdb.Spec.Resource.Storage.WAL
+	if wal == nil {
+		return nil
+	}
+	storageClass := dataStorageClass
+	if wal.StorageClass != "" {
+		storageClass = &wal.StorageClass
+	}
+	return &cnpgv1.StorageConfiguration{
+		StorageClass: storageClass,
+		Size:         wal.PvcSize,
+	}
+}
+
+// managedConfiguration returns the managed Services of spec.additionalServices,
+// with the update strategy and port protocols defaulted as the CNPG API
+// server would, or nil when there are none.
+func managedConfiguration(documentdb *dbpreview.DocumentDB) *cnpgv1.ManagedConfiguration {
+	if len(documentdb.Spec.AdditionalServices) == 0 {
+		return nil
+	}
+	services := make([]cnpgv1.ManagedService, 0, len(documentdb.Spec.AdditionalServices))
+	for _, service := range documentdb.Spec.AdditionalServices {
+		service := *service.DeepCopy()
+		service.UpdateStrategy = cmp.Or(service.UpdateStrategy, cnpgv1.ServiceUpdateStrategyPatch)
+		for i := range service.ServiceTemplate.Spec.Ports {
+			port := &service.ServiceTemplate.Spec.Ports[i]
+			port.Protocol = cmp.Or(port.Protocol, corev1.ProtocolTCP)
+		}
+		services = append(services, service)
+	}
+	return &cnpgv1.ManagedConfiguration{
+		Services: &cnpgv1.ManagedServices{Additional: services},
+	}
+}
+
 func addPluginParamIfSet(params map[string]string, key, value string) {
 	if value != "" {
 		params[key] = value
@@ -160,6 +254,32 @@ func getInheritedMetadataLabels(appName string) *cnpgv1.EmbeddedObjectMetadata {
 	}
 }
 
+// buildInheritedMetadata returns the metadata CNPG propagates to the pods, PVCs
+// and Services it creates, including the user-provided labels and annotations.
+func buildInheritedMetadata(documentdb *dbpreview.DocumentDB) *cnpgv1.EmbeddedObjectMetadata {
+	metadata := getInheritedMetadataLabels(documentdb.Name)
+	metadata.Labels = util.ChildLabels(documentdb, metadata.Labels)
+	metadata.Annotations = util.ChildAnnotations(documentdb, podAnnotations(documentdb))
+	if identity := workloadIdentity(documentdb); identity != nil && identity.Provider == dbpreview.WorkloadIdentityAzure {
+		metadata.Labels[azureWorkloadIdentityLabel] = "true"
+	}
+	return metadata
+}
+
+// podAnnotations returns the annotations the operator sets for the tools that
+// read them from the database pods: the Velero hooks and Istio. CNPG also sets
+// them on the PVCs and Services, where they are ignored.
+func podAnnotations(documentdb *dbpreview.DocumentDB) map[string]string {
+	annotations := util.VeleroHookAnnotations(documentdb)
+	if istio := util.IstioAnnotations(documentdb); len(istio) > 0 {
+		if annotations == nil {
+			annotations = map[string]string{}
+		}
+		maps.Copy(annotations, istio)
+	}
+	return annotations
+}
+
 func getBootstrapConfiguration(documentdb *dbpreview.DocumentDB, isPrimaryRegion bool, log logr.Logger) *cnpgv1.BootstrapConfiguration {
 	if isPrimaryRegion && documentdb.Spec.Bootstrap != nil && documentdb.Spec.Bootstrap.Recovery != nil {
 		recovery := documentdb.Spec.Bootstrap.Recovery
@@ -173,32 +293,203 @@ func getBootstrapConfiguration(documentdb *dbpreview.DocumentDB, isPrimaryRegion
 					Backup: &cnpgv1.BackupSource{
 						LocalObjectReference: recovery.Backup,
 					},
+					RecoveryTarget: recovery.RecoveryTarget,
+				},
+			}
+		}
+
+		// Handle recovery from an object store archive (see getExternalClusters)
+		if recovery.ObjectStore != nil {
+			log.Info("DocumentDB cluster will be bootstrapped from object store",
+				"destinationPath", recovery.ObjectStore.DestinationPath, "serverName", recovery.ObjectStore.ServerName)
+			return &cnpgv1.BootstrapConfiguration{
+				Recovery: &cnpgv1.BootstrapRecovery{
+					Source:         objectStoreSourceName,
+					RecoveryTarget: recovery.RecoveryTarget,
 				},
 			}
 		}
+	}
+
+	// Handle PV recovery, configured or adopted (via temporary PVC created by the controller)
+	if isPrimaryRegion && documentdb.IsPVRecoveryConfigured() {
+		tempPVCName := util.TempPVCNameForPVRecovery(documentdb.Name)
+		log.Info("DocumentDB cluster will be bootstrapped from PV via temp PVC",
+			"pvName", documentdb.GetPVNameForRecovery(), "tempPVC", tempPVCName)
+		return &cnpgv1.BootstrapConfiguration{
+			Recovery: &cnpgv1.BootstrapRecovery{
+				VolumeSnapshots: &cnpgv1.DataSource{
+					Storage: corev1.TypedLocalObjectReference{
+						Name:     tempPVCName,
+						Kind:     "PersistentVolumeClaim",
+						APIGroup: pointer.String(""),
+					},
+				},
+			},
+		}
+	}
 
-		// Handle PV recovery (via temporary PVC created by the controller)
-		if recovery.PersistentVolume != nil && recovery.PersistentVolume.Name != "" {
-			tempPVCName := util.TempPVCNameForPVRecovery(documentdb.Name)
-			log.Info("DocumentDB cluster will be bootstrapped from PV via temp PVC",
-				"pvName", recovery.PersistentVolume.Name, "tempPVC", tempPVCName)
+	// Handle clone (from the Backup, or the snapshot imported into the
+	// namespace, prepared by the controller)
+	if isPrimaryRegion && documentdb.IsCloneConfigured() {
+		source := documentdb.GetCloneSource()
+		if source.Namespace == documentdb.Namespace {
+			backupName := util.CloneBackupName(documentdb.Name, documentdb.Namespace, source.Namespace)
+			log.Info("DocumentDB cluster will be cloned from backup", "source", source.Name, "backupName", backupName)
 			return &cnpgv1.BootstrapConfiguration{
 				Recovery: &cnpgv1.BootstrapRecovery{
-					VolumeSnapshots: &cnpgv1.DataSource{
-						Storage: corev1.TypedLocalObjectReference{
-							Name:     tempPVCName,
-							Kind:     "PersistentVolumeClaim",
-							APIGroup: pointer.String(""),
-						},
+					Backup: &cnpgv1.BackupSource{
+						LocalObjectReference: cnpgv1.LocalObjectReference{Name: backupName},
 					},
 				},
 			}
 		}
+		snapshotName := util.CloneSnapshotName(documentdb.Name)
+		log.Info("DocumentDB cluster will be cloned from imported snapshot", "source", source, "snapshot", snapshotName)
+		return &cnpgv1.BootstrapConfiguration{
+			Recovery: &cnpgv1.BootstrapRecovery{
+				VolumeSnapshots: &cnpgv1.DataSource{
+					Storage: corev1.TypedLocalObjectReference{
+						Name:     snapshotName,
+						Kind:     "VolumeSnapshot",
+						APIGroup: pointer.String("snapshot.storage.k8s.io"),
+					},
+				},
+			},
+		}
+	}
+
+	// Handle pg_basebackup from a running cluster (see getExternalClusters)
+	if isPrimaryRegion && documentdb.Spec.Bootstrap != nil && documentdb.Spec.Bootstrap.PgBaseBackup != nil {
+		log.Info("DocumentDB cluster will be bootstrapped with pg_basebackup",
+			"sourceService", documentdb.Spec.Bootstrap.PgBaseBackup.SourceService)
+		return &cnpgv1.BootstrapConfiguration{
+			PgBaseBackup: &cnpgv1.BootstrapPgBaseBackup{
+				Source: pgBaseBackupSourceName,
+			},
+		}
 	}
 
 	return getDefaultBootstrapConfiguration(documentdb)
 }
 
+// pgBaseBackupSourceName names the external cluster of a pg_basebackup bootstrap.
+const pgBaseBackupSourceName = "pgbasebackup-source"
+
+// objectStoreSourceName names the external cluster of a recovery from an
+// object store archive.
+const objectStoreSourceName = "objectstore-source"
+
+// MigrationSourceName names the external cluster that the Subscription of
+// spec.migration connects to.
+const MigrationSourceName = "migration-source"
+
+// getExternalClusters returns the external clusters that a pg_basebackup
+// bootstrap copies from, that a recovery from an object store reads, and
+// that the Subscription of spec.migration replicates from. It returns nil
+// when none is configured.
+func getExternalClusters(documentdb *dbpreview.DocumentDB, isPrimaryRegion bool) []cnpgv1.ExternalCluster {
+	if !isPrimaryRegion {
+		return nil
+	}
+	var externalClusters []cnpgv1.ExternalCluster
+	if documentdb.Spec.Bootstrap != nil && documentdb.Spec.Bootstrap.PgBaseBackup != nil {
+		source := documentdb.Spec.Bootstrap.PgBaseBackup
+		// The CNPG cluster behind "<cluster>-rw[.<namespace>.svc]" names the
+		// default Secrets.
+		sourceCluster := strings.TrimSuffix(strings.SplitN(source.SourceService, ".", 2)[0], "-rw")
+		externalClusters = append(externalClusters, streamingReplicaExternalCluster(
+			pgBaseBackupSourceName,
+			source.SourceService,
+			cmp.Or(source.ReplicationTLSSecret, sourceCluster+"-replication"),
+			cmp.Or(source.ServerCASecret, sourceCluster+"-ca"),
+		))
+	}
+	if bootstrap := documentdb.Spec.Bootstrap; bootstrap != nil && bootstrap.Recovery != nil && bootstrap.Recovery.ObjectStore != nil {
+		objectStore := bootstrap.Recovery.ObjectStore
+		externalClusters = append(externalClusters, cnpgv1.ExternalCluster{
+			Name: objectStoreSourceName,
+			BarmanObjectStore: &cnpgv1.BarmanObjectStoreConfiguration{
+				DestinationPath:   objectStore.DestinationPath,
+				ServerName:        objectStore.ServerName,
+				EndpointURL:       objectStore.EndpointURL,
+				BarmanCredentials: getObjectStoreCredentials(objectStore, workloadIdentity(documentdb)),
+			},
+		})
+	}
+	if migration := documentdb.Spec.Migration; migration != nil {
+		// The source has no cluster replication, so its CNPG Cluster is named
+		// after it.
+		sourceCluster := migration.SourceRef.Name
+		externalClusters = append(externalClusters, streamingReplicaExternalCluster(
+			MigrationSourceName, sourceCluster+"-rw", sourceCluster+"-replication", sourceCluster+"-ca"))
+	}
+	return externalClusters
+}
+
+// getObjectStoreCredentials returns the credentials of the object store the
+// archive is read from, picked from the scheme of its path: the keys of the
+// credentials Secret or, without one, the identity of the pods. An Azure
+// workload identity is only picked up by the default Azure credential chain,
+// not as a managed identity.
+func getObjectStoreCredentials(objectStore *dbpreview.ObjectStoreRecoveryConfiguration, identity *dbpreview.WorkloadIdentitySpec) cnpgv1.BarmanCredentials {
+	secretKey := func(key string) *cnpgv1.SecretKeySelector {
+		return &cnpgv1.SecretKeySelector{LocalObjectReference: *objectStore.CredentialsSecret, Key: key}
+	}
+	hasSecret := objectStore.CredentialsSecret != nil
+	switch {
+	case strings.HasPrefix(objectStore.DestinationPath, "gs://"):
+		if hasSecret {
+			return cnpgv1.BarmanCredentials{Google: &cnpgv1.GoogleCredentials{ApplicationCredentials: secretKey("GOOGLE_APPLICATION_CREDENTIALS")}}
+		}
+		return cnpgv1.BarmanCredentials{Google: &cnpgv1.GoogleCredentials{GKEEnvironment: true}}
+	case strings.HasPrefix(objectStore.DestinationPath, "https://"):
+		if hasSecret {
+			return cnpgv1.BarmanCredentials{Azure: &cnpgv1.AzureCredentials{ConnectionString: secretKey("AZURE_STORAGE_CONNECTION_STRING")}}
+		}
+		if identity != nil && identity.Provider == dbpreview.WorkloadIdentityAzure {
+			return cnpgv1.BarmanCredentials{Azure: &cnpgv1.AzureCredentials{UseDefaultAzureCredentials: true}}
+		}
+		return cnpgv1.BarmanCredentials{Azure: &cnpgv1.AzureCredentials{InheritFromAzureAD: true}}
+	default:
+		if hasSecret {
+			return cnpgv1.BarmanCredentials{AWS: &cnpgv1.S3Credentials{
+				AccessKeyIDReference:     secretKey("AWS_ACCESS_KEY_ID"),
+				SecretAccessKeyReference: secretKey("AWS_SECRET_ACCESS_KEY"),
+			}}
+		}
+		return cnpgv1.BarmanCredentials{AWS: &cnpgv1.S3Credentials{InheritFromIAMRole: true}}
+	}
+}
+
+// streamingReplicaExternalCluster returns an external cluster reached at host
+// as the streaming_replica user with TLS client certificate authentication,
+// as CNPG clusters accept by default.
+func streamingReplicaExternalCluster(name, host, replicationTLSSecret, serverCASecret string) cnpgv1.ExternalCluster {
+	return cnpgv1.ExternalCluster{
+		Name: name,
+		ConnectionParameters: map[string]string{
+			"host":    host,
+			"port":    strconv.Itoa(util.DEFAULT_POSTGRES_PORT),
+			"dbname":  "postgres",
+			"user":    "streaming_replica",
+			"sslmode": "verify-full",
+		},
+		SSLCert: &corev1.SecretKeySelector{
+			LocalObjectReference: corev1.LocalObjectReference{Name: replicationTLSSecret},
+			Key:                  "tls.crt",
+		},
+		SSLKey: &corev1.SecretKeySelector{
+			LocalObjectReference: corev1.LocalObjectReference{Name: replicationTLSSecret},
+			Key:                  "tls.key",
+		},
+		SSLRootCert: &corev1.SecretKeySelector{
+			LocalObjectReference: corev1.LocalObjectReference{Name: serverCASecret},
+			Key:                  "ca.crt",
+		},
+	}
+}
+
 func getDefaultBootstrapConfiguration(documentdb *dbpreview.DocumentDB) *cnpgv1.BootstrapConfiguration {
 	postInitSQL := []string{
 		"CREATE EXTENSION documentdb CASCADE",
@@ -215,14 +506,43 @@ func getDefaultBootstrapConfiguration(documentdb *dbpreview.DocumentDB) *cnpgv1.
 	}
 }
 
-// getMaxStopDelayOrDefault returns StopDelay if set, otherwise util.CNPG_DEFAULT_STOP_DELAY
+// getMaxStopDelayOrDefault returns StopDelay if set within the range CNPG
+// accepts, otherwise util.CNPG_DEFAULT_STOP_DELAY
 func getMaxStopDelayOrDefault(documentdb *dbpreview.DocumentDB) int32 {
-	if documentdb.Spec.Timeouts.StopDelay != 0 {
-		return documentdb.Spec.Timeouts.StopDelay
+	if stopDelay := documentdb.Spec.Timeouts.StopDelay; stopDelay > 0 && stopDelay <= util.CNPG_MAX_STOP_DELAY {
+		return stopDelay
 	}
 	return util.CNPG_DEFAULT_STOP_DELAY
 }
 
+// getMaxStartDelayOrDefault returns StartDelay if set, otherwise
+// util.CNPG_DEFAULT_START_DELAY, so that the CNPG Cluster always carries the
+// value its startup probe is derived from.
+func getMaxStartDelayOrDefault(documentdb *dbpreview.DocumentDB) int32 {
+	if documentdb.Spec.Timeouts.StartDelay > 0 {
+		return documentdb.Spec.Timeouts.StartDelay
+	}
+	return util.CNPG_DEFAULT_START_DELAY
+}
+
+// getSmartShutdownTimeoutOrDefault returns SmartShutdownTimeout if set,
+// otherwise util.CNPG_DEFAULT_SMART_SHUTDOWN_TIMEOUT.
+func getSmartShutdownTimeoutOrDefault(documentdb *dbpreview.DocumentDB) int32 {
+	if timeout := documentdb.Spec.Timeouts.SmartShutdownTimeout; timeout != nil {
+		return *timeout
+	}
+	return util.CNPG_DEFAULT_SMART_SHUTDOWN_TIMEOUT
+}
+
+// getMaxSwitchoverDelayOrDefault returns SwitchoverDelay if set, otherwise
+// util.CNPG_DEFAULT_SWITCHOVER_DELAY.
+func getMaxSwitchoverDelayOrDefault(documentdb *dbpreview.DocumentDB) int32 {
+	if documentdb.Spec.Timeouts.SwitchoverDelay > 0 {
+		return documentdb.Spec.Timeouts.SwitchoverDelay
+	}
+	return util.CNPG_DEFAULT_SWITCHOVER_DELAY
+}
+
 // parseMemoryToBytes converts a Kubernetes quantity string (e.g., "2Gi", "4096Mi")
 // to bytes. Returns 0 if the string is empty or "0" (meaning unlimited/unset).
 func parseMemoryToBytes(memoryStr string) int64 {
@@ -278,17 +598,6 @@ func parseResourceQuantity(value string) (resource.Quantity, bool) {
 	return quantity, true
 }
 
-// parsePullPolicy converts a string to a corev1.PullPolicy.
-// Returns empty string for unrecognized values.
-func parsePullPolicy(value string) corev1.PullPolicy {
-	switch corev1.PullPolicy(value) {
-	case corev1.PullAlways, corev1.PullNever, corev1.PullIfNotPresent:
-		return corev1.PullPolicy(value)
-	default:
-		return ""
-	}
-}
-
 // imagePostgres returns spec.image.postgres or empty string when unset.
 // Nil-safe.
 func imagePostgres(documentdb *dbpreview.DocumentDB) string {
@@ -307,6 +616,27 @@ func imageGateway(documentdb *dbpreview.DocumentDB) string {
 	return documentdb.Spec.Image.Gateway
 }
 
+// additionalPlugins returns a copy of spec.plugins.additional with the CNPG
+// defaults filled in, so that it compares equal to the plugins of the
+// Cluster. Nil-safe.
+func additionalPlugins(documentdb *dbpreview.DocumentDB) []cnpgv1.PluginConfiguration {
+	if documentdb == nil || documentdb.Spec.Plugins == nil {
+		return nil
+	}
+	plugins := make([]cnpgv1.PluginConfiguration, 0, len(documentdb.Spec.Plugins.Additional))
+	for i := range documentdb.Spec.Plugins.Additional {
+		plugin := documentdb.Spec.Plugins.Additional[i].DeepCopy()
+		if plugin.Enabled == nil {
+			plugin.Enabled = pointer.Bool(true)
+		}
+		if plugin.IsWALArchiver == nil {
+			plugin.IsWALArchiver = pointer.Bool(false)
+		}
+		plugins = append(plugins, *plugin)
+	}
+	return plugins
+}
+
 // pluginsSidecarInjectorName returns spec.plugins.sidecarInjectorName
 // or empty string when unset. Nil-safe.
 func pluginsSidecarInjectorName(documentdb *dbpreview.DocumentDB) string {
@@ -323,6 +653,44 @@ func postgresCertificates(documentdb *dbpreview.DocumentDB) *cnpgv1.Certificates
 	return documentdb.Spec.TLS.Postgres
 }
 
+// imagePullSecrets returns spec.imagePullSecrets followed by the pull secrets
+// of spec.serviceAccount that are not already listed.
+func imagePullSecrets(documentdb *dbpreview.DocumentDB) []corev1.LocalObjectReference {
+	if documentdb.Spec.ServiceAccount == nil || len(documentdb.Spec.ServiceAccount.ImagePullSecrets) == 0 {
+		return documentdb.Spec.ImagePullSecrets
+	}
+	secrets := slices.Clone(documentdb.Spec.ImagePullSecrets)
+	for _, secret := range documentdb.Spec.ServiceAccount.ImagePullSecrets {
+		if !slices.Contains(secrets, secret) {
+			secrets = append(secrets, secret)
+		}
+	}
+	return secrets
+}
+
+// serviceAccountTemplate returns the metadata CNPG merges into the ServiceAccount
+// it creates for the cluster, or nil when spec.serviceAccount sets none. The
+// annotation of the workload identity takes precedence over the annotations.
+func serviceAccountTemplate(documentdb *dbpreview.DocumentDB) *cnpgv1.ServiceAccountTemplate {
+	sa := documentdb.Spec.ServiceAccount
+	if sa == nil || (len(sa.Annotations) == 0 && len(sa.Labels) == 0 && sa.WorkloadIdentity == nil) {
+		return nil
+	}
+	annotations := maps.Clone(sa.Annotations)
+	if identity := sa.WorkloadIdentity; identity != nil {
+		if annotations == nil {
+			annotations = map[string]string{}
+		}
+		annotations[WorkloadIdentityAnnotation(identity.Provider)] = identity.Identity
+	}
+	return &cnpgv1.ServiceAccountTemplate{
+		Metadata: cnpgv1.Metadata{
+			Annotations: annotations,
+			Labels:      maps.Clone(sa.Labels),
+		},
+	}
+}
+
 // toCNPGImagePullSecrets translates a list of corev1.LocalObjectReference
 // (the Kubernetes-native shape used on spec.imagePullSecrets) into the
 // CNPG-flavoured cnpgv1.LocalObjectReference shape that
@@ -373,13 +741,75 @@ func applyIOUringSeccomp(spec *cnpgv1.ClusterSpec, documentdb *dbpreview.Documen
 	if !dbpreview.IsFeatureGateEnabled(documentdb, dbpreview.FeatureGateIOUring) {
 		return
 	}
-	profile := cmp.Or(os.Getenv(util.IOURING_SECCOMP_PROFILE_ENV), util.DEFAULT_IOURING_SECCOMP_PROFILE)
+	profile := cmp.Or(util.GetOperatorSetting(util.IOURING_SECCOMP_PROFILE_ENV), util.DEFAULT_IOURING_SECCOMP_PROFILE)
 	spec.SeccompProfile = &corev1.SeccompProfile{
 		Type:             corev1.SeccompProfileTypeLocalhost,
 		LocalhostProfile: pointer.String(profile),
 	}
 }
 
+// applySecurityContext applies spec.securityContext to the instance pods:
+// the UID and GIDs through the pod security context, the seccomp profile
+// through the cluster-wide profile and readOnlyRootFilesystem through the
+// container security context. CNPG fills the fields left unset with its own
+// defaults, except fsGroup, which defaults to the effective GID here.
+func applySecurityContext(spec *cnpgv1.ClusterSpec, documentdb *dbpreview.DocumentDB) {
+	sc := documentdb.Spec.SecurityContext
+	if sc == nil {
+		return
+	}
+	if sc.SeccompProfile != nil {
+		spec.SeccompProfile = sc.SeccompProfile.DeepCopy()
+	}
+	if sc.RunAsUser != nil || sc.RunAsGroup != nil || sc.FSGroup != nil {
+		fsGroup := cmp.Or(sc.FSGroup, sc.RunAsGroup)
+		if fsGroup == nil {
+			fsGroup = pointer.Int64(cmp.Or(spec.PostgresGID, cnpgv1.DefaultPostgresGID))
+		}
+		spec.PodSecurityContext = &corev1.PodSecurityContext{
+			RunAsUser:    sc.RunAsUser,
+			RunAsGroup:   sc.RunAsGroup,
+			FSGroup:      fsGroup,
+			RunAsNonRoot: pointer.Bool(true),
+		}
+	}
+	if sc.ReadOnlyRootFilesystem != nil {
+		spec.SecurityContext = &corev1.SecurityContext{ReadOnlyRootFilesystem: sc.ReadOnlyRootFilesystem}
+	}
+}
+
+// gatewaySecurityContextParam serializes the spec.securityContext fields that
+// the sidecar-injector plugin applies to the gateway container. It returns an
+// empty string when none is set.
+func gatewaySecurityContextParam(documentdb *dbpreview.DocumentDB) (string, error) {
+	sc := documentdb.Spec.SecurityContext
+	if sc == nil || (sc.RunAsUser == nil && sc.RunAsGroup == nil && sc.SeccompProfile == nil && sc.ReadOnlyRootFilesystem == nil) {
+		return "", nil
+	}
+	data, err := json.Marshal(corev1.SecurityContext{
+		RunAsUser:              sc.RunAsUser,
+		RunAsGroup:             sc.RunAsGroup,
+		SeccompProfile:         sc.SeccompProfile,
+		ReadOnlyRootFilesystem: sc.ReadOnlyRootFilesystem,
+	})
+	return string(data), err
+}
+
+// gatewayProbesParam serializes spec.gateway.probes for the sidecar-injector
+// plugin, which probes the gateway port with these timings. It returns an
+// empty string when no probe is set.
+func gatewayProbesParam(documentdb *dbpreview.DocumentDB) (string, error) {
+	if documentdb.Spec.Gateway == nil || documentdb.Spec.Gateway.Probes == nil {
+		return "", nil
+	}
+	probes := documentdb.Spec.Gateway.Probes
+	if probes.Liveness == nil && probes.Readiness == nil {
+		return "", nil
+	}
+	data, err := json.Marshal(probes)
+	return string(data), err
+}
+
 // buildPostgresConfiguration returns the cnpgv1.PostgresConfiguration block
 // for the cluster.
 //
@@ -120,6 +120,178 @@ var _ = Describe("getBootstrapConfiguration", func() {
 		Expect(result.InitDB).To(BeNil())
 	})
 
+	It("returns backup recovery for a clone in the namespace of its source", func() {
+		documentdb := &dbpreview.DocumentDB{
+			ObjectMeta: metav1.ObjectMeta{Name: "staging", Namespace: "default"},
+			Spec: dbpreview.DocumentDBSpec{
+				Bootstrap: &dbpreview.BootstrapConfiguration{
+					Clone: &dbpreview.CloneConfiguration{SourceRef: dbpreview.CloneSourceReference{Name: "prod"}},
+				},
+			},
+		}
+
+		result := getBootstrapConfiguration(documentdb, true, log)
+		Expect(result.Recovery).ToNot(BeNil())
+		Expect(result.Recovery.Backup.LocalObjectReference.Name).To(Equal("staging-clone"))
+		Expect(result.InitDB).To(BeNil())
+	})
+
+	It("returns snapshot recovery for a clone in another namespace", func() {
+		documentdb := &dbpreview.DocumentDB{
+			ObjectMeta: metav1.ObjectMeta{Name: "staging", Namespace: "qa"},
+			Spec: dbpreview.DocumentDBSpec{
+				Bootstrap: &dbpreview.BootstrapConfiguration{
+					Clone: &dbpreview.CloneConfiguration{SourceRef: dbpreview.CloneSourceReference{Name: "prod", Namespace: "default"}},
+				},
+			},
+		}
+
+		result := getBootstrapConfiguration(documentdb, true, log)
+		Expect(result.Recovery).ToNot(BeNil())
+		Expect(result.Recovery.Backup).To(BeNil())
+		Expect(result.Recovery.VolumeSnapshots.Storage.Name).To(Equal("staging-clone"))
+		Expect(result.Recovery.VolumeSnapshots.Storage.Kind).To(Equal("VolumeSnapshot"))
+		Expect(result.Recovery.VolumeSnapshots.Storage.APIGroup).To(Equal(ptr.To("snapshot.storage.k8s.io")))
+	})
+
+	It("returns pg_basebackup bootstrap from the external source cluster", func() {
+		documentdb := &dbpreview.DocumentDB{
+			Spec: dbpreview.DocumentDBSpec{
+				Bootstrap: &dbpreview.BootstrapConfiguration{
+					PgBaseBackup: &dbpreview.PgBaseBackupConfiguration{SourceService: "blue-rw"},
+				},
+			},
+		}
+
+		result := getBootstrapConfiguration(documentdb, true, log)
+		Expect(result.PgBaseBackup).ToNot(BeNil())
+		Expect(result.PgBaseBackup.Source).To(Equal(pgBaseBackupSourceName))
+		Expect(result.InitDB).To(BeNil())
+
+		externalClusters := getExternalClusters(documentdb, true)
+		Expect(externalClusters).To(HaveLen(1))
+		Expect(externalClusters[0].Name).To(Equal(pgBaseBackupSourceName))
+		Expect(externalClusters[0].ConnectionParameters).To(HaveKeyWithValue("host", "blue-rw"))
+		Expect(externalClusters[0].ConnectionParameters).To(HaveKeyWithValue("user", "streaming_replica"))
+		Expect(externalClusters[0].SSLCert.Name).To(Equal("blue-replication"))
+		Expect(externalClusters[0].SSLRootCert.Name).To(Equal("blue-ca"))
+
+		Expect(getExternalClusters(documentdb, false)).To(BeNil())
+	})
+
+	It("uses the given certificates for a pg_basebackup source in another namespace", func() {
+		documentdb := &dbpreview.DocumentDB{
+			Spec: dbpreview.DocumentDBSpec{
+				Bootstrap: &dbpreview.BootstrapConfiguration{
+					PgBaseBackup: &dbpreview.PgBaseBackupConfiguration{
+						SourceService:        "blue-rw.prod.svc",
+						ReplicationTLSSecret: "blue-streaming-replica",
+						ServerCASecret:       "blue-server-ca",
+					},
+				},
+			},
+		}
+
+		externalClusters := getExternalClusters(documentdb, true)
+		Expect(externalClusters[0].ConnectionParameters).To(HaveKeyWithValue("host", "blue-rw.prod.svc"))
+		Expect(externalClusters[0].SSLKey.Name).To(Equal("blue-streaming-replica"))
+		Expect(externalClusters[0].SSLRootCert.Name).To(Equal("blue-server-ca"))
+	})
+
+	It("returns recovery bootstrap from an object store archive", func() {
+		documentdb := &dbpreview.DocumentDB{
+			Spec: dbpreview.DocumentDBSpec{
+				Bootstrap: &dbpreview.BootstrapConfiguration{
+					Recovery: &dbpreview.RecoveryConfiguration{
+						ObjectStore: &dbpreview.ObjectStoreRecoveryConfiguration{
+							DestinationPath:   "s3://backups/documentdb",
+							ServerName:        "orders",
+							EndpointURL:       "http://minio:9000",
+							CredentialsSecret: &cnpgv1.LocalObjectReference{Name: "s3-credentials"},
+						},
+					},
+				},
+			},
+		}
+
+		result := getBootstrapConfiguration(documentdb, true, log)
+		Expect(result.Recovery).ToNot(BeNil())
+		Expect(result.Recovery.Source).To(Equal(objectStoreSourceName))
+		Expect(result.Recovery.Backup).To(BeNil())
+
+		externalClusters := getExternalClusters(documentdb, true)
+		Expect(externalClusters).To(HaveLen(1))
+		Expect(externalClusters[0].Name).To(Equal(objectStoreSourceName))
+		objectStore := externalClusters[0].BarmanObjectStore
+		Expect(objectStore.DestinationPath).To(Equal("s3://backups/documentdb"))
+		Expect(objectStore.ServerName).To(Equal("orders"))
+		Expect(objectStore.EndpointURL).To(Equal("http://minio:9000"))
+		Expect(objectStore.AWS.AccessKeyIDReference.Name).To(Equal("s3-credentials"))
+		Expect(objectStore.AWS.AccessKeyIDReference.Key).To(Equal("AWS_ACCESS_KEY_ID"))
+		Expect(objectStore.AWS.SecretAccessKeyReference.Key).To(Equal("AWS_SECRET_ACCESS_KEY"))
+	})
+
+	It("passes the recovery target to CNPG", func() {
+		target := &cnpgv1.RecoveryTarget{TargetName: "before-migration", Exclusive: ptr.To(true)}
+		documentdb := &dbpreview.DocumentDB{
+			Spec: dbpreview.DocumentDBSpec{
+				Bootstrap: &dbpreview.BootstrapConfiguration{
+					Recovery: &dbpreview.RecoveryConfiguration{
+						Backup:         cnpgv1.LocalObjectReference{Name: "nightly"},
+						RecoveryTarget: target,
+					},
+				},
+			},
+		}
+		Expect(getBootstrapConfiguration(documentdb, true, log).Recovery.RecoveryTarget).To(Equal(target))
+
+		documentdb.Spec.Bootstrap.Recovery.Backup = cnpgv1.LocalObjectReference{}
+		documentdb.Spec.Bootstrap.Recovery.ObjectStore = &dbpreview.ObjectStoreRecoveryConfiguration{DestinationPath: "s3://backups", ServerName: "orders"}
+		result := getBootstrapConfiguration(documentdb, true, log)
+		Expect(result.Recovery.Source).To(Equal(objectStoreSourceName))
+		Expect(result.Recovery.RecoveryTarget).To(Equal(target))
+	})
+
+	It("reads an object store archive with the identity of the pods without a credentials Secret", func() {
+		objectStore := &dbpreview.ObjectStoreRecoveryConfiguration{DestinationPath: "s3://backups", ServerName: "orders"}
+		Expect(getObjectStoreCredentials(objectStore, nil).AWS.InheritFromIAMRole).To(BeTrue())
+
+		objectStore.DestinationPath = "gs://backups"
+		Expect(getObjectStoreCredentials(objectStore, nil).Google.GKEEnvironment).To(BeTrue())
+
+		objectStore.DestinationPath = "https://account.blob.core.windows.net/backups"
+		Expect(getObjectStoreCredentials(objectStore, nil).Azure.InheritFromAzureAD).To(BeTrue())
+
+		objectStore.CredentialsSecret = &cnpgv1.LocalObjectReference{Name: "azure-credentials"}
+		Expect(getObjectStoreCredentials(objectStore, nil).Azure.ConnectionString.Key).To(Equal("AZURE_STORAGE_CONNECTION_STRING"))
+	})
+
+	It("reads an Azure object store with the default credentials of an Azure workload identity", func() {
+		objectStore := &dbpreview.ObjectStoreRecoveryConfiguration{
+			DestinationPath: "https://account.blob.core.windows.net/backups",
+			ServerName:      "orders",
+		}
+		identity := &dbpreview.WorkloadIdentitySpec{Provider: dbpreview.WorkloadIdentityAzure, Identity: "00000000-1111-2222-3333-444444444444"}
+		credentials := getObjectStoreCredentials(objectStore, identity)
+		Expect(credentials.Azure.UseDefaultAzureCredentials).To(BeTrue())
+		Expect(credentials.Azure.InheritFromAzureAD).To(BeFalse())
+	})
+
+	It("adds the source of spec.migration as an external cluster", func() {
+		documentdb := &dbpreview.DocumentDB{
+			Spec: dbpreview.DocumentDBSpec{
+				Migration: &dbpreview.MigrationConfiguration{SourceRef: cnpgv1.LocalObjectReference{Name: "blue"}},
+			},
+		}
+
+		externalClusters := getExternalClusters(documentdb, true)
+		Expect(externalClusters).To(HaveLen(1))
+		Expect(externalClusters[0].Name).To(Equal(MigrationSourceName))
+		Expect(externalClusters[0].ConnectionParameters).To(HaveKeyWithValue("host", "blue-rw"))
+		Expect(externalClusters[0].SSLCert.Name).To(Equal("blue-replication"))
+		Expect(externalClusters[0].SSLRootCert.Name).To(Equal("blue-ca"))
+	})
+
 	It("returns default bootstrap when backup name is empty", func() {
 		documentdb := &dbpreview.DocumentDB{
 			Spec: dbpreview.DocumentDBSpec{
@@ -157,6 +329,23 @@ var _ = Describe("getBootstrapConfiguration", func() {
 		Expect(result.InitDB).ToNot(BeNil())
 		Expect(result.Recovery).To(BeNil())
 	})
+
+	It("recovers from the adopted PV through the temp PVC", func() {
+		documentdb := &dbpreview.DocumentDB{
+			ObjectMeta: metav1.ObjectMeta{Name: "my-documentdb"},
+			Spec: dbpreview.DocumentDBSpec{
+				Bootstrap: &dbpreview.BootstrapConfiguration{AdoptRetainedVolumes: true},
+			},
+			Status: dbpreview.DocumentDBStatus{AdoptedVolume: "retained-pv"},
+		}
+
+		result := getBootstrapConfiguration(documentdb, true, log)
+		Expect(result.Recovery).ToNot(BeNil())
+		Expect(result.Recovery.VolumeSnapshots.Storage.Name).To(Equal("my-documentdb-pv-recovery-temp"))
+
+		documentdb.Status.AdoptedVolume = ""
+		Expect(getBootstrapConfiguration(documentdb, true, log).InitDB).ToNot(BeNil())
+	})
 })
 
 var _ = Describe("getDefaultBootstrapConfiguration", func() {
@@ -390,6 +579,38 @@ var _ = Describe("GetCnpgClusterSpec", func() {
 		Expect(*result.Spec.StorageConfiguration.StorageClass).To(Equal("premium-storage"))
 	})
 
+	It("places the WAL on its own volume in the data storage class by default", func() {
+		req := ctrl.Request{}
+		req.Name = "test-cluster"
+		req.Namespace = "default"
+
+		documentdb := &dbpreview.DocumentDB{
+			Spec: dbpreview.DocumentDBSpec{
+				InstancesPerNode: 1,
+				Resource: dbpreview.Resource{
+					Storage: dbpreview.StorageConfiguration{
+						PvcSize: "10Gi",
+						WAL:     &dbpreview.WALStorageConfiguration{PvcSize: "5Gi"},
+					},
+				},
+			},
+		}
+
+		result := GetCnpgClusterSpec(req, documentdb, "postgres:16", "test-sa", "premium-storage", true, log)
+		Expect(result.Spec.WalStorage).To(Equal(&cnpgv1.StorageConfiguration{
+			StorageClass: ptr.To("premium-storage"),
+			Size:         "5Gi",
+		}))
+
+		documentdb.Spec.Resource.Storage.WAL.StorageClass = "fast-storage"
+		result = GetCnpgClusterSpec(req, documentdb, "postgres:16", "test-sa", "premium-storage", true, log)
+		Expect(*result.Spec.WalStorage.StorageClass).To(Equal("fast-storage"))
+
+		documentdb.Spec.Resource.Storage.WAL = nil
+		result = GetCnpgClusterSpec(req, documentdb, "postgres:16", "test-sa", "premium-storage", true, log)
+		Expect(result.Spec.WalStorage).To(BeNil())
+	})
+
 	It("uses nil storage class when empty string is provided", func() {
 		req := ctrl.Request{}
 		req.Name = "test-cluster"
@@ -596,6 +817,485 @@ var _ = Describe("GetCnpgClusterSpec", func() {
 		Expect(result.Spec.PostgresConfiguration.Extensions[0].ImageVolumeSource.PullPolicy).To(BeEmpty())
 	})
 
+	It("applies spec.imagePullPolicy to every image over the operator settings", func() {
+		req := ctrl.Request{}
+		req.Name = "test-cluster"
+		req.Namespace = "default"
+
+		documentdb := &dbpreview.DocumentDB{
+			Spec: dbpreview.DocumentDBSpec{
+				InstancesPerNode: 1,
+				ImagePullPolicy:  corev1.PullIfNotPresent,
+				Resource: dbpreview.Resource{
+					Storage: dbpreview.StorageConfiguration{PvcSize: "10Gi"},
+				},
+				Monitoring: &dbpreview.MonitoringSpec{Enabled: true},
+			},
+		}
+
+		GinkgoT().Setenv(util.GATEWAY_IMAGE_PULL_POLICY_ENV, "Always")
+		GinkgoT().Setenv(util.DOCUMENTDB_IMAGE_PULL_POLICY_ENV, "Always")
+		result := GetCnpgClusterSpec(req, documentdb, "ext:1.0", "test-sa", "", true, log)
+		Expect(result.Spec.ImagePullPolicy).To(Equal(corev1.PullIfNotPresent))
+		Expect(result.Spec.PostgresConfiguration.Extensions[0].ImageVolumeSource.PullPolicy).To(Equal(corev1.PullIfNotPresent))
+		Expect(result.Spec.Plugins[0].Parameters).To(HaveKeyWithValue(util.PLUGIN_PARAM_GATEWAY_IMAGE_PULL_POLICY, "IfNotPresent"))
+		Expect(result.Spec.Plugins[0].Parameters).To(HaveKeyWithValue(util.PLUGIN_PARAM_OTEL_IMAGE_PULL_POLICY, "IfNotPresent"))
+	})
+
+	It("stamps the cost labels onto the cluster and its inherited metadata", func() {
+		req := ctrl.Request{}
+		req.Name = "test-cluster"
+		req.Namespace = "default"
+
+		documentdb := &dbpreview.DocumentDB{
+			ObjectMeta: metav1.ObjectMeta{Name: "test-cluster", Namespace: "default"},
+			Spec: dbpreview.DocumentDBSpec{
+				InstancesPerNode: 1,
+				Resource: dbpreview.Resource{
+					Storage: dbpreview.StorageConfiguration{PvcSize: "10Gi"},
+				},
+				CostLabels: map[string]string{"team": "payments", util.LABEL_APP: "ignored"},
+			},
+		}
+
+		result := GetCnpgClusterSpec(req, documentdb, "documentdb-oss:1.0", "test-sa", "", true, log)
+		Expect(result.Labels).To(Equal(map[string]string{
+			"team":                     "payments",
+			util.LABEL_APP:             "ignored",
+			util.LABEL_DOCUMENTDB_NAME: "test-cluster",
+		}))
+		Expect(result.Spec.InheritedMetadata.Labels).To(HaveKeyWithValue("team", "payments"))
+		Expect(result.Spec.InheritedMetadata.Labels).To(HaveKeyWithValue(util.LABEL_APP, "test-cluster"))
+		Expect(result.Spec.InheritedMetadata.Labels).To(HaveKeyWithValue(util.LABEL_REPLICA_TYPE, "primary"))
+	})
+
+	It("merges the user-defined inherited metadata below the operator labels", func() {
+		req := ctrl.Request{}
+		req.Name = "test-cluster"
+		req.Namespace = "default"
+
+		documentdb := &dbpreview.DocumentDB{
+			ObjectMeta: metav1.ObjectMeta{Name: "test-cluster", Namespace: "default"},
+			Spec: dbpreview.DocumentDBSpec{
+				InstancesPerNode: 1,
+				Resource: dbpreview.Resource{
+					Storage: dbpreview.StorageConfiguration{PvcSize: "10Gi"},
+				},
+				CostLabels: map[string]string{"team": "payments"},
+				InheritedMetadata: &dbpreview.InheritedMetadata{
+					Labels:      map[string]string{"team": "ignored", "tier": "gold", util.LABEL_APP: "ignored"},
+					Annotations: map[string]string{"sidecar.istio.io/inject": "true"},
+				},
+			},
+		}
+
+		result := GetCnpgClusterSpec(req, documentdb, "documentdb-oss:1.0", "test-sa", "", true, log)
+		Expect(result.Spec.InheritedMetadata.Labels).To(Equal(map[string]string{
+			"team":                  "payments",
+			"tier":                  "gold",
+			util.LABEL_APP:          "test-cluster",
+			util.LABEL_REPLICA_TYPE: "primary",
+		}))
+		Expect(result.Spec.InheritedMetadata.Annotations).To(Equal(map[string]string{"sidecar.istio.io/inject": "true"}))
+	})
+
+	It("adds the Velero pre-backup hook to the pod annotations", func() {
+		req := ctrl.Request{}
+		req.Name = "test-cluster"
+		req.Namespace = "default"
+
+		documentdb := &dbpreview.DocumentDB{
+			Spec: dbpreview.DocumentDBSpec{
+				InstancesPerNode: 1,
+				Resource: dbpreview.Resource{
+					Storage: dbpreview.StorageConfiguration{PvcSize: "10Gi"},
+				},
+				Backup: &dbpreview.BackupConfiguration{
+					Velero: &dbpreview.VeleroConfiguration{HookTimeoutSeconds: 120},
+				},
+				InheritedMetadata: &dbpreview.InheritedMetadata{
+					Annotations: map[string]string{"sidecar.istio.io/inject": "true"},
+				},
+			},
+		}
+
+		result := GetCnpgClusterSpec(req, documentdb, "documentdb-oss:1.0", "test-sa", "", true, log)
+		Expect(result.Spec.InheritedMetadata.Annotations).To(Equal(map[string]string{
+			"sidecar.istio.io/inject":             "true",
+			"pre.hook.backup.velero.io/container": "postgres",
+			"pre.hook.backup.velero.io/command":   `["psql","-U","postgres","-d","postgres","-c","CHECKPOINT"]`,
+			"pre.hook.backup.velero.io/on-error":  "Fail",
+			"pre.hook.backup.velero.io/timeout":   "120s",
+		}))
+	})
+
+	It("adds the Istio annotations to the pod annotations", func() {
+		req := ctrl.Request{}
+		req.Name = "test-cluster"
+		req.Namespace = "default"
+
+		documentdb := &dbpreview.DocumentDB{
+			Spec: dbpreview.DocumentDBSpec{
+				InstancesPerNode: 1,
+				Resource: dbpreview.Resource{
+					Storage: dbpreview.StorageConfiguration{PvcSize: "10Gi"},
+				},
+				Istio: &dbpreview.IstioConfiguration{},
+			},
+		}
+
+		result := GetCnpgClusterSpec(req, documentdb, "documentdb-oss:1.0", "test-sa", "", true, log)
+		Expect(result.Spec.InheritedMetadata.Annotations).To(Equal(map[string]string{
+			"traffic.sidecar.istio.io/excludeInboundPorts":  "5432,8000",
+			"traffic.sidecar.istio.io/excludeOutboundPorts": "5432",
+			"proxy.istio.io/config":                         `{"holdApplicationUntilProxyStarts": true}`,
+		}))
+	})
+
+	It("customizes the ServiceAccount from spec.serviceAccount", func() {
+		req := ctrl.Request{}
+		req.Name = "test-cluster"
+		req.Namespace = "default"
+
+		documentdb := &dbpreview.DocumentDB{
+			ObjectMeta: metav1.ObjectMeta{Name: "test-cluster", Namespace: "default"},
+			Spec: dbpreview.DocumentDBSpec{
+				InstancesPerNode: 1,
+				Resource: dbpreview.Resource{
+					Storage: dbpreview.StorageConfiguration{PvcSize: "10Gi"},
+				},
+				ImagePullSecrets: []corev1.LocalObjectReference{{Name: "registry"}},
+				ServiceAccount: &dbpreview.ServiceAccountSpec{
+					Annotations:      map[string]string{"eks.amazonaws.com/role-arn": "arn:aws:iam::123456789012:role/documentdb"},
+					ImagePullSecrets: []corev1.LocalObjectReference{{Name: "registry"}, {Name: "mirror"}},
+				},
+			},
+		}
+
+		result := GetCnpgClusterSpec(req, documentdb, "documentdb-oss:1.0", "test-sa", "", true, log)
+		Expect(result.Spec.ServiceAccountTemplate).ToNot(BeNil())
+		Expect(result.Spec.ServiceAccountTemplate.Metadata.Annotations).To(HaveKeyWithValue(
+			"eks.amazonaws.com/role-arn", "arn:aws:iam::123456789012:role/documentdb"))
+		Expect(result.Spec.ImagePullSecrets).To(Equal([]cnpgv1.LocalObjectReference{{Name: "registry"}, {Name: "mirror"}}))
+	})
+
+	It("binds the ServiceAccount and the pods to an Azure workload identity", func() {
+		req := ctrl.Request{}
+		req.Name = "test-cluster"
+		req.Namespace = "default"
+
+		documentdb := &dbpreview.DocumentDB{
+			ObjectMeta: metav1.ObjectMeta{Name: "test-cluster", Namespace: "default"},
+			Spec: dbpreview.DocumentDBSpec{
+				InstancesPerNode: 1,
+				Resource: dbpreview.Resource{
+					Storage: dbpreview.StorageConfiguration{PvcSize: "10Gi"},
+				},
+				ServiceAccount: &dbpreview.ServiceAccountSpec{
+					WorkloadIdentity: &dbpreview.WorkloadIdentitySpec{
+						Provider: dbpreview.WorkloadIdentityAzure,
+						Identity: "00000000-1111-2222-3333-444444444444",
+					},
+				},
+			},
+		}
+
+		result := GetCnpgClusterSpec(req, documentdb, "documentdb-oss:1.0", "test-sa", "", true, log)
+		Expect(result.Spec.ServiceAccountTemplate.Metadata.Annotations).To(Equal(map[string]string{
+			"azure.workload.identity/client-id": "00000000-1111-2222-3333-444444444444",
+		}))
+		Expect(result.Spec.InheritedMetadata.Labels).To(HaveKeyWithValue("azure.workload.identity/use", "true"))
+	})
+
+	It("applies spec.securityContext to the instance pods and the gateway", func() {
+		req := ctrl.Request{}
+		req.Name = "test-cluster"
+		req.Namespace = "default"
+
+		documentdb := &dbpreview.DocumentDB{
+			Spec: dbpreview.DocumentDBSpec{
+				InstancesPerNode: 1,
+				Resource: dbpreview.Resource{
+					Storage: dbpreview.StorageConfiguration{PvcSize: "10Gi"},
+				},
+				FeatureGates: map[string]bool{dbpreview.FeatureGateIOUring: true},
+				SecurityContext: &dbpreview.SecurityContextSpec{
+					RunAsUser:              ptr.To(int64(1000700000)),
+					RunAsGroup:             ptr.To(int64(1000700000)),
+					SeccompProfile:         &corev1.SeccompProfile{Type: corev1.SeccompProfileTypeRuntimeDefault},
+					ReadOnlyRootFilesystem: ptr.To(true),
+				},
+			},
+		}
+
+		result := GetCnpgClusterSpec(req, documentdb, "documentdb-oss:1.0", "test-sa", "", true, log)
+		Expect(result.Spec.SeccompProfile).To(Equal(&corev1.SeccompProfile{Type: corev1.SeccompProfileTypeRuntimeDefault}))
+		Expect(result.Spec.PodSecurityContext).To(Equal(&corev1.PodSecurityContext{
+			RunAsUser:    ptr.To(int64(1000700000)),
+			RunAsGroup:   ptr.To(int64(1000700000)),
+			FSGroup:      ptr.To(int64(1000700000)),
+			RunAsNonRoot: ptr.To(true),
+		}))
+		Expect(result.Spec.SecurityContext).To(Equal(&corev1.SecurityContext{ReadOnlyRootFilesystem: ptr.To(true)}))
+		Expect(result.Spec.Plugins[0].Parameters).To(HaveKeyWithValue(util.PLUGIN_PARAM_GATEWAY_SECURITY_CONTEXT,
+			`{"runAsUser":1000700000,"runAsGroup":1000700000,"readOnlyRootFilesystem":true,"seccompProfile":{"type":"RuntimeDefault"}}`))
+	})
+
+	It("defaults fsGroup to the postgres GID when only the UID is overridden", func() {
+		req := ctrl.Request{}
+		req.Name = "test-cluster"
+		req.Namespace = "default"
+
+		documentdb := &dbpreview.DocumentDB{
+			Spec: dbpreview.DocumentDBSpec{
+				InstancesPerNode: 1,
+				Resource: dbpreview.Resource{
+					Storage: dbpreview.StorageConfiguration{PvcSize: "10Gi"},
+				},
+				SecurityContext: &dbpreview.SecurityContextSpec{RunAsUser: ptr.To(int64(2000))},
+			},
+		}
+
+		result := GetCnpgClusterSpec(req, documentdb, "documentdb-oss:1.0", "test-sa", "", true, log)
+		Expect(result.Spec.PodSecurityContext.FSGroup).To(Equal(ptr.To(int64(cnpgv1.DefaultPostgresGID))))
+		Expect(result.Spec.SecurityContext).To(BeNil())
+	})
+
+	It("leaves the security contexts to CNPG by default", func() {
+		req := ctrl.Request{}
+		req.Name = "test-cluster"
+		req.Namespace = "default"
+
+		documentdb := &dbpreview.DocumentDB{
+			Spec: dbpreview.DocumentDBSpec{
+				InstancesPerNode: 1,
+				Resource: dbpreview.Resource{
+					Storage: dbpreview.StorageConfiguration{PvcSize: "10Gi"},
+				},
+			},
+		}
+
+		result := GetCnpgClusterSpec(req, documentdb, "documentdb-oss:1.0", "test-sa", "", true, log)
+		Expect(result.Spec.PodSecurityContext).To(BeNil())
+		Expect(result.Spec.SecurityContext).To(BeNil())
+		Expect(result.Spec.Plugins[0].Parameters).ToNot(HaveKey(util.PLUGIN_PARAM_GATEWAY_SECURITY_CONTEXT))
+		Expect(result.Spec.Plugins[0].Parameters).ToNot(HaveKey(util.PLUGIN_PARAM_GATEWAY_PROBES))
+		Expect(result.Spec.Probes).To(BeNil())
+	})
+
+	It("passes a gateway port other than the default to the plugin", func() {
+		req := ctrl.Request{}
+		req.Name = "test-cluster"
+		req.Namespace = "default"
+
+		documentdb := &dbpreview.DocumentDB{
+			Spec: dbpreview.DocumentDBSpec{
+				InstancesPerNode: 1,
+				Resource: dbpreview.Resource{
+					Storage: dbpreview.StorageConfiguration{PvcSize: "10Gi"},
+				},
+			},
+		}
+		result := GetCnpgClusterSpec(req, documentdb, "documentdb-oss:1.0", "test-sa", "", true, log)
+		Expect(result.Spec.Plugins[0].Parameters).ToNot(HaveKey(util.PLUGIN_PARAM_GATEWAY_PORT))
+
+		documentdb.Spec.Gateway = &dbpreview.GatewaySpec{Port: 27017}
+		result = GetCnpgClusterSpec(req, documentdb, "documentdb-oss:1.0", "test-sa", "", true, log)
+		Expect(result.Spec.Plugins[0].Parameters).To(HaveKeyWithValue(util.PLUGIN_PARAM_GATEWAY_PORT, "27017"))
+	})
+
+	It("passes the PostgreSQL and gateway probe overrides through", func() {
+		req := ctrl.Request{}
+		req.Name = "test-cluster"
+		req.Namespace = "default"
+
+		documentdb := &dbpreview.DocumentDB{
+			Spec: dbpreview.DocumentDBSpec{
+				InstancesPerNode: 1,
+				Resource: dbpreview.Resource{
+					Storage: dbpreview.StorageConfiguration{PvcSize: "10Gi"},
+				},
+				Postgres: &dbpreview.PostgresSpec{Probes: &cnpgv1.ProbesConfiguration{
+					Liveness: &cnpgv1.LivenessProbe{Probe: cnpgv1.Probe{TimeoutSeconds: 10, FailureThreshold: 6}},
+				}},
+				Gateway: &dbpreview.GatewaySpec{Probes: &dbpreview.GatewayProbes{
+					Readiness: &cnpgv1.Probe{PeriodSeconds: 30},
+				}},
+			},
+		}
+
+		result := GetCnpgClusterSpec(req, documentdb, "documentdb-oss:1.0", "test-sa", "", true, log)
+		Expect(result.Spec.Probes).To(Equal(documentdb.Spec.Postgres.Probes))
+		Expect(result.Spec.Probes).ToNot(BeIdenticalTo(documentdb.Spec.Postgres.Probes))
+		Expect(result.Spec.Plugins[0].Parameters).To(HaveKeyWithValue(util.PLUGIN_PARAM_GATEWAY_PROBES,
+			`{"readiness":{"periodSeconds":30}}`))
+	})
+
+	It("appends the additional plugins after the sidecar injector", func() {
+		req := ctrl.Request{}
+		req.Name = "test-cluster"
+		req.Namespace = "default"
+
+		documentdb := &dbpreview.DocumentDB{
+			Spec: dbpreview.DocumentDBSpec{
+				InstancesPerNode: 1,
+				Resource: dbpreview.Resource{
+					Storage: dbpreview.StorageConfiguration{PvcSize: "10Gi"},
+				},
+				Plugins: &dbpreview.PluginsSpec{Additional: []cnpgv1.PluginConfiguration{{
+					Name:          "barman-cloud.cloudnative-pg.io",
+					IsWALArchiver: ptr.To(true),
+					Parameters:    map[string]string{"barmanObjectName": "store"},
+				}}},
+			},
+		}
+
+		result := GetCnpgClusterSpec(req, documentdb, "documentdb-oss:1.0", "test-sa", "", true, log)
+		Expect(result.Spec.Plugins).To(HaveLen(2))
+		Expect(result.Spec.Plugins[0].Name).To(Equal(util.DEFAULT_SIDECAR_INJECTOR_PLUGIN))
+		Expect(result.Spec.Plugins[1]).To(Equal(cnpgv1.PluginConfiguration{
+			Name:          "barman-cloud.cloudnative-pg.io",
+			Enabled:       ptr.To(true),
+			IsWALArchiver: ptr.To(true),
+			Parameters:    map[string]string{"barmanObjectName": "store"},
+		}))
+		Expect(documentdb.Spec.Plugins.Additional[0].Enabled).To(BeNil())
+	})
+
+	It("disables superuser access unless spec.postgres enables it", func() {
+		req := ctrl.Request{}
+		req.Name = "test-cluster"
+		req.Namespace = "default"
+
+		documentdb := &dbpreview.DocumentDB{
+			Spec: dbpreview.DocumentDBSpec{
+				InstancesPerNode: 1,
+				Resource: dbpreview.Resource{
+					Storage: dbpreview.StorageConfiguration{PvcSize: "10Gi"},
+				},
+			},
+		}
+
+		result := GetCnpgClusterSpec(req, documentdb, "documentdb-oss:1.0", "test-sa", "", true, log)
+		Expect(result.Spec.EnableSuperuserAccess).To(Equal(ptr.To(false)))
+		Expect(result.Spec.SuperuserSecret).To(BeNil())
+
+		documentdb.Spec.Postgres = &dbpreview.PostgresSpec{
+			EnableSuperuserAccess: ptr.To(true),
+			SuperuserSecret:       &cnpgv1.LocalObjectReference{Name: "my-superuser"},
+		}
+		result = GetCnpgClusterSpec(req, documentdb, "documentdb-oss:1.0", "test-sa", "", true, log)
+		Expect(result.Spec.EnableSuperuserAccess).To(Equal(ptr.To(true)))
+		Expect(result.Spec.SuperuserSecret).To(Equal(&cnpgv1.LocalObjectReference{Name: "my-superuser"}))
+	})
+
+	It("updates the primary unsupervised with a switchover unless configured otherwise", func() {
+		req := ctrl.Request{}
+		req.Name = "test-cluster"
+		req.Namespace = "default"
+
+		documentdb := &dbpreview.DocumentDB{
+			Spec: dbpreview.DocumentDBSpec{
+				InstancesPerNode: 3,
+				Resource: dbpreview.Resource{
+					Storage: dbpreview.StorageConfiguration{PvcSize: "10Gi"},
+				},
+			},
+		}
+
+		result := GetCnpgClusterSpec(req, documentdb, "documentdb-oss:1.0", "test-sa", "", true, log)
+		Expect(result.Spec.PrimaryUpdateStrategy).To(Equal(cnpgv1.PrimaryUpdateStrategyUnsupervised))
+		Expect(result.Spec.PrimaryUpdateMethod).To(Equal(cnpgv1.PrimaryUpdateMethodSwitchover))
+
+		documentdb.Spec.PrimaryUpdateStrategy = cnpgv1.PrimaryUpdateStrategySupervised
+		documentdb.Spec.PrimaryUpdateMethod = cnpgv1.PrimaryUpdateMethodRestart
+		result = GetCnpgClusterSpec(req, documentdb, "documentdb-oss:1.0", "test-sa", "", true, log)
+		Expect(result.Spec.PrimaryUpdateStrategy).To(Equal(cnpgv1.PrimaryUpdateStrategySupervised))
+		Expect(result.Spec.PrimaryUpdateMethod).To(Equal(cnpgv1.PrimaryUpdateMethodRestart))
+	})
+
+	It("passes the node maintenance window through with reusePVC defaulted", func() {
+		req := ctrl.Request{}
+		req.Name = "test-cluster"
+		req.Namespace = "default"
+
+		documentdb := &dbpreview.DocumentDB{
+			Spec: dbpreview.DocumentDBSpec{
+				InstancesPerNode: 1,
+				Resource: dbpreview.Resource{
+					Storage: dbpreview.StorageConfiguration{PvcSize: "10Gi"},
+				},
+			},
+		}
+
+		result := GetCnpgClusterSpec(req, documentdb, "documentdb-oss:1.0", "test-sa", "", true, log)
+		Expect(result.Spec.NodeMaintenanceWindow).To(BeNil())
+
+		documentdb.Spec.NodeMaintenanceWindow = &cnpgv1.NodeMaintenanceWindow{InProgress: true}
+		result = GetCnpgClusterSpec(req, documentdb, "documentdb-oss:1.0", "test-sa", "", true, log)
+		Expect(result.Spec.NodeMaintenanceWindow).To(Equal(&cnpgv1.NodeMaintenanceWindow{InProgress: true, ReusePVC: ptr.To(true)}))
+		Expect(documentdb.Spec.NodeMaintenanceWindow.ReusePVC).To(BeNil())
+	})
+
+	It("passes additional services through with CNPG defaults applied", func() {
+		req := ctrl.Request{}
+		req.Name = "test-cluster"
+		req.Namespace = "default"
+
+		documentdb := &dbpreview.DocumentDB{
+			Spec: dbpreview.DocumentDBSpec{
+				InstancesPerNode: 1,
+				Resource: dbpreview.Resource{
+					Storage: dbpreview.StorageConfiguration{PvcSize: "10Gi"},
+				},
+			},
+		}
+
+		result := GetCnpgClusterSpec(req, documentdb, "documentdb-oss:1.0", "test-sa", "", true, log)
+		Expect(result.Spec.Managed).To(BeNil())
+
+		documentdb.Spec.AdditionalServices = []cnpgv1.ManagedService{{
+			SelectorType: cnpgv1.ServiceSelectorTypeRO,
+			ServiceTemplate: cnpgv1.ServiceTemplateSpec{
+				ObjectMeta: cnpgv1.Metadata{Name: "test-cluster-reads"},
+				Spec: corev1.ServiceSpec{
+					Ports: []corev1.ServicePort{{Name: "gateway", Port: 10260}},
+				},
+			},
+		}}
+		result = GetCnpgClusterSpec(req, documentdb, "documentdb-oss:1.0", "test-sa", "", true, log)
+		Expect(result.Spec.Managed).NotTo(BeNil())
+		Expect(result.Spec.Managed.Services).NotTo(BeNil())
+		services := result.Spec.Managed.Services.Additional
+		Expect(services).To(HaveLen(1))
+		Expect(services[0].SelectorType).To(Equal(cnpgv1.ServiceSelectorTypeRO))
+		Expect(services[0].UpdateStrategy).To(Equal(cnpgv1.ServiceUpdateStrategy(cnpgv1.ServiceUpdateStrategyPatch)))
+		Expect(services[0].ServiceTemplate.ObjectMeta.Name).To(Equal("test-cluster-reads"))
+		Expect(services[0].ServiceTemplate.Spec.Ports[0].Protocol).To(Equal(corev1.ProtocolTCP))
+		Expect(documentdb.Spec.AdditionalServices[0].UpdateStrategy).To(BeEmpty())
+		Expect(documentdb.Spec.AdditionalServices[0].ServiceTemplate.Spec.Ports[0].Protocol).To(BeEmpty())
+	})
+
+	It("leaves the ServiceAccount template unset by default", func() {
+		req := ctrl.Request{}
+		req.Name = "test-cluster"
+		req.Namespace = "default"
+
+		documentdb := &dbpreview.DocumentDB{
+			Spec: dbpreview.DocumentDBSpec{
+				InstancesPerNode: 1,
+				Resource: dbpreview.Resource{
+					Storage: dbpreview.StorageConfiguration{PvcSize: "10Gi"},
+				},
+				ServiceAccount: &dbpreview.ServiceAccountSpec{},
+			},
+		}
+
+		result := GetCnpgClusterSpec(req, documentdb, "documentdb-oss:1.0", "test-sa", "", true, log)
+		Expect(result.Spec.ServiceAccountTemplate).To(BeNil())
+		Expect(result.Spec.ImagePullSecrets).To(BeNil())
+	})
+
 	Context("wal_level parameter", func() {
 		It("does not include wal_level when featureGates is nil", func() {
 			req := ctrl.Request{}
@@ -959,6 +1659,33 @@ var _ = Describe("GetCnpgClusterSpec", func() {
 		Expect(cluster.Spec.PostgresUID).To(Equal(int64(1001)))
 		Expect(cluster.Spec.PostgresGID).To(Equal(int64(1002)))
 	})
+
+	It("leaves the process identity and seccomp profile to OpenShift in OpenShift mode", func() {
+		GinkgoT().Setenv(util.OPENSHIFT_ENV, "true")
+		req := ctrl.Request{}
+		req.Name = "test-cluster"
+		req.Namespace = "default"
+		documentdb := &dbpreview.DocumentDB{
+			Spec: dbpreview.DocumentDBSpec{
+				InstancesPerNode: 1,
+				Postgres: &dbpreview.PostgresSpec{
+					UID: ptr.To(int64(1001)),
+					GID: ptr.To(int64(1002)),
+				},
+				FeatureGates: map[string]bool{dbpreview.FeatureGateIOUring: true},
+				Resource: dbpreview.Resource{
+					Storage: dbpreview.StorageConfiguration{PvcSize: "10Gi"},
+				},
+			},
+		}
+
+		cluster := GetCnpgClusterSpec(req, documentdb, "documentdb-oss:1.0", "test-sa", "", true, log)
+		Expect(cluster.Spec.PostgresUID).To(BeZero())
+		Expect(cluster.Spec.PostgresGID).To(BeZero())
+		Expect(cluster.Spec.SeccompProfile).To(BeNil())
+		Expect(cluster.Spec.Plugins[0].Parameters).To(
+			HaveKeyWithValue(util.PLUGIN_PARAM_GATEWAY_RUN_AS_NAMESPACE_UID, "true"))
+	})
 })
 
 // Standard Go tests for additional coverage
@@ -1018,6 +1745,67 @@ func TestGetInheritedMetadataLabels(t *testing.T) {
 	}
 }
 
+func TestGetMaxStartDelayOrDefault(t *testing.T) {
+	tests := []struct {
+		name       string
+		startDelay int32
+		expected   int32
+	}{
+		{name: "returns the CNPG default when StartDelay is 0", startDelay: 0, expected: util.CNPG_DEFAULT_START_DELAY},
+		{name: "returns custom StartDelay when set", startDelay: 14400, expected: 14400},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			documentdb := &dbpreview.DocumentDB{Spec: dbpreview.DocumentDBSpec{Timeouts: dbpreview.Timeouts{StartDelay: tt.startDelay}}}
+			if result := getMaxStartDelayOrDefault(documentdb); result != tt.expected {
+				t.Errorf("Expected %d, got %d", tt.expected, result)
+			}
+		})
+	}
+}
+
+func TestGetSmartShutdownTimeoutOrDefault(t *testing.T) {
+	tests := []struct {
+		name     string
+		timeout  *int32
+		expected int32
+	}{
+		{name: "returns the CNPG default when unset", timeout: nil, expected: util.CNPG_DEFAULT_SMART_SHUTDOWN_TIMEOUT},
+		{name: "returns 0 when set to 0", timeout: ptr.To[int32](0), expected: 0},
+		{name: "returns custom SmartShutdownTimeout when set", timeout: ptr.To[int32](20), expected: 20},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			documentdb := &dbpreview.DocumentDB{Spec: dbpreview.DocumentDBSpec{Timeouts: dbpreview.Timeouts{SmartShutdownTimeout: tt.timeout}}}
+			if result := getSmartShutdownTimeoutOrDefault(documentdb); result != tt.expected {
+				t.Errorf("Expected %d, got %d", tt.expected, result)
+			}
+		})
+	}
+}
+
+func TestGetMaxSwitchoverDelayOrDefault(t *testing.T) {
+	tests := []struct {
+		name            string
+		switchoverDelay int32
+		expected        int32
+	}{
+		{name: "returns the CNPG default when SwitchoverDelay is 0", switchoverDelay: 0, expected: util.CNPG_DEFAULT_SWITCHOVER_DELAY},
+		{name: "returns custom SwitchoverDelay when set", switchoverDelay: 600, expected: 600},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			documentdb := &dbpreview.DocumentDB{Spec: dbpreview.DocumentDBSpec{Timeouts: dbpreview.Timeouts{SwitchoverDelay: tt.switchoverDelay}}}
+			if result := getMaxSwitchoverDelayOrDefault(documentdb); result != tt.expected {
+				t.Errorf("Expected %d, got %d", tt.expected, result)
+			}
+		})
+	}
+}
+
 func TestGetMaxStopDelayOrDefault(t *testing.T) {
 	tests := []struct {
 		name       string
@@ -1057,6 +1845,17 @@ func TestGetMaxStopDelayOrDefault(t *testing.T) {
 			},
 			expected: 1800,
 		},
+		{
+			name: "returns default when StopDelay is out of range",
+			documentdb: &dbpreview.DocumentDB{
+				Spec: dbpreview.DocumentDBSpec{
+					Timeouts: dbpreview.Timeouts{
+						StopDelay: 3600,
+					},
+				},
+			},
+			expected: util.CNPG_DEFAULT_STOP_DELAY,
+		},
 		{
 			name: "returns default when Timeouts is empty",
 			documentdb: &dbpreview.DocumentDB{
@@ -0,0 +1,189 @@
+// Copyright (c) Microsoft Corporation.
+// Licensed under the MIT License.
+
+package cnpg
+
+import (
+	"encoding/json"
+	"fmt"
+	"maps"
+	"reflect"
+	"slices"
+	"strconv"
+	"strings"
+
+	cnpgv1 "github.com/cloudnative-pg/cloudnative-pg/api/v1"
+)
+
+// driftIgnoredPaths are parts of the desired spec that are only applied at
+// cluster creation or are changed on purpose by the replication flow, so a
+// difference there is not drift.
+var driftIgnoredPaths = []string{
+	PatchPathBootstrap,
+	PatchPathReplicaCluster,
+	PatchPathExternalClusters,
+}
+
+// driftUnenforcedPaths are fields that CNPG refuses to change after the cluster
+// is created, so BuildDriftPatch does not try to revert them.
+var driftUnenforcedPaths = []string{
+	"/spec/postgresUID",
+	"/spec/postgresGID",
+}
+
+// DetectDrift returns the JSON Pointer paths, in sorted order, at which the live
+// CNPG Cluster spec differs from the spec the operator renders. Only fields set
+// in desired are compared, so defaults filled in by CNPG are not reported.
+func DetectDrift(current, desired *cnpgv1.Cluster) ([]string, error) {
+	currentSpec, err := toUnstructured(current.Spec)
+	if err != nil {
+		return nil, err
+	}
+	desiredSpec, err := toUnstructured(desired.Spec)
+	if err != nil {
+		return nil, err
+	}
+
+	var paths []string
+	diffPaths("/spec", desiredSpec, currentSpec, &paths)
+	return paths, nil
+}
+
+// BuildDriftPatch returns the JSON Patch operations that reset every drifted
+// field reported by DetectDrift to its desired value. A drifted field whose
+// parent is missing from current is added along with its nearest missing
+// ancestor, since JSON Patch cannot add below a missing path.
+func BuildDriftPatch(current, desired *cnpgv1.Cluster) ([]JSONPatch, error) {
+	currentSpec, err := toUnstructured(current.Spec)
+	if err != nil {
+		return nil, err
+	}
+	desiredSpec, err := toUnstructured(desired.Spec)
+	if err != nil {
+		return nil, err
+	}
+
+	var paths []string
+	diffPaths("/spec", desiredSpec, currentSpec, &paths)
+
+	var patchOps []JSONPatch
+	added := map[string]bool{}
+	for _, path := range paths {
+		if isUnderAny(path, driftUnenforcedPaths) {
+			continue
+		}
+		tokens := strings.Split(strings.TrimPrefix(path, "/spec/"), "/")
+
+		// Walk current down to the first missing token
+		parent := currentSpec
+		depth := 0
+		for ; depth < len(tokens)-1; depth++ {
+			child, ok := lookupToken(parent, tokens[depth])
+			if !ok || child == nil {
+				break
+			}
+			parent = child
+		}
+		target := "/spec/" + strings.Join(tokens[:depth+1], "/")
+		if added[target] {
+			continue
+		}
+		added[target] = true
+
+		value := desiredSpec
+		for _, token := range tokens[:depth+1] {
+			value, _ = lookupToken(value, token)
+		}
+		op := JSONPatch{Op: PatchOpAdd, Path: target, Value: value}
+		if _, ok := parent.([]any); ok {
+			// "add" at an array index inserts instead of replacing
+			op.Op = PatchOpReplace
+		}
+		if value == nil {
+			op.Op = PatchOpRemove
+		}
+		patchOps = append(patchOps, op)
+	}
+	return patchOps, nil
+}
+
+// isUnderAny reports whether path is one of prefixes or lies below one of them.
+func isUnderAny(path string, prefixes []string) bool {
+	for _, prefix := range prefixes {
+		if path == prefix || strings.HasPrefix(path, prefix+"/") {
+			return true
+		}
+	}
+	return false
+}
+
+// lookupToken returns the child of an unstructured object or array addressed by
+// a single JSON Pointer token.
+func lookupToken(node any, token string) (any, bool) {
+	switch n := node.(type) {
+	case map[string]any:
+		value, ok := n[unescapeJSONPointer(token)]
+		return value, ok
+	case []any:
+		i, err := strconv.Atoi(token)
+		if err != nil || i < 0 || i >= len(n) {
+			return nil, false
+		}
+		return n[i], true
+	}
+	return nil, false
+}
+
+func toUnstructured(spec cnpgv1.ClusterSpec) (any, error) {
+	data, err := json.Marshal(spec)
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal CNPG Cluster spec: %w", err)
+	}
+	var out any
+	if err := json.Unmarshal(data, &out); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal CNPG Cluster spec: %w", err)
+	}
+	return out, nil
+}
+
+func diffPaths(path string, desired, current any, paths *[]string) {
+	if slices.Contains(driftIgnoredPaths, path) {
+		return
+	}
+
+	switch d := desired.(type) {
+	case map[string]any:
+		// A missing object compares as an empty one, so that only keys set
+		// in desired are reported.
+		c, ok := current.(map[string]any)
+		if !ok && current != nil {
+			*paths = append(*paths, path)
+			return
+		}
+		for _, key := range slices.Sorted(maps.Keys(d)) {
+			diffPaths(path+"/"+escapeJSONPointer(key), d[key], c[key], paths)
+		}
+	case []any:
+		c, ok := current.([]any)
+		if (!ok && current != nil) || len(c) != len(d) {
+			*paths = append(*paths, path)
+			return
+		}
+		for i := range d {
+			diffPaths(path+"/"+strconv.Itoa(i), d[i], c[i], paths)
+		}
+	default:
+		if !reflect.DeepEqual(desired, current) {
+			*paths = append(*paths, path)
+		}
+	}
+}
+
+// escapeJSONPointer escapes a map key for use in a JSON Pointer (RFC 6901).
+func escapeJSONPointer(key string) string {
+	return strings.NewReplacer("~", "~0", "/", "~1").Replace(key)
+}
+
+func unescapeJSONPointer(token string) string {
+	return strings.NewReplacer("~1", "/", "~0", "~").Replace(token)
+}
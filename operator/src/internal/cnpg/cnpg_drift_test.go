@@ -0,0 +1,157 @@
+// Copyright (c) Microsoft Corporation.
+// Licensed under the MIT License.
+
+package cnpg
+
+import (
+	cnpgv1 "github.com/cloudnative-pg/cloudnative-pg/api/v1"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("DetectDrift", func() {
+	var desired *cnpgv1.Cluster
+
+	BeforeEach(func() {
+		desired = &cnpgv1.Cluster{
+			Spec: cnpgv1.ClusterSpec{
+				Instances: 3,
+				ImageName: "postgres:16",
+				PostgresConfiguration: cnpgv1.PostgresConfiguration{
+					Parameters: map[string]string{"max_connections": "300"},
+				},
+			},
+		}
+	})
+
+	It("reports nothing when the live cluster matches", func() {
+		paths, err := DetectDrift(desired.DeepCopy(), desired)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(paths).To(BeEmpty())
+	})
+
+	It("ignores fields that CNPG fills in", func() {
+		current := desired.DeepCopy()
+		current.Spec.PostgresConfiguration.Parameters["shared_buffers"] = "128MB"
+		current.Spec.PrimaryUpdateStrategy = cnpgv1.PrimaryUpdateStrategyUnsupervised
+
+		paths, err := DetectDrift(current, desired)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(paths).To(BeEmpty())
+	})
+
+	It("reports the paths that were changed", func() {
+		current := desired.DeepCopy()
+		current.Spec.Instances = 1
+		current.Spec.PostgresConfiguration.Parameters["max_connections"] = "100"
+
+		paths, err := DetectDrift(current, desired)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(paths).To(Equal([]string{
+			"/spec/instances",
+			"/spec/postgresql/parameters/max_connections",
+		}))
+	})
+
+	It("reports a desired field missing from the live cluster", func() {
+		current := desired.DeepCopy()
+		current.Spec.PostgresConfiguration.Parameters = nil
+
+		paths, err := DetectDrift(current, desired)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(paths).To(Equal([]string{"/spec/postgresql/parameters/max_connections"}))
+	})
+
+	It("ignores bootstrap, replica and external cluster settings", func() {
+		desired.Spec.Bootstrap = &cnpgv1.BootstrapConfiguration{InitDB: &cnpgv1.BootstrapInitDB{Database: "app"}}
+		desired.Spec.ReplicaCluster = &cnpgv1.ReplicaClusterConfiguration{Primary: "east"}
+		desired.Spec.ExternalClusters = []cnpgv1.ExternalCluster{{Name: "east"}}
+
+		current := desired.DeepCopy()
+		current.Spec.Bootstrap = nil
+		current.Spec.ReplicaCluster = nil
+		current.Spec.ExternalClusters = nil
+
+		paths, err := DetectDrift(current, desired)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(paths).To(BeEmpty())
+	})
+
+	It("escapes map keys in the reported paths", func() {
+		desired.Spec.InheritedMetadata = &cnpgv1.EmbeddedObjectMetadata{
+			Labels: map[string]string{"app.kubernetes.io/name": "documentdb"},
+		}
+		current := desired.DeepCopy()
+		current.Spec.InheritedMetadata.Labels["app.kubernetes.io/name"] = "other"
+
+		paths, err := DetectDrift(current, desired)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(paths).To(Equal([]string{"/spec/inheritedMetadata/labels/app.kubernetes.io~1name"}))
+	})
+})
+
+var _ = Describe("BuildDriftPatch", func() {
+	It("resets drifted fields to their desired values", func() {
+		desired := baseCluster("test", "test-ns")
+		desired.Spec.Description = "DocumentDB cluster"
+		current := desired.DeepCopy()
+		current.Spec.Description = "edited"
+		current.Spec.PostgresConfiguration.Extensions[0].ImageVolumeSource.Reference = "edited"
+
+		ops, err := BuildDriftPatch(current, desired)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(ops).To(ConsistOf(
+			JSONPatch{Op: PatchOpAdd, Path: "/spec/description", Value: "DocumentDB cluster"},
+			JSONPatch{Op: PatchOpAdd, Path: "/spec/postgresql/extensions/0/image/reference", Value: "ghcr.io/documentdb/documentdb:0.110.0"},
+		))
+	})
+
+	It("adds a missing object as a whole", func() {
+		desired := baseCluster("test", "test-ns")
+		desired.Spec.InheritedMetadata = &cnpgv1.EmbeddedObjectMetadata{
+			Labels:      map[string]string{"app": "documentdb"},
+			Annotations: map[string]string{"team": "data"},
+		}
+		current := baseCluster("test", "test-ns")
+
+		ops, err := BuildDriftPatch(current, desired)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(ops).To(HaveLen(1))
+		Expect(ops[0].Op).To(Equal(PatchOpAdd))
+		Expect(ops[0].Path).To(Equal("/spec/inheritedMetadata"))
+	})
+
+	It("replaces an array element rather than inserting one", func() {
+		desired := baseCluster("test", "test-ns")
+		desired.Spec.PostgresConfiguration.AdditionalLibraries = []string{"pg_cron", "documentdb"}
+		current := desired.DeepCopy()
+		current.Spec.PostgresConfiguration.AdditionalLibraries[1] = "edited"
+
+		ops, err := BuildDriftPatch(current, desired)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(ops).To(ConsistOf(JSONPatch{Op: PatchOpReplace, Path: "/spec/postgresql/shared_preload_libraries/1", Value: "documentdb"}))
+	})
+
+	It("adds missing fields below an array element", func() {
+		desired := baseCluster("test", "test-ns")
+		current := desired.DeepCopy()
+		current.Spec.Plugins[0].Parameters = nil
+
+		ops, err := BuildDriftPatch(current, desired)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(ops).To(HaveLen(1))
+		Expect(ops[0].Op).To(Equal(PatchOpAdd))
+		Expect(ops[0].Path).To(Equal("/spec/plugins/0/parameters"))
+	})
+
+	It("does not revert fields CNPG cannot change", func() {
+		desired := baseCluster("test", "test-ns")
+		desired.Spec.PostgresUID = 26
+		current := desired.DeepCopy()
+		current.Spec.PostgresUID = 999
+
+		ops, err := BuildDriftPatch(current, desired)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(ops).To(BeEmpty())
+	})
+})
@@ -26,26 +26,54 @@ const (
 	PatchPathExternalClusters  = "/spec/externalClusters"
 	PatchPathCertificates      = "/spec/certificates"
 	PatchPathPostgresPgHBA     = "/spec/postgresql/pg_hba"
-	PatchPathManagedServices   = "/spec/managed/services/additional"
 	PatchPathSynchronous       = "/spec/postgresql/synchronous"
 	PatchPathBootstrap         = "/spec/bootstrap"
 
 	// JSON Patch path format strings for image upgrades (require fmt.Sprintf with index)
 	PatchPathExtensionImageFmt     = "/spec/postgresql/extensions/%d/image/reference"
+	PatchPathExtensionPullFmt      = "/spec/postgresql/extensions/%d/image/pullPolicy"
 	PatchPathPluginGatewayImageFmt = "/spec/plugins/%d/parameters/gatewayImage"
 
 	// JSON Patch path format string for plugin parameters (require fmt.Sprintf with index and key)
 	PatchPathPluginParamFmt = "/spec/plugins/%d/parameters/%s"
 
+	// JSON Patch path format string for a plugin (require fmt.Sprintf with index, or "-" to append)
+	PatchPathPluginFmt = "/spec/plugins/%v"
+
 	// JSON Patch paths — mutable spec fields
 	PatchPathImageName          = "/spec/imageName"
 	PatchPathStorageSize        = "/spec/storage/size"
+	PatchPathWalStorage         = "/spec/walStorage"
+	PatchPathWalStorageSize     = "/spec/walStorage/size"
 	PatchPathLogLevel           = "/spec/logLevel"
 	PatchPathAffinity           = "/spec/affinity"
 	PatchPathMaxStopDelay       = "/spec/stopDelay"
+	PatchPathMaxStartDelay      = "/spec/startDelay"
+	PatchPathSmartShutdown      = "/spec/smartShutdownTimeout"
+	PatchPathSwitchoverDelay    = "/spec/switchoverDelay"
+	PatchPathFailoverDelay      = "/spec/failoverDelay"
+	PatchPathProbes             = "/spec/probes"
+	PatchPathNodeMaintenance    = "/spec/nodeMaintenanceWindow"
+	PatchPathPrimaryStrategy    = "/spec/primaryUpdateStrategy"
+	PatchPathPrimaryMethod      = "/spec/primaryUpdateMethod"
+	PatchPathSuperuserAccess    = "/spec/enableSuperuserAccess"
+	PatchPathSuperuserSecret    = "/spec/superuserSecret"
 	PatchPathPostgresParameters = "/spec/postgresql/parameters"
 	PatchPathPgHBA              = "/spec/postgresql/pg_hba"
 	PatchPathResources          = "/spec/resources"
+	PatchPathInheritedMetadata  = "/spec/inheritedMetadata"
+	PatchPathServiceAccount     = "/spec/serviceAccountTemplate"
+	PatchPathImagePullSecrets   = "/spec/imagePullSecrets"
+	PatchPathImagePullPolicy    = "/spec/imagePullPolicy"
+	PatchPathSeccompProfile     = "/spec/seccompProfile"
+	PatchPathPodSecurityContext = "/spec/podSecurityContext"
+	PatchPathSecurityContext    = "/spec/securityContext"
+	PatchPathManaged            = "/spec/managed"
+	PatchPathManagedServices    = "/spec/managed/services"
+	PatchPathAdditionalServices = "/spec/managed/services/additional"
+
+	// JSON Patch path for the labels of the CNPG Cluster itself.
+	PatchPathLabels = "/metadata/labels"
 
 	// JSON Patch path for restart annotation.
 	// The '/' in the annotation key is escaped as '~1' per RFC 6901 (JSON Pointer).
@@ -8,6 +8,8 @@ import (
 	"encoding/json"
 	"fmt"
 	"reflect"
+	"slices"
+	"sort"
 	"time"
 
 	cnpgv1 "github.com/cloudnative-pg/cloudnative-pg/api/v1"
@@ -22,9 +24,10 @@ import (
 // all fields in a single atomic JSON Patch operation. This is the single entry point
 // for ALL CNPG spec mutations (images + plugin params + replication).
 //
-// Mutable plugin parameters synced: gatewayImage, gatewayTLSSecret, sidecar
-// resource params, and OTel sidecar params (otelCollectorImage,
-// otelConfigMapName, prometheusPort, otelConfigHash).
+// Mutable plugin parameters synced: gatewayImage, gatewayImagePullPolicy,
+// gatewayTLSSecret, sidecar resource params, and OTel sidecar params
+// (otelCollectorImage, otelCollectorImagePullPolicy, otelConfigMapName,
+// prometheusPort, otelConfigHash).
 // Other parameters (e.g., documentDbCredentialSecret) are set at cluster creation
 // and do not change during the lifecycle of a DocumentDB resource.
 //
@@ -38,6 +41,58 @@ func SyncCnpgCluster(
 ) error {
 	logger := log.FromContext(ctx)
 
+	patchOps, needsRestart, err := BuildSyncPatch(current, desired)
+	if err != nil {
+		return err
+	}
+
+	// Extra operations (e.g., replication changes)
+	patchOps = append(patchOps, extraOps...)
+
+	if needsRestart {
+		// Ensure the annotations map exists before adding a key into it.
+		// JSON Patch "add" requires the parent path to exist.
+		if current.Annotations == nil {
+			patchOps = append(patchOps, JSONPatch{
+				Op:   PatchOpAdd,
+				Path: "/metadata/annotations",
+				Value: map[string]string{
+					"kubectl.kubernetes.io/restartedAt": time.Now().Format(time.RFC3339Nano),
+				},
+			})
+		} else {
+			patchOps = append(patchOps, JSONPatch{
+				Op:    PatchOpAdd,
+				Path:  PatchPathRestartAnnotation,
+				Value: time.Now().Format(time.RFC3339Nano),
+			})
+		}
+	}
+
+	if len(patchOps) == 0 {
+		return nil
+	}
+
+	// Apply all patches atomically
+	patchBytes, err := json.Marshal(patchOps)
+	if err != nil {
+		return fmt.Errorf("failed to marshal sync patch: %w", err)
+	}
+	if err := c.Patch(ctx, current, client.RawPatch(types.JSONPatchType, patchBytes)); err != nil {
+		return fmt.Errorf("failed to patch CNPG cluster: %w", err)
+	}
+
+	if needsRestart {
+		logger.Info("Added restart annotation for non-extension update", "clusterName", current.Name)
+	}
+
+	return nil
+}
+
+// BuildSyncPatch returns the JSON Patch operations that SyncCnpgCluster applies
+// to bring current in line with desired, without the restart annotation, and
+// whether the pods must be restarted for the changes to take effect.
+func BuildSyncPatch(current, desired *cnpgv1.Cluster) ([]JSONPatch, bool, error) {
 	var patchOps []JSONPatch
 	extensionUpdated := false
 	gatewayUpdated := false
@@ -47,7 +102,7 @@ func SyncCnpgCluster(
 	_, desiredExtImage := findExtensionImage(desired)
 	if currentExtImage != desiredExtImage {
 		if currentExtIndex == -1 {
-			return fmt.Errorf("documentdb extension not found in current CNPG cluster spec")
+			return nil, false, fmt.Errorf("documentdb extension not found in current CNPG cluster spec")
 		}
 		patchOps = append(patchOps, JSONPatch{
 			Op:    PatchOpReplace,
@@ -56,6 +111,20 @@ func SyncCnpgCluster(
 		})
 		extensionUpdated = true
 	}
+	// CNPG rolls the pods when the pull policy of the extension image changes.
+	if desiredExtIndex, _ := findExtensionImage(desired); currentExtIndex != -1 && desiredExtIndex != -1 {
+		currentPull := current.Spec.PostgresConfiguration.Extensions[currentExtIndex].ImageVolumeSource.PullPolicy
+		desiredPull := desired.Spec.PostgresConfiguration.Extensions[desiredExtIndex].ImageVolumeSource.PullPolicy
+		if currentPull != desiredPull {
+			path := fmt.Sprintf(PatchPathExtensionPullFmt, currentExtIndex)
+			if desiredPull == "" {
+				patchOps = append(patchOps, JSONPatch{Op: PatchOpRemove, Path: path})
+			} else {
+				patchOps = append(patchOps, JSONPatch{Op: PatchOpAdd, Path: path, Value: desiredPull})
+			}
+			extensionUpdated = true
+		}
+	}
 
 	// Gateway image and plugin parameters share the same plugin lookup
 	pluginParamsChanged := false
@@ -75,6 +144,18 @@ func SyncCnpgCluster(
 				gatewayUpdated = true
 			}
 
+			// Gateway image pull policy. The plugin defaults it when unset, so
+			// it is only ever changed, never removed.
+			desiredGwPull := getParam(desiredPlugin.Parameters, util.PLUGIN_PARAM_GATEWAY_IMAGE_PULL_POLICY)
+			if desiredGwPull != "" && getParam(currentPlugin.Parameters, util.PLUGIN_PARAM_GATEWAY_IMAGE_PULL_POLICY) != desiredGwPull {
+				patchOps = append(patchOps, JSONPatch{
+					Op:    PatchOpAdd,
+					Path:  fmt.Sprintf(PatchPathPluginParamFmt, pluginIdx, util.PLUGIN_PARAM_GATEWAY_IMAGE_PULL_POLICY),
+					Value: desiredGwPull,
+				})
+				pluginParamsChanged = true
+			}
+
 			// Ensure plugin is enabled
 			if currentPlugin.Enabled == nil || !*currentPlugin.Enabled {
 				patchOps = append(patchOps, JSONPatch{
@@ -111,7 +192,11 @@ func SyncCnpgCluster(
 				util.PLUGIN_PARAM_GATEWAY_MEMORY_LIMIT,
 				util.PLUGIN_PARAM_GATEWAY_CPU_REQUEST,
 				util.PLUGIN_PARAM_GATEWAY_CPU_LIMIT,
+				util.PLUGIN_PARAM_GATEWAY_RUN_AS_NAMESPACE_UID,
+				util.PLUGIN_PARAM_GATEWAY_SECURITY_CONTEXT,
+				util.PLUGIN_PARAM_GATEWAY_PORT,
 				"otelCollectorImage",
+				util.PLUGIN_PARAM_OTEL_IMAGE_PULL_POLICY,
 				"otelConfigMapName",
 				"prometheusPort",
 				"otelConfigHash",
@@ -141,6 +226,31 @@ func SyncCnpgCluster(
 		}
 	}
 
+	// Additional CNPG-I plugins
+	// Every plugin but the sidecar injector is replaced by those of
+	// spec.plugins.additional; whether that rolls the pods is up to CNPG.
+	if len(desired.Spec.Plugins) > 0 {
+		sidecarName := desired.Spec.Plugins[0].Name
+		var currentIndexes []int
+		var currentAdditional []cnpgv1.PluginConfiguration
+		for i, plugin := range current.Spec.Plugins {
+			if plugin.Name != sidecarName {
+				currentIndexes = append(currentIndexes, i)
+				currentAdditional = append(currentAdditional, plugin)
+			}
+		}
+		desiredAdditional := desired.Spec.Plugins[1:]
+		if len(currentAdditional) != len(desiredAdditional) ||
+			(len(desiredAdditional) > 0 && !reflect.DeepEqual(currentAdditional, desiredAdditional)) {
+			for _, i := range slices.Backward(currentIndexes) {
+				patchOps = append(patchOps, JSONPatch{Op: PatchOpRemove, Path: fmt.Sprintf(PatchPathPluginFmt, i)})
+			}
+			for _, plugin := range desiredAdditional {
+				patchOps = append(patchOps, JSONPatch{Op: PatchOpAdd, Path: fmt.Sprintf(PatchPathPluginFmt, "-"), Value: plugin})
+			}
+		}
+	}
+
 	// --- Mutable spec fields ---
 	// CNPG natively detects changes to these fields and triggers rolling restarts
 	// when needed (via PodSpec drift detection or image comparison), so we only
@@ -174,6 +284,24 @@ func SyncCnpgCluster(
 		})
 	}
 
+	// WAL volume: it can be added, which CNPG does by restarting the
+	// instances, but not removed (both webhooks reject it), and only grows
+	if desired.Spec.WalStorage != nil {
+		if current.Spec.WalStorage == nil {
+			patchOps = append(patchOps, JSONPatch{
+				Op:    PatchOpAdd,
+				Path:  PatchPathWalStorage,
+				Value: desired.Spec.WalStorage,
+			})
+		} else if current.Spec.WalStorage.Size != desired.Spec.WalStorage.Size {
+			patchOps = append(patchOps, JSONPatch{
+				Op:    PatchOpReplace,
+				Path:  PatchPathWalStorageSize,
+				Value: desired.Spec.WalStorage.Size,
+			})
+		}
+	}
+
 	// Log level
 	// CNPG renders logLevel into the bootstrap container command (--log-level=...),
 	// so changes cause PodSpec drift detected by checkPodSpecIsOutdated.
@@ -205,6 +333,112 @@ func SyncCnpgCluster(
 		})
 	}
 
+	// Start delay (maxStartDelay)
+	// CNPG derives the startup probe failure threshold from it, so a change
+	// makes the PodSpec drift and triggers a rollout.
+	if current.Spec.MaxStartDelay != desired.Spec.MaxStartDelay {
+		patchOps = append(patchOps, JSONPatch{
+			Op:    PatchOpReplace,
+			Path:  PatchPathMaxStartDelay,
+			Value: desired.Spec.MaxStartDelay,
+		})
+	}
+
+	// Shutdown and failover timings
+	// The instance manager and the CNPG operator read them from the Cluster;
+	// they do not change the PodSpec and take effect without a rollout.
+	// failoverDelay is omitted from the Cluster while 0, hence "add".
+	if desired.Spec.SmartShutdownTimeout != nil && !reflect.DeepEqual(current.Spec.SmartShutdownTimeout, desired.Spec.SmartShutdownTimeout) {
+		patchOps = append(patchOps, JSONPatch{
+			Op:    PatchOpAdd,
+			Path:  PatchPathSmartShutdown,
+			Value: *desired.Spec.SmartShutdownTimeout,
+		})
+	}
+	if current.Spec.MaxSwitchoverDelay != desired.Spec.MaxSwitchoverDelay {
+		patchOps = append(patchOps, JSONPatch{
+			Op:    PatchOpAdd,
+			Path:  PatchPathSwitchoverDelay,
+			Value: desired.Spec.MaxSwitchoverDelay,
+		})
+	}
+	if current.Spec.FailoverDelay != desired.Spec.FailoverDelay {
+		patchOps = append(patchOps, JSONPatch{
+			Op:    PatchOpAdd,
+			Path:  PatchPathFailoverDelay,
+			Value: desired.Spec.FailoverDelay,
+		})
+	}
+
+	// Probes of the PostgreSQL container
+	// CNPG renders them into the PodSpec, so a change triggers a rollout.
+	if !reflect.DeepEqual(current.Spec.Probes, desired.Spec.Probes) {
+		if desired.Spec.Probes == nil {
+			patchOps = append(patchOps, JSONPatch{Op: PatchOpRemove, Path: PatchPathProbes})
+		} else {
+			patchOps = append(patchOps, JSONPatch{
+				Op:    PatchOpAdd,
+				Path:  PatchPathProbes,
+				Value: desired.Spec.Probes,
+			})
+		}
+	}
+
+	// Node maintenance window
+	// The CNPG operator reads it when a node is drained; it does not change
+	// the PodSpec.
+	if !reflect.DeepEqual(current.Spec.NodeMaintenanceWindow, desired.Spec.NodeMaintenanceWindow) {
+		if desired.Spec.NodeMaintenanceWindow == nil {
+			patchOps = append(patchOps, JSONPatch{Op: PatchOpRemove, Path: PatchPathNodeMaintenance})
+		} else {
+			patchOps = append(patchOps, JSONPatch{
+				Op:    PatchOpAdd,
+				Path:  PatchPathNodeMaintenance,
+				Value: desired.Spec.NodeMaintenanceWindow,
+			})
+		}
+	}
+
+	// Primary update strategy and method
+	// The CNPG operator reads them during a rolling update; they do not change
+	// the PodSpec.
+	if current.Spec.PrimaryUpdateStrategy != desired.Spec.PrimaryUpdateStrategy {
+		patchOps = append(patchOps, JSONPatch{
+			Op:    PatchOpAdd,
+			Path:  PatchPathPrimaryStrategy,
+			Value: desired.Spec.PrimaryUpdateStrategy,
+		})
+	}
+	if current.Spec.PrimaryUpdateMethod != desired.Spec.PrimaryUpdateMethod {
+		patchOps = append(patchOps, JSONPatch{
+			Op:    PatchOpAdd,
+			Path:  PatchPathPrimaryMethod,
+			Value: desired.Spec.PrimaryUpdateMethod,
+		})
+	}
+
+	// Superuser access
+	// The CNPG operator sets or blanks the postgres password accordingly,
+	// without a rollout.
+	if !reflect.DeepEqual(current.Spec.EnableSuperuserAccess, desired.Spec.EnableSuperuserAccess) {
+		patchOps = append(patchOps, JSONPatch{
+			Op:    PatchOpAdd,
+			Path:  PatchPathSuperuserAccess,
+			Value: desired.Spec.EnableSuperuserAccess,
+		})
+	}
+	if !reflect.DeepEqual(current.Spec.SuperuserSecret, desired.Spec.SuperuserSecret) {
+		if desired.Spec.SuperuserSecret == nil {
+			patchOps = append(patchOps, JSONPatch{Op: PatchOpRemove, Path: PatchPathSuperuserSecret})
+		} else {
+			patchOps = append(patchOps, JSONPatch{
+				Op:    PatchOpAdd,
+				Path:  PatchPathSuperuserSecret,
+				Value: desired.Spec.SuperuserSecret,
+			})
+		}
+	}
+
 	// PostgreSQL parameters (postgresql.conf settings)
 	// The desired parameters are computed by MergeParameters (memory-aware + static
 	// defaults + user overrides). CNPG detects parameter changes and reconciles the
@@ -242,6 +476,105 @@ func SyncCnpgCluster(
 		})
 	}
 
+	// Inherited metadata (labels and annotations for the pods, PVCs and Services)
+	// CNPG updates the metadata of the objects it manages in place, without a restart.
+	if !reflect.DeepEqual(current.Spec.InheritedMetadata, desired.Spec.InheritedMetadata) {
+		patchOps = append(patchOps, JSONPatch{
+			Op:    PatchOpAdd,
+			Path:  PatchPathInheritedMetadata,
+			Value: desired.Spec.InheritedMetadata,
+		})
+	}
+
+	// ServiceAccount metadata and pull secrets
+	// CNPG merges them into the existing ServiceAccount of the cluster. Removed
+	// annotations and labels are left on the ServiceAccount.
+	if !reflect.DeepEqual(current.Spec.ServiceAccountTemplate, desired.Spec.ServiceAccountTemplate) {
+		serviceAccountPatch := JSONPatch{
+			Op:    PatchOpAdd,
+			Path:  PatchPathServiceAccount,
+			Value: desired.Spec.ServiceAccountTemplate,
+		}
+		if desired.Spec.ServiceAccountTemplate == nil {
+			serviceAccountPatch.Op = PatchOpRemove
+			serviceAccountPatch.Value = nil
+		}
+		patchOps = append(patchOps, serviceAccountPatch)
+	}
+	if !slices.Equal(current.Spec.ImagePullSecrets, desired.Spec.ImagePullSecrets) {
+		patchOps = append(patchOps, JSONPatch{
+			Op:    PatchOpAdd,
+			Path:  PatchPathImagePullSecrets,
+			Value: desired.Spec.ImagePullSecrets,
+		})
+	}
+	// CNPG rolls the pods when the pull policy of the PostgreSQL image changes.
+	if current.Spec.ImagePullPolicy != desired.Spec.ImagePullPolicy {
+		if desired.Spec.ImagePullPolicy == "" {
+			patchOps = append(patchOps, JSONPatch{Op: PatchOpRemove, Path: PatchPathImagePullPolicy})
+		} else {
+			patchOps = append(patchOps, JSONPatch{
+				Op:    PatchOpAdd,
+				Path:  PatchPathImagePullPolicy,
+				Value: desired.Spec.ImagePullPolicy,
+			})
+		}
+	}
+
+	// Security contexts of the instance pods
+	// CNPG detects the PodSpec change and rolls the pods.
+	securityPatch := func(path string, current, desired any, unset bool) {
+		if reflect.DeepEqual(current, desired) {
+			return
+		}
+		if unset {
+			patchOps = append(patchOps, JSONPatch{Op: PatchOpRemove, Path: path})
+			return
+		}
+		patchOps = append(patchOps, JSONPatch{Op: PatchOpAdd, Path: path, Value: desired})
+	}
+	securityPatch(PatchPathSeccompProfile, current.Spec.SeccompProfile, desired.Spec.SeccompProfile,
+		desired.Spec.SeccompProfile == nil)
+	securityPatch(PatchPathPodSecurityContext, current.Spec.PodSecurityContext, desired.Spec.PodSecurityContext,
+		desired.Spec.PodSecurityContext == nil)
+	securityPatch(PatchPathSecurityContext, current.Spec.SecurityContext, desired.Spec.SecurityContext,
+		desired.Spec.SecurityContext == nil)
+
+	// Additional managed Services
+	// The CNPG operator creates, patches and deletes them without touching the
+	// pods. The parents of the list are added when the current cluster has none.
+	currentServices, desiredServices := additionalServices(current), additionalServices(desired)
+	if (len(currentServices) > 0 || len(desiredServices) > 0) && !reflect.DeepEqual(currentServices, desiredServices) {
+		switch {
+		case len(desiredServices) == 0:
+			patchOps = append(patchOps, JSONPatch{Op: PatchOpRemove, Path: PatchPathAdditionalServices})
+		case current.Spec.Managed == nil:
+			patchOps = append(patchOps, JSONPatch{
+				Op:   PatchOpAdd,
+				Path: PatchPathManaged,
+				Value: &cnpgv1.ManagedConfiguration{
+					Services: &cnpgv1.ManagedServices{Additional: desiredServices},
+				},
+			})
+		case current.Spec.Managed.Services == nil:
+			patchOps = append(patchOps, JSONPatch{
+				Op:    PatchOpAdd,
+				Path:  PatchPathManagedServices,
+				Value: &cnpgv1.ManagedServices{Additional: desiredServices},
+			})
+		default:
+			patchOps = append(patchOps, JSONPatch{
+				Op:    PatchOpAdd,
+				Path:  PatchPathAdditionalServices,
+				Value: desiredServices,
+			})
+		}
+	}
+
+	// Labels of the CNPG Cluster. Only the desired labels are added or updated;
+	// labels set by other parties are left alone.
+	patchOps = append(patchOps, buildLabelsPatch(current.Labels, desired.Labels)...)
+
 	if !reflect.DeepEqual(current.Spec.Certificates, desired.Spec.Certificates) {
 		certificatesPatch := JSONPatch{
 			Op:    PatchOpReplace,
@@ -257,52 +590,66 @@ func SyncCnpgCluster(
 		patchOps = append(patchOps, certificatesPatch)
 	}
 
-	// Extra operations (e.g., replication changes)
-	patchOps = append(patchOps, extraOps...)
+	// In full drift reconciliation mode, also revert every other field the
+	// operator renders. Fields already handled above are left to those patches.
+	if util.IsFullDriftReconciliation() {
+		driftOps, err := BuildDriftPatch(current, desired)
+		if err != nil {
+			return nil, false, err
+		}
+		targetedPaths := make([]string, 0, len(patchOps))
+		for _, op := range patchOps {
+			targetedPaths = append(targetedPaths, op.Path)
+		}
+		for _, op := range driftOps {
+			if !isUnderAny(op.Path, targetedPaths) {
+				patchOps = append(patchOps, op)
+			}
+		}
+	}
 
 	// CNPG auto-restarts pods when extension image changes (ImageVolume PodSpec divergence),
-	// but NOT for plugin parameter or gateway-only changes. Include a restart annotation
-	// in the same atomic patch to avoid partial-apply state where the spec is updated but
-	// the restart annotation is never applied if a subsequent reconcile no-ops the spec diff.
+	// but NOT for plugin parameter or gateway-only changes. SyncCnpgCluster includes a
+	// restart annotation in the same atomic patch to avoid partial-apply state where the
+	// spec is updated but the restart annotation is never applied if a subsequent
+	// reconcile no-ops the spec diff.
 	needsRestart := !extensionUpdated && (gatewayUpdated || pluginParamsChanged)
-	if needsRestart {
-		// Ensure the annotations map exists before adding a key into it.
-		// JSON Patch "add" requires the parent path to exist.
-		if current.Annotations == nil {
-			patchOps = append(patchOps, JSONPatch{
-				Op:   PatchOpAdd,
-				Path: "/metadata/annotations",
-				Value: map[string]string{
-					"kubectl.kubernetes.io/restartedAt": time.Now().Format(time.RFC3339Nano),
-				},
-			})
-		} else {
-			patchOps = append(patchOps, JSONPatch{
-				Op:    PatchOpAdd,
-				Path:  PatchPathRestartAnnotation,
-				Value: time.Now().Format(time.RFC3339Nano),
-			})
-		}
-	}
+	return patchOps, needsRestart, nil
+}
 
-	if len(patchOps) == 0 {
-		return nil
+// buildLabelsPatch returns the JSON Patch operations that add the desired
+// labels missing from, or different in, the current labels.
+func buildLabelsPatch(current, desired map[string]string) []JSONPatch {
+	if len(current) == 0 {
+		if len(desired) == 0 {
+			return nil
+		}
+		return []JSONPatch{{Op: PatchOpAdd, Path: PatchPathLabels, Value: desired}}
 	}
-
-	// Apply all patches atomically
-	patchBytes, err := json.Marshal(patchOps)
-	if err != nil {
-		return fmt.Errorf("failed to marshal sync patch: %w", err)
+	keys := make([]string, 0, len(desired))
+	for key, value := range desired {
+		if currentValue, ok := current[key]; !ok || currentValue != value {
+			keys = append(keys, key)
+		}
 	}
-	if err := c.Patch(ctx, current, client.RawPatch(types.JSONPatchType, patchBytes)); err != nil {
-		return fmt.Errorf("failed to patch CNPG cluster: %w", err)
+	sort.Strings(keys)
+	patchOps := make([]JSONPatch, 0, len(keys))
+	for _, key := range keys {
+		patchOps = append(patchOps, JSONPatch{
+			Op:    PatchOpAdd,
+			Path:  PatchPathLabels + "/" + escapeJSONPointer(key),
+			Value: desired[key],
+		})
 	}
+	return patchOps
+}
 
-	if needsRestart {
-		logger.Info("Added restart annotation for non-extension update", "clusterName", current.Name)
+// additionalServices returns the additional managed Services of cluster.
+func additionalServices(cluster *cnpgv1.Cluster) []cnpgv1.ManagedService {
+	if cluster.Spec.Managed == nil || cluster.Spec.Managed.Services == nil {
+		return nil
 	}
-
-	return nil
+	return cluster.Spec.Managed.Services.Additional
 }
 
 // findExtensionImage returns the index and image reference for the documentdb extension.
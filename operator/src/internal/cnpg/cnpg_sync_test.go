@@ -411,6 +411,26 @@ var _ = Describe("SyncCnpgCluster - mutable spec fields", func() {
 		Expect(updated.Spec.StorageConfiguration.Size).To(Equal("20Gi"))
 	})
 
+	It("adds the WAL volume and grows it", func() {
+		current := baseCluster("test-cluster", namespace)
+		desired := current.DeepCopy()
+		desired.Spec.WalStorage = &cnpgv1.StorageConfiguration{Size: "5Gi"}
+
+		c := buildFakeClient(current).Build()
+		Expect(SyncCnpgCluster(context.Background(), c, current, desired, nil)).To(Succeed())
+
+		updated := &cnpgv1.Cluster{}
+		Expect(c.Get(context.Background(), types.NamespacedName{Name: "test-cluster", Namespace: namespace}, updated)).To(Succeed())
+		Expect(updated.Spec.WalStorage).ToNot(BeNil())
+		Expect(updated.Spec.WalStorage.Size).To(Equal("5Gi"))
+
+		desired = updated.DeepCopy()
+		desired.Spec.WalStorage.Size = "8Gi"
+		Expect(SyncCnpgCluster(context.Background(), c, updated, desired, nil)).To(Succeed())
+		Expect(c.Get(context.Background(), types.NamespacedName{Name: "test-cluster", Namespace: namespace}, updated)).To(Succeed())
+		Expect(updated.Spec.WalStorage.Size).To(Equal("8Gi"))
+	})
+
 	It("propagates postgresImage changes", func() {
 		current := baseCluster("test-cluster", namespace)
 		current.Spec.ImageName = "ghcr.io/cloudnative-pg/postgresql:17-minimal-trixie"
@@ -478,6 +498,219 @@ var _ = Describe("SyncCnpgCluster - mutable spec fields", func() {
 		Expect(updated.Annotations).ToNot(HaveKey("kubectl.kubernetes.io/restartedAt"))
 	})
 
+	It("propagates startDelay changes", func() {
+		current := baseCluster("test-cluster", namespace)
+		desired := current.DeepCopy()
+		desired.Spec.MaxStartDelay = 7200
+
+		c := buildFakeClient(current).Build()
+		err := SyncCnpgCluster(context.Background(), c, current, desired, nil)
+		Expect(err).ToNot(HaveOccurred())
+
+		updated := &cnpgv1.Cluster{}
+		Expect(c.Get(context.Background(), types.NamespacedName{Name: "test-cluster", Namespace: namespace}, updated)).To(Succeed())
+		Expect(updated.Spec.MaxStartDelay).To(Equal(int32(7200)))
+	})
+
+	It("propagates shutdown and failover timing changes", func() {
+		current := baseCluster("test-cluster", namespace)
+		desired := current.DeepCopy()
+		desired.Spec.SmartShutdownTimeout = pointer.Int32(20)
+		desired.Spec.MaxSwitchoverDelay = 600
+		desired.Spec.FailoverDelay = 15
+
+		c := buildFakeClient(current).Build()
+		Expect(SyncCnpgCluster(context.Background(), c, current, desired, nil)).To(Succeed())
+
+		updated := &cnpgv1.Cluster{}
+		Expect(c.Get(context.Background(), types.NamespacedName{Name: "test-cluster", Namespace: namespace}, updated)).To(Succeed())
+		Expect(updated.Spec.SmartShutdownTimeout).To(Equal(pointer.Int32(20)))
+		Expect(updated.Spec.MaxSwitchoverDelay).To(Equal(int32(600)))
+		Expect(updated.Spec.FailoverDelay).To(Equal(int32(15)))
+	})
+
+	It("replaces the additional plugins and keeps the sidecar injector", func() {
+		current := baseCluster("test-cluster", namespace)
+		current.Spec.Plugins = append(current.Spec.Plugins,
+			cnpgv1.PluginConfiguration{Name: "old.example.com", Enabled: pointer.Bool(true), IsWALArchiver: pointer.Bool(false)})
+		desired := current.DeepCopy()
+		desired.Spec.Plugins = append(desired.Spec.Plugins[:1], cnpgv1.PluginConfiguration{
+			Name:          "barman-cloud.cloudnative-pg.io",
+			Enabled:       pointer.Bool(true),
+			IsWALArchiver: pointer.Bool(true),
+			Parameters:    map[string]string{"barmanObjectName": "store"},
+		})
+
+		c := buildFakeClient(current).Build()
+		Expect(SyncCnpgCluster(context.Background(), c, current, desired, nil)).To(Succeed())
+
+		updated := &cnpgv1.Cluster{}
+		Expect(c.Get(context.Background(), types.NamespacedName{Name: "test-cluster", Namespace: namespace}, updated)).To(Succeed())
+		Expect(updated.Spec.Plugins).To(Equal(desired.Spec.Plugins))
+
+		desired.Spec.Plugins = desired.Spec.Plugins[:1]
+		Expect(SyncCnpgCluster(context.Background(), c, updated, desired, nil)).To(Succeed())
+		Expect(c.Get(context.Background(), types.NamespacedName{Name: "test-cluster", Namespace: namespace}, updated)).To(Succeed())
+		Expect(updated.Spec.Plugins).To(HaveLen(1))
+		Expect(updated.Spec.Plugins[0].Name).To(Equal(util.DEFAULT_SIDECAR_INJECTOR_PLUGIN))
+	})
+
+	It("propagates superuser access changes", func() {
+		current := baseCluster("test-cluster", namespace)
+		current.Spec.EnableSuperuserAccess = pointer.Bool(false)
+		desired := current.DeepCopy()
+		desired.Spec.EnableSuperuserAccess = pointer.Bool(true)
+		desired.Spec.SuperuserSecret = &cnpgv1.LocalObjectReference{Name: "my-superuser"}
+
+		c := buildFakeClient(current).Build()
+		Expect(SyncCnpgCluster(context.Background(), c, current, desired, nil)).To(Succeed())
+
+		updated := &cnpgv1.Cluster{}
+		Expect(c.Get(context.Background(), types.NamespacedName{Name: "test-cluster", Namespace: namespace}, updated)).To(Succeed())
+		Expect(updated.Spec.EnableSuperuserAccess).To(Equal(pointer.Bool(true)))
+		Expect(updated.Spec.SuperuserSecret).To(Equal(desired.Spec.SuperuserSecret))
+
+		desired.Spec.EnableSuperuserAccess = pointer.Bool(false)
+		desired.Spec.SuperuserSecret = nil
+		Expect(SyncCnpgCluster(context.Background(), c, updated, desired, nil)).To(Succeed())
+		Expect(c.Get(context.Background(), types.NamespacedName{Name: "test-cluster", Namespace: namespace}, updated)).To(Succeed())
+		Expect(updated.Spec.EnableSuperuserAccess).To(Equal(pointer.Bool(false)))
+		Expect(updated.Spec.SuperuserSecret).To(BeNil())
+	})
+
+	It("propagates image pull policy changes", func() {
+		current := baseCluster("test-cluster", namespace)
+		current.Spec.Plugins[0].Parameters[util.PLUGIN_PARAM_GATEWAY_IMAGE_PULL_POLICY] = "Always"
+		desired := current.DeepCopy()
+		desired.Spec.ImagePullPolicy = corev1.PullIfNotPresent
+		desired.Spec.PostgresConfiguration.Extensions[0].ImageVolumeSource.PullPolicy = corev1.PullIfNotPresent
+		desired.Spec.Plugins[0].Parameters[util.PLUGIN_PARAM_GATEWAY_IMAGE_PULL_POLICY] = "IfNotPresent"
+
+		c := buildFakeClient(current).Build()
+		Expect(SyncCnpgCluster(context.Background(), c, current, desired, nil)).To(Succeed())
+
+		updated := &cnpgv1.Cluster{}
+		Expect(c.Get(context.Background(), types.NamespacedName{Name: "test-cluster", Namespace: namespace}, updated)).To(Succeed())
+		Expect(updated.Spec.ImagePullPolicy).To(Equal(corev1.PullIfNotPresent))
+		Expect(updated.Spec.PostgresConfiguration.Extensions[0].ImageVolumeSource.PullPolicy).To(Equal(corev1.PullIfNotPresent))
+		Expect(updated.Spec.Plugins[0].Parameters).To(HaveKeyWithValue(util.PLUGIN_PARAM_GATEWAY_IMAGE_PULL_POLICY, "IfNotPresent"))
+
+		// The plugin defaults the gateway pull policy, so unsetting it leaves it alone.
+		desired = baseCluster("test-cluster", namespace)
+		Expect(SyncCnpgCluster(context.Background(), c, updated, desired, nil)).To(Succeed())
+		Expect(c.Get(context.Background(), types.NamespacedName{Name: "test-cluster", Namespace: namespace}, updated)).To(Succeed())
+		Expect(updated.Spec.ImagePullPolicy).To(BeEmpty())
+		Expect(updated.Spec.PostgresConfiguration.Extensions[0].ImageVolumeSource.PullPolicy).To(BeEmpty())
+		Expect(updated.Spec.Plugins[0].Parameters).To(HaveKeyWithValue(util.PLUGIN_PARAM_GATEWAY_IMAGE_PULL_POLICY, "IfNotPresent"))
+	})
+
+	It("propagates primary update strategy and method changes", func() {
+		current := baseCluster("test-cluster", namespace)
+		current.Spec.PrimaryUpdateStrategy = cnpgv1.PrimaryUpdateStrategyUnsupervised
+		current.Spec.PrimaryUpdateMethod = cnpgv1.PrimaryUpdateMethodSwitchover
+		desired := current.DeepCopy()
+		desired.Spec.PrimaryUpdateStrategy = cnpgv1.PrimaryUpdateStrategySupervised
+		desired.Spec.PrimaryUpdateMethod = cnpgv1.PrimaryUpdateMethodRestart
+
+		c := buildFakeClient(current).Build()
+		Expect(SyncCnpgCluster(context.Background(), c, current, desired, nil)).To(Succeed())
+
+		updated := &cnpgv1.Cluster{}
+		Expect(c.Get(context.Background(), types.NamespacedName{Name: "test-cluster", Namespace: namespace}, updated)).To(Succeed())
+		Expect(updated.Spec.PrimaryUpdateStrategy).To(Equal(cnpgv1.PrimaryUpdateStrategySupervised))
+		Expect(updated.Spec.PrimaryUpdateMethod).To(Equal(cnpgv1.PrimaryUpdateMethodRestart))
+	})
+
+	It("propagates node maintenance window changes and removals", func() {
+		current := baseCluster("test-cluster", namespace)
+		desired := current.DeepCopy()
+		desired.Spec.NodeMaintenanceWindow = &cnpgv1.NodeMaintenanceWindow{InProgress: true, ReusePVC: pointer.Bool(false)}
+
+		c := buildFakeClient(current).Build()
+		Expect(SyncCnpgCluster(context.Background(), c, current, desired, nil)).To(Succeed())
+
+		updated := &cnpgv1.Cluster{}
+		Expect(c.Get(context.Background(), types.NamespacedName{Name: "test-cluster", Namespace: namespace}, updated)).To(Succeed())
+		Expect(updated.Spec.NodeMaintenanceWindow).To(Equal(desired.Spec.NodeMaintenanceWindow))
+
+		desired.Spec.NodeMaintenanceWindow = nil
+		Expect(SyncCnpgCluster(context.Background(), c, updated, desired, nil)).To(Succeed())
+		Expect(c.Get(context.Background(), types.NamespacedName{Name: "test-cluster", Namespace: namespace}, updated)).To(Succeed())
+		Expect(updated.Spec.NodeMaintenanceWindow).To(BeNil())
+	})
+
+	It("propagates additional service changes and removals", func() {
+		current := baseCluster("test-cluster", namespace)
+		current.Spec.Managed = nil
+		desired := current.DeepCopy()
+		desired.Spec.Managed = &cnpgv1.ManagedConfiguration{
+			Services: &cnpgv1.ManagedServices{Additional: []cnpgv1.ManagedService{{
+				SelectorType:    cnpgv1.ServiceSelectorTypeRO,
+				UpdateStrategy:  cnpgv1.ServiceUpdateStrategyPatch,
+				ServiceTemplate: cnpgv1.ServiceTemplateSpec{ObjectMeta: cnpgv1.Metadata{Name: "test-cluster-reads"}},
+			}}},
+		}
+
+		c := buildFakeClient(current).Build()
+		Expect(SyncCnpgCluster(context.Background(), c, current, desired, nil)).To(Succeed())
+
+		updated := &cnpgv1.Cluster{}
+		Expect(c.Get(context.Background(), types.NamespacedName{Name: "test-cluster", Namespace: namespace}, updated)).To(Succeed())
+		Expect(updated.Spec.Managed).To(Equal(desired.Spec.Managed))
+
+		desired.Spec.Managed.Services.Additional[0].SelectorType = cnpgv1.ServiceSelectorTypeR
+		Expect(SyncCnpgCluster(context.Background(), c, updated, desired, nil)).To(Succeed())
+		Expect(c.Get(context.Background(), types.NamespacedName{Name: "test-cluster", Namespace: namespace}, updated)).To(Succeed())
+		Expect(updated.Spec.Managed).To(Equal(desired.Spec.Managed))
+
+		desired.Spec.Managed = nil
+		Expect(SyncCnpgCluster(context.Background(), c, updated, desired, nil)).To(Succeed())
+		Expect(c.Get(context.Background(), types.NamespacedName{Name: "test-cluster", Namespace: namespace}, updated)).To(Succeed())
+		Expect(additionalServices(updated)).To(BeEmpty())
+	})
+
+	It("adds additional services below managed roles", func() {
+		current := baseCluster("test-cluster", namespace)
+		current.Spec.Managed = &cnpgv1.ManagedConfiguration{Roles: []cnpgv1.RoleConfiguration{{Name: "app"}}}
+		desired := current.DeepCopy()
+		desired.Spec.Managed.Services = &cnpgv1.ManagedServices{Additional: []cnpgv1.ManagedService{{
+			SelectorType:    cnpgv1.ServiceSelectorTypeRW,
+			UpdateStrategy:  cnpgv1.ServiceUpdateStrategyPatch,
+			ServiceTemplate: cnpgv1.ServiceTemplateSpec{ObjectMeta: cnpgv1.Metadata{Name: "test-cluster-writes"}},
+		}}}
+
+		patchOps, _, err := BuildSyncPatch(current, desired)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(patchOps).To(ContainElement(HaveField("Path", PatchPathManagedServices)))
+
+		c := buildFakeClient(current).Build()
+		Expect(SyncCnpgCluster(context.Background(), c, current, desired, nil)).To(Succeed())
+
+		updated := &cnpgv1.Cluster{}
+		Expect(c.Get(context.Background(), types.NamespacedName{Name: "test-cluster", Namespace: namespace}, updated)).To(Succeed())
+		Expect(updated.Spec.Managed).To(Equal(desired.Spec.Managed))
+	})
+
+	It("propagates probe changes and removals", func() {
+		current := baseCluster("test-cluster", namespace)
+		desired := current.DeepCopy()
+		desired.Spec.Probes = &cnpgv1.ProbesConfiguration{
+			Readiness: &cnpgv1.ProbeWithStrategy{Probe: cnpgv1.Probe{FailureThreshold: 10}},
+		}
+
+		c := buildFakeClient(current).Build()
+		Expect(SyncCnpgCluster(context.Background(), c, current, desired, nil)).To(Succeed())
+
+		updated := &cnpgv1.Cluster{}
+		Expect(c.Get(context.Background(), types.NamespacedName{Name: "test-cluster", Namespace: namespace}, updated)).To(Succeed())
+		Expect(updated.Spec.Probes).To(Equal(desired.Spec.Probes))
+
+		desired.Spec.Probes = nil
+		Expect(SyncCnpgCluster(context.Background(), c, updated, desired, nil)).To(Succeed())
+		Expect(c.Get(context.Background(), types.NamespacedName{Name: "test-cluster", Namespace: namespace}, updated)).To(Succeed())
+		Expect(updated.Spec.Probes).To(BeNil())
+	})
+
 	It("propagates pgHBA changes", func() {
 		current := baseCluster("test-cluster", namespace)
 		current.Spec.PostgresConfiguration.PgHBA = nil
@@ -690,3 +923,139 @@ var _ = Describe("Helper functions", func() {
 		Expect(getParam(m, "key")).To(BeEmpty())
 	})
 })
+
+var _ = Describe("BuildSyncPatch", func() {
+	It("returns no operations when current matches desired", func() {
+		current := baseCluster("test", "test-ns")
+		ops, needsRestart, err := BuildSyncPatch(current, current.DeepCopy())
+		Expect(err).ToNot(HaveOccurred())
+		Expect(ops).To(BeEmpty())
+		Expect(needsRestart).To(BeFalse())
+	})
+
+	It("reports a restart for gateway-only changes without adding the annotation", func() {
+		current := baseCluster("test", "test-ns")
+		desired := current.DeepCopy()
+		desired.Spec.Plugins[0].Parameters["gatewayImage"] = "ghcr.io/documentdb/gateway:0.111.0"
+
+		ops, needsRestart, err := BuildSyncPatch(current, desired)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(needsRestart).To(BeTrue())
+		Expect(ops).To(HaveLen(1))
+		Expect(ops[0].Path).To(Equal("/spec/plugins/0/parameters/gatewayImage"))
+	})
+
+	It("syncs the inherited metadata and adds the desired cluster labels", func() {
+		current := baseCluster("test", "test-ns")
+		current.Labels = map[string]string{"team": "old", "other": "kept"}
+		desired := current.DeepCopy()
+		desired.Labels = map[string]string{"team": "payments", "example.com/cost-center": "cc-42"}
+		desired.Spec.InheritedMetadata = &cnpgv1.EmbeddedObjectMetadata{Labels: map[string]string{"team": "payments"}}
+
+		ops, needsRestart, err := BuildSyncPatch(current, desired)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(needsRestart).To(BeFalse())
+		Expect(ops).To(ConsistOf(
+			JSONPatch{Op: PatchOpAdd, Path: PatchPathInheritedMetadata, Value: desired.Spec.InheritedMetadata},
+			JSONPatch{Op: PatchOpAdd, Path: "/metadata/labels/example.com~1cost-center", Value: "cc-42"},
+			JSONPatch{Op: PatchOpAdd, Path: "/metadata/labels/team", Value: "payments"},
+		))
+
+		c := buildFakeClient(current).Build()
+		Expect(SyncCnpgCluster(context.Background(), c, current, desired, nil)).To(Succeed())
+		updated := &cnpgv1.Cluster{}
+		Expect(c.Get(context.Background(), types.NamespacedName{Name: "test", Namespace: "test-ns"}, updated)).To(Succeed())
+		Expect(updated.Labels).To(Equal(map[string]string{"team": "payments", "example.com/cost-center": "cc-42", "other": "kept"}))
+		Expect(updated.Spec.InheritedMetadata.Labels).To(HaveKeyWithValue("team", "payments"))
+	})
+
+	It("syncs the ServiceAccount template and pull secrets", func() {
+		current := baseCluster("test", "test-ns")
+		desired := current.DeepCopy()
+		desired.Spec.ServiceAccountTemplate = &cnpgv1.ServiceAccountTemplate{
+			Metadata: cnpgv1.Metadata{Annotations: map[string]string{"azure.workload.identity/client-id": "id"}},
+		}
+		desired.Spec.ImagePullSecrets = []cnpgv1.LocalObjectReference{{Name: "mirror"}}
+
+		ops, needsRestart, err := BuildSyncPatch(current, desired)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(needsRestart).To(BeFalse())
+		Expect(ops).To(ConsistOf(
+			JSONPatch{Op: PatchOpAdd, Path: PatchPathServiceAccount, Value: desired.Spec.ServiceAccountTemplate},
+			JSONPatch{Op: PatchOpAdd, Path: PatchPathImagePullSecrets, Value: desired.Spec.ImagePullSecrets},
+		))
+
+		ops, _, err = BuildSyncPatch(desired, current)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(ops).To(ContainElement(JSONPatch{Op: PatchOpRemove, Path: PatchPathServiceAccount}))
+	})
+
+	It("syncs the security contexts of the instance pods", func() {
+		current := baseCluster("test", "test-ns")
+		desired := current.DeepCopy()
+		desired.Spec.SeccompProfile = &corev1.SeccompProfile{Type: corev1.SeccompProfileTypeRuntimeDefault}
+		desired.Spec.PodSecurityContext = &corev1.PodSecurityContext{RunAsUser: pointer.Int64(2000)}
+		desired.Spec.SecurityContext = &corev1.SecurityContext{ReadOnlyRootFilesystem: pointer.Bool(false)}
+
+		ops, _, err := BuildSyncPatch(current, desired)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(ops).To(ConsistOf(
+			JSONPatch{Op: PatchOpAdd, Path: PatchPathSeccompProfile, Value: desired.Spec.SeccompProfile},
+			JSONPatch{Op: PatchOpAdd, Path: PatchPathPodSecurityContext, Value: desired.Spec.PodSecurityContext},
+			JSONPatch{Op: PatchOpAdd, Path: PatchPathSecurityContext, Value: desired.Spec.SecurityContext},
+		))
+
+		ops, _, err = BuildSyncPatch(desired, current)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(ops).To(ConsistOf(
+			JSONPatch{Op: PatchOpRemove, Path: PatchPathSeccompProfile},
+			JSONPatch{Op: PatchOpRemove, Path: PatchPathPodSecurityContext},
+			JSONPatch{Op: PatchOpRemove, Path: PatchPathSecurityContext},
+		))
+	})
+
+	Context("with full drift reconciliation", func() {
+		AfterEach(func() {
+			util.SetOperatorSettings(nil)
+		})
+
+		It("leaves untargeted fields alone by default", func() {
+			desired := baseCluster("test", "test-ns")
+			desired.Spec.Description = "DocumentDB cluster"
+			current := desired.DeepCopy()
+			current.Spec.Description = "edited"
+
+			ops, _, err := BuildSyncPatch(current, desired)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(ops).To(BeEmpty())
+		})
+
+		It("reverts every drifted field once enabled", func() {
+			util.SetOperatorSettings(map[string]string{util.DRIFT_RECONCILIATION_ENV: util.DRIFT_RECONCILIATION_FULL})
+			desired := baseCluster("test", "test-ns")
+			desired.Spec.Description = "DocumentDB cluster"
+			desired.Spec.InheritedMetadata = &cnpgv1.EmbeddedObjectMetadata{Labels: map[string]string{"app": "documentdb"}}
+			current := desired.DeepCopy()
+			current.Spec.Description = "edited"
+			current.Spec.InheritedMetadata = nil
+			current.Spec.Instances = 3
+
+			ops, _, err := BuildSyncPatch(current, desired)
+			Expect(err).ToNot(HaveOccurred())
+			paths := make([]string, 0, len(ops))
+			for _, op := range ops {
+				paths = append(paths, op.Path)
+			}
+			// /spec/instances is patched once, by the targeted sync
+			Expect(paths).To(ConsistOf(PatchPathInstances, "/spec/description", "/spec/inheritedMetadata"))
+
+			c := buildFakeClient(current).Build()
+			Expect(SyncCnpgCluster(context.Background(), c, current, desired, nil)).To(Succeed())
+			updated := &cnpgv1.Cluster{}
+			Expect(c.Get(context.Background(), types.NamespacedName{Name: "test", Namespace: "test-ns"}, updated)).To(Succeed())
+			Expect(updated.Spec.Description).To(Equal("DocumentDB cluster"))
+			Expect(updated.Spec.InheritedMetadata.Labels).To(HaveKeyWithValue("app", "documentdb"))
+			Expect(updated.Spec.Instances).To(Equal(1))
+		})
+	})
+})
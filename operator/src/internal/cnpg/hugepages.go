@@ -0,0 +1,119 @@
+// Copyright (c) Microsoft Corporation.
+// Licensed under the MIT License.
+
+package cnpg
+
+import (
+	"fmt"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	"k8s.io/apimachinery/pkg/util/validation/field"
+
+	dbpreview "github.com/documentdb/documentdb-operator/api/preview"
+)
+
+// defaultHugePageSize is the page size of spec.resource.hugePages when unset.
+const defaultHugePageSize = "2Mi"
+
+// hugePageSizes maps the supported page sizes to the huge_page_size value
+// PostgreSQL expects.
+var hugePageSizes = map[string]string{
+	"2Mi": "2MB",
+	"1Gi": "1GB",
+}
+
+// hugePages returns the resource name and amount of the huge pages requested
+// by spec.resource.hugePages, if any and valid.
+func hugePages(documentdb *dbpreview.DocumentDB) (corev1.ResourceName, resource.Quantity, bool) {
+	config := documentdb.Spec.Resource.HugePages
+	if config == nil {
+		return "", resource.Quantity{}, false
+	}
+	pageSize := config.PageSize
+	if pageSize == "" {
+		pageSize = defaultHugePageSize
+	}
+	size, err := resource.ParseQuantity(config.Size)
+	if err != nil || size.Sign() <= 0 {
+		return "", resource.Quantity{}, false
+	}
+	return corev1.ResourceName(corev1.ResourceHugePagesPrefix + pageSize), size, true
+}
+
+// applyHugePages adds the huge pages of documentdb to the requests and limits
+// of the PostgreSQL container, which Kubernetes requires to be equal.
+func applyHugePages(resources *corev1.ResourceRequirements, documentdb *dbpreview.DocumentDB) {
+	name, size, ok := hugePages(documentdb)
+	if !ok {
+		return
+	}
+	if resources.Requests == nil {
+		resources.Requests = corev1.ResourceList{}
+	}
+	if resources.Limits == nil {
+		resources.Limits = corev1.ResourceList{}
+	}
+	resources.Requests[name] = size
+	resources.Limits[name] = size
+}
+
+// hugePagesParameters returns the parameters making PostgreSQL allocate its
+// shared memory from the huge pages of documentdb. huge_pages=on makes a
+// shortage of pages fail the start of PostgreSQL instead of silently
+// falling back to regular memory.
+func hugePagesParameters(documentdb *dbpreview.DocumentDB) map[string]string {
+	name, _, ok := hugePages(documentdb)
+	if !ok {
+		return nil
+	}
+	return map[string]string{
+		"huge_pages":     "on",
+		"huge_page_size": hugePageSizes[string(name)[len(corev1.ResourceHugePagesPrefix):]],
+	}
+}
+
+// hugePagesSharedBuffers returns the default shared_buffers of a cluster with
+// huge pages: three quarters of them, the rest being left to the other
+// shared memory of PostgreSQL, such as the WAL buffers and the lock tables.
+func hugePagesSharedBuffers(documentdb *dbpreview.DocumentDB) (string, bool) {
+	_, size, ok := hugePages(documentdb)
+	if !ok {
+		return "", false
+	}
+	return formatMB(size.Value() * 3 / 4 / (1024 * 1024)), true
+}
+
+// ValidateHugePages ensures spec.resource.hugePages requests a whole number
+// of pages, and that PostgreSQL has a memory or CPU request, without which
+// Kubernetes rejects a pod requesting huge pages.
+func ValidateHugePages(documentdb *dbpreview.DocumentDB) (allErrs field.ErrorList) {
+	res := documentdb.Spec.Resource
+	config := res.HugePages
+	if config == nil {
+		return nil
+	}
+	path := field.NewPath("spec", "resource", "hugePages")
+	pageSize := config.PageSize
+	if pageSize == "" {
+		pageSize = defaultHugePageSize
+	}
+	if _, ok := hugePageSizes[pageSize]; !ok {
+		allErrs = append(allErrs, field.NotSupported(path.Child("pageSize"), config.PageSize, []string{"2Mi", "1Gi"}))
+		return allErrs
+	}
+	size, err := resource.ParseQuantity(config.Size)
+	page := resource.MustParse(pageSize)
+	switch {
+	case err != nil || size.Sign() <= 0:
+		allErrs = append(allErrs, field.Invalid(path.Child("size"), config.Size, "size must be a positive quantity, e.g. 2Gi"))
+	case size.Value()%page.Value() != 0:
+		allErrs = append(allErrs, field.Invalid(path.Child("size"), config.Size,
+			fmt.Sprintf("size must be a multiple of the page size %s", pageSize)))
+	}
+	if !isSet(res.Memory) && !isSet(res.CPU) && !componentMemSet(res.Database) && !componentCPUSet(res.Database) {
+		allErrs = append(allErrs, field.Required(field.NewPath("spec", "resource", "memory"),
+			"huge pages require a memory or cpu request for PostgreSQL"))
+	}
+	return allErrs
+}
@@ -0,0 +1,83 @@
+// Copyright (c) Microsoft Corporation.
+// Licensed under the MIT License.
+
+package cnpg
+
+import (
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+
+	dbpreview "github.com/documentdb/documentdb-operator/api/preview"
+)
+
+var _ = Describe("HugePages", func() {
+	var documentdb *dbpreview.DocumentDB
+
+	BeforeEach(func() {
+		documentdb = &dbpreview.DocumentDB{Spec: dbpreview.DocumentDBSpec{Resource: dbpreview.Resource{
+			Memory:    "4Gi",
+			HugePages: &dbpreview.HugePagesConfiguration{Size: "2Gi"},
+		}}}
+	})
+
+	It("requests the huge pages as both request and limit of PostgreSQL", func() {
+		resources := corev1.ResourceRequirements{
+			Requests: corev1.ResourceList{corev1.ResourceMemory: resource.MustParse("3Gi")},
+		}
+		applyHugePages(&resources, documentdb)
+		Expect(resources.Requests).To(HaveKeyWithValue(corev1.ResourceName("hugepages-2Mi"), resource.MustParse("2Gi")))
+		Expect(resources.Limits).To(HaveKeyWithValue(corev1.ResourceName("hugepages-2Mi"), resource.MustParse("2Gi")))
+		Expect(resources.Requests).To(HaveKey(corev1.ResourceMemory))
+	})
+
+	It("leaves the resources alone without huge pages", func() {
+		documentdb.Spec.Resource.HugePages = nil
+		resources := corev1.ResourceRequirements{}
+		applyHugePages(&resources, documentdb)
+		Expect(resources.Requests).To(BeNil())
+		Expect(resources.Limits).To(BeNil())
+	})
+
+	It("makes PostgreSQL use the huge pages for its shared memory", func() {
+		documentdb.Spec.Resource.HugePages.PageSize = "1Gi"
+		params := MergeParameters(documentdb, 4*1024*1024*1024)
+		Expect(params).To(HaveKeyWithValue("huge_pages", "on"))
+		Expect(params).To(HaveKeyWithValue("huge_page_size", "1GB"))
+		Expect(params).To(HaveKeyWithValue("shared_buffers", "1536MB"))
+	})
+
+	It("keeps a shared_buffers set by the user", func() {
+		documentdb.Spec.Postgres = &dbpreview.PostgresSpec{Parameters: map[string]string{"shared_buffers": "1GB"}}
+		Expect(MergeParameters(documentdb, 4*1024*1024*1024)).To(HaveKeyWithValue("shared_buffers", "1GB"))
+	})
+
+	It("does not set huge_pages without huge pages", func() {
+		documentdb.Spec.Resource.HugePages = nil
+		Expect(MergeParameters(documentdb, 4*1024*1024*1024)).ToNot(HaveKey("huge_pages"))
+	})
+
+	Describe("ValidateHugePages", func() {
+		It("accepts a whole number of pages", func() {
+			Expect(ValidateHugePages(documentdb)).To(BeEmpty())
+		})
+
+		It("rejects a size that is not a multiple of the page size", func() {
+			documentdb.Spec.Resource.HugePages = &dbpreview.HugePagesConfiguration{PageSize: "1Gi", Size: "1536Mi"}
+			errs := ValidateHugePages(documentdb)
+			Expect(errs).To(HaveLen(1))
+			Expect(errs[0].Field).To(Equal("spec.resource.hugePages.size"))
+		})
+
+		It("requires a memory or cpu request for PostgreSQL", func() {
+			documentdb.Spec.Resource.Memory = ""
+			errs := ValidateHugePages(documentdb)
+			Expect(errs).To(HaveLen(1))
+			Expect(errs[0].Field).To(Equal("spec.resource.memory"))
+
+			documentdb.Spec.Resource.Database = &dbpreview.ComponentResources{CPU: "2"}
+			Expect(ValidateHugePages(documentdb)).To(BeEmpty())
+		})
+	})
+})
@@ -5,10 +5,18 @@ package cnpg
 
 import (
 	"fmt"
+	"strconv"
+
+	"k8s.io/apimachinery/pkg/api/resource"
 
 	dbpreview "github.com/documentdb/documentdb-operator/api/preview"
+	util "github.com/documentdb/documentdb-operator/internal/utils"
 )
 
+// reservedReplicationSlots is the number of replication slots and WAL
+// senders reserved for replication.
+const reservedReplicationSlots = 10
+
 // formatMB formats a megabyte value as a PostgreSQL size string.
 // Values >= 1024 MB that are evenly divisible by 1024 are expressed in GB.
 func formatMB(mb int64) string {
@@ -83,22 +91,61 @@ func StaticDefaults() map[string]string {
 func ProtectedParameters(documentdb *dbpreview.DocumentDB) map[string]string {
 	params := map[string]string{
 		"cron.database_name":        "postgres",
-		"max_replication_slots":     "10",
-		"max_wal_senders":           "10",
+		"max_replication_slots":     strconv.Itoa(reservedReplicationSlots),
+		"max_wal_senders":           strconv.Itoa(reservedReplicationSlots),
 		"max_prepared_transactions": "100",
 	}
+	for _, capability := range util.EnabledCapabilities(documentdb) {
+		for k, v := range capability.Parameters {
+			params[k] = v
+		}
+	}
 	if dbpreview.IsFeatureGateEnabled(documentdb, dbpreview.FeatureGateChangeStreams) {
-		params["wal_level"] = "logical"
+		for k, v := range changeStreamsParameters(documentdb.Spec.ChangeStreams) {
+			params[k] = v
+		}
 	}
-	if dbpreview.IsFeatureGateEnabled(documentdb, dbpreview.FeatureGateIOUring) {
-		params["io_method"] = "io_uring"
+	for k, v := range ttlParameters(documentdb.Spec.TTL) {
+		params[k] = v
+	}
+	for k, v := range hugePagesParameters(documentdb) {
+		params[k] = v
 	}
 	return params
 }
 
+// changeStreamsParameters returns the parameters set by spec.changeStreams.
+// MaxCursors raises the replication slots and WAL senders reserved above.
+func changeStreamsParameters(config *dbpreview.ChangeStreamsConfiguration) map[string]string {
+	if config == nil {
+		return nil
+	}
+	params := map[string]string{}
+	if config.RetentionHours > 0 {
+		params["idle_replication_slot_timeout"] = fmt.Sprintf("%dh", config.RetentionHours)
+	}
+	if config.MaxCursors > 0 {
+		slots := strconv.Itoa(reservedReplicationSlots + int(config.MaxCursors))
+		params["max_replication_slots"] = slots
+		params["max_wal_senders"] = slots
+	}
+	if size, err := resource.ParseQuantity(config.MaxRetainedWALSize); err == nil {
+		params["max_slot_wal_keep_size"] = formatMB(size.Value() / (1024 * 1024))
+	}
+	return params
+}
+
+// ttlParameters returns the parameters set by spec.ttl.
+func ttlParameters(config *dbpreview.TTLConfiguration) map[string]string {
+	if config == nil || config.BatchSize == 0 {
+		return nil
+	}
+	return map[string]string{"documentdb.maxTTLDeleteBatchSize": strconv.Itoa(int(config.BatchSize))}
+}
+
 // MergeParameters merges all parameter sources in priority order (last write wins):
 // 1. StaticDefaults
-// 2. ComputeMemoryAwareDefaults
+// 2. ComputeMemoryAwareDefaults, with shared_buffers sized to the huge pages
 // 3. User overrides (documentdb.Spec.Postgres.Parameters)
 // 4. ProtectedParameters (always wins)
 func MergeParameters(documentdb *dbpreview.DocumentDB, memoryLimitBytes int64) map[string]string {
@@ -110,6 +157,9 @@ func MergeParameters(documentdb *dbpreview.DocumentDB, memoryLimitBytes int64) m
 	for k, v := range ComputeMemoryAwareDefaults(memoryLimitBytes) {
 		result[k] = v
 	}
+	if sharedBuffers, ok := hugePagesSharedBuffers(documentdb); ok {
+		result["shared_buffers"] = sharedBuffers
+	}
 	if documentdb.Spec.Postgres != nil {
 		for k, v := range documentdb.Spec.Postgres.Parameters {
 			result[k] = v
@@ -225,6 +225,95 @@ var _ = Describe("ProtectedParameters", func() {
 		})
 	})
 
+	Context("with ChangeStreams tuned", func() {
+		var documentdb *dbpreview.DocumentDB
+
+		BeforeEach(func() {
+			documentdb = &dbpreview.DocumentDB{
+				Spec: dbpreview.DocumentDBSpec{
+					FeatureGates: map[string]bool{
+						dbpreview.FeatureGateChangeStreams: true,
+					},
+					ChangeStreams: &dbpreview.ChangeStreamsConfiguration{
+						RetentionHours:     48,
+						MaxCursors:         20,
+						MaxRetainedWALSize: "20Gi",
+					},
+				},
+			}
+		})
+
+		It("renders spec.changeStreams into the parameters", func() {
+			result := ProtectedParameters(documentdb)
+			Expect(result).To(HaveKeyWithValue("wal_level", "logical"))
+			Expect(result).To(HaveKeyWithValue("idle_replication_slot_timeout", "48h"))
+			Expect(result).To(HaveKeyWithValue("max_replication_slots", "30"))
+			Expect(result).To(HaveKeyWithValue("max_wal_senders", "30"))
+			Expect(result).To(HaveKeyWithValue("max_slot_wal_keep_size", "20GB"))
+		})
+
+		It("keeps the PostgreSQL defaults of unset fields", func() {
+			documentdb.Spec.ChangeStreams = &dbpreview.ChangeStreamsConfiguration{MaxRetainedWALSize: "512Mi"}
+			result := ProtectedParameters(documentdb)
+			Expect(result).To(HaveKeyWithValue("max_slot_wal_keep_size", "512MB"))
+			Expect(result).To(HaveKeyWithValue("max_replication_slots", "10"))
+			Expect(result).NotTo(HaveKey("idle_replication_slot_timeout"))
+		})
+
+		It("ignores spec.changeStreams without the feature gate", func() {
+			documentdb.Spec.FeatureGates = nil
+			result := ProtectedParameters(documentdb)
+			Expect(result).NotTo(HaveKey("idle_replication_slot_timeout"))
+			Expect(result).NotTo(HaveKey("max_slot_wal_keep_size"))
+			Expect(result).To(HaveKeyWithValue("max_replication_slots", "10"))
+		})
+	})
+
+	Context("with VectorSearch enabled", func() {
+		var result map[string]string
+
+		BeforeEach(func() {
+			documentdb := &dbpreview.DocumentDB{
+				Spec: dbpreview.DocumentDBSpec{
+					FeatureGates: map[string]bool{
+						dbpreview.FeatureGateVectorSearch: true,
+					},
+				},
+			}
+			result = ProtectedParameters(documentdb)
+		})
+
+		It("enables HNSW indexes and pre-filtering", func() {
+			Expect(result).To(HaveKeyWithValue("documentdb.enableVectorHNSWIndex", "true"))
+			Expect(result).To(HaveKeyWithValue("documentdb.enableVectorPreFilter", "true"))
+		})
+
+		It("does not enable other features", func() {
+			Expect(result).NotTo(HaveKey("wal_level"))
+			Expect(result).NotTo(HaveKey("io_method"))
+		})
+	})
+
+	Context("with spec.ttl", func() {
+		It("sets the TTL batch size", func() {
+			documentdb := &dbpreview.DocumentDB{
+				Spec: dbpreview.DocumentDBSpec{
+					TTL: &dbpreview.TTLConfiguration{IntervalSeconds: 10, BatchSize: 5000},
+				},
+			}
+			Expect(ProtectedParameters(documentdb)).To(HaveKeyWithValue("documentdb.maxTTLDeleteBatchSize", "5000"))
+		})
+
+		It("leaves the batch size to the extension when unset", func() {
+			documentdb := &dbpreview.DocumentDB{
+				Spec: dbpreview.DocumentDBSpec{
+					TTL: &dbpreview.TTLConfiguration{IntervalSeconds: 10},
+				},
+			}
+			Expect(ProtectedParameters(documentdb)).NotTo(HaveKey("documentdb.maxTTLDeleteBatchSize"))
+		})
+	})
+
 	Context("with IOUring enabled", func() {
 		var result map[string]string
 
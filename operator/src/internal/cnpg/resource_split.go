@@ -4,7 +4,6 @@
 package cnpg
 
 import (
-	"os"
 	"strconv"
 
 	"k8s.io/apimachinery/pkg/api/resource"
@@ -71,12 +70,12 @@ type ResourceSplit struct {
 // DefaultSplitConfig loads the carve-out configuration from the operator
 // environment, falling back to the documented production defaults.
 func DefaultSplitConfig() SplitConfig {
-	frac := parseFloatOr(os.Getenv(util.GATEWAY_MEMORY_FRACTION_ENV), util.DEFAULT_GATEWAY_MEMORY_FRACTION)
-	capBytes := parseQuantityOr(os.Getenv(util.GATEWAY_MEMORY_CAP_ENV), util.DEFAULT_GATEWAY_MEMORY_CAP)
+	frac := parseFloatOr(util.GetOperatorSetting(util.GATEWAY_MEMORY_FRACTION_ENV), util.DEFAULT_GATEWAY_MEMORY_FRACTION)
+	capBytes := parseQuantityOr(util.GetOperatorSetting(util.GATEWAY_MEMORY_CAP_ENV), util.DEFAULT_GATEWAY_MEMORY_CAP)
 	return SplitConfig{
 		GatewayMemoryFraction: frac,
 		GatewayMemoryCapBytes: capBytes,
-		GatewayCPULimit:       os.Getenv(util.GATEWAY_CPU_LIMIT_ENV),
+		GatewayCPULimit:       util.GetOperatorSetting(util.GATEWAY_CPU_LIMIT_ENV),
 		OTelMemoryRequest:     envOr(util.OTEL_MEMORY_REQUEST_ENV, util.DEFAULT_OTEL_MEMORY_REQUEST),
 		OTelMemoryLimit:       envOr(util.OTEL_MEMORY_LIMIT_ENV, util.DEFAULT_OTEL_MEMORY_LIMIT),
 		OTelCPURequest:        envOr(util.OTEL_CPU_REQUEST_ENV, util.DEFAULT_OTEL_CPU_REQUEST),
@@ -236,7 +235,7 @@ func normalizeCPU(cpu string) string {
 }
 
 func envOr(envKey, fallback string) string {
-	if v := os.Getenv(envKey); v != "" {
+	if v := util.GetOperatorSetting(envKey); v != "" {
 		return v
 	}
 	return fallback
@@ -27,6 +27,8 @@ import (
 //     omitted; it falls back to an envelope-independent default.
 //   - If neither the envelope nor any container sets the dimension, it is left
 //     unmanaged (no error).
+//
+// Huge pages are checked by ValidateHugePages.
 func ValidateResources(documentdb *dbpreview.DocumentDB, cfg SplitConfig) field.ErrorList {
 	res := documentdb.Spec.Resource
 	monitoring := documentdb.Spec.Monitoring != nil && documentdb.Spec.Monitoring.Enabled
@@ -88,7 +90,7 @@ func ValidateResources(documentdb *dbpreview.DocumentDB, cfg SplitConfig) field.
 		dbQty:        componentCPUMilli(res.Database),
 		format:       milliCPUString,
 	})...)
-	return errs
+	return append(errs, ValidateHugePages(documentdb)...)
 }
 
 // dimension is a fully resolved view of one resource dimension (memory or cpu)
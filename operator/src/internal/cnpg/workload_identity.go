@@ -0,0 +1,34 @@
+// Copyright (c) Microsoft Corporation.
+// Licensed under the MIT License.
+
+package cnpg
+
+import (
+	dbpreview "github.com/documentdb/documentdb-operator/api/preview"
+)
+
+// azureWorkloadIdentityLabel makes the Azure Workload Identity webhook inject
+// the federated token and client settings into a pod.
+const azureWorkloadIdentityLabel = "azure.workload.identity/use"
+
+// workloadIdentityAnnotations are the ServiceAccount annotations binding it to
+// the identity of each provider.
+var workloadIdentityAnnotations = map[dbpreview.WorkloadIdentityProvider]string{
+	dbpreview.WorkloadIdentityAWS:   "eks.amazonaws.com/role-arn",
+	dbpreview.WorkloadIdentityAzure: "azure.workload.identity/client-id",
+	dbpreview.WorkloadIdentityGCP:   "iam.gke.io/gcp-service-account",
+}
+
+// WorkloadIdentityAnnotation returns the ServiceAccount annotation that binds
+// it to an identity of provider.
+func WorkloadIdentityAnnotation(provider dbpreview.WorkloadIdentityProvider) string {
+	return workloadIdentityAnnotations[provider]
+}
+
+// workloadIdentity returns spec.serviceAccount.workloadIdentity, or nil.
+func workloadIdentity(documentdb *dbpreview.DocumentDB) *dbpreview.WorkloadIdentitySpec {
+	if documentdb.Spec.ServiceAccount == nil {
+		return nil
+	}
+	return documentdb.Spec.ServiceAccount.WorkloadIdentity
+}
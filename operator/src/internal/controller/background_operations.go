@@ -0,0 +1,82 @@
+// Copyright (c) Microsoft Corporation.
+// Licensed under the MIT License.
+
+package controller
+
+import (
+	"context"
+	"sync"
+
+	"sigs.k8s.io/controller-runtime/pkg/log"
+)
+
+// backgroundOperations runs work that outlives a single reconcile, such as the
+// demotion token wait, and ties it to the manager lifecycle. It is added to the
+// manager as a leader-election Runnable: on shutdown (SIGTERM or loss of
+// leadership) its context is cancelled, the operations observe the cancellation
+// and return at a safe point, and Start blocks until all of them have returned.
+// Their progress is persisted in DocumentDB status, so the next leader resumes
+// them instead of starting over or losing them.
+type backgroundOperations struct {
+	ctx    context.Context
+	cancel context.CancelFunc
+
+	mu       sync.Mutex
+	stopping bool
+	running  map[string]struct{}
+	wg       sync.WaitGroup
+}
+
+func newBackgroundOperations() *backgroundOperations {
+	ctx, cancel := context.WithCancel(context.Background())
+	return &backgroundOperations{
+		ctx:     ctx,
+		cancel:  cancel,
+		running: map[string]struct{}{},
+	}
+}
+
+// Start implements manager.Runnable. It returns once the manager is stopping
+// and every background operation has returned.
+func (b *backgroundOperations) Start(ctx context.Context) error {
+	<-ctx.Done()
+
+	b.mu.Lock()
+	b.stopping = true
+	inFlight := len(b.running)
+	b.mu.Unlock()
+
+	if inFlight > 0 {
+		log.FromContext(ctx).Info("Waiting for in-flight background operations to stop", "count", inFlight)
+	}
+	b.cancel()
+	b.wg.Wait()
+	return nil
+}
+
+// Go runs fn in a goroutine unless an operation with the same key is already
+// running or the operator is shutting down. It reports whether fn was started.
+func (b *backgroundOperations) Go(key string, fn func(ctx context.Context)) bool {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	if b.stopping {
+		return false
+	}
+	if _, ok := b.running[key]; ok {
+		return false
+	}
+	b.running[key] = struct{}{}
+	b.wg.Add(1)
+
+	go func() {
+		defer func() {
+			b.mu.Lock()
+			delete(b.running, key)
+			b.mu.Unlock()
+			b.wg.Done()
+		}()
+		fn(b.ctx)
+	}()
+	return true
+}
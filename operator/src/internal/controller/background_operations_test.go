@@ -0,0 +1,55 @@
+// Copyright (c) Microsoft Corporation.
+// Licensed under the MIT License.
+
+package controller
+
+import (
+	"context"
+	"time"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("backgroundOperations", func() {
+	It("runs only one operation per key at a time", func() {
+		b := newBackgroundOperations()
+		release := make(chan struct{})
+
+		Expect(b.Go("op", func(context.Context) { <-release })).To(BeTrue())
+		Expect(b.Go("op", func(context.Context) {})).To(BeFalse())
+		Expect(b.Go("other", func(context.Context) {})).To(BeTrue())
+
+		close(release)
+		Eventually(func() bool { return b.Go("op", func(context.Context) {}) }).Should(BeTrue())
+	})
+
+	It("cancels running operations and waits for them when the manager stops", func() {
+		b := newBackgroundOperations()
+		mgrCtx, stopManager := context.WithCancel(context.Background())
+
+		stopped := make(chan struct{})
+		Expect(b.Go("op", func(ctx context.Context) {
+			<-ctx.Done()
+			time.Sleep(50 * time.Millisecond)
+			close(stopped)
+		})).To(BeTrue())
+
+		startReturned := make(chan error, 1)
+		go func() { startReturned <- b.Start(mgrCtx) }()
+		Consistently(startReturned, 100*time.Millisecond).ShouldNot(Receive())
+
+		stopManager()
+		Eventually(startReturned).Should(Receive(BeNil()))
+		Expect(stopped).To(BeClosed())
+	})
+
+	It("refuses new operations once the manager is stopping", func() {
+		b := newBackgroundOperations()
+		mgrCtx, stopManager := context.WithCancel(context.Background())
+		stopManager()
+		Expect(b.Start(mgrCtx)).To(Succeed())
+
+		Expect(b.Go("op", func(context.Context) {})).To(BeFalse())
+	})
+})
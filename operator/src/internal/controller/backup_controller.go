@@ -73,10 +73,16 @@ func (r *BackupReconciler) Reconcile(ctx context.Context, req ctrl.Request) (ctr
 	if err := r.Get(ctx, clusterKey, cluster); err != nil {
 		return r.SetBackupPhaseFailed(ctx, backup, "Failed to get associated DocumentDB cluster: "+err.Error(), nil)
 	}
+	if err := util.ResolveClusterClass(ctx, r.Client, cluster); err != nil {
+		return r.SetBackupPhaseFailed(ctx, backup, "Failed to resolve cluster class of DocumentDB cluster: "+err.Error(), nil)
+	}
+	if err := util.ApplyNamespaceDefaults(ctx, r.Client, cluster); err != nil {
+		return r.SetBackupPhaseFailed(ctx, backup, "Failed to apply namespace defaults of DocumentDB cluster: "+err.Error(), nil)
+	}
 
 	// Ensure VolumeSnapshotClass exists
-	if err := r.ensureVolumeSnapshotClass(ctx, cluster.Spec.Environment); err != nil {
-		return r.SetBackupPhaseFailed(ctx, backup, "Failed to ensure VolumeSnapshotClass: "+err.Error(), cluster.Spec.Backup)
+	if err := r.ensureVolumeSnapshotClass(ctx, util.ResolveEnvironment(cluster.Spec.Environment)); err != nil {
+		return r.SetBackupPhaseFailed(ctx, backup, "Failed to ensure VolumeSnapshotClass: "+err.Error(), backupConfigurationFor(cluster))
 	}
 
 	// Get or create the CNPG Backup
@@ -95,7 +101,7 @@ func (r *BackupReconciler) Reconcile(ctx context.Context, req ctrl.Request) (ctr
 				return ctrl.Result{}, err
 			}
 			if !replicationContext.IsPrimary() {
-				return r.SetBackupPhaseSkipped(ctx, backup, "Backups can only be created from the primary cluster", cluster.Spec.Backup)
+				return r.SetBackupPhaseSkipped(ctx, backup, "Backups can only be created from the primary cluster", backupConfigurationFor(cluster))
 			}
 			if !replicationContext.EndpointEnabled() {
 				logger.Info("Backup deferred: primary cluster endpoint not ready, waiting for promotion to complete")
@@ -109,7 +115,7 @@ func (r *BackupReconciler) Reconcile(ctx context.Context, req ctrl.Request) (ctr
 	}
 
 	// Update status based on CNPG Backup status
-	return r.updateBackupStatus(ctx, backup, cnpgBackup, cluster.Spec.Backup)
+	return r.updateBackupStatus(ctx, backup, cnpgBackup, backupConfigurationFor(cluster))
 }
 
 // ensureVolumeSnapshotClass creates a VolumeSnapshotClass based on the cloud environment
@@ -154,7 +160,7 @@ func buildVolumeSnapshotClass(environment string) *snapshotv1.VolumeSnapshotClas
 	var name string
 
 	switch environment {
-	case "aks":
+	case util.ENVIRONMENT_AKS:
 		driver = "disk.csi.azure.com"
 		name = "azure-disk-snapclass"
 	default:
@@ -180,11 +186,11 @@ func (r *BackupReconciler) createCNPGBackup(ctx context.Context, backup *dbprevi
 
 	cnpgBackup, err := backup.CreateCNPGBackup(r.Scheme, cnpgClusterName)
 	if err != nil {
-		return r.SetBackupPhaseFailed(ctx, backup, "Failed to initialize backup: "+err.Error(), cluster.Spec.Backup)
+		return r.SetBackupPhaseFailed(ctx, backup, "Failed to initialize backup: "+err.Error(), backupConfigurationFor(cluster))
 	}
 
 	if err := r.Create(ctx, cnpgBackup); err != nil {
-		return r.SetBackupPhaseFailed(ctx, backup, "Failed to initialize backup: "+err.Error(), cluster.Spec.Backup)
+		return r.SetBackupPhaseFailed(ctx, backup, "Failed to initialize backup: "+err.Error(), backupConfigurationFor(cluster))
 	}
 
 	r.Recorder.Event(backup, "Normal", "BackupInitialized", "Successfully initialized backup")
@@ -193,6 +199,15 @@ func (r *BackupReconciler) createCNPGBackup(ctx context.Context, backup *dbprevi
 	return ctrl.Result{RequeueAfter: 5 * time.Second}, nil
 }
 
+// backupConfigurationFor returns the backup configuration of the cluster, or an
+// operator-default one when spec.backup is not set.
+func backupConfigurationFor(cluster *dbpreview.DocumentDB) *dbpreview.BackupConfiguration {
+	if cluster.Spec.Backup != nil {
+		return cluster.Spec.Backup
+	}
+	return &dbpreview.BackupConfiguration{RetentionDays: util.GetDefaultBackupRetentionDays()}
+}
+
 // updateBackupStatus updates the Backup status based on CNPG Backup status
 func (r *BackupReconciler) updateBackupStatus(ctx context.Context, backup *dbpreview.Backup, cnpgBackup *cnpgv1.Backup, backupConfiguration *dbpreview.BackupConfiguration) (ctrl.Result, error) {
 	original := backup.DeepCopy()
@@ -223,6 +238,7 @@ func (r *BackupReconciler) SetBackupPhaseFailed(ctx context.Context, backup *dbp
 
 	backup.Status.Phase = cnpgv1.BackupPhaseFailed
 	backup.Status.Message = errMessage
+	backup.Status.RetentionDays = backup.EffectiveRetentionDays(backupConfiguration)
 	backup.Status.ExpiredAt = backup.CalculateExpirationTime(backupConfiguration)
 
 	if err := r.Status().Patch(ctx, backup, client.MergeFrom(original)); err != nil {
@@ -244,6 +260,7 @@ func (r *BackupReconciler) SetBackupPhaseSkipped(ctx context.Context, backup *db
 
 	backup.Status.Phase = dbpreview.BackupPhaseSkipped
 	backup.Status.Message = message
+	backup.Status.RetentionDays = backup.EffectiveRetentionDays(backupConfiguration)
 	backup.Status.ExpiredAt = backup.CalculateExpirationTime(backupConfiguration)
 
 	if err := r.Status().Patch(ctx, backup, client.MergeFrom(original)); err != nil {
@@ -270,5 +287,5 @@ func (r *BackupReconciler) SetupWithManager(mgr ctrl.Manager) error {
 	return ctrl.NewControllerManagedBy(mgr).
 		For(&dbpreview.Backup{}).
 		Owns(&cnpgv1.Backup{}).
-		Complete(r)
+		Complete(trackReconciles("backup", r))
 }
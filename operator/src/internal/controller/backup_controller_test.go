@@ -11,6 +11,7 @@ import (
 	snapshotv1 "github.com/kubernetes-csi/external-snapshotter/client/v8/apis/volumesnapshot/v1"
 	. "github.com/onsi/ginkgo/v2"
 	. "github.com/onsi/gomega"
+	corev1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/runtime"
 	"k8s.io/apimachinery/pkg/types"
@@ -44,6 +45,7 @@ var _ = Describe("Backup Controller", func() {
 		Expect(dbpreview.AddToScheme(scheme)).To(Succeed())
 		Expect(cnpgv1.AddToScheme(scheme)).To(Succeed())
 		Expect(snapshotv1.AddToScheme(scheme)).To(Succeed())
+		Expect(corev1.AddToScheme(scheme)).To(Succeed())
 	})
 
 	Describe("createCNPGBackup", func() {
@@ -375,4 +377,23 @@ var _ = Describe("Backup Controller", func() {
 			Expect(cnpgBackup.Spec.Cluster.Name).To(Equal(clusterName))
 		})
 	})
+
+	Describe("backupConfigurationFor", func() {
+		AfterEach(func() {
+			util.SetOperatorSettings(nil)
+		})
+
+		It("returns spec.backup when it is set", func() {
+			cluster := &dbpreview.DocumentDB{Spec: dbpreview.DocumentDBSpec{
+				Backup: &dbpreview.BackupConfiguration{RetentionDays: 14},
+			}}
+			Expect(backupConfigurationFor(cluster).RetentionDays).To(Equal(14))
+		})
+
+		It("falls back to the operator default retention when spec.backup is not set", func() {
+			util.SetOperatorSettings(map[string]string{util.DEFAULT_BACKUP_RETENTION_DAYS_ENV: "7"})
+			cluster := &dbpreview.DocumentDB{}
+			Expect(backupConfigurationFor(cluster).RetentionDays).To(Equal(7))
+		})
+	})
 })
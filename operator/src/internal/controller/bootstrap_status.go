@@ -0,0 +1,254 @@
+// Copyright (c) Microsoft Corporation.
+// Licensed under the MIT License.
+
+package controller
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"strings"
+
+	cnpgv1 "github.com/cloudnative-pg/cloudnative-pg/api/v1"
+	batchv1 "k8s.io/api/batch/v1"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/equality"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/log"
+
+	dbpreview "github.com/documentdb/documentdb-operator/api/preview"
+)
+
+const (
+	// cnpgJobRoleLabel is set by CNPG on the Jobs, and their Pods, that
+	// create or join instances. Its value is the role of the Job, which is
+	// also the name of its container.
+	cnpgJobRoleLabel = "cnpg.io/jobRole"
+
+	// cnpgJobRoleJoin is the role of the Jobs cloning replicas, which are
+	// not part of the bootstrap.
+	cnpgJobRoleJoin = "join"
+)
+
+// bootstrapProgressCommand prints the size of the data directory and, once
+// the recovered instance accepts connections, the last WAL location it
+// replayed.
+var bootstrapProgressCommand = []string{"sh", "-c",
+	`du -sb "$PGDATA" 2>/dev/null; psql -XAtc "SELECT pg_last_wal_replay_lsn()" 2>/dev/null`}
+
+// bootstrapInProgress reports whether the first instance of the cluster is
+// still being created.
+func bootstrapInProgress(documentdb *dbpreview.DocumentDB) bool {
+	status := documentdb.Status.Bootstrap
+	return status != nil && status.Phase != dbpreview.BootstrapPhaseCompleted
+}
+
+// reconcileBootstrapStatus reports in status.bootstrap the progress of the
+// CNPG Job creating the first instance, whichever bootstrap method it uses,
+// and records an event when its phase changes. It returns whether
+// status.bootstrap changed. A cluster that was already healthy when it is
+// first seen is reported as completed without an event.
+func (r *DocumentDBReconciler) reconcileBootstrapStatus(ctx context.Context, documentdb *dbpreview.DocumentDB, cnpgCluster *cnpgv1.Cluster) (bool, error) {
+	current := documentdb.Status.Bootstrap
+	if current != nil && current.Phase == dbpreview.BootstrapPhaseCompleted {
+		return false, nil
+	}
+
+	job, err := r.bootstrapJob(ctx, cnpgCluster)
+	if err != nil {
+		return false, err
+	}
+	healthy := cnpgCluster.Status.Phase == cnpgv1.PhaseHealthy
+
+	status := &dbpreview.BootstrapStatus{Phase: dbpreview.BootstrapPhasePending}
+	if current != nil {
+		status = current.DeepCopy()
+	}
+	if job != nil {
+		status.Method = job.Labels[cnpgJobRoleLabel]
+		status.JobName = job.Name
+		if status.StartedAt == nil {
+			status.StartedAt = job.Status.StartTime
+		}
+	}
+	status.Message = ""
+
+	switch {
+	case healthy:
+		now := metav1.Now()
+		status.Phase = dbpreview.BootstrapPhaseCompleted
+		status.CompletedAt = &now
+	case cnpgCluster.Status.Phase == cnpgv1.PhaseUnrecoverable:
+		status.Phase = dbpreview.BootstrapPhaseFailed
+		status.Message = cnpgCluster.Status.PhaseReason
+	case job == nil:
+		status.Phase = dbpreview.BootstrapPhasePending
+		status.Message = cnpgCluster.Status.PhaseReason
+	case jobCondition(job, batchv1.JobFailed) != nil:
+		status.Phase = dbpreview.BootstrapPhaseFailed
+		status.Message = fmt.Sprintf("Job %s failed: %s", job.Name, jobCondition(job, batchv1.JobFailed).Message)
+	case jobCondition(job, batchv1.JobComplete) != nil:
+		status.Phase = dbpreview.BootstrapPhaseStarting
+	default:
+		status.Phase = dbpreview.BootstrapPhasePending
+		pod, err := r.bootstrapPod(ctx, job)
+		if err != nil {
+			return false, err
+		}
+		if pod != nil && pod.Status.Phase == corev1.PodRunning {
+			status.Phase = dbpreview.BootstrapPhaseRunning
+			r.probeBootstrapPod(ctx, pod, status)
+		}
+	}
+
+	if current == nil && healthy {
+		// Bootstrapped before the operator reported it, e.g. on upgrade
+		if job != nil && job.Status.CompletionTime != nil {
+			status.CompletedAt = job.Status.CompletionTime
+		}
+		documentdb.Status.Bootstrap = status
+		return true, nil
+	}
+	if equality.Semantic.DeepEqual(current, status) {
+		return false, nil
+	}
+	if current == nil || current.Phase != status.Phase {
+		r.recordBootstrapPhase(documentdb, status)
+	} else if !equalProgress(current, status) && r.Recorder != nil {
+		r.Recorder.Event(documentdb, corev1.EventTypeNormal, "BootstrapProgress", bootstrapProgressMessage(status))
+	}
+	documentdb.Status.Bootstrap = status
+	return true, nil
+}
+
+// bootstrapJob returns the CNPG Job creating the first instance of
+// cnpgCluster, or nil when CNPG has not created it yet.
+func (r *DocumentDBReconciler) bootstrapJob(ctx context.Context, cnpgCluster *cnpgv1.Cluster) (*batchv1.Job, error) {
+	jobs := &batchv1.JobList{}
+	if err := r.List(ctx, jobs, client.InNamespace(cnpgCluster.Namespace), client.MatchingLabels{"cnpg.io/cluster": cnpgCluster.Name}); err != nil {
+		return nil, fmt.Errorf("failed to list CNPG Jobs: %w", err)
+	}
+	var first *batchv1.Job
+	for i := range jobs.Items {
+		job := &jobs.Items[i]
+		role := job.Labels[cnpgJobRoleLabel]
+		if role == "" || role == cnpgJobRoleJoin {
+			continue
+		}
+		if first == nil || job.CreationTimestamp.Before(&first.CreationTimestamp) {
+			first = job
+		}
+	}
+	return first, nil
+}
+
+// bootstrapPod returns the most recent Pod of the bootstrap Job, or nil.
+func (r *DocumentDBReconciler) bootstrapPod(ctx context.Context, job *batchv1.Job) (*corev1.Pod, error) {
+	pods := &corev1.PodList{}
+	if err := r.List(ctx, pods, client.InNamespace(job.Namespace), client.MatchingLabels{batchv1.JobNameLabel: job.Name}); err != nil {
+		return nil, fmt.Errorf("failed to list bootstrap Job Pods: %w", err)
+	}
+	var latest *corev1.Pod
+	for i := range pods.Items {
+		pod := &pods.Items[i]
+		if latest == nil || latest.CreationTimestamp.Before(&pod.CreationTimestamp) {
+			latest = pod
+		}
+	}
+	return latest, nil
+}
+
+// probeBootstrapPod fills the restored bytes and the replayed WAL location
+// of status from the running bootstrap Pod. The previous values are kept
+// when the Pod cannot be probed, since the progress is only informative.
+func (r *DocumentDBReconciler) probeBootstrapPod(ctx context.Context, pod *corev1.Pod, status *dbpreview.BootstrapStatus) {
+	if r.BootstrapProgressProbe == nil {
+		return
+	}
+	output, err := r.BootstrapProgressProbe(ctx, pod)
+	if err != nil {
+		log.FromContext(ctx).V(1).Info("Could not read bootstrap progress", "pod", pod.Name, "error", err.Error())
+		return
+	}
+	restoredBytes, replayedLSN := parseBootstrapProgress(output)
+	if restoredBytes != nil {
+		status.RestoredBytes = restoredBytes
+	}
+	if replayedLSN != "" {
+		status.ReplayedLSN = replayedLSN
+	}
+}
+
+// probeBootstrapProgress runs bootstrapProgressCommand in the container of
+// the bootstrap Pod, which CNPG names after the role of the Job.
+func (r *DocumentDBReconciler) probeBootstrapProgress(ctx context.Context, pod *corev1.Pod) (string, error) {
+	stdout, stderr, err := r.execInPod(ctx, pod, pod.Labels[cnpgJobRoleLabel], bootstrapProgressCommand, nil)
+	if err != nil {
+		return "", fmt.Errorf("%w: %s", err, stderr)
+	}
+	return stdout, nil
+}
+
+// parseBootstrapProgress parses the output of bootstrapProgressCommand: the
+// du line, then the replayed WAL location when psql could connect.
+func parseBootstrapProgress(output string) (*int64, string) {
+	var restoredBytes *int64
+	replayedLSN := ""
+	for _, line := range strings.Split(strings.TrimSpace(output), "\n") {
+		fields := strings.Fields(line)
+		switch {
+		case len(fields) == 2:
+			if size, err := strconv.ParseInt(fields[0], 10, 64); err == nil {
+				restoredBytes = &size
+			}
+		case len(fields) == 1 && strings.Contains(fields[0], "/"):
+			replayedLSN = fields[0]
+		}
+	}
+	return restoredBytes, replayedLSN
+}
+
+func jobCondition(job *batchv1.Job, conditionType batchv1.JobConditionType) *batchv1.JobCondition {
+	for i := range job.Status.Conditions {
+		if job.Status.Conditions[i].Type == conditionType && job.Status.Conditions[i].Status == corev1.ConditionTrue {
+			return &job.Status.Conditions[i]
+		}
+	}
+	return nil
+}
+
+func equalProgress(a, b *dbpreview.BootstrapStatus) bool {
+	return a.ReplayedLSN == b.ReplayedLSN &&
+		(a.RestoredBytes == nil) == (b.RestoredBytes == nil) &&
+		(a.RestoredBytes == nil || *a.RestoredBytes == *b.RestoredBytes)
+}
+
+func bootstrapProgressMessage(status *dbpreview.BootstrapStatus) string {
+	message := fmt.Sprintf("Bootstrap Job %s (%s)", status.JobName, status.Method)
+	if status.RestoredBytes != nil {
+		message += fmt.Sprintf(": %d bytes restored", *status.RestoredBytes)
+	}
+	if status.ReplayedLSN != "" {
+		message += fmt.Sprintf(", WAL replayed up to %s", status.ReplayedLSN)
+	}
+	return message
+}
+
+// recordBootstrapPhase records the event of the new phase of status.
+func (r *DocumentDBReconciler) recordBootstrapPhase(documentdb *dbpreview.DocumentDB, status *dbpreview.BootstrapStatus) {
+	if r.Recorder == nil {
+		return
+	}
+	switch status.Phase {
+	case dbpreview.BootstrapPhaseRunning:
+		r.Recorder.Event(documentdb, corev1.EventTypeNormal, "BootstrapRunning", bootstrapProgressMessage(status))
+	case dbpreview.BootstrapPhaseStarting:
+		r.Recorder.Event(documentdb, corev1.EventTypeNormal, "BootstrapStarting",
+			fmt.Sprintf("Bootstrap Job %s completed, starting the first instance", status.JobName))
+	case dbpreview.BootstrapPhaseCompleted:
+		r.Recorder.Event(documentdb, corev1.EventTypeNormal, "BootstrapCompleted", "The cluster is healthy")
+	case dbpreview.BootstrapPhaseFailed:
+		r.Recorder.Event(documentdb, corev1.EventTypeWarning, "BootstrapFailed", status.Message)
+	}
+}
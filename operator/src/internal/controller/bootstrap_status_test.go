@@ -0,0 +1,182 @@
+// Copyright (c) Microsoft Corporation.
+// Licensed under the MIT License.
+
+package controller
+
+import (
+	"context"
+	"fmt"
+
+	cnpgv1 "github.com/cloudnative-pg/cloudnative-pg/api/v1"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	batchv1 "k8s.io/api/batch/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/client-go/tools/record"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	dbpreview "github.com/documentdb/documentdb-operator/api/preview"
+)
+
+var _ = Describe("Bootstrap status", func() {
+	const namespace = "default"
+
+	var (
+		ctx         context.Context
+		recorder    *record.FakeRecorder
+		reconciler  *DocumentDBReconciler
+		documentdb  *dbpreview.DocumentDB
+		cnpgCluster *cnpgv1.Cluster
+		progress    string
+		probeErr    error
+	)
+
+	BeforeEach(func() {
+		ctx = context.Background()
+		scheme := runtime.NewScheme()
+		Expect(dbpreview.AddToScheme(scheme)).To(Succeed())
+		Expect(corev1.AddToScheme(scheme)).To(Succeed())
+		Expect(batchv1.AddToScheme(scheme)).To(Succeed())
+		recorder = record.NewFakeRecorder(10)
+		progress = "1048576\t/var/lib/postgresql/data/pgdata\n"
+		probeErr = nil
+
+		documentdb = &dbpreview.DocumentDB{ObjectMeta: metav1.ObjectMeta{Name: "db", Namespace: namespace}}
+		cnpgCluster = &cnpgv1.Cluster{
+			ObjectMeta: metav1.ObjectMeta{Name: "db", Namespace: namespace},
+			Status:     cnpgv1.ClusterStatus{Phase: cnpgv1.PhaseFirstPrimary, PhaseReason: "Creating primary instance db-1"},
+		}
+		reconciler = &DocumentDBReconciler{
+			Client:   fake.NewClientBuilder().WithScheme(scheme).Build(),
+			Scheme:   scheme,
+			Recorder: recorder,
+			BootstrapProgressProbe: func(_ context.Context, pod *corev1.Pod) (string, error) {
+				Expect(pod.Name).To(Equal("db-1-full-recovery-abcde"))
+				return progress, probeErr
+			},
+		}
+	})
+
+	createJob := func(role string) *batchv1.Job {
+		job := &batchv1.Job{ObjectMeta: metav1.ObjectMeta{
+			Name:      "db-1-" + role,
+			Namespace: namespace,
+			Labels:    map[string]string{"cnpg.io/cluster": "db", cnpgJobRoleLabel: role},
+		}}
+		Expect(reconciler.Create(ctx, job)).To(Succeed())
+		return job
+	}
+
+	startPod := func(job *batchv1.Job) {
+		pod := &corev1.Pod{ObjectMeta: metav1.ObjectMeta{
+			Name:      job.Name + "-abcde",
+			Namespace: namespace,
+			Labels:    map[string]string{batchv1.JobNameLabel: job.Name, cnpgJobRoleLabel: job.Labels[cnpgJobRoleLabel]},
+		}}
+		Expect(reconciler.Create(ctx, pod)).To(Succeed())
+		pod.Status.Phase = corev1.PodRunning
+		Expect(reconciler.Status().Update(ctx, pod)).To(Succeed())
+	}
+
+	setJobCondition := func(job *batchv1.Job, conditionType batchv1.JobConditionType) {
+		Expect(reconciler.Get(ctx, client.ObjectKeyFromObject(job), job)).To(Succeed())
+		job.Status.Conditions = []batchv1.JobCondition{{Type: conditionType, Status: corev1.ConditionTrue, Message: "BackoffLimitExceeded"}}
+		Expect(reconciler.Status().Update(ctx, job)).To(Succeed())
+	}
+
+	It("follows the bootstrap Job until the cluster is healthy", func() {
+		changed, err := reconciler.reconcileBootstrapStatus(ctx, documentdb, cnpgCluster)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(changed).To(BeTrue())
+		Expect(documentdb.Status.Bootstrap.Phase).To(Equal(dbpreview.BootstrapPhasePending))
+		Expect(documentdb.Status.Bootstrap.Message).To(Equal("Creating primary instance db-1"))
+		Expect(bootstrapInProgress(documentdb)).To(BeTrue())
+
+		job := createJob("full-recovery")
+		startPod(job)
+		changed, err = reconciler.reconcileBootstrapStatus(ctx, documentdb, cnpgCluster)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(changed).To(BeTrue())
+		Expect(documentdb.Status.Bootstrap.Phase).To(Equal(dbpreview.BootstrapPhaseRunning))
+		Expect(documentdb.Status.Bootstrap.Method).To(Equal("full-recovery"))
+		Expect(documentdb.Status.Bootstrap.JobName).To(Equal("db-1-full-recovery"))
+		Expect(*documentdb.Status.Bootstrap.RestoredBytes).To(Equal(int64(1048576)))
+		Expect(recorder.Events).To(Receive(ContainSubstring("BootstrapRunning")))
+
+		changed, err = reconciler.reconcileBootstrapStatus(ctx, documentdb, cnpgCluster)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(changed).To(BeFalse())
+
+		progress = "2097152\t/var/lib/postgresql/data/pgdata\n0/5000060\n"
+		changed, err = reconciler.reconcileBootstrapStatus(ctx, documentdb, cnpgCluster)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(changed).To(BeTrue())
+		Expect(documentdb.Status.Bootstrap.ReplayedLSN).To(Equal("0/5000060"))
+		Expect(recorder.Events).To(Receive(ContainSubstring("BootstrapProgress")))
+
+		probeErr = fmt.Errorf("container not found")
+		changed, err = reconciler.reconcileBootstrapStatus(ctx, documentdb, cnpgCluster)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(changed).To(BeFalse())
+		Expect(*documentdb.Status.Bootstrap.RestoredBytes).To(Equal(int64(2097152)))
+
+		setJobCondition(job, batchv1.JobComplete)
+		_, err = reconciler.reconcileBootstrapStatus(ctx, documentdb, cnpgCluster)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(documentdb.Status.Bootstrap.Phase).To(Equal(dbpreview.BootstrapPhaseStarting))
+		Expect(recorder.Events).To(Receive(ContainSubstring("BootstrapStarting")))
+
+		cnpgCluster.Status.Phase = cnpgv1.PhaseHealthy
+		_, err = reconciler.reconcileBootstrapStatus(ctx, documentdb, cnpgCluster)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(documentdb.Status.Bootstrap.Phase).To(Equal(dbpreview.BootstrapPhaseCompleted))
+		Expect(documentdb.Status.Bootstrap.CompletedAt).ToNot(BeNil())
+		Expect(recorder.Events).To(Receive(ContainSubstring("BootstrapCompleted")))
+		Expect(bootstrapInProgress(documentdb)).To(BeFalse())
+	})
+
+	It("reports a failed bootstrap Job", func() {
+		job := createJob("pgbasebackup")
+		createJob("join")
+		setJobCondition(job, batchv1.JobFailed)
+
+		_, err := reconciler.reconcileBootstrapStatus(ctx, documentdb, cnpgCluster)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(documentdb.Status.Bootstrap.Phase).To(Equal(dbpreview.BootstrapPhaseFailed))
+		Expect(documentdb.Status.Bootstrap.Method).To(Equal("pgbasebackup"))
+		Expect(documentdb.Status.Bootstrap.Message).To(ContainSubstring("BackoffLimitExceeded"))
+		Expect(recorder.Events).To(Receive(ContainSubstring("Warning BootstrapFailed")))
+	})
+
+	It("reports a cluster that was already healthy as bootstrapped without an event", func() {
+		createJob("initdb")
+		cnpgCluster.Status.Phase = cnpgv1.PhaseHealthy
+
+		changed, err := reconciler.reconcileBootstrapStatus(ctx, documentdb, cnpgCluster)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(changed).To(BeTrue())
+		Expect(documentdb.Status.Bootstrap.Phase).To(Equal(dbpreview.BootstrapPhaseCompleted))
+		Expect(documentdb.Status.Bootstrap.Method).To(Equal("initdb"))
+		Expect(recorder.Events).To(BeEmpty())
+	})
+})
+
+var _ = Describe("parseBootstrapProgress", func() {
+	It("reads the size of the data directory and the replayed WAL location", func() {
+		restoredBytes, replayedLSN := parseBootstrapProgress("4096\t/var/lib/postgresql/data/pgdata\n0/3000060\n")
+		Expect(*restoredBytes).To(Equal(int64(4096)))
+		Expect(replayedLSN).To(Equal("0/3000060"))
+	})
+
+	It("ignores a server that is not accepting connections", func() {
+		restoredBytes, replayedLSN := parseBootstrapProgress("4096\t/var/lib/postgresql/data/pgdata\n")
+		Expect(*restoredBytes).To(Equal(int64(4096)))
+		Expect(replayedLSN).To(BeEmpty())
+
+		restoredBytes, _ = parseBootstrapProgress("")
+		Expect(restoredBytes).To(BeNil())
+	})
+})
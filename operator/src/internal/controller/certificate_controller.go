@@ -379,5 +379,5 @@ func (r *CertificateReconciler) SetupWithManager(mgr ctrl.Manager) error {
 		Owns(&cmapi.Certificate{}).
 		Owns(&cmapi.Issuer{}).
 		Named("certificate-controller").
-		Complete(r)
+		Complete(trackReconciles("certificate-controller", r))
 }
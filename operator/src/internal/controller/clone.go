@@ -0,0 +1,262 @@
+// Copyright (c) Microsoft Corporation.
+// Licensed under the MIT License.
+
+package controller
+
+import (
+	"context"
+	"fmt"
+	"maps"
+	"strings"
+
+	cnpgv1 "github.com/cloudnative-pg/cloudnative-pg/api/v1"
+	snapshotv1 "github.com/kubernetes-csi/external-snapshotter/client/v8/apis/volumesnapshot/v1"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/utils/ptr"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
+	"sigs.k8s.io/controller-runtime/pkg/log"
+
+	dbpreview "github.com/documentdb/documentdb-operator/api/preview"
+	util "github.com/documentdb/documentdb-operator/internal/utils"
+)
+
+const (
+	// cloneBackupRetentionDays is the retention of the Backup taken of the
+	// source of a clone. The clone no longer needs it once it is created.
+	cloneBackupRetentionDays = 1
+
+	// cnpgSnapshotPgDataType is the type of the CNPG Backup snapshot element
+	// holding the PGDATA volume.
+	cnpgSnapshotPgDataType = "PG_DATA"
+
+	// cnpgAnnotationPrefix prefixes the annotations CNPG writes on the
+	// snapshots of a Backup and reads back when recovering from them.
+	cnpgAnnotationPrefix = "cnpg.io/"
+)
+
+// reconcileClone prepares the data a DocumentDB of spec.bootstrap.clone is
+// bootstrapped from, before its CNPG Cluster is created.
+//
+// The operator takes a volume snapshot Backup of the source cluster, in the
+// namespace of the source. A clone in the same namespace recovers from that
+// Backup directly. CNPG only recovers from snapshots in the namespace of the
+// cluster, so for a clone in another namespace the PGDATA snapshot is
+// imported into the namespace of the clone with a pre-provisioned
+// VolumeSnapshotContent that points at the same storage snapshot. The
+// imported snapshot is deleted once the clone is healthy; the Backup expires
+// after cloneBackupRetentionDays.
+func (r *DocumentDBReconciler) reconcileClone(ctx context.Context, documentdb *dbpreview.DocumentDB, cnpgClusterName string, isPrimary bool) (ctrl.Result, error) {
+	if !documentdb.IsCloneConfigured() || !isPrimary {
+		return ctrl.Result{}, nil
+	}
+	logger := log.FromContext(ctx)
+	source := documentdb.GetCloneSource()
+
+	cnpgCluster := &cnpgv1.Cluster{}
+	if err := r.Get(ctx, types.NamespacedName{Name: cnpgClusterName, Namespace: documentdb.Namespace}, cnpgCluster); err == nil {
+		if source.Namespace != documentdb.Namespace && cnpgCluster.Status.Phase == cnpgClusterHealthyPhase {
+			return ctrl.Result{}, r.deleteCloneSnapshot(ctx, documentdb)
+		}
+		return ctrl.Result{}, nil
+	} else if !errors.IsNotFound(err) {
+		return ctrl.Result{}, fmt.Errorf("failed to get CNPG cluster: %w", err)
+	}
+
+	sourceDocumentDB := &dbpreview.DocumentDB{}
+	if err := r.Get(ctx, source, sourceDocumentDB); err != nil {
+		if errors.IsNotFound(err) {
+			r.recordCloneWarning(documentdb, "CloneSourceNotFound", fmt.Sprintf("Source DocumentDB %s not found", source))
+			return ctrl.Result{RequeueAfter: RequeueAfterLong}, nil
+		}
+		return ctrl.Result{}, fmt.Errorf("failed to get source DocumentDB %s: %w", source, err)
+	}
+	if !util.IsCloneAllowed(sourceDocumentDB.Annotations, source.Namespace, documentdb.Namespace) {
+		r.recordCloneWarning(documentdb, "CloneNotAllowed", fmt.Sprintf(
+			"Source DocumentDB %s does not allow clones in namespace %s; add it to the %s annotation of the source",
+			source, documentdb.Namespace, util.ALLOW_CLONE_TO_NAMESPACES_ANNOTATION))
+		return ctrl.Result{RequeueAfter: RequeueAfterLong}, nil
+	}
+
+	backupName := util.CloneBackupName(documentdb.Name, documentdb.Namespace, source.Namespace)
+	backup := &dbpreview.Backup{}
+	if err := r.Get(ctx, types.NamespacedName{Name: backupName, Namespace: source.Namespace}, backup); err != nil {
+		if !errors.IsNotFound(err) {
+			return ctrl.Result{}, fmt.Errorf("failed to get clone Backup %s: %w", backupName, err)
+		}
+		backup = &dbpreview.Backup{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      backupName,
+				Namespace: source.Namespace,
+				Labels: map[string]string{
+					util.LabelCluster:   documentdb.Name,
+					util.LabelNamespace: documentdb.Namespace,
+				},
+			},
+			Spec: dbpreview.BackupSpec{
+				Cluster:       cnpgv1.LocalObjectReference{Name: source.Name},
+				RetentionDays: ptr.To(cloneBackupRetentionDays),
+			},
+		}
+		if err := r.Create(ctx, backup); err != nil && !errors.IsAlreadyExists(err) {
+			return ctrl.Result{}, fmt.Errorf("failed to create clone Backup %s: %w", backupName, err)
+		}
+		logger.Info("Created Backup of the clone source", "source", source, "backup", backupName)
+		if r.Recorder != nil {
+			r.Recorder.Event(documentdb, corev1.EventTypeNormal, "CloneBackupStarted",
+				fmt.Sprintf("Backing up %s to Backup %s/%s", source, source.Namespace, backupName))
+		}
+		return ctrl.Result{RequeueAfter: RequeueAfterShort}, nil
+	}
+
+	switch backup.Status.Phase {
+	case cnpgv1.BackupPhaseCompleted:
+	case cnpgv1.BackupPhaseFailed, dbpreview.BackupPhaseSkipped:
+		r.recordCloneWarning(documentdb, "CloneBackupFailed", fmt.Sprintf(
+			"Backup %s/%s of the clone source is %s: %s. Delete the Backup to retry",
+			source.Namespace, backupName, backup.Status.Phase, backup.Status.Message))
+		return ctrl.Result{RequeueAfter: RequeueAfterLong}, nil
+	default:
+		logger.Info("Waiting for the Backup of the clone source", "backup", backupName, "phase", backup.Status.Phase)
+		return ctrl.Result{RequeueAfter: RequeueAfterShort}, nil
+	}
+
+	if source.Namespace == documentdb.Namespace {
+		return ctrl.Result{}, nil
+	}
+	return r.importCloneSnapshot(ctx, documentdb, source.Namespace, backupName)
+}
+
+// importCloneSnapshot imports the PGDATA snapshot of the clone Backup into
+// the namespace of documentdb, and returns once the imported snapshot is
+// ready to use.
+func (r *DocumentDBReconciler) importCloneSnapshot(ctx context.Context, documentdb *dbpreview.DocumentDB, sourceNamespace, backupName string) (ctrl.Result, error) {
+	logger := log.FromContext(ctx)
+	snapshotName := util.CloneSnapshotName(documentdb.Name)
+
+	snapshot := &snapshotv1.VolumeSnapshot{}
+	err := r.Get(ctx, types.NamespacedName{Name: snapshotName, Namespace: documentdb.Namespace}, snapshot)
+	if err == nil {
+		if snapshot.Status == nil || !ptr.Deref(snapshot.Status.ReadyToUse, false) {
+			logger.Info("Waiting for the imported clone snapshot to be ready", "snapshot", snapshotName)
+			return ctrl.Result{RequeueAfter: RequeueAfterShort}, nil
+		}
+		return ctrl.Result{}, nil
+	}
+	if !errors.IsNotFound(err) {
+		return ctrl.Result{}, fmt.Errorf("failed to get clone VolumeSnapshot %s: %w", snapshotName, err)
+	}
+
+	cnpgBackup := &cnpgv1.Backup{}
+	if err := r.Get(ctx, types.NamespacedName{Name: backupName, Namespace: sourceNamespace}, cnpgBackup); err != nil {
+		return ctrl.Result{}, fmt.Errorf("failed to get CNPG Backup %s/%s: %w", sourceNamespace, backupName, err)
+	}
+	var sourceSnapshotName string
+	for _, element := range cnpgBackup.Status.BackupSnapshotStatus.Elements {
+		if element.Type == cnpgSnapshotPgDataType {
+			sourceSnapshotName = element.Name
+		}
+	}
+	if sourceSnapshotName == "" {
+		return ctrl.Result{}, fmt.Errorf("CNPG Backup %s/%s has no %s snapshot", sourceNamespace, backupName, cnpgSnapshotPgDataType)
+	}
+
+	sourceSnapshot := &snapshotv1.VolumeSnapshot{}
+	if err := r.Get(ctx, types.NamespacedName{Name: sourceSnapshotName, Namespace: sourceNamespace}, sourceSnapshot); err != nil {
+		return ctrl.Result{}, fmt.Errorf("failed to get VolumeSnapshot %s/%s: %w", sourceNamespace, sourceSnapshotName, err)
+	}
+	if sourceSnapshot.Status == nil || sourceSnapshot.Status.BoundVolumeSnapshotContentName == nil {
+		return ctrl.Result{}, fmt.Errorf("VolumeSnapshot %s/%s is not bound to a VolumeSnapshotContent", sourceNamespace, sourceSnapshotName)
+	}
+	sourceContent := &snapshotv1.VolumeSnapshotContent{}
+	if err := r.Get(ctx, types.NamespacedName{Name: *sourceSnapshot.Status.BoundVolumeSnapshotContentName}, sourceContent); err != nil {
+		return ctrl.Result{}, fmt.Errorf("failed to get VolumeSnapshotContent %s: %w", *sourceSnapshot.Status.BoundVolumeSnapshotContentName, err)
+	}
+	if sourceContent.Status == nil || sourceContent.Status.SnapshotHandle == nil {
+		return ctrl.Result{}, fmt.Errorf("VolumeSnapshotContent %s has no snapshot handle", sourceContent.Name)
+	}
+
+	labels := map[string]string{
+		util.LabelCluster:   documentdb.Name,
+		util.LabelNamespace: documentdb.Namespace,
+	}
+	// Retain keeps the storage snapshot, which belongs to the source
+	// VolumeSnapshot, when the imported one is deleted.
+	content := &snapshotv1.VolumeSnapshotContent{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:   util.CloneSnapshotContentName(documentdb.Name, documentdb.Namespace),
+			Labels: labels,
+		},
+		Spec: snapshotv1.VolumeSnapshotContentSpec{
+			VolumeSnapshotRef: corev1.ObjectReference{
+				Kind:       "VolumeSnapshot",
+				APIVersion: snapshotv1.SchemeGroupVersion.String(),
+				Name:       snapshotName,
+				Namespace:  documentdb.Namespace,
+			},
+			DeletionPolicy:          snapshotv1.VolumeSnapshotContentRetain,
+			Driver:                  sourceContent.Spec.Driver,
+			VolumeSnapshotClassName: sourceContent.Spec.VolumeSnapshotClassName,
+			Source:                  snapshotv1.VolumeSnapshotContentSource{SnapshotHandle: sourceContent.Status.SnapshotHandle},
+			SourceVolumeMode:        sourceContent.Spec.SourceVolumeMode,
+		},
+	}
+	if err := r.Create(ctx, content); err != nil && !errors.IsAlreadyExists(err) {
+		return ctrl.Result{}, fmt.Errorf("failed to create VolumeSnapshotContent %s: %w", content.Name, err)
+	}
+
+	// CNPG recovers the cluster from the annotations it wrote on the source
+	// snapshot, such as the backup label file.
+	annotations := map[string]string{}
+	maps.Copy(annotations, sourceSnapshot.Annotations)
+	maps.DeleteFunc(annotations, func(key, _ string) bool { return !strings.HasPrefix(key, cnpgAnnotationPrefix) })
+	snapshot = &snapshotv1.VolumeSnapshot{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:        snapshotName,
+			Namespace:   documentdb.Namespace,
+			Labels:      labels,
+			Annotations: annotations,
+		},
+		Spec: snapshotv1.VolumeSnapshotSpec{
+			Source:                  snapshotv1.VolumeSnapshotSource{VolumeSnapshotContentName: ptr.To(content.Name)},
+			VolumeSnapshotClassName: sourceSnapshot.Spec.VolumeSnapshotClassName,
+		},
+	}
+	if err := controllerutil.SetControllerReference(documentdb, snapshot, r.Scheme); err != nil {
+		return ctrl.Result{}, fmt.Errorf("failed to set owner reference: %w", err)
+	}
+	if err := r.Create(ctx, snapshot); err != nil && !errors.IsAlreadyExists(err) {
+		return ctrl.Result{}, fmt.Errorf("failed to create clone VolumeSnapshot %s: %w", snapshotName, err)
+	}
+	logger.Info("Imported the clone source snapshot", "source", sourceSnapshotName, "snapshot", snapshotName, "content", content.Name)
+	return ctrl.Result{RequeueAfter: RequeueAfterShort}, nil
+}
+
+// deleteCloneSnapshot deletes the snapshot imported for a cross-namespace
+// clone, and its VolumeSnapshotContent.
+func (r *DocumentDBReconciler) deleteCloneSnapshot(ctx context.Context, documentdb *dbpreview.DocumentDB) error {
+	for _, obj := range []client.Object{
+		&snapshotv1.VolumeSnapshot{ObjectMeta: metav1.ObjectMeta{Name: util.CloneSnapshotName(documentdb.Name), Namespace: documentdb.Namespace}},
+		&snapshotv1.VolumeSnapshotContent{ObjectMeta: metav1.ObjectMeta{Name: util.CloneSnapshotContentName(documentdb.Name, documentdb.Namespace)}},
+	} {
+		if err := r.Delete(ctx, obj); err != nil {
+			if errors.IsNotFound(err) {
+				continue
+			}
+			return fmt.Errorf("failed to delete %s of the clone: %w", obj.GetName(), err)
+		}
+		log.FromContext(ctx).Info("Deleted imported clone snapshot", "name", obj.GetName())
+	}
+	return nil
+}
+
+// recordCloneWarning records a Warning event of the clone on documentdb.
+func (r *DocumentDBReconciler) recordCloneWarning(documentdb *dbpreview.DocumentDB, reason, message string) {
+	if r.Recorder != nil {
+		r.Recorder.Event(documentdb, corev1.EventTypeWarning, reason, message)
+	}
+}
@@ -0,0 +1,187 @@
+// Copyright (c) Microsoft Corporation.
+// Licensed under the MIT License.
+
+package controller
+
+import (
+	"context"
+
+	cnpgv1 "github.com/cloudnative-pg/cloudnative-pg/api/v1"
+	snapshotv1 "github.com/kubernetes-csi/external-snapshotter/client/v8/apis/volumesnapshot/v1"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/client-go/tools/record"
+	"k8s.io/utils/ptr"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	dbpreview "github.com/documentdb/documentdb-operator/api/preview"
+	util "github.com/documentdb/documentdb-operator/internal/utils"
+)
+
+var _ = Describe("Clone", func() {
+	const (
+		sourceNamespace = "prod"
+		sourceName      = "orders"
+	)
+
+	var (
+		ctx        context.Context
+		scheme     *runtime.Scheme
+		recorder   *record.FakeRecorder
+		source     *dbpreview.DocumentDB
+		documentdb *dbpreview.DocumentDB
+	)
+
+	newReconciler := func(objects ...client.Object) *DocumentDBReconciler {
+		return &DocumentDBReconciler{
+			Client:   fake.NewClientBuilder().WithScheme(scheme).WithObjects(objects...).WithStatusSubresource(&dbpreview.Backup{}).Build(),
+			Scheme:   scheme,
+			Recorder: recorder,
+		}
+	}
+
+	cloneIn := func(namespace string) *dbpreview.DocumentDB {
+		return &dbpreview.DocumentDB{
+			ObjectMeta: metav1.ObjectMeta{Name: "orders-staging", Namespace: namespace, UID: "uid"},
+			Spec: dbpreview.DocumentDBSpec{
+				Bootstrap: &dbpreview.BootstrapConfiguration{
+					Clone: &dbpreview.CloneConfiguration{SourceRef: dbpreview.CloneSourceReference{Name: sourceName, Namespace: sourceNamespace}},
+				},
+			},
+		}
+	}
+
+	completedBackup := func(name string) *dbpreview.Backup {
+		return &dbpreview.Backup{
+			ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: sourceNamespace},
+			Spec:       dbpreview.BackupSpec{Cluster: cnpgv1.LocalObjectReference{Name: sourceName}},
+			Status:     dbpreview.BackupStatus{Phase: cnpgv1.BackupPhaseCompleted},
+		}
+	}
+
+	BeforeEach(func() {
+		ctx = context.Background()
+		scheme = runtime.NewScheme()
+		Expect(dbpreview.AddToScheme(scheme)).To(Succeed())
+		Expect(cnpgv1.AddToScheme(scheme)).To(Succeed())
+		Expect(snapshotv1.AddToScheme(scheme)).To(Succeed())
+		recorder = record.NewFakeRecorder(10)
+
+		source = &dbpreview.DocumentDB{ObjectMeta: metav1.ObjectMeta{Name: sourceName, Namespace: sourceNamespace}}
+		documentdb = cloneIn(sourceNamespace)
+	})
+
+	It("does nothing without spec.bootstrap.clone", func() {
+		reconciler := newReconciler()
+		documentdb.Spec.Bootstrap = nil
+		result, err := reconciler.reconcileClone(ctx, documentdb, documentdb.Name, true)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(result.RequeueAfter).To(BeZero())
+	})
+
+	It("backs up the source and waits for the Backup to complete", func() {
+		reconciler := newReconciler(source)
+		result, err := reconciler.reconcileClone(ctx, documentdb, documentdb.Name, true)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(result.RequeueAfter).To(Equal(RequeueAfterShort))
+		Expect(recorder.Events).To(Receive(ContainSubstring("CloneBackupStarted")))
+
+		backup := &dbpreview.Backup{}
+		Expect(reconciler.Get(ctx, client.ObjectKey{Name: "orders-staging-clone", Namespace: sourceNamespace}, backup)).To(Succeed())
+		Expect(backup.Spec.Cluster.Name).To(Equal(sourceName))
+		Expect(*backup.Spec.RetentionDays).To(Equal(cloneBackupRetentionDays))
+
+		result, err = reconciler.reconcileClone(ctx, documentdb, documentdb.Name, true)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(result.RequeueAfter).To(Equal(RequeueAfterShort))
+
+		backup.Status.Phase = cnpgv1.BackupPhaseCompleted
+		Expect(reconciler.Status().Update(ctx, backup)).To(Succeed())
+		result, err = reconciler.reconcileClone(ctx, documentdb, documentdb.Name, true)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(result.RequeueAfter).To(BeZero())
+	})
+
+	It("reports a failed Backup of the source", func() {
+		backup := completedBackup("orders-staging-clone")
+		backup.Status = dbpreview.BackupStatus{Phase: cnpgv1.BackupPhaseFailed, Message: "snapshot failed"}
+		reconciler := newReconciler(source, backup)
+
+		result, err := reconciler.reconcileClone(ctx, documentdb, documentdb.Name, true)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(result.RequeueAfter).To(Equal(RequeueAfterLong))
+		Expect(recorder.Events).To(Receive(ContainSubstring("Warning CloneBackupFailed")))
+	})
+
+	It("does not clone into another namespace unless the source allows it", func() {
+		reconciler := newReconciler(source)
+		documentdb = cloneIn("qa")
+
+		result, err := reconciler.reconcileClone(ctx, documentdb, documentdb.Name, true)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(result.RequeueAfter).To(Equal(RequeueAfterLong))
+		Expect(recorder.Events).To(Receive(ContainSubstring("Warning CloneNotAllowed")))
+
+		err = reconciler.Get(ctx, client.ObjectKey{Name: "qa-orders-staging-clone", Namespace: sourceNamespace}, &dbpreview.Backup{})
+		Expect(apierrors.IsNotFound(err)).To(BeTrue())
+	})
+
+	It("imports the snapshot of the Backup into another namespace and cleans it up", func() {
+		source.Annotations = map[string]string{util.ALLOW_CLONE_TO_NAMESPACES_ANNOTATION: "qa"}
+		documentdb = cloneIn("qa")
+		cnpgBackup := &cnpgv1.Backup{
+			ObjectMeta: metav1.ObjectMeta{Name: "qa-orders-staging-clone", Namespace: sourceNamespace},
+			Status: cnpgv1.BackupStatus{BackupSnapshotStatus: cnpgv1.BackupSnapshotStatus{
+				Elements: []cnpgv1.BackupSnapshotElementStatus{{Name: "orders-1-snapshot", Type: "PG_DATA"}},
+			}},
+		}
+		sourceSnapshot := &snapshotv1.VolumeSnapshot{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:        "orders-1-snapshot",
+				Namespace:   sourceNamespace,
+				Annotations: map[string]string{"cnpg.io/backupLabelFile": "label", "other.io/annotation": "value"},
+			},
+			Status: &snapshotv1.VolumeSnapshotStatus{BoundVolumeSnapshotContentName: ptr.To("snapcontent-1")},
+		}
+		sourceContent := &snapshotv1.VolumeSnapshotContent{
+			ObjectMeta: metav1.ObjectMeta{Name: "snapcontent-1"},
+			Spec:       snapshotv1.VolumeSnapshotContentSpec{Driver: "disk.csi.azure.com"},
+			Status:     &snapshotv1.VolumeSnapshotContentStatus{SnapshotHandle: ptr.To("handle-1")},
+		}
+		reconciler := newReconciler(source, completedBackup("qa-orders-staging-clone"), cnpgBackup, sourceSnapshot, sourceContent)
+
+		result, err := reconciler.reconcileClone(ctx, documentdb, documentdb.Name, true)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(result.RequeueAfter).To(Equal(RequeueAfterShort))
+
+		content := &snapshotv1.VolumeSnapshotContent{}
+		Expect(reconciler.Get(ctx, client.ObjectKey{Name: "qa-orders-staging-clone"}, content)).To(Succeed())
+		Expect(*content.Spec.Source.SnapshotHandle).To(Equal("handle-1"))
+		Expect(content.Spec.DeletionPolicy).To(Equal(snapshotv1.VolumeSnapshotContentRetain))
+		Expect(content.Spec.VolumeSnapshotRef.Namespace).To(Equal("qa"))
+
+		snapshot := &snapshotv1.VolumeSnapshot{}
+		Expect(reconciler.Get(ctx, client.ObjectKey{Name: "orders-staging-clone", Namespace: "qa"}, snapshot)).To(Succeed())
+		Expect(*snapshot.Spec.Source.VolumeSnapshotContentName).To(Equal(content.Name))
+		Expect(snapshot.Annotations).To(Equal(map[string]string{"cnpg.io/backupLabelFile": "label"}))
+
+		snapshot.Status = &snapshotv1.VolumeSnapshotStatus{ReadyToUse: ptr.To(true)}
+		Expect(reconciler.Update(ctx, snapshot)).To(Succeed())
+		result, err = reconciler.reconcileClone(ctx, documentdb, documentdb.Name, true)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(result.RequeueAfter).To(BeZero())
+
+		Expect(reconciler.Create(ctx, &cnpgv1.Cluster{
+			ObjectMeta: metav1.ObjectMeta{Name: documentdb.Name, Namespace: "qa"},
+			Status:     cnpgv1.ClusterStatus{Phase: cnpgClusterHealthyPhase},
+		})).To(Succeed())
+		_, err = reconciler.reconcileClone(ctx, documentdb, documentdb.Name, true)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(apierrors.IsNotFound(reconciler.Get(ctx, client.ObjectKey{Name: "orders-staging-clone", Namespace: "qa"}, &snapshotv1.VolumeSnapshot{}))).To(BeTrue())
+		Expect(apierrors.IsNotFound(reconciler.Get(ctx, client.ObjectKey{Name: "qa-orders-staging-clone"}, &snapshotv1.VolumeSnapshotContent{}))).To(BeTrue())
+	})
+})
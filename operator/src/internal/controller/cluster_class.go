@@ -0,0 +1,32 @@
+// Copyright (c) Microsoft Corporation.
+// Licensed under the MIT License.
+
+package controller
+
+import (
+	"context"
+
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/log"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+
+	dbpreview "github.com/documentdb/documentdb-operator/api/preview"
+)
+
+// documentDBsForClusterClass maps a DocumentDBClusterClass to the DocumentDBs
+// that reference it, so that changes to the class roll out to them.
+func (r *DocumentDBReconciler) documentDBsForClusterClass(ctx context.Context, obj client.Object) []reconcile.Request {
+	documentdbs := &dbpreview.DocumentDBList{}
+	if err := r.List(ctx, documentdbs); err != nil {
+		log.FromContext(ctx).Error(err, "Failed to list DocumentDBs for cluster class", "class", obj.GetName())
+		return nil
+	}
+	var requests []reconcile.Request
+	for _, documentdb := range documentdbs.Items {
+		if documentdb.Spec.ClassRef != nil && documentdb.Spec.ClassRef.Name == obj.GetName() {
+			requests = append(requests, ctrl.Request{NamespacedName: client.ObjectKeyFromObject(&documentdb)})
+		}
+	}
+	return requests
+}
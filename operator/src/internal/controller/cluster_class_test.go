@@ -0,0 +1,147 @@
+// Copyright (c) Microsoft Corporation.
+// Licensed under the MIT License.
+
+package controller
+
+import (
+	"context"
+
+	cnpgv1 "github.com/cloudnative-pg/cloudnative-pg/api/v1"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	corev1 "k8s.io/api/core/v1"
+	rbacv1 "k8s.io/api/rbac/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/tools/record"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	dbpreview "github.com/documentdb/documentdb-operator/api/preview"
+	util "github.com/documentdb/documentdb-operator/internal/utils"
+)
+
+var _ = Describe("DocumentDBClusterClass", func() {
+	const namespace = "default"
+
+	var (
+		ctx    context.Context
+		scheme *runtime.Scheme
+		class  *dbpreview.DocumentDBClusterClass
+	)
+
+	BeforeEach(func() {
+		ctx = context.Background()
+		scheme = runtime.NewScheme()
+		Expect(dbpreview.AddToScheme(scheme)).To(Succeed())
+		Expect(cnpgv1.AddToScheme(scheme)).To(Succeed())
+		Expect(corev1.AddToScheme(scheme)).To(Succeed())
+		Expect(rbacv1.AddToScheme(scheme)).To(Succeed())
+		class = &dbpreview.DocumentDBClusterClass{
+			ObjectMeta: metav1.ObjectMeta{Name: "standard"},
+			Spec: dbpreview.DocumentDBClusterClassSpec{
+				Sizes:            []dbpreview.ClusterClassSize{{Name: "small", PvcSize: "10Gi", Memory: "2Gi"}},
+				InstancesPerNode: 2,
+			},
+		}
+	})
+
+	newDocumentDB := func(name, className string) *dbpreview.DocumentDB {
+		documentdb := &dbpreview.DocumentDB{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:       name,
+				Namespace:  namespace,
+				Finalizers: []string{documentDBFinalizer},
+			},
+		}
+		if className != "" {
+			documentdb.Spec.ClassRef = &dbpreview.ClusterClassReference{Name: className}
+		}
+		return documentdb
+	}
+
+	It("merges the referenced class into the DocumentDB spec without persisting it", func() {
+		documentdb := newDocumentDB("db", "standard")
+		c := fake.NewClientBuilder().WithScheme(scheme).WithObjects(class, documentdb).Build()
+
+		Expect(util.ResolveClusterClass(ctx, c, documentdb)).To(Succeed())
+		Expect(documentdb.Spec.InstancesPerNode).To(Equal(2))
+		Expect(documentdb.Spec.Resource.Storage.PvcSize).To(Equal("10Gi"))
+		Expect(documentdb.Spec.Resource.Memory).To(Equal("2Gi"))
+
+		stored := &dbpreview.DocumentDB{}
+		Expect(c.Get(ctx, types.NamespacedName{Name: "db", Namespace: namespace}, stored)).To(Succeed())
+		Expect(stored.Spec.InstancesPerNode).To(BeZero())
+	})
+
+	It("keeps the resolved class across status updates", func() {
+		documentdb := newDocumentDB("db", "standard")
+		c := fake.NewClientBuilder().
+			WithScheme(scheme).
+			WithObjects(class, documentdb).
+			WithStatusSubresource(&dbpreview.DocumentDB{}).
+			Build()
+		reconciler := &DocumentDBReconciler{Client: c, Scheme: scheme}
+
+		Expect(util.ResolveClusterClass(ctx, c, documentdb)).To(Succeed())
+		documentdb.Status.Status = "Cluster in healthy state"
+		Expect(reconciler.updateStatus(ctx, documentdb)).To(Succeed())
+		Expect(documentdb.Spec.InstancesPerNode).To(Equal(2))
+		Expect(documentdb.Spec.Resource.Storage.PvcSize).To(Equal("10Gi"))
+
+		stored := &dbpreview.DocumentDB{}
+		Expect(c.Get(ctx, types.NamespacedName{Name: "db", Namespace: namespace}, stored)).To(Succeed())
+		Expect(stored.Status.Status).To(Equal("Cluster in healthy state"))
+		Expect(stored.Spec.InstancesPerNode).To(BeZero())
+		Expect(documentdb.ResourceVersion).To(Equal(stored.ResourceVersion))
+	})
+
+	It("renders the CNPG Cluster from the class", func() {
+		documentdb := newDocumentDB("db", "standard")
+		documentdb.Annotations = map[string]string{util.DRY_RUN_ANNOTATION: "true"}
+		c := fake.NewClientBuilder().
+			WithScheme(scheme).
+			WithObjects(class, documentdb).
+			WithStatusSubresource(&dbpreview.DocumentDB{}).
+			Build()
+		reconciler := &DocumentDBReconciler{Client: c, Scheme: scheme, Recorder: record.NewFakeRecorder(10)}
+
+		_, err := reconciler.Reconcile(ctx, ctrl.Request{NamespacedName: types.NamespacedName{Name: "db", Namespace: namespace}})
+		Expect(err).ToNot(HaveOccurred())
+
+		cm := &corev1.ConfigMap{}
+		Expect(c.Get(ctx, types.NamespacedName{Name: dryRunConfigMapName("db"), Namespace: namespace}, cm)).To(Succeed())
+		Expect(cm.Data[dryRunClusterKey]).To(ContainSubstring("instances: 2"))
+		Expect(cm.Data[dryRunClusterKey]).To(ContainSubstring("size: 10Gi"))
+	})
+
+	It("emits a warning and requeues when the class does not exist", func() {
+		documentdb := newDocumentDB("db", "missing")
+		recorder := record.NewFakeRecorder(10)
+		reconciler := &DocumentDBReconciler{
+			Client:   fake.NewClientBuilder().WithScheme(scheme).WithObjects(documentdb).Build(),
+			Scheme:   scheme,
+			Recorder: recorder,
+		}
+
+		result, err := reconciler.Reconcile(ctx, ctrl.Request{NamespacedName: types.NamespacedName{Name: "db", Namespace: namespace}})
+		Expect(err).ToNot(HaveOccurred())
+		Expect(result.RequeueAfter).To(Equal(RequeueAfterLong))
+		Expect(recorder.Events).To(Receive(ContainSubstring("ClusterClassNotResolved")))
+	})
+
+	It("maps a class to the DocumentDBs that reference it", func() {
+		reconciler := &DocumentDBReconciler{
+			Client: fake.NewClientBuilder().WithScheme(scheme).WithObjects(
+				newDocumentDB("uses-class", "standard"),
+				newDocumentDB("other-class", "premium"),
+				newDocumentDB("no-class", ""),
+			).Build(),
+			Scheme: scheme,
+		}
+
+		requests := reconciler.documentDBsForClusterClass(ctx, class)
+		Expect(requests).To(ConsistOf(ctrl.Request{NamespacedName: types.NamespacedName{Name: "uses-class", Namespace: namespace}}))
+	})
+})
@@ -0,0 +1,105 @@
+// Copyright (c) Microsoft Corporation.
+// Licensed under the MIT License.
+
+package controller
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/types"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
+	"sigs.k8s.io/controller-runtime/pkg/log"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+
+	dbpreview "github.com/documentdb/documentdb-operator/api/preview"
+	util "github.com/documentdb/documentdb-operator/internal/utils"
+)
+
+const (
+	// connectionSecretSuffix names the Secret, <documentdb>-connection-string,
+	// that holds the resolved connection string of a DocumentDB.
+	connectionSecretSuffix = "-connection-string"
+
+	// Connection string Secret keys.
+	connectionSecretURIKey      = "uri"
+	connectionSecretHostKey     = "host"
+	connectionSecretPortKey     = "port"
+	connectionSecretUsernameKey = "username"
+	connectionSecretPasswordKey = "password"
+)
+
+func connectionSecretName(documentdbName string) string {
+	return documentdbName + connectionSecretSuffix
+}
+
+// reconcileConnectionSecret writes the connection string of documentdb, with
+// the credentials of its credential Secret resolved, to the connection string
+// Secret that applications can mount. seeds are those of connectionSeeds, the
+// DocumentDB Service first. Nothing is written until the credential Secret
+// exists.
+func (r *DocumentDBReconciler) reconcileConnectionSecret(ctx context.Context, documentdb *dbpreview.DocumentDB, seeds []string, trustTLS bool) error {
+	logger := log.FromContext(ctx)
+
+	credentials := &corev1.Secret{}
+	credentialsKey := types.NamespacedName{Name: util.CredentialSecretName(documentdb), Namespace: documentdb.Namespace}
+	if err := r.Get(ctx, credentialsKey, credentials); err != nil {
+		if errors.IsNotFound(err) {
+			logger.V(1).Info("Credential Secret not found, skipping the connection string Secret", "name", credentialsKey.Name)
+			return nil
+		}
+		return fmt.Errorf("failed to get credential Secret %s: %w", credentialsKey.Name, err)
+	}
+	username := string(credentials.Data["username"])
+	password := string(credentials.Data["password"])
+
+	secret := &corev1.Secret{}
+	secret.Name = connectionSecretName(documentdb.Name)
+	secret.Namespace = documentdb.Namespace
+	result, err := controllerutil.CreateOrUpdate(ctx, r.Client, secret, func() error {
+		if err := controllerutil.SetControllerReference(documentdb, secret, r.Scheme); err != nil {
+			return fmt.Errorf("failed to set owner reference: %w", err)
+		}
+		secret.Labels = util.ChildLabels(documentdb, map[string]string{util.LABEL_DOCUMENTDB_NAME: documentdb.Name})
+		secret.Type = corev1.SecretTypeOpaque
+		port := util.GetGatewayPort(documentdb)
+		secret.Data = map[string][]byte{
+			connectionSecretURIKey:      []byte(util.GenerateHAConnectionURI(seeds, port, username, password, trustTLS)),
+			connectionSecretHostKey:     []byte(seeds[0]),
+			connectionSecretPortKey:     []byte(strconv.Itoa(int(port))),
+			connectionSecretUsernameKey: []byte(username),
+			connectionSecretPasswordKey: []byte(password),
+		}
+		return nil
+	})
+	if err != nil {
+		return fmt.Errorf("failed to reconcile connection string Secret %s: %w", secret.Name, err)
+	}
+	if result != controllerutil.OperationResultNone {
+		logger.Info("Connection string Secret reconciled", "name", secret.Name, "operation", result)
+	}
+	return nil
+}
+
+// documentDBsForCredentialSecret maps a Secret to the DocumentDBs of its
+// namespace that use it as their credential Secret, so that a password change
+// is carried to their connection string Secret.
+func (r *DocumentDBReconciler) documentDBsForCredentialSecret(ctx context.Context, obj client.Object) []reconcile.Request {
+	documentdbs := &dbpreview.DocumentDBList{}
+	if err := r.List(ctx, documentdbs, client.InNamespace(obj.GetNamespace())); err != nil {
+		log.FromContext(ctx).Error(err, "Failed to list DocumentDBs for credential Secret", "namespace", obj.GetNamespace())
+		return nil
+	}
+	var requests []reconcile.Request
+	for _, documentdb := range documentdbs.Items {
+		if util.CredentialSecretName(&documentdb) == obj.GetName() {
+			requests = append(requests, ctrl.Request{NamespacedName: client.ObjectKeyFromObject(&documentdb)})
+		}
+	}
+	return requests
+}
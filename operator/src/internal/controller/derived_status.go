@@ -0,0 +1,30 @@
+// Copyright (c) Microsoft Corporation.
+// Licensed under the MIT License.
+
+package controller
+
+import (
+	dbpreview "github.com/documentdb/documentdb-operator/api/preview"
+)
+
+// reconcileDerivedStatus publishes in the status of documentdb the values the
+// operator derives from its spec, so that tools need not compute them again:
+// the name of its local CNPG Cluster and the retention of its backups and
+// volumes. It returns whether the status changed.
+func reconcileDerivedStatus(documentdb *dbpreview.DocumentDB, cnpgClusterName string) bool {
+	status := &documentdb.Status
+	changed := false
+	if status.CNPGClusterName != cnpgClusterName {
+		status.CNPGClusterName = cnpgClusterName
+		changed = true
+	}
+	if days := documentdb.EffectiveBackupRetentionDays(); status.BackupRetentionDays != days {
+		status.BackupRetentionDays = days
+		changed = true
+	}
+	if policy := documentdb.EffectivePersistentVolumeReclaimPolicy(); status.PersistentVolumeReclaimPolicy != policy {
+		status.PersistentVolumeReclaimPolicy = policy
+		changed = true
+	}
+	return changed
+}
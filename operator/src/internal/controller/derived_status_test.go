@@ -0,0 +1,30 @@
+// Copyright (c) Microsoft Corporation.
+// Licensed under the MIT License.
+
+package controller
+
+import (
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	dbpreview "github.com/documentdb/documentdb-operator/api/preview"
+)
+
+var _ = Describe("Derived status", func() {
+	It("publishes the CNPG Cluster name and the effective retention", func() {
+		documentdb := &dbpreview.DocumentDB{ObjectMeta: metav1.ObjectMeta{Name: "db", Namespace: "default"}}
+		Expect(reconcileDerivedStatus(documentdb, "db")).To(BeTrue())
+		Expect(documentdb.Status.CNPGClusterName).To(Equal("db"))
+		Expect(documentdb.Status.BackupRetentionDays).To(Equal(dbpreview.DefaultBackupRetentionDays))
+		Expect(documentdb.Status.PersistentVolumeReclaimPolicy).To(Equal("Retain"))
+		Expect(reconcileDerivedStatus(documentdb, "db")).To(BeFalse())
+
+		documentdb.Spec.Backup = &dbpreview.BackupConfiguration{RetentionDays: 7}
+		documentdb.Spec.Resource.Storage.PersistentVolumeReclaimPolicy = "Delete"
+		Expect(reconcileDerivedStatus(documentdb, "db-member-2")).To(BeTrue())
+		Expect(documentdb.Status.CNPGClusterName).To(Equal("db-member-2"))
+		Expect(documentdb.Status.BackupRetentionDays).To(Equal(7))
+		Expect(documentdb.Status.PersistentVolumeReclaimPolicy).To(Equal("Delete"))
+	})
+})
@@ -4,9 +4,11 @@
 package controller
 
 import (
-	"bytes"
 	"context"
+	"encoding/json"
 	"fmt"
+	"io"
+	"net"
 	"slices"
 	"strconv"
 	"strings"
@@ -16,23 +18,27 @@ import (
 	cnpgv1 "github.com/cloudnative-pg/cloudnative-pg/api/v1"
 	"github.com/cloudnative-pg/cloudnative-pg/pkg/resources/status"
 	pgTime "github.com/cloudnative-pg/machinery/pkg/postgres/time"
+	snapshotv1 "github.com/kubernetes-csi/external-snapshotter/client/v8/apis/volumesnapshot/v1"
+	batchv1 "k8s.io/api/batch/v1"
 	corev1 "k8s.io/api/core/v1"
 	rbacv1 "k8s.io/api/rbac/v1"
+	"k8s.io/apimachinery/pkg/api/equality"
 	"k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/runtime"
 	"k8s.io/apimachinery/pkg/types"
 	"k8s.io/client-go/kubernetes"
-	"k8s.io/client-go/kubernetes/scheme"
 	"k8s.io/client-go/rest"
 	"k8s.io/client-go/tools/record"
-	"k8s.io/client-go/tools/remotecommand"
 	ctrl "sigs.k8s.io/controller-runtime"
 	"sigs.k8s.io/controller-runtime/pkg/builder"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
 	"sigs.k8s.io/controller-runtime/pkg/event"
+	"sigs.k8s.io/controller-runtime/pkg/handler"
 	"sigs.k8s.io/controller-runtime/pkg/log"
 	"sigs.k8s.io/controller-runtime/pkg/predicate"
+	"sigs.k8s.io/controller-runtime/pkg/source"
 
 	dbpreview "github.com/documentdb/documentdb-operator/api/preview"
 	cnpg "github.com/documentdb/documentdb-operator/internal/cnpg"
@@ -47,6 +53,10 @@ const (
 	// documentDBFinalizer ensures we can emit PV retention warnings before deletion completes
 	documentDBFinalizer = "documentdb.io/pv-retention-finalizer"
 
+	// extensionUpgradeTimeout bounds ALTER EXTENSION documentdb UPDATE, which
+	// is allowed to finish even when the operator is shutting down.
+	extensionUpgradeTimeout = 5 * time.Minute
+
 	// cnpgClusterHealthyPhase is the CNPG cluster status phase indicating a healthy cluster.
 	// This value is from CNPG's internal status representation.
 	cnpgClusterHealthyPhase = "Cluster in healthy state"
@@ -64,6 +74,51 @@ type DocumentDBReconciler struct {
 	// Defaults to executeSQLCommand (real pod exec via SPDY). Override in tests
 	// to inject canned responses without requiring a live Kubernetes cluster.
 	SQLExecutor func(ctx context.Context, cluster *cnpgv1.Cluster, sqlCommand string) (string, error)
+	// PodExec runs commands in the pods of the CNPG clusters, for SQLExecutor
+	// among others. Defaults to a runner built from Config and Clientset.
+	PodExec *util.PodExecRunner
+	// queryCache keeps the output of the read-only queries of querySQL
+	// briefly. Nil in tests, where every query reaches SQLExecutor.
+	queryCache *sqlQueryCache
+	// cnpgVersions keeps the detected CloudNativePG version for
+	// cnpgVersionTTL. Nil in tests, where it is detected on every reconcile.
+	cnpgVersions *cnpgVersionCache
+	// healthPoller reads the extension versions and the replication grants
+	// from the primaries in the background. Nil in tests, where reconciles
+	// query them.
+	healthPoller *clusterHealthPoller
+	// MigrationSchemaCopier creates the collection tables of the source of
+	// spec.migration on the target, all of them or only the given tables.
+	// Defaults to copyMigrationSchema.
+	MigrationSchemaCopier func(ctx context.Context, source, target *cnpgv1.Cluster, tables []string) error
+	// BootstrapProgressProbe reads the progress of the bootstrap Job Pod of
+	// a CNPG Cluster. Defaults to probeBootstrapProgress.
+	BootstrapProgressProbe func(ctx context.Context, pod *corev1.Pod) (string, error)
+	// HostResolver resolves spec.exposeViaService.hostname. Defaults to
+	// net.DefaultResolver.LookupHost.
+	HostResolver func(ctx context.Context, host string) ([]string, error)
+	// MemberClientFactory builds a client of a member cluster from the
+	// kubeconfigSecret of its entry in spec.clusterReplication. Defaults to
+	// newMemberClient.
+	MemberClientFactory func(kubeconfig []byte) (client.Client, error)
+	// APIReader reads an object from the API server again when a write
+	// conflicts, since the cache may not have caught up with the conflicting
+	// write yet. Defaults to the API reader of the manager.
+	APIReader client.Reader
+	// memberMu guards memberClients and memberSyncRetries, which the member
+	// syncs running in the background share.
+	memberMu sync.Mutex
+	// memberClients caches the clients of the member clusters by kubeconfig
+	// Secret and key.
+	memberClients map[string]cachedMemberClient
+	// memberSyncRetries delays the member syncs of the DocumentDBs whose
+	// last sync failed.
+	memberSyncRetries map[types.NamespacedName]memberSyncRetry
+	// backgroundOps tracks work that outlives a reconcile (see backgroundOperations).
+	backgroundOps *backgroundOperations
+	// OperatorConfigEvents, when set, re-queues DocumentDBs after the operator
+	// settings are hot-reloaded (see OperatorConfigReconciler).
+	OperatorConfigEvents <-chan event.GenericEvent
 }
 
 var reconcileMutex sync.Mutex
@@ -71,9 +126,21 @@ var reconcileMutex sync.Mutex
 // +kubebuilder:rbac:groups=documentdb.io,resources=dbs,verbs=get;list;watch;create;update;patch;delete
 // +kubebuilder:rbac:groups=documentdb.io,resources=dbs/status,verbs=get;update;patch
 // +kubebuilder:rbac:groups=documentdb.io,resources=dbs/finalizers,verbs=update
+// +kubebuilder:rbac:groups=documentdb.io,resources=backups,verbs=get;list;watch;create
+// +kubebuilder:rbac:groups=postgresql.cnpg.io,resources=backups,verbs=get;list;watch
+// +kubebuilder:rbac:groups=postgresql.cnpg.io,resources=publications;subscriptions,verbs=get;list;watch;create;delete
+// +kubebuilder:rbac:groups=snapshot.storage.k8s.io,resources=volumesnapshots,verbs=get;list;watch;create;delete
+// +kubebuilder:rbac:groups=snapshot.storage.k8s.io,resources=volumesnapshotcontents,verbs=get;list;watch;create;delete
+// +kubebuilder:rbac:groups=documentdb.io,resources=documentdbclusterclasses,verbs=get;list;watch
 // +kubebuilder:rbac:groups="",resources=events,verbs=create;patch
+// +kubebuilder:rbac:groups="",resources=nodes,verbs=list
 // +kubebuilder:rbac:groups="",resources=persistentvolumeclaims,verbs=get;list;watch;create;delete
+// +kubebuilder:rbac:groups="",resources=pods,verbs=get;list;watch;patch;delete
 // +kubebuilder:rbac:groups="",resources=persistentvolumes,verbs=get;list;watch;update;patch
+// +kubebuilder:rbac:groups="",resources=secrets,verbs=get;list;watch;create;update;patch
+// +kubebuilder:rbac:groups=batch,resources=jobs,verbs=get;list;watch;create;delete
+// +kubebuilder:rbac:groups=batch,resources=cronjobs,verbs=get;list;watch;create;update;patch;delete
+// +kubebuilder:rbac:groups=placement.kubernetes-fleet.io,resources=clusterresourceplacements;resourceplacements,verbs=get;list;watch;create;update;patch
 func (r *DocumentDBReconciler) Reconcile(ctx context.Context, req ctrl.Request) (ctrl.Result, error) {
 	reconcileMutex.Lock()
 	defer reconcileMutex.Unlock()
@@ -96,14 +163,37 @@ func (r *DocumentDBReconciler) Reconcile(ctx context.Context, req ctrl.Request)
 		return ctrl.Result{}, err
 	}
 
+	// On a Fleet hub, the DocumentDB is only placed on its member clusters
+	if util.IsFleetHub() {
+		return r.reconcileFleetHub(ctx, documentdb)
+	}
+
 	// Handle finalizer lifecycle (add on create, remove on delete)
 	if done, result, err := r.reconcileFinalizer(ctx, documentdb); done || err != nil {
 		return result, err
 	}
 
+	// Create and update the DocumentDBs of the member clusters reached through
+	// a kubeconfigSecret, from the spec as stored, in the background
+	r.startMemberSync(documentdb)
+
+	// Fill the fields left unset from the referenced DocumentDBClusterClass,
+	// then from the defaults of the namespace recorded in the status
+	if err := util.ResolveClusterClass(ctx, r.Client, documentdb); err != nil {
+		r.recordReconcileFailure(ctx, documentdb, err, "Failed to resolve DocumentDBClusterClass")
+		if r.Recorder != nil {
+			r.Recorder.Event(documentdb, corev1.EventTypeWarning, "ClusterClassNotResolved", err.Error())
+		}
+		return ctrl.Result{RequeueAfter: RequeueAfterLong}, nil
+	}
+	if err := r.applyNamespaceDefaults(ctx, documentdb); err != nil {
+		r.recordReconcileFailure(ctx, documentdb, err, "Failed to apply namespace defaults")
+		return ctrl.Result{RequeueAfter: RequeueAfterShort}, nil
+	}
+
 	replicationContext, err := util.GetReplicationContext(ctx, r.Client, *documentdb)
 	if err != nil {
-		logger.Error(err, "Failed to determine replication context")
+		r.recordReconcileFailure(ctx, documentdb, err, "Failed to determine replication context")
 		return ctrl.Result{}, err
 	}
 
@@ -115,9 +205,24 @@ func (r *DocumentDBReconciler) Reconcile(ctx context.Context, req ctrl.Request)
 		if err := util.DeleteOwnedResources(ctx, r.Client, documentdb.ObjectMeta); err != nil {
 			return ctrl.Result{}, err
 		}
+		// Keep following the member clusters this DocumentDB describes
+		if memberSyncPending(documentdb) {
+			return ctrl.Result{RequeueAfter: RequeueAfterLong}, nil
+		} else if len(documentdb.Status.Members) > 0 {
+			return ctrl.Result{RequeueAfter: util.GetDriftCheckInterval()}, nil
+		}
 		return ctrl.Result{}, nil
 	}
 
+	// Hold off creating anything until the prerequisites are met
+	cnpgVersion := r.detectCNPGVersion(ctx)
+	if passed, err := r.reconcilePreflight(ctx, documentdb, replicationContext, cnpgVersion); err != nil {
+		r.recordReconcileFailure(ctx, documentdb, err, "Failed to run preflight checks")
+		return ctrl.Result{RequeueAfter: RequeueAfterShort}, nil
+	} else if !passed {
+		return ctrl.Result{RequeueAfter: RequeueAfterLong}, nil
+	}
+
 	var documentDbServiceIp string
 
 	// Only create/manage the service if ExposeViaService is configured
@@ -131,9 +236,13 @@ func (r *DocumentDBReconciler) Reconcile(ctx context.Context, req ctrl.Request)
 		ddbService := util.GetDocumentDBServiceDefinition(documentdb, replicationContext, req.Namespace, serviceType)
 
 		// Check if the DocumentDB Service already exists for this instance
-		foundService, err := util.UpsertService(ctx, r.Client, ddbService)
+		var staleAnnotations []string
+		if serviceType == corev1.ServiceTypeLoadBalancer {
+			staleAnnotations = util.StaleLoadBalancerAnnotations(replicationContext.Environment)
+		}
+		foundService, err := util.UpsertService(ctx, r.Client, ddbService, staleAnnotations...)
 		if err != nil {
-			logger.Error(err, "Failed to create DocumentDB Service; Requeuing.")
+			r.recordReconcileFailure(ctx, documentdb, err, "Failed to create DocumentDB Service")
 			return ctrl.Result{RequeueAfter: RequeueAfterShort}, nil
 		}
 
@@ -147,7 +256,7 @@ func (r *DocumentDBReconciler) Reconcile(ctx context.Context, req ctrl.Request)
 
 	// Ensure App ServiceAccount, Role and RoleBindings are created
 	if err := r.EnsureServiceAccountRoleAndRoleBinding(ctx, documentdb, req.Namespace); err != nil {
-		logger.Info("Failed to create ServiceAccount, Role and RoleBinding; Requeuing.")
+		r.recordReconcileFailure(ctx, documentdb, err, "Failed to create ServiceAccount, Role and RoleBinding")
 		return ctrl.Result{RequeueAfter: RequeueAfterShort}, nil
 	}
 
@@ -160,19 +269,52 @@ func (r *DocumentDBReconciler) Reconcile(ctx context.Context, req ctrl.Request)
 	if replicationContext.IsReplicating() {
 		err = r.AddClusterReplicationToClusterSpec(ctx, documentdb, replicationContext, desiredCnpgCluster)
 		if err != nil {
-			logger.Error(err, "Failed to add physical replication features cnpg Cluster spec")
+			r.recordReconcileFailure(ctx, documentdb, err, "Failed to add physical replication features cnpg Cluster spec")
 			return ctrl.Result{RequeueAfter: RequeueAfterShort}, nil
 		}
 	}
 
+	// In dry-run mode, publish the CNPG Cluster for review instead of applying it
+	if documentdb.Annotations[util.DRY_RUN_ANNOTATION] == "true" {
+		if err := r.renderDryRun(ctx, documentdb, desiredCnpgCluster); err != nil {
+			r.recordReconcileFailure(ctx, documentdb, err, "Failed to render dry-run CNPG Cluster")
+			return ctrl.Result{RequeueAfter: RequeueAfterShort}, nil
+		}
+		return ctrl.Result{}, nil
+	}
+	if err := r.deleteDryRunConfigMap(ctx, documentdb); err != nil {
+		r.recordReconcileFailure(ctx, documentdb, err, "Failed to clean up dry-run ConfigMap")
+		return ctrl.Result{RequeueAfter: RequeueAfterShort}, nil
+	}
+
+	// Pick the retained PV a new cluster adopts, which the next reconcile recovers from
+	if adopted, err := r.reconcileVolumeAdoption(ctx, documentdb, desiredCnpgCluster.Name, replicationContext.IsPrimary()); err != nil {
+		r.recordReconcileFailure(ctx, documentdb, err, "Failed to look for retained PersistentVolumes")
+		return ctrl.Result{RequeueAfter: RequeueAfterShort}, nil
+	} else if adopted {
+		if err := r.updateStatus(ctx, documentdb); err != nil {
+			logger.Error(err, "Failed to record the adopted PersistentVolume")
+			return ctrl.Result{RequeueAfter: RequeueAfterShort}, nil
+		}
+		return ctrl.Result{RequeueAfter: RequeueAfterShort}, nil
+	}
+
 	// Handle PV recovery lifecycle (create temp PVC before CNPG, cleanup after healthy)
 	if result, err := r.reconcilePVRecovery(ctx, documentdb, req.Namespace, desiredCnpgCluster.Name); err != nil {
-		logger.Error(err, "Failed to reconcile PV recovery")
+		r.recordReconcileFailure(ctx, documentdb, err, "Failed to reconcile PV recovery")
 		return result, err
 	} else if result.Requeue || result.RequeueAfter > 0 {
 		return result, nil
 	}
 
+	// Handle clone lifecycle (back up the source before CNPG, cleanup after healthy)
+	if result, err := r.reconcileClone(ctx, documentdb, desiredCnpgCluster.Name, replicationContext.IsPrimary()); err != nil {
+		r.recordReconcileFailure(ctx, documentdb, err, "Failed to reconcile clone")
+		return ctrl.Result{RequeueAfter: RequeueAfterShort}, nil
+	} else if result.RequeueAfter > 0 {
+		return result, nil
+	}
+
 	// Reconcile OTel Collector ConfigMap when monitoring is enabled.
 	// When monitoring is disabled or removed, delete the ConfigMap.
 	// The sidecar itself is added/removed via CNPG plugin parameters;
@@ -180,12 +322,12 @@ func (r *DocumentDBReconciler) Reconcile(ctx context.Context, req ctrl.Request)
 	// and CNPG manages the pod rollout.
 	if documentdb.Spec.Monitoring != nil && documentdb.Spec.Monitoring.Enabled {
 		if err := r.reconcileOtelConfigMap(ctx, documentdb, req.Namespace); err != nil {
-			logger.Error(err, "Failed to reconcile OTel ConfigMap")
+			r.recordReconcileFailure(ctx, documentdb, err, "Failed to reconcile OTel ConfigMap")
 			return ctrl.Result{RequeueAfter: RequeueAfterShort}, nil
 		}
 	} else {
 		if err := r.deleteOtelConfigMap(ctx, documentdb.Name, req.Namespace); err != nil {
-			logger.Error(err, "Failed to clean up OTel ConfigMap")
+			r.recordReconcileFailure(ctx, documentdb, err, "Failed to clean up OTel ConfigMap")
 			return ctrl.Result{RequeueAfter: RequeueAfterShort}, nil
 		}
 	}
@@ -193,16 +335,28 @@ func (r *DocumentDBReconciler) Reconcile(ctx context.Context, req ctrl.Request)
 	if err := r.Client.Get(ctx, types.NamespacedName{Name: desiredCnpgCluster.Name, Namespace: req.Namespace}, currentCnpgCluster); err != nil {
 		if errors.IsNotFound(err) {
 			if err := r.Client.Create(ctx, desiredCnpgCluster); err != nil {
-				logger.Error(err, "Failed to create CNPG Cluster")
+				r.recordReconcileFailure(ctx, documentdb, err, "Failed to create CNPG Cluster")
 				return ctrl.Result{RequeueAfter: RequeueAfterShort}, nil
 			}
 			logger.Info("CNPG Cluster created successfully", "Cluster.Name", desiredCnpgCluster.Name, "Namespace", desiredCnpgCluster.Namespace)
 			return ctrl.Result{RequeueAfter: RequeueAfterLong}, nil
 		}
-		logger.Error(err, "Failed to get CNPG Cluster")
+		r.recordReconcileFailure(ctx, documentdb, err, "Failed to get CNPG Cluster")
 		return ctrl.Result{RequeueAfter: RequeueAfterShort}, nil
 	}
 
+	// Take over a hand-built CNPG Cluster that was annotated for adoption
+	if currentCnpgCluster.Annotations[util.ADOPT_CLUSTER_ANNOTATION] == "true" && !metav1.IsControlledBy(currentCnpgCluster, documentdb) {
+		adopted, err := r.adoptCNPGCluster(ctx, documentdb, currentCnpgCluster)
+		if err != nil {
+			r.recordReconcileFailure(ctx, documentdb, err, "Failed to adopt CNPG Cluster")
+			return ctrl.Result{RequeueAfter: RequeueAfterShort}, nil
+		}
+		if !adopted {
+			return ctrl.Result{RequeueAfter: RequeueAfterLong}, nil
+		}
+	}
+
 	// Build replication patch ops (performs side effects: HTTP token reads, service creation).
 	// syncReplicationChanges handles non-replicating cases internally via nil checks.
 	replicationOps, err, requeueTime := r.syncReplicationChanges(ctx, currentCnpgCluster, desiredCnpgCluster, documentdb, replicationContext)
@@ -213,28 +367,43 @@ func (r *DocumentDBReconciler) Reconcile(ctx context.Context, req ctrl.Request)
 		return ctrl.Result{RequeueAfter: requeueTime}, nil
 	}
 
+	// Resume a demotion token wait left in progress by a previous operator instance.
+	r.startDemotionTokenWait(documentdb, replicationContext)
+
+	// Report manual changes to the CNPG Cluster before they are overwritten
+	if err := r.reportCNPGClusterDrift(ctx, documentdb, currentCnpgCluster, desiredCnpgCluster); err != nil {
+		logger.Error(err, "Failed to report CNPG Cluster drift")
+	}
+
 	// Sync all CNPG Cluster changes in one atomic patch (images + plugins + replication)
 	if err := cnpg.SyncCnpgCluster(ctx, r.Client, currentCnpgCluster, desiredCnpgCluster, replicationOps); err != nil {
-		logger.Error(err, "Failed to sync CNPG Cluster spec")
+		r.recordReconcileFailure(ctx, documentdb, err, "Failed to sync CNPG Cluster spec")
 		return ctrl.Result{RequeueAfter: RequeueAfterShort}, nil
 	}
 
 	if slices.Contains(currentCnpgCluster.Status.InstancesStatus[cnpgv1.PodHealthy], currentCnpgCluster.Status.CurrentPrimary) && replicationContext.IsPrimary() {
-		// Check if permissions have already been granted
-		checkCommand := "SELECT 1 FROM pg_roles WHERE rolname = 'streaming_replica' AND pg_has_role('streaming_replica', 'documentdb_admin_role', 'USAGE');"
-		output, err := r.SQLExecutor(ctx, currentCnpgCluster, checkCommand)
+		// Check if permissions have already been granted. Until the poller
+		// has read it from the primary, the grant waits for its re-queue.
+		granted, known, err := r.replicationRoleGranted(ctx, documentdb, currentCnpgCluster)
 		if err != nil {
-			logger.Error(err, "Failed to check if permissions already granted")
+			r.recordReconcileFailure(ctx, documentdb, err, "Failed to check if permissions already granted")
 			return ctrl.Result{RequeueAfter: RequeueAfterLong}, nil
 		}
 
-		if !strings.Contains(output, "(1 row)") {
+		if known && !granted {
 			grantCommand := "GRANT documentdb_admin_role TO streaming_replica;"
 
 			if _, err := r.SQLExecutor(ctx, currentCnpgCluster, grantCommand); err != nil {
-				logger.Error(err, "Failed to grant permissions to streaming_replica")
+				r.recordReconcileFailure(ctx, documentdb, err, "Failed to grant permissions to streaming_replica")
 				return ctrl.Result{RequeueAfter: RequeueAfterShort}, nil
 			}
+			r.queryCache.invalidate(currentCnpgCluster)
+			r.healthPoller.invalidate(currentCnpgCluster)
+		}
+
+		if err := r.reconcileTTLSchedule(ctx, documentdb, currentCnpgCluster); err != nil {
+			r.recordReconcileFailure(ctx, documentdb, err, "Failed to reschedule the TTL task")
+			return ctrl.Result{RequeueAfter: RequeueAfterShort}, nil
 		}
 	}
 
@@ -243,7 +412,7 @@ func (r *DocumentDBReconciler) Reconcile(ctx context.Context, req ctrl.Request)
 		if documentdb.Status.TargetPrimary != currentCnpgCluster.Status.TargetPrimary {
 
 			if err = Promote(ctx, r.Client, currentCnpgCluster.Namespace, currentCnpgCluster.Name, documentdb.Status.TargetPrimary); err != nil {
-				logger.Error(err, "Failed to promote standby cluster to primary")
+				r.recordReconcileFailure(ctx, documentdb, err, "Failed to promote standby cluster to primary")
 				return ctrl.Result{RequeueAfter: RequeueAfterShort}, nil
 			}
 		} else if documentdb.Status.TargetPrimary != documentdb.Status.LocalPrimary &&
@@ -251,8 +420,8 @@ func (r *DocumentDBReconciler) Reconcile(ctx context.Context, req ctrl.Request)
 
 			logger.Info("Marking failover as complete")
 			documentdb.Status.LocalPrimary = currentCnpgCluster.Status.CurrentPrimary
-			if err := r.Status().Update(ctx, documentdb); err != nil {
-				logger.Error(err, "Failed to update DocumentDB status")
+			if err := r.updateStatus(ctx, documentdb); err != nil {
+				r.recordReconcileFailure(ctx, documentdb, err, "Failed to update DocumentDB status")
 				return ctrl.Result{RequeueAfter: RequeueAfterShort}, nil
 			}
 		}
@@ -268,18 +437,114 @@ func (r *DocumentDBReconciler) Reconcile(ctx context.Context, req ctrl.Request)
 			statusChanged = true
 		}
 
+		statusChanged = reconcileReadyCondition(documentdb, currentCnpgCluster) || statusChanged
+		statusChanged = reconcileDerivedStatus(documentdb, currentCnpgCluster.Name) || statusChanged
+
+		if cnpgVersion != nil && documentdb.Status.CNPGVersion != cnpgVersion.String() {
+			documentdb.Status.CNPGVersion = cnpgVersion.String()
+			statusChanged = true
+		}
+
+		instances, err := r.instanceStatuses(ctx, currentCnpgCluster)
+		if err != nil {
+			logger.Error(err, "Failed to collect instance status")
+		} else if !equality.Semantic.DeepEqual(documentdb.Status.Instances, instances) {
+			documentdb.Status.Instances = instances
+			statusChanged = true
+		}
+
+		bootstrapChanged, err := r.reconcileBootstrapStatus(ctx, documentdb, currentCnpgCluster)
+		if err != nil {
+			logger.Error(err, "Failed to collect bootstrap progress")
+		}
+		statusChanged = statusChanged || bootstrapChanged
+
+		selfHealChanged, err := r.reconcileSelfHeal(ctx, documentdb, currentCnpgCluster)
+		if err != nil {
+			r.recordReconcileFailure(ctx, documentdb, err, "Failed to remediate an unhealthy replica")
+			return ctrl.Result{RequeueAfter: RequeueAfterShort}, nil
+		}
+		statusChanged = statusChanged || selfHealChanged
+
+		if err := r.reconcileEvictionAnnotations(ctx, documentdb, currentCnpgCluster); err != nil {
+			logger.Error(err, "Failed to annotate instance pods for node autoscalers")
+		}
+
+		storageExpansionChanged, err := r.reconcileStorageExpansion(ctx, documentdb, currentCnpgCluster)
+		if err != nil {
+			logger.Error(err, "Failed to collect storage expansion progress")
+		}
+		statusChanged = statusChanged || storageExpansionChanged
+
+		quotasChanged, err := r.reconcileQuotas(ctx, documentdb, currentCnpgCluster)
+		if err != nil {
+			logger.Error(err, "Failed to collect database quota usage")
+		}
+		statusChanged = statusChanged || quotasChanged
+
+		walChanged, err := r.reconcileWALStatus(ctx, documentdb, currentCnpgCluster)
+		if err != nil {
+			logger.Error(err, "Failed to collect WAL usage")
+		}
+		statusChanged = statusChanged || walChanged
+
+		connectionHost, hostnameChanged := r.reconcileHostname(ctx, documentdb, documentDbServiceIp)
+		statusChanged = statusChanged || hostnameChanged
+
 		// Update connection string if primary and service IP available
-		if replicationContext.IsPrimary() && documentDbServiceIp != "" {
+		if replicationContext.IsPrimary() && connectionHost != "" {
 			trustTLS := documentdb.Status.TLS != nil && documentdb.Status.TLS.Ready
-			newConnStr := util.GenerateConnectionString(documentdb, documentDbServiceIp, trustTLS)
+			seeds := connectionSeeds(documentdb, currentCnpgCluster, connectionHost, trustTLS)
+			newConnStr := util.GenerateHAConnectionString(documentdb, seeds, trustTLS)
 			if documentdb.Status.ConnectionString != newConnStr {
 				documentdb.Status.ConnectionString = newConnStr
 				statusChanged = true
 			}
+			if err := r.reconcileConnectionSecret(ctx, documentdb, seeds, trustTLS); err != nil {
+				r.recordReconcileFailure(ctx, documentdb, err, "Failed to reconcile connection string Secret")
+				return ctrl.Result{RequeueAfter: RequeueAfterShort}, nil
+			}
+			importChanged, err := r.reconcileMongoDBImport(ctx, documentdb, currentCnpgCluster)
+			if err != nil {
+				r.recordReconcileFailure(ctx, documentdb, err, "Failed to reconcile import")
+				return ctrl.Result{RequeueAfter: RequeueAfterShort}, nil
+			}
+			statusChanged = statusChanged || importChanged
+		}
+
+		if endpoints := endpointStatuses(documentdb, replicationContext, connectionHost); !equality.Semantic.DeepEqual(documentdb.Status.Endpoints, endpoints) {
+			documentdb.Status.Endpoints = endpoints
+			statusChanged = true
+		}
+
+		if replicationContext.IsPrimary() {
+			migrationChanged, err := r.reconcileMigration(ctx, documentdb, currentCnpgCluster)
+			if err != nil {
+				r.recordReconcileFailure(ctx, documentdb, err, "Failed to reconcile migration")
+				return ctrl.Result{RequeueAfter: RequeueAfterShort}, nil
+			}
+			statusChanged = statusChanged || migrationChanged
+
+			initScriptsChanged, err := r.reconcileInitScripts(ctx, documentdb, currentCnpgCluster)
+			if err != nil {
+				r.recordReconcileFailure(ctx, documentdb, err, "Failed to reconcile init scripts")
+				return ctrl.Result{RequeueAfter: RequeueAfterShort}, nil
+			}
+			statusChanged = statusChanged || initScriptsChanged
+		}
+
+		if err := r.reconcileEndpointsConfigMap(ctx, documentdb, currentCnpgCluster, connectionHost); err != nil {
+			r.recordReconcileFailure(ctx, documentdb, err, "Failed to reconcile endpoints ConfigMap")
+			return ctrl.Result{RequeueAfter: RequeueAfterShort}, nil
+		}
+
+		if err := r.reconcileExportCronJob(ctx, documentdb, replicationContext); err != nil {
+			r.recordReconcileFailure(ctx, documentdb, err, "Failed to reconcile export CronJob")
+			return ctrl.Result{RequeueAfter: RequeueAfterShort}, nil
 		}
 
 		if statusChanged {
-			if err := r.Status().Update(ctx, documentdb); err != nil {
+			if err := r.updateStatus(ctx, documentdb); err != nil {
 				logger.Error(err, "Failed to update DocumentDB status")
 			}
 		}
@@ -289,7 +554,7 @@ func (r *DocumentDBReconciler) Reconcile(ctx context.Context, req ctrl.Request)
 	if replicationContext.IsAzureFleetNetworking() {
 		deleted, imports, err := r.CleanupMismatchedServiceImports(ctx, documentdb.Namespace, replicationContext)
 		if err != nil {
-			log.Log.Error(err, "Failed to cleanup ServiceImports")
+			r.recordReconcileFailure(ctx, documentdb, err, "Failed to cleanup ServiceImports")
 			return ctrl.Result{RequeueAfter: RequeueAfterShort}, nil
 		}
 		if deleted {
@@ -298,7 +563,7 @@ func (r *DocumentDBReconciler) Reconcile(ctx context.Context, req ctrl.Request)
 		}
 		reconciled, err := r.ForceReconcileInternalServiceExports(ctx, documentdb.Namespace, replicationContext, imports)
 		if err != nil {
-			log.Log.Error(err, "Failed to force reconcile InternalServiceExports")
+			r.recordReconcileFailure(ctx, documentdb, err, "Failed to force reconcile InternalServiceExports")
 			return ctrl.Result{RequeueAfter: RequeueAfterShort}, nil
 		}
 		if reconciled {
@@ -309,12 +574,21 @@ func (r *DocumentDBReconciler) Reconcile(ctx context.Context, req ctrl.Request)
 
 	// Check if documentdb extension needs ALTER EXTENSION UPDATE
 	if err := r.handleExtensionUpgrade(ctx, currentCnpgCluster, documentdb); err != nil {
-		logger.Error(err, "Failed to handle DocumentDB extension upgrade")
+		r.recordReconcileFailure(ctx, documentdb, err, "Failed to handle DocumentDB extension upgrade")
 		return ctrl.Result{RequeueAfter: RequeueAfterShort}, nil
 	}
 
-	// Don't requeue again unless there is a change
-	return ctrl.Result{}, nil
+	// The reconcile went through: the failures before it were transient
+	r.resetReconcileFailures(ctx, documentdb)
+
+	// Follow a bootstrap, a migration or a storage expansion, retry the init scripts, watch unhealthy replicas, wait for the hostname to resolve and for the member clusters sooner than drift
+	if bootstrapInProgress(documentdb) || migrationInProgress(documentdb) || initScriptsPending(documentdb) || selfHealPending(documentdb) ||
+		storageExpansionPending(documentdb) || hostnamePending(documentdb) || memberSyncPending(documentdb) {
+		return ctrl.Result{RequeueAfter: RequeueAfterLong}, nil
+	}
+
+	// Come back periodically to check the CNPG Cluster for drift
+	return ctrl.Result{RequeueAfter: util.GetDriftCheckInterval()}, nil
 }
 
 // cleanupResources handles the cleanup of associated resources when a DocumentDB resource is not found
@@ -341,6 +615,18 @@ func (r *DocumentDBReconciler) cleanupResources(ctx context.Context, req ctrl.Re
 	return nil
 }
 
+// updateStatus writes the status of documentdb through a copy: the update
+// response carries the stored spec, which would drop the cluster class
+// resolved in memory for the rest of the reconcile.
+func (r *DocumentDBReconciler) updateStatus(ctx context.Context, documentdb *dbpreview.DocumentDB) error {
+	updated := documentdb.DeepCopy()
+	if err := r.Status().Update(ctx, updated); err != nil {
+		return err
+	}
+	documentdb.ResourceVersion = updated.ResourceVersion
+	return nil
+}
+
 // reconcileFinalizer handles the finalizer lifecycle:
 //   - If resource is being deleted: process deletion and remove finalizer
 //   - If finalizer is missing: add it
@@ -357,8 +643,57 @@ func (r *DocumentDBReconciler) reconcileFinalizer(ctx context.Context, documentd
 			return true, ctrl.Result{}, nil
 		}
 
+		// Keep the finalizer while deletion protection is on. Clearing the flag
+		// is a spec update, which triggers another reconcile.
+		if documentdb.Spec.DeletionProtection {
+			logger.Info("Deletion protection is enabled, holding finalizer")
+			if r.Recorder != nil {
+				r.Recorder.Event(documentdb, corev1.EventTypeWarning, "DeletionProtected", fmt.Sprintf(
+					"Deletion is blocked by spec.deletionProtection. To proceed: "+
+						"kubectl patch dbs.documentdb.io %s -n %s --type merge -p '{\"spec\":{\"deletionProtection\":false}}'",
+					documentdb.Name, documentdb.Namespace))
+			}
+			return true, ctrl.Result{}, nil
+		}
+
+		if documentdb.Spec.DeletionPolicy == dbpreview.DeletionPolicyOrphan {
+			// Detach the CNPG Cluster before the finalizer is removed, so the
+			// garbage collector never sees it as a dependent of a deleted owner.
+			if err := r.orphanCNPGClusters(ctx, documentdb); err != nil {
+				logger.Error(err, "Failed to orphan CNPG Cluster")
+				return true, ctrl.Result{}, err
+			}
+		} else if blocked, err := r.isDeletionBlockedByMissingBackup(ctx, documentdb); err != nil {
+			return true, ctrl.Result{}, err
+		} else if blocked {
+			// Hold deletion of clusters whose data goes away with them until a
+			// recent backup exists. Backups are checked again after RequeueAfterLong.
+			return true, ctrl.Result{RequeueAfter: RequeueAfterLong}, nil
+		}
+
+		// The DocumentDBs of the member clusters go with the one describing them
+		if err := r.deleteMemberDocumentDBs(ctx, documentdb); err != nil {
+			logger.Error(err, "Failed to delete the DocumentDBs of the member clusters")
+			if r.Recorder != nil {
+				r.Recorder.Event(documentdb, corev1.EventTypeWarning, "MemberDeletionFailed", err.Error())
+			}
+			return true, ctrl.Result{}, err
+		}
+
+		if documentdb.IsPVRecoveryConfigured() {
+			if err := r.deletePVRecoveryTempPVC(ctx, documentdb); err != nil {
+				logger.Error(err, "Failed to delete PV recovery temp PVC")
+				return true, ctrl.Result{}, err
+			}
+		}
+
+		if err := r.cleanupReplicationResources(ctx, documentdb); err != nil {
+			logger.Error(err, "Failed to clean up replication resources")
+			return true, ctrl.Result{}, err
+		}
+
 		// Check if PVs will be retained and emit warning
-		if documentdb.ShouldWarnAboutRetainedPVs() {
+		if documentdb.Spec.DeletionPolicy != dbpreview.DeletionPolicyOrphan && documentdb.ShouldWarnAboutRetainedPVs() {
 			if err := r.emitPVRetentionWarning(ctx, documentdb); err != nil {
 				// Log but don't block deletion
 				logger.Error(err, "Failed to emit PV retention warning, continuing with deletion")
@@ -391,6 +726,145 @@ func (r *DocumentDBReconciler) reconcileFinalizer(ctx context.Context, documentd
 	return false, ctrl.Result{}, nil
 }
 
+// documentdbExtensionInstalledQuery reports whether the documentdb extension
+// is installed, as a JSON row.
+const documentdbExtensionInstalledQuery = `SELECT json_build_object('installed',
+EXISTS (SELECT 1 FROM pg_extension WHERE extname = 'documentdb'));`
+
+// adoptCNPGCluster makes documentdb the controller of an existing CNPG Cluster
+// once the documentdb extension is confirmed to be installed in it. The cluster
+// is patched in place, never recreated. It returns false, with a warning event
+// where the user has to act, while the cluster cannot be adopted yet.
+func (r *DocumentDBReconciler) adoptCNPGCluster(ctx context.Context, documentdb *dbpreview.DocumentDB, cluster *cnpgv1.Cluster) (bool, error) {
+	logger := log.FromContext(ctx)
+
+	if !slices.Contains(cluster.Status.InstancesStatus[cnpgv1.PodHealthy], cluster.Status.CurrentPrimary) {
+		logger.Info("Waiting for a healthy primary before adopting CNPG Cluster", "cluster", cluster.Name)
+		return false, nil
+	}
+
+	output, err := r.SQLExecutor(ctx, cluster, documentdbExtensionInstalledQuery)
+	if err != nil {
+		return false, fmt.Errorf("failed to check documentdb extension: %w", err)
+	}
+	var extension struct {
+		Installed bool `json:"installed"`
+	}
+	if err := parseJSONRowFromOutput(output, &extension); err != nil {
+		return false, fmt.Errorf("failed to parse the documentdb extension check: %w", err)
+	}
+	if !extension.Installed {
+		if r.Recorder != nil {
+			r.Recorder.Event(documentdb, corev1.EventTypeWarning, "AdoptionFailed", fmt.Sprintf(
+				"CNPG Cluster %s cannot be adopted: the documentdb extension is not installed. "+
+					"Run CREATE EXTENSION documentdb CASCADE on its primary first.", cluster.Name))
+		}
+		return false, nil
+	}
+
+	var ownerErr error
+	err = util.PatchWithRetry(ctx, r.Client, r.APIReader, cluster, func() error {
+		if ownerErr = controllerutil.SetControllerReference(documentdb, cluster, r.Scheme); ownerErr != nil {
+			return ownerErr
+		}
+		delete(cluster.Annotations, util.ADOPT_CLUSTER_ANNOTATION)
+		return nil
+	})
+	if ownerErr != nil {
+		if r.Recorder != nil {
+			r.Recorder.Event(documentdb, corev1.EventTypeWarning, "AdoptionFailed", fmt.Sprintf(
+				"CNPG Cluster %s cannot be adopted: %v", cluster.Name, ownerErr))
+		}
+		return false, nil
+	}
+	if err != nil {
+		return false, err
+	}
+
+	logger.Info("Adopted CNPG Cluster", "cluster", cluster.Name)
+	if r.Recorder != nil {
+		r.Recorder.Event(documentdb, corev1.EventTypeNormal, "ClusterAdopted", fmt.Sprintf(
+			"CNPG Cluster %s is now managed by this DocumentDB", cluster.Name))
+	}
+	return true, nil
+}
+
+// orphanCNPGClusters removes the DocumentDB owner reference from the CNPG
+// Clusters it owns, leaving them and their PVCs in place after the DocumentDB
+// is deleted.
+func (r *DocumentDBReconciler) orphanCNPGClusters(ctx context.Context, documentdb *dbpreview.DocumentDB) error {
+	logger := log.FromContext(ctx)
+
+	clusterList := &cnpgv1.ClusterList{}
+	if err := r.List(ctx, clusterList, client.InNamespace(documentdb.Namespace)); err != nil {
+		return fmt.Errorf("failed to list CNPG Clusters: %w", err)
+	}
+
+	for i := range clusterList.Items {
+		cluster := &clusterList.Items[i]
+		isOwner := func(ref metav1.OwnerReference) bool { return ref.UID == documentdb.UID }
+		if !slices.ContainsFunc(cluster.OwnerReferences, isOwner) {
+			continue
+		}
+
+		err := util.PatchWithRetry(ctx, r.Client, r.APIReader, cluster, func() error {
+			cluster.OwnerReferences = slices.DeleteFunc(cluster.OwnerReferences, isOwner)
+			return nil
+		})
+		if err != nil {
+			return fmt.Errorf("failed to remove owner reference from CNPG Cluster %s: %w", cluster.Name, err)
+		}
+		logger.Info("Orphaned CNPG Cluster", "cluster", cluster.Name)
+		if r.Recorder != nil {
+			r.Recorder.Event(documentdb, corev1.EventTypeNormal, "ClusterOrphaned", fmt.Sprintf(
+				"CNPG Cluster %s and its PVCs are kept (deletionPolicy=Orphan). "+
+					"To delete them when no longer needed: kubectl delete clusters.postgresql.cnpg.io %s -n %s",
+				cluster.Name, cluster.Name, cluster.Namespace))
+		}
+	}
+	return nil
+}
+
+// isDeletionBlockedByMissingBackup reports whether the deletion of a cluster
+// whose PVs are deleted with it must wait because no backup completed within
+// the configured max age (see util.GetDeletionBackupMaxAge). It emits a warning
+// event explaining how to take a backup or skip the check.
+func (r *DocumentDBReconciler) isDeletionBlockedByMissingBackup(ctx context.Context, documentdb *dbpreview.DocumentDB) (bool, error) {
+	logger := log.FromContext(ctx)
+
+	maxAge := util.GetDeletionBackupMaxAge()
+	if maxAge == 0 ||
+		documentdb.Spec.Resource.Storage.PersistentVolumeReclaimPolicy != reclaimPolicyDelete ||
+		documentdb.Annotations[util.SKIP_DELETION_BACKUP_CHECK_ANNOTATION] == "true" {
+		return false, nil
+	}
+
+	backupList := &dbpreview.BackupList{}
+	if err := r.List(ctx, backupList, client.InNamespace(documentdb.Namespace)); err != nil {
+		return false, fmt.Errorf("failed to list backups: %w", err)
+	}
+	clusterBackups := &dbpreview.BackupList{}
+	for _, backup := range backupList.Items {
+		if backup.Spec.Cluster.Name == documentdb.Name {
+			clusterBackups.Items = append(clusterBackups.Items, backup)
+		}
+	}
+
+	lastBackup := clusterBackups.GetLastCompletedBackup()
+	if lastBackup != nil && time.Since(lastBackup.Status.StoppedAt.Time) <= maxAge {
+		return false, nil
+	}
+
+	logger.Info("Deletion held until a recent backup exists", "maxAge", maxAge)
+	if r.Recorder != nil {
+		r.Recorder.Event(documentdb, corev1.EventTypeWarning, "DeletionBlockedNoBackup", fmt.Sprintf(
+			"Deletion is waiting for a backup: persistentVolumeReclaimPolicy is Delete and no backup completed in the last %s. "+
+				"Create a Backup for this cluster, or skip the check: kubectl annotate dbs.documentdb.io %s -n %s %s=true",
+			maxAge, documentdb.Name, documentdb.Namespace, util.SKIP_DELETION_BACKUP_CHECK_ANNOTATION))
+	}
+	return true, nil
+}
+
 // emitPVRetentionWarning emits a warning event listing PVs that will be retained after deletion
 func (r *DocumentDBReconciler) emitPVRetentionWarning(ctx context.Context, documentdb *dbpreview.DocumentDB) error {
 	logger := log.FromContext(ctx)
@@ -490,9 +964,10 @@ func clusterInstanceStatusChangedPredicate() predicate.Predicate {
 			if !ok {
 				return true
 			}
-			// Trigger on healthy instances change OR phase change
+			// Trigger on healthy instances change, phase change OR primary change
 			return !slices.Equal(oldCluster.Status.InstancesStatus[cnpgv1.PodHealthy], newCluster.Status.InstancesStatus[cnpgv1.PodHealthy]) ||
-				oldCluster.Status.Phase != newCluster.Status.Phase
+				oldCluster.Status.Phase != newCluster.Status.Phase ||
+				oldCluster.Status.CurrentPrimary != newCluster.Status.CurrentPrimary
 		},
 	}
 }
@@ -522,9 +997,30 @@ func (r *DocumentDBReconciler) SetupWithManager(mgr ctrl.Manager) error {
 		return fmt.Errorf("Clientset must be configured: required for Kubernetes version detection and SQL execution")
 	}
 
+	if r.PodExec == nil {
+		r.PodExec = util.NewPodExecRunner(r.Config, r.Clientset)
+	}
 	if r.SQLExecutor == nil {
 		r.SQLExecutor = r.executeSQLCommand
 	}
+	if r.queryCache == nil {
+		r.queryCache = newSQLQueryCache()
+	}
+	if r.cnpgVersions == nil {
+		r.cnpgVersions = newCNPGVersionCache()
+	}
+	if r.MigrationSchemaCopier == nil {
+		r.MigrationSchemaCopier = r.copyMigrationSchema
+	}
+	if r.BootstrapProgressProbe == nil {
+		r.BootstrapProgressProbe = r.probeBootstrapProgress
+	}
+	if r.HostResolver == nil {
+		r.HostResolver = net.DefaultResolver.LookupHost
+	}
+	if r.MemberClientFactory == nil {
+		r.MemberClientFactory = r.newMemberClient
+	}
 
 	// Verify the cluster meets the minimum Kubernetes version requirement.
 	// ImageVolume (GA in K8s 1.35) is required for mounting the DocumentDB extension image.
@@ -532,14 +1028,52 @@ func (r *DocumentDBReconciler) SetupWithManager(mgr ctrl.Manager) error {
 		return err
 	}
 
-	return ctrl.NewControllerManagedBy(mgr).
-		For(&dbpreview.DocumentDB{}).
+	if r.APIReader == nil {
+		r.APIReader = mgr.GetAPIReader()
+	}
+
+	// The CloudNativePG version is checked for each DocumentDB by the
+	// preflight checks; an unsupported one is also reported at startup.
+	r.logCNPGVersion(mgr.GetLogger())
+
+	// Clones import VolumeSnapshots across namespaces.
+	if err := snapshotv1.AddToScheme(mgr.GetScheme()); err != nil {
+		return err
+	}
+
+	b := ctrl.NewControllerManagedBy(mgr).
+		For(&dbpreview.DocumentDB{}, builder.WithPredicates(ignoreReconcileFailureUpdatesPredicate())).
 		Owns(&corev1.Service{}, builder.WithPredicates(documentDBServicePredicate())).
 		Owns(&cnpgv1.Cluster{}, builder.WithPredicates(clusterInstanceStatusChangedPredicate())).
 		Owns(&cnpgv1.Publication{}).
 		Owns(&cnpgv1.Subscription{}).
-		Named("documentdb-controller").
-		Complete(r)
+		Owns(&corev1.Secret{}, builder.OnlyMetadata).
+		Owns(&batchv1.Job{}).
+		Owns(&batchv1.CronJob{}).
+		Watches(&corev1.Secret{}, handler.EnqueueRequestsFromMapFunc(r.documentDBsForCredentialSecret), builder.OnlyMetadata).
+		Watches(&corev1.Pod{}, r.podEventHandler(), builder.WithPredicates(cnpgPodPredicate())).
+		Watches(&dbpreview.DocumentDBClusterClass{}, handler.EnqueueRequestsFromMapFunc(r.documentDBsForClusterClass))
+	if r.OperatorConfigEvents != nil {
+		b = b.WatchesRawSource(source.Channel(r.OperatorConfigEvents, &handler.EnqueueRequestForObject{}))
+	}
+	if r.healthPoller == nil {
+		r.healthPoller = newClusterHealthPoller(mgr.GetClient(), r.SQLExecutor)
+	}
+	b = b.WatchesRawSource(source.Channel(r.healthPoller.events, &handler.EnqueueRequestForObject{}))
+	if err := b.Named("documentdb-controller").Complete(trackReconciles("documentdb-controller", r)); err != nil {
+		return err
+	}
+
+	if err := mgr.Add(r.healthPoller); err != nil {
+		return err
+	}
+
+	// Background operations stop with the controller, and the manager waits
+	// for them on shutdown.
+	if r.backgroundOps == nil {
+		r.backgroundOps = newBackgroundOperations()
+	}
+	return mgr.Add(r.backgroundOps)
 }
 
 // validateK8sVersion checks that the Kubernetes cluster version is at least 1.35.
@@ -628,58 +1162,55 @@ func Promote(ctx context.Context, cli client.Client,
 
 // executeSQLCommand executes SQL commands directly in the postgres container of a running pod
 func (r *DocumentDBReconciler) executeSQLCommand(ctx context.Context, cluster *cnpgv1.Cluster, sqlCommand string) (string, error) {
-	logger := log.FromContext(ctx)
+	return r.executeSQLCommands(ctx, cluster, sqlCommand)
+}
 
-	var targetPod corev1.Pod
-	if err := r.Client.Get(ctx, types.NamespacedName{Name: cluster.Status.CurrentPrimary, Namespace: cluster.Namespace}, &targetPod); err != nil {
-		return "", fmt.Errorf("failed to get primary pod: %w", err)
-	}
+// executeSQLCommands executes sqlCommands one after the other in a psql
+// session, each in its own transaction, so that a SET applies to the
+// commands after it, including those that cannot run in a transaction block.
+func (r *DocumentDBReconciler) executeSQLCommands(ctx context.Context, cluster *cnpgv1.Cluster, sqlCommands ...string) (string, error) {
+	logger := log.FromContext(ctx)
 
 	// Execute psql command in the postgres container
 	cmd := []string{
 		"psql",
 		"-U", "postgres",
 		"-d", "postgres",
-		"-c", sqlCommand,
-	}
-
-	req := r.Clientset.CoreV1().RESTClient().Post().
-		Resource("pods").
-		Name(targetPod.Name).
-		Namespace(cluster.Namespace).
-		SubResource("exec").
-		VersionedParams(&corev1.PodExecOptions{
-			Container: "postgres",
-			Command:   cmd,
-			Stdin:     false,
-			Stdout:    true,
-			Stderr:    true,
-			TTY:       false,
-		}, scheme.ParameterCodec)
-
-	exec, err := remotecommand.NewSPDYExecutor(r.Config, "POST", req.URL())
-	if err != nil {
-		return "", fmt.Errorf("failed to create executor: %w", err)
+	}
+	for _, sqlCommand := range sqlCommands {
+		cmd = append(cmd, "-c", sqlCommand)
 	}
 
-	var stdout, stderr bytes.Buffer
-	err = exec.StreamWithContext(ctx, remotecommand.StreamOptions{
-		Stdout: &stdout,
-		Stderr: &stderr,
-	})
-
+	stdout, stderr, err := r.execInPrimary(ctx, cluster, cmd, nil)
 	if err != nil {
 		logger.Error(err, "Failed to execute SQL command",
-			"stdout", stdout.String(),
-			"stderr", stderr.String())
-		return "", fmt.Errorf("failed to execute command: %w (stderr: %s)", err, stderr.String())
+			"stdout", stdout,
+			"stderr", stderr)
+		return "", fmt.Errorf("failed to execute command: %w (stderr: %s)", err, stderr)
+	}
+
+	if len(stderr) > 0 && !strings.Contains(stderr, "GRANT") {
+		logger.Info("SQL command executed with warnings", "stderr", stderr)
 	}
 
-	if stderr.Len() > 0 && !strings.Contains(stderr.String(), "GRANT") {
-		logger.Info("SQL command executed with warnings", "stderr", stderr.String())
+	return stdout, nil
+}
+
+// execInPrimary runs cmd in the postgres container of the primary pod of
+// cluster, with stdin as its standard input when it is not nil, and returns
+// its standard output and error.
+func (r *DocumentDBReconciler) execInPrimary(ctx context.Context, cluster *cnpgv1.Cluster, cmd []string, stdin io.Reader) (string, string, error) {
+	var targetPod corev1.Pod
+	if err := r.Client.Get(ctx, types.NamespacedName{Name: cluster.Status.CurrentPrimary, Namespace: cluster.Namespace}, &targetPod); err != nil {
+		return "", "", fmt.Errorf("failed to get primary pod: %w", err)
 	}
+	return r.execInPod(ctx, &targetPod, "postgres", cmd, stdin)
+}
 
-	return stdout.String(), nil
+// execInPod runs cmd in the given container of pod and returns its stdout
+// and stderr.
+func (r *DocumentDBReconciler) execInPod(ctx context.Context, pod *corev1.Pod, container string, cmd []string, stdin io.Reader) (string, string, error) {
+	return r.PodExec.Exec(ctx, pod, container, cmd, stdin)
 }
 
 // reconcilePVRecovery handles recovery from a retained PersistentVolume.
@@ -688,12 +1219,16 @@ func (r *DocumentDBReconciler) executeSQLCommand(ctx context.Context, cluster *c
 // not directly from PV. To bridge this gap, we create a temporary PVC that binds to the retained PV
 // via spec.volumeName. CNPG then clones the data from this temp PVC to new cluster PVCs.
 // After recovery completes (cluster healthy), we delete the temp PVC to release the source PV
-// back to the user for manual cleanup or reuse.
+// back to the user for manual cleanup or reuse. The temp PVC is also deleted when the recovery
+// fails or does not complete within the PV recovery timeout, so that it never holds the PV forever.
+// Before CNPG recovers from the PV, a Job checks that it holds a PostgreSQL data directory.
 //
 // Flow:
 //   - If no PV recovery configured, return immediately
 //   - If CNPG exists and healthy, delete temp PVC (recovery complete)
+//   - If CNPG exists and is unrecoverable or the timeout passed, delete temp PVC (recovery failed)
 //   - If CNPG doesn't exist, validate PV and create temp PVC bound to it
+//   - Once the temp PVC is bound, check the contents of the PV
 func (r *DocumentDBReconciler) reconcilePVRecovery(ctx context.Context, documentdb *dbpreview.DocumentDB, namespace, cnpgClusterName string) (ctrl.Result, error) {
 	logger := log.FromContext(ctx)
 
@@ -710,15 +1245,22 @@ func (r *DocumentDBReconciler) reconcilePVRecovery(ctx context.Context, document
 	cnpgErr := r.Get(ctx, types.NamespacedName{Name: cnpgClusterName, Namespace: namespace}, cnpgCluster)
 
 	if cnpgErr == nil {
-		// CNPG exists - check if healthy and cleanup temp PVC
-		if cnpgCluster.Status.Phase == cnpgClusterHealthyPhase {
-			tempPVC := &corev1.PersistentVolumeClaim{}
-			if err := r.Get(ctx, types.NamespacedName{Name: tempPVCName, Namespace: namespace}, tempPVC); err == nil {
-				logger.Info("Deleting temp PVC after successful recovery", "pvc", tempPVCName)
-				if err := r.Delete(ctx, tempPVC); err != nil {
-					return ctrl.Result{}, fmt.Errorf("failed to delete temp PVC %s: %w", tempPVCName, err)
-				}
+		// CNPG exists - cleanup temp PVC once the recovery has succeeded or failed
+		tempPVC := &corev1.PersistentVolumeClaim{}
+		if err := r.Get(ctx, types.NamespacedName{Name: tempPVCName, Namespace: namespace}, tempPVC); err != nil {
+			return ctrl.Result{}, nil
+		}
+		switch {
+		case cnpgCluster.Status.Phase == cnpgClusterHealthyPhase:
+			logger.Info("Deleting temp PVC after successful recovery", "pvc", tempPVCName)
+			if err := r.Delete(ctx, tempPVC); err != nil {
+				return ctrl.Result{}, fmt.Errorf("failed to delete temp PVC %s: %w", tempPVCName, err)
 			}
+		case cnpgCluster.Status.Phase == cnpgv1.PhaseUnrecoverable:
+			return ctrl.Result{}, r.abandonPVRecovery(ctx, documentdb, tempPVC, "the CNPG Cluster is unrecoverable")
+		case pvRecoveryTimedOut(tempPVC):
+			return ctrl.Result{}, r.abandonPVRecovery(ctx, documentdb, tempPVC,
+				fmt.Sprintf("the CNPG Cluster did not become healthy within %s", util.GetPVRecoveryTimeout()))
 		}
 		return ctrl.Result{}, nil
 	}
@@ -734,12 +1276,19 @@ func (r *DocumentDBReconciler) reconcilePVRecovery(ctx context.Context, document
 	tempPVCErr := r.Get(ctx, types.NamespacedName{Name: tempPVCName, Namespace: namespace}, tempPVC)
 	if tempPVCErr == nil {
 		// Temp PVC exists, check if bound
+		if tempPVC.Status.Phase != corev1.ClaimBound && pvRecoveryTimedOut(tempPVC) {
+			if err := r.abandonPVRecovery(ctx, documentdb, tempPVC,
+				fmt.Sprintf("the temp PVC did not bind to the PV within %s", util.GetPVRecoveryTimeout())); err != nil {
+				return ctrl.Result{}, err
+			}
+			return ctrl.Result{RequeueAfter: RequeueAfterLong}, nil
+		}
 		if tempPVC.Status.Phase != corev1.ClaimBound {
 			logger.Info("Waiting for temp PVC to bind to PV", "pvc", tempPVCName, "phase", tempPVC.Status.Phase)
 			return ctrl.Result{RequeueAfter: RequeueAfterShort}, nil
 		}
-		// PVC is bound, ready to proceed with CNPG creation
-		return ctrl.Result{}, nil
+		// PVC is bound, proceed with CNPG creation once the PV is checked
+		return r.checkPVRecoveryVolume(ctx, documentdb, tempPVC)
 	}
 
 	if !errors.IsNotFound(tempPVCErr) {
@@ -762,8 +1311,11 @@ func (r *DocumentDBReconciler) reconcilePVRecovery(ctx context.Context, document
 	// Clear claimRef if PV is Released
 	if util.NeedsToClearClaimRef(pv) {
 		logger.Info("Clearing claimRef on Released PV", "pv", pvName)
-		pv.Spec.ClaimRef = nil
-		if err := r.Update(ctx, pv); err != nil {
+		err := util.UpdateWithRetry(ctx, r.Client, r.APIReader, pv, func() error {
+			pv.Spec.ClaimRef = nil
+			return nil
+		})
+		if err != nil {
 			return ctrl.Result{}, fmt.Errorf("failed to clear claimRef on PV %s: %w", pvName, err)
 		}
 		return ctrl.Result{RequeueAfter: RequeueAfterShort}, nil
@@ -771,6 +1323,11 @@ func (r *DocumentDBReconciler) reconcilePVRecovery(ctx context.Context, document
 
 	// Create temp PVC
 	newPVC := util.BuildTempPVCForPVRecovery(documentdb.Name, namespace, pv)
+	if util.VeleroEnabled(documentdb) {
+		newPVC.Labels[util.VeleroExcludeLabel] = "true"
+	}
+	newPVC.Labels = util.ChildLabels(documentdb, newPVC.Labels)
+	newPVC.Annotations = util.ChildAnnotations(documentdb, nil)
 	if err := controllerutil.SetControllerReference(documentdb, newPVC, r.Scheme); err != nil {
 		return ctrl.Result{}, fmt.Errorf("failed to set owner reference on temp PVC: %w", err)
 	}
@@ -783,18 +1340,74 @@ func (r *DocumentDBReconciler) reconcilePVRecovery(ctx context.Context, document
 	return ctrl.Result{RequeueAfter: RequeueAfterShort}, nil
 }
 
-// parseExtensionVersionsFromOutput parses the output of pg_available_extensions query
-// Returns defaultVersion, installedVersion, and a boolean indicating if parsing was successful
-// Expected output format:
+// pvRecoveryTimedOut reports whether the recovery that created tempPVC has
+// exceeded the PV recovery timeout.
+func pvRecoveryTimedOut(tempPVC *corev1.PersistentVolumeClaim) bool {
+	return !tempPVC.CreationTimestamp.IsZero() && time.Since(tempPVC.CreationTimestamp.Time) > util.GetPVRecoveryTimeout()
+}
+
+// abandonPVRecovery deletes the temp PVC of a failed PV recovery, so that the
+// source PV is released again, and reports the failure in a warning event.
+func (r *DocumentDBReconciler) abandonPVRecovery(ctx context.Context, documentdb *dbpreview.DocumentDB, tempPVC *corev1.PersistentVolumeClaim, reason string) error {
+	log.FromContext(ctx).Info("Deleting temp PVC of failed PV recovery", "pvc", tempPVC.Name, "reason", reason)
+	if err := r.Delete(ctx, tempPVC); err != nil && !errors.IsNotFound(err) {
+		return fmt.Errorf("failed to delete temp PVC %s: %w", tempPVC.Name, err)
+	}
+	if r.Recorder != nil {
+		r.Recorder.Event(documentdb, corev1.EventTypeWarning, "PVRecoveryFailed", fmt.Sprintf(
+			"Recovery from PV %s failed: %s. Deleted temp PVC %s to release the PV.",
+			documentdb.GetPVNameForRecovery(), reason, tempPVC.Name))
+	}
+	return nil
+}
+
+// deletePVRecoveryTempPVC deletes the temp PVC of a PV recovery still in
+// progress when the DocumentDB is deleted.
+func (r *DocumentDBReconciler) deletePVRecoveryTempPVC(ctx context.Context, documentdb *dbpreview.DocumentDB) error {
+	tempPVC := &corev1.PersistentVolumeClaim{}
+	tempPVC.Name = util.TempPVCNameForPVRecovery(documentdb.Name)
+	tempPVC.Namespace = documentdb.Namespace
+	if err := r.Delete(ctx, tempPVC); err != nil && !errors.IsNotFound(err) {
+		return fmt.Errorf("failed to delete temp PVC %s: %w", tempPVC.Name, err)
+	}
+	return nil
+}
+
+// extensionVersionsQuery returns the default and installed versions of the
+// documentdb extension as a JSON object, so that reading them does not depend
+// on the tabular output of psql.
+const extensionVersionsQuery = `SELECT json_build_object(
+  'default_version', default_version,
+  'installed_version', installed_version)
+FROM pg_available_extensions WHERE name = 'documentdb'`
+
+// extensionVersionsFromOutput returns the versions of the documentdb extension
+// in the output of extensionVersionsQuery. Output without a JSON row is parsed
+// as the table of a plain query by parseExtensionVersionsFromOutput. ok is
+// false when the extension is not available.
+func extensionVersionsFromOutput(output string) (defaultVersion, installedVersion string, ok bool) {
+	var versions struct {
+		DefaultVersion   string `json:"default_version"`
+		InstalledVersion string `json:"installed_version"`
+	}
+	if err := parseJSONRowFromOutput(output, &versions); err != nil {
+		return parseExtensionVersionsFromOutput(output)
+	}
+	if versions.DefaultVersion == "" {
+		return "", "", false
+	}
+	return versions.DefaultVersion, versions.InstalledVersion, true
+}
+
+// parseExtensionVersionsFromOutput parses the tabular psql output of a query of
+// the default and installed versions in pg_available_extensions, e.g.:
 //
 //	 default_version | installed_version
 //	-----------------+-------------------
 //	 0.110-0         | 0.110-0
 //
-// TODO: This parsing is fragile as it relies on psql tabular output format which can vary
-// with locale or PostgreSQL version. Consider one of these improvements:
-// - Option 1: Add -t -A flags to SQLExecutor for unaligned, tuples-only output ("0.110-0|0.110-0")
-// - Option 2: Use json_build_object() in SQL and json.Unmarshal() in Go for robust parsing
+// It depends on the format of the output and is only kept as the fallback of
+// extensionVersionsFromOutput.
 func parseExtensionVersionsFromOutput(output string) (defaultVersion, installedVersion string, ok bool) {
 	lines := strings.Split(strings.TrimSpace(output), "\n")
 	if len(lines) < 3 {
@@ -813,6 +1426,20 @@ func parseExtensionVersionsFromOutput(output string) (defaultVersion, installedV
 	return defaultVersion, installedVersion, true
 }
 
+// parseJSONRowFromOutput unmarshals into v the JSON object returned as the
+// only row of a query, e.g. by json_build_object(), from psql's tabular
+// output.
+func parseJSONRowFromOutput(output string, v any) error {
+	for _, line := range strings.Split(output, "\n") {
+		line = strings.TrimSpace(line)
+		if !strings.HasPrefix(line, "{") {
+			continue
+		}
+		return json.Unmarshal([]byte(line), v)
+	}
+	return fmt.Errorf("no JSON object in output %q", output)
+}
+
 // handleExtensionUpgrade handles the ALTER EXTENSION lifecycle after images have been synced
 // by SyncCnpgCluster. It:
 // 1. Updates DocumentDB status with the current images from the CNPG cluster
@@ -839,15 +1466,12 @@ func (r *DocumentDBReconciler) handleExtensionUpgrade(ctx context.Context, curre
 	}
 
 	// Check if ALTER EXTENSION UPDATE is needed
-	checkVersionSQL := "SELECT default_version, installed_version FROM pg_available_extensions WHERE name = 'documentdb'"
-	output, err := r.SQLExecutor(ctx, currentCluster, checkVersionSQL)
+	defaultVersion, installedVersion, found, err := r.extensionVersions(ctx, documentdb, currentCluster)
 	if err != nil {
-		return fmt.Errorf("failed to check documentdb extension versions: %w", err)
+		return err
 	}
-
-	defaultVersion, installedVersion, ok := parseExtensionVersionsFromOutput(output)
-	if !ok {
-		logger.Info("DocumentDB extension not found or not installed yet", "output", output)
+	if !found {
+		logger.Info("DocumentDB extension not found or not installed yet")
 		return nil
 	}
 
@@ -865,7 +1489,7 @@ func (r *DocumentDBReconciler) handleExtensionUpgrade(ctx context.Context, curre
 			return fmt.Errorf("failed to refetch DocumentDB before schema version update: %w", err)
 		}
 		documentdb.Status.SchemaVersion = installedSemver
-		if err := r.Status().Update(ctx, documentdb); err != nil {
+		if err := r.updateStatus(ctx, documentdb); err != nil {
 			logger.Error(err, "Failed to update DocumentDB status with schema version")
 			return fmt.Errorf("failed to update DocumentDB status with schema version: %w", err)
 		}
@@ -874,6 +1498,12 @@ func (r *DocumentDBReconciler) handleExtensionUpgrade(ctx context.Context, curre
 	// If versions match, no upgrade needed
 	if defaultVersion == installedVersion {
 		logger.V(1).Info("DocumentDB extension is up to date", "version", installedVersion)
+		// An upgrade interrupted after ALTER EXTENSION completed is finished.
+		if documentdb.ClearInProgressOperation(dbpreview.OperationExtensionUpgrade) {
+			if err := r.updateStatus(ctx, documentdb); err != nil {
+				return fmt.Errorf("failed to clear extension upgrade from status: %w", err)
+			}
+		}
 		return nil
 	}
 
@@ -910,12 +1540,29 @@ func (r *DocumentDBReconciler) handleExtensionUpgrade(ctx context.Context, curre
 		return nil
 	}
 
+	// Record the upgrade before running it so that an operator restarted mid-upgrade
+	// re-checks the installed version and resumes instead of losing track of it.
+	// Recording is best-effort: the version check above makes the upgrade idempotent.
+	if documentdb.SetInProgressOperation(dbpreview.OperationExtensionUpgrade, schemaTarget, metav1.Now()) {
+		if err := r.updateStatus(ctx, documentdb); err != nil {
+			logger.Error(err, "Failed to record extension upgrade in status")
+		}
+	}
+
 	// Run ALTER EXTENSION to upgrade
 	logger.Info("Upgrading DocumentDB extension",
 		"fromVersion", installedVersion,
 		"toVersion", schemaTarget)
 
-	if _, err := r.SQLExecutor(ctx, currentCluster, updateSQL); err != nil {
+	// ALTER EXTENSION must not be cut off by operator shutdown: the manager
+	// waits for in-flight reconciles, so detach it from cancellation and
+	// bound it by its own timeout instead.
+	upgradeCtx, cancel := context.WithTimeout(context.WithoutCancel(ctx), extensionUpgradeTimeout)
+	defer cancel()
+	_, err = r.SQLExecutor(upgradeCtx, currentCluster, updateSQL)
+	r.queryCache.invalidate(currentCluster)
+	r.healthPoller.invalidate(currentCluster)
+	if err != nil {
 		return fmt.Errorf("failed to run ALTER EXTENSION documentdb UPDATE: %w", err)
 	}
 
@@ -928,7 +1575,8 @@ func (r *DocumentDBReconciler) handleExtensionUpgrade(ctx context.Context, curre
 		return fmt.Errorf("failed to refetch DocumentDB after schema upgrade: %w", err)
 	}
 	documentdb.Status.SchemaVersion = util.ExtensionVersionToSemver(schemaTarget)
-	if err := r.Status().Update(ctx, documentdb); err != nil {
+	documentdb.ClearInProgressOperation(dbpreview.OperationExtensionUpgrade)
+	if err := r.updateStatus(ctx, documentdb); err != nil {
 		logger.Error(err, "Failed to update DocumentDB status after schema upgrade")
 		return fmt.Errorf("failed to update DocumentDB status after schema upgrade: %w", err)
 	}
@@ -1011,11 +1659,9 @@ func (r *DocumentDBReconciler) determineSchemaTarget(
 			return "", ""
 		}
 
-		// Safety: targetPgVersion is derived from spec.schemaVersion via SemverToExtensionVersion,
-		// which produces a "Major.Minor-Patch" string (e.g., "0.112-0"). The input is validated
-		// by the CRD schema regex pattern and the validating webhook's version parsing, so it
-		// contains only digits, dots, and hyphens — no SQL injection risk.
-		return targetPgVersion, fmt.Sprintf("ALTER EXTENSION documentdb UPDATE TO '%s'", targetPgVersion)
+		// targetPgVersion is derived from spec.schemaVersion, which the CRD schema and the
+		// validating webhook restrict to a version string; it is quoted all the same.
+		return targetPgVersion, fmt.Sprintf("ALTER EXTENSION documentdb UPDATE TO %s", util.QuoteLiteral(targetPgVersion))
 	}
 }
 
@@ -1049,7 +1695,7 @@ func (r *DocumentDBReconciler) updateImageStatus(ctx context.Context, documentdb
 	}
 	documentdb.Status.DocumentDBImage = currentExtImage
 	documentdb.Status.GatewayImage = currentGwImage
-	if err := r.Status().Update(ctx, documentdb); err != nil {
+	if err := r.updateStatus(ctx, documentdb); err != nil {
 		return fmt.Errorf("failed to update DocumentDB image status: %w", err)
 	}
 	return nil
@@ -1069,6 +1715,7 @@ func (r *DocumentDBReconciler) reconcileOtelConfigMap(ctx context.Context, docum
 		if err := controllerutil.SetControllerReference(documentdb, cm, r.Scheme); err != nil {
 			return fmt.Errorf("failed to set owner reference: %w", err)
 		}
+		cm.Labels = util.ChildLabels(documentdb, map[string]string{util.LABEL_DOCUMENTDB_NAME: documentdb.Name})
 
 		configData, err := otelcfg.GenerateConfigMapData(documentdb.Name, namespace, documentdb.Spec.Monitoring)
 		if err != nil {
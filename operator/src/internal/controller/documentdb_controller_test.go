@@ -7,6 +7,7 @@ import (
 	"context"
 	"fmt"
 	"strings"
+	"time"
 
 	cnpgv1 "github.com/cloudnative-pg/cloudnative-pg/api/v1"
 	. "github.com/onsi/ginkgo/v2"
@@ -23,6 +24,7 @@ import (
 	kubefake "k8s.io/client-go/kubernetes/fake"
 	k8stesting "k8s.io/client-go/testing"
 	"k8s.io/client-go/tools/record"
+	"k8s.io/utils/ptr"
 	ctrl "sigs.k8s.io/controller-runtime"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 	"sigs.k8s.io/controller-runtime/pkg/client/fake"
@@ -864,6 +866,121 @@ var _ = Describe("DocumentDB Controller", func() {
 			Expect(updatedDB.Status.SchemaVersion).To(Equal("0.110.0"))
 		})
 
+		It("should track the upgrade in status and not cancel ALTER EXTENSION on shutdown", func() {
+			cluster := &cnpgv1.Cluster{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      clusterName,
+					Namespace: clusterNamespace,
+				},
+				Status: cnpgv1.ClusterStatus{
+					CurrentPrimary: "test-cluster-1",
+					InstancesStatus: map[cnpgv1.PodStatus][]string{
+						cnpgv1.PodHealthy: {"test-cluster-1"},
+					},
+				},
+			}
+
+			documentdb := &dbpreview.DocumentDB{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      "test-documentdb",
+					Namespace: clusterNamespace,
+				},
+				Spec: dbpreview.DocumentDBSpec{
+					SchemaVersion: "auto",
+				},
+			}
+
+			fakeClient := fake.NewClientBuilder().
+				WithScheme(scheme).
+				WithObjects(cluster, documentdb).
+				WithStatusSubresource(&dbpreview.DocumentDB{}).
+				Build()
+
+			// Simulate SIGTERM arriving while the reconcile is in flight.
+			reconcileCtx, cancel := context.WithCancel(ctx)
+			var recordedDuringAlter *dbpreview.InProgressOperation
+			var alterCtxErr error
+			reconciler := &DocumentDBReconciler{
+				Client:   fakeClient,
+				Scheme:   scheme,
+				Recorder: recorder,
+				SQLExecutor: func(sqlCtx context.Context, _ *cnpgv1.Cluster, sql string) (string, error) {
+					if strings.Contains(sql, "pg_available_extensions") {
+						return " default_version | installed_version \n-----------------+-------------------\n 0.110-0         | 0.109-0           \n", nil
+					}
+					cancel()
+					alterCtxErr = sqlCtx.Err()
+					inFlight := &dbpreview.DocumentDB{}
+					Expect(fakeClient.Get(ctx, types.NamespacedName{Name: "test-documentdb", Namespace: clusterNamespace}, inFlight)).To(Succeed())
+					recordedDuringAlter = inFlight.GetInProgressOperation(dbpreview.OperationExtensionUpgrade)
+					return "ALTER EXTENSION", nil
+				},
+			}
+
+			err := reconciler.handleExtensionUpgrade(reconcileCtx, cluster, documentdb)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(alterCtxErr).ToNot(HaveOccurred())
+
+			Expect(recordedDuringAlter).ToNot(BeNil())
+			Expect(recordedDuringAlter.Target).To(Equal("0.110-0"))
+
+			updatedDB := &dbpreview.DocumentDB{}
+			Expect(fakeClient.Get(ctx, types.NamespacedName{Name: "test-documentdb", Namespace: clusterNamespace}, updatedDB)).To(Succeed())
+			Expect(updatedDB.Status.SchemaVersion).To(Equal("0.110.0"))
+			Expect(updatedDB.Status.InProgressOperations).To(BeEmpty())
+		})
+
+		It("should clear an upgrade left in progress by a previous leader once versions match", func() {
+			cluster := &cnpgv1.Cluster{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      clusterName,
+					Namespace: clusterNamespace,
+				},
+				Status: cnpgv1.ClusterStatus{
+					CurrentPrimary: "test-cluster-1",
+					InstancesStatus: map[cnpgv1.PodStatus][]string{
+						cnpgv1.PodHealthy: {"test-cluster-1"},
+					},
+				},
+			}
+
+			documentdb := &dbpreview.DocumentDB{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      "test-documentdb",
+					Namespace: clusterNamespace,
+				},
+				Status: dbpreview.DocumentDBStatus{
+					SchemaVersion: "0.110.0",
+					InProgressOperations: []dbpreview.InProgressOperation{{
+						Type:      dbpreview.OperationExtensionUpgrade,
+						Target:    "0.110-0",
+						StartedAt: metav1.Now(),
+					}},
+				},
+			}
+
+			fakeClient := fake.NewClientBuilder().
+				WithScheme(scheme).
+				WithObjects(cluster, documentdb).
+				WithStatusSubresource(&dbpreview.DocumentDB{}).
+				Build()
+
+			reconciler := &DocumentDBReconciler{
+				Client:   fakeClient,
+				Scheme:   scheme,
+				Recorder: recorder,
+				SQLExecutor: func(_ context.Context, _ *cnpgv1.Cluster, _ string) (string, error) {
+					return " default_version | installed_version \n-----------------+-------------------\n 0.110-0         | 0.110-0           \n", nil
+				},
+			}
+
+			Expect(reconciler.handleExtensionUpgrade(ctx, cluster, documentdb)).To(Succeed())
+
+			updatedDB := &dbpreview.DocumentDB{}
+			Expect(fakeClient.Get(ctx, types.NamespacedName{Name: "test-documentdb", Namespace: clusterNamespace}, updatedDB)).To(Succeed())
+			Expect(updatedDB.Status.InProgressOperations).To(BeEmpty())
+		})
+
 		It("should return error when ALTER EXTENSION fails", func() {
 			cluster := &cnpgv1.Cluster{
 				ObjectMeta: metav1.ObjectMeta{
@@ -1948,6 +2065,53 @@ var _ = Describe("DocumentDB Controller", func() {
 		})
 	})
 
+	Describe("extensionVersionsFromOutput", func() {
+		It("parses the JSON row of extensionVersionsQuery", func() {
+			output := ` json_build_object
+--------------------------------------------------------------
+ {"default_version" : "0.110-0", "installed_version" : "0.109-0"}
+(1 row)`
+
+			defaultVersion, installedVersion, ok := extensionVersionsFromOutput(output)
+			Expect(ok).To(BeTrue())
+			Expect(defaultVersion).To(Equal("0.110-0"))
+			Expect(installedVersion).To(Equal("0.109-0"))
+		})
+
+		It("reports an extension that is not installed", func() {
+			output := ` json_build_object
+--------------------------------------------------------------
+ {"default_version" : "0.110-0", "installed_version" : null}
+(1 row)`
+
+			defaultVersion, installedVersion, ok := extensionVersionsFromOutput(output)
+			Expect(ok).To(BeTrue())
+			Expect(defaultVersion).To(Equal("0.110-0"))
+			Expect(installedVersion).To(BeEmpty())
+		})
+
+		It("returns false when the extension is not available", func() {
+			output := ` json_build_object
+-------------------
+(0 rows)`
+
+			_, _, ok := extensionVersionsFromOutput(output)
+			Expect(ok).To(BeFalse())
+		})
+
+		It("falls back to the tabular output of a plain query", func() {
+			output := ` default_version | installed_version
+-----------------+-------------------
+ 0.110-0         | 0.110-0
+(1 row)`
+
+			defaultVersion, installedVersion, ok := extensionVersionsFromOutput(output)
+			Expect(ok).To(BeTrue())
+			Expect(defaultVersion).To(Equal("0.110-0"))
+			Expect(installedVersion).To(Equal("0.110-0"))
+		})
+	})
+
 	Describe("findPVsForDocumentDB", func() {
 		It("returns PV names for PVs with matching documentdb.io/cluster label", func() {
 			pv1 := &corev1.PersistentVolume{
@@ -2308,6 +2472,450 @@ var _ = Describe("DocumentDB Controller", func() {
 			// Verify NO warning event was emitted (policy is Delete)
 			Consistently(localRecorder.Events).ShouldNot(Receive())
 		})
+
+		It("holds the finalizer while deletion protection is enabled", func() {
+			documentdb := &dbpreview.DocumentDB{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:              documentDBName,
+					Namespace:         documentDBNamespace,
+					Finalizers:        []string{documentDBFinalizer},
+					DeletionTimestamp: &metav1.Time{Time: time.Now()},
+				},
+				Spec: dbpreview.DocumentDBSpec{
+					DeletionProtection: true,
+				},
+			}
+
+			fakeClient := fake.NewClientBuilder().
+				WithScheme(scheme).
+				WithObjects(documentdb).
+				Build()
+
+			localRecorder := record.NewFakeRecorder(10)
+			reconciler := &DocumentDBReconciler{
+				Client:   fakeClient,
+				Scheme:   scheme,
+				Recorder: localRecorder,
+			}
+
+			done, result, err := reconciler.reconcileFinalizer(ctx, documentdb)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(done).To(BeTrue())
+			Expect(result.Requeue).To(BeFalse())
+
+			updated := &dbpreview.DocumentDB{}
+			Expect(fakeClient.Get(ctx, types.NamespacedName{Name: documentDBName, Namespace: documentDBNamespace}, updated)).To(Succeed())
+			Expect(controllerutil.ContainsFinalizer(updated, documentDBFinalizer)).To(BeTrue())
+
+			var event string
+			Expect(localRecorder.Events).To(Receive(&event))
+			Expect(event).To(ContainSubstring("DeletionProtected"))
+			Expect(event).To(ContainSubstring("deletionProtection"))
+		})
+
+		It("removes the finalizer once deletion protection is turned off", func() {
+			documentdb := &dbpreview.DocumentDB{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:              documentDBName,
+					Namespace:         documentDBNamespace,
+					Finalizers:        []string{documentDBFinalizer},
+					DeletionTimestamp: &metav1.Time{Time: time.Now()},
+				},
+				Spec: dbpreview.DocumentDBSpec{
+					DeletionProtection: false,
+					Resource: dbpreview.Resource{
+						Storage: dbpreview.StorageConfiguration{
+							PersistentVolumeReclaimPolicy: "Delete",
+						},
+					},
+				},
+			}
+
+			fakeClient := fake.NewClientBuilder().
+				WithScheme(scheme).
+				WithObjects(documentdb).
+				Build()
+
+			reconciler := &DocumentDBReconciler{
+				Client:   fakeClient,
+				Scheme:   scheme,
+				Recorder: recorder,
+			}
+
+			done, _, err := reconciler.reconcileFinalizer(ctx, documentdb)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(done).To(BeTrue())
+
+			// Removing the last finalizer lets the API server delete the object
+			updated := &dbpreview.DocumentDB{}
+			err = fakeClient.Get(ctx, types.NamespacedName{Name: documentDBName, Namespace: documentDBNamespace}, updated)
+			Expect(errors.IsNotFound(err)).To(BeTrue())
+		})
+	})
+
+	Describe("adoptCNPGCluster", func() {
+		newAdoptableCluster := func(healthy bool) *cnpgv1.Cluster {
+			cluster := &cnpgv1.Cluster{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:        documentDBName,
+					Namespace:   documentDBNamespace,
+					Annotations: map[string]string{util.ADOPT_CLUSTER_ANNOTATION: "true"},
+				},
+				Status: cnpgv1.ClusterStatus{
+					CurrentPrimary: documentDBName + "-1",
+				},
+			}
+			if healthy {
+				cluster.Status.InstancesStatus = map[cnpgv1.PodStatus][]string{
+					cnpgv1.PodHealthy: {documentDBName + "-1"},
+				}
+			}
+			return cluster
+		}
+
+		newDocumentDB := func() *dbpreview.DocumentDB {
+			return &dbpreview.DocumentDB{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      documentDBName,
+					Namespace: documentDBNamespace,
+					UID:       "documentdb-uid",
+				},
+			}
+		}
+
+		It("takes ownership of a cluster with the documentdb extension installed", func() {
+			documentdb := newDocumentDB()
+			cluster := newAdoptableCluster(true)
+			fakeClient := fake.NewClientBuilder().WithScheme(scheme).WithObjects(documentdb, cluster).Build()
+
+			localRecorder := record.NewFakeRecorder(10)
+			reconciler := &DocumentDBReconciler{
+				Client:   fakeClient,
+				Scheme:   scheme,
+				Recorder: localRecorder,
+				SQLExecutor: func(_ context.Context, _ *cnpgv1.Cluster, sql string) (string, error) {
+					Expect(sql).To(ContainSubstring("pg_extension"))
+					return " json_build_object \n-------------------\n {\"installed\" : true}\n(1 row)\n", nil
+				},
+			}
+
+			adopted, err := reconciler.adoptCNPGCluster(ctx, documentdb, cluster)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(adopted).To(BeTrue())
+
+			updated := &cnpgv1.Cluster{}
+			Expect(fakeClient.Get(ctx, types.NamespacedName{Name: documentDBName, Namespace: documentDBNamespace}, updated)).To(Succeed())
+			Expect(metav1.IsControlledBy(updated, documentdb)).To(BeTrue())
+			Expect(updated.Annotations).ToNot(HaveKey(util.ADOPT_CLUSTER_ANNOTATION))
+
+			var event string
+			Expect(localRecorder.Events).To(Receive(&event))
+			Expect(event).To(ContainSubstring("ClusterAdopted"))
+		})
+
+		It("refuses a cluster without the documentdb extension", func() {
+			documentdb := newDocumentDB()
+			cluster := newAdoptableCluster(true)
+			fakeClient := fake.NewClientBuilder().WithScheme(scheme).WithObjects(documentdb, cluster).Build()
+
+			localRecorder := record.NewFakeRecorder(10)
+			reconciler := &DocumentDBReconciler{
+				Client:   fakeClient,
+				Scheme:   scheme,
+				Recorder: localRecorder,
+				SQLExecutor: func(_ context.Context, _ *cnpgv1.Cluster, _ string) (string, error) {
+					return " json_build_object \n-------------------\n {\"installed\" : false}\n(1 row)\n", nil
+				},
+			}
+
+			adopted, err := reconciler.adoptCNPGCluster(ctx, documentdb, cluster)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(adopted).To(BeFalse())
+
+			updated := &cnpgv1.Cluster{}
+			Expect(fakeClient.Get(ctx, types.NamespacedName{Name: documentDBName, Namespace: documentDBNamespace}, updated)).To(Succeed())
+			Expect(updated.OwnerReferences).To(BeEmpty())
+
+			var event string
+			Expect(localRecorder.Events).To(Receive(&event))
+			Expect(event).To(ContainSubstring("AdoptionFailed"))
+		})
+
+		It("returns an error when the extension check cannot be parsed", func() {
+			documentdb := newDocumentDB()
+			cluster := newAdoptableCluster(true)
+			fakeClient := fake.NewClientBuilder().WithScheme(scheme).WithObjects(documentdb, cluster).Build()
+
+			reconciler := &DocumentDBReconciler{
+				Client:   fakeClient,
+				Scheme:   scheme,
+				Recorder: recorder,
+				SQLExecutor: func(_ context.Context, _ *cnpgv1.Cluster, _ string) (string, error) {
+					return "WARNING:  could not flush dirty data\n(1 row)\n", nil
+				},
+			}
+
+			adopted, err := reconciler.adoptCNPGCluster(ctx, documentdb, cluster)
+			Expect(err).To(MatchError(ContainSubstring("failed to parse the documentdb extension check")))
+			Expect(adopted).To(BeFalse())
+
+			updated := &cnpgv1.Cluster{}
+			Expect(fakeClient.Get(ctx, types.NamespacedName{Name: documentDBName, Namespace: documentDBNamespace}, updated)).To(Succeed())
+			Expect(updated.OwnerReferences).To(BeEmpty())
+		})
+
+		It("waits for a healthy primary before checking the extension", func() {
+			documentdb := newDocumentDB()
+			cluster := newAdoptableCluster(false)
+			fakeClient := fake.NewClientBuilder().WithScheme(scheme).WithObjects(documentdb, cluster).Build()
+
+			reconciler := &DocumentDBReconciler{
+				Client:   fakeClient,
+				Scheme:   scheme,
+				Recorder: recorder,
+				SQLExecutor: func(_ context.Context, _ *cnpgv1.Cluster, _ string) (string, error) {
+					Fail("SQL must not run before the primary is healthy")
+					return "", nil
+				},
+			}
+
+			adopted, err := reconciler.adoptCNPGCluster(ctx, documentdb, cluster)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(adopted).To(BeFalse())
+		})
+
+		It("refuses a cluster controlled by another owner", func() {
+			documentdb := newDocumentDB()
+			cluster := newAdoptableCluster(true)
+			cluster.OwnerReferences = []metav1.OwnerReference{{
+				APIVersion: "example.com/v1", Kind: "Other", Name: "other", UID: "other-uid",
+				Controller: ptr.To(true),
+			}}
+			fakeClient := fake.NewClientBuilder().WithScheme(scheme).WithObjects(documentdb, cluster).Build()
+
+			localRecorder := record.NewFakeRecorder(10)
+			reconciler := &DocumentDBReconciler{
+				Client:   fakeClient,
+				Scheme:   scheme,
+				Recorder: localRecorder,
+				SQLExecutor: func(_ context.Context, _ *cnpgv1.Cluster, _ string) (string, error) {
+					return "{\"installed\" : true}\n(1 row)", nil
+				},
+			}
+
+			adopted, err := reconciler.adoptCNPGCluster(ctx, documentdb, cluster)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(adopted).To(BeFalse())
+
+			var event string
+			Expect(localRecorder.Events).To(Receive(&event))
+			Expect(event).To(ContainSubstring("AdoptionFailed"))
+		})
+	})
+
+	Describe("deletionPolicy", func() {
+		newDeletingDocumentDB := func(policy dbpreview.DeletionPolicy) *dbpreview.DocumentDB {
+			return &dbpreview.DocumentDB{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:              documentDBName,
+					Namespace:         documentDBNamespace,
+					UID:               "documentdb-uid",
+					Finalizers:        []string{documentDBFinalizer},
+					DeletionTimestamp: &metav1.Time{Time: time.Now()},
+				},
+				Spec: dbpreview.DocumentDBSpec{
+					DeletionPolicy: policy,
+					Resource: dbpreview.Resource{
+						Storage: dbpreview.StorageConfiguration{
+							PersistentVolumeReclaimPolicy: "Delete",
+						},
+					},
+				},
+			}
+		}
+
+		newOwnedCluster := func() *cnpgv1.Cluster {
+			return &cnpgv1.Cluster{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      documentDBName,
+					Namespace: documentDBNamespace,
+					OwnerReferences: []metav1.OwnerReference{
+						{APIVersion: "documentdb.io/preview", Kind: "DocumentDB", Name: documentDBName, UID: "documentdb-uid"},
+						{APIVersion: "v1", Kind: "ConfigMap", Name: "other-owner", UID: "other-uid"},
+					},
+				},
+			}
+		}
+
+		It("detaches the CNPG Cluster before releasing the finalizer when set to Orphan", func() {
+			documentdb := newDeletingDocumentDB(dbpreview.DeletionPolicyOrphan)
+			fakeClient := fake.NewClientBuilder().
+				WithScheme(scheme).
+				WithObjects(documentdb, newOwnedCluster()).
+				Build()
+
+			localRecorder := record.NewFakeRecorder(10)
+			reconciler := &DocumentDBReconciler{Client: fakeClient, Scheme: scheme, Recorder: localRecorder}
+
+			done, _, err := reconciler.reconcileFinalizer(ctx, documentdb)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(done).To(BeTrue())
+
+			cluster := &cnpgv1.Cluster{}
+			Expect(fakeClient.Get(ctx, types.NamespacedName{Name: documentDBName, Namespace: documentDBNamespace}, cluster)).To(Succeed())
+			Expect(cluster.OwnerReferences).To(HaveLen(1))
+			Expect(cluster.OwnerReferences[0].UID).To(Equal(types.UID("other-uid")))
+
+			err = fakeClient.Get(ctx, types.NamespacedName{Name: documentDBName, Namespace: documentDBNamespace}, &dbpreview.DocumentDB{})
+			Expect(errors.IsNotFound(err)).To(BeTrue())
+
+			var event string
+			Expect(localRecorder.Events).To(Receive(&event))
+			Expect(event).To(ContainSubstring("ClusterOrphaned"))
+		})
+
+		It("leaves the owner reference for garbage collection by default", func() {
+			documentdb := newDeletingDocumentDB("")
+			fakeClient := fake.NewClientBuilder().
+				WithScheme(scheme).
+				WithObjects(documentdb, newOwnedCluster()).
+				Build()
+			reconciler := &DocumentDBReconciler{Client: fakeClient, Scheme: scheme, Recorder: recorder}
+
+			_, _, err := reconciler.reconcileFinalizer(ctx, documentdb)
+			Expect(err).ToNot(HaveOccurred())
+
+			cluster := &cnpgv1.Cluster{}
+			Expect(fakeClient.Get(ctx, types.NamespacedName{Name: documentDBName, Namespace: documentDBNamespace}, cluster)).To(Succeed())
+			Expect(cluster.OwnerReferences).To(HaveLen(2))
+		})
+
+		It("skips the recent backup check when set to Orphan", func() {
+			util.SetOperatorSettings(map[string]string{util.DELETION_BACKUP_MAX_AGE_ENV: "24h"})
+			DeferCleanup(util.SetOperatorSettings, map[string]string(nil))
+
+			documentdb := newDeletingDocumentDB(dbpreview.DeletionPolicyOrphan)
+			fakeClient := fake.NewClientBuilder().WithScheme(scheme).WithObjects(documentdb).Build()
+			reconciler := &DocumentDBReconciler{Client: fakeClient, Scheme: scheme, Recorder: recorder}
+
+			_, result, err := reconciler.reconcileFinalizer(ctx, documentdb)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(result.RequeueAfter).To(BeZero())
+
+			err = fakeClient.Get(ctx, types.NamespacedName{Name: documentDBName, Namespace: documentDBNamespace}, &dbpreview.DocumentDB{})
+			Expect(errors.IsNotFound(err)).To(BeTrue())
+		})
+	})
+
+	Describe("isDeletionBlockedByMissingBackup", func() {
+		BeforeEach(func() {
+			util.SetOperatorSettings(map[string]string{util.DELETION_BACKUP_MAX_AGE_ENV: "24h"})
+		})
+
+		AfterEach(func() {
+			util.SetOperatorSettings(nil)
+		})
+
+		newDeletingDocumentDB := func(policy string) *dbpreview.DocumentDB {
+			return &dbpreview.DocumentDB{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:              documentDBName,
+					Namespace:         documentDBNamespace,
+					Finalizers:        []string{documentDBFinalizer},
+					DeletionTimestamp: &metav1.Time{Time: time.Now()},
+				},
+				Spec: dbpreview.DocumentDBSpec{
+					Resource: dbpreview.Resource{
+						Storage: dbpreview.StorageConfiguration{
+							PersistentVolumeReclaimPolicy: policy,
+						},
+					},
+				},
+			}
+		}
+
+		newCompletedBackup := func(name, cluster string, stoppedAt time.Time) *dbpreview.Backup {
+			return &dbpreview.Backup{
+				ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: documentDBNamespace},
+				Spec:       dbpreview.BackupSpec{Cluster: cnpgv1.LocalObjectReference{Name: cluster}},
+				Status: dbpreview.BackupStatus{
+					Phase:     cnpgv1.BackupPhaseCompleted,
+					StoppedAt: &metav1.Time{Time: stoppedAt},
+				},
+			}
+		}
+
+		It("holds deletion and explains how to proceed when there is no recent backup", func() {
+			documentdb := newDeletingDocumentDB("Delete")
+			staleBackup := newCompletedBackup("stale", documentDBName, time.Now().Add(-48*time.Hour))
+			otherClusterBackup := newCompletedBackup("other", "other-cluster", time.Now())
+
+			fakeClient := fake.NewClientBuilder().
+				WithScheme(scheme).
+				WithObjects(documentdb, staleBackup, otherClusterBackup).
+				Build()
+
+			localRecorder := record.NewFakeRecorder(10)
+			reconciler := &DocumentDBReconciler{
+				Client:   fakeClient,
+				Scheme:   scheme,
+				Recorder: localRecorder,
+			}
+
+			done, result, err := reconciler.reconcileFinalizer(ctx, documentdb)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(done).To(BeTrue())
+			Expect(result.RequeueAfter).To(Equal(RequeueAfterLong))
+
+			updated := &dbpreview.DocumentDB{}
+			Expect(fakeClient.Get(ctx, types.NamespacedName{Name: documentDBName, Namespace: documentDBNamespace}, updated)).To(Succeed())
+			Expect(controllerutil.ContainsFinalizer(updated, documentDBFinalizer)).To(BeTrue())
+
+			var event string
+			Expect(localRecorder.Events).To(Receive(&event))
+			Expect(event).To(ContainSubstring("DeletionBlockedNoBackup"))
+			Expect(event).To(ContainSubstring(util.SKIP_DELETION_BACKUP_CHECK_ANNOTATION))
+		})
+
+		It("allows deletion when a backup completed within the max age", func() {
+			documentdb := newDeletingDocumentDB("Delete")
+			fakeClient := fake.NewClientBuilder().
+				WithScheme(scheme).
+				WithObjects(documentdb, newCompletedBackup("recent", documentDBName, time.Now().Add(-time.Hour))).
+				Build()
+			reconciler := &DocumentDBReconciler{Client: fakeClient, Scheme: scheme, Recorder: recorder}
+
+			blocked, err := reconciler.isDeletionBlockedByMissingBackup(ctx, documentdb)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(blocked).To(BeFalse())
+		})
+
+		It("allows deletion when the skip annotation is set", func() {
+			documentdb := newDeletingDocumentDB("Delete")
+			documentdb.Annotations = map[string]string{util.SKIP_DELETION_BACKUP_CHECK_ANNOTATION: "true"}
+			fakeClient := fake.NewClientBuilder().WithScheme(scheme).WithObjects(documentdb).Build()
+			reconciler := &DocumentDBReconciler{Client: fakeClient, Scheme: scheme, Recorder: recorder}
+
+			blocked, err := reconciler.isDeletionBlockedByMissingBackup(ctx, documentdb)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(blocked).To(BeFalse())
+		})
+
+		It("does not apply when PVs are retained or the check is disabled", func() {
+			documentdb := newDeletingDocumentDB("Retain")
+			fakeClient := fake.NewClientBuilder().WithScheme(scheme).WithObjects(documentdb).Build()
+			reconciler := &DocumentDBReconciler{Client: fakeClient, Scheme: scheme, Recorder: recorder}
+
+			blocked, err := reconciler.isDeletionBlockedByMissingBackup(ctx, documentdb)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(blocked).To(BeFalse())
+
+			util.SetOperatorSettings(nil)
+			documentdb.Spec.Resource.Storage.PersistentVolumeReclaimPolicy = "Delete"
+			blocked, err = reconciler.isDeletionBlockedByMissingBackup(ctx, documentdb)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(blocked).To(BeFalse())
+		})
 	})
 
 	Describe("reconcilePVRecovery", func() {
@@ -2531,6 +3139,40 @@ var _ = Describe("DocumentDB Controller", func() {
 			tempPVCName := documentDBName + "-pv-recovery-temp"
 			Expect(fakeClient.Get(ctx, types.NamespacedName{Name: tempPVCName, Namespace: documentDBNamespace}, tempPVC)).To(Succeed())
 			Expect(tempPVC.Spec.VolumeName).To(Equal("available-pv"))
+			Expect(tempPVC.Labels).NotTo(HaveKey(util.VeleroExcludeLabel))
+		})
+
+		It("excludes the temp PVC from Velero backups when spec.backup.velero is set", func() {
+			pv := &corev1.PersistentVolume{
+				ObjectMeta: metav1.ObjectMeta{Name: "available-pv"},
+				Spec: corev1.PersistentVolumeSpec{
+					StorageClassName: "standard",
+					Capacity: corev1.ResourceList{
+						corev1.ResourceStorage: resource.MustParse("10Gi"),
+					},
+				},
+				Status: corev1.PersistentVolumeStatus{Phase: corev1.VolumeAvailable},
+			}
+			documentdb := &dbpreview.DocumentDB{
+				ObjectMeta: metav1.ObjectMeta{Name: documentDBName, Namespace: documentDBNamespace, UID: "test-uid"},
+				Spec: dbpreview.DocumentDBSpec{
+					Backup: &dbpreview.BackupConfiguration{Velero: &dbpreview.VeleroConfiguration{}},
+					Bootstrap: &dbpreview.BootstrapConfiguration{
+						Recovery: &dbpreview.RecoveryConfiguration{
+							PersistentVolume: &dbpreview.PVRecoveryConfiguration{Name: "available-pv"},
+						},
+					},
+				},
+			}
+			fakeClient := fake.NewClientBuilder().WithScheme(scheme).WithObjects(documentdb, pv).Build()
+			reconciler := &DocumentDBReconciler{Client: fakeClient, Scheme: scheme, Recorder: recorder}
+
+			_, err := reconciler.reconcilePVRecovery(ctx, documentdb, documentDBNamespace, documentDBName)
+			Expect(err).ToNot(HaveOccurred())
+
+			tempPVC := &corev1.PersistentVolumeClaim{}
+			Expect(fakeClient.Get(ctx, types.NamespacedName{Name: documentDBName + "-pv-recovery-temp", Namespace: documentDBNamespace}, tempPVC)).To(Succeed())
+			Expect(tempPVC.Labels).To(HaveKeyWithValue(util.VeleroExcludeLabel, "true"))
 		})
 
 		It("waits for temp PVC to bind when it exists but is not bound", func() {
@@ -2595,7 +3237,7 @@ var _ = Describe("DocumentDB Controller", func() {
 			Expect(result.RequeueAfter).To(Equal(RequeueAfterShort))
 		})
 
-		It("proceeds when temp PVC is bound", func() {
+		It("proceeds when temp PVC is bound and the PV was checked", func() {
 			pv := &corev1.PersistentVolume{
 				ObjectMeta: metav1.ObjectMeta{
 					Name: "available-pv",
@@ -2614,14 +3256,15 @@ var _ = Describe("DocumentDB Controller", func() {
 
 			tempPVC := &corev1.PersistentVolumeClaim{
 				ObjectMeta: metav1.ObjectMeta{
-					Name:      documentDBName + "-pv-recovery-temp",
-					Namespace: documentDBNamespace,
+					Name:        documentDBName + "-pv-recovery-temp",
+					Namespace:   documentDBNamespace,
+					Annotations: map[string]string{util.PVRecoveryCheckedAnnotation: "true"},
 				},
 				Spec: corev1.PersistentVolumeClaimSpec{
 					VolumeName: "available-pv",
 				},
 				Status: corev1.PersistentVolumeClaimStatus{
-					Phase: corev1.ClaimBound, // Bound and ready
+					Phase: corev1.ClaimBound, // Bound, and the PV was checked
 				},
 			}
 
@@ -2767,6 +3410,113 @@ var _ = Describe("DocumentDB Controller", func() {
 			existingPVC := &corev1.PersistentVolumeClaim{}
 			Expect(fakeClient.Get(ctx, types.NamespacedName{Name: documentDBName + "-pv-recovery-temp", Namespace: documentDBNamespace}, existingPVC)).To(Succeed())
 		})
+
+		Context("when the recovery fails", func() {
+			var documentdb *dbpreview.DocumentDB
+
+			BeforeEach(func() {
+				documentdb = &dbpreview.DocumentDB{
+					ObjectMeta: metav1.ObjectMeta{
+						Name:      documentDBName,
+						Namespace: documentDBNamespace,
+					},
+					Spec: dbpreview.DocumentDBSpec{
+						Bootstrap: &dbpreview.BootstrapConfiguration{
+							Recovery: &dbpreview.RecoveryConfiguration{
+								PersistentVolume: &dbpreview.PVRecoveryConfiguration{Name: "some-pv"},
+							},
+						},
+					},
+				}
+			})
+
+			tempPVCCreatedAt := func(created time.Time, phase corev1.PersistentVolumeClaimPhase) *corev1.PersistentVolumeClaim {
+				return &corev1.PersistentVolumeClaim{
+					ObjectMeta: metav1.ObjectMeta{
+						Name:              documentDBName + "-pv-recovery-temp",
+						Namespace:         documentDBNamespace,
+						CreationTimestamp: metav1.NewTime(created),
+					},
+					Spec:   corev1.PersistentVolumeClaimSpec{VolumeName: "some-pv"},
+					Status: corev1.PersistentVolumeClaimStatus{Phase: phase},
+				}
+			}
+
+			cnpgClusterInPhase := func(phase string) *cnpgv1.Cluster {
+				return &cnpgv1.Cluster{
+					ObjectMeta: metav1.ObjectMeta{Name: documentDBName, Namespace: documentDBNamespace},
+					Status:     cnpgv1.ClusterStatus{Phase: phase},
+				}
+			}
+
+			expectTempPVCDeleted := func(reconciler *DocumentDBReconciler) {
+				err := reconciler.Get(ctx, types.NamespacedName{Name: documentDBName + "-pv-recovery-temp", Namespace: documentDBNamespace}, &corev1.PersistentVolumeClaim{})
+				Expect(errors.IsNotFound(err)).To(BeTrue())
+				Expect(recorder.Events).To(Receive(ContainSubstring("PVRecoveryFailed")))
+			}
+
+			It("deletes temp PVC when the CNPG cluster is unrecoverable", func() {
+				reconciler := &DocumentDBReconciler{
+					Client: fake.NewClientBuilder().WithScheme(scheme).
+						WithObjects(documentdb, cnpgClusterInPhase(cnpgv1.PhaseUnrecoverable), tempPVCCreatedAt(time.Now(), corev1.ClaimBound)).
+						Build(),
+					Scheme:   scheme,
+					Recorder: recorder,
+				}
+
+				_, err := reconciler.reconcilePVRecovery(ctx, documentdb, documentDBNamespace, documentDBName)
+				Expect(err).ToNot(HaveOccurred())
+				expectTempPVCDeleted(reconciler)
+			})
+
+			It("deletes temp PVC when the CNPG cluster is not healthy within the timeout", func() {
+				reconciler := &DocumentDBReconciler{
+					Client: fake.NewClientBuilder().WithScheme(scheme).
+						WithObjects(documentdb, cnpgClusterInPhase(cnpgv1.PhaseFirstPrimary),
+							tempPVCCreatedAt(time.Now().Add(-util.DEFAULT_PV_RECOVERY_TIMEOUT-time.Minute), corev1.ClaimBound)).
+						Build(),
+					Scheme:   scheme,
+					Recorder: recorder,
+				}
+
+				_, err := reconciler.reconcilePVRecovery(ctx, documentdb, documentDBNamespace, documentDBName)
+				Expect(err).ToNot(HaveOccurred())
+				expectTempPVCDeleted(reconciler)
+			})
+
+			It("deletes temp PVC that does not bind within the timeout", func() {
+				reconciler := &DocumentDBReconciler{
+					Client: fake.NewClientBuilder().WithScheme(scheme).
+						WithObjects(documentdb, tempPVCCreatedAt(time.Now().Add(-util.DEFAULT_PV_RECOVERY_TIMEOUT-time.Minute), corev1.ClaimPending)).
+						Build(),
+					Scheme:   scheme,
+					Recorder: recorder,
+				}
+
+				result, err := reconciler.reconcilePVRecovery(ctx, documentdb, documentDBNamespace, documentDBName)
+				Expect(err).ToNot(HaveOccurred())
+				Expect(result.RequeueAfter).To(Equal(RequeueAfterLong))
+				expectTempPVCDeleted(reconciler)
+			})
+
+			It("deletes temp PVC when the DocumentDB is deleted mid-recovery", func() {
+				documentdb.Finalizers = []string{documentDBFinalizer}
+				documentdb.DeletionTimestamp = &metav1.Time{Time: time.Now()}
+				reconciler := &DocumentDBReconciler{
+					Client: fake.NewClientBuilder().WithScheme(scheme).
+						WithObjects(documentdb, tempPVCCreatedAt(time.Now(), corev1.ClaimBound)).
+						Build(),
+					Scheme:   scheme,
+					Recorder: recorder,
+				}
+
+				done, _, err := reconciler.reconcileFinalizer(ctx, documentdb)
+				Expect(err).ToNot(HaveOccurred())
+				Expect(done).To(BeTrue())
+				err = reconciler.Get(ctx, types.NamespacedName{Name: documentDBName + "-pv-recovery-temp", Namespace: documentDBNamespace}, &corev1.PersistentVolumeClaim{})
+				Expect(errors.IsNotFound(err)).To(BeTrue())
+			})
+		})
 	})
 
 	Describe("SetupWithManager", func() {
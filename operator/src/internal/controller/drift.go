@@ -0,0 +1,76 @@
+// Copyright (c) Microsoft Corporation.
+// Licensed under the MIT License.
+
+package controller
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	cnpgv1 "github.com/cloudnative-pg/cloudnative-pg/api/v1"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/log"
+
+	dbpreview "github.com/documentdb/documentdb-operator/api/preview"
+	cnpg "github.com/documentdb/documentdb-operator/internal/cnpg"
+)
+
+// maxReportedDriftPaths caps the number of paths listed in the drift condition message.
+const maxReportedDriftPaths = 10
+
+// reportCNPGClusterDrift compares the live CNPG Cluster with the desired spec,
+// before the operator patches it, and records the result in the
+// CNPGClusterDrifted condition.
+//
+// Differences right after the DocumentDB spec changed are the rollout of that
+// change rather than drift, so they are only reported once the generation
+// observed by the condition matches the DocumentDB generation.
+func (r *DocumentDBReconciler) reportCNPGClusterDrift(ctx context.Context, documentdb *dbpreview.DocumentDB, current, desired *cnpgv1.Cluster) error {
+	paths, err := cnpg.DetectDrift(current, desired)
+	if err != nil {
+		return err
+	}
+
+	condition := metav1.Condition{
+		Type:               dbpreview.ConditionCNPGClusterDrifted,
+		Status:             metav1.ConditionFalse,
+		Reason:             dbpreview.ReasonInSync,
+		Message:            "CNPG Cluster matches the DocumentDB spec",
+		ObservedGeneration: documentdb.Generation,
+	}
+	previous := meta.FindStatusCondition(documentdb.Status.Conditions, dbpreview.ConditionCNPGClusterDrifted)
+	specChanged := previous == nil || previous.ObservedGeneration != documentdb.Generation
+	switch {
+	case len(paths) > 0 && specChanged:
+		condition.Reason = dbpreview.ReasonSpecChanged
+		condition.Message = "Applying DocumentDB spec changes to the CNPG Cluster"
+	case len(paths) > 0:
+		condition.Status = metav1.ConditionTrue
+		condition.Reason = dbpreview.ReasonSpecDrift
+		condition.Message = "CNPG Cluster differs from the DocumentDB spec at: " + summarizeDriftPaths(paths)
+	}
+
+	if !meta.SetStatusCondition(&documentdb.Status.Conditions, condition) {
+		return nil
+	}
+	if condition.Status == metav1.ConditionTrue {
+		log.FromContext(ctx).Info("CNPG Cluster drift detected", "cluster", current.Name, "paths", paths)
+		if r.Recorder != nil {
+			r.Recorder.Event(documentdb, corev1.EventTypeWarning, "CNPGClusterDrifted", condition.Message)
+		}
+	}
+	if err := r.updateStatus(ctx, documentdb); err != nil {
+		return fmt.Errorf("failed to update drift condition: %w", err)
+	}
+	return nil
+}
+
+func summarizeDriftPaths(paths []string) string {
+	if len(paths) <= maxReportedDriftPaths {
+		return strings.Join(paths, ", ")
+	}
+	return fmt.Sprintf("%s and %d more", strings.Join(paths[:maxReportedDriftPaths], ", "), len(paths)-maxReportedDriftPaths)
+}
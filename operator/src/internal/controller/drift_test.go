@@ -0,0 +1,120 @@
+// Copyright (c) Microsoft Corporation.
+// Licensed under the MIT License.
+
+package controller
+
+import (
+	"context"
+
+	cnpgv1 "github.com/cloudnative-pg/cloudnative-pg/api/v1"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/tools/record"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	dbpreview "github.com/documentdb/documentdb-operator/api/preview"
+)
+
+var _ = Describe("CNPG Cluster drift reporting", func() {
+	const (
+		name      = "drift-db"
+		namespace = "default"
+	)
+
+	var (
+		ctx        context.Context
+		documentdb *dbpreview.DocumentDB
+		desired    *cnpgv1.Cluster
+		recorder   *record.FakeRecorder
+		reconciler *DocumentDBReconciler
+	)
+
+	BeforeEach(func() {
+		ctx = context.Background()
+		scheme := runtime.NewScheme()
+		Expect(dbpreview.AddToScheme(scheme)).To(Succeed())
+		Expect(cnpgv1.AddToScheme(scheme)).To(Succeed())
+
+		documentdb = &dbpreview.DocumentDB{
+			ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: namespace, Generation: 2},
+		}
+		desired = &cnpgv1.Cluster{
+			ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: namespace},
+			Spec:       cnpgv1.ClusterSpec{Instances: 3},
+		}
+		recorder = record.NewFakeRecorder(10)
+		reconciler = &DocumentDBReconciler{
+			Client: fake.NewClientBuilder().
+				WithScheme(scheme).
+				WithObjects(documentdb).
+				WithStatusSubresource(&dbpreview.DocumentDB{}).
+				Build(),
+			Scheme:   scheme,
+			Recorder: recorder,
+		}
+	})
+
+	driftCondition := func() *metav1.Condition {
+		stored := &dbpreview.DocumentDB{}
+		Expect(reconciler.Get(ctx, types.NamespacedName{Name: name, Namespace: namespace}, stored)).To(Succeed())
+		return meta.FindStatusCondition(stored.Status.Conditions, dbpreview.ConditionCNPGClusterDrifted)
+	}
+
+	It("reports the cluster in sync when it matches", func() {
+		Expect(reconciler.reportCNPGClusterDrift(ctx, documentdb, desired.DeepCopy(), desired)).To(Succeed())
+
+		condition := driftCondition()
+		Expect(condition).ToNot(BeNil())
+		Expect(condition.Status).To(Equal(metav1.ConditionFalse))
+		Expect(condition.Reason).To(Equal(dbpreview.ReasonInSync))
+		Expect(condition.ObservedGeneration).To(Equal(int64(2)))
+		Expect(recorder.Events).To(BeEmpty())
+	})
+
+	It("does not report drift while a DocumentDB spec change rolls out", func() {
+		current := desired.DeepCopy()
+		current.Spec.Instances = 1
+
+		Expect(reconciler.reportCNPGClusterDrift(ctx, documentdb, current, desired)).To(Succeed())
+
+		condition := driftCondition()
+		Expect(condition.Status).To(Equal(metav1.ConditionFalse))
+		Expect(condition.Reason).To(Equal(dbpreview.ReasonSpecChanged))
+		Expect(recorder.Events).To(BeEmpty())
+	})
+
+	It("reports drift and emits an event when the cluster was changed by hand", func() {
+		Expect(reconciler.reportCNPGClusterDrift(ctx, documentdb, desired.DeepCopy(), desired)).To(Succeed())
+
+		current := desired.DeepCopy()
+		current.Spec.Instances = 1
+		Expect(reconciler.reportCNPGClusterDrift(ctx, documentdb, current, desired)).To(Succeed())
+
+		condition := driftCondition()
+		Expect(condition.Status).To(Equal(metav1.ConditionTrue))
+		Expect(condition.Reason).To(Equal(dbpreview.ReasonSpecDrift))
+		Expect(condition.Message).To(ContainSubstring("/spec/instances"))
+		Expect(recorder.Events).To(Receive(ContainSubstring("CNPGClusterDrifted")))
+
+		// The same drift is not reported twice
+		Expect(reconciler.reportCNPGClusterDrift(ctx, documentdb, current, desired)).To(Succeed())
+		Expect(recorder.Events).To(BeEmpty())
+
+		// Clears once the operator has restored the cluster
+		Expect(reconciler.reportCNPGClusterDrift(ctx, documentdb, desired.DeepCopy(), desired)).To(Succeed())
+		Expect(driftCondition().Status).To(Equal(metav1.ConditionFalse))
+	})
+
+	It("summarizes long lists of drifted paths", func() {
+		paths := make([]string, maxReportedDriftPaths+2)
+		for i := range paths {
+			paths[i] = "/spec/p"
+		}
+		Expect(summarizeDriftPaths(paths)).To(HaveSuffix("and 2 more"))
+		Expect(summarizeDriftPaths(paths[:1])).To(Equal("/spec/p"))
+	})
+})
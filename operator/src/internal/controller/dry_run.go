@@ -0,0 +1,114 @@
+// Copyright (c) Microsoft Corporation.
+// Licensed under the MIT License.
+
+package controller
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strconv"
+
+	cnpgv1 "github.com/cloudnative-pg/cloudnative-pg/api/v1"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
+	"sigs.k8s.io/controller-runtime/pkg/log"
+	"sigs.k8s.io/yaml"
+
+	dbpreview "github.com/documentdb/documentdb-operator/api/preview"
+	cnpg "github.com/documentdb/documentdb-operator/internal/cnpg"
+)
+
+const (
+	// dryRunConfigMapSuffix names the ConfigMap, <documentdb>-cnpg-dry-run, that
+	// holds the rendered CNPG Cluster while dry-run mode is on.
+	dryRunConfigMapSuffix = "-cnpg-dry-run"
+
+	// Dry-run ConfigMap keys.
+	dryRunActionKey          = "action"
+	dryRunClusterKey         = "cluster.yaml"
+	dryRunPatchKey           = "patch.json"
+	dryRunRestartRequiredKey = "restartRequired"
+
+	// Values of the dry-run action key.
+	dryRunActionCreate = "create"
+	dryRunActionPatch  = "patch"
+	dryRunActionNone   = "none"
+)
+
+func dryRunConfigMapName(documentdbName string) string {
+	return documentdbName + dryRunConfigMapSuffix
+}
+
+// renderDryRun writes the CNPG Cluster the operator would create, or the patch it
+// would apply to the existing one, to the dry-run ConfigMap. Nothing is applied
+// to the CNPG Cluster itself.
+func (r *DocumentDBReconciler) renderDryRun(ctx context.Context, documentdb *dbpreview.DocumentDB, desired *cnpgv1.Cluster) error {
+	logger := log.FromContext(ctx)
+
+	rendered := desired.DeepCopy()
+	rendered.TypeMeta.APIVersion = cnpgv1.SchemeGroupVersion.String()
+	rendered.TypeMeta.Kind = "Cluster"
+	clusterYAML, err := yaml.Marshal(rendered)
+	if err != nil {
+		return fmt.Errorf("failed to render CNPG Cluster: %w", err)
+	}
+
+	data := map[string]string{
+		dryRunActionKey:  dryRunActionCreate,
+		dryRunClusterKey: string(clusterYAML),
+	}
+
+	current := &cnpgv1.Cluster{}
+	err = r.Get(ctx, types.NamespacedName{Name: desired.Name, Namespace: desired.Namespace}, current)
+	if err != nil && !errors.IsNotFound(err) {
+		return fmt.Errorf("failed to get CNPG Cluster: %w", err)
+	}
+	if err == nil {
+		patchOps, needsRestart, err := cnpg.BuildSyncPatch(current, desired)
+		if err != nil {
+			return err
+		}
+		data[dryRunActionKey] = dryRunActionNone
+		if len(patchOps) > 0 {
+			data[dryRunActionKey] = dryRunActionPatch
+			patchJSON, err := json.MarshalIndent(patchOps, "", "  ")
+			if err != nil {
+				return fmt.Errorf("failed to render CNPG Cluster patch: %w", err)
+			}
+			data[dryRunPatchKey] = string(patchJSON)
+		}
+		data[dryRunRestartRequiredKey] = strconv.FormatBool(needsRestart)
+	}
+
+	cm := &corev1.ConfigMap{}
+	cm.Name = dryRunConfigMapName(documentdb.Name)
+	cm.Namespace = documentdb.Namespace
+	result, err := controllerutil.CreateOrUpdate(ctx, r.Client, cm, func() error {
+		if err := controllerutil.SetControllerReference(documentdb, cm, r.Scheme); err != nil {
+			return fmt.Errorf("failed to set owner reference: %w", err)
+		}
+		cm.Data = data
+		return nil
+	})
+	if err != nil {
+		return fmt.Errorf("failed to reconcile dry-run ConfigMap %s: %w", cm.Name, err)
+	}
+	if result != controllerutil.OperationResultNone {
+		logger.Info("Dry-run ConfigMap rendered", "name", cm.Name, "action", data[dryRunActionKey])
+	}
+	return nil
+}
+
+// deleteDryRunConfigMap removes the dry-run ConfigMap once dry-run mode is off.
+func (r *DocumentDBReconciler) deleteDryRunConfigMap(ctx context.Context, documentdb *dbpreview.DocumentDB) error {
+	cm := &corev1.ConfigMap{}
+	cm.Name = dryRunConfigMapName(documentdb.Name)
+	cm.Namespace = documentdb.Namespace
+	if err := r.Delete(ctx, cm); err != nil && !errors.IsNotFound(err) {
+		return fmt.Errorf("failed to delete dry-run ConfigMap %s: %w", cm.Name, err)
+	}
+	return nil
+}
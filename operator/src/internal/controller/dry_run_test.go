@@ -0,0 +1,149 @@
+// Copyright (c) Microsoft Corporation.
+// Licensed under the MIT License.
+
+package controller
+
+import (
+	"context"
+
+	cnpgv1 "github.com/cloudnative-pg/cloudnative-pg/api/v1"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	corev1 "k8s.io/api/core/v1"
+	rbacv1 "k8s.io/api/rbac/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/tools/record"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	dbpreview "github.com/documentdb/documentdb-operator/api/preview"
+	util "github.com/documentdb/documentdb-operator/internal/utils"
+)
+
+var _ = Describe("Dry-run rendering", func() {
+	const (
+		name      = "dry-run-db"
+		namespace = "default"
+	)
+
+	var (
+		ctx    context.Context
+		scheme *runtime.Scheme
+	)
+
+	BeforeEach(func() {
+		ctx = context.Background()
+		scheme = runtime.NewScheme()
+		Expect(dbpreview.AddToScheme(scheme)).To(Succeed())
+		Expect(cnpgv1.AddToScheme(scheme)).To(Succeed())
+		Expect(corev1.AddToScheme(scheme)).To(Succeed())
+		Expect(rbacv1.AddToScheme(scheme)).To(Succeed())
+	})
+
+	newDocumentDB := func(dryRun bool) *dbpreview.DocumentDB {
+		documentdb := &dbpreview.DocumentDB{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:       name,
+				Namespace:  namespace,
+				Finalizers: []string{documentDBFinalizer},
+			},
+			Spec: dbpreview.DocumentDBSpec{
+				InstancesPerNode: 1,
+				Resource: dbpreview.Resource{
+					Storage: dbpreview.StorageConfiguration{PvcSize: "1Gi"},
+				},
+			},
+		}
+		if dryRun {
+			documentdb.Annotations = map[string]string{util.DRY_RUN_ANNOTATION: "true"}
+		}
+		return documentdb
+	}
+
+	It("renders the CNPG Cluster it would create without creating it", func() {
+		documentdb := newDocumentDB(true)
+		fakeClient := fake.NewClientBuilder().
+			WithScheme(scheme).
+			WithObjects(documentdb).
+			WithStatusSubresource(&dbpreview.DocumentDB{}).
+			Build()
+		reconciler := &DocumentDBReconciler{Client: fakeClient, Scheme: scheme, Recorder: record.NewFakeRecorder(10)}
+
+		result, err := reconciler.Reconcile(ctx, ctrl.Request{NamespacedName: types.NamespacedName{Name: name, Namespace: namespace}})
+		Expect(err).ToNot(HaveOccurred())
+		Expect(result).To(Equal(ctrl.Result{}))
+
+		err = fakeClient.Get(ctx, types.NamespacedName{Name: name, Namespace: namespace}, &cnpgv1.Cluster{})
+		Expect(errors.IsNotFound(err)).To(BeTrue())
+
+		cm := &corev1.ConfigMap{}
+		Expect(fakeClient.Get(ctx, types.NamespacedName{Name: dryRunConfigMapName(name), Namespace: namespace}, cm)).To(Succeed())
+		Expect(cm.Data[dryRunActionKey]).To(Equal(dryRunActionCreate))
+		Expect(cm.Data[dryRunClusterKey]).To(ContainSubstring("kind: Cluster"))
+		Expect(cm.Data[dryRunClusterKey]).To(ContainSubstring("instances: 1"))
+		Expect(cm.Data).ToNot(HaveKey(dryRunPatchKey))
+	})
+
+	It("renders the patch it would apply to an existing CNPG Cluster", func() {
+		documentdb := newDocumentDB(true)
+		reconciler := &DocumentDBReconciler{Scheme: scheme}
+		desired := &cnpgv1.Cluster{
+			ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: namespace},
+			Spec:       cnpgv1.ClusterSpec{Instances: 3},
+		}
+		current := desired.DeepCopy()
+		current.Spec.Instances = 1
+		reconciler.Client = fake.NewClientBuilder().WithScheme(scheme).WithObjects(documentdb, current).Build()
+
+		Expect(reconciler.renderDryRun(ctx, documentdb, desired)).To(Succeed())
+
+		cm := &corev1.ConfigMap{}
+		Expect(reconciler.Get(ctx, types.NamespacedName{Name: dryRunConfigMapName(name), Namespace: namespace}, cm)).To(Succeed())
+		Expect(cm.Data[dryRunActionKey]).To(Equal(dryRunActionPatch))
+		Expect(cm.Data[dryRunPatchKey]).To(ContainSubstring(`"path": "/spec/instances"`))
+		Expect(cm.Data[dryRunRestartRequiredKey]).To(Equal("false"))
+
+		// The live cluster is left untouched
+		live := &cnpgv1.Cluster{}
+		Expect(reconciler.Get(ctx, types.NamespacedName{Name: name, Namespace: namespace}, live)).To(Succeed())
+		Expect(live.Spec.Instances).To(Equal(1))
+	})
+
+	It("reports no action when the existing CNPG Cluster is up to date", func() {
+		documentdb := newDocumentDB(true)
+		desired := &cnpgv1.Cluster{
+			ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: namespace},
+			Spec:       cnpgv1.ClusterSpec{Instances: 1},
+		}
+		reconciler := &DocumentDBReconciler{
+			Scheme: scheme,
+			Client: fake.NewClientBuilder().WithScheme(scheme).WithObjects(documentdb, desired.DeepCopy()).Build(),
+		}
+
+		Expect(reconciler.renderDryRun(ctx, documentdb, desired)).To(Succeed())
+
+		cm := &corev1.ConfigMap{}
+		Expect(reconciler.Get(ctx, types.NamespacedName{Name: dryRunConfigMapName(name), Namespace: namespace}, cm)).To(Succeed())
+		Expect(cm.Data[dryRunActionKey]).To(Equal(dryRunActionNone))
+		Expect(cm.Data).ToNot(HaveKey(dryRunPatchKey))
+	})
+
+	It("deletes the dry-run ConfigMap once dry-run mode is turned off", func() {
+		documentdb := newDocumentDB(false)
+		cm := &corev1.ConfigMap{ObjectMeta: metav1.ObjectMeta{Name: dryRunConfigMapName(name), Namespace: namespace}}
+		reconciler := &DocumentDBReconciler{
+			Scheme: scheme,
+			Client: fake.NewClientBuilder().WithScheme(scheme).WithObjects(documentdb, cm).Build(),
+		}
+
+		Expect(reconciler.deleteDryRunConfigMap(ctx, documentdb)).To(Succeed())
+		err := reconciler.Get(ctx, types.NamespacedName{Name: cm.Name, Namespace: namespace}, &corev1.ConfigMap{})
+		Expect(errors.IsNotFound(err)).To(BeTrue())
+
+		// Deleting again is a no-op
+		Expect(reconciler.deleteDryRunConfigMap(ctx, documentdb)).To(Succeed())
+	})
+})
@@ -0,0 +1,162 @@
+// Copyright (c) Microsoft Corporation.
+// Licensed under the MIT License.
+
+package controller
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"slices"
+	"strconv"
+	"strings"
+
+	cnpgv1 "github.com/cloudnative-pg/cloudnative-pg/api/v1"
+	corev1 "k8s.io/api/core/v1"
+	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
+	"sigs.k8s.io/controller-runtime/pkg/log"
+
+	dbpreview "github.com/documentdb/documentdb-operator/api/preview"
+	util "github.com/documentdb/documentdb-operator/internal/utils"
+)
+
+const (
+	// endpointsConfigMapSuffix names the ConfigMap, <documentdb>-endpoints, that
+	// publishes the non-secret connection metadata of a DocumentDB.
+	endpointsConfigMapSuffix = "-endpoints"
+
+	// Endpoints ConfigMap keys.
+	endpointsHostKey            = "host"
+	endpointsServiceHostKey     = "serviceHost"
+	endpointsPortKey            = "port"
+	endpointsReplicaSetKey      = "replicaSet"
+	endpointsTLSModeKey         = "tlsMode"
+	endpointsTLSTrustedKey      = "tlsTrusted"
+	endpointsPrimaryInstanceKey = "primaryInstance"
+	endpointsReadEndpointsKey   = "readEndpoints"
+
+	// endpointsReplicaSet is the replica set name the gateway reports.
+	endpointsReplicaSet = "rs0"
+)
+
+func endpointsConfigMapName(documentdbName string) string {
+	return documentdbName + endpointsConfigMapSuffix
+}
+
+// reconcileEndpointsConfigMap publishes the topology of documentdb, as last
+// reported by its CNPG Cluster, for applications and sidecars to discover
+// without reading the DocumentDB resource. host is the address of the
+// DocumentDB Service, empty when the DocumentDB is not exposed.
+func (r *DocumentDBReconciler) reconcileEndpointsConfigMap(ctx context.Context, documentdb *dbpreview.DocumentDB, cluster *cnpgv1.Cluster, host string) error {
+	configMap := &corev1.ConfigMap{}
+	configMap.Name = endpointsConfigMapName(documentdb.Name)
+	configMap.Namespace = documentdb.Namespace
+	result, err := controllerutil.CreateOrUpdate(ctx, r.Client, configMap, func() error {
+		if err := controllerutil.SetControllerReference(documentdb, configMap, r.Scheme); err != nil {
+			return fmt.Errorf("failed to set owner reference: %w", err)
+		}
+		configMap.Labels = util.ChildLabels(documentdb, map[string]string{util.LABEL_DOCUMENTDB_NAME: documentdb.Name})
+		configMap.Data = endpointsData(documentdb, cluster, host)
+		return nil
+	})
+	if err != nil {
+		return fmt.Errorf("failed to reconcile endpoints ConfigMap %s: %w", configMap.Name, err)
+	}
+	if result != controllerutil.OperationResultNone {
+		log.FromContext(ctx).Info("Endpoints ConfigMap reconciled", "name", configMap.Name, "operation", result)
+	}
+	return nil
+}
+
+// endpointsData returns the content of the endpoints ConfigMap. Keys whose
+// value is not known yet are left out rather than published empty.
+func endpointsData(documentdb *dbpreview.DocumentDB, cluster *cnpgv1.Cluster, host string) map[string]string {
+	port := util.GetGatewayPort(documentdb)
+	data := map[string]string{
+		endpointsPortKey:       strconv.Itoa(int(port)),
+		endpointsReplicaSetKey: endpointsReplicaSet,
+		endpointsTLSModeKey:    gatewayTLSMode(documentdb),
+		endpointsTLSTrustedKey: strconv.FormatBool(documentdb.Status.TLS != nil && documentdb.Status.TLS.Ready),
+	}
+	if host != "" {
+		data[endpointsHostKey] = host
+	}
+	if documentdb.Spec.ExposeViaService.ServiceType != "" {
+		data[endpointsServiceHostKey] = fmt.Sprintf("%s.%s.svc", util.DocumentDBServiceName(documentdb), documentdb.Namespace)
+	}
+	if cluster.Status.CurrentPrimary != "" {
+		data[endpointsPrimaryInstanceKey] = cluster.Status.CurrentPrimary
+	}
+
+	if readEndpoints := replicaGatewayEndpoints(cluster, port); len(readEndpoints) > 0 {
+		data[endpointsReadEndpointsKey] = strings.Join(readEndpoints, ",")
+	}
+	return data
+}
+
+// replicaGatewayEndpoints returns the sorted host:port of the gateways of the
+// replicas of cluster, listening on gatewayPort. Every instance runs a
+// gateway; the replicas serve reads.
+func replicaGatewayEndpoints(cluster *cnpgv1.Cluster, gatewayPort int32) []string {
+	port := strconv.Itoa(int(gatewayPort))
+	var endpoints []string
+	for _, state := range cluster.Status.InstancesReportedState {
+		if !state.IsPrimary && state.IP != "" {
+			endpoints = append(endpoints, net.JoinHostPort(state.IP, port))
+		}
+	}
+	slices.Sort(endpoints)
+	return endpoints
+}
+
+// connectionSeeds returns the seeds of the connection strings of documentdb:
+// host, the address of the DocumentDB Service, followed by the gateways of
+// the replicas. Those are Pod IPs, which only clients in the cluster reach
+// and which the gateway certificates do not cover, so they are only listed
+// for a ClusterIP Service whose certificates are not verified.
+func connectionSeeds(documentdb *dbpreview.DocumentDB, cluster *cnpgv1.Cluster, host string, trustTLS bool) []string {
+	seeds := []string{host}
+	if documentdb.Spec.ExposeViaService.ServiceType != string(corev1.ServiceTypeClusterIP) || trustTLS {
+		return seeds
+	}
+	return append(seeds, replicaGatewayEndpoints(cluster, util.GetGatewayPort(documentdb))...)
+}
+
+// endpointStatuses returns the gateway endpoint of each member cluster of the
+// replicated documentdb, in clusterList order, or nil when it is not
+// replicated. host is the address of the local DocumentDB Service, empty
+// when it is not exposed.
+func endpointStatuses(documentdb *dbpreview.DocumentDB, replicationContext *util.ReplicationContext, host string) []dbpreview.EndpointStatus {
+	if !replicationContext.IsReplicating() {
+		return nil
+	}
+	replication := documentdb.Spec.ClusterReplication
+	endpoints := make([]dbpreview.EndpointStatus, 0, len(replication.ClusterList))
+	for _, member := range replication.ClusterList {
+		endpoint := dbpreview.EndpointStatus{
+			Cluster: member.Name,
+			Role:    dbpreview.EndpointRoleReadOnly,
+		}
+		if member.Name == replication.Primary {
+			endpoint.Role = dbpreview.EndpointRoleReadWrite
+		}
+		if member.Name == replicationContext.FleetMemberName {
+			endpoint.Local = true
+			if host != "" {
+				endpoint.Host = host
+				endpoint.Port = util.GetGatewayPort(documentdb)
+			}
+		}
+		endpoints = append(endpoints, endpoint)
+	}
+	return endpoints
+}
+
+// gatewayTLSMode returns the gateway TLS mode of documentdb, SelfSigned unless
+// configured otherwise.
+func gatewayTLSMode(documentdb *dbpreview.DocumentDB) string {
+	if tls := documentdb.Spec.TLS; tls != nil && tls.Gateway != nil && tls.Gateway.Mode != "" {
+		return tls.Gateway.Mode
+	}
+	return "SelfSigned"
+}
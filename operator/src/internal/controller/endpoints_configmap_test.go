@@ -0,0 +1,170 @@
+// Copyright (c) Microsoft Corporation.
+// Licensed under the MIT License.
+
+package controller
+
+import (
+	"context"
+
+	cnpgv1 "github.com/cloudnative-pg/cloudnative-pg/api/v1"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	dbpreview "github.com/documentdb/documentdb-operator/api/preview"
+	util "github.com/documentdb/documentdb-operator/internal/utils"
+)
+
+var _ = Describe("Endpoints ConfigMap", func() {
+	const namespace = "default"
+
+	var (
+		ctx        context.Context
+		scheme     *runtime.Scheme
+		documentdb *dbpreview.DocumentDB
+		cluster    *cnpgv1.Cluster
+	)
+
+	BeforeEach(func() {
+		ctx = context.Background()
+		scheme = runtime.NewScheme()
+		Expect(dbpreview.AddToScheme(scheme)).To(Succeed())
+		Expect(cnpgv1.AddToScheme(scheme)).To(Succeed())
+		Expect(corev1.AddToScheme(scheme)).To(Succeed())
+		documentdb = &dbpreview.DocumentDB{
+			ObjectMeta: metav1.ObjectMeta{Name: "db", Namespace: namespace, UID: "db-uid"},
+			Spec: dbpreview.DocumentDBSpec{
+				ExposeViaService: dbpreview.ExposeViaService{ServiceType: "ClusterIP"},
+			},
+		}
+		cluster = &cnpgv1.Cluster{
+			ObjectMeta: metav1.ObjectMeta{Name: "db", Namespace: namespace},
+			Status: cnpgv1.ClusterStatus{
+				CurrentPrimary: "db-1",
+				InstancesReportedState: map[cnpgv1.PodName]cnpgv1.InstanceReportedState{
+					"db-1": {IsPrimary: true, IP: "10.1.0.1"},
+					"db-3": {IP: "10.1.0.3"},
+					"db-2": {IP: "10.1.0.2"},
+				},
+			},
+		}
+	})
+
+	getConfigMap := func(reconciler *DocumentDBReconciler) *corev1.ConfigMap {
+		configMap := &corev1.ConfigMap{}
+		Expect(reconciler.Get(ctx, types.NamespacedName{Name: "db-endpoints", Namespace: namespace}, configMap)).To(Succeed())
+		return configMap
+	}
+
+	It("publishes the topology owned by the DocumentDB", func() {
+		reconciler := &DocumentDBReconciler{
+			Client: fake.NewClientBuilder().WithScheme(scheme).WithObjects(documentdb).Build(),
+			Scheme: scheme,
+		}
+
+		Expect(reconciler.reconcileEndpointsConfigMap(ctx, documentdb, cluster, "10.0.0.1")).To(Succeed())
+
+		configMap := getConfigMap(reconciler)
+		Expect(configMap.Data).To(Equal(map[string]string{
+			"host":            "10.0.0.1",
+			"serviceHost":     "documentdb-service-db.default.svc",
+			"port":            "10260",
+			"replicaSet":      "rs0",
+			"tlsMode":         "SelfSigned",
+			"tlsTrusted":      "false",
+			"primaryInstance": "db-1",
+			"readEndpoints":   "10.1.0.2:10260,10.1.0.3:10260",
+		}))
+		Expect(configMap.Labels).To(HaveKeyWithValue(util.LABEL_DOCUMENTDB_NAME, "db"))
+		Expect(configMap.OwnerReferences).To(HaveLen(1))
+		Expect(configMap.OwnerReferences[0].Name).To(Equal("db"))
+	})
+
+	It("follows a failover and the TLS status", func() {
+		reconciler := &DocumentDBReconciler{
+			Client: fake.NewClientBuilder().WithScheme(scheme).WithObjects(documentdb).Build(),
+			Scheme: scheme,
+		}
+		Expect(reconciler.reconcileEndpointsConfigMap(ctx, documentdb, cluster, "10.0.0.1")).To(Succeed())
+
+		cluster.Status.CurrentPrimary = "db-2"
+		cluster.Status.InstancesReportedState = map[cnpgv1.PodName]cnpgv1.InstanceReportedState{
+			"db-1": {IP: "10.1.0.1"},
+			"db-2": {IsPrimary: true, IP: "10.1.0.2"},
+		}
+		documentdb.Spec.TLS = &dbpreview.TLSConfiguration{Gateway: &dbpreview.GatewayTLS{Mode: "CertManager"}}
+		documentdb.Status.TLS = &dbpreview.TLSStatus{Ready: true}
+		Expect(reconciler.reconcileEndpointsConfigMap(ctx, documentdb, cluster, "10.0.0.1")).To(Succeed())
+
+		configMap := getConfigMap(reconciler)
+		Expect(configMap.Data).To(HaveKeyWithValue("primaryInstance", "db-2"))
+		Expect(configMap.Data).To(HaveKeyWithValue("readEndpoints", "10.1.0.1:10260"))
+		Expect(configMap.Data).To(HaveKeyWithValue("tlsMode", "CertManager"))
+		Expect(configMap.Data).To(HaveKeyWithValue("tlsTrusted", "true"))
+	})
+
+	It("leaves out the endpoints that are not known yet", func() {
+		documentdb.Spec.ExposeViaService.ServiceType = ""
+		reconciler := &DocumentDBReconciler{
+			Client: fake.NewClientBuilder().WithScheme(scheme).WithObjects(documentdb).Build(),
+			Scheme: scheme,
+		}
+
+		Expect(reconciler.reconcileEndpointsConfigMap(ctx, documentdb, &cnpgv1.Cluster{}, "")).To(Succeed())
+
+		configMap := getConfigMap(reconciler)
+		Expect(configMap.Data).ToNot(HaveKey("host"))
+		Expect(configMap.Data).ToNot(HaveKey("serviceHost"))
+		Expect(configMap.Data).ToNot(HaveKey("primaryInstance"))
+		Expect(configMap.Data).ToNot(HaveKey("readEndpoints"))
+		Expect(configMap.Data).To(HaveKeyWithValue("port", "10260"))
+	})
+
+	It("reports the endpoint of each member cluster of a replicated DocumentDB", func() {
+		documentdb.Name = "member-b"
+		documentdb.Spec.ClusterReplication = &dbpreview.ClusterReplication{
+			CrossCloudNetworkingStrategy: string(util.None),
+			Primary:                      "member-a",
+			ClusterList:                  []dbpreview.MemberCluster{{Name: "member-a"}, {Name: "member-b"}},
+		}
+		c := fake.NewClientBuilder().WithScheme(scheme).Build()
+		replicationContext, err := util.GetReplicationContext(ctx, c, *documentdb)
+		Expect(err).ToNot(HaveOccurred())
+
+		Expect(endpointStatuses(documentdb, replicationContext, "")).To(Equal([]dbpreview.EndpointStatus{
+			{Cluster: "member-a", Role: dbpreview.EndpointRoleReadWrite},
+			{Cluster: "member-b", Role: dbpreview.EndpointRoleReadOnly, Local: true},
+		}))
+		Expect(endpointStatuses(documentdb, replicationContext, "10.0.0.2")).To(ContainElement(dbpreview.EndpointStatus{
+			Cluster: "member-b", Role: dbpreview.EndpointRoleReadOnly, Local: true, Host: "10.0.0.2", Port: 10260,
+		}))
+
+		// After a failover the local gateway accepts writes
+		documentdb.Spec.ClusterReplication.Primary = "member-b"
+		replicationContext, err = util.GetReplicationContext(ctx, c, *documentdb)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(endpointStatuses(documentdb, replicationContext, "10.0.0.2")).To(Equal([]dbpreview.EndpointStatus{
+			{Cluster: "member-a", Role: dbpreview.EndpointRoleReadOnly},
+			{Cluster: "member-b", Role: dbpreview.EndpointRoleReadWrite, Local: true, Host: "10.0.0.2", Port: 10260},
+		}))
+	})
+
+	It("reports no member endpoints without replication", func() {
+		replicationContext, err := util.GetReplicationContext(ctx, fake.NewClientBuilder().WithScheme(scheme).Build(), *documentdb)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(endpointStatuses(documentdb, replicationContext, "10.0.0.1")).To(BeNil())
+	})
+
+	It("seeds the connection strings with the replica gateways of a ClusterIP Service", func() {
+		Expect(connectionSeeds(documentdb, cluster, "10.0.0.1", false)).To(Equal([]string{"10.0.0.1", "10.1.0.2:10260", "10.1.0.3:10260"}))
+		// The gateway certificates do not cover the Pod IPs.
+		Expect(connectionSeeds(documentdb, cluster, "10.0.0.1", true)).To(Equal([]string{"10.0.0.1"}))
+
+		documentdb.Spec.ExposeViaService.ServiceType = "LoadBalancer"
+		Expect(connectionSeeds(documentdb, cluster, "20.0.0.1", false)).To(Equal([]string{"20.0.0.1"}))
+	})
+})
@@ -0,0 +1,84 @@
+// Copyright (c) Microsoft Corporation.
+// Licensed under the MIT License.
+
+package controller
+
+import (
+	"cmp"
+	"context"
+	"fmt"
+	"maps"
+
+	cnpgv1 "github.com/cloudnative-pg/cloudnative-pg/api/v1"
+	corev1 "k8s.io/api/core/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	dbpreview "github.com/documentdb/documentdb-operator/api/preview"
+)
+
+const (
+	// safeToEvictAnnotation tells the cluster autoscaler whether it may evict
+	// a pod to remove its node.
+	safeToEvictAnnotation = "cluster-autoscaler.kubernetes.io/safe-to-evict"
+
+	// doNotDisruptAnnotation keeps Karpenter from disrupting the node of a
+	// pod; it has no opposite.
+	doNotDisruptAnnotation = "karpenter.sh/do-not-disrupt"
+)
+
+// evictionAnnotations returns the autoscaler annotations of a pod evicted
+// following policy.
+func evictionAnnotations(policy dbpreview.EvictionPolicy) map[string]string {
+	if policy == dbpreview.EvictionPolicyBlock {
+		return map[string]string{safeToEvictAnnotation: "false", doNotDisruptAnnotation: "true"}
+	}
+	return map[string]string{safeToEvictAnnotation: "true"}
+}
+
+// reconcileEvictionAnnotations sets the autoscaler annotations of
+// spec.eviction on the instance pods of cluster, following the role of each
+// pod, since CNPG gives all its pods the same metadata. Without
+// spec.eviction, it removes the annotations spec.inheritedMetadata does not
+// set.
+func (r *DocumentDBReconciler) reconcileEvictionAnnotations(ctx context.Context, documentdb *dbpreview.DocumentDB, cluster *cnpgv1.Cluster) error {
+	pods := &corev1.PodList{}
+	if err := r.List(ctx, pods, client.InNamespace(cluster.Namespace), client.MatchingLabels{
+		"cnpg.io/cluster": cluster.Name,
+		"cnpg.io/podRole": "instance",
+	}); err != nil {
+		return fmt.Errorf("failed to list instance Pods: %w", err)
+	}
+	eviction := documentdb.Spec.Eviction
+	for i := range pods.Items {
+		pod := &pods.Items[i]
+		var desired map[string]string
+		if eviction != nil {
+			policy := cmp.Or(eviction.Replicas, dbpreview.EvictionPolicyAllow)
+			if pod.Name == cluster.Status.CurrentPrimary {
+				policy = cmp.Or(eviction.Primary, dbpreview.EvictionPolicyBlock)
+			}
+			desired = evictionAnnotations(policy)
+		}
+
+		annotations := maps.Clone(pod.Annotations)
+		if annotations == nil {
+			annotations = map[string]string{}
+		}
+		for _, key := range []string{safeToEvictAnnotation, doNotDisruptAnnotation} {
+			if value, ok := desired[key]; ok {
+				annotations[key] = value
+			} else if documentdb.Spec.InheritedMetadata == nil || documentdb.Spec.InheritedMetadata.Annotations[key] == "" {
+				delete(annotations, key)
+			}
+		}
+		if maps.Equal(annotations, pod.Annotations) {
+			continue
+		}
+		patch := client.MergeFrom(pod.DeepCopy())
+		pod.Annotations = annotations
+		if err := r.Patch(ctx, pod, patch); err != nil {
+			return fmt.Errorf("failed to annotate Pod %s: %w", pod.Name, err)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,123 @@
+// Copyright (c) Microsoft Corporation.
+// Licensed under the MIT License.
+
+package controller
+
+import (
+	"context"
+
+	cnpgv1 "github.com/cloudnative-pg/cloudnative-pg/api/v1"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	dbpreview "github.com/documentdb/documentdb-operator/api/preview"
+)
+
+var _ = Describe("Eviction annotations", func() {
+	const namespace = "default"
+
+	var (
+		ctx        context.Context
+		scheme     *runtime.Scheme
+		cluster    *cnpgv1.Cluster
+		documentdb *dbpreview.DocumentDB
+	)
+
+	BeforeEach(func() {
+		ctx = context.Background()
+		scheme = runtime.NewScheme()
+		Expect(corev1.AddToScheme(scheme)).To(Succeed())
+		cluster = &cnpgv1.Cluster{
+			ObjectMeta: metav1.ObjectMeta{Name: "db", Namespace: namespace},
+			Status:     cnpgv1.ClusterStatus{CurrentPrimary: "db-1"},
+		}
+		documentdb = &dbpreview.DocumentDB{
+			ObjectMeta: metav1.ObjectMeta{Name: "db", Namespace: namespace},
+			Spec:       dbpreview.DocumentDBSpec{Eviction: &dbpreview.EvictionConfiguration{}},
+		}
+	})
+
+	instancePod := func(name string, annotations map[string]string) *corev1.Pod {
+		return &corev1.Pod{ObjectMeta: metav1.ObjectMeta{
+			Name:        name,
+			Namespace:   namespace,
+			Labels:      map[string]string{"cnpg.io/cluster": "db", "cnpg.io/podRole": "instance"},
+			Annotations: annotations,
+		}}
+	}
+	annotationsOf := func(r *DocumentDBReconciler, name string) map[string]string {
+		pod := &corev1.Pod{}
+		Expect(r.Get(ctx, client.ObjectKey{Name: name, Namespace: namespace}, pod)).To(Succeed())
+		return pod.Annotations
+	}
+	newReconciler := func(objects ...client.Object) *DocumentDBReconciler {
+		return &DocumentDBReconciler{
+			Client: fake.NewClientBuilder().WithScheme(scheme).WithObjects(objects...).Build(),
+			Scheme: scheme,
+		}
+	}
+
+	It("blocks the eviction of the primary and allows the one of the replicas by default", func() {
+		r := newReconciler(instancePod("db-1", nil), instancePod("db-2", map[string]string{"other": "kept"}))
+
+		Expect(r.reconcileEvictionAnnotations(ctx, documentdb, cluster)).To(Succeed())
+		Expect(annotationsOf(r, "db-1")).To(Equal(map[string]string{
+			safeToEvictAnnotation:  "false",
+			doNotDisruptAnnotation: "true",
+		}))
+		Expect(annotationsOf(r, "db-2")).To(Equal(map[string]string{
+			safeToEvictAnnotation: "true",
+			"other":               "kept",
+		}))
+	})
+
+	It("moves the annotations to the new primary after a switchover", func() {
+		r := newReconciler(
+			instancePod("db-1", map[string]string{safeToEvictAnnotation: "false", doNotDisruptAnnotation: "true"}),
+			instancePod("db-2", map[string]string{safeToEvictAnnotation: "true"}),
+		)
+		cluster.Status.CurrentPrimary = "db-2"
+
+		Expect(r.reconcileEvictionAnnotations(ctx, documentdb, cluster)).To(Succeed())
+		Expect(annotationsOf(r, "db-1")).To(Equal(map[string]string{safeToEvictAnnotation: "true"}))
+		Expect(annotationsOf(r, "db-2")).To(Equal(map[string]string{
+			safeToEvictAnnotation:  "false",
+			doNotDisruptAnnotation: "true",
+		}))
+	})
+
+	It("follows the policies of spec.eviction", func() {
+		documentdb.Spec.Eviction = &dbpreview.EvictionConfiguration{
+			Primary:  dbpreview.EvictionPolicyAllow,
+			Replicas: dbpreview.EvictionPolicyBlock,
+		}
+		r := newReconciler(instancePod("db-1", nil), instancePod("db-2", nil))
+
+		Expect(r.reconcileEvictionAnnotations(ctx, documentdb, cluster)).To(Succeed())
+		Expect(annotationsOf(r, "db-1")).To(Equal(map[string]string{safeToEvictAnnotation: "true"}))
+		Expect(annotationsOf(r, "db-2")).To(Equal(map[string]string{
+			safeToEvictAnnotation:  "false",
+			doNotDisruptAnnotation: "true",
+		}))
+	})
+
+	It("removes the annotations when spec.eviction is unset, except those of spec.inheritedMetadata", func() {
+		documentdb.Spec.Eviction = nil
+		documentdb.Spec.InheritedMetadata = &dbpreview.InheritedMetadata{
+			Annotations: map[string]string{doNotDisruptAnnotation: "true"},
+		}
+		r := newReconciler(
+			instancePod("db-1", map[string]string{safeToEvictAnnotation: "false", doNotDisruptAnnotation: "true"}),
+			instancePod("db-2", map[string]string{safeToEvictAnnotation: "true"}),
+		)
+
+		Expect(r.reconcileEvictionAnnotations(ctx, documentdb, cluster)).To(Succeed())
+		Expect(annotationsOf(r, "db-1")).To(Equal(map[string]string{doNotDisruptAnnotation: "true"}))
+		Expect(annotationsOf(r, "db-2")).To(BeEmpty())
+	})
+})
@@ -0,0 +1,184 @@
+// Copyright (c) Microsoft Corporation.
+// Licensed under the MIT License.
+
+package controller
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	batchv1 "k8s.io/api/batch/v1"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/utils/ptr"
+	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
+	"sigs.k8s.io/controller-runtime/pkg/log"
+
+	dbpreview "github.com/documentdb/documentdb-operator/api/preview"
+	util "github.com/documentdb/documentdb-operator/internal/utils"
+)
+
+const (
+	// exportCronJobSuffix names the CronJob, <documentdb>-export, that runs
+	// the exports of spec.export.
+	exportCronJobSuffix = "-export"
+
+	// exportHistoryLimit is the number of finished export Jobs kept for
+	// their logs.
+	exportHistoryLimit = 3
+)
+
+// exportDumpScript writes each database to a gzipped archive in /export, or
+// every database to a single archive.
+const exportDumpScript = `set -eu
+if [ -n "$DATABASES" ]; then
+  for db in $DATABASES; do
+    echo "Exporting database $db"
+    mongodump --uri="$SOURCE_URI" --db="$db" --gzip --archive="/export/$db.archive.gz"
+  done
+else
+  echo "Exporting every database"
+  mongodump --uri="$SOURCE_URI" --gzip --archive=/export/all.archive.gz
+fi
+`
+
+// exportUploadScript uploads the archives to a folder of the destination
+// named after the UTC time of the upload.
+const exportUploadScript = `set -eu
+target="${DESTINATION_PATH%/}/$(date -u +%Y%m%dT%H%M%SZ)/"
+echo "Uploading to $target"
+aws s3 cp --recursive --no-progress /export "$target" ${ENDPOINT_URL:+--endpoint-url "$ENDPOINT_URL"}
+`
+
+func exportCronJobName(documentdbName string) string {
+	return documentdbName + exportCronJobSuffix
+}
+
+// reconcileExportCronJob creates or updates the CronJob of spec.export on the
+// primary cluster, and deletes it once spec.export is removed or the cluster
+// becomes a replica. The exports run under the ServiceAccount of the CNPG
+// Cluster so that, without a credentials Secret, they upload with the cloud
+// identity bound through spec.serviceAccount.
+func (r *DocumentDBReconciler) reconcileExportCronJob(ctx context.Context, documentdb *dbpreview.DocumentDB, replicationContext *util.ReplicationContext) error {
+	logger := log.FromContext(ctx)
+
+	cronJob := &batchv1.CronJob{}
+	cronJob.Name = exportCronJobName(documentdb.Name)
+	cronJob.Namespace = documentdb.Namespace
+
+	if documentdb.Spec.Export == nil || !replicationContext.IsPrimary() {
+		if err := r.Get(ctx, types.NamespacedName{Name: cronJob.Name, Namespace: cronJob.Namespace}, cronJob); err != nil {
+			if errors.IsNotFound(err) {
+				return nil
+			}
+			return fmt.Errorf("failed to get export CronJob: %w", err)
+		}
+		if !metav1.IsControlledBy(cronJob, documentdb) {
+			return nil
+		}
+		if err := r.Delete(ctx, cronJob); err != nil && !errors.IsNotFound(err) {
+			return fmt.Errorf("failed to delete export CronJob: %w", err)
+		}
+		logger.Info("Deleted export CronJob", "name", cronJob.Name)
+		return nil
+	}
+
+	result, err := controllerutil.CreateOrUpdate(ctx, r.Client, cronJob, func() error {
+		if err := controllerutil.SetControllerReference(documentdb, cronJob, r.Scheme); err != nil {
+			return fmt.Errorf("failed to set owner reference: %w", err)
+		}
+		cronJob.Labels = util.ChildLabels(documentdb, map[string]string{util.LABEL_DOCUMENTDB_NAME: documentdb.Name})
+		cronJob.Annotations = util.ChildAnnotations(documentdb, nil)
+		cronJob.Spec = exportCronJobSpec(documentdb, replicationContext.CNPGClusterName)
+		return nil
+	})
+	if err != nil {
+		return fmt.Errorf("failed to reconcile export CronJob %s: %w", cronJob.Name, err)
+	}
+	if result != controllerutil.OperationResultNone {
+		logger.Info("Export CronJob reconciled", "name", cronJob.Name, "operation", result)
+	}
+	return nil
+}
+
+// exportCronJobSpec returns the spec of the export CronJob of documentdb. The
+// archives are dumped to an emptyDir by an init container and uploaded by
+// the main container, so that the upload image needs no MongoDB tools.
+func exportCronJobSpec(documentdb *dbpreview.DocumentDB, serviceAccountName string) batchv1.CronJobSpec {
+	export := documentdb.Spec.Export
+	labels := util.ChildLabels(documentdb, map[string]string{util.LABEL_DOCUMENTDB_NAME: documentdb.Name})
+	securityContext := &corev1.SecurityContext{
+		AllowPrivilegeEscalation: ptr.To(false),
+		Capabilities:             &corev1.Capabilities{Drop: []corev1.Capability{"ALL"}},
+	}
+	exportVolume := corev1.VolumeMount{Name: "export", MountPath: "/export"}
+
+	uploadEnv := []corev1.EnvVar{
+		{Name: "DESTINATION_PATH", Value: export.Destination.DestinationPath},
+		{Name: "ENDPOINT_URL", Value: export.Destination.EndpointURL},
+		// The aws CLI writes its cache to the home directory.
+		{Name: "HOME", Value: "/tmp"},
+	}
+	var uploadEnvFrom []corev1.EnvFromSource
+	if secret := export.Destination.CredentialsSecret; secret != nil {
+		uploadEnvFrom = []corev1.EnvFromSource{{
+			SecretRef: &corev1.SecretEnvSource{LocalObjectReference: corev1.LocalObjectReference{Name: secret.Name}},
+		}}
+	}
+
+	return batchv1.CronJobSpec{
+		Schedule:                   export.Schedule,
+		Suspend:                    ptr.To(export.Suspend),
+		ConcurrencyPolicy:          batchv1.ForbidConcurrent,
+		SuccessfulJobsHistoryLimit: ptr.To(int32(exportHistoryLimit)),
+		FailedJobsHistoryLimit:     ptr.To(int32(exportHistoryLimit)),
+		JobTemplate: batchv1.JobTemplateSpec{
+			ObjectMeta: metav1.ObjectMeta{Labels: labels},
+			Spec: batchv1.JobSpec{
+				BackoffLimit: ptr.To(int32(2)),
+				Template: corev1.PodTemplateSpec{
+					ObjectMeta: metav1.ObjectMeta{Labels: labels},
+					Spec: corev1.PodSpec{
+						ServiceAccountName: serviceAccountName,
+						RestartPolicy:      corev1.RestartPolicyNever,
+						InitContainers: []corev1.Container{{
+							Name:            "dump",
+							Image:           util.GetMongoDBToolsImage(),
+							ImagePullPolicy: documentdb.Spec.ImagePullPolicy,
+							Command:         []string{"/bin/sh", "-c", exportDumpScript},
+							Env: []corev1.EnvVar{
+								{
+									Name: "SOURCE_URI",
+									ValueFrom: &corev1.EnvVarSource{SecretKeyRef: &corev1.SecretKeySelector{
+										LocalObjectReference: corev1.LocalObjectReference{Name: connectionSecretName(documentdb.Name)},
+										Key:                  connectionSecretURIKey,
+									}},
+								},
+								{Name: "DATABASES", Value: strings.Join(export.Databases, " ")},
+							},
+							VolumeMounts:    []corev1.VolumeMount{exportVolume},
+							SecurityContext: securityContext,
+						}},
+						Containers: []corev1.Container{{
+							Name:            "upload",
+							Image:           util.GetAWSCLIImage(),
+							ImagePullPolicy: documentdb.Spec.ImagePullPolicy,
+							Command:         []string{"/bin/sh", "-c", exportUploadScript},
+							Env:             uploadEnv,
+							EnvFrom:         uploadEnvFrom,
+							VolumeMounts:    []corev1.VolumeMount{exportVolume, {Name: "tmp", MountPath: "/tmp"}},
+							SecurityContext: securityContext,
+						}},
+						Volumes: []corev1.Volume{
+							{Name: "export", VolumeSource: corev1.VolumeSource{EmptyDir: &corev1.EmptyDirVolumeSource{}}},
+							{Name: "tmp", VolumeSource: corev1.VolumeSource{EmptyDir: &corev1.EmptyDirVolumeSource{}}},
+						},
+					},
+				},
+			},
+		},
+	}
+}
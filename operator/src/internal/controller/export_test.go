@@ -0,0 +1,130 @@
+// Copyright (c) Microsoft Corporation.
+// Licensed under the MIT License.
+
+package controller
+
+import (
+	"context"
+
+	cnpgv1 "github.com/cloudnative-pg/cloudnative-pg/api/v1"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	batchv1 "k8s.io/api/batch/v1"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	dbpreview "github.com/documentdb/documentdb-operator/api/preview"
+	util "github.com/documentdb/documentdb-operator/internal/utils"
+)
+
+var _ = Describe("Export CronJob", func() {
+	const namespace = "default"
+
+	var (
+		ctx                context.Context
+		reconciler         *DocumentDBReconciler
+		documentdb         *dbpreview.DocumentDB
+		replicationContext *util.ReplicationContext
+	)
+
+	BeforeEach(func() {
+		ctx = context.Background()
+		scheme := runtime.NewScheme()
+		Expect(dbpreview.AddToScheme(scheme)).To(Succeed())
+		Expect(batchv1.AddToScheme(scheme)).To(Succeed())
+
+		documentdb = &dbpreview.DocumentDB{
+			ObjectMeta: metav1.ObjectMeta{Name: "db", Namespace: namespace, UID: "uid"},
+			Spec: dbpreview.DocumentDBSpec{
+				Export: &dbpreview.ExportConfiguration{
+					Schedule:  "0 2 * * *",
+					Databases: []string{"orders"},
+					Destination: dbpreview.ExportDestination{
+						DestinationPath:   "s3://bucket/exports",
+						EndpointURL:       "http://minio:9000",
+						CredentialsSecret: &cnpgv1.LocalObjectReference{Name: "s3-credentials"},
+					},
+				},
+			},
+		}
+		reconciler = &DocumentDBReconciler{
+			Client: fake.NewClientBuilder().WithScheme(scheme).Build(),
+			Scheme: scheme,
+		}
+		var err error
+		replicationContext, err = util.GetReplicationContext(ctx, reconciler.Client, *documentdb)
+		Expect(err).ToNot(HaveOccurred())
+	})
+
+	getCronJob := func() (*batchv1.CronJob, error) {
+		cronJob := &batchv1.CronJob{}
+		err := reconciler.Get(ctx, client.ObjectKey{Name: exportCronJobName("db"), Namespace: namespace}, cronJob)
+		return cronJob, err
+	}
+
+	It("creates a CronJob that dumps and uploads the databases", func() {
+		Expect(reconciler.reconcileExportCronJob(ctx, documentdb, replicationContext)).To(Succeed())
+
+		cronJob, err := getCronJob()
+		Expect(err).ToNot(HaveOccurred())
+		Expect(cronJob.Spec.Schedule).To(Equal("0 2 * * *"))
+		Expect(cronJob.Spec.ConcurrencyPolicy).To(Equal(batchv1.ForbidConcurrent))
+		Expect(cronJob.OwnerReferences).To(HaveLen(1))
+
+		pod := cronJob.Spec.JobTemplate.Spec.Template.Spec
+		Expect(pod.ServiceAccountName).To(Equal("db"))
+		Expect(pod.InitContainers[0].Env).To(ContainElement(corev1.EnvVar{Name: "DATABASES", Value: "orders"}))
+		Expect(pod.Containers[0].Env).To(ContainElement(corev1.EnvVar{Name: "ENDPOINT_URL", Value: "http://minio:9000"}))
+		Expect(pod.Containers[0].EnvFrom[0].SecretRef.Name).To(Equal("s3-credentials"))
+	})
+
+	It("updates the CronJob when spec.export changes", func() {
+		Expect(reconciler.reconcileExportCronJob(ctx, documentdb, replicationContext)).To(Succeed())
+
+		documentdb.Spec.Export.Schedule = "0 3 * * *"
+		documentdb.Spec.Export.Suspend = true
+		Expect(reconciler.reconcileExportCronJob(ctx, documentdb, replicationContext)).To(Succeed())
+
+		cronJob, err := getCronJob()
+		Expect(err).ToNot(HaveOccurred())
+		Expect(cronJob.Spec.Schedule).To(Equal("0 3 * * *"))
+		Expect(*cronJob.Spec.Suspend).To(BeTrue())
+	})
+
+	It("pulls the images of the CronJob with spec.imagePullPolicy", func() {
+		documentdb.Spec.ImagePullPolicy = corev1.PullIfNotPresent
+		Expect(reconciler.reconcileExportCronJob(ctx, documentdb, replicationContext)).To(Succeed())
+
+		cronJob, err := getCronJob()
+		Expect(err).ToNot(HaveOccurred())
+		pod := cronJob.Spec.JobTemplate.Spec.Template.Spec
+		Expect(pod.InitContainers[0].ImagePullPolicy).To(Equal(corev1.PullIfNotPresent))
+		Expect(pod.Containers[0].ImagePullPolicy).To(Equal(corev1.PullIfNotPresent))
+	})
+
+	It("deletes the CronJob when spec.export is removed", func() {
+		Expect(reconciler.reconcileExportCronJob(ctx, documentdb, replicationContext)).To(Succeed())
+
+		documentdb.Spec.Export = nil
+		Expect(reconciler.reconcileExportCronJob(ctx, documentdb, replicationContext)).To(Succeed())
+
+		_, err := getCronJob()
+		Expect(apierrors.IsNotFound(err)).To(BeTrue())
+	})
+
+	It("does not delete a CronJob it does not own", func() {
+		Expect(reconciler.Create(ctx, &batchv1.CronJob{
+			ObjectMeta: metav1.ObjectMeta{Name: exportCronJobName("db"), Namespace: namespace},
+		})).To(Succeed())
+
+		documentdb.Spec.Export = nil
+		Expect(reconciler.reconcileExportCronJob(ctx, documentdb, replicationContext)).To(Succeed())
+
+		_, err := getCronJob()
+		Expect(err).ToNot(HaveOccurred())
+	})
+})
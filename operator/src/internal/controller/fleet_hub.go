@@ -0,0 +1,135 @@
+// Copyright (c) Microsoft Corporation.
+// Licensed under the MIT License.
+
+package controller
+
+import (
+	"context"
+	"fmt"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/equality"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
+	"sigs.k8s.io/controller-runtime/pkg/log"
+
+	dbpreview "github.com/documentdb/documentdb-operator/api/preview"
+	util "github.com/documentdb/documentdb-operator/internal/utils"
+)
+
+// reconcileFleetHub places documentdb on the member clusters of its
+// spec.clusterReplication, where the operator of each member runs its CNPG
+// Cluster, and records the progress in the Placed condition. The namespace is
+// placed by a ClusterResourcePlacement shared by the DocumentDBs in it, and
+// the DocumentDB by a ResourcePlacement it owns, which Fleet removes from the
+// members when the DocumentDB is deleted.
+func (r *DocumentDBReconciler) reconcileFleetHub(ctx context.Context, documentdb *dbpreview.DocumentDB) (ctrl.Result, error) {
+	logger := log.FromContext(ctx)
+
+	// The members hold the data and run the deletion checks, the hub has
+	// nothing to clean up.
+	if !documentdb.DeletionTimestamp.IsZero() {
+		if controllerutil.RemoveFinalizer(documentdb, documentDBFinalizer) {
+			if err := r.Update(ctx, documentdb); err != nil {
+				return ctrl.Result{}, err
+			}
+		}
+		return ctrl.Result{}, nil
+	}
+
+	condition := metav1.Condition{
+		Type:               dbpreview.ConditionPlaced,
+		Status:             metav1.ConditionFalse,
+		Reason:             dbpreview.ReasonNoMemberClusters,
+		Message:            "spec.clusterReplication lists no member cluster to place the DocumentDB on",
+		ObservedGeneration: documentdb.Generation,
+	}
+	if replication := documentdb.Spec.ClusterReplication; replication != nil && len(replication.ClusterList) > 0 {
+		if err := r.upsertPlacement(ctx, nil, util.ClusterResourcePlacementGVK, "", util.NamespacePlacementName(documentdb.Namespace),
+			util.NamespacePlacementSpec(documentdb.Namespace)); err != nil {
+			r.recordReconcileFailure(ctx, documentdb, err, "Failed to place the namespace on the member clusters")
+			return ctrl.Result{RequeueAfter: RequeueAfterShort}, nil
+		}
+		placement := newPlacement(util.ResourcePlacementGVK, documentdb.Namespace, documentdb.Name)
+		if err := r.upsertPlacement(ctx, documentdb, util.ResourcePlacementGVK, documentdb.Namespace, documentdb.Name,
+			util.DocumentDBPlacementSpec(documentdb)); err != nil {
+			r.recordReconcileFailure(ctx, documentdb, err, "Failed to place the DocumentDB on the member clusters")
+			return ctrl.Result{RequeueAfter: RequeueAfterShort}, nil
+		}
+		if err := r.Get(ctx, client.ObjectKeyFromObject(placement), placement); err != nil {
+			r.recordReconcileFailure(ctx, documentdb, err, "Failed to read the placement of the DocumentDB")
+			return ctrl.Result{RequeueAfter: RequeueAfterShort}, nil
+		}
+		condition.Reason = dbpreview.ReasonPlacementPending
+		available, message := util.PlacementAvailable(placement)
+		condition.Message = message
+		if available {
+			condition.Status = metav1.ConditionTrue
+			condition.Reason = dbpreview.ReasonPlacementAvailable
+			condition.Message = fmt.Sprintf("Placed on %d member clusters", len(replication.ClusterList))
+		}
+	}
+
+	changed := meta.SetStatusCondition(&documentdb.Status.Conditions, condition)
+	if documentdb.Status.ObservedGeneration != documentdb.Generation {
+		documentdb.Status.ObservedGeneration = documentdb.Generation
+		changed = true
+	}
+	if changed {
+		if condition.Reason == dbpreview.ReasonNoMemberClusters && r.Recorder != nil {
+			r.Recorder.Event(documentdb, corev1.EventTypeWarning, condition.Reason, condition.Message)
+		}
+		if err := r.Status().Update(ctx, documentdb); err != nil {
+			logger.Error(err, "Failed to update DocumentDB status")
+			return ctrl.Result{RequeueAfter: RequeueAfterShort}, nil
+		}
+	}
+	r.resetReconcileFailures(ctx, documentdb)
+
+	// Fleet reports the rollout in the status of the placement, which is not
+	// watched
+	if condition.Status != metav1.ConditionTrue {
+		return ctrl.Result{RequeueAfter: RequeueAfterLong}, nil
+	}
+	return ctrl.Result{RequeueAfter: util.GetDriftCheckInterval()}, nil
+}
+
+// upsertPlacement creates the placement of the given kind, or updates the
+// fields of its spec that differ from spec. The fields Fleet defaults are left
+// alone. A non-nil owner becomes its controller.
+func (r *DocumentDBReconciler) upsertPlacement(ctx context.Context, owner *dbpreview.DocumentDB, gvk schema.GroupVersionKind, namespace, name string, spec map[string]any) error {
+	placement := newPlacement(gvk, namespace, name)
+	_, err := controllerutil.CreateOrUpdate(ctx, r.Client, placement, func() error {
+		if owner != nil {
+			if err := controllerutil.SetControllerReference(owner, placement, r.Scheme); err != nil {
+				return err
+			}
+		}
+		for field, value := range spec {
+			if current, found, _ := unstructured.NestedFieldNoCopy(placement.Object, "spec", field); found && equality.Semantic.DeepEqual(current, value) {
+				continue
+			}
+			if err := unstructured.SetNestedField(placement.Object, value, "spec", field); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return fmt.Errorf("failed to upsert %s %s: %w", gvk.Kind, name, err)
+	}
+	return nil
+}
+
+func newPlacement(gvk schema.GroupVersionKind, namespace, name string) *unstructured.Unstructured {
+	placement := &unstructured.Unstructured{}
+	placement.SetGroupVersionKind(gvk)
+	placement.SetNamespace(namespace)
+	placement.SetName(name)
+	return placement
+}
@@ -0,0 +1,125 @@
+// Copyright (c) Microsoft Corporation.
+// Licensed under the MIT License.
+
+package controller
+
+import (
+	"context"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/client-go/tools/record"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
+
+	dbpreview "github.com/documentdb/documentdb-operator/api/preview"
+	util "github.com/documentdb/documentdb-operator/internal/utils"
+)
+
+var _ = Describe("Fleet hub", func() {
+	const namespace = "documentdb-ns"
+
+	var (
+		ctx        context.Context
+		scheme     *runtime.Scheme
+		documentdb *dbpreview.DocumentDB
+		reconciler *DocumentDBReconciler
+	)
+
+	BeforeEach(func() {
+		ctx = context.Background()
+		scheme = runtime.NewScheme()
+		Expect(dbpreview.AddToScheme(scheme)).To(Succeed())
+		documentdb = &dbpreview.DocumentDB{
+			ObjectMeta: metav1.ObjectMeta{Name: "db", Namespace: namespace, Generation: 1},
+			Spec: dbpreview.DocumentDBSpec{
+				ClusterReplication: &dbpreview.ClusterReplication{
+					Primary:     "east",
+					ClusterList: []dbpreview.MemberCluster{{Name: "east"}, {Name: "west"}},
+				},
+			},
+		}
+	})
+
+	build := func() {
+		reconciler = &DocumentDBReconciler{
+			Client:   fake.NewClientBuilder().WithScheme(scheme).WithObjects(documentdb).WithStatusSubresource(documentdb).Build(),
+			Scheme:   scheme,
+			Recorder: record.NewFakeRecorder(10),
+		}
+	}
+	placement := func(gvk schema.GroupVersionKind, namespace, name string) *unstructured.Unstructured {
+		obj := &unstructured.Unstructured{}
+		obj.SetGroupVersionKind(gvk)
+		Expect(reconciler.Get(ctx, client.ObjectKey{Namespace: namespace, Name: name}, obj)).To(Succeed())
+		return obj
+	}
+	placed := func() *metav1.Condition {
+		current := &dbpreview.DocumentDB{}
+		Expect(reconciler.Get(ctx, client.ObjectKeyFromObject(documentdb), current)).To(Succeed())
+		return meta.FindStatusCondition(current.Status.Conditions, dbpreview.ConditionPlaced)
+	}
+
+	It("places the namespace and the DocumentDB on the member clusters", func() {
+		build()
+		_, err := reconciler.reconcileFleetHub(ctx, documentdb)
+		Expect(err).ToNot(HaveOccurred())
+
+		namespacePlacement := placement(util.ClusterResourcePlacementGVK, "", "documentdb-"+namespace)
+		placementType, _, _ := unstructured.NestedString(namespacePlacement.Object, "spec", "policy", "placementType")
+		Expect(placementType).To(Equal("PickAll"))
+
+		documentdbPlacement := placement(util.ResourcePlacementGVK, namespace, "db")
+		names, _, _ := unstructured.NestedStringSlice(documentdbPlacement.Object, "spec", "policy", "clusterNames")
+		Expect(names).To(Equal([]string{"east", "west"}))
+		Expect(metav1.IsControlledBy(documentdbPlacement, documentdb)).To(BeTrue())
+
+		Expect(placed().Status).To(Equal(metav1.ConditionFalse))
+		Expect(placed().Reason).To(Equal(dbpreview.ReasonPlacementPending))
+	})
+
+	It("reports the DocumentDB placed once Fleet finds it available", func() {
+		build()
+		_, err := reconciler.reconcileFleetHub(ctx, documentdb)
+		Expect(err).ToNot(HaveOccurred())
+
+		documentdbPlacement := placement(util.ResourcePlacementGVK, namespace, "db")
+		Expect(unstructured.SetNestedSlice(documentdbPlacement.Object, []any{
+			map[string]any{"type": "ResourcePlacementAvailable", "status": "True"},
+		}, "status", "conditions")).To(Succeed())
+		Expect(reconciler.Update(ctx, documentdbPlacement)).To(Succeed())
+
+		Expect(reconciler.Get(ctx, client.ObjectKeyFromObject(documentdb), documentdb)).To(Succeed())
+		_, err = reconciler.reconcileFleetHub(ctx, documentdb)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(placed().Status).To(Equal(metav1.ConditionTrue))
+		Expect(placed().Reason).To(Equal(dbpreview.ReasonPlacementAvailable))
+	})
+
+	It("reports a DocumentDB without member clusters", func() {
+		documentdb.Spec.ClusterReplication = nil
+		build()
+		_, err := reconciler.reconcileFleetHub(ctx, documentdb)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(placed().Reason).To(Equal(dbpreview.ReasonNoMemberClusters))
+	})
+
+	It("releases a deleted DocumentDB right away", func() {
+		controllerutil.AddFinalizer(documentdb, documentDBFinalizer)
+		build()
+		Expect(reconciler.Delete(ctx, documentdb)).To(Succeed())
+		Expect(reconciler.Get(ctx, client.ObjectKeyFromObject(documentdb), documentdb)).To(Succeed())
+
+		_, err := reconciler.reconcileFleetHub(ctx, documentdb)
+		Expect(err).ToNot(HaveOccurred())
+		err = reconciler.Get(ctx, client.ObjectKeyFromObject(documentdb), &dbpreview.DocumentDB{})
+		Expect(client.IgnoreNotFound(err)).To(Succeed())
+		Expect(err).To(HaveOccurred())
+	})
+})
@@ -0,0 +1,99 @@
+// Copyright (c) Microsoft Corporation.
+// Licensed under the MIT License.
+
+package controller
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"sync"
+	"time"
+
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/cache"
+	"sigs.k8s.io/controller-runtime/pkg/healthz"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+)
+
+// cacheSyncCheckTimeout bounds how long a readiness probe waits for the
+// informer caches before reporting them as not synced.
+const cacheSyncCheckTimeout = time.Second
+
+// reconcileWatchdog records when each in-flight reconcile started, so that
+// the liveness probe can fail when a reconcile never returns.
+type reconcileWatchdog struct {
+	mu       sync.Mutex
+	inFlight map[string]time.Time
+	now      func() time.Time
+}
+
+// watchdog tracks the reconciles of every controller of the operator.
+var watchdog = newReconcileWatchdog()
+
+func newReconcileWatchdog() *reconcileWatchdog {
+	return &reconcileWatchdog{inFlight: map[string]time.Time{}, now: time.Now}
+}
+
+// track records the start of a reconcile of req by controller and returns the
+// function recording its end. A controller never reconciles the same request
+// concurrently, so the pair identifies the reconcile.
+func (w *reconcileWatchdog) track(controller string, req ctrl.Request) func() {
+	key := controller + "/" + req.String()
+	w.mu.Lock()
+	w.inFlight[key] = w.now()
+	w.mu.Unlock()
+	return func() {
+		w.mu.Lock()
+		delete(w.inFlight, key)
+		w.mu.Unlock()
+	}
+}
+
+// checker returns a healthz check that fails while a reconcile has been
+// running for longer than threshold. A zero threshold disables the check.
+func (w *reconcileWatchdog) checker(threshold time.Duration) healthz.Checker {
+	return func(_ *http.Request) error {
+		if threshold <= 0 {
+			return nil
+		}
+		w.mu.Lock()
+		defer w.mu.Unlock()
+		for key, started := range w.inFlight {
+			if running := w.now().Sub(started); running > threshold {
+				return fmt.Errorf("reconcile %s has been running for %s, longer than %s", key, running.Round(time.Second), threshold)
+			}
+		}
+		return nil
+	}
+}
+
+// trackReconciles wraps r so that its reconciles are watched by the liveness
+// check returned by ReconcileHealthChecker.
+func trackReconciles(controller string, r reconcile.Reconciler) reconcile.Reconciler {
+	return reconcile.Func(func(ctx context.Context, req ctrl.Request) (ctrl.Result, error) {
+		defer watchdog.track(controller, req)()
+		return r.Reconcile(ctx, req)
+	})
+}
+
+// ReconcileHealthChecker returns a liveness check that fails while a reconcile
+// of any controller has been running for longer than threshold, so that the
+// kubelet restarts an operator whose reconciles are wedged. A zero threshold
+// disables the check.
+func ReconcileHealthChecker(threshold time.Duration) healthz.Checker {
+	return watchdog.checker(threshold)
+}
+
+// CacheSyncChecker returns a readiness check that fails until the informer
+// caches the controllers read from have synced.
+func CacheSyncChecker(c cache.Cache) healthz.Checker {
+	return func(req *http.Request) error {
+		ctx, cancel := context.WithTimeout(req.Context(), cacheSyncCheckTimeout)
+		defer cancel()
+		if !c.WaitForCacheSync(ctx) {
+			return fmt.Errorf("informer caches are not synced")
+		}
+		return nil
+	}
+}
@@ -0,0 +1,269 @@
+// Copyright (c) Microsoft Corporation.
+// Licensed under the MIT License.
+
+package controller
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+
+	cnpgv1 "github.com/cloudnative-pg/cloudnative-pg/api/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/event"
+	"sigs.k8s.io/controller-runtime/pkg/log"
+
+	dbpreview "github.com/documentdb/documentdb-operator/api/preview"
+	util "github.com/documentdb/documentdb-operator/internal/utils"
+)
+
+const (
+	// replicationRoleGrantedQuery returns a row once streaming_replica may
+	// use the DocumentDB admin role, which physical replication needs.
+	replicationRoleGrantedQuery = "SELECT 1 FROM pg_roles WHERE rolname = 'streaming_replica' AND pg_has_role('streaming_replica', 'documentdb_admin_role', 'USAGE');"
+
+	// healthPollTimeout bounds the queries of a poll of one cluster.
+	healthPollTimeout = 30 * time.Second
+)
+
+// clusterProbe is what a poll read from the primary of a CNPG cluster.
+type clusterProbe struct {
+	// primary is the instance the probe was read from.
+	primary string
+	// versionsFound is false while the documentdb extension is not available.
+	versionsFound    bool
+	defaultVersion   string
+	installedVersion string
+	// replicationRoleGranted reports whether replicationRoleGrantedQuery
+	// returned a row.
+	replicationRoleGranted bool
+}
+
+// probeCluster reads a clusterProbe from the primary of cluster through query.
+func probeCluster(ctx context.Context, cluster *cnpgv1.Cluster, query func(ctx context.Context, cluster *cnpgv1.Cluster, sqlCommand string) (string, error)) (*clusterProbe, error) {
+	probe := &clusterProbe{primary: cluster.Status.CurrentPrimary}
+	output, err := query(ctx, cluster, extensionVersionsQuery)
+	if err != nil {
+		return nil, fmt.Errorf("failed to check documentdb extension versions: %w", err)
+	}
+	probe.defaultVersion, probe.installedVersion, probe.versionsFound = extensionVersionsFromOutput(output)
+
+	output, err = query(ctx, cluster, replicationRoleGrantedQuery)
+	if err != nil {
+		return nil, fmt.Errorf("failed to check if permissions already granted: %w", err)
+	}
+	probe.replicationRoleGranted = strings.Contains(output, "(1 row)")
+	return probe, nil
+}
+
+// clusterHealthPoller reads a clusterProbe from the primary of each CNPG
+// cluster a reconcile looked up, every DOCUMENTDB_HEALTH_POLL_INTERVAL, so
+// that reconciles do not run psql themselves. A DocumentDB is re-queued when
+// the probe of its cluster changes. It runs as a leader-election Runnable of
+// the manager.
+type clusterHealthPoller struct {
+	client client.Reader
+	query  func(ctx context.Context, cluster *cnpgv1.Cluster, sqlCommand string) (string, error)
+	// events re-queues the DocumentDBs whose probe changed.
+	events chan event.GenericEvent
+	// wake asks for a poll of the clusters that have no probe yet.
+	wake chan struct{}
+	now  func() time.Time
+
+	mu       sync.Mutex
+	clusters map[types.NamespacedName]*polledCluster
+}
+
+type polledCluster struct {
+	documentdb types.NamespacedName
+	probe      *clusterProbe
+	polledAt   time.Time
+}
+
+func newClusterHealthPoller(reader client.Reader, query func(ctx context.Context, cluster *cnpgv1.Cluster, sqlCommand string) (string, error)) *clusterHealthPoller {
+	return &clusterHealthPoller{
+		client:   reader,
+		query:    query,
+		events:   make(chan event.GenericEvent, 16),
+		wake:     make(chan struct{}, 1),
+		now:      time.Now,
+		clusters: map[types.NamespacedName]*polledCluster{},
+	}
+}
+
+// enabled reports whether reconciles read the probes of the poller. A nil
+// poller, as in tests, is never enabled.
+func (p *clusterHealthPoller) enabled() bool {
+	return p != nil && util.GetHealthPollInterval() > 0
+}
+
+// lookup returns the probe of cluster, nil until one was read from its
+// current primary. It starts polling cluster on behalf of documentdb.
+func (p *clusterHealthPoller) lookup(documentdb *dbpreview.DocumentDB, cluster *cnpgv1.Cluster) *clusterProbe {
+	key := client.ObjectKeyFromObject(cluster)
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	polled, found := p.clusters[key]
+	if !found {
+		p.clusters[key] = &polledCluster{documentdb: client.ObjectKeyFromObject(documentdb)}
+		p.wakeUp()
+		return nil
+	}
+	if polled.probe == nil || polled.probe.primary != cluster.Status.CurrentPrimary {
+		return nil
+	}
+	return polled.probe
+}
+
+// invalidate drops the probe of cluster after a statement that changes it,
+// and polls it again.
+func (p *clusterHealthPoller) invalidate(cluster *cnpgv1.Cluster) {
+	if p == nil {
+		return
+	}
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	if polled, found := p.clusters[client.ObjectKeyFromObject(cluster)]; found {
+		polled.probe = nil
+		p.wakeUp()
+	}
+}
+
+// wakeUp must be called with mu held.
+func (p *clusterHealthPoller) wakeUp() {
+	select {
+	case p.wake <- struct{}{}:
+	default:
+	}
+}
+
+// Start implements manager.Runnable. It polls the clusters until ctx is
+// cancelled.
+func (p *clusterHealthPoller) Start(ctx context.Context) error {
+	for {
+		interval := util.GetHealthPollInterval()
+		wait := interval
+		if interval > 0 {
+			p.pollDue(ctx, interval)
+		} else {
+			// Turned off: look at the setting again in a while.
+			wait = time.Minute
+		}
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-p.wake:
+		case <-time.After(wait):
+		}
+	}
+}
+
+// pollDue polls the clusters without a probe or whose probe is older than
+// interval, and re-queues the DocumentDBs whose probe changed.
+func (p *clusterHealthPoller) pollDue(ctx context.Context, interval time.Duration) {
+	logger := log.FromContext(ctx)
+	now := p.now()
+	var due []types.NamespacedName
+	p.mu.Lock()
+	for key, polled := range p.clusters {
+		if polled.probe == nil || now.Sub(polled.polledAt) >= interval {
+			due = append(due, key)
+		}
+	}
+	p.mu.Unlock()
+
+	for _, key := range due {
+		if ctx.Err() != nil {
+			return
+		}
+		cluster := &cnpgv1.Cluster{}
+		if err := p.client.Get(ctx, key, cluster); err != nil {
+			if errors.IsNotFound(err) {
+				p.mu.Lock()
+				delete(p.clusters, key)
+				p.mu.Unlock()
+			} else {
+				logger.Error(err, "Failed to get the CNPG Cluster to poll", "cluster", key)
+			}
+			continue
+		}
+		// A restarting primary cannot answer: poll it once it is healthy.
+		if !slices.Contains(cluster.Status.InstancesStatus[cnpgv1.PodHealthy], cluster.Status.CurrentPrimary) {
+			continue
+		}
+
+		pollCtx, cancel := context.WithTimeout(ctx, healthPollTimeout)
+		probe, err := probeCluster(pollCtx, cluster, p.query)
+		cancel()
+
+		p.mu.Lock()
+		polled, found := p.clusters[key]
+		if !found {
+			p.mu.Unlock()
+			continue
+		}
+		polled.polledAt = p.now()
+		if err != nil {
+			p.mu.Unlock()
+			logger.Error(err, "Failed to poll the primary of the CNPG Cluster", "cluster", key)
+			continue
+		}
+		changed := polled.probe == nil || *polled.probe != *probe
+		polled.probe = probe
+		documentdb := polled.documentdb
+		p.mu.Unlock()
+
+		if changed {
+			select {
+			case p.events <- event.GenericEvent{Object: &dbpreview.DocumentDB{
+				ObjectMeta: metav1.ObjectMeta{Name: documentdb.Name, Namespace: documentdb.Namespace},
+			}}:
+			case <-ctx.Done():
+				return
+			}
+		}
+	}
+}
+
+// extensionVersions returns the versions of the documentdb extension on the
+// primary of cluster, as last polled, or queried now when the poller is not
+// enabled. found is false while the extension is not available or not polled.
+func (r *DocumentDBReconciler) extensionVersions(ctx context.Context, documentdb *dbpreview.DocumentDB, cluster *cnpgv1.Cluster) (defaultVersion, installedVersion string, found bool, err error) {
+	if r.healthPoller.enabled() {
+		probe := r.healthPoller.lookup(documentdb, cluster)
+		if probe == nil {
+			return "", "", false, nil
+		}
+		return probe.defaultVersion, probe.installedVersion, probe.versionsFound, nil
+	}
+	output, err := r.querySQL(ctx, cluster, extensionVersionsQuery)
+	if err != nil {
+		return "", "", false, fmt.Errorf("failed to check documentdb extension versions: %w", err)
+	}
+	defaultVersion, installedVersion, found = extensionVersionsFromOutput(output)
+	return defaultVersion, installedVersion, found, nil
+}
+
+// replicationRoleGranted reports whether streaming_replica was granted the
+// DocumentDB admin role on the primary of cluster, as last polled, or queried
+// now when the poller is not enabled. known is false until it was polled.
+func (r *DocumentDBReconciler) replicationRoleGranted(ctx context.Context, documentdb *dbpreview.DocumentDB, cluster *cnpgv1.Cluster) (granted, known bool, err error) {
+	if r.healthPoller.enabled() {
+		probe := r.healthPoller.lookup(documentdb, cluster)
+		if probe == nil {
+			return false, false, nil
+		}
+		return probe.replicationRoleGranted, true, nil
+	}
+	output, err := r.querySQL(ctx, cluster, replicationRoleGrantedQuery)
+	if err != nil {
+		return false, false, fmt.Errorf("failed to check if permissions already granted: %w", err)
+	}
+	return strings.Contains(output, "(1 row)"), true, nil
+}
@@ -0,0 +1,149 @@
+// Copyright (c) Microsoft Corporation.
+// Licensed under the MIT License.
+
+package controller
+
+import (
+	"context"
+	"strings"
+	"time"
+
+	cnpgv1 "github.com/cloudnative-pg/cloudnative-pg/api/v1"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+	"sigs.k8s.io/controller-runtime/pkg/event"
+
+	dbpreview "github.com/documentdb/documentdb-operator/api/preview"
+	util "github.com/documentdb/documentdb-operator/internal/utils"
+)
+
+var _ = Describe("clusterHealthPoller", func() {
+	const namespace = "documentdb-ns"
+
+	var (
+		ctx        context.Context
+		now        time.Time
+		queries    []string
+		granted    bool
+		documentdb *dbpreview.DocumentDB
+		cluster    *cnpgv1.Cluster
+		poller     *clusterHealthPoller
+	)
+
+	BeforeEach(func() {
+		ctx = context.Background()
+		now = time.Now()
+		queries = nil
+		granted = false
+		documentdb = &dbpreview.DocumentDB{ObjectMeta: metav1.ObjectMeta{Name: "db", Namespace: namespace}}
+		cluster = &cnpgv1.Cluster{
+			ObjectMeta: metav1.ObjectMeta{Name: "db", Namespace: namespace},
+			Status: cnpgv1.ClusterStatus{
+				CurrentPrimary:  "db-1",
+				InstancesStatus: map[cnpgv1.PodStatus][]string{cnpgv1.PodHealthy: {"db-1"}},
+			},
+		}
+		scheme := runtime.NewScheme()
+		Expect(cnpgv1.AddToScheme(scheme)).To(Succeed())
+		reader := fake.NewClientBuilder().WithScheme(scheme).WithObjects(cluster).WithStatusSubresource(cluster).Build()
+		poller = newClusterHealthPoller(reader, func(_ context.Context, _ *cnpgv1.Cluster, sql string) (string, error) {
+			queries = append(queries, sql)
+			if strings.Contains(sql, "pg_available_extensions") {
+				return `{"default_version": "0.110-0", "installed_version": "0.109-0"}` + "\n(1 row)", nil
+			}
+			if granted {
+				return "(1 row)", nil
+			}
+			return "(0 rows)", nil
+		})
+		poller.now = func() time.Time { return now }
+	})
+
+	It("polls a cluster once a reconcile looked it up and re-queues its DocumentDB", func() {
+		Expect(poller.lookup(documentdb, cluster)).To(BeNil())
+		Expect(poller.wake).To(HaveLen(1))
+
+		poller.pollDue(ctx, time.Minute)
+		Expect(queries).To(HaveLen(2))
+		var requeued event.GenericEvent
+		Expect(poller.events).To(Receive(&requeued))
+		Expect(client.ObjectKeyFromObject(requeued.Object)).To(Equal(client.ObjectKeyFromObject(documentdb)))
+		probe := poller.lookup(documentdb, cluster)
+		Expect(probe).NotTo(BeNil())
+		Expect(probe.versionsFound).To(BeTrue())
+		Expect(probe.defaultVersion).To(Equal("0.110-0"))
+		Expect(probe.installedVersion).To(Equal("0.109-0"))
+		Expect(probe.replicationRoleGranted).To(BeFalse())
+	})
+
+	It("polls again after the interval and re-queues only on a change", func() {
+		poller.lookup(documentdb, cluster)
+		poller.pollDue(ctx, time.Minute)
+		Expect(poller.events).To(Receive())
+
+		poller.pollDue(ctx, time.Minute)
+		Expect(queries).To(HaveLen(2))
+
+		now = now.Add(time.Minute)
+		poller.pollDue(ctx, time.Minute)
+		Expect(queries).To(HaveLen(4))
+		Expect(poller.events).NotTo(Receive())
+
+		granted = true
+		poller.invalidate(cluster)
+		Expect(poller.lookup(documentdb, cluster)).To(BeNil())
+		poller.pollDue(ctx, time.Minute)
+		Expect(poller.events).To(Receive())
+		Expect(poller.lookup(documentdb, cluster).replicationRoleGranted).To(BeTrue())
+	})
+
+	It("ignores the probe of a former primary", func() {
+		poller.lookup(documentdb, cluster)
+		poller.pollDue(ctx, time.Minute)
+
+		cluster.Status.CurrentPrimary = "db-2"
+		Expect(poller.lookup(documentdb, cluster)).To(BeNil())
+	})
+
+	It("forgets a cluster that was deleted", func() {
+		deleted := cluster.DeepCopy()
+		deleted.Name = "deleted"
+		poller.lookup(documentdb, deleted)
+		poller.pollDue(ctx, time.Minute)
+		Expect(queries).To(BeEmpty())
+		Expect(poller.clusters).To(BeEmpty())
+	})
+
+	It("lets reconciles wait for the first poll", func() {
+		reconciler := &DocumentDBReconciler{healthPoller: poller}
+		_, known, err := reconciler.replicationRoleGranted(ctx, documentdb, cluster)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(known).To(BeFalse())
+		_, _, found, err := reconciler.extensionVersions(ctx, documentdb, cluster)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(found).To(BeFalse())
+		Expect(queries).To(BeEmpty())
+
+		poller.pollDue(ctx, time.Minute)
+		granted, known, err := reconciler.replicationRoleGranted(ctx, documentdb, cluster)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(known).To(BeTrue())
+		Expect(granted).To(BeFalse())
+	})
+
+	It("is bypassed while turned off", func() {
+		util.SetOperatorSettings(map[string]string{util.HEALTH_POLL_INTERVAL_ENV: "0"})
+		DeferCleanup(util.SetOperatorSettings, map[string]string(nil))
+		reconciler := &DocumentDBReconciler{healthPoller: poller, SQLExecutor: poller.query}
+
+		defaultVersion, _, found, err := reconciler.extensionVersions(ctx, documentdb, cluster)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(found).To(BeTrue())
+		Expect(defaultVersion).To(Equal("0.110-0"))
+		Expect(poller.clusters).To(BeEmpty())
+	})
+})
@@ -0,0 +1,64 @@
+// Copyright (c) Microsoft Corporation.
+// Licensed under the MIT License.
+
+package controller
+
+import (
+	"context"
+	"net/http"
+	"time"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	"k8s.io/apimachinery/pkg/types"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+)
+
+var _ = Describe("Reconcile watchdog", func() {
+	var (
+		w   *reconcileWatchdog
+		now time.Time
+		req = ctrl.Request{NamespacedName: types.NamespacedName{Namespace: "default", Name: "db"}}
+	)
+
+	BeforeEach(func() {
+		now = time.Now()
+		w = newReconcileWatchdog()
+		w.now = func() time.Time { return now }
+	})
+
+	It("fails while a reconcile runs longer than the threshold", func() {
+		check := w.checker(time.Minute)
+		done := w.track("documentdb-controller", req)
+		Expect(check(&http.Request{})).To(Succeed())
+
+		now = now.Add(2 * time.Minute)
+		err := check(&http.Request{})
+		Expect(err).To(HaveOccurred())
+		Expect(err.Error()).To(ContainSubstring("documentdb-controller/default/db"))
+
+		done()
+		Expect(check(&http.Request{})).To(Succeed())
+	})
+
+	It("never fails with a zero threshold", func() {
+		w.track("documentdb-controller", req)
+		now = now.Add(24 * time.Hour)
+		Expect(w.checker(0)(&http.Request{})).To(Succeed())
+	})
+
+	It("tracks the reconciles of a wrapped reconciler", func() {
+		var inFlight int
+		r := trackReconciles("test-controller", reconcile.Func(func(context.Context, ctrl.Request) (ctrl.Result, error) {
+			watchdog.mu.Lock()
+			inFlight = len(watchdog.inFlight)
+			watchdog.mu.Unlock()
+			return ctrl.Result{}, nil
+		}))
+		_, err := r.Reconcile(context.Background(), req)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(inFlight).To(Equal(1))
+		Expect(watchdog.inFlight).To(BeEmpty())
+	})
+})
@@ -0,0 +1,52 @@
+// Copyright (c) Microsoft Corporation.
+// Licensed under the MIT License.
+
+package controller
+
+import (
+	"context"
+	"time"
+
+	"sigs.k8s.io/controller-runtime/pkg/log"
+
+	dbpreview "github.com/documentdb/documentdb-operator/api/preview"
+)
+
+// hostnameLookupTimeout bounds the resolution of spec.exposeViaService.hostname,
+// which runs within a reconcile.
+const hostnameLookupTimeout = 5 * time.Second
+
+// reconcileHostname reports spec.exposeViaService.hostname in status.hostname
+// once it resolves, and returns the host the clients should connect to: the
+// hostname, or serviceHost until then. A hostname is resolved until it first
+// succeeds, so that a DNS outage does not change the connection string back.
+// It also returns whether status.hostname changed.
+func (r *DocumentDBReconciler) reconcileHostname(ctx context.Context, documentdb *dbpreview.DocumentDB, serviceHost string) (string, bool) {
+	hostname := documentdb.Spec.ExposeViaService.Hostname
+	if hostname == "" || serviceHost == "" {
+		changed := documentdb.Status.Hostname != ""
+		documentdb.Status.Hostname = ""
+		return serviceHost, changed
+	}
+	if documentdb.Status.Hostname == hostname {
+		return hostname, false
+	}
+
+	lookupCtx, cancel := context.WithTimeout(ctx, hostnameLookupTimeout)
+	defer cancel()
+	if addresses, err := r.HostResolver(lookupCtx, hostname); err != nil || len(addresses) == 0 {
+		log.FromContext(ctx).V(1).Info("Hostname does not resolve yet", "hostname", hostname, "error", err)
+		changed := documentdb.Status.Hostname != ""
+		documentdb.Status.Hostname = ""
+		return serviceHost, changed
+	}
+	documentdb.Status.Hostname = hostname
+	return hostname, true
+}
+
+// hostnamePending reports whether spec.exposeViaService.hostname has yet to
+// resolve.
+func hostnamePending(documentdb *dbpreview.DocumentDB) bool {
+	hostname := documentdb.Spec.ExposeViaService.Hostname
+	return hostname != "" && documentdb.Status.Hostname != hostname
+}
@@ -0,0 +1,98 @@
+// Copyright (c) Microsoft Corporation.
+// Licensed under the MIT License.
+
+package controller
+
+import (
+	"context"
+	"errors"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	dbpreview "github.com/documentdb/documentdb-operator/api/preview"
+)
+
+var _ = Describe("Hostname", func() {
+	const serviceIP = "10.0.0.1"
+
+	var (
+		ctx        context.Context
+		reconciler *DocumentDBReconciler
+		documentdb *dbpreview.DocumentDB
+		lookups    []string
+		addresses  []string
+		lookupErr  error
+	)
+
+	BeforeEach(func() {
+		ctx = context.Background()
+		lookups = nil
+		addresses = []string{"20.1.2.3"}
+		lookupErr = nil
+		reconciler = &DocumentDBReconciler{
+			HostResolver: func(_ context.Context, host string) ([]string, error) {
+				lookups = append(lookups, host)
+				return addresses, lookupErr
+			},
+		}
+		documentdb = &dbpreview.DocumentDB{
+			ObjectMeta: metav1.ObjectMeta{Name: "db", Namespace: "default"},
+			Spec: dbpreview.DocumentDBSpec{
+				ExposeViaService: dbpreview.ExposeViaService{ServiceType: "LoadBalancer", Hostname: "db.example.com"},
+			},
+		}
+	})
+
+	It("connects through the address of the Service when no hostname is set", func() {
+		documentdb.Spec.ExposeViaService.Hostname = ""
+		host, changed := reconciler.reconcileHostname(ctx, documentdb, serviceIP)
+		Expect(host).To(Equal(serviceIP))
+		Expect(changed).To(BeFalse())
+		Expect(lookups).To(BeEmpty())
+		Expect(hostnamePending(documentdb)).To(BeFalse())
+	})
+
+	It("keeps the address of the Service until the hostname resolves", func() {
+		lookupErr = errors.New("no such host")
+		host, changed := reconciler.reconcileHostname(ctx, documentdb, serviceIP)
+		Expect(host).To(Equal(serviceIP))
+		Expect(changed).To(BeFalse())
+		Expect(documentdb.Status.Hostname).To(BeEmpty())
+		Expect(hostnamePending(documentdb)).To(BeTrue())
+	})
+
+	It("switches to the hostname once it resolves and stops resolving it", func() {
+		host, changed := reconciler.reconcileHostname(ctx, documentdb, serviceIP)
+		Expect(host).To(Equal("db.example.com"))
+		Expect(changed).To(BeTrue())
+		Expect(documentdb.Status.Hostname).To(Equal("db.example.com"))
+		Expect(hostnamePending(documentdb)).To(BeFalse())
+
+		lookupErr = errors.New("no such host")
+		host, changed = reconciler.reconcileHostname(ctx, documentdb, serviceIP)
+		Expect(host).To(Equal("db.example.com"))
+		Expect(changed).To(BeFalse())
+		Expect(lookups).To(HaveLen(1))
+	})
+
+	It("resolves a new hostname again and falls back to the Service meanwhile", func() {
+		documentdb.Status.Hostname = "old.example.com"
+		addresses = nil
+		host, changed := reconciler.reconcileHostname(ctx, documentdb, serviceIP)
+		Expect(host).To(Equal(serviceIP))
+		Expect(changed).To(BeTrue())
+		Expect(documentdb.Status.Hostname).To(BeEmpty())
+		Expect(lookups).To(Equal([]string{"db.example.com"}))
+	})
+
+	It("clears the hostname when it is removed", func() {
+		documentdb.Status.Hostname = "db.example.com"
+		documentdb.Spec.ExposeViaService.Hostname = ""
+		host, changed := reconciler.reconcileHostname(ctx, documentdb, serviceIP)
+		Expect(host).To(Equal(serviceIP))
+		Expect(changed).To(BeTrue())
+		Expect(documentdb.Status.Hostname).To(BeEmpty())
+	})
+})
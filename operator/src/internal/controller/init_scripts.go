@@ -0,0 +1,237 @@
+// Copyright (c) Microsoft Corporation.
+// Licensed under the MIT License.
+
+package controller
+
+import (
+	"context"
+	"fmt"
+
+	cnpgv1 "github.com/cloudnative-pg/cloudnative-pg/api/v1"
+	batchv1 "k8s.io/api/batch/v1"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/utils/ptr"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
+	"sigs.k8s.io/controller-runtime/pkg/log"
+
+	dbpreview "github.com/documentdb/documentdb-operator/api/preview"
+	util "github.com/documentdb/documentdb-operator/internal/utils"
+)
+
+const (
+	// initScriptJobSuffix names the Jobs, <documentdb>-init-<index>, that run
+	// the JavaScript init scripts.
+	initScriptJobSuffix = "-init-"
+
+	// initScriptBackoffLimit is the number of retries of a failing init
+	// script Pod before its Job fails.
+	initScriptBackoffLimit = 2
+
+	// initScriptMountPath is the directory the script is mounted in.
+	initScriptMountPath = "/scripts"
+)
+
+func initScriptJobName(documentdbName string, index int) string {
+	return fmt.Sprintf("%s%s%d", documentdbName, initScriptJobSuffix, index)
+}
+
+// initScriptsPending reports whether spec.bootstrap.initScripts has scripts
+// left to run.
+func initScriptsPending(documentdb *dbpreview.DocumentDB) bool {
+	if documentdb.Spec.Bootstrap == nil || len(documentdb.Spec.Bootstrap.InitScripts) == 0 {
+		return false
+	}
+	status := documentdb.Status.InitScripts
+	return status == nil || status.Phase != dbpreview.InitScriptsPhaseSucceeded
+}
+
+// reconcileInitScripts runs the scripts of spec.bootstrap.initScripts in
+// order once the cluster is healthy and any import has succeeded, and
+// reports their progress in status.initScripts. It returns whether
+// status.initScripts changed. The progress is saved after each script, before
+// the next one starts, so that a script is not run again when the rest of
+// the reconcile fails. A failed script stops the scripts after it and is
+// retried until it succeeds, so that fixing its ConfigMap resumes the
+// bootstrap.
+func (r *DocumentDBReconciler) reconcileInitScripts(ctx context.Context, documentdb *dbpreview.DocumentDB, cnpgCluster *cnpgv1.Cluster) (bool, error) {
+	if !initScriptsPending(documentdb) || cnpgCluster.Status.Phase != cnpgv1.PhaseHealthy {
+		return false, nil
+	}
+	if documentdb.Spec.Bootstrap.Import != nil &&
+		(documentdb.Status.Import == nil || documentdb.Status.Import.Phase != dbpreview.ImportPhaseSucceeded) {
+		return false, nil
+	}
+	scripts := documentdb.Spec.Bootstrap.InitScripts
+
+	changed := false
+	status := documentdb.Status.InitScripts
+	if status == nil {
+		now := metav1.Now()
+		status = &dbpreview.InitScriptsStatus{
+			Phase:     dbpreview.InitScriptsPhaseRunning,
+			Total:     int32(len(scripts)),
+			StartedAt: &now,
+		}
+		documentdb.Status.InitScripts = status
+		changed = true
+	}
+
+	for int(status.Applied) < len(scripts) {
+		index := int(status.Applied)
+		done, failure, err := r.runInitScript(ctx, documentdb, index, scripts[index], cnpgCluster)
+		if err != nil {
+			return changed, err
+		}
+		if failure != "" {
+			message := fmt.Sprintf("init script %d (%s/%s) failed: %s", index,
+				scripts[index].ConfigMapKeyRef.Name, scripts[index].ConfigMapKeyRef.Key, failure)
+			if status.Phase != dbpreview.InitScriptsPhaseFailed || status.Message != message {
+				if r.Recorder != nil {
+					r.Recorder.Event(documentdb, corev1.EventTypeWarning, "InitScriptFailed", message)
+				}
+				status.Phase = dbpreview.InitScriptsPhaseFailed
+				status.Message = message
+				changed = true
+			}
+			return changed, nil
+		}
+		if !done {
+			return changed, nil
+		}
+		log.FromContext(ctx).Info("Applied init script", "index", index, "configMap", scripts[index].ConfigMapKeyRef.Name)
+		status.Applied++
+		status.Phase = dbpreview.InitScriptsPhaseRunning
+		status.Message = ""
+		changed = true
+		if err := r.updateStatus(ctx, documentdb); err != nil {
+			return changed, fmt.Errorf("failed to save the progress of the init scripts: %w", err)
+		}
+	}
+
+	now := metav1.Now()
+	status.Phase = dbpreview.InitScriptsPhaseSucceeded
+	status.CompletedAt = &now
+	if r.Recorder != nil {
+		r.Recorder.Event(documentdb, corev1.EventTypeNormal, "InitScriptsSucceeded", fmt.Sprintf("Applied %d init scripts", len(scripts)))
+	}
+	return true, nil
+}
+
+// runInitScript runs the init script at index. It returns whether the script
+// succeeded and, when it failed, why. SQL scripts run synchronously; a
+// JavaScript script runs in a Job, which is deleted when it fails so that
+// the next attempt starts a new one.
+func (r *DocumentDBReconciler) runInitScript(ctx context.Context, documentdb *dbpreview.DocumentDB, index int, script dbpreview.InitScript, cnpgCluster *cnpgv1.Cluster) (bool, string, error) {
+	ref := script.ConfigMapKeyRef
+	configMap := &corev1.ConfigMap{}
+	if err := r.Get(ctx, types.NamespacedName{Name: ref.Name, Namespace: documentdb.Namespace}, configMap); err != nil {
+		if errors.IsNotFound(err) {
+			return false, fmt.Sprintf("ConfigMap %s not found", ref.Name), nil
+		}
+		return false, "", fmt.Errorf("failed to get init script ConfigMap: %w", err)
+	}
+	content, ok := configMap.Data[ref.Key]
+	if !ok {
+		return false, fmt.Sprintf("ConfigMap %s has no key %s", ref.Name, ref.Key), nil
+	}
+
+	if script.Language != dbpreview.InitScriptLanguageJavaScript {
+		if _, err := r.SQLExecutor(ctx, cnpgCluster, content); err != nil {
+			return false, err.Error(), nil
+		}
+		return true, "", nil
+	}
+
+	job := &batchv1.Job{}
+	err := r.Get(ctx, types.NamespacedName{Name: initScriptJobName(documentdb.Name, index), Namespace: documentdb.Namespace}, job)
+	if err == nil {
+		for _, condition := range job.Status.Conditions {
+			if condition.Status != corev1.ConditionTrue {
+				continue
+			}
+			switch condition.Type {
+			case batchv1.JobComplete:
+				return true, "", nil
+			case batchv1.JobFailed:
+				if err := r.Delete(ctx, job, client.PropagationPolicy(metav1.DeletePropagationBackground)); err != nil && !errors.IsNotFound(err) {
+					return false, "", fmt.Errorf("failed to delete init script Job: %w", err)
+				}
+				return false, fmt.Sprintf("Job %s failed: %s", job.Name, condition.Message), nil
+			}
+		}
+		return false, "", nil
+	}
+	if !errors.IsNotFound(err) {
+		return false, "", fmt.Errorf("failed to get init script Job: %w", err)
+	}
+
+	// The Job connects through the connection string Secret, which is only
+	// written once the cluster is exposed.
+	if err := r.Get(ctx, types.NamespacedName{Name: connectionSecretName(documentdb.Name), Namespace: documentdb.Namespace}, &corev1.Secret{}); err != nil {
+		if errors.IsNotFound(err) {
+			return false, "", nil
+		}
+		return false, "", fmt.Errorf("failed to get connection string Secret: %w", err)
+	}
+	job = initScriptJob(documentdb, index, ref)
+	if err := controllerutil.SetControllerReference(documentdb, job, r.Scheme); err != nil {
+		return false, "", fmt.Errorf("failed to set owner reference: %w", err)
+	}
+	if err := r.Create(ctx, job); err != nil && !errors.IsAlreadyExists(err) {
+		return false, "", fmt.Errorf("failed to create init script Job: %w", err)
+	}
+	log.FromContext(ctx).Info("Started init script Job", "job", job.Name)
+	return false, "", nil
+}
+
+// initScriptJob returns the Job that runs the JavaScript init script at
+// index with mongosh.
+func initScriptJob(documentdb *dbpreview.DocumentDB, index int, ref cnpgv1.ConfigMapKeySelector) *batchv1.Job {
+	labels := util.ChildLabels(documentdb, map[string]string{util.LABEL_DOCUMENTDB_NAME: documentdb.Name})
+	return &batchv1.Job{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:        initScriptJobName(documentdb.Name, index),
+			Namespace:   documentdb.Namespace,
+			Labels:      labels,
+			Annotations: util.ChildAnnotations(documentdb, nil),
+		},
+		Spec: batchv1.JobSpec{
+			BackoffLimit: ptr.To(int32(initScriptBackoffLimit)),
+			Template: corev1.PodTemplateSpec{
+				ObjectMeta: metav1.ObjectMeta{Labels: labels},
+				Spec: corev1.PodSpec{
+					RestartPolicy: corev1.RestartPolicyNever,
+					Containers: []corev1.Container{{
+						Name:            "init-script",
+						Image:           util.GetMongoDBToolsImage(),
+						ImagePullPolicy: documentdb.Spec.ImagePullPolicy,
+						Command:         []string{"/bin/sh", "-c", `exec mongosh "$TARGET_URI" --quiet --file ` + initScriptMountPath + "/script.js"},
+						Env: []corev1.EnvVar{{
+							Name: "TARGET_URI",
+							ValueFrom: &corev1.EnvVarSource{SecretKeyRef: &corev1.SecretKeySelector{
+								LocalObjectReference: corev1.LocalObjectReference{Name: connectionSecretName(documentdb.Name)},
+								Key:                  connectionSecretURIKey,
+							}},
+						}},
+						VolumeMounts: []corev1.VolumeMount{{Name: "script", MountPath: initScriptMountPath, ReadOnly: true}},
+						SecurityContext: &corev1.SecurityContext{
+							AllowPrivilegeEscalation: ptr.To(false),
+							Capabilities:             &corev1.Capabilities{Drop: []corev1.Capability{"ALL"}},
+						},
+					}},
+					Volumes: []corev1.Volume{{
+						Name: "script",
+						VolumeSource: corev1.VolumeSource{ConfigMap: &corev1.ConfigMapVolumeSource{
+							LocalObjectReference: corev1.LocalObjectReference{Name: ref.Name},
+							Items:                []corev1.KeyToPath{{Key: ref.Key, Path: "script.js"}},
+						}},
+					}},
+				},
+			},
+		},
+	}
+}
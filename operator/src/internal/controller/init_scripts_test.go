@@ -0,0 +1,222 @@
+// Copyright (c) Microsoft Corporation.
+// Licensed under the MIT License.
+
+package controller
+
+import (
+	"context"
+	"fmt"
+
+	cnpgv1 "github.com/cloudnative-pg/cloudnative-pg/api/v1"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	batchv1 "k8s.io/api/batch/v1"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/client-go/tools/record"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	dbpreview "github.com/documentdb/documentdb-operator/api/preview"
+)
+
+var _ = Describe("Init scripts", func() {
+	const namespace = "default"
+
+	var (
+		ctx         context.Context
+		recorder    *record.FakeRecorder
+		reconciler  *DocumentDBReconciler
+		documentdb  *dbpreview.DocumentDB
+		cnpgCluster *cnpgv1.Cluster
+		executed    []string
+		sqlErr      error
+	)
+
+	script := func(key string, language dbpreview.InitScriptLanguage) dbpreview.InitScript {
+		return dbpreview.InitScript{
+			ConfigMapKeyRef: cnpgv1.ConfigMapKeySelector{LocalObjectReference: cnpgv1.LocalObjectReference{Name: "seed"}, Key: key},
+			Language:        language,
+		}
+	}
+
+	BeforeEach(func() {
+		ctx = context.Background()
+		scheme := runtime.NewScheme()
+		Expect(dbpreview.AddToScheme(scheme)).To(Succeed())
+		Expect(corev1.AddToScheme(scheme)).To(Succeed())
+		Expect(batchv1.AddToScheme(scheme)).To(Succeed())
+		recorder = record.NewFakeRecorder(10)
+		executed = nil
+		sqlErr = nil
+
+		documentdb = &dbpreview.DocumentDB{
+			ObjectMeta: metav1.ObjectMeta{Name: "db", Namespace: namespace, UID: "uid"},
+			Spec: dbpreview.DocumentDBSpec{
+				Bootstrap: &dbpreview.BootstrapConfiguration{
+					InitScripts: []dbpreview.InitScript{
+						script("schema.sql", dbpreview.InitScriptLanguageSQL),
+						script("indexes.js", dbpreview.InitScriptLanguageJavaScript),
+						script("grants.sql", ""),
+					},
+				},
+			},
+		}
+		cnpgCluster = &cnpgv1.Cluster{
+			ObjectMeta: metav1.ObjectMeta{Name: "db", Namespace: namespace},
+			Status:     cnpgv1.ClusterStatus{Phase: cnpgv1.PhaseHealthy},
+		}
+		configMap := &corev1.ConfigMap{
+			ObjectMeta: metav1.ObjectMeta{Name: "seed", Namespace: namespace},
+			Data: map[string]string{
+				"schema.sql": "SELECT 1;",
+				"indexes.js": "db.orders.createIndex({customer: 1})",
+				"grants.sql": "SELECT 2;",
+			},
+		}
+		connectionSecret := &corev1.Secret{ObjectMeta: metav1.ObjectMeta{Name: connectionSecretName("db"), Namespace: namespace}}
+
+		reconciler = &DocumentDBReconciler{
+			Client: fake.NewClientBuilder().WithScheme(scheme).
+				WithObjects(documentdb, configMap, connectionSecret).
+				WithStatusSubresource(&dbpreview.DocumentDB{}).
+				Build(),
+			Scheme:   scheme,
+			Recorder: recorder,
+			SQLExecutor: func(_ context.Context, _ *cnpgv1.Cluster, sql string) (string, error) {
+				executed = append(executed, sql)
+				return "", sqlErr
+			},
+		}
+	})
+
+	completeJob := func(name string, conditionType batchv1.JobConditionType) {
+		job := &batchv1.Job{}
+		Expect(reconciler.Get(ctx, client.ObjectKey{Name: name, Namespace: namespace}, job)).To(Succeed())
+		job.Status.Conditions = []batchv1.JobCondition{{Type: conditionType, Status: corev1.ConditionTrue, Message: "BackoffLimitExceeded"}}
+		Expect(reconciler.Status().Update(ctx, job)).To(Succeed())
+	}
+
+	It("runs the scripts in order and reports their progress", func() {
+		changed, err := reconciler.reconcileInitScripts(ctx, documentdb, cnpgCluster)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(changed).To(BeTrue())
+		Expect(executed).To(Equal([]string{"SELECT 1;"}))
+		Expect(documentdb.Status.InitScripts.Applied).To(Equal(int32(1)))
+		Expect(documentdb.Status.InitScripts.Total).To(Equal(int32(3)))
+		Expect(documentdb.Status.InitScripts.Phase).To(Equal(dbpreview.InitScriptsPhaseRunning))
+
+		job := &batchv1.Job{}
+		Expect(reconciler.Get(ctx, client.ObjectKey{Name: "db-init-1", Namespace: namespace}, job)).To(Succeed())
+		Expect(job.OwnerReferences).To(HaveLen(1))
+		volume := job.Spec.Template.Spec.Volumes[0].ConfigMap
+		Expect(volume.Name).To(Equal("seed"))
+		Expect(volume.Items[0].Key).To(Equal("indexes.js"))
+
+		changed, err = reconciler.reconcileInitScripts(ctx, documentdb, cnpgCluster)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(changed).To(BeFalse())
+
+		completeJob("db-init-1", batchv1.JobComplete)
+		changed, err = reconciler.reconcileInitScripts(ctx, documentdb, cnpgCluster)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(changed).To(BeTrue())
+		Expect(executed).To(Equal([]string{"SELECT 1;", "SELECT 2;"}))
+		Expect(documentdb.Status.InitScripts.Phase).To(Equal(dbpreview.InitScriptsPhaseSucceeded))
+		Expect(documentdb.Status.InitScripts.CompletedAt).ToNot(BeNil())
+		Expect(recorder.Events).To(Receive(ContainSubstring("InitScriptsSucceeded")))
+		Expect(initScriptsPending(documentdb)).To(BeFalse())
+
+		changed, err = reconciler.reconcileInitScripts(ctx, documentdb, cnpgCluster)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(changed).To(BeFalse())
+		Expect(executed).To(HaveLen(2))
+	})
+
+	It("saves the progress after each script", func() {
+		_, err := reconciler.reconcileInitScripts(ctx, documentdb, cnpgCluster)
+		Expect(err).ToNot(HaveOccurred())
+
+		stored := &dbpreview.DocumentDB{}
+		Expect(reconciler.Get(ctx, client.ObjectKeyFromObject(documentdb), stored)).To(Succeed())
+		Expect(stored.Status.InitScripts).ToNot(BeNil())
+		Expect(stored.Status.InitScripts.Applied).To(Equal(int32(1)))
+
+		// A reconcile starting from the stored status does not run the
+		// applied script again
+		_, err = reconciler.reconcileInitScripts(ctx, stored, cnpgCluster)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(executed).To(Equal([]string{"SELECT 1;"}))
+	})
+
+	It("returns the error of saving the progress", func() {
+		Expect(reconciler.Delete(ctx, documentdb.DeepCopy())).To(Succeed())
+		_, err := reconciler.reconcileInitScripts(ctx, documentdb, cnpgCluster)
+		Expect(err).To(MatchError(ContainSubstring("failed to save the progress of the init scripts")))
+		Expect(executed).To(Equal([]string{"SELECT 1;"}))
+	})
+
+	It("waits for the cluster to be healthy and the import to succeed", func() {
+		cnpgCluster.Status.Phase = "Setting up primary"
+		changed, err := reconciler.reconcileInitScripts(ctx, documentdb, cnpgCluster)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(changed).To(BeFalse())
+
+		cnpgCluster.Status.Phase = cnpgv1.PhaseHealthy
+		documentdb.Spec.Bootstrap.Import = &dbpreview.ImportConfiguration{MongoDB: &dbpreview.MongoDBImportConfiguration{}}
+		documentdb.Status.Import = &dbpreview.ImportStatus{Phase: dbpreview.ImportPhaseRunning}
+		changed, err = reconciler.reconcileInitScripts(ctx, documentdb, cnpgCluster)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(changed).To(BeFalse())
+		Expect(executed).To(BeEmpty())
+	})
+
+	It("retries a failed SQL script until it succeeds", func() {
+		sqlErr = fmt.Errorf("syntax error")
+		changed, err := reconciler.reconcileInitScripts(ctx, documentdb, cnpgCluster)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(changed).To(BeTrue())
+		Expect(documentdb.Status.InitScripts.Phase).To(Equal(dbpreview.InitScriptsPhaseFailed))
+		Expect(documentdb.Status.InitScripts.Message).To(ContainSubstring("syntax error"))
+		Expect(recorder.Events).To(Receive(ContainSubstring("Warning InitScriptFailed")))
+
+		changed, err = reconciler.reconcileInitScripts(ctx, documentdb, cnpgCluster)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(changed).To(BeFalse())
+
+		sqlErr = nil
+		changed, err = reconciler.reconcileInitScripts(ctx, documentdb, cnpgCluster)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(changed).To(BeTrue())
+		Expect(documentdb.Status.InitScripts.Applied).To(Equal(int32(1)))
+		Expect(documentdb.Status.InitScripts.Message).To(BeEmpty())
+	})
+
+	It("deletes a failed script Job so that the script is retried", func() {
+		documentdb.Spec.Bootstrap.InitScripts = documentdb.Spec.Bootstrap.InitScripts[1:2]
+		_, err := reconciler.reconcileInitScripts(ctx, documentdb, cnpgCluster)
+		Expect(err).ToNot(HaveOccurred())
+
+		completeJob("db-init-0", batchv1.JobFailed)
+		_, err = reconciler.reconcileInitScripts(ctx, documentdb, cnpgCluster)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(documentdb.Status.InitScripts.Phase).To(Equal(dbpreview.InitScriptsPhaseFailed))
+		Expect(documentdb.Status.InitScripts.Message).To(ContainSubstring("BackoffLimitExceeded"))
+		err = reconciler.Get(ctx, client.ObjectKey{Name: "db-init-0", Namespace: namespace}, &batchv1.Job{})
+		Expect(apierrors.IsNotFound(err)).To(BeTrue())
+
+		_, err = reconciler.reconcileInitScripts(ctx, documentdb, cnpgCluster)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(reconciler.Get(ctx, client.ObjectKey{Name: "db-init-0", Namespace: namespace}, &batchv1.Job{})).To(Succeed())
+	})
+
+	It("reports a missing ConfigMap key", func() {
+		documentdb.Spec.Bootstrap.InitScripts = []dbpreview.InitScript{script("missing.sql", dbpreview.InitScriptLanguageSQL)}
+		_, err := reconciler.reconcileInitScripts(ctx, documentdb, cnpgCluster)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(documentdb.Status.InitScripts.Message).To(ContainSubstring("has no key missing.sql"))
+		Expect(executed).To(BeEmpty())
+	})
+})
@@ -0,0 +1,75 @@
+// Copyright (c) Microsoft Corporation.
+// Licensed under the MIT License.
+
+package controller
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"strings"
+
+	cnpgv1 "github.com/cloudnative-pg/cloudnative-pg/api/v1"
+	corev1 "k8s.io/api/core/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	dbpreview "github.com/documentdb/documentdb-operator/api/preview"
+)
+
+// documentdbExtensionVolume is the name of the image volume CNPG mounts the
+// documentdb extension from.
+const documentdbExtensionVolume = "ext-documentdb"
+
+// instanceStatuses returns the status of each instance of cluster, sorted by
+// name, from the CNPG Cluster status and the instance Pods.
+func (r *DocumentDBReconciler) instanceStatuses(ctx context.Context, cluster *cnpgv1.Cluster) ([]dbpreview.InstanceStatus, error) {
+	pods := &corev1.PodList{}
+	if err := r.List(ctx, pods, client.InNamespace(cluster.Namespace), client.MatchingLabels{"cnpg.io/cluster": cluster.Name}); err != nil {
+		return nil, fmt.Errorf("failed to list instance Pods: %w", err)
+	}
+	podsByName := make(map[string]*corev1.Pod, len(pods.Items))
+	for i := range pods.Items {
+		podsByName[pods.Items[i].Name] = &pods.Items[i]
+	}
+
+	names := slices.Clone(cluster.Status.InstanceNames)
+	slices.Sort(names)
+	var instances []dbpreview.InstanceStatus
+	for _, name := range names {
+		instance := dbpreview.InstanceStatus{
+			Name:    name,
+			Role:    dbpreview.InstanceRoleReplica,
+			Healthy: slices.Contains(cluster.Status.InstancesStatus[cnpgv1.PodHealthy], name),
+		}
+		if name == cluster.Status.CurrentPrimary {
+			instance.Role = dbpreview.InstanceRolePrimary
+		}
+		if pod, ok := podsByName[name]; ok {
+			instance.ExtensionVersion = podExtensionVersion(pod)
+			for _, container := range pod.Status.ContainerStatuses {
+				instance.RestartCount += container.RestartCount
+			}
+		}
+		instances = append(instances, instance)
+	}
+	return instances, nil
+}
+
+// podExtensionVersion returns the tag of the documentdb extension image pod
+// mounts, or "" when the image is referenced by digest.
+func podExtensionVersion(pod *corev1.Pod) string {
+	for _, volume := range pod.Spec.Volumes {
+		if volume.Name != documentdbExtensionVolume || volume.Image == nil {
+			continue
+		}
+		ref := volume.Image.Reference
+		if strings.Contains(ref, "@") {
+			return ""
+		}
+		// A colon after the last slash separates the tag, not a registry port
+		if idx := strings.LastIndex(ref, ":"); idx > strings.LastIndex(ref, "/") {
+			return ref[idx+1:]
+		}
+	}
+	return ""
+}
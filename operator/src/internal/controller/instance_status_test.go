@@ -0,0 +1,101 @@
+// Copyright (c) Microsoft Corporation.
+// Licensed under the MIT License.
+
+package controller
+
+import (
+	"context"
+
+	cnpgv1 "github.com/cloudnative-pg/cloudnative-pg/api/v1"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	dbpreview "github.com/documentdb/documentdb-operator/api/preview"
+)
+
+var _ = Describe("Instance status", func() {
+	const namespace = "default"
+
+	var (
+		ctx     context.Context
+		scheme  *runtime.Scheme
+		cluster *cnpgv1.Cluster
+	)
+
+	BeforeEach(func() {
+		ctx = context.Background()
+		scheme = runtime.NewScheme()
+		Expect(cnpgv1.AddToScheme(scheme)).To(Succeed())
+		Expect(corev1.AddToScheme(scheme)).To(Succeed())
+		cluster = &cnpgv1.Cluster{
+			ObjectMeta: metav1.ObjectMeta{Name: "db", Namespace: namespace},
+			Status: cnpgv1.ClusterStatus{
+				CurrentPrimary: "db-1",
+				InstanceNames:  []string{"db-2", "db-1"},
+				InstancesStatus: map[cnpgv1.PodStatus][]string{
+					cnpgv1.PodHealthy: {"db-1"},
+					cnpgv1.PodFailed:  {"db-2"},
+				},
+			},
+		}
+	})
+
+	newPod := func(name, image string, restarts ...int32) *corev1.Pod {
+		pod := &corev1.Pod{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      name,
+				Namespace: namespace,
+				Labels:    map[string]string{"cnpg.io/cluster": "db"},
+			},
+			Spec: corev1.PodSpec{
+				Volumes: []corev1.Volume{{
+					Name:         documentdbExtensionVolume,
+					VolumeSource: corev1.VolumeSource{Image: &corev1.ImageVolumeSource{Reference: image}},
+				}},
+			},
+		}
+		for _, count := range restarts {
+			pod.Status.ContainerStatuses = append(pod.Status.ContainerStatuses, corev1.ContainerStatus{RestartCount: count})
+		}
+		return pod
+	}
+
+	It("reports the role, health, extension version and restarts of each instance", func() {
+		reconciler := &DocumentDBReconciler{
+			Client: fake.NewClientBuilder().WithScheme(scheme).WithObjects(
+				newPod("db-1", "registry:5000/documentdb/extension:0.110.0", 0, 1),
+				newPod("db-2", "registry:5000/documentdb/extension:0.109.0", 3, 2),
+			).Build(),
+			Scheme: scheme,
+		}
+
+		instances, err := reconciler.instanceStatuses(ctx, cluster)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(instances).To(Equal([]dbpreview.InstanceStatus{
+			{Name: "db-1", Role: dbpreview.InstanceRolePrimary, Healthy: true, ExtensionVersion: "0.110.0", RestartCount: 1},
+			{Name: "db-2", Role: dbpreview.InstanceRoleReplica, Healthy: false, ExtensionVersion: "0.109.0", RestartCount: 5},
+		}))
+	})
+
+	It("reports instances whose Pod is missing", func() {
+		reconciler := &DocumentDBReconciler{
+			Client: fake.NewClientBuilder().WithScheme(scheme).Build(),
+			Scheme: scheme,
+		}
+
+		instances, err := reconciler.instanceStatuses(ctx, cluster)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(instances).To(HaveLen(2))
+		Expect(instances[1]).To(Equal(dbpreview.InstanceStatus{Name: "db-2", Role: dbpreview.InstanceRoleReplica}))
+	})
+
+	It("leaves the extension version empty for a digest or untagged reference", func() {
+		Expect(podExtensionVersion(newPod("db-1", "registry/extension@sha256:abc"))).To(BeEmpty())
+		Expect(podExtensionVersion(newPod("db-1", "registry:5000/extension"))).To(BeEmpty())
+		Expect(podExtensionVersion(&corev1.Pod{})).To(BeEmpty())
+	})
+})
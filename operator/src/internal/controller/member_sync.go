@@ -0,0 +1,547 @@
+// Copyright (c) Microsoft Corporation.
+// Licensed under the MIT License.
+
+package controller
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"hash/fnv"
+	"slices"
+	"strings"
+	"time"
+
+	cnpgv1 "github.com/cloudnative-pg/cloudnative-pg/api/v1"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/equality"
+	"k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/rest"
+	"k8s.io/client-go/tools/clientcmd"
+	clientcmdapi "k8s.io/client-go/tools/clientcmd/api"
+	"k8s.io/client-go/util/retry"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
+	"sigs.k8s.io/controller-runtime/pkg/log"
+
+	dbpreview "github.com/documentdb/documentdb-operator/api/preview"
+	util "github.com/documentdb/documentdb-operator/internal/utils"
+)
+
+const (
+	// memberClientTimeout bounds each request to a member cluster, so that a
+	// member cluster that does not answer fails its sync instead of hanging it.
+	memberClientTimeout = 10 * time.Second
+	// memberSyncMaxBackoff caps the delay before syncing again the member
+	// clusters of a DocumentDB whose sync failed. The delay starts at
+	// RequeueAfterShort and doubles with each failure.
+	memberSyncMaxBackoff = 5 * time.Minute
+)
+
+// cachedMemberClient is a client of a member cluster, with the resourceVersion
+// of the kubeconfig Secret it was built from.
+type cachedMemberClient struct {
+	resourceVersion string
+	client          client.Client
+}
+
+// memberSyncRetry delays the next sync of the member clusters of a DocumentDB
+// after a failed one.
+type memberSyncRetry struct {
+	failures  int
+	notBefore time.Time
+}
+
+// startMemberSync syncs the DocumentDBs of the member clusters of documentdb in
+// the background (see reconcileMemberDocumentDBs), so that a member cluster
+// that does not answer does not hold up the reconciles. The outcome is
+// recorded in status.members, whose update triggers the next reconcile. After
+// a failed sync, the next one waits for a backoff.
+func (r *DocumentDBReconciler) startMemberSync(documentdb *dbpreview.DocumentDB) {
+	if len(documentdb.Status.Members) == 0 &&
+		meta.FindStatusCondition(documentdb.Status.Conditions, dbpreview.ConditionMembersSynced) == nil &&
+		!slices.ContainsFunc(memberClusters(documentdb), func(member dbpreview.MemberCluster) bool { return member.KubeconfigSecret != nil }) {
+		return
+	}
+	key := client.ObjectKeyFromObject(documentdb)
+	r.memberMu.Lock()
+	next, found := r.memberSyncRetries[key]
+	r.memberMu.Unlock()
+	if found && time.Now().Before(next.notBefore) {
+		return
+	}
+	if r.backgroundOps == nil {
+		r.backgroundOps = newBackgroundOperations()
+	}
+	r.backgroundOps.Go("members/"+key.String(), func(ctx context.Context) {
+		r.syncMembers(ctx, key)
+	})
+}
+
+// syncMembers runs reconcileMemberDocumentDBs for the DocumentDB of key and
+// sets the backoff of its next sync.
+func (r *DocumentDBReconciler) syncMembers(ctx context.Context, key types.NamespacedName) {
+	documentdb := &dbpreview.DocumentDB{}
+	if err := r.Get(ctx, key, documentdb); err != nil {
+		if !errors.IsNotFound(err) {
+			log.Log.Error(err, "Failed to get DocumentDB", "documentdb", key)
+		}
+		return
+	}
+	if !documentdb.DeletionTimestamp.IsZero() {
+		return
+	}
+	err := r.reconcileMemberDocumentDBs(ctx, documentdb)
+	if err != nil {
+		log.Log.Error(err, "Failed to sync the DocumentDBs of the member clusters", "documentdb", key)
+	}
+	failed := err != nil || slices.ContainsFunc(documentdb.Status.Members, func(member dbpreview.MemberDocumentDBStatus) bool {
+		return !member.Synced
+	})
+
+	r.memberMu.Lock()
+	defer r.memberMu.Unlock()
+	if !failed {
+		delete(r.memberSyncRetries, key)
+		return
+	}
+	if r.memberSyncRetries == nil {
+		r.memberSyncRetries = map[types.NamespacedName]memberSyncRetry{}
+	}
+	next := r.memberSyncRetries[key]
+	next.failures++
+	next.notBefore = time.Now().Add(memberSyncBackoff(next.failures))
+	r.memberSyncRetries[key] = next
+}
+
+// memberSyncBackoff returns the delay before the next sync of the member
+// clusters after the given number of failed syncs in a row.
+func memberSyncBackoff(failures int) time.Duration {
+	backoff := RequeueAfterShort
+	for i := 1; i < failures && backoff < memberSyncMaxBackoff; i++ {
+		backoff *= 2
+	}
+	return min(backoff, memberSyncMaxBackoff)
+}
+
+func memberClusters(documentdb *dbpreview.DocumentDB) []dbpreview.MemberCluster {
+	if documentdb.Spec.ClusterReplication == nil {
+		return nil
+	}
+	return documentdb.Spec.ClusterReplication.ClusterList
+}
+
+// reconcileMemberDocumentDBs creates the DocumentDB of each member cluster of
+// spec.clusterReplication that sets a kubeconfigSecret, and keeps its spec in
+// sync with the one of documentdb. The DocumentDBs of the member clusters
+// that left spec.clusterReplication are deleted. The outcome is recorded in
+// status.members and the MembersSynced condition; an error is only returned
+// when the status cannot be updated.
+func (r *DocumentDBReconciler) reconcileMemberDocumentDBs(ctx context.Context, documentdb *dbpreview.DocumentDB) error {
+	selectors := map[string]cnpgv1.SecretKeySelector{}
+	var clusters []string
+	for _, member := range memberClusters(documentdb) {
+		if member.KubeconfigSecret != nil {
+			clusters = append(clusters, member.Name)
+			selectors[member.Name] = *member.KubeconfigSecret
+		}
+	}
+	if len(clusters) == 0 && len(documentdb.Status.Members) == 0 {
+		if meta.RemoveStatusCondition(&documentdb.Status.Conditions, dbpreview.ConditionMembersSynced) {
+			return r.updateMemberStatus(ctx, documentdb)
+		}
+		return nil
+	}
+
+	spec, hash, err := memberDocumentDBSpec(documentdb)
+	if err != nil {
+		return err
+	}
+
+	var members []dbpreview.MemberDocumentDBStatus
+	for _, member := range documentdb.Status.Members {
+		if _, found := selectors[member.Cluster]; found {
+			continue
+		}
+		if err := r.deleteMemberDocumentDB(ctx, documentdb, member); err != nil {
+			member.Synced = false
+			member.Message = err.Error()
+			members = append(members, member)
+		}
+	}
+	for _, cluster := range clusters {
+		member := dbpreview.MemberDocumentDBStatus{Cluster: cluster, KubeconfigSecret: selectors[cluster]}
+		if previous := findMemberStatus(documentdb, cluster); previous != nil {
+			member.Generation = previous.Generation
+		}
+		if err := r.syncMemberDocumentDB(ctx, documentdb, spec, hash, &member); err != nil {
+			member.Synced = false
+			member.Message = err.Error()
+		}
+		members = append(members, member)
+	}
+	slices.SortFunc(members, func(a, b dbpreview.MemberDocumentDBStatus) int {
+		return strings.Compare(a.Cluster, b.Cluster)
+	})
+
+	changed := !equality.Semantic.DeepEqual(documentdb.Status.Members, members)
+	documentdb.Status.Members = members
+	if len(members) == 0 {
+		changed = meta.RemoveStatusCondition(&documentdb.Status.Conditions, dbpreview.ConditionMembersSynced) || changed
+	} else {
+		condition := memberSyncCondition(documentdb)
+		if meta.SetStatusCondition(&documentdb.Status.Conditions, condition) {
+			changed = true
+			if condition.Status == metav1.ConditionFalse && r.Recorder != nil {
+				r.Recorder.Event(documentdb, corev1.EventTypeWarning, condition.Reason, condition.Message)
+			}
+		}
+	}
+	if !changed {
+		return nil
+	}
+	if err := r.updateMemberStatus(ctx, documentdb); err != nil {
+		return fmt.Errorf("failed to update the status of the member clusters: %w", err)
+	}
+	return nil
+}
+
+// updateMemberStatus writes status.members and the MembersSynced condition of
+// documentdb to a fresh copy, retrying on conflicts, since the reconciles
+// write the rest of the status while the member clusters are synced.
+func (r *DocumentDBReconciler) updateMemberStatus(ctx context.Context, documentdb *dbpreview.DocumentDB) error {
+	return retry.RetryOnConflict(retry.DefaultRetry, func() error {
+		current := &dbpreview.DocumentDB{}
+		if err := r.Get(ctx, client.ObjectKeyFromObject(documentdb), current); err != nil {
+			return client.IgnoreNotFound(err)
+		}
+		current.Status.Members = documentdb.Status.Members
+		if condition := meta.FindStatusCondition(documentdb.Status.Conditions, dbpreview.ConditionMembersSynced); condition != nil {
+			meta.SetStatusCondition(&current.Status.Conditions, *condition)
+		} else {
+			meta.RemoveStatusCondition(&current.Status.Conditions, dbpreview.ConditionMembersSynced)
+		}
+		if err := r.Status().Update(ctx, current); err != nil {
+			return err
+		}
+		documentdb.ResourceVersion = current.ResourceVersion
+		return nil
+	})
+}
+
+// memberSyncCondition returns the MembersSynced condition for the members
+// recorded in the status of documentdb.
+func memberSyncCondition(documentdb *dbpreview.DocumentDB) metav1.Condition {
+	var failures []string
+	for _, member := range documentdb.Status.Members {
+		if !member.Synced {
+			failures = append(failures, member.Cluster+": "+member.Message)
+		}
+	}
+	if len(failures) > 0 {
+		return metav1.Condition{
+			Type:               dbpreview.ConditionMembersSynced,
+			Status:             metav1.ConditionFalse,
+			Reason:             dbpreview.ReasonMemberSyncFailed,
+			Message:            strings.Join(failures, "; "),
+			ObservedGeneration: documentdb.Generation,
+		}
+	}
+	return metav1.Condition{
+		Type:               dbpreview.ConditionMembersSynced,
+		Status:             metav1.ConditionTrue,
+		Reason:             dbpreview.ReasonMembersInSync,
+		Message:            fmt.Sprintf("Synced the DocumentDB of %d member clusters", len(documentdb.Status.Members)),
+		ObservedGeneration: documentdb.Generation,
+	}
+}
+
+// syncMemberDocumentDB creates or updates the DocumentDB of member, along with
+// its namespace and the Secrets and ConfigMaps it is copied with, and records
+// the result in member. A DocumentDB changed on the member cluster is set back
+// to spec.
+func (r *DocumentDBReconciler) syncMemberDocumentDB(ctx context.Context, documentdb *dbpreview.DocumentDB, spec *dbpreview.DocumentDBSpec, hash string, member *dbpreview.MemberDocumentDBStatus) error {
+	memberClient, err := r.memberClient(ctx, documentdb.Namespace, member.Cluster, member.KubeconfigSecret)
+	if err != nil {
+		return err
+	}
+	if err := copyToMember(ctx, r.Client, memberClient, documentdb); err != nil {
+		return err
+	}
+
+	current := &dbpreview.DocumentDB{}
+	err = memberClient.Get(ctx, client.ObjectKeyFromObject(documentdb), current)
+	switch {
+	case errors.IsNotFound(err):
+		current = &dbpreview.DocumentDB{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:        documentdb.Name,
+				Namespace:   documentdb.Namespace,
+				Annotations: map[string]string{util.MEMBER_SPEC_HASH_ANNOTATION: hash},
+			},
+			Spec: *spec,
+		}
+		if err := memberClient.Create(ctx, current); err != nil {
+			return fmt.Errorf("failed to create the DocumentDB: %w", err)
+		}
+		log.FromContext(ctx).Info("Created the DocumentDB of a member cluster", "cluster", member.Cluster)
+	case err != nil:
+		return fmt.Errorf("failed to get the DocumentDB: %w", err)
+	case !metav1.HasAnnotation(current.ObjectMeta, util.MEMBER_SPEC_HASH_ANNOTATION):
+		return fmt.Errorf("a DocumentDB %s that was not created from this one already exists", documentdb.Name)
+	case current.Annotations[util.MEMBER_SPEC_HASH_ANNOTATION] != hash || current.Generation != member.Generation:
+		current.Spec = *spec
+		current.Annotations[util.MEMBER_SPEC_HASH_ANNOTATION] = hash
+		if err := memberClient.Update(ctx, current); err != nil {
+			return fmt.Errorf("failed to update the DocumentDB: %w", err)
+		}
+	}
+
+	member.Synced = true
+	member.Generation = current.Generation
+	member.Status = current.Status.Status
+	member.Message = ""
+	return nil
+}
+
+// deleteMemberDocumentDB deletes the DocumentDB created on the member cluster
+// of member. A DocumentDB the operator did not create is left alone.
+func (r *DocumentDBReconciler) deleteMemberDocumentDB(ctx context.Context, documentdb *dbpreview.DocumentDB, member dbpreview.MemberDocumentDBStatus) error {
+	memberClient, err := r.memberClient(ctx, documentdb.Namespace, member.Cluster, member.KubeconfigSecret)
+	if err != nil {
+		return err
+	}
+	current := &dbpreview.DocumentDB{}
+	if err := memberClient.Get(ctx, client.ObjectKeyFromObject(documentdb), current); err != nil {
+		if errors.IsNotFound(err) {
+			r.forgetMemberClient(documentdb.Namespace, member.KubeconfigSecret)
+			return nil
+		}
+		return fmt.Errorf("failed to get the DocumentDB: %w", err)
+	}
+	if metav1.HasAnnotation(current.ObjectMeta, util.MEMBER_SPEC_HASH_ANNOTATION) {
+		if err := memberClient.Delete(ctx, current); err != nil && !errors.IsNotFound(err) {
+			return fmt.Errorf("failed to delete the DocumentDB: %w", err)
+		}
+		log.FromContext(ctx).Info("Deleted the DocumentDB of a member cluster", "cluster", member.Cluster)
+	}
+	r.forgetMemberClient(documentdb.Namespace, member.KubeconfigSecret)
+	return nil
+}
+
+// deleteMemberDocumentDBs deletes the DocumentDBs created on the member
+// clusters from documentdb, which is being deleted. Each of them then runs its
+// own deletion checks on its member cluster.
+func (r *DocumentDBReconciler) deleteMemberDocumentDBs(ctx context.Context, documentdb *dbpreview.DocumentDB) error {
+	members := slices.Clone(documentdb.Status.Members)
+	if replication := documentdb.Spec.ClusterReplication; replication != nil {
+		for _, member := range replication.ClusterList {
+			if member.KubeconfigSecret != nil && findMemberStatus(documentdb, member.Name) == nil {
+				members = append(members, dbpreview.MemberDocumentDBStatus{Cluster: member.Name, KubeconfigSecret: *member.KubeconfigSecret})
+			}
+		}
+	}
+	for _, member := range members {
+		if err := r.deleteMemberDocumentDB(ctx, documentdb, member); err != nil {
+			return fmt.Errorf("failed to delete the DocumentDB of member cluster %s: %w", member.Cluster, err)
+		}
+	}
+	return nil
+}
+
+// memberClient returns a client of a member cluster, built from the
+// kubeconfig in selector. The client is kept until the kubeconfig Secret
+// changes, which is told from its metadata alone.
+func (r *DocumentDBReconciler) memberClient(ctx context.Context, namespace, cluster string, selector cnpgv1.SecretKeySelector) (client.Client, error) {
+	key := memberClientKey(namespace, selector)
+	secretMeta := &metav1.PartialObjectMetadata{}
+	secretMeta.SetGroupVersionKind(corev1.SchemeGroupVersion.WithKind("Secret"))
+	if err := r.Get(ctx, types.NamespacedName{Name: selector.Name, Namespace: namespace}, secretMeta); err != nil {
+		return nil, fmt.Errorf("failed to get kubeconfig Secret %s: %w", selector.Name, err)
+	}
+	r.memberMu.Lock()
+	cached, found := r.memberClients[key]
+	r.memberMu.Unlock()
+	if found && cached.resourceVersion == secretMeta.ResourceVersion {
+		return cached.client, nil
+	}
+
+	secret := &corev1.Secret{}
+	if err := r.Get(ctx, types.NamespacedName{Name: selector.Name, Namespace: namespace}, secret); err != nil {
+		return nil, fmt.Errorf("failed to get kubeconfig Secret %s: %w", selector.Name, err)
+	}
+	kubeconfig, found := secret.Data[selector.Key]
+	if !found {
+		return nil, fmt.Errorf("kubeconfig Secret %s has no key %s", selector.Name, selector.Key)
+	}
+	memberClient, err := r.MemberClientFactory(kubeconfig)
+	if err != nil {
+		return nil, fmt.Errorf("failed to connect to member cluster %s: %w", cluster, err)
+	}
+	r.memberMu.Lock()
+	defer r.memberMu.Unlock()
+	if r.memberClients == nil {
+		r.memberClients = map[string]cachedMemberClient{}
+	}
+	r.memberClients[key] = cachedMemberClient{resourceVersion: secret.ResourceVersion, client: memberClient}
+	return memberClient, nil
+}
+
+// forgetMemberClient drops the cached client built from the kubeconfig in
+// selector.
+func (r *DocumentDBReconciler) forgetMemberClient(namespace string, selector cnpgv1.SecretKeySelector) {
+	r.memberMu.Lock()
+	defer r.memberMu.Unlock()
+	delete(r.memberClients, memberClientKey(namespace, selector))
+}
+
+func memberClientKey(namespace string, selector cnpgv1.SecretKeySelector) string {
+	return namespace + "/" + selector.Name + "/" + selector.Key
+}
+
+// newMemberClient builds a client of a member cluster from its kubeconfig.
+func (r *DocumentDBReconciler) newMemberClient(kubeconfig []byte) (client.Client, error) {
+	config, err := memberRESTConfig(kubeconfig)
+	if err != nil {
+		return nil, err
+	}
+	return client.New(config, client.Options{Scheme: r.Scheme})
+}
+
+// memberRESTConfig returns the configuration of the clients of a member
+// cluster, whose requests time out after memberClientTimeout. The kubeconfig
+// comes from a Secret of the user: see checkMemberKubeconfig.
+func memberRESTConfig(kubeconfig []byte) (*rest.Config, error) {
+	apiConfig, err := clientcmd.Load(kubeconfig)
+	if err != nil {
+		return nil, err
+	}
+	if err := checkMemberKubeconfig(apiConfig); err != nil {
+		return nil, err
+	}
+	config, err := clientcmd.NewDefaultClientConfig(*apiConfig, &clientcmd.ConfigOverrides{}).ClientConfig()
+	if err != nil {
+		return nil, err
+	}
+	config.Timeout = memberClientTimeout
+	return config, nil
+}
+
+// checkMemberKubeconfig rejects the kubeconfigs that would make the operator
+// run a command or read one of its own files, such as the token of its
+// ServiceAccount: credentials and certificates must be inline.
+func checkMemberKubeconfig(config *clientcmdapi.Config) error {
+	for name, authInfo := range config.AuthInfos {
+		switch {
+		case authInfo.Exec != nil:
+			return fmt.Errorf("user %s of the kubeconfig sets exec, which is not allowed", name)
+		case authInfo.AuthProvider != nil:
+			return fmt.Errorf("user %s of the kubeconfig sets auth-provider, which is not allowed", name)
+		case authInfo.TokenFile != "":
+			return fmt.Errorf("user %s of the kubeconfig sets tokenFile: use token instead", name)
+		case authInfo.ClientCertificate != "":
+			return fmt.Errorf("user %s of the kubeconfig sets client-certificate: use client-certificate-data instead", name)
+		case authInfo.ClientKey != "":
+			return fmt.Errorf("user %s of the kubeconfig sets client-key: use client-key-data instead", name)
+		}
+	}
+	for name, cluster := range config.Clusters {
+		if cluster.CertificateAuthority != "" {
+			return fmt.Errorf("cluster %s of the kubeconfig sets certificate-authority: use certificate-authority-data instead", name)
+		}
+	}
+	return nil
+}
+
+// copyToMember creates the namespace of documentdb on a member cluster, and
+// copies its credential Secret there, along with the Secrets and ConfigMaps
+// labelled LABEL_FLEET_PLACEMENT with its name.
+func copyToMember(ctx context.Context, local, member client.Client, documentdb *dbpreview.DocumentDB) error {
+	namespace := &corev1.Namespace{ObjectMeta: metav1.ObjectMeta{Name: documentdb.Namespace}}
+	if err := member.Create(ctx, namespace); err != nil && !errors.IsAlreadyExists(err) {
+		return fmt.Errorf("failed to create namespace %s: %w", documentdb.Namespace, err)
+	}
+
+	secrets := &corev1.SecretList{}
+	if err := local.List(ctx, secrets, client.InNamespace(documentdb.Namespace),
+		client.MatchingLabels{util.LABEL_FLEET_PLACEMENT: documentdb.Name}); err != nil {
+		return fmt.Errorf("failed to list the Secrets to copy: %w", err)
+	}
+	credentials := &corev1.Secret{}
+	if err := local.Get(ctx, types.NamespacedName{Name: util.CredentialSecretName(documentdb), Namespace: documentdb.Namespace}, credentials); err == nil {
+		secrets.Items = append(secrets.Items, *credentials)
+	} else if !errors.IsNotFound(err) {
+		return fmt.Errorf("failed to get the credential Secret: %w", err)
+	}
+	for _, source := range secrets.Items {
+		secret := &corev1.Secret{ObjectMeta: metav1.ObjectMeta{Name: source.Name, Namespace: source.Namespace}}
+		if _, err := controllerutil.CreateOrUpdate(ctx, member, secret, func() error {
+			if secret.Type == "" {
+				secret.Type = source.Type
+			}
+			secret.Data = source.Data
+			return nil
+		}); err != nil {
+			return fmt.Errorf("failed to copy Secret %s: %w", source.Name, err)
+		}
+	}
+
+	configMaps := &corev1.ConfigMapList{}
+	if err := local.List(ctx, configMaps, client.InNamespace(documentdb.Namespace),
+		client.MatchingLabels{util.LABEL_FLEET_PLACEMENT: documentdb.Name}); err != nil {
+		return fmt.Errorf("failed to list the ConfigMaps to copy: %w", err)
+	}
+	for _, source := range configMaps.Items {
+		configMap := &corev1.ConfigMap{ObjectMeta: metav1.ObjectMeta{Name: source.Name, Namespace: source.Namespace}}
+		if _, err := controllerutil.CreateOrUpdate(ctx, member, configMap, func() error {
+			configMap.Data = source.Data
+			configMap.BinaryData = source.BinaryData
+			return nil
+		}); err != nil {
+			return fmt.Errorf("failed to copy ConfigMap %s: %w", source.Name, err)
+		}
+	}
+	return nil
+}
+
+// memberDocumentDBSpec returns the spec of the DocumentDBs of the member
+// clusters, and a hash of it. It is the spec of documentdb without the
+// kubeconfigSecrets, so that they do not sync DocumentDBs of their own.
+func memberDocumentDBSpec(documentdb *dbpreview.DocumentDB) (*dbpreview.DocumentDBSpec, string, error) {
+	spec := documentdb.Spec.DeepCopy()
+	if spec.ClusterReplication != nil {
+		for i := range spec.ClusterReplication.ClusterList {
+			spec.ClusterReplication.ClusterList[i].KubeconfigSecret = nil
+		}
+	}
+	data, err := json.Marshal(spec)
+	if err != nil {
+		return nil, "", fmt.Errorf("failed to hash the spec of the member clusters: %w", err)
+	}
+	h := fnv.New64a()
+	h.Write(data)
+	return spec, fmt.Sprintf("%x", h.Sum64()), nil
+}
+
+func findMemberStatus(documentdb *dbpreview.DocumentDB, cluster string) *dbpreview.MemberDocumentDBStatus {
+	for i := range documentdb.Status.Members {
+		if documentdb.Status.Members[i].Cluster == cluster {
+			return &documentdb.Status.Members[i]
+		}
+	}
+	return nil
+}
+
+// memberSyncPending reports whether the DocumentDB of a member cluster is not
+// synced or not healthy yet. Their status is not watched, only polled.
+func memberSyncPending(documentdb *dbpreview.DocumentDB) bool {
+	for _, member := range documentdb.Status.Members {
+		if !member.Synced || member.Status != cnpgClusterHealthyPhase {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,275 @@
+// Copyright (c) Microsoft Corporation.
+// Licensed under the MIT License.
+
+package controller
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	cnpgv1 "github.com/cloudnative-pg/cloudnative-pg/api/v1"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/client-go/tools/record"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	dbpreview "github.com/documentdb/documentdb-operator/api/preview"
+	util "github.com/documentdb/documentdb-operator/internal/utils"
+)
+
+var _ = Describe("Member cluster sync", func() {
+	const namespace = "documentdb-ns"
+
+	var (
+		ctx        context.Context
+		scheme     *runtime.Scheme
+		documentdb *dbpreview.DocumentDB
+		objects    []client.Object
+		members    map[string]client.Client
+		reconciler *DocumentDBReconciler
+	)
+
+	kubeconfigSecret := func(cluster string) *cnpgv1.SecretKeySelector {
+		return &cnpgv1.SecretKeySelector{
+			LocalObjectReference: cnpgv1.LocalObjectReference{Name: cluster + "-kubeconfig"},
+			Key:                  "kubeconfig",
+		}
+	}
+
+	BeforeEach(func() {
+		ctx = context.Background()
+		scheme = runtime.NewScheme()
+		Expect(dbpreview.AddToScheme(scheme)).To(Succeed())
+		Expect(corev1.AddToScheme(scheme)).To(Succeed())
+		documentdb = &dbpreview.DocumentDB{
+			ObjectMeta: metav1.ObjectMeta{Name: "db", Namespace: namespace, Generation: 1},
+			Spec: dbpreview.DocumentDBSpec{
+				NodeCount:        1,
+				InstancesPerNode: 1,
+				ClusterReplication: &dbpreview.ClusterReplication{
+					Primary: "east",
+					ClusterList: []dbpreview.MemberCluster{
+						{Name: "east"},
+						{Name: "west", KubeconfigSecret: kubeconfigSecret("west")},
+					},
+				},
+			},
+		}
+		objects = []client.Object{
+			&corev1.Secret{
+				ObjectMeta: metav1.ObjectMeta{Name: "west-kubeconfig", Namespace: namespace},
+				Data:       map[string][]byte{"kubeconfig": []byte("west")},
+			},
+			&corev1.Secret{
+				ObjectMeta: metav1.ObjectMeta{Name: util.DEFAULT_DOCUMENTDB_CREDENTIALS_SECRET, Namespace: namespace},
+				Data:       map[string][]byte{"password": []byte("secret")},
+			},
+		}
+		members = map[string]client.Client{
+			"west": fake.NewClientBuilder().WithScheme(scheme).Build(),
+		}
+	})
+
+	build := func() {
+		reconciler = &DocumentDBReconciler{
+			Client:   fake.NewClientBuilder().WithScheme(scheme).WithObjects(append(objects, documentdb)...).WithStatusSubresource(documentdb).Build(),
+			Scheme:   scheme,
+			Recorder: record.NewFakeRecorder(10),
+			MemberClientFactory: func(kubeconfig []byte) (client.Client, error) {
+				if member, found := members[string(kubeconfig)]; found {
+					return member, nil
+				}
+				return nil, fmt.Errorf("unknown cluster %s", kubeconfig)
+			},
+		}
+	}
+	memberDocumentDB := func(cluster string) *dbpreview.DocumentDB {
+		current := &dbpreview.DocumentDB{}
+		Expect(members[cluster].Get(ctx, client.ObjectKeyFromObject(documentdb), current)).To(Succeed())
+		return current
+	}
+	synced := func() *metav1.Condition {
+		return meta.FindStatusCondition(documentdb.Status.Conditions, dbpreview.ConditionMembersSynced)
+	}
+
+	It("creates the DocumentDB of the member cluster without the kubeconfigSecrets", func() {
+		build()
+		Expect(reconciler.reconcileMemberDocumentDBs(ctx, documentdb)).To(Succeed())
+
+		west := memberDocumentDB("west")
+		Expect(west.Annotations).To(HaveKey(util.MEMBER_SPEC_HASH_ANNOTATION))
+		Expect(west.Spec.ClusterReplication.Primary).To(Equal("east"))
+		for _, member := range west.Spec.ClusterReplication.ClusterList {
+			Expect(member.KubeconfigSecret).To(BeNil())
+		}
+		Expect(members["west"].Get(ctx, client.ObjectKey{Name: namespace}, &corev1.Namespace{})).To(Succeed())
+		credentials := &corev1.Secret{}
+		Expect(members["west"].Get(ctx, client.ObjectKey{Name: util.DEFAULT_DOCUMENTDB_CREDENTIALS_SECRET, Namespace: namespace}, credentials)).To(Succeed())
+		Expect(credentials.Data).To(HaveKeyWithValue("password", []byte("secret")))
+
+		Expect(documentdb.Status.Members).To(HaveLen(1))
+		Expect(documentdb.Status.Members[0].Cluster).To(Equal("west"))
+		Expect(documentdb.Status.Members[0].Synced).To(BeTrue())
+		Expect(synced().Status).To(Equal(metav1.ConditionTrue))
+		Expect(memberSyncPending(documentdb)).To(BeTrue())
+	})
+
+	It("propagates a change of the spec and reverts one made on the member cluster", func() {
+		build()
+		Expect(reconciler.reconcileMemberDocumentDBs(ctx, documentdb)).To(Succeed())
+
+		documentdb.Spec.ClusterReplication.Primary = "west"
+		Expect(reconciler.reconcileMemberDocumentDBs(ctx, documentdb)).To(Succeed())
+		Expect(memberDocumentDB("west").Spec.ClusterReplication.Primary).To(Equal("west"))
+
+		west := memberDocumentDB("west")
+		west.Spec.NodeCount = 3
+		west.Generation = documentdb.Status.Members[0].Generation + 1
+		Expect(members["west"].Update(ctx, west)).To(Succeed())
+		Expect(reconciler.reconcileMemberDocumentDBs(ctx, documentdb)).To(Succeed())
+		Expect(memberDocumentDB("west").Spec.NodeCount).To(Equal(1))
+	})
+
+	It("leaves alone a DocumentDB it did not create", func() {
+		existing := &dbpreview.DocumentDB{ObjectMeta: metav1.ObjectMeta{Name: "db", Namespace: namespace}}
+		members["west"] = fake.NewClientBuilder().WithScheme(scheme).WithObjects(existing).Build()
+		build()
+		Expect(reconciler.reconcileMemberDocumentDBs(ctx, documentdb)).To(Succeed())
+
+		Expect(memberDocumentDB("west").Spec.ClusterReplication).To(BeNil())
+		Expect(documentdb.Status.Members[0].Synced).To(BeFalse())
+		Expect(synced().Status).To(Equal(metav1.ConditionFalse))
+		Expect(synced().Reason).To(Equal(dbpreview.ReasonMemberSyncFailed))
+		Expect(synced().Message).To(ContainSubstring("west: "))
+	})
+
+	It("reports a member cluster it cannot reach", func() {
+		documentdb.Spec.ClusterReplication.ClusterList[1].KubeconfigSecret.Name = "missing"
+		build()
+		Expect(reconciler.reconcileMemberDocumentDBs(ctx, documentdb)).To(Succeed())
+		Expect(documentdb.Status.Members[0].Synced).To(BeFalse())
+		Expect(synced().Message).To(ContainSubstring("kubeconfig Secret missing"))
+	})
+
+	It("deletes the DocumentDB of a member cluster that left spec.clusterReplication", func() {
+		build()
+		Expect(reconciler.reconcileMemberDocumentDBs(ctx, documentdb)).To(Succeed())
+
+		documentdb.Spec.ClusterReplication.ClusterList = documentdb.Spec.ClusterReplication.ClusterList[:1]
+		Expect(reconciler.reconcileMemberDocumentDBs(ctx, documentdb)).To(Succeed())
+		err := members["west"].Get(ctx, client.ObjectKeyFromObject(documentdb), &dbpreview.DocumentDB{})
+		Expect(errors.IsNotFound(err)).To(BeTrue())
+		Expect(documentdb.Status.Members).To(BeEmpty())
+		Expect(synced()).To(BeNil())
+	})
+
+	It("deletes the DocumentDBs of the member clusters with the one describing them", func() {
+		build()
+		Expect(reconciler.reconcileMemberDocumentDBs(ctx, documentdb)).To(Succeed())
+
+		Expect(reconciler.deleteMemberDocumentDBs(ctx, documentdb)).To(Succeed())
+		err := members["west"].Get(ctx, client.ObjectKeyFromObject(documentdb), &dbpreview.DocumentDB{})
+		Expect(errors.IsNotFound(err)).To(BeTrue())
+	})
+	It("reuses the client of a member cluster until its kubeconfig Secret changes", func() {
+		build()
+		factory := reconciler.MemberClientFactory
+		built := 0
+		reconciler.MemberClientFactory = func(kubeconfig []byte) (client.Client, error) {
+			built++
+			return factory(kubeconfig)
+		}
+		Expect(reconciler.reconcileMemberDocumentDBs(ctx, documentdb)).To(Succeed())
+		Expect(reconciler.reconcileMemberDocumentDBs(ctx, documentdb)).To(Succeed())
+		Expect(built).To(Equal(1))
+
+		secret := &corev1.Secret{}
+		Expect(reconciler.Get(ctx, client.ObjectKey{Name: "west-kubeconfig", Namespace: namespace}, secret)).To(Succeed())
+		secret.Labels = map[string]string{"rotated": "true"}
+		Expect(reconciler.Update(ctx, secret)).To(Succeed())
+		Expect(reconciler.reconcileMemberDocumentDBs(ctx, documentdb)).To(Succeed())
+		Expect(built).To(Equal(2))
+	})
+
+	It("backs off before syncing again a member cluster that failed", func() {
+		build()
+		attempts := make(chan struct{}, 10)
+		reconciler.MemberClientFactory = func([]byte) (client.Client, error) {
+			attempts <- struct{}{}
+			return nil, fmt.Errorf("i/o timeout")
+		}
+		reconciler.backgroundOps = newBackgroundOperations()
+
+		reconciler.startMemberSync(documentdb)
+		Eventually(attempts).Should(Receive())
+		key := client.ObjectKeyFromObject(documentdb)
+		Eventually(func() int {
+			reconciler.memberMu.Lock()
+			defer reconciler.memberMu.Unlock()
+			return reconciler.memberSyncRetries[key].failures
+		}).Should(Equal(1))
+
+		reconciler.startMemberSync(documentdb)
+		Consistently(attempts, 100*time.Millisecond).ShouldNot(Receive())
+
+		stored := &dbpreview.DocumentDB{}
+		Expect(reconciler.Get(ctx, key, stored)).To(Succeed())
+		Expect(stored.Status.Members).To(HaveLen(1))
+		Expect(stored.Status.Members[0].Message).To(ContainSubstring("i/o timeout"))
+	})
+
+	It("doubles the backoff of the member syncs up to a limit", func() {
+		Expect(memberSyncBackoff(1)).To(Equal(RequeueAfterShort))
+		Expect(memberSyncBackoff(2)).To(Equal(2 * RequeueAfterShort))
+		Expect(memberSyncBackoff(100)).To(Equal(memberSyncMaxBackoff))
+	})
+
+	memberKubeconfig := func(cluster, user string) []byte {
+		return []byte(`apiVersion: v1
+kind: Config
+clusters:
+- name: west
+  cluster:
+    server: https://west.example.com
+` + cluster + `
+users:
+- name: west
+  user:
+    token: abc
+` + user + `
+contexts:
+- name: west
+  context:
+    cluster: west
+    user: west
+current-context: west
+`)
+	}
+
+	It("bounds the requests to a member cluster", func() {
+		config, err := memberRESTConfig(memberKubeconfig("", ""))
+		Expect(err).ToNot(HaveOccurred())
+		Expect(config.Timeout).To(Equal(memberClientTimeout))
+		Expect(config.BearerToken).To(Equal("abc"))
+	})
+
+	DescribeTable("rejects a kubeconfig that runs commands or reads local files",
+		func(cluster, user, field string) {
+			_, err := memberRESTConfig(memberKubeconfig(cluster, user))
+			Expect(err).To(MatchError(ContainSubstring("west of the kubeconfig sets " + field)))
+		},
+		Entry("exec", "", "    exec:\n      apiVersion: client.authentication.k8s.io/v1\n      command: /bin/sh", "exec"),
+		Entry("auth-provider", "", "    auth-provider:\n      name: oidc", "auth-provider"),
+		Entry("tokenFile", "", "    tokenFile: /var/run/secrets/kubernetes.io/serviceaccount/token", "tokenFile"),
+		Entry("client-certificate", "", "    client-certificate: /etc/tls/tls.crt", "client-certificate"),
+		Entry("client-key", "", "    client-key: /etc/tls/tls.key", "client-key"),
+		Entry("certificate-authority", "    certificate-authority: /var/run/secrets/kubernetes.io/serviceaccount/ca.crt", "", "certificate-authority"),
+	)
+})
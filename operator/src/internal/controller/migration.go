@@ -0,0 +1,517 @@
+// Copyright (c) Microsoft Corporation.
+// Licensed under the MIT License.
+
+package controller
+
+import (
+	"context"
+	"fmt"
+	"maps"
+	"slices"
+	"strconv"
+	"strings"
+
+	cnpgv1 "github.com/cloudnative-pg/cloudnative-pg/api/v1"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/utils/ptr"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
+	"sigs.k8s.io/controller-runtime/pkg/log"
+
+	dbpreview "github.com/documentdb/documentdb-operator/api/preview"
+	"github.com/documentdb/documentdb-operator/internal/cnpg"
+	util "github.com/documentdb/documentdb-operator/internal/utils"
+)
+
+const (
+	// migrationObjectSuffix names the Publication and the Subscription,
+	// <documentdb>-migration, that replicate spec.migration.
+	migrationObjectSuffix = "-migration"
+
+	// migrationReplicationName is the name of the publication, the
+	// subscription and its replication slot in PostgreSQL.
+	migrationReplicationName = "documentdb_migration"
+
+	// migrationDataSchema holds the tables of the collections, whose
+	// definitions are copied before the replication starts.
+	migrationDataSchema = "documentdb_data"
+
+	// migrationCatalogSchema holds the metadata of the collections, which is
+	// created with the extension and replicated with the collections.
+	migrationCatalogSchema = "documentdb_api_catalog"
+)
+
+// migrationLagQuery returns the WAL written by the source that the
+// subscription has not confirmed yet.
+var migrationLagQuery = fmt.Sprintf(
+	"SELECT pg_wal_lsn_diff(pg_current_wal_lsn(), confirmed_flush_lsn)::bigint FROM pg_replication_slots WHERE slot_name = '%s';",
+	migrationReplicationName)
+
+// migrationSourceSchemaQuery returns the collection tables of the source and
+// the statements that create their indexes. The indexes that back a
+// constraint, such as those of unique indexes, are created with the
+// constraint.
+var migrationSourceSchemaQuery = fmt.Sprintf(`SELECT json_build_object(
+'tables', (SELECT coalesce(json_agg(tablename), '[]') FROM pg_tables WHERE schemaname = '%[1]s'),
+'indexes', (SELECT coalesce(json_object_agg(i.relname, json_build_object('table', t.relname, 'definition',
+  CASE WHEN con.oid IS NULL THEN pg_get_indexdef(i.oid)
+  ELSE format('ALTER TABLE %%I.%%I ADD CONSTRAINT %%I %%s', n.nspname, t.relname, con.conname, pg_get_constraintdef(con.oid)) END)), '{}')
+  FROM pg_index x JOIN pg_class i ON i.oid = x.indexrelid JOIN pg_class t ON t.oid = x.indrelid
+  JOIN pg_namespace n ON n.oid = t.relnamespace
+  LEFT JOIN pg_constraint con ON con.conindid = i.oid AND con.conrelid = t.oid AND con.contype IN ('p', 'u', 'x')
+  WHERE n.nspname = '%[1]s'));`, migrationDataSchema)
+
+// migrationTargetSchemaQuery returns the collection tables and indexes of the
+// target, the tables of the subscription and how many of its tables are
+// still being copied.
+var migrationTargetSchemaQuery = fmt.Sprintf(`SELECT json_build_object(
+'tables', (SELECT coalesce(json_agg(tablename), '[]') FROM pg_tables WHERE schemaname = '%[1]s'),
+'indexes', (SELECT coalesce(json_agg(indexname), '[]') FROM pg_indexes WHERE schemaname = '%[1]s'),
+'subscription', EXISTS (SELECT 1 FROM pg_subscription WHERE subname = '%[2]s'),
+'subscribed', (SELECT coalesce(json_agg(c.relname), '[]') FROM pg_subscription_rel r
+  JOIN pg_subscription s ON s.oid = r.srsubid JOIN pg_class c ON c.oid = r.srrelid
+  JOIN pg_namespace n ON n.oid = c.relnamespace WHERE s.subname = '%[2]s' AND n.nspname = '%[1]s'),
+'copying', (SELECT count(*) FROM pg_subscription_rel r JOIN pg_subscription s ON s.oid = r.srsubid
+  WHERE s.subname = '%[2]s' AND r.srsubstate <> 'r'));`, migrationDataSchema, migrationReplicationName)
+
+// migrationRefreshCommand makes the subscription copy and replicate the
+// tables added to the publication since it was created or last refreshed.
+const migrationRefreshCommand = "ALTER SUBSCRIPTION " + migrationReplicationName + " REFRESH PUBLICATION;"
+
+// migrationReadOnlyCommand makes the source read-only and disconnects its
+// clients, so that they reconnect read-only. The walsender of the
+// subscription is not a client backend and is kept.
+const migrationReadOnlyCommand = `ALTER DATABASE postgres SET default_transaction_read_only = on;
+SELECT pg_terminate_backend(pid) FROM pg_stat_activity
+WHERE datname = 'postgres' AND backend_type = 'client backend' AND pid <> pg_backend_pid();`
+
+// migrationSequencesQuery returns the setval calls that bring the sequences
+// of the extension, such as the collection ids, to their value on the
+// source. Logical replication does not replicate sequences.
+const migrationSequencesQuery = `SELECT string_agg(format('SELECT setval(%L, %s);', format('%I.%I', schemaname, sequencename), last_value), ' ')
+FROM pg_sequences WHERE schemaname LIKE 'documentdb%' AND last_value IS NOT NULL;`
+
+func migrationObjectName(documentdbName string) string {
+	return documentdbName + migrationObjectSuffix
+}
+
+// migrationInProgress reports whether spec.migration is replicating or
+// cutting over.
+func migrationInProgress(documentdb *dbpreview.DocumentDB) bool {
+	status := documentdb.Status.Migration
+	return documentdb.Spec.Migration != nil && status != nil &&
+		(status.Phase == dbpreview.MigrationPhaseSyncing || status.Phase == dbpreview.MigrationPhaseCuttingOver)
+}
+
+// reconcileMigration drives spec.migration once the cluster is healthy. It
+// copies the collection tables of the source, replicates the source through
+// a Publication and a Subscription, copies the collections and indexes
+// created on the source since, reports the replication lag and, once
+// spec.migration.cutover is set, makes the source read-only, waits for the
+// last changes and routes the Service of the source to this cluster. It
+// returns whether status.migration changed. Removing spec.migration stops
+// the replication.
+func (r *DocumentDBReconciler) reconcileMigration(ctx context.Context, documentdb *dbpreview.DocumentDB, cnpgCluster *cnpgv1.Cluster) (bool, error) {
+	migration := documentdb.Spec.Migration
+	if migration == nil {
+		return false, r.deleteMigrationReplication(ctx, documentdb)
+	}
+	status := documentdb.Status.Migration
+	if status != nil && status.Phase == dbpreview.MigrationPhaseCompleted {
+		// Retry the deletion of the replication of a completed migration
+		return false, r.deleteMigrationReplication(ctx, documentdb)
+	}
+	if status != nil && status.Phase == dbpreview.MigrationPhaseFailed {
+		return false, nil
+	}
+	if cnpgCluster.Status.Phase != cnpgv1.PhaseHealthy {
+		return false, nil
+	}
+
+	source := &dbpreview.DocumentDB{}
+	if err := r.Get(ctx, types.NamespacedName{Name: migration.SourceRef.Name, Namespace: documentdb.Namespace}, source); err != nil {
+		if errors.IsNotFound(err) {
+			r.recordMigrationWarning(documentdb, "MigrationSourceNotFound", fmt.Sprintf("DocumentDB %s does not exist", migration.SourceRef.Name))
+			return false, nil
+		}
+		return false, fmt.Errorf("failed to get migration source: %w", err)
+	}
+	if source.Spec.ClusterReplication != nil {
+		return r.failMigration(documentdb, "the source uses spec.clusterReplication, which is not supported"), nil
+	}
+	sourceCluster := &cnpgv1.Cluster{}
+	if err := r.Get(ctx, types.NamespacedName{Name: source.Name, Namespace: source.Namespace}, sourceCluster); err != nil {
+		if errors.IsNotFound(err) {
+			return false, nil
+		}
+		return false, fmt.Errorf("failed to get the CNPG Cluster of the migration source: %w", err)
+	}
+	if sourceCluster.Status.Phase != cnpgv1.PhaseHealthy {
+		return false, nil
+	}
+
+	if status == nil {
+		return r.startMigration(ctx, documentdb, sourceCluster, cnpgCluster)
+	}
+
+	changed := false
+	lag, found, err := r.migrationLag(ctx, sourceCluster)
+	if err != nil {
+		return false, err
+	}
+	if found && (status.LagBytes == nil || *status.LagBytes != lag) {
+		status.LagBytes = ptr.To(lag)
+		changed = true
+	}
+	if message := r.migrationSubscriptionMessage(ctx, documentdb); status.Message != message {
+		status.Message = message
+		changed = true
+	}
+	synced, err := r.syncMigrationSchema(ctx, sourceCluster, cnpgCluster)
+	if err != nil {
+		return changed, err
+	}
+
+	if !migration.Cutover {
+		return changed, nil
+	}
+	if status.Phase == dbpreview.MigrationPhaseSyncing {
+		if _, err := r.SQLExecutor(ctx, sourceCluster, migrationReadOnlyCommand); err != nil {
+			return changed, fmt.Errorf("failed to make the migration source read-only: %w", err)
+		}
+		log.FromContext(ctx).Info("Migration source is read-only, cutting over", "source", source.Name)
+		if r.Recorder != nil {
+			r.Recorder.Event(documentdb, corev1.EventTypeNormal, "MigrationCuttingOver",
+				fmt.Sprintf("DocumentDB %s is read-only, waiting for its last changes", source.Name))
+		}
+		status.Phase = dbpreview.MigrationPhaseCuttingOver
+		return true, nil
+	}
+	if !found || lag > 0 || !synced {
+		return changed, nil
+	}
+	return true, r.completeMigration(ctx, documentdb, source, sourceCluster, cnpgCluster)
+}
+
+// startMigration copies the collection tables of the source and creates the
+// Publication and the Subscription. The tables are not copied again when the
+// Subscription already exists, so that a failed status update does not
+// fail the migration.
+func (r *DocumentDBReconciler) startMigration(ctx context.Context, documentdb *dbpreview.DocumentDB, sourceCluster, cnpgCluster *cnpgv1.Cluster) (bool, error) {
+	key := types.NamespacedName{Name: migrationObjectName(documentdb.Name), Namespace: documentdb.Namespace}
+	err := r.Get(ctx, key, &cnpgv1.Subscription{})
+	if err != nil && !errors.IsNotFound(err) {
+		return false, fmt.Errorf("failed to get migration Subscription: %w", err)
+	}
+	if errors.IsNotFound(err) {
+		if err := r.MigrationSchemaCopier(ctx, sourceCluster, cnpgCluster, nil); err != nil {
+			return r.failMigration(documentdb, fmt.Sprintf("failed to copy the collections of %s: %v", sourceCluster.Name, err)), nil
+		}
+	}
+
+	publication := &cnpgv1.Publication{
+		ObjectMeta: r.migrationObjectMeta(documentdb),
+		Spec: cnpgv1.PublicationSpec{
+			ClusterRef: corev1.LocalObjectReference{Name: sourceCluster.Name},
+			Name:       migrationReplicationName,
+			DBName:     "postgres",
+			Target: cnpgv1.PublicationTarget{Objects: []cnpgv1.PublicationTargetObject{
+				{TablesInSchema: migrationDataSchema},
+				{TablesInSchema: migrationCatalogSchema},
+			}},
+			ReclaimPolicy: cnpgv1.PublicationReclaimDelete,
+		},
+	}
+	subscription := &cnpgv1.Subscription{
+		ObjectMeta: r.migrationObjectMeta(documentdb),
+		Spec: cnpgv1.SubscriptionSpec{
+			ClusterRef:          corev1.LocalObjectReference{Name: cnpgCluster.Name},
+			Name:                migrationReplicationName,
+			DBName:              "postgres",
+			PublicationName:     migrationReplicationName,
+			ExternalClusterName: cnpg.MigrationSourceName,
+			ReclaimPolicy:       cnpgv1.SubscriptionReclaimDelete,
+		},
+	}
+	for _, obj := range []client.Object{publication, subscription} {
+		if err := controllerutil.SetControllerReference(documentdb, obj, r.Scheme); err != nil {
+			return false, fmt.Errorf("failed to set owner reference: %w", err)
+		}
+		if err := r.Create(ctx, obj); err != nil && !errors.IsAlreadyExists(err) {
+			return false, fmt.Errorf("failed to create migration %T: %w", obj, err)
+		}
+	}
+
+	log.FromContext(ctx).Info("Started migration", "source", sourceCluster.Name)
+	if r.Recorder != nil {
+		r.Recorder.Event(documentdb, corev1.EventTypeNormal, "MigrationSyncing",
+			fmt.Sprintf("Replicating DocumentDB %s", documentdb.Spec.Migration.SourceRef.Name))
+	}
+	now := metav1.Now()
+	documentdb.Status.Migration = &dbpreview.MigrationStatus{
+		Phase:     dbpreview.MigrationPhaseSyncing,
+		StartedAt: &now,
+	}
+	return true, nil
+}
+
+// migrationSourceSchema is the output of migrationSourceSchemaQuery.
+type migrationSourceSchema struct {
+	Tables  []string `json:"tables"`
+	Indexes map[string]struct {
+		Table      string `json:"table"`
+		Definition string `json:"definition"`
+	} `json:"indexes"`
+}
+
+// migrationTargetSchema is the output of migrationTargetSchemaQuery.
+type migrationTargetSchema struct {
+	Tables       []string `json:"tables"`
+	Indexes      []string `json:"indexes"`
+	Subscription bool     `json:"subscription"`
+	Subscribed   []string `json:"subscribed"`
+	Copying      int      `json:"copying"`
+}
+
+// syncMigrationSchema copies to the target the collection tables and the
+// indexes created on the source since the migration started, which logical
+// replication does not copy, and refreshes the subscription so that it
+// copies and replicates the new tables. It returns whether the target has
+// all the tables and indexes of the source and the subscription has copied
+// all its tables.
+func (r *DocumentDBReconciler) syncMigrationSchema(ctx context.Context, sourceCluster, cnpgCluster *cnpgv1.Cluster) (bool, error) {
+	output, err := r.SQLExecutor(ctx, sourceCluster, migrationSourceSchemaQuery)
+	if err != nil {
+		return false, fmt.Errorf("failed to read the collections of the migration source: %w", err)
+	}
+	source := migrationSourceSchema{}
+	if err := parseJSONRowFromOutput(output, &source); err != nil {
+		return false, fmt.Errorf("failed to parse the collections of the migration source: %w", err)
+	}
+	output, err = r.SQLExecutor(ctx, cnpgCluster, migrationTargetSchemaQuery)
+	if err != nil {
+		return false, fmt.Errorf("failed to read the collections of the migration target: %w", err)
+	}
+	target := migrationTargetSchema{}
+	if err := parseJSONRowFromOutput(output, &target); err != nil {
+		return false, fmt.Errorf("failed to parse the collections of the migration target: %w", err)
+	}
+	// CNPG has not created the subscription yet, which copies the tables of
+	// the publication when it is created
+	if !target.Subscription {
+		return false, nil
+	}
+
+	missingTables := missingNames(source.Tables, target.Tables)
+	if len(missingTables) > 0 {
+		if err := r.MigrationSchemaCopier(ctx, sourceCluster, cnpgCluster, missingTables); err != nil {
+			return false, fmt.Errorf("failed to copy the new collections of the migration source: %w", err)
+		}
+		log.FromContext(ctx).Info("Copied the new collections of the migration source", "tables", missingTables)
+	}
+
+	// The indexes of the new tables were copied with them
+	var definitions []string
+	for _, name := range missingNames(slices.Sorted(maps.Keys(source.Indexes)), target.Indexes) {
+		if index := source.Indexes[name]; !slices.Contains(missingTables, index.Table) {
+			definitions = append(definitions, index.Definition+";")
+		}
+	}
+	if len(definitions) > 0 {
+		if _, err := r.SQLExecutor(ctx, cnpgCluster, strings.Join(definitions, "\n")); err != nil {
+			return false, fmt.Errorf("failed to copy the new indexes of the migration source: %w", err)
+		}
+		log.FromContext(ctx).Info("Copied the new indexes of the migration source", "indexes", len(definitions))
+	}
+
+	if len(missingNames(source.Tables, target.Subscribed)) > 0 {
+		if _, err := r.SQLExecutor(ctx, cnpgCluster, migrationRefreshCommand); err != nil {
+			return false, fmt.Errorf("failed to refresh the migration subscription: %w", err)
+		}
+		log.FromContext(ctx).Info("Refreshed the migration subscription")
+		return false, nil
+	}
+	return len(missingTables) == 0 && len(definitions) == 0 && target.Copying == 0, nil
+}
+
+// missingNames returns the names of names that are not in existing.
+func missingNames(names, existing []string) []string {
+	var missing []string
+	for _, name := range names {
+		if !slices.Contains(existing, name) {
+			missing = append(missing, name)
+		}
+	}
+	return missing
+}
+
+// completeMigration brings the sequences of the extension to their value on
+// the source, routes the Service of the source to this cluster, saves the
+// completed migration and stops the replication.
+func (r *DocumentDBReconciler) completeMigration(ctx context.Context, documentdb, source *dbpreview.DocumentDB, sourceCluster, cnpgCluster *cnpgv1.Cluster) error {
+	output, err := r.SQLExecutor(ctx, sourceCluster, migrationSequencesQuery)
+	if err != nil {
+		return fmt.Errorf("failed to read the sequences of the migration source: %w", err)
+	}
+	if setvals, ok := parseSingleValueFromOutput(output); ok && setvals != "" {
+		if _, err := r.SQLExecutor(ctx, cnpgCluster, setvals); err != nil {
+			return fmt.Errorf("failed to copy the sequences of the migration source: %w", err)
+		}
+	}
+
+	if source.Annotations[util.MIGRATED_TO_ANNOTATION] != documentdb.Name {
+		patch := client.MergeFrom(source.DeepCopy())
+		if source.Annotations == nil {
+			source.Annotations = map[string]string{}
+		}
+		source.Annotations[util.MIGRATED_TO_ANNOTATION] = documentdb.Name
+		if err := r.Patch(ctx, source, patch); err != nil {
+			return fmt.Errorf("failed to annotate the migration source: %w", err)
+		}
+	}
+	service := &corev1.Service{}
+	err = r.Get(ctx, types.NamespacedName{Name: util.DocumentDBServiceName(source), Namespace: source.Namespace}, service)
+	if err != nil && !errors.IsNotFound(err) {
+		return fmt.Errorf("failed to get the Service of the migration source: %w", err)
+	}
+	// The source recreates a missing Service with the annotated selector
+	if err == nil && service.Spec.Selector != nil && service.Spec.Selector[util.LABEL_APP] != documentdb.Name {
+		patch := client.MergeFrom(service.DeepCopy())
+		service.Spec.Selector[util.LABEL_APP] = documentdb.Name
+		if err := r.Patch(ctx, service, patch); err != nil {
+			return fmt.Errorf("failed to route the Service of the migration source: %w", err)
+		}
+	}
+
+	// The phase is saved before the replication slot is dropped with the
+	// subscription: without the slot, the lag of a cut over migration
+	// cannot be read again
+	now := metav1.Now()
+	documentdb.Status.Migration.Phase = dbpreview.MigrationPhaseCompleted
+	documentdb.Status.Migration.CompletedAt = &now
+	documentdb.Status.Migration.Message = ""
+	if err := r.updateStatus(ctx, documentdb); err != nil {
+		return fmt.Errorf("failed to save the completed migration: %w", err)
+	}
+	log.FromContext(ctx).Info("Completed migration", "source", source.Name)
+	if r.Recorder != nil {
+		r.Recorder.Event(documentdb, corev1.EventTypeNormal, "MigrationCompleted",
+			fmt.Sprintf("Service %s now routes to this cluster", service.Name))
+	}
+	return r.deleteMigrationReplication(ctx, documentdb)
+}
+
+// migrationLag returns the replication lag of the subscription, in bytes,
+// and whether its replication slot exists on the source yet.
+func (r *DocumentDBReconciler) migrationLag(ctx context.Context, sourceCluster *cnpgv1.Cluster) (int64, bool, error) {
+	output, err := r.SQLExecutor(ctx, sourceCluster, migrationLagQuery)
+	if err != nil {
+		return 0, false, fmt.Errorf("failed to read the migration lag: %w", err)
+	}
+	value, ok := parseSingleValueFromOutput(output)
+	if !ok {
+		return 0, false, nil
+	}
+	lag, err := strconv.ParseInt(value, 10, 64)
+	if err != nil {
+		return 0, false, nil
+	}
+	return lag, true, nil
+}
+
+// migrationSubscriptionMessage returns why CNPG could not apply the
+// Subscription, if it could not.
+func (r *DocumentDBReconciler) migrationSubscriptionMessage(ctx context.Context, documentdb *dbpreview.DocumentDB) string {
+	subscription := &cnpgv1.Subscription{}
+	key := types.NamespacedName{Name: migrationObjectName(documentdb.Name), Namespace: documentdb.Namespace}
+	if err := r.Get(ctx, key, subscription); err != nil || ptr.Deref(subscription.Status.Applied, true) {
+		return ""
+	}
+	return subscription.Status.Message
+}
+
+// deleteMigrationReplication deletes the Publication and the Subscription of
+// spec.migration. CNPG drops the subscription, and with it the replication
+// slot on the source, before the publication.
+func (r *DocumentDBReconciler) deleteMigrationReplication(ctx context.Context, documentdb *dbpreview.DocumentDB) error {
+	key := types.NamespacedName{Name: migrationObjectName(documentdb.Name), Namespace: documentdb.Namespace}
+	for _, obj := range []client.Object{&cnpgv1.Subscription{}, &cnpgv1.Publication{}} {
+		if err := r.Get(ctx, key, obj); err != nil {
+			if errors.IsNotFound(err) {
+				continue
+			}
+			return fmt.Errorf("failed to get migration %T: %w", obj, err)
+		}
+		if !metav1.IsControlledBy(obj, documentdb) {
+			continue
+		}
+		if err := r.Delete(ctx, obj); err != nil && !errors.IsNotFound(err) {
+			return fmt.Errorf("failed to delete migration %T: %w", obj, err)
+		}
+		log.FromContext(ctx).Info("Deleted migration replication object", "kind", fmt.Sprintf("%T", obj), "name", key.Name)
+	}
+	return nil
+}
+
+// failMigration marks the migration failed and returns true, the status
+// having changed.
+func (r *DocumentDBReconciler) failMigration(documentdb *dbpreview.DocumentDB, message string) bool {
+	r.recordMigrationWarning(documentdb, "MigrationFailed", message)
+	if documentdb.Status.Migration == nil {
+		documentdb.Status.Migration = &dbpreview.MigrationStatus{}
+	}
+	documentdb.Status.Migration.Phase = dbpreview.MigrationPhaseFailed
+	documentdb.Status.Migration.Message = message
+	return true
+}
+
+func (r *DocumentDBReconciler) recordMigrationWarning(documentdb *dbpreview.DocumentDB, reason, message string) {
+	if r.Recorder != nil {
+		r.Recorder.Event(documentdb, corev1.EventTypeWarning, reason, message)
+	}
+}
+
+func (r *DocumentDBReconciler) migrationObjectMeta(documentdb *dbpreview.DocumentDB) metav1.ObjectMeta {
+	return metav1.ObjectMeta{
+		Name:        migrationObjectName(documentdb.Name),
+		Namespace:   documentdb.Namespace,
+		Labels:      util.ChildLabels(documentdb, map[string]string{util.LABEL_DOCUMENTDB_NAME: documentdb.Name}),
+		Annotations: util.ChildAnnotations(documentdb, nil),
+	}
+}
+
+// copyMigrationSchema creates the collection tables of the source primary on
+// the target primary, all of them or only tables when it is not empty, by
+// piping a schema-only pg_dump of the source into psql on the target. The
+// extension already created their schema on the target.
+func (r *DocumentDBReconciler) copyMigrationSchema(ctx context.Context, source, target *cnpgv1.Cluster, tables []string) error {
+	command := []string{"pg_dump", "-U", "postgres", "-d", "postgres", "--schema-only", "--no-owner", "-n", migrationDataSchema}
+	for _, table := range tables {
+		command = append(command, "-t", migrationDataSchema+"."+table)
+	}
+	dump, stderr, err := r.execInPrimary(ctx, source, command, nil)
+	if err != nil {
+		return fmt.Errorf("pg_dump failed: %w (stderr: %s)", err, stderr)
+	}
+	dump = strings.ReplaceAll(dump, "CREATE SCHEMA "+migrationDataSchema+";", "CREATE SCHEMA IF NOT EXISTS "+migrationDataSchema+";")
+	_, stderr, err = r.execInPrimary(ctx, target, []string{
+		"psql", "-U", "postgres", "-d", "postgres", "-v", "ON_ERROR_STOP=1", "-f", "-",
+	}, strings.NewReader(dump))
+	if err != nil {
+		return fmt.Errorf("psql failed: %w (stderr: %s)", err, stderr)
+	}
+	return nil
+}
+
+// parseSingleValueFromOutput returns the value of a single-column, single-row
+// psql result, or false when the query returned no row.
+func parseSingleValueFromOutput(output string) (string, bool) {
+	lines := strings.Split(strings.TrimSpace(output), "\n")
+	if len(lines) < 3 || strings.HasPrefix(strings.TrimSpace(lines[2]), "(") {
+		return "", false
+	}
+	return strings.TrimSpace(lines[2]), true
+}
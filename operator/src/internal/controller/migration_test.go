@@ -0,0 +1,261 @@
+// Copyright (c) Microsoft Corporation.
+// Licensed under the MIT License.
+
+package controller
+
+import (
+	"context"
+	"strings"
+
+	cnpgv1 "github.com/cloudnative-pg/cloudnative-pg/api/v1"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/client-go/tools/record"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	dbpreview "github.com/documentdb/documentdb-operator/api/preview"
+	"github.com/documentdb/documentdb-operator/internal/cnpg"
+	util "github.com/documentdb/documentdb-operator/internal/utils"
+)
+
+var _ = Describe("Migration", func() {
+	const namespace = "default"
+
+	var (
+		ctx           context.Context
+		recorder      *record.FakeRecorder
+		reconciler    *DocumentDBReconciler
+		documentdb    *dbpreview.DocumentDB
+		cnpgCluster   *cnpgv1.Cluster
+		copiedSchemas []string
+		copiedTables  [][]string
+		executed      map[string][]string
+		lag           string
+		sourceSchema  string
+		targetSchema  string
+	)
+
+	BeforeEach(func() {
+		ctx = context.Background()
+		scheme := runtime.NewScheme()
+		Expect(dbpreview.AddToScheme(scheme)).To(Succeed())
+		Expect(cnpgv1.AddToScheme(scheme)).To(Succeed())
+		Expect(corev1.AddToScheme(scheme)).To(Succeed())
+		recorder = record.NewFakeRecorder(10)
+		copiedSchemas = nil
+		copiedTables = nil
+		executed = map[string][]string{}
+		lag = "4096"
+		sourceSchema = `{"tables" : ["documents_1"], "indexes" : {"documents_1_pkey" : {"table" : "documents_1", "definition" : "ALTER TABLE documentdb_data.documents_1 ADD CONSTRAINT documents_1_pkey PRIMARY KEY (shard_key_value, object_id)"}}}`
+		targetSchema = `{"tables" : ["documents_1"], "indexes" : ["documents_1_pkey"], "subscription" : true, "subscribed" : ["documents_1"], "copying" : 0}`
+
+		source := &dbpreview.DocumentDB{ObjectMeta: metav1.ObjectMeta{Name: "blue", Namespace: namespace}}
+		sourceCluster := &cnpgv1.Cluster{
+			ObjectMeta: metav1.ObjectMeta{Name: "blue", Namespace: namespace},
+			Status:     cnpgv1.ClusterStatus{Phase: cnpgv1.PhaseHealthy},
+		}
+		sourceService := &corev1.Service{
+			ObjectMeta: metav1.ObjectMeta{Name: util.DocumentDBServiceName(source), Namespace: namespace},
+			Spec: corev1.ServiceSpec{Selector: map[string]string{
+				util.LABEL_APP:         "blue",
+				"cnpg.io/instanceRole": "primary",
+			}},
+		}
+		documentdb = &dbpreview.DocumentDB{
+			ObjectMeta: metav1.ObjectMeta{Name: "green", Namespace: namespace, UID: "uid"},
+			Spec: dbpreview.DocumentDBSpec{
+				Migration: &dbpreview.MigrationConfiguration{SourceRef: cnpgv1.LocalObjectReference{Name: "blue"}},
+			},
+		}
+		cnpgCluster = &cnpgv1.Cluster{
+			ObjectMeta: metav1.ObjectMeta{Name: "green", Namespace: namespace},
+			Status:     cnpgv1.ClusterStatus{Phase: cnpgv1.PhaseHealthy},
+		}
+
+		reconciler = &DocumentDBReconciler{
+			Client: fake.NewClientBuilder().WithScheme(scheme).
+				WithObjects(documentdb, source, sourceCluster, sourceService).
+				WithStatusSubresource(&dbpreview.DocumentDB{}).
+				Build(),
+			Scheme:   scheme,
+			Recorder: recorder,
+			SQLExecutor: func(_ context.Context, cluster *cnpgv1.Cluster, sql string) (string, error) {
+				executed[cluster.Name] = append(executed[cluster.Name], sql)
+				switch {
+				case sql == migrationLagQuery:
+					return " pg_wal_lsn_diff \n-----------------\n " + lag + "\n(1 row)\n", nil
+				case sql == migrationSequencesQuery:
+					return " string_agg \n------------\n SELECT setval('documentdb_api_catalog.collection_id_seq', 7);\n(1 row)\n", nil
+				case sql == migrationSourceSchemaQuery:
+					return " json_build_object \n-------------------\n " + sourceSchema + "\n(1 row)\n", nil
+				case sql == migrationTargetSchemaQuery:
+					return " json_build_object \n-------------------\n " + targetSchema + "\n(1 row)\n", nil
+				}
+				return "", nil
+			},
+			MigrationSchemaCopier: func(_ context.Context, source, target *cnpgv1.Cluster, tables []string) error {
+				copiedSchemas = append(copiedSchemas, source.Name+"->"+target.Name)
+				copiedTables = append(copiedTables, tables)
+				return nil
+			},
+		}
+	})
+
+	key := client.ObjectKey{Name: "green-migration", Namespace: namespace}
+
+	It("copies the schema and replicates the source", func() {
+		changed, err := reconciler.reconcileMigration(ctx, documentdb, cnpgCluster)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(changed).To(BeTrue())
+		Expect(copiedSchemas).To(Equal([]string{"blue->green"}))
+		Expect(documentdb.Status.Migration.Phase).To(Equal(dbpreview.MigrationPhaseSyncing))
+		Expect(recorder.Events).To(Receive(ContainSubstring("MigrationSyncing")))
+
+		publication := &cnpgv1.Publication{}
+		Expect(reconciler.Get(ctx, key, publication)).To(Succeed())
+		Expect(publication.Spec.ClusterRef.Name).To(Equal("blue"))
+		Expect(publication.Spec.Target.Objects).To(HaveLen(2))
+		subscription := &cnpgv1.Subscription{}
+		Expect(reconciler.Get(ctx, key, subscription)).To(Succeed())
+		Expect(subscription.Spec.ClusterRef.Name).To(Equal("green"))
+		Expect(subscription.Spec.ExternalClusterName).To(Equal(cnpg.MigrationSourceName))
+
+		changed, err = reconciler.reconcileMigration(ctx, documentdb, cnpgCluster)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(changed).To(BeTrue())
+		Expect(*documentdb.Status.Migration.LagBytes).To(Equal(int64(4096)))
+		Expect(copiedSchemas).To(HaveLen(1))
+	})
+
+	It("copies the collections and indexes created on the source during the migration", func() {
+		_, err := reconciler.reconcileMigration(ctx, documentdb, cnpgCluster)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(copiedTables).To(Equal([][]string{nil}))
+
+		sourceSchema = `{"tables" : ["documents_1", "documents_2"], "indexes" : {` +
+			`"documents_1_pkey" : {"table" : "documents_1", "definition" : "ALTER TABLE documentdb_data.documents_1 ADD CONSTRAINT documents_1_pkey PRIMARY KEY (shard_key_value, object_id)"}, ` +
+			`"documents_rum_index_3" : {"table" : "documents_1", "definition" : "CREATE INDEX documents_rum_index_3 ON documentdb_data.documents_1 USING documentdb_rum (document)"}, ` +
+			`"documents_2_pkey" : {"table" : "documents_2", "definition" : "ALTER TABLE documentdb_data.documents_2 ADD CONSTRAINT documents_2_pkey PRIMARY KEY (shard_key_value, object_id)"}}}`
+		_, err = reconciler.reconcileMigration(ctx, documentdb, cnpgCluster)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(copiedTables).To(Equal([][]string{nil, {"documents_2"}}))
+		Expect(executed["green"]).To(ContainElement("CREATE INDEX documents_rum_index_3 ON documentdb_data.documents_1 USING documentdb_rum (document);"))
+		Expect(executed["green"]).To(ContainElement(migrationRefreshCommand))
+	})
+
+	It("waits for the subscription to copy its tables before completing the cutover", func() {
+		_, err := reconciler.reconcileMigration(ctx, documentdb, cnpgCluster)
+		Expect(err).ToNot(HaveOccurred())
+		documentdb.Spec.Migration.Cutover = true
+		_, err = reconciler.reconcileMigration(ctx, documentdb, cnpgCluster)
+		Expect(err).ToNot(HaveOccurred())
+
+		lag = "0"
+		targetSchema = `{"tables" : ["documents_1"], "indexes" : ["documents_1_pkey"], "subscription" : true, "subscribed" : ["documents_1"], "copying" : 1}`
+		_, err = reconciler.reconcileMigration(ctx, documentdb, cnpgCluster)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(documentdb.Status.Migration.Phase).To(Equal(dbpreview.MigrationPhaseCuttingOver))
+	})
+
+	It("waits for the cluster to be healthy", func() {
+		cnpgCluster.Status.Phase = "Setting up primary"
+		changed, err := reconciler.reconcileMigration(ctx, documentdb, cnpgCluster)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(changed).To(BeFalse())
+		Expect(copiedSchemas).To(BeEmpty())
+	})
+
+	It("fails when the schema cannot be copied", func() {
+		reconciler.MigrationSchemaCopier = func(context.Context, *cnpgv1.Cluster, *cnpgv1.Cluster, []string) error {
+			return apierrors.NewBadRequest("relation already exists")
+		}
+		changed, err := reconciler.reconcileMigration(ctx, documentdb, cnpgCluster)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(changed).To(BeTrue())
+		Expect(documentdb.Status.Migration.Phase).To(Equal(dbpreview.MigrationPhaseFailed))
+		Expect(recorder.Events).To(Receive(ContainSubstring("Warning MigrationFailed")))
+		Expect(apierrors.IsNotFound(reconciler.Get(ctx, key, &cnpgv1.Subscription{}))).To(BeTrue())
+	})
+
+	It("cuts over once the read-only source is caught up", func() {
+		_, err := reconciler.reconcileMigration(ctx, documentdb, cnpgCluster)
+		Expect(err).ToNot(HaveOccurred())
+
+		documentdb.Spec.Migration.Cutover = true
+		_, err = reconciler.reconcileMigration(ctx, documentdb, cnpgCluster)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(documentdb.Status.Migration.Phase).To(Equal(dbpreview.MigrationPhaseCuttingOver))
+		Expect(executed["blue"]).To(ContainElement(migrationReadOnlyCommand))
+
+		_, err = reconciler.reconcileMigration(ctx, documentdb, cnpgCluster)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(documentdb.Status.Migration.Phase).To(Equal(dbpreview.MigrationPhaseCuttingOver))
+
+		lag = "0"
+		changed, err := reconciler.reconcileMigration(ctx, documentdb, cnpgCluster)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(changed).To(BeTrue())
+		Expect(documentdb.Status.Migration.Phase).To(Equal(dbpreview.MigrationPhaseCompleted))
+		Expect(documentdb.Status.Migration.CompletedAt).ToNot(BeNil())
+		Expect(executed["green"]).To(ContainElement(HavePrefix("SELECT setval(")))
+
+		source := &dbpreview.DocumentDB{}
+		Expect(reconciler.Get(ctx, client.ObjectKey{Name: "blue", Namespace: namespace}, source)).To(Succeed())
+		Expect(source.Annotations).To(HaveKeyWithValue(util.MIGRATED_TO_ANNOTATION, "green"))
+		service := &corev1.Service{}
+		Expect(reconciler.Get(ctx, client.ObjectKey{Name: util.DocumentDBServiceName(source), Namespace: namespace}, service)).To(Succeed())
+		Expect(service.Spec.Selector).To(HaveKeyWithValue(util.LABEL_APP, "green"))
+		Expect(apierrors.IsNotFound(reconciler.Get(ctx, key, &cnpgv1.Subscription{}))).To(BeTrue())
+		Expect(apierrors.IsNotFound(reconciler.Get(ctx, key, &cnpgv1.Publication{}))).To(BeTrue())
+
+		stored := &dbpreview.DocumentDB{}
+		Expect(reconciler.Get(ctx, client.ObjectKeyFromObject(documentdb), stored)).To(Succeed())
+		Expect(stored.Status.Migration.Phase).To(Equal(dbpreview.MigrationPhaseCompleted))
+	})
+
+	It("deletes the replication left by a completed migration", func() {
+		_, err := reconciler.reconcileMigration(ctx, documentdb, cnpgCluster)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(reconciler.Get(ctx, key, &cnpgv1.Subscription{})).To(Succeed())
+
+		documentdb.Status.Migration.Phase = dbpreview.MigrationPhaseCompleted
+		changed, err := reconciler.reconcileMigration(ctx, documentdb, cnpgCluster)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(changed).To(BeFalse())
+		Expect(apierrors.IsNotFound(reconciler.Get(ctx, key, &cnpgv1.Subscription{}))).To(BeTrue())
+		Expect(apierrors.IsNotFound(reconciler.Get(ctx, key, &cnpgv1.Publication{}))).To(BeTrue())
+	})
+
+	It("stops the replication when spec.migration is removed", func() {
+		_, err := reconciler.reconcileMigration(ctx, documentdb, cnpgCluster)
+		Expect(err).ToNot(HaveOccurred())
+
+		documentdb.Spec.Migration = nil
+		changed, err := reconciler.reconcileMigration(ctx, documentdb, cnpgCluster)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(changed).To(BeFalse())
+		Expect(apierrors.IsNotFound(reconciler.Get(ctx, key, &cnpgv1.Subscription{}))).To(BeTrue())
+		Expect(apierrors.IsNotFound(reconciler.Get(ctx, key, &cnpgv1.Publication{}))).To(BeTrue())
+		Expect(migrationInProgress(documentdb)).To(BeFalse())
+	})
+})
+
+var _ = Describe("parseSingleValueFromOutput", func() {
+	It("returns the value of the row", func() {
+		value, ok := parseSingleValueFromOutput(" lag \n-----\n 42\n(1 row)\n")
+		Expect(ok).To(BeTrue())
+		Expect(value).To(Equal("42"))
+	})
+
+	It("reports a result without rows", func() {
+		_, ok := parseSingleValueFromOutput(" lag \n-----\n(0 rows)\n")
+		Expect(ok).To(BeFalse())
+		_, ok = parseSingleValueFromOutput(strings.Repeat("\n", 3))
+		Expect(ok).To(BeFalse())
+	})
+})
@@ -0,0 +1,214 @@
+// Copyright (c) Microsoft Corporation.
+// Licensed under the MIT License.
+
+package controller
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	cnpgv1 "github.com/cloudnative-pg/cloudnative-pg/api/v1"
+	batchv1 "k8s.io/api/batch/v1"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/equality"
+	"k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/utils/ptr"
+	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
+	"sigs.k8s.io/controller-runtime/pkg/log"
+
+	dbpreview "github.com/documentdb/documentdb-operator/api/preview"
+	util "github.com/documentdb/documentdb-operator/internal/utils"
+)
+
+const (
+	// importJobSuffix names the Job, <documentdb>-import, that runs the
+	// import of spec.bootstrap.import.
+	importJobSuffix = "-import"
+
+	// importBackoffLimit is the number of retries of a failing import Pod.
+	importBackoffLimit = 3
+)
+
+// importScript copies the source databases with mongodump and mongorestore.
+// With a database list the Job is indexed and each completion imports the
+// database at its index, so that the succeeded completions count the
+// imported databases. Collections are dropped before they are restored so
+// that a retried completion starts over.
+const importScript = `set -euo pipefail
+if [ -n "$DATABASES" ]; then
+  set -- $DATABASES
+  shift "$JOB_COMPLETION_INDEX"
+  echo "Importing database $1"
+  mongodump --uri="$SOURCE_URI" --db="$1" --archive | mongorestore --uri="$TARGET_URI" --archive --drop
+else
+  echo "Importing every database"
+  mongodump --uri="$SOURCE_URI" --archive | mongorestore --uri="$TARGET_URI" --archive --drop --nsExclude='admin.*' --nsExclude='config.*'
+fi
+`
+
+func importJobName(documentdbName string) string {
+	return documentdbName + importJobSuffix
+}
+
+// reconcileMongoDBImport starts the import Job of spec.bootstrap.import once
+// the cluster is healthy, and reports the progress of the Job in
+// status.import. It returns whether status.import changed. A failed import
+// is retried when its Job is deleted.
+func (r *DocumentDBReconciler) reconcileMongoDBImport(ctx context.Context, documentdb *dbpreview.DocumentDB, cnpgCluster *cnpgv1.Cluster) (bool, error) {
+	bootstrap := documentdb.Spec.Bootstrap
+	if bootstrap == nil || bootstrap.Import == nil || bootstrap.Import.MongoDB == nil {
+		return false, nil
+	}
+	status := documentdb.Status.Import
+	if status != nil && status.Phase == dbpreview.ImportPhaseSucceeded {
+		return false, nil
+	}
+
+	job := &batchv1.Job{}
+	err := r.Get(ctx, types.NamespacedName{Name: importJobName(documentdb.Name), Namespace: documentdb.Namespace}, job)
+	if err == nil {
+		return r.updateImportStatus(documentdb, job), nil
+	}
+	if !errors.IsNotFound(err) {
+		return false, fmt.Errorf("failed to get import Job: %w", err)
+	}
+
+	if cnpgCluster.Status.Phase != cnpgv1.PhaseHealthy {
+		return false, nil
+	}
+	// The Job connects through the connection string Secret, which is only
+	// written once the credential Secret exists.
+	if err := r.Get(ctx, types.NamespacedName{Name: connectionSecretName(documentdb.Name), Namespace: documentdb.Namespace}, &corev1.Secret{}); err != nil {
+		if errors.IsNotFound(err) {
+			return false, nil
+		}
+		return false, fmt.Errorf("failed to get connection string Secret: %w", err)
+	}
+
+	job = r.importJob(documentdb)
+	if err := controllerutil.SetControllerReference(documentdb, job, r.Scheme); err != nil {
+		return false, fmt.Errorf("failed to set owner reference: %w", err)
+	}
+	if err := r.Create(ctx, job); err != nil && !errors.IsAlreadyExists(err) {
+		return false, fmt.Errorf("failed to create import Job: %w", err)
+	}
+	log.FromContext(ctx).Info("Started import", "job", job.Name)
+	if r.Recorder != nil {
+		r.Recorder.Event(documentdb, corev1.EventTypeNormal, "ImportStarted", fmt.Sprintf("Job %s started importing from MongoDB", job.Name))
+	}
+	now := metav1.Now()
+	documentdb.Status.Import = &dbpreview.ImportStatus{
+		Phase:          dbpreview.ImportPhaseRunning,
+		JobName:        job.Name,
+		DatabasesTotal: ptr.Deref(job.Spec.Completions, 1),
+		StartedAt:      &now,
+	}
+	return true, nil
+}
+
+// importJob returns the Job that imports the source of spec.bootstrap.import
+// into documentdb.
+func (r *DocumentDBReconciler) importJob(documentdb *dbpreview.DocumentDB) *batchv1.Job {
+	source := documentdb.Spec.Bootstrap.Import.MongoDB
+	completions := int32(max(len(source.Databases), 1))
+	return &batchv1.Job{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:        importJobName(documentdb.Name),
+			Namespace:   documentdb.Namespace,
+			Labels:      util.ChildLabels(documentdb, map[string]string{util.LABEL_DOCUMENTDB_NAME: documentdb.Name}),
+			Annotations: util.ChildAnnotations(documentdb, nil),
+		},
+		Spec: batchv1.JobSpec{
+			Completions:    ptr.To(completions),
+			Parallelism:    ptr.To(int32(1)),
+			CompletionMode: ptr.To(batchv1.IndexedCompletion),
+			BackoffLimit:   ptr.To(int32(importBackoffLimit)),
+			Template: corev1.PodTemplateSpec{
+				ObjectMeta: metav1.ObjectMeta{
+					Labels: util.ChildLabels(documentdb, map[string]string{util.LABEL_DOCUMENTDB_NAME: documentdb.Name}),
+				},
+				Spec: corev1.PodSpec{
+					RestartPolicy: corev1.RestartPolicyNever,
+					Containers: []corev1.Container{{
+						Name:            "import",
+						Image:           util.GetMongoDBToolsImage(),
+						ImagePullPolicy: documentdb.Spec.ImagePullPolicy,
+						Command:         []string{"/bin/bash", "-c", importScript},
+						Env: []corev1.EnvVar{
+							{
+								Name: "SOURCE_URI",
+								ValueFrom: &corev1.EnvVarSource{SecretKeyRef: &corev1.SecretKeySelector{
+									LocalObjectReference: corev1.LocalObjectReference{Name: source.SourceURISecret.Name},
+									Key:                  source.SourceURISecret.Key,
+								}},
+							},
+							{
+								Name: "TARGET_URI",
+								ValueFrom: &corev1.EnvVarSource{SecretKeyRef: &corev1.SecretKeySelector{
+									LocalObjectReference: corev1.LocalObjectReference{Name: connectionSecretName(documentdb.Name)},
+									Key:                  connectionSecretURIKey,
+								}},
+							},
+							{Name: "DATABASES", Value: strings.Join(source.Databases, " ")},
+						},
+						SecurityContext: &corev1.SecurityContext{
+							AllowPrivilegeEscalation: ptr.To(false),
+							Capabilities:             &corev1.Capabilities{Drop: []corev1.Capability{"ALL"}},
+						},
+					}},
+				},
+			},
+		},
+	}
+}
+
+// updateImportStatus reports the progress of the import Job in
+// status.import and returns whether it changed.
+func (r *DocumentDBReconciler) updateImportStatus(documentdb *dbpreview.DocumentDB, job *batchv1.Job) bool {
+	previous := documentdb.Status.Import
+	status := &dbpreview.ImportStatus{
+		Phase:             dbpreview.ImportPhaseRunning,
+		JobName:           job.Name,
+		DatabasesImported: job.Status.Succeeded,
+		DatabasesTotal:    ptr.Deref(job.Spec.Completions, 1),
+		StartedAt:         job.Status.StartTime,
+	}
+	if previous != nil && previous.StartedAt != nil {
+		status.StartedAt = previous.StartedAt
+	}
+	for _, condition := range job.Status.Conditions {
+		if condition.Status != corev1.ConditionTrue {
+			continue
+		}
+		switch condition.Type {
+		case batchv1.JobComplete:
+			status.Phase = dbpreview.ImportPhaseSucceeded
+		case batchv1.JobFailed:
+			status.Phase = dbpreview.ImportPhaseFailed
+			status.Message = condition.Message
+		default:
+			continue
+		}
+		status.CompletedAt = job.Status.CompletionTime
+		if status.CompletedAt == nil {
+			status.CompletedAt = &condition.LastTransitionTime
+		}
+	}
+	if equality.Semantic.DeepEqual(previous, status) {
+		return false
+	}
+
+	if r.Recorder != nil && (previous == nil || previous.Phase != status.Phase) {
+		switch status.Phase {
+		case dbpreview.ImportPhaseSucceeded:
+			r.Recorder.Event(documentdb, corev1.EventTypeNormal, "ImportSucceeded", fmt.Sprintf("Job %s imported %d of %d databases", job.Name, status.DatabasesImported, status.DatabasesTotal))
+		case dbpreview.ImportPhaseFailed:
+			r.Recorder.Event(documentdb, corev1.EventTypeWarning, "ImportFailed", fmt.Sprintf("Job %s failed: %s. Delete the Job to retry the import", job.Name, status.Message))
+		}
+	}
+	documentdb.Status.Import = status
+	return true
+}
@@ -0,0 +1,154 @@
+// Copyright (c) Microsoft Corporation.
+// Licensed under the MIT License.
+
+package controller
+
+import (
+	"context"
+
+	cnpgv1 "github.com/cloudnative-pg/cloudnative-pg/api/v1"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	batchv1 "k8s.io/api/batch/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/client-go/tools/record"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	dbpreview "github.com/documentdb/documentdb-operator/api/preview"
+)
+
+var _ = Describe("MongoDB import", func() {
+	const namespace = "default"
+
+	var (
+		ctx        context.Context
+		recorder   *record.FakeRecorder
+		reconciler *DocumentDBReconciler
+		documentdb *dbpreview.DocumentDB
+		cluster    *cnpgv1.Cluster
+	)
+
+	BeforeEach(func() {
+		ctx = context.Background()
+		scheme := runtime.NewScheme()
+		Expect(dbpreview.AddToScheme(scheme)).To(Succeed())
+		Expect(cnpgv1.AddToScheme(scheme)).To(Succeed())
+		Expect(corev1.AddToScheme(scheme)).To(Succeed())
+		Expect(batchv1.AddToScheme(scheme)).To(Succeed())
+		recorder = record.NewFakeRecorder(10)
+
+		documentdb = &dbpreview.DocumentDB{
+			ObjectMeta: metav1.ObjectMeta{Name: "db", Namespace: namespace, UID: "uid"},
+			Spec: dbpreview.DocumentDBSpec{
+				Bootstrap: &dbpreview.BootstrapConfiguration{
+					Import: &dbpreview.ImportConfiguration{MongoDB: &dbpreview.MongoDBImportConfiguration{
+						SourceURISecret: cnpgv1.SecretKeySelector{LocalObjectReference: cnpgv1.LocalObjectReference{Name: "source"}, Key: "uri"},
+						Databases:       []string{"orders", "users"},
+					}},
+				},
+			},
+		}
+		cluster = &cnpgv1.Cluster{
+			ObjectMeta: metav1.ObjectMeta{Name: "db", Namespace: namespace},
+			Status:     cnpgv1.ClusterStatus{Phase: cnpgv1.PhaseHealthy},
+		}
+		reconciler = &DocumentDBReconciler{
+			Client: fake.NewClientBuilder().WithScheme(scheme).WithObjects(
+				&corev1.Secret{ObjectMeta: metav1.ObjectMeta{Name: connectionSecretName("db"), Namespace: namespace}},
+			).Build(),
+			Scheme:   scheme,
+			Recorder: recorder,
+		}
+	})
+
+	getJob := func() *batchv1.Job {
+		job := &batchv1.Job{}
+		Expect(reconciler.Get(ctx, client.ObjectKey{Name: importJobName("db"), Namespace: namespace}, job)).To(Succeed())
+		return job
+	}
+
+	setJobStatus := func(status batchv1.JobStatus) {
+		job := getJob()
+		job.Status = status
+		Expect(reconciler.Status().Update(ctx, job)).To(Succeed())
+	}
+
+	It("does nothing without spec.bootstrap.import", func() {
+		documentdb.Spec.Bootstrap = nil
+		changed, err := reconciler.reconcileMongoDBImport(ctx, documentdb, cluster)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(changed).To(BeFalse())
+	})
+
+	It("waits for the cluster to be healthy", func() {
+		cluster.Status.Phase = cnpgv1.PhaseFirstPrimary
+		changed, err := reconciler.reconcileMongoDBImport(ctx, documentdb, cluster)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(changed).To(BeFalse())
+		Expect(documentdb.Status.Import).To(BeNil())
+	})
+
+	It("starts an indexed Job with a completion per database", func() {
+		changed, err := reconciler.reconcileMongoDBImport(ctx, documentdb, cluster)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(changed).To(BeTrue())
+		Expect(documentdb.Status.Import.Phase).To(Equal(dbpreview.ImportPhaseRunning))
+		Expect(documentdb.Status.Import.DatabasesTotal).To(Equal(int32(2)))
+		Expect(recorder.Events).To(Receive(ContainSubstring("ImportStarted")))
+
+		job := getJob()
+		Expect(*job.Spec.Completions).To(Equal(int32(2)))
+		Expect(*job.Spec.CompletionMode).To(Equal(batchv1.IndexedCompletion))
+		Expect(job.OwnerReferences).To(HaveLen(1))
+		Expect(job.Spec.Template.Spec.Containers[0].Env).To(ContainElement(corev1.EnvVar{Name: "DATABASES", Value: "orders users"}))
+	})
+
+	It("reports the progress and the success of the Job", func() {
+		_, err := reconciler.reconcileMongoDBImport(ctx, documentdb, cluster)
+		Expect(err).ToNot(HaveOccurred())
+
+		setJobStatus(batchv1.JobStatus{Succeeded: 1})
+		changed, err := reconciler.reconcileMongoDBImport(ctx, documentdb, cluster)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(changed).To(BeTrue())
+		Expect(documentdb.Status.Import.DatabasesImported).To(Equal(int32(1)))
+
+		changed, err = reconciler.reconcileMongoDBImport(ctx, documentdb, cluster)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(changed).To(BeFalse())
+
+		setJobStatus(batchv1.JobStatus{
+			Succeeded:      2,
+			CompletionTime: &metav1.Time{},
+			Conditions:     []batchv1.JobCondition{{Type: batchv1.JobComplete, Status: corev1.ConditionTrue}},
+		})
+		_, err = reconciler.reconcileMongoDBImport(ctx, documentdb, cluster)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(documentdb.Status.Import.Phase).To(Equal(dbpreview.ImportPhaseSucceeded))
+		Expect(documentdb.Status.Import.CompletedAt).ToNot(BeNil())
+	})
+
+	It("reports a failed Job and retries once it is deleted", func() {
+		_, err := reconciler.reconcileMongoDBImport(ctx, documentdb, cluster)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(recorder.Events).To(Receive())
+
+		setJobStatus(batchv1.JobStatus{Conditions: []batchv1.JobCondition{{
+			Type: batchv1.JobFailed, Status: corev1.ConditionTrue, Message: "Job has reached the specified backoff limit",
+		}}})
+		_, err = reconciler.reconcileMongoDBImport(ctx, documentdb, cluster)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(documentdb.Status.Import.Phase).To(Equal(dbpreview.ImportPhaseFailed))
+		Expect(documentdb.Status.Import.Message).To(ContainSubstring("backoff limit"))
+		Expect(recorder.Events).To(Receive(ContainSubstring("Warning ImportFailed")))
+
+		Expect(reconciler.Delete(ctx, getJob())).To(Succeed())
+		_, err = reconciler.reconcileMongoDBImport(ctx, documentdb, cluster)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(documentdb.Status.Import.Phase).To(Equal(dbpreview.ImportPhaseRunning))
+		getJob()
+	})
+})
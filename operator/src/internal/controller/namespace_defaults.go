@@ -0,0 +1,32 @@
+// Copyright (c) Microsoft Corporation.
+// Licensed under the MIT License.
+
+package controller
+
+import (
+	"context"
+	"fmt"
+
+	dbpreview "github.com/documentdb/documentdb-operator/api/preview"
+	util "github.com/documentdb/documentdb-operator/internal/utils"
+)
+
+// applyNamespaceDefaults fills the fields of documentdb left unset by it and
+// its cluster class from the defaults of its namespace, in memory as the
+// cluster class, so that the stored spec stays the one the user applied. The
+// defaults are read the first time and saved in status.namespaceDefaults:
+// changing them later does not touch the cluster.
+func (r *DocumentDBReconciler) applyNamespaceDefaults(ctx context.Context, documentdb *dbpreview.DocumentDB) error {
+	recorded := documentdb.Status.NamespaceDefaults != nil
+	if err := util.ApplyNamespaceDefaults(ctx, r.Client, documentdb); err != nil {
+		return err
+	}
+	if recorded {
+		return nil
+	}
+	if err := r.updateStatus(ctx, documentdb); err != nil {
+		documentdb.Status.NamespaceDefaults = nil
+		return fmt.Errorf("failed to save the namespace defaults: %w", err)
+	}
+	return nil
+}
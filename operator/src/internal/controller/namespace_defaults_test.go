@@ -0,0 +1,115 @@
+// Copyright (c) Microsoft Corporation.
+// Licensed under the MIT License.
+
+package controller
+
+import (
+	"context"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	dbpreview "github.com/documentdb/documentdb-operator/api/preview"
+	util "github.com/documentdb/documentdb-operator/internal/utils"
+)
+
+var _ = Describe("Namespace defaults", func() {
+	const namespace = "team-a"
+
+	var (
+		ctx      context.Context
+		scheme   *runtime.Scheme
+		defaults *corev1.ConfigMap
+	)
+
+	BeforeEach(func() {
+		ctx = context.Background()
+		scheme = runtime.NewScheme()
+		Expect(dbpreview.AddToScheme(scheme)).To(Succeed())
+		Expect(corev1.AddToScheme(scheme)).To(Succeed())
+		defaults = &corev1.ConfigMap{
+			ObjectMeta: metav1.ObjectMeta{Name: util.NAMESPACE_DEFAULTS_CONFIG_MAP, Namespace: namespace},
+			Data: map[string]string{
+				util.DEFAULT_STORAGE_CLASS_ENV: "fast",
+				util.DEFAULT_SERVICE_TYPE_ENV:  "ClusterIP",
+			},
+		}
+	})
+
+	newClient := func(objs ...client.Object) client.Client {
+		return fake.NewClientBuilder().WithScheme(scheme).
+			WithObjects(objs...).
+			WithStatusSubresource(&dbpreview.DocumentDB{}).
+			Build()
+	}
+
+	It("applies the defaults in memory and records them in the status", func() {
+		documentdb := &dbpreview.DocumentDB{ObjectMeta: metav1.ObjectMeta{Name: "db", Namespace: namespace}}
+		c := newClient(defaults, documentdb)
+		reconciler := &DocumentDBReconciler{Client: c, Scheme: scheme}
+
+		Expect(reconciler.applyNamespaceDefaults(ctx, documentdb)).To(Succeed())
+		Expect(documentdb.Spec.Resource.Storage.StorageClass).To(Equal("fast"))
+		Expect(documentdb.Spec.ExposeViaService.ServiceType).To(Equal("ClusterIP"))
+
+		stored := &dbpreview.DocumentDB{}
+		key := types.NamespacedName{Name: "db", Namespace: namespace}
+		Expect(c.Get(ctx, key, stored)).To(Succeed())
+		Expect(stored.Spec.Resource.Storage.StorageClass).To(BeEmpty())
+		Expect(stored.Spec.ExposeViaService.ServiceType).To(BeEmpty())
+		Expect(stored.Status.NamespaceDefaults).To(Equal(&dbpreview.NamespaceDefaultsStatus{
+			StorageClass: "fast",
+			ServiceType:  "ClusterIP",
+		}))
+
+		// Later changes to the defaults leave the existing cluster alone
+		defaults.Data[util.DEFAULT_SERVICE_TYPE_ENV] = "LoadBalancer"
+		Expect(c.Update(ctx, defaults)).To(Succeed())
+		Expect(c.Get(ctx, key, stored)).To(Succeed())
+		Expect(reconciler.applyNamespaceDefaults(ctx, stored)).To(Succeed())
+		Expect(stored.Spec.ExposeViaService.ServiceType).To(Equal("ClusterIP"))
+		Expect(c.Get(ctx, key, stored)).To(Succeed())
+		Expect(stored.Status.NamespaceDefaults.ServiceType).To(Equal("ClusterIP"))
+	})
+
+	It("leaves the fields of the cluster class to it", func() {
+		class := &dbpreview.DocumentDBClusterClass{
+			ObjectMeta: metav1.ObjectMeta{Name: "standard"},
+			Spec: dbpreview.DocumentDBClusterClassSpec{
+				Sizes:        []dbpreview.ClusterClassSize{{Name: "small", PvcSize: "10Gi"}},
+				StorageClass: "premium",
+			},
+		}
+		documentdb := &dbpreview.DocumentDB{
+			ObjectMeta: metav1.ObjectMeta{Name: "db", Namespace: namespace},
+			Spec:       dbpreview.DocumentDBSpec{ClassRef: &dbpreview.ClusterClassReference{Name: "standard"}},
+		}
+		c := newClient(defaults, class, documentdb)
+		reconciler := &DocumentDBReconciler{Client: c, Scheme: scheme}
+
+		Expect(util.ResolveClusterClass(ctx, c, documentdb)).To(Succeed())
+		Expect(reconciler.applyNamespaceDefaults(ctx, documentdb)).To(Succeed())
+		Expect(documentdb.Spec.Resource.Storage.StorageClass).To(Equal("premium"))
+		Expect(documentdb.Spec.ExposeViaService.ServiceType).To(Equal("ClusterIP"))
+
+		stored := &dbpreview.DocumentDB{}
+		Expect(c.Get(ctx, client.ObjectKeyFromObject(documentdb), stored)).To(Succeed())
+		Expect(stored.Spec.Resource.Storage.StorageClass).To(BeEmpty())
+		Expect(stored.Spec.Resource.Storage.PvcSize).To(BeEmpty())
+	})
+
+	It("returns the error of recording the defaults", func() {
+		documentdb := &dbpreview.DocumentDB{ObjectMeta: metav1.ObjectMeta{Name: "db", Namespace: namespace}}
+		reconciler := &DocumentDBReconciler{Client: newClient(defaults), Scheme: scheme}
+
+		err := reconciler.applyNamespaceDefaults(ctx, documentdb)
+		Expect(err).To(MatchError(ContainSubstring("failed to save the namespace defaults")))
+		Expect(documentdb.Status.NamespaceDefaults).To(BeNil())
+	})
+})
@@ -0,0 +1,113 @@
+// Copyright (c) Microsoft Corporation.
+// Licensed under the MIT License.
+
+package controller
+
+import (
+	"context"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/fields"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/cache"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/event"
+	"sigs.k8s.io/controller-runtime/pkg/handler"
+	"sigs.k8s.io/controller-runtime/pkg/log"
+	"sigs.k8s.io/controller-runtime/pkg/source"
+
+	dbpreview "github.com/documentdb/documentdb-operator/api/preview"
+	util "github.com/documentdb/documentdb-operator/internal/utils"
+)
+
+// OperatorConfigReconciler watches the operator ConfigMap and hot-reloads the
+// operator-level settings it carries (see util.GetOperatorSetting). When the
+// effective settings change, every DocumentDB is re-queued so that new defaults
+// such as images or sidecar resources are rolled out without an operator restart.
+type OperatorConfigReconciler struct {
+	client.Client
+	// Namespace and Name identify the operator ConfigMap.
+	Namespace string
+	Name      string
+	// ConfigMapReader reads the operator ConfigMap. Defaults to a cache of
+	// the operator ConfigMap alone, so that watching it does not cache every
+	// ConfigMap of the cluster.
+	ConfigMapReader client.Reader
+	// DocumentDBEvents, when set, receives one event per DocumentDB after the
+	// settings change. It is consumed by the DocumentDB controller.
+	DocumentDBEvents chan<- event.GenericEvent
+}
+
+// +kubebuilder:rbac:groups="",resources=configmaps,verbs=get;list;watch
+
+func (r *OperatorConfigReconciler) Reconcile(ctx context.Context, req ctrl.Request) (ctrl.Result, error) {
+	logger := log.FromContext(ctx)
+
+	configMap := &corev1.ConfigMap{}
+	var data map[string]string
+	if err := r.ConfigMapReader.Get(ctx, req.NamespacedName, configMap); err != nil {
+		if !errors.IsNotFound(err) {
+			return ctrl.Result{}, err
+		}
+		// Deleting the ConfigMap reverts every setting to the operator environment.
+	} else {
+		data = configMap.Data
+	}
+
+	if !util.SetOperatorSettings(data) {
+		return ctrl.Result{}, nil
+	}
+	logger.Info("Operator settings reloaded", "configMap", req.NamespacedName, "keys", len(data))
+
+	if r.DocumentDBEvents == nil {
+		return ctrl.Result{}, nil
+	}
+
+	documentdbs := &dbpreview.DocumentDBList{}
+	if err := r.List(ctx, documentdbs); err != nil {
+		return ctrl.Result{}, err
+	}
+	for i := range documentdbs.Items {
+		select {
+		case r.DocumentDBEvents <- event.GenericEvent{Object: &documentdbs.Items[i]}:
+		case <-ctx.Done():
+			return ctrl.Result{}, ctx.Err()
+		}
+	}
+	return ctrl.Result{}, nil
+}
+
+// SetupWithManager sets up the controller with the Manager. The operator
+// ConfigMap is watched through a cache of its own, which the manager starts.
+func (r *OperatorConfigReconciler) SetupWithManager(mgr ctrl.Manager) error {
+	options := r.configMapCacheOptions()
+	options.Scheme = mgr.GetScheme()
+	options.Mapper = mgr.GetRESTMapper()
+	configMapCache, err := cache.New(mgr.GetConfig(), options)
+	if err != nil {
+		return err
+	}
+	if err := mgr.Add(configMapCache); err != nil {
+		return err
+	}
+	if r.ConfigMapReader == nil {
+		r.ConfigMapReader = configMapCache
+	}
+	return ctrl.NewControllerManagedBy(mgr).
+		Named("operator-config-controller").
+		WatchesRawSource(source.Kind(configMapCache, &corev1.ConfigMap{}, &handler.TypedEnqueueRequestForObject[*corev1.ConfigMap]{})).
+		Complete(trackReconciles("operator-config-controller", r))
+}
+
+// configMapCacheOptions restricts a cache to the operator ConfigMap.
+func (r *OperatorConfigReconciler) configMapCacheOptions() cache.Options {
+	return cache.Options{
+		ByObject: map[client.Object]cache.ByObject{
+			&corev1.ConfigMap{}: {
+				Namespaces: map[string]cache.Config{r.Namespace: {}},
+				Field:      fields.OneTermEqualSelector("metadata.name", r.Name),
+			},
+		},
+	}
+}
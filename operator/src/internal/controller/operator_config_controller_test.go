@@ -0,0 +1,117 @@
+// Copyright (c) Microsoft Corporation.
+// Licensed under the MIT License.
+
+package controller
+
+import (
+	"context"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/fields"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+	"sigs.k8s.io/controller-runtime/pkg/event"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+
+	dbpreview "github.com/documentdb/documentdb-operator/api/preview"
+	util "github.com/documentdb/documentdb-operator/internal/utils"
+)
+
+var _ = Describe("OperatorConfig Controller", func() {
+	const (
+		operatorNamespace = "documentdb-operator"
+		configMapName     = util.DEFAULT_OPERATOR_CONFIG_MAP
+	)
+
+	var (
+		ctx    context.Context
+		scheme *runtime.Scheme
+		req    reconcile.Request
+	)
+
+	BeforeEach(func() {
+		ctx = context.Background()
+		scheme = runtime.NewScheme()
+		Expect(dbpreview.AddToScheme(scheme)).To(Succeed())
+		Expect(corev1.AddToScheme(scheme)).To(Succeed())
+		req = reconcile.Request{NamespacedName: types.NamespacedName{Name: configMapName, Namespace: operatorNamespace}}
+		util.SetOperatorSettings(nil)
+	})
+
+	AfterEach(func() {
+		util.SetOperatorSettings(nil)
+	})
+
+	newConfigMap := func(data map[string]string) *corev1.ConfigMap {
+		return &corev1.ConfigMap{
+			ObjectMeta: metav1.ObjectMeta{Name: configMapName, Namespace: operatorNamespace},
+			Data:       data,
+		}
+	}
+
+	It("loads settings from the ConfigMap and re-queues every DocumentDB", func() {
+		db1 := &dbpreview.DocumentDB{ObjectMeta: metav1.ObjectMeta{Name: "db1", Namespace: "ns1"}}
+		db2 := &dbpreview.DocumentDB{ObjectMeta: metav1.ObjectMeta{Name: "db2", Namespace: "ns2"}}
+		cm := newConfigMap(map[string]string{util.DOCUMENTDB_VERSION_ENV: "0.300.0"})
+		fakeClient := fake.NewClientBuilder().WithScheme(scheme).WithObjects(cm, db1, db2).Build()
+
+		events := make(chan event.GenericEvent, 10)
+		r := &OperatorConfigReconciler{
+			Client:           fakeClient,
+			ConfigMapReader:  fakeClient,
+			Namespace:        operatorNamespace,
+			Name:             configMapName,
+			DocumentDBEvents: events,
+		}
+
+		_, err := r.Reconcile(ctx, req)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(util.GetOperatorSetting(util.DOCUMENTDB_VERSION_ENV)).To(Equal("0.300.0"))
+		Expect(events).To(HaveLen(2))
+
+		names := []string{(<-events).Object.GetName(), (<-events).Object.GetName()}
+		Expect(names).To(ConsistOf("db1", "db2"))
+	})
+
+	It("does not re-queue DocumentDBs when the settings are unchanged", func() {
+		db := &dbpreview.DocumentDB{ObjectMeta: metav1.ObjectMeta{Name: "db1", Namespace: "ns1"}}
+		data := map[string]string{util.DOCUMENTDB_VERSION_ENV: "0.300.0"}
+		fakeClient := fake.NewClientBuilder().WithScheme(scheme).WithObjects(newConfigMap(data), db).Build()
+		util.SetOperatorSettings(data)
+
+		events := make(chan event.GenericEvent, 10)
+		r := &OperatorConfigReconciler{Client: fakeClient, ConfigMapReader: fakeClient, Namespace: operatorNamespace, Name: configMapName, DocumentDBEvents: events}
+
+		_, err := r.Reconcile(ctx, req)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(events).To(BeEmpty())
+	})
+
+	It("clears settings when the ConfigMap is deleted", func() {
+		util.SetOperatorSettings(map[string]string{util.OTEL_COLLECTOR_IMAGE_ENV: "registry.example.com/otel:1.0"})
+		fakeClient := fake.NewClientBuilder().WithScheme(scheme).Build()
+		r := &OperatorConfigReconciler{Client: fakeClient, ConfigMapReader: fakeClient, Namespace: operatorNamespace, Name: configMapName}
+
+		_, err := r.Reconcile(ctx, req)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(util.GetOtelCollectorImage()).To(Equal(util.DEFAULT_OTEL_COLLECTOR_IMAGE))
+	})
+
+	It("only caches the operator ConfigMap", func() {
+		r := &OperatorConfigReconciler{Namespace: operatorNamespace, Name: configMapName}
+		options := r.configMapCacheOptions()
+
+		Expect(options.ByObject).To(HaveLen(1))
+		for obj, byObject := range options.ByObject {
+			Expect(obj).To(BeAssignableToTypeOf(&corev1.ConfigMap{}))
+			Expect(byObject.Namespaces).To(HaveKey(operatorNamespace))
+			Expect(byObject.Namespaces).To(HaveLen(1))
+			Expect(byObject.Field.Matches(fields.Set{"metadata.name": configMapName})).To(BeTrue())
+			Expect(byObject.Field.Matches(fields.Set{"metadata.name": "other"})).To(BeFalse())
+		}
+	})
+})
@@ -0,0 +1,482 @@
+// Copyright (c) Microsoft Corporation.
+// Licensed under the MIT License.
+
+package controller
+
+import (
+	"context"
+	"crypto/rand"
+	"errors"
+	"fmt"
+	"slices"
+	"strings"
+	"time"
+
+	cnpgv1 "github.com/cloudnative-pg/cloudnative-pg/api/v1"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/util/version"
+	"k8s.io/client-go/kubernetes"
+	"k8s.io/client-go/rest"
+	"k8s.io/client-go/tools/record"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/log"
+
+	dbpreview "github.com/documentdb/documentdb-operator/api/preview"
+	util "github.com/documentdb/documentdb-operator/internal/utils"
+)
+
+const (
+	// compactSQL returns the space of deleted documents to the file system.
+	compactSQL = "VACUUM (FULL, ANALYZE);"
+	// reindexSQL rebuilds the indexes of the database the gateway serves.
+	reindexSQL = "REINDEX DATABASE CONCURRENTLY postgres;"
+	// defaultOpsSQLTimeout bounds a Compact or a Reindex that does not set
+	// spec.timeoutMinutes.
+	defaultOpsSQLTimeout = 24 * time.Hour
+)
+
+// opsFailure is an error that fails a DocumentDBOpsRequest instead of being
+// retried.
+type opsFailure struct {
+	message string
+}
+
+func (f *opsFailure) Error() string { return f.message }
+
+func opsFailed(format string, args ...any) error {
+	return &opsFailure{message: fmt.Sprintf(format, args...)}
+}
+
+// OpsRequestReconciler runs the administrative operations requested with
+// DocumentDBOpsRequest resources. The operations on a cluster run one at a
+// time, oldest first.
+type OpsRequestReconciler struct {
+	client.Client
+	Scheme    *runtime.Scheme
+	Config    *rest.Config
+	Clientset kubernetes.Interface
+	Recorder  record.EventRecorder
+	// APIReader reads a DocumentDBOpsRequest from the API server before its
+	// SQL runs, so that a stale cache does not run it twice, and the objects
+	// whose writes conflict. Defaults to the API reader of the manager.
+	APIReader client.Reader
+	// SQLExecutor executes SQL commands, one after the other in a session,
+	// against a CNPG cluster's primary pod. Defaults to the pod exec of the
+	// DocumentDB controller.
+	SQLExecutor func(ctx context.Context, cluster *cnpgv1.Cluster, sqlCommands ...string) (string, error)
+	// PodExec runs the SQL commands in the primary pod. Defaults to a runner
+	// built from Config and Clientset.
+	PodExec *util.PodExecRunner
+	// backgroundOps runs the SQL operations, which can outlast a reconcile.
+	backgroundOps *backgroundOperations
+}
+
+// +kubebuilder:rbac:groups=documentdb.io,resources=documentdbopsrequests,verbs=get;list;watch;update;patch
+// +kubebuilder:rbac:groups=documentdb.io,resources=documentdbopsrequests/status,verbs=get;update;patch
+func (r *OpsRequestReconciler) Reconcile(ctx context.Context, req ctrl.Request) (ctrl.Result, error) {
+	logger := log.FromContext(ctx)
+
+	ops := &dbpreview.DocumentDBOpsRequest{}
+	if err := r.Get(ctx, req.NamespacedName, ops); err != nil {
+		if apierrors.IsNotFound(err) {
+			return ctrl.Result{}, nil
+		}
+		logger.Error(err, "Failed to get DocumentDBOpsRequest")
+		return ctrl.Result{}, err
+	}
+	if ops.Status.IsDone() {
+		return ctrl.Result{}, nil
+	}
+
+	result, err := r.reconcileOpsRequest(ctx, ops)
+	var failure *opsFailure
+	if errors.As(err, &failure) {
+		return ctrl.Result{}, r.finish(ctx, ops, dbpreview.OpsRequestPhaseFailed, failure.message)
+	}
+	return result, err
+}
+
+func (r *OpsRequestReconciler) reconcileOpsRequest(ctx context.Context, ops *dbpreview.DocumentDBOpsRequest) (ctrl.Result, error) {
+	documentdb := &dbpreview.DocumentDB{}
+	if err := r.Get(ctx, client.ObjectKey{Name: ops.Spec.Cluster.Name, Namespace: ops.Namespace}, documentdb); err != nil {
+		if apierrors.IsNotFound(err) {
+			return ctrl.Result{}, opsFailed("DocumentDB %s not found", ops.Spec.Cluster.Name)
+		}
+		return ctrl.Result{}, err
+	}
+	replicationContext, err := util.GetReplicationContext(ctx, r.Client, *documentdb)
+	if err != nil {
+		return ctrl.Result{}, err
+	}
+	cluster := &cnpgv1.Cluster{}
+	if err := r.Get(ctx, client.ObjectKey{Name: replicationContext.CNPGClusterName, Namespace: ops.Namespace}, cluster); err != nil {
+		if apierrors.IsNotFound(err) {
+			return ctrl.Result{}, opsFailed("the CNPG Cluster of DocumentDB %s does not exist yet", documentdb.Name)
+		}
+		return ctrl.Result{}, err
+	}
+
+	if ops.Status.Phase != dbpreview.OpsRequestPhaseRunning {
+		blocking, err := r.blockingOpsRequest(ctx, ops)
+		if err != nil {
+			return ctrl.Result{}, err
+		}
+		if blocking != "" {
+			message := fmt.Sprintf("Waiting for DocumentDBOpsRequest %s to complete", blocking)
+			if ops.Status.Phase != dbpreview.OpsRequestPhasePending || ops.Status.Message != message {
+				original := ops.DeepCopy()
+				ops.Status.Phase = dbpreview.OpsRequestPhasePending
+				ops.Status.Message = message
+				if err := r.Status().Patch(ctx, ops, client.MergeFrom(original)); err != nil {
+					return ctrl.Result{}, err
+				}
+			}
+			return ctrl.Result{RequeueAfter: RequeueAfterLong}, nil
+		}
+		if err := r.start(ctx, ops, documentdb, replicationContext, cluster); err != nil {
+			return ctrl.Result{}, err
+		}
+	}
+
+	var done bool
+	switch ops.Spec.Type {
+	case dbpreview.OpsRequestCompact:
+		r.runSQL(ops, cluster, compactSQL)
+	case dbpreview.OpsRequestReindex:
+		r.runSQL(ops, cluster, reindexSQL)
+	case dbpreview.OpsRequestRotateCredentials:
+		done, err = r.rotateCredentials(ctx, ops, documentdb, cluster)
+	case dbpreview.OpsRequestSwitchover:
+		done, err = r.switchover(ctx, ops, cluster)
+	case dbpreview.OpsRequestMinorUpgrade:
+		done, err = r.minorUpgrade(ctx, ops, documentdb)
+	}
+	if err != nil {
+		return ctrl.Result{}, err
+	}
+	if done {
+		return ctrl.Result{}, r.finish(ctx, ops, dbpreview.OpsRequestPhaseSucceeded, "Completed")
+	}
+	return ctrl.Result{RequeueAfter: RequeueAfterShort}, nil
+}
+
+// blockingOpsRequest returns the name of an older DocumentDBOpsRequest on the
+// same cluster that has not completed, if any.
+func (r *OpsRequestReconciler) blockingOpsRequest(ctx context.Context, ops *dbpreview.DocumentDBOpsRequest) (string, error) {
+	list := &dbpreview.DocumentDBOpsRequestList{}
+	if err := r.List(ctx, list, client.InNamespace(ops.Namespace)); err != nil {
+		return "", fmt.Errorf("failed to list DocumentDBOpsRequests: %w", err)
+	}
+	for _, other := range list.Items {
+		if other.Name == ops.Name || other.Spec.Cluster.Name != ops.Spec.Cluster.Name || other.Status.IsDone() {
+			continue
+		}
+		older := other.CreationTimestamp.Before(&ops.CreationTimestamp) ||
+			(other.CreationTimestamp.Equal(&ops.CreationTimestamp) && other.Name < ops.Name)
+		if older || other.Status.IsActive() {
+			return other.Name, nil
+		}
+	}
+	return "", nil
+}
+
+// start validates ops against the current state of the cluster and moves it
+// to the Running phase.
+func (r *OpsRequestReconciler) start(ctx context.Context, ops *dbpreview.DocumentDBOpsRequest, documentdb *dbpreview.DocumentDB,
+	replicationContext *util.ReplicationContext, cluster *cnpgv1.Cluster) error {
+	original := ops.DeepCopy()
+
+	switch ops.Spec.Type {
+	case dbpreview.OpsRequestCompact, dbpreview.OpsRequestReindex:
+		if !replicationContext.IsPrimary() {
+			return opsFailed("%s can only run on the primary cluster", ops.Spec.Type)
+		}
+	case dbpreview.OpsRequestRotateCredentials:
+		if documentdb.Spec.ClusterReplication != nil {
+			return opsFailed("RotateCredentials is not supported with spec.clusterReplication: update the credential Secret of every member instead")
+		}
+	case dbpreview.OpsRequestSwitchover:
+		target, err := switchoverTarget(ops, cluster)
+		if err != nil {
+			return err
+		}
+		if documentdb.Status.TargetPrimary != "" && documentdb.Status.TargetPrimary != target {
+			return opsFailed("status.targetPrimary of DocumentDB %s is set to %s", documentdb.Name, documentdb.Status.TargetPrimary)
+		}
+		ops.Status.TargetInstance = target
+	case dbpreview.OpsRequestMinorUpgrade:
+		if err := r.validateMinorUpgrade(ctx, ops, documentdb); err != nil {
+			return err
+		}
+	default:
+		return opsFailed("unsupported operation %q", ops.Spec.Type)
+	}
+
+	now := metav1.Now()
+	ops.Status.Phase = dbpreview.OpsRequestPhaseRunning
+	ops.Status.StartedAt = &now
+	ops.Status.Message = fmt.Sprintf("%s started", ops.Spec.Type)
+	if err := r.Status().Patch(ctx, ops, client.MergeFrom(original)); err != nil {
+		return fmt.Errorf("failed to patch DocumentDBOpsRequest status: %w", err)
+	}
+	r.Recorder.Event(ops, corev1.EventTypeNormal, "OpsRequestStarted", ops.Status.Message)
+	return nil
+}
+
+// finish moves ops to a terminal phase.
+func (r *OpsRequestReconciler) finish(ctx context.Context, ops *dbpreview.DocumentDBOpsRequest, phase dbpreview.OpsRequestPhase, message string) error {
+	original := ops.DeepCopy()
+	now := metav1.Now()
+	ops.Status.Phase = phase
+	ops.Status.CompletedAt = &now
+	ops.Status.Message = message
+	if err := r.Status().Patch(ctx, ops, client.MergeFrom(original)); err != nil {
+		log.FromContext(ctx).Error(err, "Failed to patch DocumentDBOpsRequest status")
+		return err
+	}
+	if phase == dbpreview.OpsRequestPhaseFailed {
+		r.Recorder.Event(ops, corev1.EventTypeWarning, "OpsRequestFailed", message)
+	} else {
+		r.Recorder.Event(ops, corev1.EventTypeNormal, "OpsRequestSucceeded", fmt.Sprintf("%s completed", ops.Spec.Type))
+	}
+	return nil
+}
+
+// runSQL runs sqlCommand on the primary of cluster in the background and
+// completes ops when it returns. The phase of ops is read from the API server
+// first, so that an operation the cache still shows Running after it completed
+// does not run again. The command runs for up to spec.timeoutMinutes, both in
+// the pod exec and as the statement_timeout of its session, so that the
+// server does not keep running a command the operator gave up on. An
+// operation interrupted by a shutdown is run again by the next leader.
+func (r *OpsRequestReconciler) runSQL(ops *dbpreview.DocumentDBOpsRequest, cluster *cnpgv1.Cluster, sqlCommand string) {
+	key := client.ObjectKeyFromObject(ops)
+	timeout := opsSQLTimeout(ops)
+	r.backgroundOps.Go("opsrequest/"+key.String(), func(ctx context.Context) {
+		logger := log.FromContext(ctx).WithValues("opsRequest", key.String())
+		latest := &dbpreview.DocumentDBOpsRequest{}
+		if err := r.APIReader.Get(ctx, key, latest); err != nil {
+			logger.Error(err, "Failed to get DocumentDBOpsRequest")
+			return
+		}
+		if latest.Status.Phase != dbpreview.OpsRequestPhaseRunning {
+			return
+		}
+		sqlCtx, cancel := context.WithTimeout(ctx, timeout)
+		_, sqlErr := r.SQLExecutor(sqlCtx, cluster, fmt.Sprintf("SET statement_timeout = %d;", timeout.Milliseconds()), sqlCommand)
+		timedOut := sqlCtx.Err() != nil
+		cancel()
+		if ctx.Err() != nil {
+			return
+		}
+		if err := r.APIReader.Get(ctx, key, latest); err != nil {
+			logger.Error(err, "Failed to get DocumentDBOpsRequest")
+			return
+		}
+		if timedOut {
+			_ = r.finish(ctx, latest, dbpreview.OpsRequestPhaseFailed, fmt.Sprintf("%s did not complete within %s", latest.Spec.Type, timeout))
+			return
+		}
+		if sqlErr != nil {
+			_ = r.finish(ctx, latest, dbpreview.OpsRequestPhaseFailed, fmt.Sprintf("%s failed: %v", latest.Spec.Type, sqlErr))
+			return
+		}
+		_ = r.finish(ctx, latest, dbpreview.OpsRequestPhaseSucceeded, "Completed")
+	})
+}
+
+// opsSQLTimeout returns how long the SQL of ops can run.
+func opsSQLTimeout(ops *dbpreview.DocumentDBOpsRequest) time.Duration {
+	if ops.Spec.TimeoutMinutes > 0 {
+		return time.Duration(ops.Spec.TimeoutMinutes) * time.Minute
+	}
+	return defaultOpsSQLTimeout
+}
+
+// rotateCredentials sets a new password for the gateway user and restarts the
+// instances so that the gateway picks it up. Only the SCRAM verifier of the
+// password is sent to PostgreSQL. The password is stored in the credential
+// Secret before it is set, so that a retry sets the same password.
+func (r *OpsRequestReconciler) rotateCredentials(ctx context.Context, ops *dbpreview.DocumentDBOpsRequest, documentdb *dbpreview.DocumentDB,
+	cluster *cnpgv1.Cluster) (bool, error) {
+	secret := &corev1.Secret{}
+	secretName := util.CredentialSecretName(documentdb)
+	if err := r.Get(ctx, client.ObjectKey{Name: secretName, Namespace: documentdb.Namespace}, secret); err != nil {
+		if apierrors.IsNotFound(err) {
+			return false, opsFailed("credential Secret %s not found", secretName)
+		}
+		return false, err
+	}
+	username := string(secret.Data["username"])
+	if username == "" {
+		return false, opsFailed("credential Secret %s has no username", secretName)
+	}
+
+	if rotatedBy, ok := secret.Annotations[util.CREDENTIALS_ROTATED_BY_ANNOTATION]; !ok || rotatedBy != string(ops.UID) {
+		if secret.Annotations == nil {
+			secret.Annotations = map[string]string{}
+		}
+		secret.Annotations[util.CREDENTIALS_ROTATED_BY_ANNOTATION] = string(ops.UID)
+		secret.Data["password"] = []byte(rand.Text())
+		if err := r.Update(ctx, secret); err != nil {
+			return false, fmt.Errorf("failed to update credential Secret %s: %w", secretName, err)
+		}
+	}
+
+	verifier, err := util.SCRAMSHA256Verifier(string(secret.Data["password"]))
+	if err != nil {
+		return false, err
+	}
+	alterRole := fmt.Sprintf("ALTER ROLE %s WITH PASSWORD %s;", util.QuoteIdentifier(username), util.QuoteLiteral(verifier))
+	if _, err := r.SQLExecutor(ctx, cluster, alterRole); err != nil {
+		return false, fmt.Errorf("failed to set the new password: %w", err)
+	}
+
+	// The restart is stamped with the start of ops, so that a retry does not
+	// restart the instances again.
+	err = util.PatchWithRetry(ctx, r.Client, r.APIReader, cluster, func() error {
+		if cluster.Annotations == nil {
+			cluster.Annotations = map[string]string{}
+		}
+		cluster.Annotations["kubectl.kubernetes.io/restartedAt"] = ops.Status.StartedAt.Format(time.RFC3339)
+		return nil
+	})
+	if err != nil {
+		return false, fmt.Errorf("failed to restart the instances: %w", err)
+	}
+	return true, nil
+}
+
+// switchoverTarget returns the instance a Switchover promotes: the requested
+// one, or the first healthy replica.
+func switchoverTarget(ops *dbpreview.DocumentDBOpsRequest, cluster *cnpgv1.Cluster) (string, error) {
+	healthy := cluster.Status.InstancesStatus[cnpgv1.PodHealthy]
+	if ops.Spec.Switchover != nil && ops.Spec.Switchover.TargetInstance != "" {
+		target := ops.Spec.Switchover.TargetInstance
+		switch {
+		case target == cluster.Status.CurrentPrimary:
+			return "", opsFailed("instance %s is already the primary", target)
+		case !slices.Contains(cluster.Status.InstanceNames, target):
+			return "", opsFailed("instance %s is not part of the cluster", target)
+		case !slices.Contains(healthy, target):
+			return "", opsFailed("instance %s is not healthy", target)
+		}
+		return target, nil
+	}
+	replicas := slices.DeleteFunc(slices.Clone(healthy), func(name string) bool { return name == cluster.Status.CurrentPrimary })
+	if len(replicas) == 0 {
+		return "", opsFailed("there is no healthy replica to switch over to")
+	}
+	slices.Sort(replicas)
+	return replicas[0], nil
+}
+
+// switchover promotes the target instance and reports whether it has become
+// the primary.
+func (r *OpsRequestReconciler) switchover(ctx context.Context, ops *dbpreview.DocumentDBOpsRequest, cluster *cnpgv1.Cluster) (bool, error) {
+	target := ops.Status.TargetInstance
+	if cluster.Status.CurrentPrimary == target {
+		return true, nil
+	}
+	if cluster.Status.TargetPrimary != target {
+		if err := Promote(ctx, r.Client, cluster.Namespace, cluster.Name, target); err != nil {
+			return false, err
+		}
+	}
+	return false, nil
+}
+
+// validateMinorUpgrade checks that the upgrade moves documentdb to a newer
+// version of the same major version.
+func (r *OpsRequestReconciler) validateMinorUpgrade(ctx context.Context, ops *dbpreview.DocumentDBOpsRequest, documentdb *dbpreview.DocumentDB) error {
+	resolved := documentdb.DeepCopy()
+	if err := util.ResolveClusterClass(ctx, r.Client, resolved); err != nil {
+		return err
+	}
+	if image := resolved.Spec.Image; image != nil && (image.DocumentDB != "" || image.Gateway != "") {
+		return opsFailed("spec.image overrides the DocumentDB version: update the images instead")
+	}
+	target, err := version.ParseSemantic(ops.Spec.MinorUpgrade.Version)
+	if err != nil {
+		return opsFailed("invalid version %q: %v", ops.Spec.MinorUpgrade.Version, err)
+	}
+	image := util.GetDocumentDBImageForInstance(resolved)
+	tag := image[strings.LastIndex(image, ":")+1:]
+	current, err := version.ParseSemantic(tag)
+	if err != nil {
+		// An image without a version tag cannot be compared; the webhook
+		// still rejects downgrades below the installed schema.
+		return nil
+	}
+	if target.Major() != current.Major() {
+		return opsFailed("version %s is not a minor upgrade of %s", target, current)
+	}
+	if !current.LessThan(target) {
+		return opsFailed("version %s is not newer than the current version %s", target, current)
+	}
+	return nil
+}
+
+// minorUpgrade sets the version of the upgrade in an annotation of documentdb,
+// which the cluster runs instead of spec.documentDBVersion, and reports whether
+// every instance runs it. The spec is left to its owner: once it is set to the
+// same version or a newer one, the annotation no longer has any effect.
+func (r *OpsRequestReconciler) minorUpgrade(ctx context.Context, ops *dbpreview.DocumentDBOpsRequest, documentdb *dbpreview.DocumentDB) (bool, error) {
+	target := ops.Spec.MinorUpgrade.Version
+	if documentdb.Annotations[util.MINOR_UPGRADE_VERSION_ANNOTATION] != target {
+		patch := client.MergeFrom(documentdb.DeepCopy())
+		if documentdb.Annotations == nil {
+			documentdb.Annotations = map[string]string{}
+		}
+		documentdb.Annotations[util.MINOR_UPGRADE_VERSION_ANNOTATION] = target
+		if err := r.Patch(ctx, documentdb, patch); err != nil {
+			if apierrors.IsForbidden(err) || apierrors.IsInvalid(err) {
+				return false, opsFailed("failed to set the version of the upgrade: %v", err)
+			}
+			return false, err
+		}
+		return false, nil
+	}
+	if current := util.DocumentDBVersion(documentdb); current != target {
+		return false, opsFailed("spec.documentDBVersion %s has replaced the upgrade to %s", current, target)
+	}
+
+	instances := documentdb.Status.Instances
+	if len(instances) == 0 {
+		return false, nil
+	}
+	for _, instance := range instances {
+		if !instance.Healthy || instance.ExtensionVersion != target {
+			return false, nil
+		}
+	}
+	return true, nil
+}
+
+// SetupWithManager sets up the controller with the Manager.
+func (r *OpsRequestReconciler) SetupWithManager(mgr ctrl.Manager) error {
+	if r.PodExec == nil {
+		r.PodExec = util.NewPodExecRunner(r.Config, r.Clientset)
+	}
+	if r.SQLExecutor == nil {
+		documentdbReconciler := &DocumentDBReconciler{Client: r.Client, PodExec: r.PodExec}
+		r.SQLExecutor = documentdbReconciler.executeSQLCommands
+	}
+	if r.backgroundOps == nil {
+		r.backgroundOps = newBackgroundOperations()
+	}
+	if r.APIReader == nil {
+		r.APIReader = mgr.GetAPIReader()
+	}
+	if err := ctrl.NewControllerManagedBy(mgr).
+		For(&dbpreview.DocumentDBOpsRequest{}).
+		Named("opsrequest-controller").
+		Complete(trackReconciles("opsrequest-controller", r)); err != nil {
+		return err
+	}
+	return mgr.Add(r.backgroundOps)
+}
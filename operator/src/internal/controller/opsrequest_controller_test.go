@@ -0,0 +1,299 @@
+// Copyright (c) Microsoft Corporation.
+// Licensed under the MIT License.
+
+package controller
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"time"
+
+	cnpgv1 "github.com/cloudnative-pg/cloudnative-pg/api/v1"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/tools/record"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+
+	dbpreview "github.com/documentdb/documentdb-operator/api/preview"
+	util "github.com/documentdb/documentdb-operator/internal/utils"
+)
+
+var _ = Describe("OpsRequest Controller", func() {
+	const namespace = "default"
+
+	var (
+		ctx      context.Context
+		scheme   *runtime.Scheme
+		recorder *record.FakeRecorder
+		executed chan string
+		// sessionCommands are the commands run before the last executed
+		// one, and sqlDeadline the deadline of its context
+		sessionCommands []string
+		sqlDeadline     time.Time
+		sqlErr          error
+		documentdb      *dbpreview.DocumentDB
+		cluster         *cnpgv1.Cluster
+	)
+
+	BeforeEach(func() {
+		ctx = context.Background()
+		scheme = runtime.NewScheme()
+		Expect(dbpreview.AddToScheme(scheme)).To(Succeed())
+		Expect(cnpgv1.AddToScheme(scheme)).To(Succeed())
+		Expect(corev1.AddToScheme(scheme)).To(Succeed())
+		recorder = record.NewFakeRecorder(10)
+		executed = make(chan string, 10)
+		sqlErr = nil
+
+		documentdb = &dbpreview.DocumentDB{
+			ObjectMeta: metav1.ObjectMeta{Name: "db", Namespace: namespace},
+			Spec:       dbpreview.DocumentDBSpec{DocumentDBVersion: "0.110.0"},
+		}
+		cluster = &cnpgv1.Cluster{
+			ObjectMeta: metav1.ObjectMeta{Name: "db", Namespace: namespace},
+			Status: cnpgv1.ClusterStatus{
+				CurrentPrimary: "db-1",
+				TargetPrimary:  "db-1",
+				InstanceNames:  []string{"db-1", "db-2", "db-3"},
+				InstancesStatus: map[cnpgv1.PodStatus][]string{
+					cnpgv1.PodHealthy: {"db-1", "db-3", "db-2"},
+				},
+			},
+		}
+	})
+
+	opsRequest := func(name string, opsType dbpreview.OpsRequestType) *dbpreview.DocumentDBOpsRequest {
+		return &dbpreview.DocumentDBOpsRequest{
+			ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: namespace, UID: types.UID("uid-" + name)},
+			Spec: dbpreview.DocumentDBOpsRequestSpec{
+				Cluster: cnpgv1.LocalObjectReference{Name: "db"},
+				Type:    opsType,
+			},
+		}
+	}
+
+	newReconciler := func(objects ...client.Object) *OpsRequestReconciler {
+		objects = append(objects, documentdb, cluster)
+		for _, name := range cluster.Status.InstanceNames {
+			objects = append(objects, &corev1.Pod{ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: namespace}})
+		}
+		c := fake.NewClientBuilder().
+			WithScheme(scheme).
+			WithObjects(objects...).
+			WithStatusSubresource(&dbpreview.DocumentDBOpsRequest{}, &dbpreview.DocumentDB{}, &cnpgv1.Cluster{}).
+			Build()
+		return &OpsRequestReconciler{
+			Client:    c,
+			Scheme:    scheme,
+			Recorder:  recorder,
+			APIReader: c,
+			SQLExecutor: func(ctx context.Context, _ *cnpgv1.Cluster, sqlCommands ...string) (string, error) {
+				sessionCommands = sqlCommands[:len(sqlCommands)-1]
+				sqlDeadline, _ = ctx.Deadline()
+				executed <- sqlCommands[len(sqlCommands)-1]
+				return "", sqlErr
+			},
+			backgroundOps: newBackgroundOperations(),
+		}
+	}
+
+	reconcileOps := func(r *OpsRequestReconciler, name string) *dbpreview.DocumentDBOpsRequest {
+		key := client.ObjectKey{Name: name, Namespace: namespace}
+		_, err := r.Reconcile(ctx, reconcile.Request{NamespacedName: key})
+		Expect(err).ToNot(HaveOccurred())
+		ops := &dbpreview.DocumentDBOpsRequest{}
+		Expect(r.Get(ctx, key, ops)).To(Succeed())
+		return ops
+	}
+
+	getPhase := func(r *OpsRequestReconciler, name string) func() dbpreview.OpsRequestPhase {
+		return func() dbpreview.OpsRequestPhase {
+			ops := &dbpreview.DocumentDBOpsRequest{}
+			Expect(r.Get(ctx, client.ObjectKey{Name: name, Namespace: namespace}, ops)).To(Succeed())
+			return ops.Status.Phase
+		}
+	}
+
+	It("runs a Compact on the primary in the background", func() {
+		r := newReconciler(opsRequest("compact", dbpreview.OpsRequestCompact))
+
+		ops := reconcileOps(r, "compact")
+		Expect(ops.Status.StartedAt).ToNot(BeNil())
+		Expect(<-executed).To(Equal(compactSQL))
+		Eventually(getPhase(r, "compact")).Should(Equal(dbpreview.OpsRequestPhaseSucceeded))
+		Expect(recorder.Events).To(Receive(ContainSubstring("OpsRequestStarted")))
+	})
+
+	It("bounds a Compact with the timeout of the request", func() {
+		ops := opsRequest("compact", dbpreview.OpsRequestCompact)
+		ops.Spec.TimeoutMinutes = 90
+		r := newReconciler(ops)
+
+		reconcileOps(r, "compact")
+		Expect(<-executed).To(Equal(compactSQL))
+		Expect(sessionCommands).To(Equal([]string{"SET statement_timeout = 5400000;"}))
+		Expect(time.Until(sqlDeadline)).To(BeNumerically("~", 90*time.Minute, time.Minute))
+		Eventually(getPhase(r, "compact")).Should(Equal(dbpreview.OpsRequestPhaseSucceeded))
+	})
+
+	It("bounds a Reindex with a day by default", func() {
+		r := newReconciler(opsRequest("reindex", dbpreview.OpsRequestReindex))
+
+		reconcileOps(r, "reindex")
+		Expect(<-executed).To(Equal(reindexSQL))
+		Expect(sessionCommands).To(Equal([]string{"SET statement_timeout = 86400000;"}))
+		Expect(time.Until(sqlDeadline)).To(BeNumerically("~", defaultOpsSQLTimeout, time.Minute))
+	})
+
+	It("fails a Reindex whose SQL fails", func() {
+		sqlErr = errors.New("deadlock detected")
+		r := newReconciler(opsRequest("reindex", dbpreview.OpsRequestReindex))
+
+		reconcileOps(r, "reindex")
+		Expect(<-executed).To(Equal(reindexSQL))
+		Eventually(getPhase(r, "reindex")).Should(Equal(dbpreview.OpsRequestPhaseFailed))
+	})
+
+	It("does not run the SQL of a request that has completed", func() {
+		r := newReconciler(opsRequest("compact", dbpreview.OpsRequestCompact))
+		reconcileOps(r, "compact")
+		Expect(<-executed).To(Equal(compactSQL))
+		Eventually(getPhase(r, "compact")).Should(Equal(dbpreview.OpsRequestPhaseSucceeded))
+
+		// A cache that still shows the request Running
+		stale := &dbpreview.DocumentDBOpsRequest{}
+		Expect(r.Get(ctx, client.ObjectKey{Name: "compact", Namespace: namespace}, stale)).To(Succeed())
+		stale.Status.Phase = dbpreview.OpsRequestPhaseRunning
+		r.runSQL(stale, cluster, compactSQL)
+		Consistently(executed, 100*time.Millisecond).ShouldNot(Receive())
+	})
+
+	It("fails a request for a DocumentDB that does not exist", func() {
+		ops := opsRequest("missing", dbpreview.OpsRequestCompact)
+		ops.Spec.Cluster.Name = "other"
+		r := newReconciler(ops)
+
+		ops = reconcileOps(r, "missing")
+		Expect(ops.Status.Phase).To(Equal(dbpreview.OpsRequestPhaseFailed))
+		Expect(ops.Status.Message).To(ContainSubstring("DocumentDB other not found"))
+		Expect(ops.Status.CompletedAt).ToNot(BeNil())
+	})
+
+	It("runs the operations on a cluster one at a time, oldest first", func() {
+		older := opsRequest("older", dbpreview.OpsRequestSwitchover)
+		older.CreationTimestamp = metav1.NewTime(time.Now().Add(-time.Minute))
+		newer := opsRequest("newer", dbpreview.OpsRequestCompact)
+		newer.CreationTimestamp = metav1.NewTime(time.Now())
+		r := newReconciler(older, newer)
+
+		ops := reconcileOps(r, "newer")
+		Expect(ops.Status.Phase).To(Equal(dbpreview.OpsRequestPhasePending))
+		Expect(ops.Status.Message).To(ContainSubstring("older"))
+		Expect(executed).To(BeEmpty())
+
+		Expect(reconcileOps(r, "older").Status.Phase).To(Equal(dbpreview.OpsRequestPhaseRunning))
+	})
+
+	It("switches over to the first healthy replica", func() {
+		r := newReconciler(opsRequest("switchover", dbpreview.OpsRequestSwitchover))
+
+		ops := reconcileOps(r, "switchover")
+		Expect(ops.Status.Phase).To(Equal(dbpreview.OpsRequestPhaseRunning))
+		Expect(ops.Status.TargetInstance).To(Equal("db-2"))
+
+		updated := &cnpgv1.Cluster{}
+		Expect(r.Get(ctx, client.ObjectKeyFromObject(cluster), updated)).To(Succeed())
+		Expect(updated.Status.TargetPrimary).To(Equal("db-2"))
+
+		updated.Status.CurrentPrimary = "db-2"
+		Expect(r.Status().Update(ctx, updated)).To(Succeed())
+		Expect(reconcileOps(r, "switchover").Status.Phase).To(Equal(dbpreview.OpsRequestPhaseSucceeded))
+	})
+
+	It("rejects a switchover to the current primary", func() {
+		ops := opsRequest("switchover", dbpreview.OpsRequestSwitchover)
+		ops.Spec.Switchover = &dbpreview.SwitchoverOptions{TargetInstance: "db-1"}
+		r := newReconciler(ops)
+
+		ops = reconcileOps(r, "switchover")
+		Expect(ops.Status.Phase).To(Equal(dbpreview.OpsRequestPhaseFailed))
+		Expect(ops.Status.Message).To(ContainSubstring("already the primary"))
+	})
+
+	It("rotates the credentials without sending the password to PostgreSQL", func() {
+		secret := &corev1.Secret{
+			ObjectMeta: metav1.ObjectMeta{Name: util.DEFAULT_DOCUMENTDB_CREDENTIALS_SECRET, Namespace: namespace},
+			Data:       map[string][]byte{"username": []byte("docdb"), "password": []byte("old")},
+		}
+		r := newReconciler(opsRequest("rotate", dbpreview.OpsRequestRotateCredentials), secret)
+
+		Expect(reconcileOps(r, "rotate").Status.Phase).To(Equal(dbpreview.OpsRequestPhaseSucceeded))
+
+		Expect(r.Get(ctx, client.ObjectKeyFromObject(secret), secret)).To(Succeed())
+		password := string(secret.Data["password"])
+		Expect(password).ToNot(Equal("old"))
+		sql := <-executed
+		Expect(sql).To(HavePrefix(`ALTER ROLE "docdb" WITH PASSWORD 'SCRAM-SHA-256$4096:`))
+		Expect(strings.Contains(sql, password)).To(BeFalse())
+
+		updated := &cnpgv1.Cluster{}
+		Expect(r.Get(ctx, client.ObjectKeyFromObject(cluster), updated)).To(Succeed())
+		Expect(updated.Annotations).To(HaveKey("kubectl.kubernetes.io/restartedAt"))
+	})
+
+	It("sets the stored password again when a rotation is retried", func() {
+		secret := &corev1.Secret{
+			ObjectMeta: metav1.ObjectMeta{Name: util.DEFAULT_DOCUMENTDB_CREDENTIALS_SECRET, Namespace: namespace},
+			Data:       map[string][]byte{"username": []byte("docdb"), "password": []byte("old")},
+		}
+		sqlErr = errors.New("connection refused")
+		r := newReconciler(opsRequest("rotate", dbpreview.OpsRequestRotateCredentials), secret)
+
+		key := client.ObjectKey{Name: "rotate", Namespace: namespace}
+		_, err := r.Reconcile(ctx, reconcile.Request{NamespacedName: key})
+		Expect(err).To(HaveOccurred())
+		Expect(r.Get(ctx, client.ObjectKeyFromObject(secret), secret)).To(Succeed())
+		password := string(secret.Data["password"])
+		Expect(password).ToNot(Equal("old"))
+		<-executed
+
+		sqlErr = nil
+		Expect(reconcileOps(r, "rotate").Status.Phase).To(Equal(dbpreview.OpsRequestPhaseSucceeded))
+		Expect(r.Get(ctx, client.ObjectKeyFromObject(secret), secret)).To(Succeed())
+		Expect(string(secret.Data["password"])).To(Equal(password))
+	})
+
+	It("upgrades to a newer minor version and waits for the instances", func() {
+		ops := opsRequest("upgrade", dbpreview.OpsRequestMinorUpgrade)
+		ops.Spec.MinorUpgrade = &dbpreview.MinorUpgradeOptions{Version: "0.111.0"}
+		r := newReconciler(ops)
+
+		Expect(reconcileOps(r, "upgrade").Status.Phase).To(Equal(dbpreview.OpsRequestPhaseRunning))
+		updated := &dbpreview.DocumentDB{}
+		Expect(r.Get(ctx, client.ObjectKeyFromObject(documentdb), updated)).To(Succeed())
+		Expect(updated.Spec.DocumentDBVersion).To(Equal("0.110.0"))
+		Expect(updated.Annotations).To(HaveKeyWithValue(util.MINOR_UPGRADE_VERSION_ANNOTATION, "0.111.0"))
+		Expect(util.GetDocumentDBImageForInstance(updated)).To(HaveSuffix(":0.111.0"))
+
+		updated.Status.Instances = []dbpreview.InstanceStatus{{Name: "db-1", Role: dbpreview.InstanceRolePrimary, Healthy: true, ExtensionVersion: "0.111.0"}}
+		Expect(r.Status().Update(ctx, updated)).To(Succeed())
+		Expect(reconcileOps(r, "upgrade").Status.Phase).To(Equal(dbpreview.OpsRequestPhaseSucceeded))
+	})
+
+	It("rejects a minor upgrade to an older version", func() {
+		ops := opsRequest("upgrade", dbpreview.OpsRequestMinorUpgrade)
+		ops.Spec.MinorUpgrade = &dbpreview.MinorUpgradeOptions{Version: "0.109.0"}
+		r := newReconciler(ops)
+
+		ops = reconcileOps(r, "upgrade")
+		Expect(ops.Status.Phase).To(Equal(dbpreview.OpsRequestPhaseFailed))
+		Expect(ops.Status.Message).To(ContainSubstring("not newer"))
+	})
+})
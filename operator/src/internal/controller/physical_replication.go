@@ -10,6 +10,7 @@ import (
 	"net/http"
 	"reflect"
 	"slices"
+	"strconv"
 	"strings"
 	"time"
 
@@ -23,6 +24,7 @@ import (
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/types"
 	"k8s.io/apimachinery/pkg/util/intstr"
+	"k8s.io/client-go/util/retry"
 	"k8s.io/utils/ptr"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 	"sigs.k8s.io/controller-runtime/pkg/log"
@@ -31,6 +33,10 @@ import (
 const (
 	demotionTokenPollInterval = 5 * time.Second
 	demotionTokenWaitTimeout  = 10 * time.Minute
+
+	// promotionTokenName names the ConfigMap, Pod, Service and fleet objects
+	// that hand the demotion token of the old primary to the new one.
+	promotionTokenName = "promotion-token"
 )
 
 func (r *DocumentDBReconciler) AddClusterReplicationToClusterSpec(
@@ -40,6 +46,9 @@ func (r *DocumentDBReconciler) AddClusterReplicationToClusterSpec(
 	cnpgCluster *cnpgv1.Cluster,
 ) error {
 	if replicationContext.IsAzureFleetNetworking() {
+		if err := replicationContext.ValidateServiceNames(documentdb.Name, documentdb.Namespace); err != nil {
+			return err
+		}
 		err := r.CreateServiceImportAndExport(ctx, replicationContext, documentdb)
 		if err != nil {
 			return err
@@ -126,16 +135,19 @@ func (r *DocumentDBReconciler) AddClusterReplicationToClusterSpec(
 	}
 
 	if replicationContext.IsAzureFleetNetworking() {
-		// need to create services for each of the other clusters
-		cnpgCluster.Spec.Managed = &cnpgv1.ManagedConfiguration{
-			Services: &cnpgv1.ManagedServices{
-				Additional: []cnpgv1.ManagedService{},
-			},
+		// need to create services for each of the other clusters, next to
+		// the ones of spec.additionalServices
+		if cnpgCluster.Spec.Managed == nil {
+			cnpgCluster.Spec.Managed = &cnpgv1.ManagedConfiguration{}
+		}
+		if cnpgCluster.Spec.Managed.Services == nil {
+			cnpgCluster.Spec.Managed.Services = &cnpgv1.ManagedServices{}
 		}
 		for serviceName := range replicationContext.GenerateOutgoingServiceNames(documentdb.Name, documentdb.Namespace) {
 			cnpgCluster.Spec.Managed.Services.Additional = append(cnpgCluster.Spec.Managed.Services.Additional,
 				cnpgv1.ManagedService{
-					SelectorType: cnpgv1.ServiceSelectorTypeRW,
+					SelectorType:   cnpgv1.ServiceSelectorTypeRW,
+					UpdateStrategy: cnpgv1.ServiceUpdateStrategyPatch,
 					ServiceTemplate: cnpgv1.ServiceTemplateSpec{
 						ObjectMeta: cnpgv1.Metadata{
 							Name: serviceName,
@@ -150,7 +162,7 @@ func (r *DocumentDBReconciler) AddClusterReplicationToClusterSpec(
 			Name: replicationContext.CNPGClusterName,
 			ConnectionParameters: map[string]string{
 				"host":   selfHost,
-				"port":   "5432",
+				"port":   strconv.Itoa(util.DEFAULT_POSTGRES_PORT),
 				"dbname": "postgres",
 				"user":   "postgres",
 			},
@@ -159,7 +171,7 @@ func (r *DocumentDBReconciler) AddClusterReplicationToClusterSpec(
 	for clusterName, serviceName := range replicationContext.GenerateExternalClusterServices(documentdb.Name, documentdb.Namespace, replicationContext.IsAzureFleetNetworking()) {
 		connectionParameters := map[string]string{
 			"host":   serviceName,
-			"port":   "5432",
+			"port":   strconv.Itoa(util.DEFAULT_POSTGRES_PORT),
 			"dbname": "postgres",
 			"user":   "streaming_replica",
 		}
@@ -218,18 +230,20 @@ func (r *DocumentDBReconciler) CreateIstioRemoteServices(ctx context.Context, re
 				ObjectMeta: metav1.ObjectMeta{
 					Name:      serviceNameRW,
 					Namespace: documentdb.Namespace,
-					Labels: map[string]string{
-						"cnpg.io/cluster": remoteCluster,
-						"replica_type":    "primary",
-					},
+					Labels: util.ChildLabels(documentdb, map[string]string{
+						util.LABEL_DOCUMENTDB_NAME: documentdb.Name,
+						"cnpg.io/cluster":          remoteCluster,
+						util.LABEL_REPLICA_TYPE:    "primary",
+					}),
+					Annotations: util.ChildAnnotations(documentdb, nil),
 				},
 				Spec: corev1.ServiceSpec{
 					Ports: []corev1.ServicePort{
 						{
 							Name:       "postgres",
-							Port:       5432,
+							Port:       util.DEFAULT_POSTGRES_PORT,
 							Protocol:   corev1.ProtocolTCP,
-							TargetPort: intstr.FromInt(5432),
+							TargetPort: intstr.FromInt(util.DEFAULT_POSTGRES_PORT),
 						},
 					},
 					Selector: map[string]string{
@@ -279,9 +293,10 @@ func (r *DocumentDBReconciler) CreateServiceImportAndExport(ctx context.Context,
 		if !exists {
 			ringServiceExport := &fleetv1alpha1.ServiceExport{
 				ObjectMeta: metav1.ObjectMeta{
-					Name:      serviceName,
-					Namespace: documentdb.Namespace,
-					Labels:    labels,
+					Name:        serviceName,
+					Namespace:   documentdb.Namespace,
+					Labels:      util.ChildLabels(documentdb, labels),
+					Annotations: util.ChildAnnotations(documentdb, nil),
 					OwnerReferences: []metav1.OwnerReference{
 						{
 							APIVersion:         documentdb.APIVersion,
@@ -294,7 +309,11 @@ func (r *DocumentDBReconciler) CreateServiceImportAndExport(ctx context.Context,
 					},
 				},
 			}
-			if err := r.Create(ctx, ringServiceExport); err != nil && !errors.IsAlreadyExists(err) {
+			if err := r.Create(ctx, ringServiceExport); errors.IsAlreadyExists(err) {
+				if err := r.checkReplicationServiceOwner(ctx, documentdb, &fleetv1alpha1.ServiceExport{}, serviceName); err != nil {
+					return err
+				}
+			} else if err != nil {
 				return err
 			}
 		} else { // if exists then we don't want to remove it
@@ -302,8 +321,13 @@ func (r *DocumentDBReconciler) CreateServiceImportAndExport(ctx context.Context,
 		}
 	}
 
-	// If it's still in the existingExports map, it means it's no longer needed and should be deleted
+	// If it's still in the existingExports map, it means it's no longer needed and should be deleted.
+	// This also removes the exports named by earlier versions of generateServiceName; CNPG removes
+	// the managed Services that leave its spec along with them.
 	for serviceName, export := range existingExports {
+		if !metav1.IsControlledBy(export, documentdb) {
+			continue
+		}
 		if err := r.Client.Delete(ctx, export); err != nil && !errors.IsNotFound(err) {
 			return fmt.Errorf("failed to delete ServiceExport %s: %w", serviceName, err)
 		}
@@ -331,9 +355,10 @@ func (r *DocumentDBReconciler) CreateServiceImportAndExport(ctx context.Context,
 			// Multi Cluster Service with owner reference to ensure cleanup
 			newMCS := &fleetv1alpha1.MultiClusterService{
 				ObjectMeta: metav1.ObjectMeta{
-					Name:      sourceServiceName,
-					Namespace: documentdb.Namespace,
-					Labels:    labels,
+					Name:        sourceServiceName,
+					Namespace:   documentdb.Namespace,
+					Labels:      util.ChildLabels(documentdb, labels),
+					Annotations: util.ChildAnnotations(documentdb, nil),
 					OwnerReferences: []metav1.OwnerReference{
 						{
 							APIVersion:         documentdb.APIVersion,
@@ -351,7 +376,11 @@ func (r *DocumentDBReconciler) CreateServiceImportAndExport(ctx context.Context,
 					},
 				},
 			}
-			if err := r.Create(ctx, newMCS); err != nil && !errors.IsAlreadyExists(err) {
+			if err := r.Create(ctx, newMCS); errors.IsAlreadyExists(err) {
+				if err := r.checkReplicationServiceOwner(ctx, documentdb, &fleetv1alpha1.MultiClusterService{}, sourceServiceName); err != nil {
+					return err
+				}
+			} else if err != nil {
 				return err
 			}
 		} else { // if exists then we don't want to remove it
@@ -361,6 +390,9 @@ func (r *DocumentDBReconciler) CreateServiceImportAndExport(ctx context.Context,
 
 	// If it's still in the existingMCS map, it means it's no longer needed and should be deleted
 	for serviceName, mcs := range existingMCS {
+		if !metav1.IsControlledBy(mcs, documentdb) {
+			continue
+		}
 		if err := r.Client.Delete(ctx, mcs); err != nil && !errors.IsNotFound(err) {
 			return fmt.Errorf("failed to delete MultiClusterService %s: %w", serviceName, err)
 		}
@@ -373,6 +405,19 @@ func (r *DocumentDBReconciler) CreateServiceImportAndExport(ctx context.Context,
 // replica list change).
 // It performs side effects (HTTP token reads, service creation, goroutines) but returns the
 // patch ops for the caller to include in the consolidated SyncCnpgCluster patch.
+// checkReplicationServiceOwner returns an error when the object named name,
+// which already exists, is not controlled by documentdb: the name generated
+// for its replication services is taken by another resource.
+func (r *DocumentDBReconciler) checkReplicationServiceOwner(ctx context.Context, documentdb *dbpreview.DocumentDB, existing client.Object, name string) error {
+	if err := r.Get(ctx, types.NamespacedName{Name: name, Namespace: documentdb.Namespace}, existing); err != nil {
+		return client.IgnoreNotFound(err)
+	}
+	if !metav1.IsControlledBy(existing, documentdb) {
+		return fmt.Errorf("replication service name %s is already taken by another resource in namespace %s", name, documentdb.Namespace)
+	}
+	return nil
+}
+
 func (r *DocumentDBReconciler) syncReplicationChanges(ctx context.Context, current, desired *cnpgv1.Cluster, documentdb *dbpreview.DocumentDB, replicationContext *util.ReplicationContext) ([]cnpg.JSONPatch, error, time.Duration) {
 	if current.Spec.ReplicaCluster == nil || desired.Spec.ReplicaCluster == nil {
 		// FOR NOW assume that we aren't going to turn on or off physical replication
@@ -461,9 +506,15 @@ func (r *DocumentDBReconciler) getPrimaryChangePatchOps(ctx context.Context, pat
 
 		log.Log.Info("Applying patch for Primary => Replica transition", "cluster", current.Name)
 
-		// push out the  promotion token when it's available
-		nn := types.NamespacedName{Name: current.Name, Namespace: current.Namespace}
-		go r.waitForDemotionTokenAndCreateService(nn, replicationContext)
+		// push out the  promotion token when it's available. The wait is recorded
+		// in status first so that it is resumed if the operator restarts before
+		// the token is published.
+		if documentdb.SetInProgressOperation(dbpreview.OperationDemotionTokenWait, current.Name, metav1.Now()) {
+			if err := r.updateStatus(ctx, documentdb); err != nil {
+				return fmt.Errorf("failed to record demotion token wait in status: %w", err), RequeueAfterShort
+			}
+		}
+		r.startDemotionTokenWait(documentdb, replicationContext)
 
 	} else if desired.Spec.ReplicaCluster.Primary == current.Spec.ReplicaCluster.Self {
 		// Replica => primary
@@ -545,13 +596,6 @@ func getReplicasChangePatchOps(patchOps *[]cnpg.JSONPatch, current, desired *cnp
 			Value: desired.Spec.PostgresConfiguration.PgHBA,
 		})
 	}
-	if externalClusterSpecChanged && replicationContext.IsAzureFleetNetworking() {
-		*patchOps = append(*patchOps, cnpg.JSONPatch{
-			Op:    cnpg.PatchOpReplace,
-			Path:  cnpg.PatchPathManagedServices,
-			Value: desired.Spec.Managed.Services.Additional,
-		})
-	}
 	if externalClusterSpecChanged && replicationContext.IsPrimary() {
 		currentSynchronous := current.Spec.PostgresConfiguration.Synchronous
 		desiredSynchronous := desired.Spec.PostgresConfiguration.Synchronous
@@ -575,7 +619,7 @@ func getReplicasChangePatchOps(patchOps *[]cnpg.JSONPatch, current, desired *cnp
 }
 
 func (r *DocumentDBReconciler) ReadToken(ctx context.Context, documentdb *dbpreview.DocumentDB, replicationContext *util.ReplicationContext) (string, error, time.Duration) {
-	tokenServiceName := "promotion-token"
+	tokenServiceName := promotionTokenName
 	namespace := documentdb.Namespace
 
 	// If we are not using cross-cloud networking, we only need to read the token from the configmap
@@ -602,9 +646,10 @@ func (r *DocumentDBReconciler) ReadToken(ctx context.Context, documentdb *dbprev
 				ObjectMeta: metav1.ObjectMeta{
 					Name:      tokenServiceName,
 					Namespace: namespace,
-					Labels: map[string]string{
-						"app": tokenServiceName,
-					},
+					Labels: util.ChildLabels(documentdb, map[string]string{
+						util.LABEL_APP: tokenServiceName,
+					}),
+					Annotations: util.ChildAnnotations(documentdb, nil),
 				},
 				Spec: corev1.ServiceSpec{
 					Ports: []corev1.ServicePort{
@@ -651,8 +696,10 @@ func (r *DocumentDBReconciler) ReadToken(ctx context.Context, documentdb *dbprev
 	if err != nil && errors.IsNotFound(err) {
 		foundMCS = &fleetv1alpha1.MultiClusterService{
 			ObjectMeta: metav1.ObjectMeta{
-				Name:      tokenServiceName,
-				Namespace: namespace,
+				Name:        tokenServiceName,
+				Namespace:   namespace,
+				Labels:      util.ChildLabels(documentdb, nil),
+				Annotations: util.ChildAnnotations(documentdb, nil),
 				OwnerReferences: []metav1.OwnerReference{
 					{
 						APIVersion:         documentdb.APIVersion,
@@ -693,30 +740,74 @@ func (r *DocumentDBReconciler) ReadToken(ctx context.Context, documentdb *dbprev
 	return string(token[:]), nil, -1
 }
 
-func (r *DocumentDBReconciler) waitForDemotionTokenAndCreateService(clusterNN types.NamespacedName, replicationContext *util.ReplicationContext) {
-	ctx := context.Background()
+// startDemotionTokenWait starts waiting for the demotion token recorded in the
+// DocumentDB status, unless the wait is already running in this process.
+func (r *DocumentDBReconciler) startDemotionTokenWait(documentdb *dbpreview.DocumentDB, replicationContext *util.ReplicationContext) {
+	op := documentdb.GetInProgressOperation(dbpreview.OperationDemotionTokenWait)
+	if op == nil {
+		return
+	}
+	if r.backgroundOps == nil {
+		r.backgroundOps = newBackgroundOperations()
+	}
+
+	documentdbNN := types.NamespacedName{Name: documentdb.Name, Namespace: documentdb.Namespace}
+	clusterNN := types.NamespacedName{Name: op.Target, Namespace: documentdb.Namespace}
+	// Bound the wait by the original start time so a resumed wait does not get a fresh timeout.
+	deadline := op.StartedAt.Add(demotionTokenWaitTimeout)
+	key := fmt.Sprintf("%s/%s/%s", dbpreview.OperationDemotionTokenWait, clusterNN.Namespace, clusterNN.Name)
+	r.backgroundOps.Go(key, func(ctx context.Context) {
+		r.waitForDemotionTokenAndCreateService(ctx, documentdbNN, clusterNN, deadline, replicationContext)
+	})
+}
+
+func (r *DocumentDBReconciler) waitForDemotionTokenAndCreateService(ctx context.Context, documentdbNN, clusterNN types.NamespacedName, deadline time.Time, replicationContext *util.ReplicationContext) {
 	ticker := time.NewTicker(demotionTokenPollInterval)
-	timeout := time.NewTimer(demotionTokenWaitTimeout)
+	timeout := time.NewTimer(time.Until(deadline))
 	defer ticker.Stop()
 	defer timeout.Stop()
 
 	for {
 		select {
 		case <-ticker.C:
-			done, err := r.ensureTokenServiceResources(ctx, clusterNN, replicationContext)
+			done, err := r.ensureTokenServiceResources(ctx, documentdbNN, clusterNN, replicationContext)
 			if err != nil {
 				log.Log.Error(err, "Failed to create token service resources", "cluster", clusterNN.Name)
 			}
 			if done {
+				r.clearInProgressOperation(ctx, documentdbNN, dbpreview.OperationDemotionTokenWait)
 				return
 			}
 		case <-timeout.C:
 			log.Log.Info("Timed out waiting for demotion token", "cluster", clusterNN.Name, "timeout", demotionTokenWaitTimeout)
+			r.clearInProgressOperation(ctx, documentdbNN, dbpreview.OperationDemotionTokenWait)
+			return
+		case <-ctx.Done():
+			// The operator is shutting down. The wait stays recorded in status
+			// and is resumed by the next leader.
+			log.Log.Info("Stopping demotion token wait for operator shutdown", "cluster", clusterNN.Name)
 			return
 		}
 	}
 }
 
+// clearInProgressOperation removes a completed operation from the DocumentDB status.
+func (r *DocumentDBReconciler) clearInProgressOperation(ctx context.Context, documentdbNN types.NamespacedName, opType dbpreview.OperationType) {
+	err := retry.RetryOnConflict(retry.DefaultRetry, func() error {
+		documentdb := &dbpreview.DocumentDB{}
+		if err := r.Client.Get(ctx, documentdbNN, documentdb); err != nil {
+			return client.IgnoreNotFound(err)
+		}
+		if !documentdb.ClearInProgressOperation(opType) {
+			return nil
+		}
+		return r.Status().Update(ctx, documentdb)
+	})
+	if err != nil {
+		log.Log.Error(err, "Failed to clear in-progress operation", "documentdb", documentdbNN, "operation", opType)
+	}
+}
+
 // CleanupMismatchedServiceImports finds and removes ServiceImports that have no ownerReferences
 // and are marked as "in-use-by" the current cluster.
 // RETURNS: Whether or not a deletion occurred, and error if any error occurs during the process
@@ -833,7 +924,12 @@ func containsClusterName(inUseBy, clusterName string) bool {
 }
 
 // Returns true when token service resources are ready
-func (r *DocumentDBReconciler) ensureTokenServiceResources(ctx context.Context, clusterNN types.NamespacedName, replicationContext *util.ReplicationContext) (bool, error) {
+func (r *DocumentDBReconciler) ensureTokenServiceResources(ctx context.Context, documentdbNN, clusterNN types.NamespacedName, replicationContext *util.ReplicationContext) (bool, error) {
+	documentdb := &dbpreview.DocumentDB{}
+	if err := r.Client.Get(ctx, documentdbNN, documentdb); err != nil {
+		return false, err
+	}
+
 	cluster := &cnpgv1.Cluster{}
 	if err := r.Client.Get(ctx, clusterNN, cluster); err != nil {
 		return false, err
@@ -844,16 +940,18 @@ func (r *DocumentDBReconciler) ensureTokenServiceResources(ctx context.Context,
 		return false, nil
 	}
 
-	tokenServiceName := "promotion-token"
+	tokenServiceName := promotionTokenName
 	labels := map[string]string{
-		"app": tokenServiceName,
+		util.LABEL_APP: tokenServiceName,
 	}
 
 	// Create ConfigMap with token and nginx config
 	configMap := &corev1.ConfigMap{
 		ObjectMeta: metav1.ObjectMeta{
-			Name:      tokenServiceName,
-			Namespace: clusterNN.Namespace,
+			Name:        tokenServiceName,
+			Namespace:   clusterNN.Namespace,
+			Labels:      util.ChildLabels(documentdb, nil),
+			Annotations: util.ChildAnnotations(documentdb, nil),
 		},
 		Data: map[string]string{
 			"index.html": token,
@@ -881,15 +979,17 @@ func (r *DocumentDBReconciler) ensureTokenServiceResources(ctx context.Context,
 	// Create nginx Pod
 	pod := &corev1.Pod{
 		ObjectMeta: metav1.ObjectMeta{
-			Name:      tokenServiceName,
-			Namespace: clusterNN.Namespace,
-			Labels:    labels,
+			Name:        tokenServiceName,
+			Namespace:   clusterNN.Namespace,
+			Labels:      util.ChildLabels(documentdb, labels),
+			Annotations: util.ChildAnnotations(documentdb, nil),
 		},
 		Spec: corev1.PodSpec{
 			Containers: []corev1.Container{
 				{
-					Name:  "nginx",
-					Image: "nginx:alpine",
+					Name:            "nginx",
+					Image:           "nginx:alpine",
+					ImagePullPolicy: documentdb.Spec.ImagePullPolicy,
 					Ports: []corev1.ContainerPort{
 						{
 							ContainerPort: 80,
@@ -927,9 +1027,10 @@ func (r *DocumentDBReconciler) ensureTokenServiceResources(ctx context.Context,
 	// Create Service
 	service := &corev1.Service{
 		ObjectMeta: metav1.ObjectMeta{
-			Name:      tokenServiceName,
-			Namespace: clusterNN.Namespace,
-			Labels:    labels,
+			Name:        tokenServiceName,
+			Namespace:   clusterNN.Namespace,
+			Labels:      util.ChildLabels(documentdb, labels),
+			Annotations: util.ChildAnnotations(documentdb, nil),
 		},
 		Spec: corev1.ServiceSpec{
 			Selector: labels,
@@ -952,8 +1053,10 @@ func (r *DocumentDBReconciler) ensureTokenServiceResources(ctx context.Context,
 	if replicationContext.IsAzureFleetNetworking() {
 		serviceExport := &fleetv1alpha1.ServiceExport{
 			ObjectMeta: metav1.ObjectMeta{
-				Name:      tokenServiceName,
-				Namespace: clusterNN.Namespace,
+				Name:        tokenServiceName,
+				Namespace:   clusterNN.Namespace,
+				Labels:      util.ChildLabels(documentdb, nil),
+				Annotations: util.ChildAnnotations(documentdb, nil),
 				OwnerReferences: []metav1.OwnerReference{
 					{
 						APIVersion:         cluster.APIVersion,
@@ -7,12 +7,14 @@ import (
 	cnpgv1 "github.com/cloudnative-pg/cloudnative-pg/api/v1"
 	. "github.com/onsi/ginkgo/v2"
 	. "github.com/onsi/gomega"
+	fleetv1alpha1 "go.goms.io/fleet-networking/api/v1alpha1"
 	corev1 "k8s.io/api/core/v1"
 	rbacv1 "k8s.io/api/rbac/v1"
 	"k8s.io/apimachinery/pkg/api/errors"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/runtime"
 	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/utils/ptr"
 	ctrl "sigs.k8s.io/controller-runtime"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 	"sigs.k8s.io/controller-runtime/pkg/client/fake"
@@ -317,7 +319,7 @@ var _ = Describe("Physical Replication", func() {
 		desired := current.DeepCopy()
 		desired.Spec.ReplicaCluster.Primary = "cluster-b"
 
-		reconciler := buildDocumentDBReconciler(current)
+		reconciler := buildDocumentDBReconciler(current, documentdb)
 		replicationContext := &util.ReplicationContext{
 			OtherCNPGClusterNames: []string{"cluster-b"},
 		}
@@ -326,6 +328,14 @@ var _ = Describe("Physical Replication", func() {
 		Expect(err).ToNot(HaveOccurred())
 		Expect(requeue).To(Equal(time.Duration(-1)))
 		Expect(patchOps).ToNot(BeEmpty())
+
+		// The token wait is persisted so that a new leader can resume it
+		persisted := &dbpreview.DocumentDB{}
+		Expect(reconciler.Get(ctx, types.NamespacedName{Name: documentdb.Name, Namespace: namespace}, persisted)).To(Succeed())
+		op := persisted.GetInProgressOperation(dbpreview.OperationDemotionTokenWait)
+		Expect(op).ToNot(BeNil())
+		Expect(op.Target).To(Equal("docdb-p2r"))
+
 		// Should have bootstrap remove and replica cluster replace
 		hasBootstrapRemove := false
 		hasReplicaReplace := false
@@ -389,7 +399,7 @@ var _ = Describe("Physical Replication", func() {
 		desired.Spec.Instances = 1
 		desired.Spec.Plugins = []cnpgv1.PluginConfiguration{{Name: "my-plugin-updated"}}
 
-		reconciler := buildDocumentDBReconciler(current)
+		reconciler := buildDocumentDBReconciler(current, documentdb)
 		replicationContext := &util.ReplicationContext{
 			OtherCNPGClusterNames: []string{"cluster-b"},
 		}
@@ -1241,3 +1251,204 @@ var _ = Describe("AddClusterReplicationToClusterSpec - cert management fields",
 		}))
 	})
 })
+
+var _ = Describe("AddClusterReplicationToClusterSpec - fleet managed services", func() {
+	It("appends the fleet services to the additional services of the spec", func() {
+		ctx := context.Background()
+		namespace := "default"
+
+		scheme := runtime.NewScheme()
+		Expect(dbpreview.AddToScheme(scheme)).To(Succeed())
+		Expect(cnpgv1.AddToScheme(scheme)).To(Succeed())
+		Expect(fleetv1alpha1.AddToScheme(scheme)).To(Succeed())
+		reconciler := &DocumentDBReconciler{
+			Client: fake.NewClientBuilder().WithScheme(scheme).Build(),
+			Scheme: scheme,
+		}
+
+		documentdb := baseDocumentDB("docdb-fleet", namespace)
+		documentdb.Spec.ClusterReplication = &dbpreview.ClusterReplication{
+			CrossCloudNetworkingStrategy: string(util.AzureFleet),
+			Primary:                      "cluster-a",
+			DisableTLS:                   true,
+			ClusterList: []dbpreview.MemberCluster{
+				{Name: "cluster-a"},
+				{Name: "cluster-b"},
+			},
+		}
+		userService := cnpgv1.ManagedService{
+			SelectorType:    cnpgv1.ServiceSelectorTypeRO,
+			UpdateStrategy:  cnpgv1.ServiceUpdateStrategyPatch,
+			ServiceTemplate: cnpgv1.ServiceTemplateSpec{ObjectMeta: cnpgv1.Metadata{Name: "docdb-fleet-reads"}},
+		}
+		cnpgCluster := &cnpgv1.Cluster{
+			ObjectMeta: metav1.ObjectMeta{Name: "docdb-fleet", Namespace: namespace},
+			Spec: cnpgv1.ClusterSpec{
+				InheritedMetadata: &cnpgv1.EmbeddedObjectMetadata{Labels: map[string]string{}},
+				Managed: &cnpgv1.ManagedConfiguration{
+					Services: &cnpgv1.ManagedServices{Additional: []cnpgv1.ManagedService{userService}},
+				},
+			},
+		}
+		replicationContext := &util.ReplicationContext{
+			CNPGClusterName:              "docdb-fleet-local",
+			OtherCNPGClusterNames:        []string{"docdb-fleet-remote"},
+			PrimaryCNPGClusterName:       "docdb-fleet-local",
+			CrossCloudNetworkingStrategy: util.AzureFleet,
+		}
+
+		Expect(reconciler.AddClusterReplicationToClusterSpec(ctx, documentdb, replicationContext, cnpgCluster)).To(Succeed())
+
+		services := cnpgCluster.Spec.Managed.Services.Additional
+		Expect(services).To(HaveLen(2))
+		Expect(services[0]).To(Equal(userService))
+		Expect(services[1].SelectorType).To(Equal(cnpgv1.ServiceSelectorTypeRW))
+		for serviceName := range replicationContext.GenerateOutgoingServiceNames(documentdb.Name, namespace) {
+			Expect(services[1].ServiceTemplate.ObjectMeta.Name).To(Equal(serviceName))
+		}
+	})
+
+	It("refuses a replication service name taken by another resource", func() {
+		ctx := context.Background()
+		namespace := "default"
+
+		scheme := runtime.NewScheme()
+		Expect(dbpreview.AddToScheme(scheme)).To(Succeed())
+		Expect(fleetv1alpha1.AddToScheme(scheme)).To(Succeed())
+		documentdb := baseDocumentDB("docdb-fleet", namespace)
+		documentdb.UID = "docdb-fleet-uid"
+		replicationContext := &util.ReplicationContext{
+			CNPGClusterName:              "docdb-fleet-local",
+			OtherCNPGClusterNames:        []string{"docdb-fleet-remote"},
+			CrossCloudNetworkingStrategy: util.AzureFleet,
+		}
+		var serviceName string
+		for name := range replicationContext.GenerateOutgoingServiceNames(documentdb.Name, namespace) {
+			serviceName = name
+		}
+		foreign := &fleetv1alpha1.ServiceExport{ObjectMeta: metav1.ObjectMeta{Name: serviceName, Namespace: namespace}}
+		reconciler := &DocumentDBReconciler{
+			Client: fake.NewClientBuilder().WithScheme(scheme).WithObjects(foreign).Build(),
+			Scheme: scheme,
+		}
+
+		err := reconciler.CreateServiceImportAndExport(ctx, replicationContext, documentdb)
+		Expect(err).To(MatchError(ContainSubstring("already taken by another resource")))
+	})
+
+	It("replaces the exports of the service names of earlier versions", func() {
+		ctx := context.Background()
+		namespace := "default"
+
+		scheme := runtime.NewScheme()
+		Expect(dbpreview.AddToScheme(scheme)).To(Succeed())
+		Expect(fleetv1alpha1.AddToScheme(scheme)).To(Succeed())
+		documentdb := baseDocumentDB("docdb-fleet", namespace)
+		documentdb.UID = "docdb-fleet-uid"
+		replicationContext := &util.ReplicationContext{
+			CNPGClusterName:              "docdb-fleet-local",
+			OtherCNPGClusterNames:        []string{"docdb-fleet-remote"},
+			CrossCloudNetworkingStrategy: util.AzureFleet,
+		}
+		// Names cut into the hash, as earlier versions did for long resource groups
+		legacy := func() metav1.ObjectMeta {
+			return metav1.ObjectMeta{
+				Name:      "docdb-fleet-8f1c2e",
+				Namespace: namespace,
+				Labels:    map[string]string{util.LABEL_DOCUMENTDB_NAME: documentdb.Name},
+				OwnerReferences: []metav1.OwnerReference{{
+					APIVersion: "documentdb.io/preview", Kind: "DocumentDB", Name: documentdb.Name,
+					UID: documentdb.UID, Controller: ptr.To(true),
+				}},
+			}
+		}
+		foreign := &fleetv1alpha1.ServiceExport{ObjectMeta: metav1.ObjectMeta{
+			Name:      "docdb-fleet-other",
+			Namespace: namespace,
+			Labels:    map[string]string{util.LABEL_DOCUMENTDB_NAME: documentdb.Name},
+		}}
+		c := fake.NewClientBuilder().WithScheme(scheme).WithObjects(
+			&fleetv1alpha1.ServiceExport{ObjectMeta: legacy()},
+			&fleetv1alpha1.MultiClusterService{ObjectMeta: legacy()},
+			foreign,
+		).Build()
+		reconciler := &DocumentDBReconciler{Client: c, Scheme: scheme}
+
+		Expect(reconciler.CreateServiceImportAndExport(ctx, replicationContext, documentdb)).To(Succeed())
+
+		exports := &fleetv1alpha1.ServiceExportList{}
+		Expect(c.List(ctx, exports, client.InNamespace(namespace))).To(Succeed())
+		var exportNames []string
+		for _, export := range exports.Items {
+			exportNames = append(exportNames, export.Name)
+		}
+		var outgoing string
+		for name := range replicationContext.GenerateOutgoingServiceNames(documentdb.Name, namespace) {
+			outgoing = name
+		}
+		Expect(exportNames).To(ConsistOf(outgoing, foreign.Name))
+
+		services := &fleetv1alpha1.MultiClusterServiceList{}
+		Expect(c.List(ctx, services, client.InNamespace(namespace))).To(Succeed())
+		Expect(services.Items).To(HaveLen(1))
+		for name := range replicationContext.GenerateIncomingServiceNames(documentdb.Name, namespace) {
+			Expect(services.Items[0].Name).To(Equal(name))
+		}
+	})
+})
+
+var _ = Describe("Replication resource labels", func() {
+	var (
+		ctx        context.Context
+		documentdb *dbpreview.DocumentDB
+	)
+
+	BeforeEach(func() {
+		ctx = context.Background()
+		documentdb = baseDocumentDB("docdb", "default")
+		documentdb.Spec.CostLabels = map[string]string{"team": "payments"}
+		documentdb.Spec.InheritedMetadata = &dbpreview.InheritedMetadata{
+			Annotations: map[string]string{"sidecar.istio.io/inject": "true"},
+		}
+	})
+
+	It("labels the Istio placeholder Services like every other child resource", func() {
+		r := buildDocumentDBReconciler(documentdb)
+		replicationContext := &util.ReplicationContext{
+			OtherCNPGClusterNames:        []string{"docdb-remote"},
+			CrossCloudNetworkingStrategy: util.Istio,
+		}
+
+		Expect(r.CreateIstioRemoteServices(ctx, replicationContext, documentdb)).To(Succeed())
+
+		service := &corev1.Service{}
+		Expect(r.Get(ctx, types.NamespacedName{Name: "docdb-remote-rw", Namespace: "default"}, service)).To(Succeed())
+		Expect(service.Labels).To(Equal(map[string]string{
+			"team":                     "payments",
+			util.LABEL_DOCUMENTDB_NAME: "docdb",
+			"cnpg.io/cluster":          "docdb-remote",
+			util.LABEL_REPLICA_TYPE:    "primary",
+		}))
+		Expect(service.Annotations).To(HaveKeyWithValue("sidecar.istio.io/inject", "true"))
+	})
+
+	It("labels the promotion token resources", func() {
+		cluster := &cnpgv1.Cluster{
+			ObjectMeta: metav1.ObjectMeta{Name: "docdb-local", Namespace: "default"},
+			Status:     cnpgv1.ClusterStatus{DemotionToken: "token"},
+		}
+		r := buildDocumentDBReconciler(documentdb, cluster)
+
+		done, err := r.ensureTokenServiceResources(ctx,
+			types.NamespacedName{Name: "docdb", Namespace: "default"},
+			types.NamespacedName{Name: "docdb-local", Namespace: "default"},
+			&util.ReplicationContext{CrossCloudNetworkingStrategy: util.None})
+		Expect(err).ToNot(HaveOccurred())
+		Expect(done).To(BeTrue())
+
+		configMap := &corev1.ConfigMap{}
+		Expect(r.Get(ctx, types.NamespacedName{Name: promotionTokenName, Namespace: "default"}, configMap)).To(Succeed())
+		Expect(configMap.Labels).To(Equal(map[string]string{"team": "payments"}))
+		Expect(configMap.Data).To(HaveKeyWithValue("index.html", "token"))
+	})
+})
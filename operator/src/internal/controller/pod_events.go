@@ -0,0 +1,124 @@
+// Copyright (c) Microsoft Corporation.
+// Licensed under the MIT License.
+
+package controller
+
+import (
+	"context"
+	"fmt"
+	"slices"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/client-go/util/workqueue"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/event"
+	"sigs.k8s.io/controller-runtime/pkg/handler"
+	"sigs.k8s.io/controller-runtime/pkg/log"
+	"sigs.k8s.io/controller-runtime/pkg/predicate"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+
+	dbpreview "github.com/documentdb/documentdb-operator/api/preview"
+	util "github.com/documentdb/documentdb-operator/internal/utils"
+)
+
+// podWaitingProblems are the waiting reasons of an instance container that
+// are re-emitted as events on its DocumentDB.
+var podWaitingProblems = []string{"CrashLoopBackOff", "ImagePullBackOff", "ErrImagePull", "CreateContainerConfigError"}
+
+// podProblem is a container of an instance Pod that is failing.
+type podProblem struct {
+	container string
+	reason    string
+	message   string
+	// restartCount tells OOM kills of the same container apart.
+	restartCount int32
+}
+
+// podProblems returns the failing containers of pod: those waiting for one of
+// podWaitingProblems and those last terminated by the OOM killer.
+func podProblems(pod *corev1.Pod) []podProblem {
+	var problems []podProblem
+	statuses := slices.Concat(pod.Status.InitContainerStatuses, pod.Status.ContainerStatuses)
+	for _, status := range statuses {
+		if waiting := status.State.Waiting; waiting != nil && slices.Contains(podWaitingProblems, waiting.Reason) {
+			problems = append(problems, podProblem{container: status.Name, reason: waiting.Reason, message: waiting.Message})
+		}
+		if terminated := status.LastTerminationState.Terminated; terminated != nil && terminated.Reason == "OOMKilled" {
+			problems = append(problems, podProblem{
+				container:    status.Name,
+				reason:       terminated.Reason,
+				message:      fmt.Sprintf("exit code %d", terminated.ExitCode),
+				restartCount: status.RestartCount,
+			})
+		}
+	}
+	return problems
+}
+
+// cnpgPodPredicate only lets through the Pods of CNPG clusters, the only ones
+// the manager caches (see util.CNPGPodsCacheOptions).
+func cnpgPodPredicate() predicate.Predicate {
+	return predicate.NewPredicateFuncs(func(obj client.Object) bool {
+		return obj.GetLabels()["cnpg.io/cluster"] != ""
+	})
+}
+
+// podEventHandler re-emits the problems that newly appear on the instance
+// Pods as Warning events on their DocumentDB, and requeues it to refresh
+// status.instances.
+func (r *DocumentDBReconciler) podEventHandler() handler.EventHandler {
+	return handler.Funcs{
+		UpdateFunc: func(ctx context.Context, e event.UpdateEvent, q workqueue.TypedRateLimitingInterface[reconcile.Request]) {
+			oldPod, oldOK := e.ObjectOld.(*corev1.Pod)
+			newPod, newOK := e.ObjectNew.(*corev1.Pod)
+			if !oldOK || !newOK {
+				return
+			}
+			if request := r.reportPodProblems(ctx, oldPod, newPod); request != nil {
+				q.Add(*request)
+			}
+		},
+	}
+}
+
+// reportPodProblems records an event on the DocumentDB of newPod for each
+// problem newPod has and oldPod did not. It returns the request of the
+// DocumentDB when an event was recorded.
+func (r *DocumentDBReconciler) reportPodProblems(ctx context.Context, oldPod, newPod *corev1.Pod) *reconcile.Request {
+	name := newPod.Labels[util.LABEL_APP]
+	if name == "" || newPod.Labels["cnpg.io/cluster"] == "" || r.Recorder == nil {
+		return nil
+	}
+	previous := podProblems(oldPod)
+	var problems []podProblem
+	for _, problem := range podProblems(newPod) {
+		// The kubelet message changes with each back-off; only a new reason
+		// or OOM kill is reported.
+		if !slices.ContainsFunc(previous, func(p podProblem) bool {
+			return p.container == problem.container && p.reason == problem.reason && p.restartCount == problem.restartCount
+		}) {
+			problems = append(problems, problem)
+		}
+	}
+	if len(problems) == 0 {
+		return nil
+	}
+
+	documentdb := &dbpreview.DocumentDB{}
+	key := client.ObjectKey{Name: name, Namespace: newPod.Namespace}
+	if err := r.Get(ctx, key, documentdb); err != nil {
+		if !errors.IsNotFound(err) {
+			log.FromContext(ctx).Error(err, "Failed to get DocumentDB for Pod event", "pod", newPod.Name)
+		}
+		return nil
+	}
+	for _, problem := range problems {
+		message := fmt.Sprintf("Container %s of Pod %s: %s", problem.container, newPod.Name, problem.reason)
+		if problem.message != "" {
+			message += ": " + problem.message
+		}
+		r.Recorder.Event(documentdb, corev1.EventTypeWarning, problem.reason, message)
+	}
+	return &reconcile.Request{NamespacedName: key}
+}
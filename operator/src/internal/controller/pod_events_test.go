@@ -0,0 +1,111 @@
+// Copyright (c) Microsoft Corporation.
+// Licensed under the MIT License.
+
+package controller
+
+import (
+	"context"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/tools/record"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+	"sigs.k8s.io/controller-runtime/pkg/event"
+
+	dbpreview "github.com/documentdb/documentdb-operator/api/preview"
+	util "github.com/documentdb/documentdb-operator/internal/utils"
+)
+
+var _ = Describe("Pod events", func() {
+	const namespace = "default"
+
+	var (
+		ctx        context.Context
+		recorder   *record.FakeRecorder
+		reconciler *DocumentDBReconciler
+	)
+
+	BeforeEach(func() {
+		ctx = context.Background()
+		scheme := runtime.NewScheme()
+		Expect(dbpreview.AddToScheme(scheme)).To(Succeed())
+		Expect(corev1.AddToScheme(scheme)).To(Succeed())
+		recorder = record.NewFakeRecorder(10)
+		reconciler = &DocumentDBReconciler{
+			Client: fake.NewClientBuilder().WithScheme(scheme).WithObjects(&dbpreview.DocumentDB{
+				ObjectMeta: metav1.ObjectMeta{Name: "db", Namespace: namespace},
+			}).Build(),
+			Scheme:   scheme,
+			Recorder: recorder,
+		}
+	})
+
+	instancePod := func(statuses ...corev1.ContainerStatus) *corev1.Pod {
+		return &corev1.Pod{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      "db-1",
+				Namespace: namespace,
+				Labels:    map[string]string{util.LABEL_APP: "db", "cnpg.io/cluster": "db"},
+			},
+			Status: corev1.PodStatus{ContainerStatuses: statuses},
+		}
+	}
+	running := corev1.ContainerStatus{Name: "postgres", State: corev1.ContainerState{Running: &corev1.ContainerStateRunning{}}}
+	crashLooping := func(message string) corev1.ContainerStatus {
+		return corev1.ContainerStatus{
+			Name:  "postgres",
+			State: corev1.ContainerState{Waiting: &corev1.ContainerStateWaiting{Reason: "CrashLoopBackOff", Message: message}},
+		}
+	}
+	oomKilled := func(restartCount int32) corev1.ContainerStatus {
+		return corev1.ContainerStatus{
+			Name:                 "documentdb-gateway",
+			RestartCount:         restartCount,
+			State:                corev1.ContainerState{Running: &corev1.ContainerStateRunning{}},
+			LastTerminationState: corev1.ContainerState{Terminated: &corev1.ContainerStateTerminated{Reason: "OOMKilled", ExitCode: 137}},
+		}
+	}
+
+	It("re-emits a container entering CrashLoopBackOff on the DocumentDB", func() {
+		request := reconciler.reportPodProblems(ctx, instancePod(running), instancePod(crashLooping("back-off 10s")))
+		Expect(request).ToNot(BeNil())
+		Expect(request.NamespacedName).To(Equal(types.NamespacedName{Name: "db", Namespace: namespace}))
+		Expect(recorder.Events).To(Receive(And(
+			ContainSubstring("Warning CrashLoopBackOff"),
+			ContainSubstring("Container postgres of Pod db-1"),
+		)))
+	})
+
+	It("does not repeat a problem the Pod already had", func() {
+		request := reconciler.reportPodProblems(ctx, instancePod(crashLooping("back-off 10s")), instancePod(crashLooping("back-off 20s")))
+		Expect(request).To(BeNil())
+		Expect(recorder.Events).To(BeEmpty())
+	})
+
+	It("reports each OOM kill", func() {
+		Expect(reconciler.reportPodProblems(ctx, instancePod(running), instancePod(oomKilled(1)))).ToNot(BeNil())
+		Expect(recorder.Events).To(Receive(ContainSubstring("Warning OOMKilled")))
+
+		Expect(reconciler.reportPodProblems(ctx, instancePod(oomKilled(1)), instancePod(oomKilled(1)))).To(BeNil())
+		Expect(reconciler.reportPodProblems(ctx, instancePod(oomKilled(1)), instancePod(oomKilled(2)))).ToNot(BeNil())
+		Expect(recorder.Events).To(Receive(ContainSubstring("Warning OOMKilled")))
+	})
+
+	It("ignores Pods that do not belong to a DocumentDB", func() {
+		pod := instancePod(crashLooping("back-off 10s"))
+		pod.Labels[util.LABEL_APP] = "other"
+		Expect(reconciler.reportPodProblems(ctx, instancePod(running), pod)).To(BeNil())
+		Expect(recorder.Events).To(BeEmpty())
+	})
+	It("only watches the Pods of CNPG clusters", func() {
+		predicate := cnpgPodPredicate()
+		Expect(predicate.Generic(event.GenericEvent{Object: instancePod(running)})).To(BeTrue())
+		Expect(predicate.Generic(event.GenericEvent{Object: &corev1.Pod{
+			ObjectMeta: metav1.ObjectMeta{Name: "web", Namespace: namespace, Labels: map[string]string{"app": "web"}},
+		}})).To(BeFalse())
+	})
+})
@@ -0,0 +1,85 @@
+// Copyright (c) Microsoft Corporation.
+// Licensed under the MIT License.
+
+package controller
+
+import (
+	"context"
+	"crypto/x509"
+	"encoding/pem"
+	"fmt"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/types"
+
+	dbpreview "github.com/documentdb/documentdb-operator/api/preview"
+)
+
+// replicationCertificateCommonName is the PostgreSQL role the replication
+// client certificate authenticates as.
+const replicationCertificateCommonName = "streaming_replica"
+
+// postgresCertificateFailures returns a message for each Secret of
+// spec.tls.postgres that CNPG could not use: a missing Secret, a missing key,
+// or a certificate that does not parse. The replication client certificate
+// must also be issued to streaming_replica, the role it logs in as.
+func (r *DocumentDBReconciler) postgresCertificateFailures(ctx context.Context, documentdb *dbpreview.DocumentDB) ([]string, error) {
+	if documentdb.Spec.TLS == nil || documentdb.Spec.TLS.Postgres == nil {
+		return nil, nil
+	}
+	certificates := documentdb.Spec.TLS.Postgres
+	secrets := []struct {
+		field string
+		name  string
+		keys  []string
+	}{
+		{"serverTLSSecret", certificates.ServerTLSSecret, []string{corev1.TLSCertKey, corev1.TLSPrivateKeyKey}},
+		{"serverCASecret", certificates.ServerCASecret, []string{"ca.crt"}},
+		{"replicationTLSSecret", certificates.ReplicationTLSSecret, []string{corev1.TLSCertKey, corev1.TLSPrivateKeyKey}},
+		{"clientCASecret", certificates.ClientCASecret, []string{"ca.crt"}},
+	}
+
+	var failures []string
+	for _, ref := range secrets {
+		if ref.name == "" {
+			continue
+		}
+		secret := &corev1.Secret{}
+		if err := r.Get(ctx, types.NamespacedName{Name: ref.name, Namespace: documentdb.Namespace}, secret); err != nil {
+			if !errors.IsNotFound(err) {
+				return nil, fmt.Errorf("failed to get Secret %s: %w", ref.name, err)
+			}
+			failures = append(failures, fmt.Sprintf("spec.tls.postgres.%s: Secret %q does not exist", ref.field, ref.name))
+			continue
+		}
+		for _, key := range ref.keys {
+			if len(secret.Data[key]) == 0 {
+				failures = append(failures, fmt.Sprintf("spec.tls.postgres.%s: Secret %q has no %s", ref.field, ref.name, key))
+			}
+		}
+		data := secret.Data[ref.keys[0]]
+		if len(data) == 0 {
+			continue
+		}
+		certificate, err := parseCertificate(data)
+		if err != nil {
+			failures = append(failures, fmt.Sprintf("spec.tls.postgres.%s: %s of Secret %q: %v", ref.field, ref.keys[0], ref.name, err))
+			continue
+		}
+		if ref.field == "replicationTLSSecret" && certificate.Subject.CommonName != replicationCertificateCommonName {
+			failures = append(failures, fmt.Sprintf("spec.tls.postgres.%s: the certificate of Secret %q is issued to %q, not %q",
+				ref.field, ref.name, certificate.Subject.CommonName, replicationCertificateCommonName))
+		}
+	}
+	return failures, nil
+}
+
+// parseCertificate parses the first certificate of the PEM data.
+func parseCertificate(data []byte) (*x509.Certificate, error) {
+	block, _ := pem.Decode(data)
+	if block == nil || block.Type != "CERTIFICATE" {
+		return nil, fmt.Errorf("no PEM-encoded certificate")
+	}
+	return x509.ParseCertificate(block.Bytes)
+}
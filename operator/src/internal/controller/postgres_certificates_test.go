@@ -0,0 +1,143 @@
+// Copyright (c) Microsoft Corporation.
+// Licensed under the MIT License.
+
+package controller
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"math/big"
+	"time"
+
+	cnpgv1 "github.com/cloudnative-pg/cloudnative-pg/api/v1"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	dbpreview "github.com/documentdb/documentdb-operator/api/preview"
+)
+
+var _ = Describe("postgresCertificateFailures", func() {
+	const namespace = "default"
+
+	var (
+		ctx        context.Context
+		scheme     *runtime.Scheme
+		documentdb *dbpreview.DocumentDB
+	)
+
+	BeforeEach(func() {
+		ctx = context.Background()
+		scheme = runtime.NewScheme()
+		Expect(corev1.AddToScheme(scheme)).To(Succeed())
+		documentdb = &dbpreview.DocumentDB{
+			ObjectMeta: metav1.ObjectMeta{Name: "db", Namespace: namespace},
+			Spec: dbpreview.DocumentDBSpec{TLS: &dbpreview.TLSConfiguration{Postgres: &cnpgv1.CertificatesConfiguration{
+				ServerTLSSecret:      "server",
+				ServerCASecret:       "server-ca",
+				ReplicationTLSSecret: "replication",
+				ClientCASecret:       "client-ca",
+			}}},
+		}
+	})
+
+	certificatePEM := func(commonName string) []byte {
+		key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+		Expect(err).ToNot(HaveOccurred())
+		template := &x509.Certificate{
+			SerialNumber: big.NewInt(1),
+			Subject:      pkix.Name{CommonName: commonName},
+			NotBefore:    time.Now(),
+			NotAfter:     time.Now().Add(time.Hour),
+		}
+		der, err := x509.CreateCertificate(rand.Reader, template, template, &key.PublicKey, key)
+		Expect(err).ToNot(HaveOccurred())
+		return pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: der})
+	}
+
+	tlsSecret := func(name, commonName string) *corev1.Secret {
+		return &corev1.Secret{
+			ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: namespace},
+			Type:       corev1.SecretTypeTLS,
+			Data: map[string][]byte{
+				corev1.TLSCertKey:       certificatePEM(commonName),
+				corev1.TLSPrivateKeyKey: []byte("key"),
+			},
+		}
+	}
+
+	caSecret := func(name string) *corev1.Secret {
+		return &corev1.Secret{
+			ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: namespace},
+			Data:       map[string][]byte{"ca.crt": certificatePEM("ca")},
+		}
+	}
+
+	newReconciler := func(objs ...client.Object) *DocumentDBReconciler {
+		return &DocumentDBReconciler{
+			Client: fake.NewClientBuilder().WithScheme(scheme).WithObjects(objs...).Build(),
+			Scheme: scheme,
+		}
+	}
+
+	It("accepts usable certificates", func() {
+		r := newReconciler(tlsSecret("server", "db-rw"), caSecret("server-ca"),
+			tlsSecret("replication", "streaming_replica"), caSecret("client-ca"))
+
+		failures, err := r.postgresCertificateFailures(ctx, documentdb)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(failures).To(BeEmpty())
+	})
+
+	It("has nothing to check without spec.tls.postgres", func() {
+		documentdb.Spec.TLS = nil
+
+		failures, err := newReconciler().postgresCertificateFailures(ctx, documentdb)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(failures).To(BeEmpty())
+	})
+
+	It("reports missing Secrets and keys", func() {
+		server := tlsSecret("server", "db-rw")
+		delete(server.Data, corev1.TLSPrivateKeyKey)
+		r := newReconciler(server, tlsSecret("replication", "streaming_replica"), caSecret("client-ca"))
+
+		failures, err := r.postgresCertificateFailures(ctx, documentdb)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(failures).To(ConsistOf(
+			`spec.tls.postgres.serverTLSSecret: Secret "server" has no tls.key`,
+			`spec.tls.postgres.serverCASecret: Secret "server-ca" does not exist`,
+		))
+	})
+
+	It("reports a certificate that does not parse", func() {
+		serverCA := caSecret("server-ca")
+		serverCA.Data["ca.crt"] = []byte("not a certificate")
+		r := newReconciler(tlsSecret("server", "db-rw"), serverCA,
+			tlsSecret("replication", "streaming_replica"), caSecret("client-ca"))
+
+		failures, err := r.postgresCertificateFailures(ctx, documentdb)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(failures).To(ConsistOf(ContainSubstring(`spec.tls.postgres.serverCASecret: ca.crt of Secret "server-ca"`)))
+	})
+
+	It("reports a replication certificate not issued to streaming_replica", func() {
+		r := newReconciler(tlsSecret("server", "db-rw"), caSecret("server-ca"),
+			tlsSecret("replication", "replicator"), caSecret("client-ca"))
+
+		failures, err := r.postgresCertificateFailures(ctx, documentdb)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(failures).To(ConsistOf(
+			`spec.tls.postgres.replicationTLSSecret: the certificate of Secret "replication" is issued to "replicator", not "streaming_replica"`,
+		))
+	})
+})
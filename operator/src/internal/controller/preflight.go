@@ -0,0 +1,227 @@
+// Copyright (c) Microsoft Corporation.
+// Licensed under the MIT License.
+
+package controller
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+
+	cnpgv1 "github.com/cloudnative-pg/cloudnative-pg/api/v1"
+	"github.com/go-logr/logr"
+	fleetv1alpha1 "go.goms.io/fleet-networking/api/v1alpha1"
+	corev1 "k8s.io/api/core/v1"
+	storagev1 "k8s.io/api/storage/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/util/version"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/log"
+
+	dbpreview "github.com/documentdb/documentdb-operator/api/preview"
+	util "github.com/documentdb/documentdb-operator/internal/utils"
+)
+
+// istioGatewayKind stands for the Istio CRDs the Istio networking strategy
+// relies on.
+var istioGatewayKind = schema.GroupKind{Group: "networking.istio.io", Kind: "Gateway"}
+
+// reconcilePreflight checks the prerequisites of documentdb and records the
+// result in the PreflightFailed condition. The checks run until they pass for
+// the current generation, so fixing a prerequisite or the spec lets the
+// reconciliation proceed. It returns false while a prerequisite is missing.
+func (r *DocumentDBReconciler) reconcilePreflight(ctx context.Context, documentdb *dbpreview.DocumentDB, replicationContext *util.ReplicationContext, cnpgVersion *version.Version) (bool, error) {
+	previous := meta.FindStatusCondition(documentdb.Status.Conditions, dbpreview.ConditionPreflightFailed)
+	if previous != nil && previous.Status == metav1.ConditionFalse && previous.ObservedGeneration == documentdb.Generation {
+		return true, nil
+	}
+
+	failures, err := r.preflightFailures(ctx, documentdb, replicationContext, cnpgVersion)
+	if err != nil {
+		return false, err
+	}
+
+	condition := metav1.Condition{
+		Type:               dbpreview.ConditionPreflightFailed,
+		Status:             metav1.ConditionFalse,
+		Reason:             dbpreview.ReasonPrerequisitesMet,
+		Message:            "All prerequisites are met",
+		ObservedGeneration: documentdb.Generation,
+	}
+	if len(failures) > 0 {
+		condition.Status = metav1.ConditionTrue
+		condition.Reason = dbpreview.ReasonPrerequisitesMissing
+		condition.Message = strings.Join(failures, "; ")
+	}
+
+	if meta.SetStatusCondition(&documentdb.Status.Conditions, condition) {
+		if condition.Status == metav1.ConditionTrue {
+			log.FromContext(ctx).Info("Preflight checks failed", "failures", failures)
+			if r.Recorder != nil {
+				r.Recorder.Event(documentdb, corev1.EventTypeWarning, "PreflightFailed", condition.Message)
+			}
+		}
+		if err := r.updateStatus(ctx, documentdb); err != nil {
+			return false, fmt.Errorf("failed to update preflight condition: %w", err)
+		}
+	}
+	return len(failures) == 0, nil
+}
+
+// preflightFailures returns a message for each missing prerequisite of
+// documentdb. A nil cnpgVersion skips the version checks.
+func (r *DocumentDBReconciler) preflightFailures(ctx context.Context, documentdb *dbpreview.DocumentDB, replicationContext *util.ReplicationContext, cnpgVersion *version.Version) ([]string, error) {
+	var failures []string
+	namespace := documentdb.Namespace
+
+	installed, err := r.crdInstalled(ctx, &cnpgv1.ClusterList{}, namespace)
+	if err != nil {
+		return nil, err
+	}
+	if !installed {
+		failures = append(failures, "the CloudNativePG operator is not installed: the clusters.postgresql.cnpg.io CRD is missing")
+	} else if cnpgVersion != nil {
+		if !cnpgVersion.AtLeast(version.MustParseGeneric(util.MinCNPGVersion)) {
+			failures = append(failures, fmt.Sprintf("the CloudNativePG operator version %s cannot mount the DocumentDB extension image: %s or later is required",
+				cnpgVersion, util.MinCNPGVersion))
+		} else {
+			failures = append(failures, util.UnsupportedCNPGFeatures(&documentdb.Spec, cnpgVersion)...)
+		}
+	}
+
+	if name := replicationContext.StorageClass; name != "" {
+		storageClass := &storagev1.StorageClass{}
+		if err := r.Get(ctx, client.ObjectKey{Name: name}, storageClass); err != nil {
+			if !errors.IsNotFound(err) {
+				return nil, fmt.Errorf("failed to get StorageClass %s: %w", name, err)
+			}
+			failures = append(failures, fmt.Sprintf("StorageClass %q does not exist", name))
+		} else if storageClass.AllowVolumeExpansion == nil || !*storageClass.AllowVolumeExpansion {
+			failures = append(failures, fmt.Sprintf("StorageClass %q does not allow volume expansion, so the storage could never be resized", name))
+		}
+	}
+
+	certificateFailures, err := r.postgresCertificateFailures(ctx, documentdb)
+	if err != nil {
+		return nil, err
+	}
+	failures = append(failures, certificateFailures...)
+
+	if replicationContext.IsAzureFleetNetworking() {
+		fleetCRDs := []struct {
+			kind string
+			list client.ObjectList
+		}{
+			{"ServiceExport", &fleetv1alpha1.ServiceExportList{}},
+			{"MultiClusterService", &fleetv1alpha1.MultiClusterServiceList{}},
+		}
+		for _, crd := range fleetCRDs {
+			installed, err := r.crdInstalled(ctx, crd.list, namespace)
+			if err != nil {
+				return nil, err
+			}
+			if !installed {
+				failures = append(failures, fmt.Sprintf("crossCloudNetworkingStrategy AzureFleet requires the fleet networking %s CRD", crd.kind))
+			}
+		}
+	}
+	if replicationContext.IsIstioNetworking() {
+		installed, err := r.kindInstalled(istioGatewayKind)
+		if err != nil {
+			return nil, err
+		}
+		if !installed {
+			failures = append(failures, "crossCloudNetworkingStrategy Istio requires Istio, but the networking.istio.io CRDs are missing")
+		}
+	}
+	return failures, nil
+}
+
+// cnpgVersionTTL is how long a detected CloudNativePG version is reused. It
+// spares each reconcile a list of the CNPG Deployments while still picking up
+// an upgrade of CNPG.
+const cnpgVersionTTL = 10 * time.Minute
+
+// cnpgVersionCache keeps the last detected CloudNativePG version.
+type cnpgVersionCache struct {
+	mu         sync.Mutex
+	version    *version.Version
+	detectedAt time.Time
+	// now defaults to time.Now.
+	now func() time.Time
+}
+
+func newCNPGVersionCache() *cnpgVersionCache {
+	return &cnpgVersionCache{now: time.Now}
+}
+
+// detectCNPGVersion returns the version of the CloudNativePG operator, or nil
+// when it cannot be detected, e.g. for a CNPG deployed without the standard
+// labels. An undetected version is not held against the cluster. The version
+// is detected again once cnpgVersionTTL has passed; without a cache, on every
+// call.
+func (r *DocumentDBReconciler) detectCNPGVersion(ctx context.Context) *version.Version {
+	if r.Clientset == nil {
+		return nil
+	}
+	if c := r.cnpgVersions; c != nil {
+		c.mu.Lock()
+		defer c.mu.Unlock()
+		if !c.detectedAt.IsZero() && c.now().Sub(c.detectedAt) < cnpgVersionTTL {
+			return c.version
+		}
+	}
+	cnpgVersion, err := util.DetectCNPGVersion(ctx, r.Clientset)
+	if err != nil {
+		log.FromContext(ctx).V(1).Info("Could not detect the CloudNativePG operator version", "error", err.Error())
+		cnpgVersion = nil
+	}
+	if c := r.cnpgVersions; c != nil {
+		c.version, c.detectedAt = cnpgVersion, c.now()
+	}
+	return cnpgVersion
+}
+
+// logCNPGVersion logs the version of the CloudNativePG operator, as an error
+// when it is older than util.MinCNPGVersion.
+func (r *DocumentDBReconciler) logCNPGVersion(logger logr.Logger) {
+	cnpgVersion := r.detectCNPGVersion(log.IntoContext(context.Background(), logger))
+	switch {
+	case cnpgVersion == nil:
+		logger.Info("CloudNativePG operator version not detected", "namespace", util.GetCNPGNamespace())
+	case !cnpgVersion.AtLeast(version.MustParseGeneric(util.MinCNPGVersion)):
+		logger.Error(nil, "CloudNativePG operator version is not supported; DocumentDB clusters will report PreflightFailed",
+			"version", cnpgVersion.String(), "minVersion", util.MinCNPGVersion)
+	default:
+		logger.Info("Detected CloudNativePG operator", "version", cnpgVersion.String())
+	}
+}
+
+// crdInstalled reports whether the CRD of the list type is installed, by
+// listing it in namespace, which the operator is allowed to do.
+func (r *DocumentDBReconciler) crdInstalled(ctx context.Context, list client.ObjectList, namespace string) (bool, error) {
+	if err := r.List(ctx, list, client.InNamespace(namespace), client.Limit(1)); err != nil {
+		if util.IsCRDMissing(err) {
+			return false, nil
+		}
+		return false, fmt.Errorf("failed to list %T: %w", list, err)
+	}
+	return true, nil
+}
+
+// kindInstalled reports whether the API server serves the kind. Unlike
+// crdInstalled it needs no permission on the kind.
+func (r *DocumentDBReconciler) kindInstalled(kind schema.GroupKind) (bool, error) {
+	if _, err := r.RESTMapper().RESTMapping(kind); err != nil {
+		if meta.IsNoMatchError(err) {
+			return false, nil
+		}
+		return false, fmt.Errorf("failed to look up %s: %w", kind, err)
+	}
+	return true, nil
+}
@@ -0,0 +1,228 @@
+// Copyright (c) Microsoft Corporation.
+// Licensed under the MIT License.
+
+package controller
+
+import (
+	"context"
+	"time"
+
+	cnpgv1 "github.com/cloudnative-pg/cloudnative-pg/api/v1"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	storagev1 "k8s.io/api/storage/v1"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/apimachinery/pkg/util/version"
+	kubefake "k8s.io/client-go/kubernetes/fake"
+	"k8s.io/client-go/tools/record"
+	"k8s.io/utils/ptr"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	dbpreview "github.com/documentdb/documentdb-operator/api/preview"
+	util "github.com/documentdb/documentdb-operator/internal/utils"
+)
+
+var _ = Describe("Preflight checks", func() {
+	const namespace = "default"
+
+	var (
+		ctx        context.Context
+		scheme     *runtime.Scheme
+		documentdb *dbpreview.DocumentDB
+		recorder   *record.FakeRecorder
+	)
+
+	BeforeEach(func() {
+		ctx = context.Background()
+		scheme = runtime.NewScheme()
+		Expect(dbpreview.AddToScheme(scheme)).To(Succeed())
+		Expect(corev1.AddToScheme(scheme)).To(Succeed())
+		Expect(storagev1.AddToScheme(scheme)).To(Succeed())
+		recorder = record.NewFakeRecorder(10)
+		documentdb = &dbpreview.DocumentDB{
+			ObjectMeta: metav1.ObjectMeta{Name: "db", Namespace: namespace, Generation: 1},
+			Spec: dbpreview.DocumentDBSpec{
+				Resource: dbpreview.Resource{Storage: dbpreview.StorageConfiguration{StorageClass: "fast"}},
+			},
+		}
+	})
+
+	expandable := func(name string, allow bool) *storagev1.StorageClass {
+		return &storagev1.StorageClass{
+			ObjectMeta:           metav1.ObjectMeta{Name: name},
+			Provisioner:          "disk.csi.example.com",
+			AllowVolumeExpansion: ptr.To(allow),
+		}
+	}
+
+	newReconciler := func(objs ...client.Object) *DocumentDBReconciler {
+		objs = append(objs, documentdb)
+		return &DocumentDBReconciler{
+			Client: fake.NewClientBuilder().
+				WithScheme(scheme).
+				WithObjects(objs...).
+				WithStatusSubresource(&dbpreview.DocumentDB{}).
+				Build(),
+			Scheme:   scheme,
+			Recorder: recorder,
+		}
+	}
+
+	replicationContext := func() *util.ReplicationContext {
+		return &util.ReplicationContext{
+			CrossCloudNetworkingStrategy: util.None,
+			StorageClass:                 documentdb.Spec.Resource.Storage.StorageClass,
+			CNPGClusterName:              documentdb.Name,
+		}
+	}
+
+	preflightCondition := func(r *DocumentDBReconciler) *metav1.Condition {
+		stored := &dbpreview.DocumentDB{}
+		Expect(r.Get(ctx, types.NamespacedName{Name: documentdb.Name, Namespace: namespace}, stored)).To(Succeed())
+		return meta.FindStatusCondition(stored.Status.Conditions, dbpreview.ConditionPreflightFailed)
+	}
+
+	It("passes and records the condition as False when the prerequisites are met", func() {
+		Expect(cnpgv1.AddToScheme(scheme)).To(Succeed())
+		r := newReconciler(expandable("fast", true))
+
+		passed, err := r.reconcilePreflight(ctx, documentdb, replicationContext(), nil)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(passed).To(BeTrue())
+
+		condition := preflightCondition(r)
+		Expect(condition).ToNot(BeNil())
+		Expect(condition.Status).To(Equal(metav1.ConditionFalse))
+		Expect(condition.Reason).To(Equal(dbpreview.ReasonPrerequisitesMet))
+		Expect(recorder.Events).To(BeEmpty())
+	})
+
+	It("fails when the storage class does not exist", func() {
+		Expect(cnpgv1.AddToScheme(scheme)).To(Succeed())
+		r := newReconciler()
+
+		passed, err := r.reconcilePreflight(ctx, documentdb, replicationContext(), nil)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(passed).To(BeFalse())
+
+		condition := preflightCondition(r)
+		Expect(condition.Status).To(Equal(metav1.ConditionTrue))
+		Expect(condition.Reason).To(Equal(dbpreview.ReasonPrerequisitesMissing))
+		Expect(condition.Message).To(ContainSubstring(`StorageClass "fast" does not exist`))
+		Expect(recorder.Events).To(Receive(ContainSubstring("PreflightFailed")))
+	})
+
+	It("fails when the storage class does not allow volume expansion", func() {
+		Expect(cnpgv1.AddToScheme(scheme)).To(Succeed())
+		r := newReconciler(expandable("fast", false))
+
+		passed, err := r.reconcilePreflight(ctx, documentdb, replicationContext(), nil)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(passed).To(BeFalse())
+		Expect(preflightCondition(r).Message).To(ContainSubstring("does not allow volume expansion"))
+	})
+
+	It("fails when the CloudNativePG CRDs are not installed", func() {
+		r := newReconciler(expandable("fast", true))
+
+		passed, err := r.reconcilePreflight(ctx, documentdb, replicationContext(), nil)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(passed).To(BeFalse())
+		Expect(preflightCondition(r).Message).To(ContainSubstring("the CloudNativePG operator is not installed"))
+	})
+
+	It("fails when the CloudNativePG operator is too old", func() {
+		Expect(cnpgv1.AddToScheme(scheme)).To(Succeed())
+		r := newReconciler(expandable("fast", true))
+		r.Clientset = kubefake.NewSimpleClientset(&appsv1.Deployment{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      "cnpg-controller-manager",
+				Namespace: util.DEFAULT_CNPG_NAMESPACE,
+				Labels:    map[string]string{"app.kubernetes.io/name": "cloudnative-pg"},
+			},
+			Spec: appsv1.DeploymentSpec{Template: corev1.PodTemplateSpec{Spec: corev1.PodSpec{
+				Containers: []corev1.Container{{Name: "manager", Image: "ghcr.io/cloudnative-pg/cloudnative-pg:1.26.0"}},
+			}}},
+		})
+
+		passed, err := r.reconcilePreflight(ctx, documentdb, replicationContext(), r.detectCNPGVersion(ctx))
+		Expect(err).ToNot(HaveOccurred())
+		Expect(passed).To(BeFalse())
+		Expect(preflightCondition(r).Message).To(ContainSubstring("version 1.26.0 cannot mount the DocumentDB extension image"))
+	})
+
+	It("reuses the detected CloudNativePG version until it expires", func() {
+		deployment := &appsv1.Deployment{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      "cnpg-controller-manager",
+				Namespace: util.DEFAULT_CNPG_NAMESPACE,
+				Labels:    map[string]string{"app.kubernetes.io/name": "cloudnative-pg"},
+			},
+			Spec: appsv1.DeploymentSpec{Template: corev1.PodTemplateSpec{Spec: corev1.PodSpec{
+				Containers: []corev1.Container{{Name: "manager", Image: "ghcr.io/cloudnative-pg/cloudnative-pg:1.27.0"}},
+			}}},
+		}
+		clientset := kubefake.NewSimpleClientset(deployment)
+		now := time.Now()
+		r := newReconciler()
+		r.Clientset = clientset
+		r.cnpgVersions = newCNPGVersionCache()
+		r.cnpgVersions.now = func() time.Time { return now }
+
+		Expect(r.detectCNPGVersion(ctx).String()).To(Equal("1.27.0"))
+
+		deployment.Spec.Template.Spec.Containers[0].Image = "ghcr.io/cloudnative-pg/cloudnative-pg:1.28.0"
+		_, err := clientset.AppsV1().Deployments(util.DEFAULT_CNPG_NAMESPACE).Update(ctx, deployment, metav1.UpdateOptions{})
+		Expect(err).ToNot(HaveOccurred())
+		Expect(r.detectCNPGVersion(ctx).String()).To(Equal("1.27.0"))
+		Expect(clientset.Actions()).To(HaveLen(2))
+
+		now = now.Add(cnpgVersionTTL)
+		Expect(r.detectCNPGVersion(ctx).String()).To(Equal("1.28.0"))
+	})
+
+	It("fails when a setting needs a newer CloudNativePG operator", func() {
+		Expect(cnpgv1.AddToScheme(scheme)).To(Succeed())
+		documentdb.Spec.SecurityContext = &dbpreview.SecurityContextSpec{RunAsUser: ptr.To[int64](1000)}
+		r := newReconciler(expandable("fast", true))
+
+		passed, err := r.reconcilePreflight(ctx, documentdb, replicationContext(), version.MustParseGeneric("1.27.1"))
+		Expect(err).ToNot(HaveOccurred())
+		Expect(passed).To(BeFalse())
+		Expect(preflightCondition(r).Message).To(ContainSubstring("require CloudNativePG 1.28.0 or later, but version 1.27.1 is installed"))
+	})
+
+	It("fails when AzureFleet networking is configured without the fleet CRDs", func() {
+		Expect(cnpgv1.AddToScheme(scheme)).To(Succeed())
+		r := newReconciler(expandable("fast", true))
+		replication := replicationContext()
+		replication.CrossCloudNetworkingStrategy = util.AzureFleet
+
+		passed, err := r.reconcilePreflight(ctx, documentdb, replication, nil)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(passed).To(BeFalse())
+		Expect(preflightCondition(r).Message).To(ContainSubstring("fleet networking ServiceExport CRD"))
+	})
+
+	It("does not rerun the checks once they passed for the generation", func() {
+		Expect(cnpgv1.AddToScheme(scheme)).To(Succeed())
+		meta.SetStatusCondition(&documentdb.Status.Conditions, metav1.Condition{
+			Type:               dbpreview.ConditionPreflightFailed,
+			Status:             metav1.ConditionFalse,
+			Reason:             dbpreview.ReasonPrerequisitesMet,
+			ObservedGeneration: documentdb.Generation,
+		})
+		// The storage class is gone, but the checks are not run again.
+		r := newReconciler()
+
+		passed, err := r.reconcilePreflight(ctx, documentdb, replicationContext(), nil)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(passed).To(BeTrue())
+	})
+})
@@ -5,15 +5,18 @@ package controller
 
 import (
 	"context"
+	"fmt"
 	"slices"
 	"strings"
 
 	cnpgv1 "github.com/cloudnative-pg/cloudnative-pg/api/v1"
 	corev1 "k8s.io/api/core/v1"
 	storagev1 "k8s.io/api/storage/v1"
+	"k8s.io/apimachinery/pkg/api/equality"
 	"k8s.io/apimachinery/pkg/api/errors"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/tools/record"
 	ctrl "sigs.k8s.io/controller-runtime"
 	"sigs.k8s.io/controller-runtime/pkg/builder"
 	"sigs.k8s.io/controller-runtime/pkg/client"
@@ -37,8 +40,9 @@ const (
 	// ownerRefKindDocumentDB is the Kind for DocumentDB owner references
 	ownerRefKindDocumentDB = "DocumentDB"
 
-	// reclaimPolicyRetain is the string value for Retain policy in DocumentDB spec
-	reclaimPolicyRetain = "Retain"
+	// cnpgInstanceRoleLabel is set by CNPG on the Pods and PVCs of an
+	// instance to its role, primary or replica
+	cnpgInstanceRoleLabel = "cnpg.io/instanceRole"
 
 	// reclaimPolicyDelete is the string value for Delete policy in DocumentDB spec
 	reclaimPolicyDelete = "Delete"
@@ -57,19 +61,33 @@ const (
 var securityMountOptions = []string{"nodev", "noexec", "nosuid"}
 
 // unsupportedMountOptionsProvisioners lists storage provisioners that do not support
-// mount options. These are typically local/dev environment provisioners used in
-// kind, minikube, or similar local Kubernetes clusters.
+// mount options. These are local and hostPath provisioners, used in kind, minikube
+// or similar local Kubernetes clusters and by the OpenShift local storage operators.
 // When a PV uses one of these provisioners, security mount options will be skipped
-// to avoid PV binding failures.
+// to avoid PV binding failures. Administrators extend the list with the
+// DOCUMENTDB_UNSUPPORTED_MOUNT_OPTIONS_PROVISIONERS operator setting.
 var unsupportedMountOptionsProvisioners = []string{
-	"rancher.io/local-path",    // kind default local-path-provisioner
-	"k8s.io/minikube-hostpath", // minikube default hostpath provisioner
+	"rancher.io/local-path",            // kind default local-path-provisioner
+	"k8s.io/minikube-hostpath",         // minikube default hostpath provisioner
+	"kubernetes.io/no-provisioner",     // OpenShift Local Storage Operator (static local PVs)
+	"kubevirt.io.hostpath-provisioner", // OpenShift hostpath provisioner (CSI)
+	"kubevirt.io/hostpath-provisioner", // OpenShift hostpath provisioner (legacy)
 }
 
 // PersistentVolumeReconciler reconciles PersistentVolume objects
 // to set their ReclaimPolicy and mount options based on the associated DocumentDB configuration
 type PersistentVolumeReconciler struct {
 	client.Client
+	// WatchNamespaces restricts reconciliation to PVs claimed from these
+	// namespaces. Empty means all namespaces (see --watch-namespaces).
+	WatchNamespaces []string
+	// Recorder emits events on the DocumentDB of a PV, e.g. when its mount
+	// options are skipped.
+	Recorder record.EventRecorder
+	// APIReader reads an object from the API server again when a write
+	// conflicts, since the cache may not have caught up with the conflicting
+	// write yet. Defaults to the API reader of the manager.
+	APIReader client.Reader
 }
 
 // +kubebuilder:rbac:groups="",resources=persistentvolumes,verbs=get;list;watch;update;patch
@@ -103,10 +121,16 @@ func (r *PersistentVolumeReconciler) Reconcile(ctx context.Context, req ctrl.Req
 	}
 
 	// Apply desired configuration to PV
+	firstSeen := pv.Labels[util.LabelCluster] != documentdb.Name
+	wasHeld := isRetentionHeld(pv)
 	needsUpdate := r.applyDesiredPVConfiguration(ctx, pv, documentdb)
 
 	if needsUpdate {
-		if err := r.Update(ctx, pv); err != nil {
+		err := util.UpdateWithRetry(ctx, r.Client, r.APIReader, pv, func() error {
+			r.applyDesiredPVConfiguration(ctx, pv, documentdb)
+			return nil
+		})
+		if err != nil {
 			logger.Error(err, "Failed to update PV")
 			return ctrl.Result{}, err
 		}
@@ -115,6 +139,21 @@ func (r *PersistentVolumeReconciler) Reconcile(ctx context.Context, req ctrl.Req
 			"pv", pv.Name,
 			"reclaimPolicy", pv.Spec.PersistentVolumeReclaimPolicy,
 			"mountOptions", pv.Spec.MountOptions)
+
+		// Report the skipped mount options once, when the PV is first labeled
+		if firstSeen {
+			r.reportSkippedMountOptions(ctx, pv, documentdb)
+		}
+		if held := isRetentionHeld(pv); held != wasHeld && r.Recorder != nil {
+			if held {
+				r.Recorder.Event(documentdb, corev1.EventTypeNormal, "RetentionHoldApplied", fmt.Sprintf(
+					"PersistentVolume %s of PVC %s is under retention hold: it is retained and never deleted by the operator",
+					pv.Name, pv.Spec.ClaimRef.Name))
+			} else {
+				r.Recorder.Event(documentdb, corev1.EventTypeNormal, "RetentionHoldReleased", fmt.Sprintf(
+					"PersistentVolume %s of PVC %s is no longer under retention hold", pv.Name, pv.Spec.ClaimRef.Name))
+			}
+		}
 	}
 
 	return ctrl.Result{}, nil
@@ -151,8 +190,34 @@ func (r *PersistentVolumeReconciler) applyDesiredPVConfiguration(ctx context.Con
 		needsUpdate = true
 	}
 
+	// Mirror the retention hold and the instance role of the PVC, so that
+	// they outlive the PVC. Without the PVC, e.g. after its deletion, the
+	// values recorded on the PV stand.
+	if pvc := r.boundPVC(ctx, pv); pvc != nil {
+		if held := isRetentionHeld(pvc); held != isRetentionHeld(pv) {
+			logger.Info("PV retention hold needs update", "pv", pv.Name, "held", held)
+			if held {
+				if pv.Annotations == nil {
+					pv.Annotations = make(map[string]string)
+				}
+				pv.Annotations[util.RETENTION_HOLD_ANNOTATION] = "true"
+			} else {
+				delete(pv.Annotations, util.RETENTION_HOLD_ANNOTATION)
+			}
+			needsUpdate = true
+		}
+		if role := pvc.Labels[cnpgInstanceRoleLabel]; role != "" && pv.Labels[util.LabelInstanceRole] != role {
+			logger.Info("PV instance role label needs update", "pv", pv.Name, "role", role)
+			pv.Labels[util.LabelInstanceRole] = role
+			needsUpdate = true
+		}
+	}
+
 	// Check if reclaim policy needs update
 	desiredPolicy := r.getDesiredReclaimPolicy(documentdb)
+	if isRetentionHeld(pv) {
+		desiredPolicy = corev1.PersistentVolumeReclaimRetain
+	}
 	if pv.Spec.PersistentVolumeReclaimPolicy != desiredPolicy {
 		logger.Info("PV reclaim policy needs update",
 			"pv", pv.Name,
@@ -163,64 +228,149 @@ func (r *PersistentVolumeReconciler) applyDesiredPVConfiguration(ctx context.Con
 		needsUpdate = true
 	}
 
-	// Check if the storage provisioner supports mount options
-	// Skip mount options for local/dev provisioners (kind, minikube, etc.)
-	if r.provisionerSupportsMountOptions(ctx, pv) {
-		// Check if mount options need update
-		if !containsAllMountOptions(pv.Spec.MountOptions, securityMountOptions) {
-			logger.Info("PV mount options need update",
-				"pv", pv.Name,
-				"currentMountOptions", pv.Spec.MountOptions,
-				"desiredMountOptions", securityMountOptions)
-			pv.Spec.MountOptions = mergeMountOptions(pv.Spec.MountOptions, securityMountOptions)
-			needsUpdate = true
-		}
-	} else {
-		logger.V(1).Info("Skipping mount options for PV - provisioner does not support them",
+	// Check if mount options need update
+	desired, stale := r.desiredMountOptions(ctx, pv, documentdb)
+	if !containsAllMountOptions(pv.Spec.MountOptions, desired) || slices.ContainsFunc(stale, func(opt string) bool {
+		return slices.Contains(pv.Spec.MountOptions, opt)
+	}) {
+		logger.Info("PV mount options need update",
 			"pv", pv.Name,
-			"storageClassName", pv.Spec.StorageClassName)
+			"currentMountOptions", pv.Spec.MountOptions,
+			"desiredMountOptions", desired,
+			"removedMountOptions", stale)
+		pv.Spec.MountOptions = removeMountOptions(mergeMountOptions(pv.Spec.MountOptions, desired), stale)
+		needsUpdate = true
 	}
 
 	return needsUpdate
 }
 
-// provisionerSupportsMountOptions checks if the PV's storage class provisioner supports mount options.
-// Returns false for known local/dev provisioners (kind, minikube, etc.) that don't support mount options.
-// Returns true for production provisioners (Azure Disk, AWS EBS, etc.) or if the provisioner cannot be determined.
-func (r *PersistentVolumeReconciler) provisionerSupportsMountOptions(ctx context.Context, pv *corev1.PersistentVolume) bool {
+// desiredMountOptions returns the mount options pv must have according to
+// spec.resource.storage.mountOptions of documentdb, and the default security
+// options it must no longer have: those the configuration leaves out and the
+// StorageClass of pv does not set either.
+func (r *PersistentVolumeReconciler) desiredMountOptions(ctx context.Context, pv *corev1.PersistentVolume, documentdb *dbpreview.DocumentDB) (desired, stale []string) {
 	logger := log.FromContext(ctx)
+	config := documentdb.Spec.Resource.Storage.MountOptions
+	if config == nil {
+		config = &dbpreview.MountOptionsConfiguration{}
+	}
 
-	// If no storage class is specified, assume mount options are supported (safer default for production)
-	if pv.Spec.StorageClassName == "" {
-		return true
+	override, overridden := mountOptionsOverride(config, pv.Spec.StorageClassName)
+	switch {
+	case config.Disabled:
+		desired = nil
+	case overridden:
+		desired = override
+	case !r.provisionerSupportsMountOptions(ctx, pv):
+		// Skip mount options for local/dev provisioners (kind, minikube, etc.)
+		logger.Info("Skipping mount options for PV - provisioner does not support them",
+			"pv", pv.Name,
+			"storageClassName", pv.Spec.StorageClassName)
+		desired = nil
+	case len(config.Options) > 0:
+		desired = config.Options
+	default:
+		desired = securityMountOptions
 	}
 
-	// Fetch the StorageClass to get the provisioner
+	storageClass, err := r.getStorageClass(ctx, pv.Spec.StorageClassName)
+	if err != nil {
+		// Without the StorageClass the options it sets are unknown: keep them all
+		logger.Error(err, "Failed to get StorageClass, keeping the current mount options",
+			"storageClassName", pv.Spec.StorageClassName)
+		return desired, nil
+	}
+	for _, opt := range securityMountOptions {
+		if slices.Contains(desired, opt) || (storageClass != nil && slices.Contains(storageClass.MountOptions, opt)) {
+			continue
+		}
+		stale = append(stale, opt)
+	}
+	return desired, stale
+}
+
+// getStorageClass returns the StorageClass named name, or nil when name is
+// empty or the StorageClass does not exist.
+func (r *PersistentVolumeReconciler) getStorageClass(ctx context.Context, name string) (*storagev1.StorageClass, error) {
+	if name == "" {
+		return nil, nil
+	}
 	storageClass := &storagev1.StorageClass{}
-	if err := r.Get(ctx, types.NamespacedName{Name: pv.Spec.StorageClassName}, storageClass); err != nil {
+	if err := r.Get(ctx, types.NamespacedName{Name: name}, storageClass); err != nil {
 		if errors.IsNotFound(err) {
-			logger.V(1).Info("StorageClass not found, assuming mount options are supported",
-				"storageClassName", pv.Spec.StorageClassName)
-			return true
+			return nil, nil
 		}
+		return nil, err
+	}
+	return storageClass, nil
+}
+
+// provisionerSupportsMountOptions checks if the PV's storage class provisioner supports mount options.
+// Returns false for known local/dev provisioners (kind, minikube, etc.) that don't support mount options.
+// Returns true for production provisioners (Azure Disk, AWS EBS, etc.) or if the provisioner cannot be determined.
+func (r *PersistentVolumeReconciler) provisionerSupportsMountOptions(ctx context.Context, pv *corev1.PersistentVolume) bool {
+	logger := log.FromContext(ctx)
+
+	// Fetch the StorageClass to get the provisioner. If no storage class is specified,
+	// assume mount options are supported (safer default for production)
+	storageClass, err := r.getStorageClass(ctx, pv.Spec.StorageClassName)
+	if err != nil {
 		logger.Error(err, "Failed to get StorageClass, assuming mount options are supported",
 			"storageClassName", pv.Spec.StorageClassName)
 		return true
 	}
+	if storageClass == nil {
+		logger.V(1).Info("No StorageClass found, assuming mount options are supported",
+			"storageClassName", pv.Spec.StorageClassName)
+		return true
+	}
 
-	// Check if the provisioner is in the unsupported list
-	for _, unsupportedProvisioner := range unsupportedMountOptionsProvisioners {
-		if storageClass.Provisioner == unsupportedProvisioner {
-			logger.V(1).Info("Provisioner does not support mount options",
-				"provisioner", storageClass.Provisioner,
-				"storageClassName", pv.Spec.StorageClassName)
-			return false
-		}
+	// Check if the provisioner is in the unsupported list, built-in or configured
+	if slices.Contains(unsupportedMountOptionsProvisioners, storageClass.Provisioner) ||
+		slices.Contains(util.GetUnsupportedMountOptionsProvisioners(), storageClass.Provisioner) {
+		logger.V(1).Info("Provisioner does not support mount options",
+			"provisioner", storageClass.Provisioner,
+			"storageClassName", pv.Spec.StorageClassName)
+		return false
 	}
 
 	return true
 }
 
+// reportSkippedMountOptions emits an event on documentdb when pv gets no
+// security mount options because the provisioner of its StorageClass does not
+// support them.
+func (r *PersistentVolumeReconciler) reportSkippedMountOptions(ctx context.Context, pv *corev1.PersistentVolume, documentdb *dbpreview.DocumentDB) {
+	if r.Recorder == nil {
+		return
+	}
+	config := documentdb.Spec.Resource.Storage.MountOptions
+	if config != nil && config.Disabled {
+		return
+	}
+	if _, ok := mountOptionsOverride(config, pv.Spec.StorageClassName); ok || r.provisionerSupportsMountOptions(ctx, pv) {
+		return
+	}
+	r.Recorder.Event(documentdb, corev1.EventTypeNormal, "MountOptionsSkipped", fmt.Sprintf(
+		"PersistentVolume %s gets no security mount options: the provisioner of StorageClass %s does not support them",
+		pv.Name, pv.Spec.StorageClassName))
+}
+
+// mountOptionsOverride returns the mount options config sets for the
+// PersistentVolumes of storageClass, if it overrides them.
+func mountOptionsOverride(config *dbpreview.MountOptionsConfiguration, storageClass string) ([]string, bool) {
+	if config == nil {
+		return nil, false
+	}
+	for _, override := range config.StorageClassOverrides {
+		if override.StorageClass == storageClass {
+			return override.Options, true
+		}
+	}
+	return nil, false
+}
+
 // containsAllMountOptions checks if all desired mount options are present in current options
 func containsAllMountOptions(current, desired []string) bool {
 	for _, opt := range desired {
@@ -231,6 +381,13 @@ func containsAllMountOptions(current, desired []string) bool {
 	return true
 }
 
+// removeMountOptions returns current without the options in remove.
+func removeMountOptions(current, remove []string) []string {
+	return slices.DeleteFunc(slices.Clone(current), func(opt string) bool {
+		return slices.Contains(remove, opt)
+	})
+}
+
 // mergeMountOptions merges desired mount options into current, avoiding duplicates.
 func mergeMountOptions(current, desired []string) []string {
 	optSet := make(map[string]struct{}, len(current)+len(desired))
@@ -258,6 +415,11 @@ func (r *PersistentVolumeReconciler) findDocumentDBForPV(ctx context.Context, pv
 	if pv.Spec.ClaimRef == nil {
 		return nil, nil
 	}
+	// PVCs outside the watched namespaces are not in the cache and cannot
+	// belong to a DocumentDB managed by this operator instance.
+	if !util.IsWatchedNamespace(r.WatchNamespaces, pv.Spec.ClaimRef.Namespace) {
+		return nil, nil
+	}
 
 	pvc := &corev1.PersistentVolumeClaim{}
 	pvcKey := types.NamespacedName{
@@ -285,6 +447,9 @@ func (r *PersistentVolumeReconciler) findDocumentDBForPV(ctx context.Context, pv
 		logger.V(1).Info("No DocumentDB owner found for CNPG Cluster", "cluster", cnpgCluster.Name)
 		return nil, nil
 	}
+	if err := util.ResolveClusterClass(ctx, r.Client, documentdb); err != nil {
+		return nil, err
+	}
 
 	logger.V(1).Info("Found DocumentDB for PV",
 		"pv", pv.Name,
@@ -345,6 +510,23 @@ func (r *PersistentVolumeReconciler) findDocumentDBOwner(ctx context.Context, cl
 	return nil
 }
 
+// boundPVC returns the PVC bound to pv, or nil when it cannot be read.
+func (r *PersistentVolumeReconciler) boundPVC(ctx context.Context, pv *corev1.PersistentVolume) *corev1.PersistentVolumeClaim {
+	if pv.Spec.ClaimRef == nil {
+		return nil
+	}
+	pvc := &corev1.PersistentVolumeClaim{}
+	if err := r.Get(ctx, types.NamespacedName{Name: pv.Spec.ClaimRef.Name, Namespace: pv.Spec.ClaimRef.Namespace}, pvc); err != nil {
+		return nil
+	}
+	return pvc
+}
+
+// isRetentionHeld reports whether obj, a PVC or a PV, is under retention hold.
+func isRetentionHeld(obj client.Object) bool {
+	return obj.GetAnnotations()[util.RETENTION_HOLD_ANNOTATION] == "true"
+}
+
 // isCNPGClusterOwnerRef checks if an owner reference refers to a CNPG Cluster
 func isCNPGClusterOwnerRef(ownerRef metav1.OwnerReference) bool {
 	return ownerRef.Kind == ownerRefKindCluster && strings.Contains(ownerRef.APIVersion, cnpgAPIVersionPrefix)
@@ -352,67 +534,92 @@ func isCNPGClusterOwnerRef(ownerRef metav1.OwnerReference) bool {
 
 // getDesiredReclaimPolicy returns the reclaim policy based on DocumentDB configuration
 func (r *PersistentVolumeReconciler) getDesiredReclaimPolicy(documentdb *dbpreview.DocumentDB) corev1.PersistentVolumeReclaimPolicy {
-	switch documentdb.Spec.Resource.Storage.PersistentVolumeReclaimPolicy {
-	case reclaimPolicyRetain:
-		return corev1.PersistentVolumeReclaimRetain
-	case reclaimPolicyDelete:
+	if documentdb.EffectivePersistentVolumeReclaimPolicy() == reclaimPolicyDelete {
 		return corev1.PersistentVolumeReclaimDelete
-	default:
-		// Default to Retain if not specified - safer for database workloads
-		return corev1.PersistentVolumeReclaimRetain
 	}
+	// Retain unless set otherwise - safer for database workloads
+	return corev1.PersistentVolumeReclaimRetain
 }
 
-// pvPredicate filters PV events to only process bound PVs
-func pvPredicate() predicate.Predicate {
+// pvPredicate filters PV events to the bound PVs that may belong to a
+// DocumentDB, so that clusters with many unrelated PVs do not reconcile them
+// all. See isDocumentDBPVCandidate.
+func pvPredicate(reader client.Reader, watchNamespaces []string) predicate.Predicate {
+	candidate := func(obj client.Object) bool {
+		pv, ok := obj.(*corev1.PersistentVolume)
+		if !ok {
+			return false
+		}
+		return isDocumentDBPVCandidate(context.Background(), reader, watchNamespaces, pv)
+	}
 	return predicate.Funcs{
 		CreateFunc: func(e event.CreateEvent) bool {
-			pv, ok := e.Object.(*corev1.PersistentVolume)
-			if !ok {
-				return false
-			}
-			// Only process PVs that are bound and have a claimRef
-			return pv.Status.Phase == corev1.VolumeBound && pv.Spec.ClaimRef != nil
+			return candidate(e.Object)
 		},
 		UpdateFunc: func(e event.UpdateEvent) bool {
-			newPV, ok := e.ObjectNew.(*corev1.PersistentVolume)
-			if !ok {
-				return false
-			}
 			// Process when PV becomes bound or when claimRef changes
-			return newPV.Status.Phase == corev1.VolumeBound && newPV.Spec.ClaimRef != nil
+			return candidate(e.ObjectNew)
 		},
 		DeleteFunc: func(e event.DeleteEvent) bool {
 			// No need to reconcile deleted PVs
 			return false
 		},
 		GenericFunc: func(e event.GenericEvent) bool {
-			pv, ok := e.Object.(*corev1.PersistentVolume)
-			if !ok {
-				return false
-			}
-			return pv.Status.Phase == corev1.VolumeBound && pv.Spec.ClaimRef != nil
+			return candidate(e.Object)
 		},
 	}
 }
 
+// isDocumentDBPVCandidate reports whether pv is bound and may belong to a
+// DocumentDB. A PV the controller has already labeled with
+// documentdb.io/cluster is one. An unlabeled PV is one only when it is
+// claimed from a watched namespace by a PVC created by CNPG; the ownership
+// walk of findDocumentDBForPV then tells whether a DocumentDB owns it. An
+// error reading the PVC from the cache lets the PV through.
+func isDocumentDBPVCandidate(ctx context.Context, reader client.Reader, watchNamespaces []string, pv *corev1.PersistentVolume) bool {
+	if pv.Status.Phase != corev1.VolumeBound || pv.Spec.ClaimRef == nil {
+		return false
+	}
+	if pv.Labels[util.LabelCluster] != "" {
+		return true
+	}
+	if !util.IsWatchedNamespace(watchNamespaces, pv.Spec.ClaimRef.Namespace) {
+		return false
+	}
+	pvc := &corev1.PersistentVolumeClaim{}
+	if err := reader.Get(ctx, types.NamespacedName{Name: pv.Spec.ClaimRef.Name, Namespace: pv.Spec.ClaimRef.Namespace}, pvc); err != nil {
+		return !errors.IsNotFound(err)
+	}
+	return pvc.Labels["cnpg.io/cluster"] != ""
+}
+
 // SetupWithManager sets up the controller with the Manager
 func (r *PersistentVolumeReconciler) SetupWithManager(mgr ctrl.Manager) error {
+	if r.APIReader == nil {
+		r.APIReader = mgr.GetAPIReader()
+	}
 	return ctrl.NewControllerManagedBy(mgr).
 		// Apply pvPredicate only to PersistentVolume events, not globally
-		For(&corev1.PersistentVolume{}, builder.WithPredicates(pvPredicate())).
+		For(&corev1.PersistentVolume{}, builder.WithPredicates(pvPredicate(mgr.GetClient(), r.WatchNamespaces))).
+		// Watch the retention hold and instance role of the PVCs and mirror them onto their PV
+		Watches(
+			&corev1.PersistentVolumeClaim{},
+			handler.EnqueueRequestsFromMapFunc(pvForPVC),
+			builder.WithPredicates(mirroredPVCMetadataChangedPredicate()),
+		).
 		// Watch DocumentDB changes and trigger reconciliation of associated PVs
 		Watches(
 			&dbpreview.DocumentDB{},
 			handler.EnqueueRequestsFromMapFunc(r.findPVsForDocumentDB),
-			builder.WithPredicates(documentDBReclaimPolicyPredicate()),
+			builder.WithPredicates(documentDBPVSettingsPredicate()),
 		).
 		Named("pv-controller").
-		Complete(r)
+		Complete(trackReconciles("pv-controller", r))
 }
 
-// documentDBReclaimPolicyPredicate only triggers when the reclaim policy field changes
-func documentDBReclaimPolicyPredicate() predicate.Predicate {
+// documentDBPVSettingsPredicate only triggers when the reclaim policy or the
+// mount options of the storage change
+func documentDBPVSettingsPredicate() predicate.Predicate {
 	return predicate.Funcs{
 		UpdateFunc: func(e event.UpdateEvent) bool {
 			oldDB, ok := e.ObjectOld.(*dbpreview.DocumentDB)
@@ -423,7 +630,23 @@ func documentDBReclaimPolicyPredicate() predicate.Predicate {
 			if !ok {
 				return false
 			}
-			return oldDB.Spec.Resource.Storage.PersistentVolumeReclaimPolicy != newDB.Spec.Resource.Storage.PersistentVolumeReclaimPolicy
+			oldStorage, newStorage := oldDB.Spec.Resource.Storage, newDB.Spec.Resource.Storage
+			return oldStorage.PersistentVolumeReclaimPolicy != newStorage.PersistentVolumeReclaimPolicy ||
+				!equality.Semantic.DeepEqual(oldStorage.MountOptions, newStorage.MountOptions)
+		},
+		CreateFunc:  func(e event.CreateEvent) bool { return false },
+		DeleteFunc:  func(e event.DeleteEvent) bool { return false },
+		GenericFunc: func(e event.GenericEvent) bool { return false },
+	}
+}
+
+// mirroredPVCMetadataChangedPredicate only triggers when the retention hold
+// annotation or the CNPG instance role label of a PVC changes
+func mirroredPVCMetadataChangedPredicate() predicate.Predicate {
+	return predicate.Funcs{
+		UpdateFunc: func(e event.UpdateEvent) bool {
+			return isRetentionHeld(e.ObjectOld) != isRetentionHeld(e.ObjectNew) ||
+				e.ObjectOld.GetLabels()[cnpgInstanceRoleLabel] != e.ObjectNew.GetLabels()[cnpgInstanceRoleLabel]
 		},
 		CreateFunc:  func(e event.CreateEvent) bool { return false },
 		DeleteFunc:  func(e event.DeleteEvent) bool { return false },
@@ -431,6 +654,15 @@ func documentDBReclaimPolicyPredicate() predicate.Predicate {
 	}
 }
 
+// pvForPVC returns a reconcile request for the PV bound to a PVC.
+func pvForPVC(_ context.Context, obj client.Object) []reconcile.Request {
+	pvc, ok := obj.(*corev1.PersistentVolumeClaim)
+	if !ok || pvc.Spec.VolumeName == "" {
+		return nil
+	}
+	return []reconcile.Request{{NamespacedName: types.NamespacedName{Name: pvc.Spec.VolumeName}}}
+}
+
 // findPVsForDocumentDB finds all PVs associated with a DocumentDB and returns reconcile requests for them.
 // Uses the documentdb.io/cluster and documentdb.io/namespace labels on PVs, which is set by the PV controller.
 // This works correctly in both single and multi-cluster scenarios where CNPG
@@ -15,12 +15,14 @@ import (
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/runtime"
 	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/tools/record"
 	ctrl "sigs.k8s.io/controller-runtime"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 	"sigs.k8s.io/controller-runtime/pkg/client/fake"
 	"sigs.k8s.io/controller-runtime/pkg/client/interceptor"
 	"sigs.k8s.io/controller-runtime/pkg/event"
 	"sigs.k8s.io/controller-runtime/pkg/predicate"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
 
 	dbpreview "github.com/documentdb/documentdb-operator/api/preview"
 	util "github.com/documentdb/documentdb-operator/internal/utils"
@@ -277,6 +279,229 @@ var _ = Describe("PersistentVolume Controller", func() {
 			needsUpdate := reconciler.applyDesiredPVConfiguration(ctx, pv, documentdb)
 			Expect(needsUpdate).To(BeFalse())
 		})
+
+		Context("with spec.resource.storage.mountOptions", func() {
+			labeledPV := func(storageClass string, mountOptions ...string) *corev1.PersistentVolume {
+				return &corev1.PersistentVolume{
+					ObjectMeta: metav1.ObjectMeta{
+						Name:   pvName,
+						Labels: map[string]string{util.LabelCluster: documentdbName, util.LabelNamespace: testNamespace},
+					},
+					Spec: corev1.PersistentVolumeSpec{
+						StorageClassName:              storageClass,
+						PersistentVolumeReclaimPolicy: corev1.PersistentVolumeReclaimRetain,
+						MountOptions:                  mountOptions,
+					},
+				}
+			}
+			documentDBWith := func(mountOptions *dbpreview.MountOptionsConfiguration) *dbpreview.DocumentDB {
+				return &dbpreview.DocumentDB{
+					ObjectMeta: metav1.ObjectMeta{Name: documentdbName, Namespace: testNamespace},
+					Spec: dbpreview.DocumentDBSpec{
+						Resource: dbpreview.Resource{
+							Storage: dbpreview.StorageConfiguration{MountOptions: mountOptions},
+						},
+					},
+				}
+			}
+
+			It("removes the security options when disabled, except those set by the StorageClass", func() {
+				reconciler.Client = fake.NewClientBuilder().WithScheme(scheme).WithObjects(&storagev1.StorageClass{
+					ObjectMeta:   metav1.ObjectMeta{Name: "csi"},
+					Provisioner:  "csi.example.com",
+					MountOptions: []string{"nodev"},
+				}).Build()
+				pv := labeledPV("csi", "nodev", "noexec", "nosuid", "rw")
+
+				Expect(reconciler.applyDesiredPVConfiguration(ctx, pv, documentDBWith(&dbpreview.MountOptionsConfiguration{Disabled: true}))).To(BeTrue())
+				Expect(pv.Spec.MountOptions).To(ConsistOf("nodev", "rw"))
+			})
+
+			It("replaces the security options with the configured ones", func() {
+				pv := labeledPV("", "nodev", "noexec", "nosuid")
+
+				Expect(reconciler.applyDesiredPVConfiguration(ctx, pv, documentDBWith(&dbpreview.MountOptionsConfiguration{
+					Options: []string{"nodev", "nosuid"},
+				}))).To(BeTrue())
+				Expect(pv.Spec.MountOptions).To(ConsistOf("nodev", "nosuid"))
+			})
+
+			It("applies a StorageClass override even on a provisioner that is otherwise skipped", func() {
+				reconciler.Client = fake.NewClientBuilder().WithScheme(scheme).WithObjects(&storagev1.StorageClass{
+					ObjectMeta:  metav1.ObjectMeta{Name: "standard"},
+					Provisioner: "rancher.io/local-path",
+				}).Build()
+				pv := labeledPV("standard")
+				documentdb := documentDBWith(&dbpreview.MountOptionsConfiguration{
+					StorageClassOverrides: []dbpreview.StorageClassMountOptions{{StorageClass: "standard", Options: []string{"noatime"}}},
+				})
+
+				Expect(reconciler.applyDesiredPVConfiguration(ctx, pv, documentdb)).To(BeTrue())
+				Expect(pv.Spec.MountOptions).To(ConsistOf("noatime"))
+				Expect(reconciler.applyDesiredPVConfiguration(ctx, pv, documentdb)).To(BeFalse())
+			})
+
+			It("sets no options for a StorageClass override without options", func() {
+				pv := labeledPV("csi", "nodev", "noexec", "nosuid")
+				documentdb := documentDBWith(&dbpreview.MountOptionsConfiguration{
+					StorageClassOverrides: []dbpreview.StorageClassMountOptions{{StorageClass: "csi"}},
+				})
+
+				Expect(reconciler.applyDesiredPVConfiguration(ctx, pv, documentdb)).To(BeTrue())
+				Expect(pv.Spec.MountOptions).To(BeEmpty())
+			})
+		})
+	})
+
+	Describe("retention hold", func() {
+		heldPVC := func(held bool) *corev1.PersistentVolumeClaim {
+			pvc := &corev1.PersistentVolumeClaim{
+				ObjectMeta: metav1.ObjectMeta{Name: pvcName, Namespace: testNamespace},
+				Spec:       corev1.PersistentVolumeClaimSpec{VolumeName: pvName},
+			}
+			if held {
+				pvc.Annotations = map[string]string{util.RETENTION_HOLD_ANNOTATION: "true"}
+			}
+			return pvc
+		}
+		boundPV := func(annotations map[string]string) *corev1.PersistentVolume {
+			return &corev1.PersistentVolume{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:        pvName,
+					Annotations: annotations,
+					Labels:      map[string]string{util.LabelCluster: documentdbName, util.LabelNamespace: testNamespace},
+				},
+				Spec: corev1.PersistentVolumeSpec{
+					ClaimRef:                      &corev1.ObjectReference{Name: pvcName, Namespace: testNamespace},
+					PersistentVolumeReclaimPolicy: corev1.PersistentVolumeReclaimDelete,
+					MountOptions:                  []string{"nodev", "noexec", "nosuid"},
+				},
+			}
+		}
+		deleteDocumentDB := &dbpreview.DocumentDB{
+			ObjectMeta: metav1.ObjectMeta{Name: documentdbName, Namespace: testNamespace},
+			Spec: dbpreview.DocumentDBSpec{
+				Resource: dbpreview.Resource{
+					Storage: dbpreview.StorageConfiguration{PersistentVolumeReclaimPolicy: "Delete"},
+				},
+			},
+		}
+
+		It("copies the hold of the PVC onto the PV and retains it despite the Delete policy", func() {
+			reconciler := &PersistentVolumeReconciler{
+				Client: fake.NewClientBuilder().WithScheme(scheme).WithObjects(heldPVC(true)).Build(),
+			}
+			pv := boundPV(nil)
+
+			Expect(reconciler.applyDesiredPVConfiguration(ctx, pv, deleteDocumentDB)).To(BeTrue())
+			Expect(pv.Annotations).To(HaveKeyWithValue(util.RETENTION_HOLD_ANNOTATION, "true"))
+			Expect(pv.Spec.PersistentVolumeReclaimPolicy).To(Equal(corev1.PersistentVolumeReclaimRetain))
+		})
+
+		It("keeps the hold of the PV once its PVC is gone", func() {
+			reconciler := &PersistentVolumeReconciler{Client: fake.NewClientBuilder().WithScheme(scheme).Build()}
+			pv := boundPV(map[string]string{util.RETENTION_HOLD_ANNOTATION: "true"})
+			pv.Spec.PersistentVolumeReclaimPolicy = corev1.PersistentVolumeReclaimRetain
+
+			Expect(reconciler.applyDesiredPVConfiguration(ctx, pv, deleteDocumentDB)).To(BeFalse())
+			Expect(pv.Spec.PersistentVolumeReclaimPolicy).To(Equal(corev1.PersistentVolumeReclaimRetain))
+		})
+
+		It("releases the PV when the hold is removed from the PVC", func() {
+			reconciler := &PersistentVolumeReconciler{
+				Client: fake.NewClientBuilder().WithScheme(scheme).WithObjects(heldPVC(false)).Build(),
+			}
+			pv := boundPV(map[string]string{util.RETENTION_HOLD_ANNOTATION: "true"})
+			pv.Spec.PersistentVolumeReclaimPolicy = corev1.PersistentVolumeReclaimRetain
+
+			Expect(reconciler.applyDesiredPVConfiguration(ctx, pv, deleteDocumentDB)).To(BeTrue())
+			Expect(pv.Annotations).ToNot(HaveKey(util.RETENTION_HOLD_ANNOTATION))
+			Expect(pv.Spec.PersistentVolumeReclaimPolicy).To(Equal(corev1.PersistentVolumeReclaimDelete))
+		})
+
+		It("reports the hold on the DocumentDB when reconciling the PV", func() {
+			recorder := record.NewFakeRecorder(10)
+			cluster := &cnpgv1.Cluster{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:            clusterName,
+					Namespace:       testNamespace,
+					OwnerReferences: []metav1.OwnerReference{{Kind: ownerRefKindDocumentDB, Name: documentdbName}},
+				},
+			}
+			pvc := heldPVC(true)
+			pvc.OwnerReferences = []metav1.OwnerReference{{APIVersion: "postgresql.cnpg.io/v1", Kind: ownerRefKindCluster, Name: clusterName}}
+			reconciler := &PersistentVolumeReconciler{
+				Client:   fake.NewClientBuilder().WithScheme(scheme).WithObjects(boundPV(nil), pvc, cluster, deleteDocumentDB.DeepCopy()).Build(),
+				Recorder: recorder,
+			}
+
+			_, err := reconciler.Reconcile(ctx, ctrl.Request{NamespacedName: types.NamespacedName{Name: pvName}})
+			Expect(err).ToNot(HaveOccurred())
+			Expect(recorder.Events).To(Receive(ContainSubstring("Normal RetentionHoldApplied PersistentVolume test-pv of PVC test-pvc is under retention hold")))
+		})
+
+		It("records the instance role of the PVC on the PV", func() {
+			pvc := heldPVC(false)
+			pvc.Labels = map[string]string{"cnpg.io/instanceRole": "primary"}
+			reconciler := &PersistentVolumeReconciler{
+				Client: fake.NewClientBuilder().WithScheme(scheme).WithObjects(pvc).Build(),
+			}
+			pv := boundPV(nil)
+
+			reconciler.applyDesiredPVConfiguration(ctx, pv, deleteDocumentDB)
+			Expect(pv.Labels).To(HaveKeyWithValue(util.LabelInstanceRole, "primary"))
+		})
+
+		It("maps a PVC whose hold or instance role changes to its PV", func() {
+			pred := mirroredPVCMetadataChangedPredicate()
+			Expect(pred.Update(event.UpdateEvent{ObjectOld: heldPVC(false), ObjectNew: heldPVC(true)})).To(BeTrue())
+			Expect(pred.Update(event.UpdateEvent{ObjectOld: heldPVC(true), ObjectNew: heldPVC(true)})).To(BeFalse())
+			promoted := heldPVC(true)
+			promoted.Labels = map[string]string{"cnpg.io/instanceRole": "primary"}
+			Expect(pred.Update(event.UpdateEvent{ObjectOld: heldPVC(true), ObjectNew: promoted})).To(BeTrue())
+			Expect(pvForPVC(ctx, heldPVC(true))).To(Equal([]reconcile.Request{{NamespacedName: types.NamespacedName{Name: pvName}}}))
+		})
+	})
+
+	Describe("reportSkippedMountOptions", func() {
+		var (
+			recorder   *record.FakeRecorder
+			reconciler *PersistentVolumeReconciler
+			pv         *corev1.PersistentVolume
+			documentdb *dbpreview.DocumentDB
+		)
+
+		BeforeEach(func() {
+			recorder = record.NewFakeRecorder(10)
+			reconciler = &PersistentVolumeReconciler{
+				Client: fake.NewClientBuilder().WithScheme(scheme).WithObjects(&storagev1.StorageClass{
+					ObjectMeta:  metav1.ObjectMeta{Name: "standard"},
+					Provisioner: "rancher.io/local-path",
+				}).Build(),
+				Recorder: recorder,
+			}
+			pv = &corev1.PersistentVolume{
+				ObjectMeta: metav1.ObjectMeta{Name: pvName},
+				Spec:       corev1.PersistentVolumeSpec{StorageClassName: "standard"},
+			}
+			documentdb = &dbpreview.DocumentDB{ObjectMeta: metav1.ObjectMeta{Name: documentdbName, Namespace: testNamespace}}
+		})
+
+		It("emits an event when the provisioner does not support mount options", func() {
+			reconciler.reportSkippedMountOptions(ctx, pv, documentdb)
+			Expect(recorder.Events).To(Receive(And(
+				ContainSubstring("Normal MountOptionsSkipped"),
+				ContainSubstring("PersistentVolume test-pv gets no security mount options"),
+			)))
+		})
+
+		It("stays silent when the StorageClass has an override", func() {
+			documentdb.Spec.Resource.Storage.MountOptions = &dbpreview.MountOptionsConfiguration{
+				StorageClassOverrides: []dbpreview.StorageClassMountOptions{{StorageClass: "standard"}},
+			}
+			reconciler.reportSkippedMountOptions(ctx, pv, documentdb)
+			Expect(recorder.Events).To(BeEmpty())
+		})
 	})
 
 	Describe("provisionerSupportsMountOptions", func() {
@@ -352,6 +577,52 @@ var _ = Describe("PersistentVolume Controller", func() {
 			Expect(reconciler.provisionerSupportsMountOptions(ctx, pv)).To(BeFalse())
 		})
 
+		DescribeTable("returns false for OpenShift local storage provisioners",
+			func(provisioner string) {
+				storageClass := &storagev1.StorageClass{
+					ObjectMeta:  metav1.ObjectMeta{Name: "local"},
+					Provisioner: provisioner,
+				}
+
+				pv := &corev1.PersistentVolume{
+					ObjectMeta: metav1.ObjectMeta{Name: pvName},
+					Spec: corev1.PersistentVolumeSpec{
+						StorageClassName: "local",
+					},
+				}
+
+				fakeClient := fake.NewClientBuilder().
+					WithScheme(scheme).
+					WithObjects(storageClass).
+					Build()
+				reconciler := &PersistentVolumeReconciler{Client: fakeClient}
+
+				Expect(reconciler.provisionerSupportsMountOptions(ctx, pv)).To(BeFalse())
+			},
+			Entry("local storage operator", "kubernetes.io/no-provisioner"),
+			Entry("hostpath provisioner", "kubevirt.io.hostpath-provisioner"),
+		)
+
+		It("returns false for a provisioner added by the operator settings", func() {
+			util.SetOperatorSettings(map[string]string{
+				util.UNSUPPORTED_MOUNT_OPTIONS_PROVISIONERS_ENV: "example.com/nfs, csi.example.com",
+			})
+			DeferCleanup(func() { util.SetOperatorSettings(nil) })
+			storageClass := &storagev1.StorageClass{
+				ObjectMeta:  metav1.ObjectMeta{Name: "example"},
+				Provisioner: "csi.example.com",
+			}
+			pv := &corev1.PersistentVolume{
+				ObjectMeta: metav1.ObjectMeta{Name: pvName},
+				Spec:       corev1.PersistentVolumeSpec{StorageClassName: "example"},
+			}
+			reconciler := &PersistentVolumeReconciler{
+				Client: fake.NewClientBuilder().WithScheme(scheme).WithObjects(storageClass).Build(),
+			}
+
+			Expect(reconciler.provisionerSupportsMountOptions(ctx, pv)).To(BeFalse())
+		})
+
 		It("returns true for Azure Disk provisioner", func() {
 			storageClass := &storagev1.StorageClass{
 				ObjectMeta:  metav1.ObjectMeta{Name: "managed-premium"},
@@ -862,9 +1133,30 @@ var _ = Describe("PersistentVolume Controller", func() {
 		var pred predicate.Predicate
 
 		BeforeEach(func() {
-			pred = pvPredicate()
+			cnpgPVC := &corev1.PersistentVolumeClaim{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      pvcName,
+					Namespace: testNamespace,
+					Labels:    map[string]string{"cnpg.io/cluster": clusterName},
+				},
+			}
+			otherPVC := &corev1.PersistentVolumeClaim{
+				ObjectMeta: metav1.ObjectMeta{Name: "other-pvc", Namespace: testNamespace},
+			}
+			reader := fake.NewClientBuilder().WithScheme(scheme).WithObjects(cnpgPVC, otherPVC).Build()
+			pred = pvPredicate(reader, nil)
 		})
 
+		boundPV := func(claimName, claimNamespace string, labels map[string]string) *corev1.PersistentVolume {
+			return &corev1.PersistentVolume{
+				ObjectMeta: metav1.ObjectMeta{Name: pvName, Labels: labels},
+				Spec: corev1.PersistentVolumeSpec{
+					ClaimRef: &corev1.ObjectReference{Name: claimName, Namespace: claimNamespace},
+				},
+				Status: corev1.PersistentVolumeStatus{Phase: corev1.VolumeBound},
+			}
+		}
+
 		Describe("CreateFunc", func() {
 			It("returns true for bound PV with claimRef", func() {
 				pv := &corev1.PersistentVolume{
@@ -912,6 +1204,27 @@ var _ = Describe("PersistentVolume Controller", func() {
 				e := event.CreateEvent{Object: pvc}
 				Expect(pred.Create(e)).To(BeFalse())
 			})
+
+			It("returns true for a PV labeled for a DocumentDB without looking up its PVC", func() {
+				pv := boundPV("missing-pvc", testNamespace, map[string]string{util.LabelCluster: documentdbName})
+				Expect(pred.Create(event.CreateEvent{Object: pv})).To(BeTrue())
+			})
+
+			It("returns false for an unlabeled PV claimed by a PVC not created by CNPG", func() {
+				pv := boundPV("other-pvc", testNamespace, nil)
+				Expect(pred.Create(event.CreateEvent{Object: pv})).To(BeFalse())
+			})
+
+			It("returns false for an unlabeled PV whose PVC does not exist", func() {
+				pv := boundPV("missing-pvc", testNamespace, nil)
+				Expect(pred.Create(event.CreateEvent{Object: pv})).To(BeFalse())
+			})
+
+			It("returns false for an unlabeled PV claimed from an unwatched namespace", func() {
+				reader := fake.NewClientBuilder().WithScheme(scheme).Build()
+				pv := boundPV(pvcName, testNamespace, nil)
+				Expect(pvPredicate(reader, []string{"other"}).Create(event.CreateEvent{Object: pv})).To(BeFalse())
+			})
 		})
 
 		Describe("UpdateFunc", func() {
@@ -955,11 +1268,11 @@ var _ = Describe("PersistentVolume Controller", func() {
 		})
 	})
 
-	Describe("documentDBReclaimPolicyPredicate", func() {
+	Describe("documentDBPVSettingsPredicate", func() {
 		var pred predicate.Predicate
 
 		BeforeEach(func() {
-			pred = documentDBReclaimPolicyPredicate()
+			pred = documentDBPVSettingsPredicate()
 		})
 
 		Describe("UpdateFunc", func() {
@@ -988,6 +1301,13 @@ var _ = Describe("PersistentVolume Controller", func() {
 				Expect(pred.Update(e)).To(BeTrue())
 			})
 
+			It("returns true when the mount options change", func() {
+				oldDB := &dbpreview.DocumentDB{ObjectMeta: metav1.ObjectMeta{Name: documentdbName, Namespace: testNamespace}}
+				newDB := oldDB.DeepCopy()
+				newDB.Spec.Resource.Storage.MountOptions = &dbpreview.MountOptionsConfiguration{Disabled: true}
+				Expect(pred.Update(event.UpdateEvent{ObjectOld: oldDB, ObjectNew: newDB})).To(BeTrue())
+			})
+
 			It("returns false when reclaim policy unchanged", func() {
 				oldDB := &dbpreview.DocumentDB{
 					ObjectMeta: metav1.ObjectMeta{Name: documentdbName, Namespace: testNamespace},
@@ -1060,6 +1380,33 @@ var _ = Describe("PersistentVolume Controller", func() {
 			Expect(result).To(BeNil())
 		})
 
+		It("skips PVs claimed from namespaces outside the watch list", func() {
+			pv := &corev1.PersistentVolume{
+				ObjectMeta: metav1.ObjectMeta{Name: pvName},
+				Spec: corev1.PersistentVolumeSpec{
+					ClaimRef: &corev1.ObjectReference{
+						Name:      pvcName,
+						Namespace: testNamespace,
+					},
+				},
+			}
+
+			fakeClient := fake.NewClientBuilder().
+				WithScheme(scheme).
+				WithInterceptorFuncs(interceptor.Funcs{
+					Get: func(ctx context.Context, client client.WithWatch, key client.ObjectKey, obj client.Object, opts ...client.GetOption) error {
+						return fmt.Errorf("unknown namespace for the cache")
+					},
+				}).
+				Build()
+
+			reconciler := &PersistentVolumeReconciler{Client: fakeClient, WatchNamespaces: []string{"team-a"}}
+
+			result, err := reconciler.findDocumentDBForPV(ctx, pv)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(result).To(BeNil())
+		})
+
 		It("returns nil when PVC is not found", func() {
 			pv := &corev1.PersistentVolume{
 				ObjectMeta: metav1.ObjectMeta{Name: pvName},
@@ -0,0 +1,170 @@
+// Copyright (c) Microsoft Corporation.
+// Licensed under the MIT License.
+
+package controller
+
+import (
+	"context"
+	"fmt"
+
+	cnpgv1 "github.com/cloudnative-pg/cloudnative-pg/api/v1"
+	"github.com/cloudnative-pg/cloudnative-pg/pkg/versions"
+	batchv1 "k8s.io/api/batch/v1"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/utils/ptr"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
+	"sigs.k8s.io/controller-runtime/pkg/log"
+
+	dbpreview "github.com/documentdb/documentdb-operator/api/preview"
+	util "github.com/documentdb/documentdb-operator/internal/utils"
+)
+
+const (
+	// pvRecoveryCheckMountPath is where the check Job mounts the recovered
+	// volume, which is also where CNPG mounts the data volume of an instance.
+	pvRecoveryCheckMountPath = "/var/lib/postgresql/data"
+
+	// pvRecoveryCheckScript fails unless the volume holds a PostgreSQL data
+	// directory in the pgdata directory, where CNPG keeps PGDATA.
+	pvRecoveryCheckScript = `if [ -f "$PGDATA/PG_VERSION" ] && [ -f "$PGDATA/global/pg_control" ]; then
+  echo "PostgreSQL $(cat "$PGDATA/PG_VERSION") data directory" | tee /dev/termination-log
+else
+  echo "no PostgreSQL data directory in pgdata/ of the volume" | tee /dev/termination-log
+  exit 1
+fi`
+)
+
+// pvRecoveryCheckJobName returns the name of the Job checking the PV a
+// DocumentDB recovers from.
+func pvRecoveryCheckJobName(documentdbName string) string {
+	return fmt.Sprintf("%s-pv-recovery-check", documentdbName)
+}
+
+// checkPVRecoveryVolume checks, before the CNPG Cluster recovers from it,
+// that the PV bound to tempPVC holds a PostgreSQL data directory where CNPG
+// expects it: a Job mounts the volume read-only and looks for it. A PV that
+// passes the check is marked on tempPVC so that it is only checked once; one
+// that fails it is released as for any failed recovery. It returns an empty
+// result once CNPG can recover from the PV.
+func (r *DocumentDBReconciler) checkPVRecoveryVolume(ctx context.Context, documentdb *dbpreview.DocumentDB, tempPVC *corev1.PersistentVolumeClaim) (ctrl.Result, error) {
+	if tempPVC.Annotations[util.PVRecoveryCheckedAnnotation] == "true" {
+		return ctrl.Result{}, nil
+	}
+	logger := log.FromContext(ctx)
+
+	job := &batchv1.Job{}
+	err := r.Get(ctx, types.NamespacedName{Name: pvRecoveryCheckJobName(documentdb.Name), Namespace: documentdb.Namespace}, job)
+	if errors.IsNotFound(err) {
+		job = pvRecoveryCheckJob(documentdb, tempPVC)
+		if err := controllerutil.SetControllerReference(documentdb, job, r.Scheme); err != nil {
+			return ctrl.Result{}, fmt.Errorf("failed to set owner reference: %w", err)
+		}
+		if err := r.Create(ctx, job); err != nil && !errors.IsAlreadyExists(err) {
+			return ctrl.Result{}, fmt.Errorf("failed to create PV recovery check Job: %w", err)
+		}
+		logger.Info("Started PV recovery check Job", "job", job.Name, "pv", tempPVC.Spec.VolumeName)
+		return ctrl.Result{RequeueAfter: RequeueAfterShort}, nil
+	}
+	if err != nil {
+		return ctrl.Result{}, fmt.Errorf("failed to get PV recovery check Job: %w", err)
+	}
+
+	failed := jobCondition(job, batchv1.JobFailed)
+	if failed == nil && jobCondition(job, batchv1.JobComplete) == nil {
+		logger.Info("Waiting for the PV recovery check Job", "job", job.Name)
+		return ctrl.Result{RequeueAfter: RequeueAfterShort}, nil
+	}
+	if err := r.Delete(ctx, job, client.PropagationPolicy(metav1.DeletePropagationBackground)); err != nil && !errors.IsNotFound(err) {
+		return ctrl.Result{}, fmt.Errorf("failed to delete PV recovery check Job: %w", err)
+	}
+	if failed != nil {
+		if err := r.abandonPVRecovery(ctx, documentdb, tempPVC, fmt.Sprintf(
+			"the PV does not hold a PostgreSQL data directory (Job %s: %s)", job.Name, failed.Message)); err != nil {
+			return ctrl.Result{}, err
+		}
+		return ctrl.Result{RequeueAfter: RequeueAfterLong}, nil
+	}
+
+	err = util.PatchWithRetry(ctx, r.Client, r.APIReader, tempPVC, func() error {
+		if tempPVC.Annotations == nil {
+			tempPVC.Annotations = map[string]string{}
+		}
+		tempPVC.Annotations[util.PVRecoveryCheckedAnnotation] = "true"
+		return nil
+	})
+	if err != nil {
+		return ctrl.Result{}, fmt.Errorf("failed to mark temp PVC %s as checked: %w", tempPVC.Name, err)
+	}
+	if r.Recorder != nil {
+		r.Recorder.Event(documentdb, corev1.EventTypeNormal, "PVRecoveryChecked", fmt.Sprintf(
+			"PV %s holds a PostgreSQL data directory; recovering from it", tempPVC.Spec.VolumeName))
+	}
+	return ctrl.Result{}, nil
+}
+
+// pvRecoveryCheckJob returns the Job that checks the volume bound to
+// tempPVC, with the PostgreSQL image and identity of the cluster so that it
+// can read the data directory.
+func pvRecoveryCheckJob(documentdb *dbpreview.DocumentDB, tempPVC *corev1.PersistentVolumeClaim) *batchv1.Job {
+	image := versions.DefaultImageName
+	if documentdb.Spec.Image != nil && documentdb.Spec.Image.Postgres != "" {
+		image = documentdb.Spec.Image.Postgres
+	}
+	uid, gid := int64(cnpgv1.DefaultPostgresUID), int64(cnpgv1.DefaultPostgresGID)
+	if pg := documentdb.Spec.Postgres; pg != nil && pg.UID != nil && pg.GID != nil {
+		uid, gid = *pg.UID, *pg.GID
+	}
+
+	labels := util.ChildLabels(documentdb, map[string]string{util.LABEL_DOCUMENTDB_NAME: documentdb.Name})
+	return &batchv1.Job{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:        pvRecoveryCheckJobName(documentdb.Name),
+			Namespace:   documentdb.Namespace,
+			Labels:      labels,
+			Annotations: util.ChildAnnotations(documentdb, nil),
+		},
+		Spec: batchv1.JobSpec{
+			BackoffLimit: ptr.To(int32(0)),
+			Template: corev1.PodTemplateSpec{
+				ObjectMeta: metav1.ObjectMeta{Labels: labels},
+				Spec: corev1.PodSpec{
+					RestartPolicy: corev1.RestartPolicyNever,
+					SecurityContext: &corev1.PodSecurityContext{
+						RunAsUser:    ptr.To(uid),
+						RunAsGroup:   ptr.To(gid),
+						RunAsNonRoot: ptr.To(true),
+					},
+					Containers: []corev1.Container{{
+						Name:            "check",
+						Image:           image,
+						ImagePullPolicy: documentdb.Spec.ImagePullPolicy,
+						Command:         []string{"/bin/sh", "-c", pvRecoveryCheckScript},
+						Env:             []corev1.EnvVar{{Name: "PGDATA", Value: pvRecoveryCheckMountPath + "/pgdata"}},
+						VolumeMounts: []corev1.VolumeMount{{
+							Name:      "pgdata",
+							MountPath: pvRecoveryCheckMountPath,
+							ReadOnly:  true,
+						}},
+						TerminationMessagePolicy: corev1.TerminationMessageFallbackToLogsOnError,
+						SecurityContext: &corev1.SecurityContext{
+							AllowPrivilegeEscalation: ptr.To(false),
+							Capabilities:             &corev1.Capabilities{Drop: []corev1.Capability{"ALL"}},
+						},
+					}},
+					Volumes: []corev1.Volume{{
+						Name: "pgdata",
+						VolumeSource: corev1.VolumeSource{PersistentVolumeClaim: &corev1.PersistentVolumeClaimVolumeSource{
+							ClaimName: tempPVC.Name,
+							ReadOnly:  true,
+						}},
+					}},
+				},
+			},
+		},
+	}
+}
@@ -0,0 +1,147 @@
+// Copyright (c) Microsoft Corporation.
+// Licensed under the MIT License.
+
+package controller
+
+import (
+	"context"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	batchv1 "k8s.io/api/batch/v1"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/tools/record"
+	"k8s.io/utils/ptr"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	dbpreview "github.com/documentdb/documentdb-operator/api/preview"
+	util "github.com/documentdb/documentdb-operator/internal/utils"
+)
+
+var _ = Describe("PV recovery check", func() {
+	const namespace = "default"
+
+	var (
+		ctx        context.Context
+		scheme     *runtime.Scheme
+		recorder   *record.FakeRecorder
+		documentdb *dbpreview.DocumentDB
+		tempPVC    *corev1.PersistentVolumeClaim
+	)
+
+	BeforeEach(func() {
+		ctx = context.Background()
+		scheme = runtime.NewScheme()
+		Expect(corev1.AddToScheme(scheme)).To(Succeed())
+		Expect(batchv1.AddToScheme(scheme)).To(Succeed())
+		Expect(dbpreview.AddToScheme(scheme)).To(Succeed())
+		recorder = record.NewFakeRecorder(10)
+		documentdb = &dbpreview.DocumentDB{
+			ObjectMeta: metav1.ObjectMeta{Name: "db", Namespace: namespace, UID: "uid"},
+			Spec: dbpreview.DocumentDBSpec{
+				Bootstrap: &dbpreview.BootstrapConfiguration{
+					Recovery: &dbpreview.RecoveryConfiguration{
+						PersistentVolume: &dbpreview.PVRecoveryConfiguration{Name: "retained-pv"},
+					},
+				},
+			},
+		}
+		tempPVC = &corev1.PersistentVolumeClaim{
+			ObjectMeta: metav1.ObjectMeta{Name: util.TempPVCNameForPVRecovery("db"), Namespace: namespace},
+			Spec:       corev1.PersistentVolumeClaimSpec{VolumeName: "retained-pv"},
+			Status:     corev1.PersistentVolumeClaimStatus{Phase: corev1.ClaimBound},
+		}
+	})
+
+	finishedJob := func(conditionType batchv1.JobConditionType) *batchv1.Job {
+		return &batchv1.Job{
+			ObjectMeta: metav1.ObjectMeta{Name: pvRecoveryCheckJobName("db"), Namespace: namespace},
+			Status: batchv1.JobStatus{Conditions: []batchv1.JobCondition{{
+				Type:    conditionType,
+				Status:  corev1.ConditionTrue,
+				Message: "Job has reached the specified backoff limit",
+			}}},
+		}
+	}
+	newReconciler := func(objects ...client.Object) *DocumentDBReconciler {
+		return &DocumentDBReconciler{
+			Client:   fake.NewClientBuilder().WithScheme(scheme).WithObjects(objects...).Build(),
+			Scheme:   scheme,
+			Recorder: recorder,
+		}
+	}
+	jobExists := func(r *DocumentDBReconciler) bool {
+		err := r.Get(ctx, types.NamespacedName{Name: pvRecoveryCheckJobName("db"), Namespace: namespace}, &batchv1.Job{})
+		if apierrors.IsNotFound(err) {
+			return false
+		}
+		Expect(err).ToNot(HaveOccurred())
+		return true
+	}
+
+	It("starts a Job mounting the volume read-only as the PostgreSQL user", func() {
+		documentdb.Spec.Postgres = &dbpreview.PostgresSpec{UID: ptr.To(int64(1000)), GID: ptr.To(int64(1000))}
+		r := newReconciler(documentdb, tempPVC)
+
+		result, err := r.checkPVRecoveryVolume(ctx, documentdb, tempPVC)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(result.RequeueAfter).To(Equal(RequeueAfterShort))
+
+		job := &batchv1.Job{}
+		Expect(r.Get(ctx, types.NamespacedName{Name: pvRecoveryCheckJobName("db"), Namespace: namespace}, job)).To(Succeed())
+		pod := job.Spec.Template.Spec
+		Expect(pod.Volumes[0].PersistentVolumeClaim.ClaimName).To(Equal(tempPVC.Name))
+		Expect(pod.Volumes[0].PersistentVolumeClaim.ReadOnly).To(BeTrue())
+		Expect(pod.Containers[0].VolumeMounts[0].ReadOnly).To(BeTrue())
+		Expect(*pod.SecurityContext.RunAsUser).To(Equal(int64(1000)))
+		Expect(job.OwnerReferences).To(HaveLen(1))
+	})
+
+	It("waits for the Job to finish", func() {
+		r := newReconciler(documentdb, tempPVC, &batchv1.Job{
+			ObjectMeta: metav1.ObjectMeta{Name: pvRecoveryCheckJobName("db"), Namespace: namespace},
+		})
+
+		result, err := r.checkPVRecoveryVolume(ctx, documentdb, tempPVC)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(result.RequeueAfter).To(Equal(RequeueAfterShort))
+		Expect(jobExists(r)).To(BeTrue())
+	})
+
+	It("marks the temp PVC once the PV passes the check", func() {
+		r := newReconciler(documentdb, tempPVC, finishedJob(batchv1.JobComplete))
+
+		result, err := r.checkPVRecoveryVolume(ctx, documentdb, tempPVC)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(result.RequeueAfter).To(BeZero())
+		Expect(jobExists(r)).To(BeFalse())
+
+		checked := &corev1.PersistentVolumeClaim{}
+		Expect(r.Get(ctx, client.ObjectKeyFromObject(tempPVC), checked)).To(Succeed())
+		Expect(checked.Annotations).To(HaveKeyWithValue(util.PVRecoveryCheckedAnnotation, "true"))
+		Expect(recorder.Events).To(Receive(ContainSubstring("Normal PVRecoveryChecked PV retained-pv holds a PostgreSQL data directory")))
+
+		result, err = r.checkPVRecoveryVolume(ctx, documentdb, checked)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(result.RequeueAfter).To(BeZero())
+		Expect(jobExists(r)).To(BeFalse())
+	})
+
+	It("releases the PV when it fails the check", func() {
+		r := newReconciler(documentdb, tempPVC, finishedJob(batchv1.JobFailed))
+
+		result, err := r.checkPVRecoveryVolume(ctx, documentdb, tempPVC)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(result.RequeueAfter).To(Equal(RequeueAfterLong))
+		Expect(jobExists(r)).To(BeFalse())
+
+		err = r.Get(ctx, client.ObjectKeyFromObject(tempPVC), &corev1.PersistentVolumeClaim{})
+		Expect(apierrors.IsNotFound(err)).To(BeTrue())
+		Expect(recorder.Events).To(Receive(ContainSubstring("PVRecoveryFailed Recovery from PV retained-pv failed: the PV does not hold a PostgreSQL data directory")))
+	})
+})
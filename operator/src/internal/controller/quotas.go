@@ -0,0 +1,107 @@
+// Copyright (c) Microsoft Corporation.
+// Licensed under the MIT License.
+
+package controller
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"strings"
+
+	cnpgv1 "github.com/cloudnative-pg/cloudnative-pg/api/v1"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/equality"
+	"k8s.io/apimachinery/pkg/api/resource"
+
+	dbpreview "github.com/documentdb/documentdb-operator/api/preview"
+	util "github.com/documentdb/documentdb-operator/internal/utils"
+)
+
+const mebibyte = 1024 * 1024
+
+// databaseSizesQuery returns, as a JSON object, the storage used by the
+// documents and indexes of each of the databases %s lists.
+const databaseSizesQuery = `SELECT COALESCE(json_object_agg(database_name, size), '{}') FROM (
+  SELECT c.database_name, sum(pg_total_relation_size(t.oid))::bigint AS size
+  FROM documentdb_api_catalog.collections c
+  JOIN pg_class t ON t.oid = to_regclass('documentdb_data.documents_' || c.collection_id)
+  WHERE c.database_name IN (%s)
+  GROUP BY c.database_name) sizes;`
+
+// reconcileQuotas reports in status.quotas the storage used by the databases
+// of spec.quotas, and records an event when a database exceeds its quota and
+// when it is back under it. It returns whether status.quotas changed.
+func (r *DocumentDBReconciler) reconcileQuotas(ctx context.Context, documentdb *dbpreview.DocumentDB, cluster *cnpgv1.Cluster) (bool, error) {
+	var statuses []dbpreview.DatabaseQuotaStatus
+	if len(documentdb.Spec.Quotas) > 0 {
+		if cluster.Status.CurrentPrimary == "" {
+			return false, nil
+		}
+		sizes, err := r.databaseSizes(ctx, cluster, documentdb.Spec.Quotas)
+		if err != nil {
+			return false, err
+		}
+		for _, quota := range documentdb.Spec.Quotas {
+			limit, err := resource.ParseQuantity(quota.MaxStorage)
+			if err != nil {
+				continue
+			}
+			used := sizes[quota.Database]
+			statuses = append(statuses, dbpreview.DatabaseQuotaStatus{
+				Database: quota.Database,
+				Used:     mebibytes(used),
+				Exceeded: used > limit.Value(),
+			})
+		}
+	}
+
+	previous := documentdb.Status.Quotas
+	if equality.Semantic.DeepEqual(previous, statuses) {
+		return false, nil
+	}
+	if r.Recorder != nil {
+		for _, status := range statuses {
+			wasExceeded := slices.ContainsFunc(previous, func(before dbpreview.DatabaseQuotaStatus) bool {
+				return before.Database == status.Database && before.Exceeded
+			})
+			switch {
+			case status.Exceeded && !wasExceeded:
+				r.Recorder.Event(documentdb, corev1.EventTypeWarning, "QuotaExceeded", fmt.Sprintf(
+					"Database %s uses %s, over its quota of %s", status.Database, status.Used, quotaOf(documentdb, status.Database)))
+			case !status.Exceeded && wasExceeded:
+				r.Recorder.Event(documentdb, corev1.EventTypeNormal, "QuotaRestored", fmt.Sprintf(
+					"Database %s uses %s, back under its quota of %s", status.Database, status.Used, quotaOf(documentdb, status.Database)))
+			}
+		}
+	}
+	documentdb.Status.Quotas = statuses
+	return true, nil
+}
+
+// databaseSizes returns the storage, in bytes, used by the databases of
+// quotas. Databases without collections are left out.
+func (r *DocumentDBReconciler) databaseSizes(ctx context.Context, cluster *cnpgv1.Cluster, quotas []dbpreview.DatabaseQuota) (map[string]int64, error) {
+	names := make([]string, 0, len(quotas))
+	for _, quota := range quotas {
+		names = append(names, util.QuoteLiteral(quota.Database))
+	}
+	output, err := r.querySQL(ctx, cluster, fmt.Sprintf(databaseSizesQuery, strings.Join(names, ", ")))
+	if err != nil {
+		return nil, fmt.Errorf("failed to query the size of the databases: %w", err)
+	}
+	sizes := map[string]int64{}
+	if err := parseJSONRowFromOutput(output, &sizes); err != nil {
+		return nil, fmt.Errorf("failed to parse the size of the databases: %w", err)
+	}
+	return sizes, nil
+}
+
+func quotaOf(documentdb *dbpreview.DocumentDB, database string) string {
+	for _, quota := range documentdb.Spec.Quotas {
+		if quota.Database == database {
+			return quota.MaxStorage
+		}
+	}
+	return ""
+}
@@ -0,0 +1,116 @@
+// Copyright (c) Microsoft Corporation.
+// Licensed under the MIT License.
+
+package controller
+
+import (
+	"context"
+	"fmt"
+
+	cnpgv1 "github.com/cloudnative-pg/cloudnative-pg/api/v1"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/tools/record"
+
+	dbpreview "github.com/documentdb/documentdb-operator/api/preview"
+)
+
+var _ = Describe("Database quotas", func() {
+	var (
+		ctx         context.Context
+		recorder    *record.FakeRecorder
+		reconciler  *DocumentDBReconciler
+		documentdb  *dbpreview.DocumentDB
+		cnpgCluster *cnpgv1.Cluster
+		executed    []string
+		output      string
+		sqlErr      error
+	)
+
+	BeforeEach(func() {
+		ctx = context.Background()
+		recorder = record.NewFakeRecorder(10)
+		executed = nil
+		output = ""
+		sqlErr = nil
+		documentdb = &dbpreview.DocumentDB{
+			ObjectMeta: metav1.ObjectMeta{Name: "db", Namespace: "default"},
+			Spec: dbpreview.DocumentDBSpec{
+				Quotas: []dbpreview.DatabaseQuota{
+					{Database: "orders", MaxStorage: "1Gi"},
+					{Database: "o'brien", MaxStorage: "10Mi"},
+				},
+			},
+		}
+		cnpgCluster = &cnpgv1.Cluster{Status: cnpgv1.ClusterStatus{CurrentPrimary: "db-1"}}
+		reconciler = &DocumentDBReconciler{
+			Recorder: recorder,
+			SQLExecutor: func(_ context.Context, _ *cnpgv1.Cluster, sql string) (string, error) {
+				executed = append(executed, sql)
+				return output, sqlErr
+			},
+		}
+	})
+
+	psqlOutput := func(json string) string {
+		return fmt.Sprintf(" coalesce \n----------\n %s\n(1 row)\n", json)
+	}
+
+	It("reports the usage of each database and quotes their names", func() {
+		output = psqlOutput(`{ "orders" : 5242880 }`)
+		changed, err := reconciler.reconcileQuotas(ctx, documentdb, cnpgCluster)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(changed).To(BeTrue())
+		Expect(executed).To(HaveLen(1))
+		Expect(executed[0]).To(ContainSubstring("IN ('orders', 'o''brien')"))
+		Expect(documentdb.Status.Quotas).To(Equal([]dbpreview.DatabaseQuotaStatus{
+			{Database: "orders", Used: "5Mi"},
+			{Database: "o'brien", Used: "0Mi"},
+		}))
+		Expect(recorder.Events).To(BeEmpty())
+	})
+
+	It("records an event when a database exceeds its quota and when it is back under it", func() {
+		output = psqlOutput(`{ "o'brien" : 10485761 }`)
+		changed, err := reconciler.reconcileQuotas(ctx, documentdb, cnpgCluster)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(changed).To(BeTrue())
+		Expect(documentdb.Status.Quotas[1]).To(Equal(dbpreview.DatabaseQuotaStatus{Database: "o'brien", Used: "11Mi", Exceeded: true}))
+		Expect(recorder.Events).To(Receive(ContainSubstring("QuotaExceeded Database o'brien uses 11Mi, over its quota of 10Mi")))
+
+		changed, err = reconciler.reconcileQuotas(ctx, documentdb, cnpgCluster)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(changed).To(BeFalse())
+		Expect(recorder.Events).To(BeEmpty())
+
+		output = psqlOutput(`{ "o'brien" : 1048576 }`)
+		changed, err = reconciler.reconcileQuotas(ctx, documentdb, cnpgCluster)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(changed).To(BeTrue())
+		Expect(recorder.Events).To(Receive(ContainSubstring("QuotaRestored")))
+	})
+
+	It("clears the status once the quotas are removed", func() {
+		documentdb.Status.Quotas = []dbpreview.DatabaseQuotaStatus{{Database: "orders", Used: "5Mi"}}
+		documentdb.Spec.Quotas = nil
+		changed, err := reconciler.reconcileQuotas(ctx, documentdb, cnpgCluster)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(changed).To(BeTrue())
+		Expect(documentdb.Status.Quotas).To(BeNil())
+		Expect(executed).To(BeEmpty())
+	})
+
+	It("keeps the status when the query fails", func() {
+		sqlErr = fmt.Errorf("connection refused")
+		changed, err := reconciler.reconcileQuotas(ctx, documentdb, cnpgCluster)
+		Expect(err).To(MatchError(ContainSubstring("connection refused")))
+		Expect(changed).To(BeFalse())
+	})
+
+	It("rejects unexpected output", func() {
+		output = "ERROR"
+		_, err := reconciler.reconcileQuotas(ctx, documentdb, cnpgCluster)
+		Expect(err).To(MatchError(ContainSubstring("no JSON object")))
+	})
+})
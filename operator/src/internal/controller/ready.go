@@ -0,0 +1,56 @@
+// Copyright (c) Microsoft Corporation.
+// Licensed under the MIT License.
+
+package controller
+
+import (
+	cnpgv1 "github.com/cloudnative-pg/cloudnative-pg/api/v1"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	dbpreview "github.com/documentdb/documentdb-operator/api/preview"
+)
+
+// failedClusterPhases are the phases of a CNPG Cluster that do not resolve
+// without a change of the spec or a manual intervention.
+var failedClusterPhases = map[string]bool{
+	cnpgv1.PhaseUnrecoverable:              true,
+	cnpgv1.PhaseCannotCreateClusterObjects: true,
+	cnpgv1.PhaseImageCatalogError:          true,
+	cnpgv1.PhaseUnknownPlugin:              true,
+	cnpgv1.PhaseFailurePlugin:              true,
+	cnpgv1.PhaseArchitectureBinaryMissing:  true,
+	cnpgv1.PhaseWaitingForUser:             true,
+}
+
+// reconcileReadyCondition records the health of the CNPG Cluster in the Ready
+// condition, and that the current generation of documentdb was applied to it
+// in status.observedGeneration. It returns whether the status changed.
+func reconcileReadyCondition(documentdb *dbpreview.DocumentDB, cluster *cnpgv1.Cluster) bool {
+	condition := metav1.Condition{
+		Type:               dbpreview.ConditionReady,
+		Status:             metav1.ConditionFalse,
+		Reason:             dbpreview.ReasonClusterProgressing,
+		Message:            cluster.Status.Phase,
+		ObservedGeneration: documentdb.Generation,
+	}
+	switch {
+	case cluster.Status.Phase == cnpgv1.PhaseHealthy:
+		condition.Status = metav1.ConditionTrue
+		condition.Reason = dbpreview.ReasonClusterHealthy
+	case failedClusterPhases[cluster.Status.Phase]:
+		condition.Reason = dbpreview.ReasonClusterFailed
+		if cluster.Status.PhaseReason != "" {
+			condition.Message += ": " + cluster.Status.PhaseReason
+		}
+	case cluster.Status.Phase == "":
+		condition.Message = "Waiting for the CNPG Cluster to report its phase"
+	}
+
+	changed := meta.SetStatusCondition(&documentdb.Status.Conditions, condition)
+	if documentdb.Status.ObservedGeneration != documentdb.Generation {
+		documentdb.Status.ObservedGeneration = documentdb.Generation
+		changed = true
+	}
+	return changed
+}
@@ -0,0 +1,71 @@
+// Copyright (c) Microsoft Corporation.
+// Licensed under the MIT License.
+
+package controller
+
+import (
+	cnpgv1 "github.com/cloudnative-pg/cloudnative-pg/api/v1"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	dbpreview "github.com/documentdb/documentdb-operator/api/preview"
+)
+
+var _ = Describe("Ready condition", func() {
+	var (
+		documentdb *dbpreview.DocumentDB
+		cluster    *cnpgv1.Cluster
+	)
+
+	BeforeEach(func() {
+		documentdb = &dbpreview.DocumentDB{
+			ObjectMeta: metav1.ObjectMeta{Name: "db", Namespace: "default", Generation: 3},
+		}
+		cluster = &cnpgv1.Cluster{}
+	})
+
+	ready := func() *metav1.Condition {
+		return meta.FindStatusCondition(documentdb.Status.Conditions, dbpreview.ConditionReady)
+	}
+
+	It("is True for a healthy cluster and records the observed generation", func() {
+		cluster.Status.Phase = cnpgv1.PhaseHealthy
+		Expect(reconcileReadyCondition(documentdb, cluster)).To(BeTrue())
+		Expect(ready().Status).To(Equal(metav1.ConditionTrue))
+		Expect(ready().Reason).To(Equal(dbpreview.ReasonClusterHealthy))
+		Expect(ready().ObservedGeneration).To(Equal(int64(3)))
+		Expect(documentdb.Status.ObservedGeneration).To(Equal(int64(3)))
+	})
+
+	It("reports a transient phase as progressing", func() {
+		cluster.Status.Phase = cnpgv1.PhaseCreatingReplica
+		reconcileReadyCondition(documentdb, cluster)
+		Expect(ready().Status).To(Equal(metav1.ConditionFalse))
+		Expect(ready().Reason).To(Equal(dbpreview.ReasonClusterProgressing))
+		Expect(ready().Message).To(Equal(cnpgv1.PhaseCreatingReplica))
+	})
+
+	It("reports a phase that needs an intervention as failed", func() {
+		cluster.Status.Phase = cnpgv1.PhaseImageCatalogError
+		cluster.Status.PhaseReason = "missing major 17"
+		reconcileReadyCondition(documentdb, cluster)
+		Expect(ready().Status).To(Equal(metav1.ConditionFalse))
+		Expect(ready().Reason).To(Equal(dbpreview.ReasonClusterFailed))
+		Expect(ready().Message).To(Equal(cnpgv1.PhaseImageCatalogError + ": missing major 17"))
+	})
+
+	It("leaves the status alone while nothing changes", func() {
+		cluster.Status.Phase = cnpgv1.PhaseHealthy
+		reconcileReadyCondition(documentdb, cluster)
+		transition := ready().LastTransitionTime
+		Expect(reconcileReadyCondition(documentdb, cluster)).To(BeFalse())
+		Expect(ready().LastTransitionTime).To(Equal(transition))
+
+		documentdb.Generation = 4
+		Expect(reconcileReadyCondition(documentdb, cluster)).To(BeTrue())
+		Expect(documentdb.Status.ObservedGeneration).To(Equal(int64(4)))
+		Expect(ready().LastTransitionTime).To(Equal(transition))
+	})
+})
@@ -0,0 +1,103 @@
+// Copyright (c) Microsoft Corporation.
+// Licensed under the MIT License.
+
+package controller
+
+import (
+	"context"
+	"fmt"
+
+	"k8s.io/apimachinery/pkg/api/equality"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/util/retry"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/event"
+	"sigs.k8s.io/controller-runtime/pkg/log"
+	"sigs.k8s.io/controller-runtime/pkg/predicate"
+
+	dbpreview "github.com/documentdb/documentdb-operator/api/preview"
+)
+
+// recordReconcileFailure logs err and counts the reconcile as failed in
+// status.reconcileFailures, with err as status.lastError. It is called by
+// the paths that end the reconcile early because of err.
+func (r *DocumentDBReconciler) recordReconcileFailure(ctx context.Context, documentdb *dbpreview.DocumentDB, err error, message string) {
+	log.FromContext(ctx).Error(err, message)
+	now := metav1.Now()
+	r.updateReconcileFailures(ctx, documentdb, func(status *dbpreview.DocumentDBStatus) bool {
+		status.ReconcileFailures++
+		status.LastError = fmt.Sprintf("%s: %v", message, err)
+		status.LastErrorTime = &now
+		return true
+	})
+}
+
+// resetReconcileFailures clears status.reconcileFailures after a reconcile
+// that went through. status.lastError is kept for reference.
+func (r *DocumentDBReconciler) resetReconcileFailures(ctx context.Context, documentdb *dbpreview.DocumentDB) {
+	if documentdb.Status.ReconcileFailures == 0 {
+		return
+	}
+	r.updateReconcileFailures(ctx, documentdb, func(status *dbpreview.DocumentDBStatus) bool {
+		if status.ReconcileFailures == 0 {
+			return false
+		}
+		status.ReconcileFailures = 0
+		return true
+	})
+}
+
+// updateReconcileFailures applies mutate to the status of a fresh copy of
+// documentdb and writes it, retrying on conflicts, since the reconcile may
+// have written the status already. The failure count is only informative,
+// so an error writing it is logged rather than returned.
+func (r *DocumentDBReconciler) updateReconcileFailures(ctx context.Context, documentdb *dbpreview.DocumentDB, mutate func(*dbpreview.DocumentDBStatus) bool) {
+	err := retry.RetryOnConflict(retry.DefaultRetry, func() error {
+		current := &dbpreview.DocumentDB{}
+		if err := r.Get(ctx, client.ObjectKeyFromObject(documentdb), current); err != nil {
+			return client.IgnoreNotFound(err)
+		}
+		if !mutate(&current.Status) {
+			return nil
+		}
+		if err := r.Status().Update(ctx, current); err != nil {
+			return err
+		}
+		documentdb.Status.ReconcileFailures = current.Status.ReconcileFailures
+		documentdb.Status.LastError = current.Status.LastError
+		documentdb.Status.LastErrorTime = current.Status.LastErrorTime
+		return nil
+	})
+	if err != nil {
+		log.FromContext(ctx).Error(err, "Failed to update reconcile failures")
+	}
+}
+
+// ignoreReconcileFailureUpdatesPredicate drops the updates of a DocumentDB
+// that only change its reconcile failure count, so that recording a failure
+// does not trigger the next reconcile before the requeue delay.
+func ignoreReconcileFailureUpdatesPredicate() predicate.Predicate {
+	return predicate.Funcs{
+		UpdateFunc: func(e event.UpdateEvent) bool {
+			oldDB, ok := e.ObjectOld.(*dbpreview.DocumentDB)
+			if !ok {
+				return true
+			}
+			newDB, ok := e.ObjectNew.(*dbpreview.DocumentDB)
+			if !ok {
+				return true
+			}
+			return !equality.Semantic.DeepEqual(withoutReconcileFailures(oldDB), withoutReconcileFailures(newDB))
+		},
+	}
+}
+
+func withoutReconcileFailures(documentdb *dbpreview.DocumentDB) *dbpreview.DocumentDB {
+	stripped := documentdb.DeepCopy()
+	stripped.ResourceVersion = ""
+	stripped.ManagedFields = nil
+	stripped.Status.ReconcileFailures = 0
+	stripped.Status.LastError = ""
+	stripped.Status.LastErrorTime = nil
+	return stripped
+}
@@ -0,0 +1,82 @@
+// Copyright (c) Microsoft Corporation.
+// Licensed under the MIT License.
+
+package controller
+
+import (
+	"context"
+	"fmt"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+	"sigs.k8s.io/controller-runtime/pkg/event"
+
+	dbpreview "github.com/documentdb/documentdb-operator/api/preview"
+)
+
+var _ = Describe("Reconcile failures", func() {
+	var (
+		ctx        context.Context
+		reconciler *DocumentDBReconciler
+		documentdb *dbpreview.DocumentDB
+	)
+
+	BeforeEach(func() {
+		ctx = context.Background()
+		scheme := runtime.NewScheme()
+		Expect(dbpreview.AddToScheme(scheme)).To(Succeed())
+		documentdb = &dbpreview.DocumentDB{ObjectMeta: metav1.ObjectMeta{Name: "db", Namespace: "default"}}
+		reconciler = &DocumentDBReconciler{
+			Client: fake.NewClientBuilder().WithScheme(scheme).WithObjects(documentdb.DeepCopy()).
+				WithStatusSubresource(&dbpreview.DocumentDB{}).Build(),
+			Scheme: scheme,
+		}
+	})
+
+	stored := func() *dbpreview.DocumentDB {
+		current := &dbpreview.DocumentDB{}
+		Expect(reconciler.Get(ctx, client.ObjectKeyFromObject(documentdb), current)).To(Succeed())
+		return current
+	}
+
+	It("counts consecutive failures and resets the count on success", func() {
+		reconciler.recordReconcileFailure(ctx, documentdb, fmt.Errorf("quota exceeded"), "Failed to create CNPG Cluster")
+		reconciler.recordReconcileFailure(ctx, documentdb, fmt.Errorf("quota exceeded"), "Failed to create CNPG Cluster")
+
+		current := stored()
+		Expect(current.Status.ReconcileFailures).To(Equal(int32(2)))
+		Expect(current.Status.LastError).To(Equal("Failed to create CNPG Cluster: quota exceeded"))
+		Expect(current.Status.LastErrorTime).ToNot(BeNil())
+		Expect(documentdb.Status.ReconcileFailures).To(Equal(int32(2)))
+
+		reconciler.resetReconcileFailures(ctx, documentdb)
+		current = stored()
+		Expect(current.Status.ReconcileFailures).To(BeZero())
+		Expect(current.Status.LastError).To(Equal("Failed to create CNPG Cluster: quota exceeded"))
+	})
+
+	It("keeps the status written earlier in the reconcile", func() {
+		current := stored()
+		current.Status.Status = "Cluster in healthy state"
+		Expect(reconciler.Status().Update(ctx, current)).To(Succeed())
+
+		reconciler.recordReconcileFailure(ctx, documentdb, fmt.Errorf("timeout"), "Failed to reconcile export CronJob")
+		Expect(stored().Status.Status).To(Equal("Cluster in healthy state"))
+	})
+
+	It("ignores updates that only change the failure count", func() {
+		p := ignoreReconcileFailureUpdatesPredicate()
+		updated := documentdb.DeepCopy()
+		updated.ResourceVersion = "2"
+		updated.Status.ReconcileFailures = 3
+		updated.Status.LastError = "boom"
+		Expect(p.Update(event.UpdateEvent{ObjectOld: documentdb, ObjectNew: updated})).To(BeFalse())
+
+		updated.Spec.InstancesPerNode = 3
+		Expect(p.Update(event.UpdateEvent{ObjectOld: documentdb, ObjectNew: updated})).To(BeTrue())
+	})
+})
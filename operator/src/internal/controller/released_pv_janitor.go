@@ -0,0 +1,163 @@
+// Copyright (c) Microsoft Corporation.
+// Licensed under the MIT License.
+
+package controller
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/tools/record"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/builder"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/log"
+	"sigs.k8s.io/controller-runtime/pkg/predicate"
+
+	dbpreview "github.com/documentdb/documentdb-operator/api/preview"
+	util "github.com/documentdb/documentdb-operator/internal/utils"
+)
+
+// releasedPVRecheckInterval is how often a Released PV that is not due for
+// deletion is looked at again, so that a change of the operator settings, of
+// the retention hold or of its DocumentDB is noticed.
+const releasedPVRecheckInterval = time.Hour
+
+// ReleasedPVReconciler deletes the Released PVs of deleted DocumentDB
+// clusters once they have been retained for the period set by the
+// DOCUMENTDB_RELEASED_PV_RETENTION operator setting. It is a no-op while the
+// setting is unset.
+type ReleasedPVReconciler struct {
+	client.Client
+	// WatchNamespaces restricts the janitor to PVs of DocumentDBs in these
+	// namespaces. Empty means all namespaces (see --watch-namespaces).
+	WatchNamespaces []string
+	// Recorder emits events on the PVs the janitor schedules or deletes.
+	Recorder record.EventRecorder
+	// APIReader reads an object from the API server again when a write
+	// conflicts, since the cache may not have caught up with the conflicting
+	// write yet. Defaults to the API reader of the manager.
+	APIReader client.Reader
+}
+
+// +kubebuilder:rbac:groups="",resources=persistentvolumes,verbs=get;list;watch;update;patch
+// +kubebuilder:rbac:groups="",resources=events,verbs=create;patch
+
+// Reconcile records on a Released PV of a deleted DocumentDB when it expires,
+// in the documentdb.io/retained-until annotation, and deletes it afterwards
+// by switching its reclaim policy to Delete, so that the provisioner deletes
+// the volume along with the PV. PVs under retention hold are never deleted.
+func (r *ReleasedPVReconciler) Reconcile(ctx context.Context, req ctrl.Request) (ctrl.Result, error) {
+	logger := log.FromContext(ctx)
+
+	pv := &corev1.PersistentVolume{}
+	if err := r.Get(ctx, req.NamespacedName, pv); err != nil {
+		return ctrl.Result{}, client.IgnoreNotFound(err)
+	}
+	if !isReleasedDocumentDBPV(pv) || pv.DeletionTimestamp != nil ||
+		pv.Spec.PersistentVolumeReclaimPolicy != corev1.PersistentVolumeReclaimRetain ||
+		!util.IsWatchedNamespace(r.WatchNamespaces, pv.Labels[util.LabelNamespace]) {
+		return ctrl.Result{}, nil
+	}
+
+	retention := util.GetReleasedPVRetention()
+	if retention == 0 || isRetentionHeld(pv) {
+		return ctrl.Result{RequeueAfter: releasedPVRecheckInterval}, nil
+	}
+
+	// The volume of a DocumentDB that exists, e.g. one recreated with the
+	// same name, may still be recovered from: it does not expire
+	documentdb := &dbpreview.DocumentDB{}
+	err := r.Get(ctx, types.NamespacedName{Name: pv.Labels[util.LabelCluster], Namespace: pv.Labels[util.LabelNamespace]}, documentdb)
+	if err == nil {
+		if _, ok := pv.Annotations[util.RETAINED_UNTIL_ANNOTATION]; ok {
+			if err := util.PatchWithRetry(ctx, r.Client, r.APIReader, pv, func() error {
+				delete(pv.Annotations, util.RETAINED_UNTIL_ANNOTATION)
+				return nil
+			}); err != nil {
+				return ctrl.Result{}, fmt.Errorf("failed to clear the expiry of PV %s: %w", pv.Name, err)
+			}
+		}
+		return ctrl.Result{RequeueAfter: releasedPVRecheckInterval}, nil
+	}
+	if !errors.IsNotFound(err) {
+		return ctrl.Result{}, fmt.Errorf("failed to get DocumentDB of PV %s: %w", pv.Name, err)
+	}
+
+	value, ok := pv.Annotations[util.RETAINED_UNTIL_ANNOTATION]
+	if !ok {
+		retainedUntil := time.Now().Add(retention).UTC().Truncate(time.Second)
+		if err := util.PatchWithRetry(ctx, r.Client, r.APIReader, pv, func() error {
+			if pv.Annotations == nil {
+				pv.Annotations = map[string]string{}
+			}
+			pv.Annotations[util.RETAINED_UNTIL_ANNOTATION] = retainedUntil.Format(time.RFC3339)
+			return nil
+		}); err != nil {
+			return ctrl.Result{}, fmt.Errorf("failed to set the expiry of PV %s: %w", pv.Name, err)
+		}
+		logger.Info("Released PV of a deleted DocumentDB scheduled for deletion", "pv", pv.Name, "retainedUntil", retainedUntil)
+		r.event(pv, corev1.EventTypeNormal, "ReleasedPVRetained", fmt.Sprintf(
+			"DocumentDB %s/%s no longer exists; PV is retained until %s, then deleted",
+			pv.Labels[util.LabelNamespace], pv.Labels[util.LabelCluster], retainedUntil.Format(time.RFC3339)))
+		return ctrl.Result{RequeueAfter: retention}, nil
+	}
+	retainedUntil, err := time.Parse(time.RFC3339, value)
+	if err != nil {
+		r.event(pv, corev1.EventTypeWarning, "InvalidRetainedUntil", fmt.Sprintf(
+			"Not deleting PV: annotation %s=%q is not an RFC 3339 timestamp", util.RETAINED_UNTIL_ANNOTATION, value))
+		return ctrl.Result{RequeueAfter: releasedPVRecheckInterval}, nil
+	}
+	if remaining := time.Until(retainedUntil); remaining > 0 {
+		return ctrl.Result{RequeueAfter: min(remaining, releasedPVRecheckInterval)}, nil
+	}
+
+	if util.IsReleasedPVCleanupDryRun() {
+		logger.Info("Dry run: would delete expired Released PV", "pv", pv.Name, "retainedUntil", value)
+		r.event(pv, corev1.EventTypeNormal, "ReleasedPVExpired", fmt.Sprintf(
+			"Dry run: PV expired at %s and would be deleted", value))
+		return ctrl.Result{RequeueAfter: releasedPVRecheckInterval}, nil
+	}
+	if err := util.PatchWithRetry(ctx, r.Client, r.APIReader, pv, func() error {
+		pv.Spec.PersistentVolumeReclaimPolicy = corev1.PersistentVolumeReclaimDelete
+		return nil
+	}); err != nil {
+		return ctrl.Result{}, fmt.Errorf("failed to delete expired PV %s: %w", pv.Name, err)
+	}
+	logger.Info("Deleting expired Released PV", "pv", pv.Name, "retainedUntil", value)
+	r.event(pv, corev1.EventTypeNormal, "ReleasedPVDeleted", fmt.Sprintf(
+		"PV expired at %s; its reclaim policy is now Delete so that the provisioner deletes it", value))
+	return ctrl.Result{}, nil
+}
+
+// event records an event on pv when the reconciler has a recorder.
+func (r *ReleasedPVReconciler) event(pv *corev1.PersistentVolume, eventType, reason, message string) {
+	if r.Recorder != nil {
+		r.Recorder.Event(pv, eventType, reason, message)
+	}
+}
+
+// isReleasedDocumentDBPV reports whether pv is Released and was labeled by
+// the PV controller as belonging to a DocumentDB.
+func isReleasedDocumentDBPV(pv *corev1.PersistentVolume) bool {
+	return pv.Status.Phase == corev1.VolumeReleased &&
+		pv.Labels[util.LabelCluster] != "" && pv.Labels[util.LabelNamespace] != ""
+}
+
+// SetupWithManager sets up the janitor with the Manager.
+func (r *ReleasedPVReconciler) SetupWithManager(mgr ctrl.Manager) error {
+	if r.APIReader == nil {
+		r.APIReader = mgr.GetAPIReader()
+	}
+	return ctrl.NewControllerManagedBy(mgr).
+		For(&corev1.PersistentVolume{}, builder.WithPredicates(predicate.NewPredicateFuncs(func(obj client.Object) bool {
+			pv, ok := obj.(*corev1.PersistentVolume)
+			return ok && isReleasedDocumentDBPV(pv)
+		}))).
+		Named("released-pv-janitor").
+		Complete(trackReconciles("released-pv-janitor", r))
+}
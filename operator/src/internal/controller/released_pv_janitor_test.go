@@ -0,0 +1,150 @@
+// Copyright (c) Microsoft Corporation.
+// Licensed under the MIT License.
+
+package controller
+
+import (
+	"context"
+	"time"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/tools/record"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	dbpreview "github.com/documentdb/documentdb-operator/api/preview"
+	util "github.com/documentdb/documentdb-operator/internal/utils"
+)
+
+var _ = Describe("Released PV janitor", func() {
+	const namespace = "default"
+
+	var (
+		ctx      context.Context
+		scheme   *runtime.Scheme
+		recorder *record.FakeRecorder
+	)
+
+	BeforeEach(func() {
+		ctx = context.Background()
+		scheme = runtime.NewScheme()
+		Expect(corev1.AddToScheme(scheme)).To(Succeed())
+		Expect(dbpreview.AddToScheme(scheme)).To(Succeed())
+		recorder = record.NewFakeRecorder(10)
+		util.SetOperatorSettings(map[string]string{util.RELEASED_PV_RETENTION_ENV: "720h"})
+		DeferCleanup(func() { util.SetOperatorSettings(nil) })
+	})
+
+	releasedPV := func(retainedUntil string) *corev1.PersistentVolume {
+		pv := &corev1.PersistentVolume{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:   "pv-1",
+				Labels: map[string]string{util.LabelCluster: "db", util.LabelNamespace: namespace},
+			},
+			Spec:   corev1.PersistentVolumeSpec{PersistentVolumeReclaimPolicy: corev1.PersistentVolumeReclaimRetain},
+			Status: corev1.PersistentVolumeStatus{Phase: corev1.VolumeReleased},
+		}
+		if retainedUntil != "" {
+			pv.Annotations = map[string]string{util.RETAINED_UNTIL_ANNOTATION: retainedUntil}
+		}
+		return pv
+	}
+	expired := time.Now().Add(-time.Hour).UTC().Format(time.RFC3339)
+	newReconciler := func(objects ...client.Object) *ReleasedPVReconciler {
+		return &ReleasedPVReconciler{
+			Client:   fake.NewClientBuilder().WithScheme(scheme).WithObjects(objects...).WithStatusSubresource(&corev1.PersistentVolume{}).Build(),
+			Recorder: recorder,
+		}
+	}
+	reconcilePV := func(r *ReleasedPVReconciler) (ctrl.Result, *corev1.PersistentVolume) {
+		result, err := r.Reconcile(ctx, ctrl.Request{NamespacedName: types.NamespacedName{Name: "pv-1"}})
+		Expect(err).ToNot(HaveOccurred())
+		pv := &corev1.PersistentVolume{}
+		Expect(r.Get(ctx, types.NamespacedName{Name: "pv-1"}, pv)).To(Succeed())
+		return result, pv
+	}
+
+	It("schedules the deletion of a Released PV of a deleted DocumentDB", func() {
+		r := newReconciler(releasedPV(""))
+
+		result, pv := reconcilePV(r)
+		Expect(result.RequeueAfter).To(Equal(720 * time.Hour))
+		retainedUntil, err := time.Parse(time.RFC3339, pv.Annotations[util.RETAINED_UNTIL_ANNOTATION])
+		Expect(err).ToNot(HaveOccurred())
+		Expect(retainedUntil).To(BeTemporally("~", time.Now().Add(720*time.Hour), time.Minute))
+		Expect(pv.Spec.PersistentVolumeReclaimPolicy).To(Equal(corev1.PersistentVolumeReclaimRetain))
+		Expect(recorder.Events).To(Receive(ContainSubstring("Normal ReleasedPVRetained DocumentDB default/db no longer exists")))
+	})
+
+	It("deletes an expired PV through its reclaim policy", func() {
+		r := newReconciler(releasedPV(expired))
+
+		_, pv := reconcilePV(r)
+		Expect(pv.Spec.PersistentVolumeReclaimPolicy).To(Equal(corev1.PersistentVolumeReclaimDelete))
+		Expect(recorder.Events).To(Receive(ContainSubstring("Normal ReleasedPVDeleted")))
+	})
+
+	It("only reports an expired PV in dry run", func() {
+		util.SetOperatorSettings(map[string]string{
+			util.RELEASED_PV_RETENTION_ENV:       "720h",
+			util.RELEASED_PV_CLEANUP_DRY_RUN_ENV: "true",
+		})
+		r := newReconciler(releasedPV(expired))
+
+		_, pv := reconcilePV(r)
+		Expect(pv.Spec.PersistentVolumeReclaimPolicy).To(Equal(corev1.PersistentVolumeReclaimRetain))
+		Expect(recorder.Events).To(Receive(ContainSubstring("Normal ReleasedPVExpired Dry run")))
+	})
+
+	It("never deletes a PV under retention hold", func() {
+		held := releasedPV(expired)
+		held.Annotations[util.RETENTION_HOLD_ANNOTATION] = "true"
+		r := newReconciler(held)
+
+		result, pv := reconcilePV(r)
+		Expect(result.RequeueAfter).To(Equal(releasedPVRecheckInterval))
+		Expect(pv.Spec.PersistentVolumeReclaimPolicy).To(Equal(corev1.PersistentVolumeReclaimRetain))
+		Expect(recorder.Events).ToNot(Receive())
+	})
+
+	It("clears the expiry of a PV whose DocumentDB exists again", func() {
+		r := newReconciler(releasedPV(expired),
+			&dbpreview.DocumentDB{ObjectMeta: metav1.ObjectMeta{Name: "db", Namespace: namespace}})
+
+		_, pv := reconcilePV(r)
+		Expect(pv.Annotations).ToNot(HaveKey(util.RETAINED_UNTIL_ANNOTATION))
+		Expect(pv.Spec.PersistentVolumeReclaimPolicy).To(Equal(corev1.PersistentVolumeReclaimRetain))
+	})
+
+	It("does nothing unless the retention is configured", func() {
+		util.SetOperatorSettings(nil)
+		r := newReconciler(releasedPV(""))
+
+		_, pv := reconcilePV(r)
+		Expect(pv.Annotations).ToNot(HaveKey(util.RETAINED_UNTIL_ANNOTATION))
+		Expect(recorder.Events).ToNot(Receive())
+	})
+
+	It("keeps a PV whose expiry is not a timestamp", func() {
+		r := newReconciler(releasedPV("next month"))
+
+		_, pv := reconcilePV(r)
+		Expect(pv.Spec.PersistentVolumeReclaimPolicy).To(Equal(corev1.PersistentVolumeReclaimRetain))
+		Expect(recorder.Events).To(Receive(ContainSubstring("Warning InvalidRetainedUntil")))
+	})
+
+	It("ignores PVs of DocumentDBs outside the watched namespaces", func() {
+		r := newReconciler(releasedPV(expired))
+		r.WatchNamespaces = []string{"other"}
+
+		result, pv := reconcilePV(r)
+		Expect(result).To(Equal(ctrl.Result{}))
+		Expect(pv.Spec.PersistentVolumeReclaimPolicy).To(Equal(corev1.PersistentVolumeReclaimRetain))
+	})
+})
@@ -0,0 +1,137 @@
+// Copyright (c) Microsoft Corporation.
+// Licensed under the MIT License.
+
+package controller
+
+import (
+	"context"
+	"fmt"
+	"slices"
+
+	fleetv1alpha1 "go.goms.io/fleet-networking/api/v1alpha1"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/log"
+
+	dbpreview "github.com/documentdb/documentdb-operator/api/preview"
+	util "github.com/documentdb/documentdb-operator/internal/utils"
+)
+
+// cleanupReplicationResources deletes the objects created for cross-cluster
+// replication of documentdb when it is deleted. Owner references do not cover
+// all of them: the promotion token objects and the Istio placeholder Services
+// have none, and the token ServiceExport is owned by the CNPG Cluster, which
+// outlives the DocumentDB with the Orphan deletion policy.
+func (r *DocumentDBReconciler) cleanupReplicationResources(ctx context.Context, documentdb *dbpreview.DocumentDB) error {
+	logger := log.FromContext(ctx)
+	namespace := client.InNamespace(documentdb.Namespace)
+
+	// ServiceExports and MultiClusterServices from CreateServiceImportAndExport
+	labels := client.MatchingLabels{util.LABEL_DOCUMENTDB_NAME: documentdb.Name}
+	exports := &fleetv1alpha1.ServiceExportList{}
+	if err := r.listFleetObjects(ctx, exports, namespace, labels); err != nil {
+		return fmt.Errorf("failed to list ServiceExports: %w", err)
+	}
+	for i := range exports.Items {
+		if err := r.deleteIfExists(ctx, &exports.Items[i]); err != nil {
+			return err
+		}
+	}
+	services := &fleetv1alpha1.MultiClusterServiceList{}
+	if err := r.listFleetObjects(ctx, services, namespace, labels); err != nil {
+		return fmt.Errorf("failed to list MultiClusterServices: %w", err)
+	}
+	for i := range services.Items {
+		if err := r.deleteIfExists(ctx, &services.Items[i]); err != nil {
+			return err
+		}
+	}
+
+	if documentdb.Spec.ClusterReplication == nil {
+		return nil
+	}
+
+	// Placeholder -rw Services for remote clusters from CreateIstioRemoteServices.
+	// Only Services with the non-matching selector are removed, never the ones
+	// CNPG creates for the local cluster.
+	replicationContext, err := util.GetReplicationContext(ctx, r.Client, *documentdb)
+	if err != nil {
+		logger.Error(err, "Failed to resolve replication topology, skipping remote Service cleanup")
+	} else {
+		for _, remoteCluster := range replicationContext.OtherCNPGClusterNames {
+			service := &corev1.Service{}
+			err := r.Get(ctx, client.ObjectKey{Name: remoteCluster + "-rw", Namespace: documentdb.Namespace}, service)
+			if errors.IsNotFound(err) {
+				continue
+			}
+			if err != nil {
+				return fmt.Errorf("failed to get Service %s-rw: %w", remoteCluster, err)
+			}
+			if service.Spec.Selector["cnpg.io/cluster"] != "does-not-exist" {
+				continue
+			}
+			if err := r.deleteIfExists(ctx, service); err != nil {
+				return err
+			}
+		}
+	}
+
+	// The promotion token objects have a fixed name per namespace, so leave them
+	// to any other replicated DocumentDB in the namespace.
+	shared, err := r.hasOtherReplicatedDocumentDB(ctx, documentdb)
+	if err != nil {
+		return err
+	}
+	if shared {
+		return nil
+	}
+	tokenObjects := []client.Object{
+		&corev1.ConfigMap{},
+		&corev1.Pod{},
+		&corev1.Service{},
+		&fleetv1alpha1.ServiceExport{},
+		&fleetv1alpha1.MultiClusterService{},
+	}
+	for _, obj := range tokenObjects {
+		obj.SetName(promotionTokenName)
+		obj.SetNamespace(documentdb.Namespace)
+		if err := r.deleteIfExists(ctx, obj); err != nil {
+			return err
+		}
+	}
+
+	logger.Info("Cleaned up replication resources")
+	return nil
+}
+
+// hasOtherReplicatedDocumentDB reports whether another DocumentDB in the
+// namespace of documentdb, not itself being deleted, uses cross-cluster replication.
+func (r *DocumentDBReconciler) hasOtherReplicatedDocumentDB(ctx context.Context, documentdb *dbpreview.DocumentDB) (bool, error) {
+	documentdbs := &dbpreview.DocumentDBList{}
+	if err := r.List(ctx, documentdbs, client.InNamespace(documentdb.Namespace)); err != nil {
+		return false, fmt.Errorf("failed to list DocumentDBs: %w", err)
+	}
+	return slices.ContainsFunc(documentdbs.Items, func(other dbpreview.DocumentDB) bool {
+		return other.Name != documentdb.Name &&
+			other.DeletionTimestamp.IsZero() &&
+			other.Spec.ClusterReplication != nil
+	}), nil
+}
+
+// listFleetObjects lists fleet networking objects, treating missing fleet CRDs
+// as an empty list.
+func (r *DocumentDBReconciler) listFleetObjects(ctx context.Context, list client.ObjectList, opts ...client.ListOption) error {
+	if err := r.List(ctx, list, opts...); err != nil && !util.IsCRDMissing(err) {
+		return err
+	}
+	return nil
+}
+
+// deleteIfExists deletes obj, ignoring objects, or fleet CRDs, that are already gone.
+func (r *DocumentDBReconciler) deleteIfExists(ctx context.Context, obj client.Object) error {
+	if err := r.Delete(ctx, obj); err != nil && !errors.IsNotFound(err) && !util.IsCRDMissing(err) {
+		return fmt.Errorf("failed to delete %T %s: %w", obj, obj.GetName(), err)
+	}
+	return nil
+}
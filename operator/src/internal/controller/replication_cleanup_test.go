@@ -0,0 +1,152 @@
+// Copyright (c) Microsoft Corporation.
+// Licensed under the MIT License.
+
+package controller
+
+import (
+	"context"
+
+	cnpgv1 "github.com/cloudnative-pg/cloudnative-pg/api/v1"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	fleetv1alpha1 "go.goms.io/fleet-networking/api/v1alpha1"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	dbpreview "github.com/documentdb/documentdb-operator/api/preview"
+	util "github.com/documentdb/documentdb-operator/internal/utils"
+)
+
+var _ = Describe("Replication resource cleanup", func() {
+	const namespace = "default"
+
+	var ctx context.Context
+
+	BeforeEach(func() {
+		ctx = context.Background()
+	})
+
+	buildReconciler := func(objs ...client.Object) *DocumentDBReconciler {
+		scheme := runtime.NewScheme()
+		Expect(dbpreview.AddToScheme(scheme)).To(Succeed())
+		Expect(cnpgv1.AddToScheme(scheme)).To(Succeed())
+		Expect(corev1.AddToScheme(scheme)).To(Succeed())
+		Expect(fleetv1alpha1.AddToScheme(scheme)).To(Succeed())
+		return &DocumentDBReconciler{
+			Client: fake.NewClientBuilder().WithScheme(scheme).WithObjects(objs...).Build(),
+			Scheme: scheme,
+		}
+	}
+
+	objectMeta := func(name string, labels map[string]string) metav1.ObjectMeta {
+		return metav1.ObjectMeta{Name: name, Namespace: namespace, Labels: labels}
+	}
+
+	exists := func(r *DocumentDBReconciler, name string, obj client.Object) bool {
+		err := r.Get(ctx, types.NamespacedName{Name: name, Namespace: namespace}, obj)
+		if errors.IsNotFound(err) {
+			return false
+		}
+		Expect(err).ToNot(HaveOccurred())
+		return true
+	}
+
+	It("deletes the fleet objects and promotion token objects of the DocumentDB", func() {
+		documentdb := baseDocumentDB("docdb", namespace)
+		enableAzureFleetReplication(documentdb)
+		ownLabels := map[string]string{util.LABEL_DOCUMENTDB_NAME: "docdb"}
+		otherLabels := map[string]string{util.LABEL_DOCUMENTDB_NAME: "other"}
+
+		r := buildReconciler(
+			documentdb,
+			fleetMemberNameConfigMap("member-a"),
+			&fleetv1alpha1.ServiceExport{ObjectMeta: objectMeta("docdb-export", ownLabels)},
+			&fleetv1alpha1.ServiceExport{ObjectMeta: objectMeta("other-export", otherLabels)},
+			&fleetv1alpha1.MultiClusterService{ObjectMeta: objectMeta("docdb-mcs", ownLabels)},
+			&fleetv1alpha1.ServiceExport{ObjectMeta: objectMeta(promotionTokenName, nil)},
+			&fleetv1alpha1.MultiClusterService{ObjectMeta: objectMeta(promotionTokenName, nil)},
+			&corev1.ConfigMap{ObjectMeta: objectMeta(promotionTokenName, nil)},
+			&corev1.Pod{ObjectMeta: objectMeta(promotionTokenName, nil)},
+			&corev1.Service{ObjectMeta: objectMeta(promotionTokenName, nil)},
+		)
+
+		Expect(r.cleanupReplicationResources(ctx, documentdb)).To(Succeed())
+
+		Expect(exists(r, "docdb-export", &fleetv1alpha1.ServiceExport{})).To(BeFalse())
+		Expect(exists(r, "docdb-mcs", &fleetv1alpha1.MultiClusterService{})).To(BeFalse())
+		Expect(exists(r, "other-export", &fleetv1alpha1.ServiceExport{})).To(BeTrue())
+		Expect(exists(r, promotionTokenName, &fleetv1alpha1.ServiceExport{})).To(BeFalse())
+		Expect(exists(r, promotionTokenName, &fleetv1alpha1.MultiClusterService{})).To(BeFalse())
+		Expect(exists(r, promotionTokenName, &corev1.ConfigMap{})).To(BeFalse())
+		Expect(exists(r, promotionTokenName, &corev1.Pod{})).To(BeFalse())
+		Expect(exists(r, promotionTokenName, &corev1.Service{})).To(BeFalse())
+
+		// Running again once everything is gone is a no-op
+		Expect(r.cleanupReplicationResources(ctx, documentdb)).To(Succeed())
+	})
+
+	It("keeps the promotion token objects while another replicated DocumentDB uses them", func() {
+		documentdb := baseDocumentDB("docdb", namespace)
+		enableAzureFleetReplication(documentdb)
+		other := baseDocumentDB("other", namespace)
+		enableAzureFleetReplication(other)
+
+		r := buildReconciler(
+			documentdb,
+			other,
+			fleetMemberNameConfigMap("member-a"),
+			&corev1.ConfigMap{ObjectMeta: objectMeta(promotionTokenName, nil)},
+		)
+
+		Expect(r.cleanupReplicationResources(ctx, documentdb)).To(Succeed())
+		Expect(exists(r, promotionTokenName, &corev1.ConfigMap{})).To(BeTrue())
+	})
+
+	It("deletes only the placeholder Services of remote clusters", func() {
+		documentdb := baseDocumentDB("docdb", namespace)
+		enableAzureFleetReplication(documentdb)
+		documentdb.Spec.ClusterReplication.CrossCloudNetworkingStrategy = string(util.Istio)
+		memberConfigMap := fleetMemberNameConfigMap("member-a")
+
+		replicationContext, err := util.GetReplicationContext(ctx, buildReconciler(memberConfigMap).Client, *documentdb)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(replicationContext.OtherCNPGClusterNames).To(HaveLen(1))
+		remoteService := replicationContext.OtherCNPGClusterNames[0] + "-rw"
+		localService := replicationContext.CNPGClusterName + "-rw"
+
+		r := buildReconciler(
+			documentdb,
+			memberConfigMap,
+			&corev1.Service{
+				ObjectMeta: objectMeta(remoteService, nil),
+				Spec:       corev1.ServiceSpec{Selector: map[string]string{"cnpg.io/cluster": "does-not-exist"}},
+			},
+			&corev1.Service{
+				ObjectMeta: objectMeta(localService, nil),
+				Spec:       corev1.ServiceSpec{Selector: map[string]string{"cnpg.io/cluster": replicationContext.CNPGClusterName}},
+			},
+		)
+
+		Expect(r.cleanupReplicationResources(ctx, documentdb)).To(Succeed())
+		Expect(exists(r, remoteService, &corev1.Service{})).To(BeFalse())
+		Expect(exists(r, localService, &corev1.Service{})).To(BeTrue())
+	})
+
+	It("tolerates missing fleet CRDs", func() {
+		documentdb := baseDocumentDB("docdb", namespace)
+		scheme := runtime.NewScheme()
+		Expect(dbpreview.AddToScheme(scheme)).To(Succeed())
+		Expect(corev1.AddToScheme(scheme)).To(Succeed())
+		r := &DocumentDBReconciler{
+			Client: fake.NewClientBuilder().WithScheme(scheme).WithObjects(documentdb).Build(),
+			Scheme: scheme,
+		}
+
+		Expect(r.cleanupReplicationResources(ctx, documentdb)).To(Succeed())
+	})
+})
@@ -0,0 +1,177 @@
+// Copyright (c) Microsoft Corporation.
+// Licensed under the MIT License.
+
+package controller
+
+import (
+	"context"
+	"strconv"
+	"time"
+
+	cnpgv1 "github.com/cloudnative-pg/cloudnative-pg/api/v1"
+	"github.com/prometheus/client_golang/prometheus"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/log"
+
+	dbpreview "github.com/documentdb/documentdb-operator/api/preview"
+	util "github.com/documentdb/documentdb-operator/internal/utils"
+)
+
+// retainedVolumeCollectTimeout bounds the cache reads of a scrape.
+const retainedVolumeCollectTimeout = 10 * time.Second
+
+var (
+	retainedVolumeLabels = []string{"kind", "namespace", "documentdb", "orphaned"}
+
+	retainedVolumesDesc = prometheus.NewDesc("documentdb_retained_volumes",
+		"Number of retained volumes of a DocumentDB: PersistentVolumes no longer bound to a PVC, "+
+			"and PVCs left behind by a deleted CNPG Cluster. orphaned is \"true\" when the DocumentDB no longer exists.",
+		retainedVolumeLabels, nil)
+	retainedVolumeCapacityDesc = prometheus.NewDesc("documentdb_retained_volume_capacity_bytes",
+		"Total capacity of the retained volumes of a DocumentDB.",
+		retainedVolumeLabels, nil)
+	retainedVolumeAgeDesc = prometheus.NewDesc("documentdb_retained_volume_oldest_age_seconds",
+		"Time since the oldest retained volume of a DocumentDB was released, or created for a PVC.",
+		retainedVolumeLabels, nil)
+)
+
+// retainedVolumeKey groups the retained volumes a sample reports on.
+type retainedVolumeKey struct {
+	kind       string
+	namespace  string
+	documentdb string
+	orphaned   bool
+}
+
+// retainedVolumeStats aggregates the retained volumes of a retainedVolumeKey.
+type retainedVolumeStats struct {
+	count    int
+	capacity int64
+	oldest   time.Time
+}
+
+// add counts a volume of capacity retained since since.
+func (s *retainedVolumeStats) add(capacity int64, since time.Time) {
+	s.count++
+	s.capacity += capacity
+	if s.oldest.IsZero() || since.Before(s.oldest) {
+		s.oldest = since
+	}
+}
+
+// RetainedVolumeCollector exports, on each scrape, the inventory of the
+// volumes kept by the retention policy of the DocumentDB clusters, so that
+// storage cost dashboards show what is being held onto. It reads from the
+// manager cache.
+type RetainedVolumeCollector struct {
+	Reader client.Reader
+	// WatchNamespaces restricts the inventory to volumes of DocumentDBs in
+	// these namespaces. Empty means all namespaces (see --watch-namespaces).
+	WatchNamespaces []string
+	// now returns the current time; tests override it.
+	now func() time.Time
+}
+
+// Describe implements prometheus.Collector.
+func (c *RetainedVolumeCollector) Describe(ch chan<- *prometheus.Desc) {
+	ch <- retainedVolumesDesc
+	ch <- retainedVolumeCapacityDesc
+	ch <- retainedVolumeAgeDesc
+}
+
+// Collect implements prometheus.Collector.
+func (c *RetainedVolumeCollector) Collect(ch chan<- prometheus.Metric) {
+	ctx, cancel := context.WithTimeout(context.Background(), retainedVolumeCollectTimeout)
+	defer cancel()
+	inventory, err := c.inventory(ctx)
+	if err != nil {
+		log.FromContext(ctx).Error(err, "Failed to collect the retained volume inventory")
+		return
+	}
+	now := time.Now()
+	if c.now != nil {
+		now = c.now()
+	}
+	for key, stats := range inventory {
+		labels := []string{key.kind, key.namespace, key.documentdb, strconv.FormatBool(key.orphaned)}
+		ch <- prometheus.MustNewConstMetric(retainedVolumesDesc, prometheus.GaugeValue, float64(stats.count), labels...)
+		ch <- prometheus.MustNewConstMetric(retainedVolumeCapacityDesc, prometheus.GaugeValue, float64(stats.capacity), labels...)
+		ch <- prometheus.MustNewConstMetric(retainedVolumeAgeDesc, prometheus.GaugeValue, now.Sub(stats.oldest).Seconds(), labels...)
+	}
+}
+
+// inventory groups the retained volumes by DocumentDB. A PV is retained once
+// it is no longer bound, and belongs to the DocumentDB it is labeled for by
+// the PV controller. A PVC is retained when the CNPG Cluster that created it
+// no longer exists, and belongs to the DocumentDB of its PV.
+func (c *RetainedVolumeCollector) inventory(ctx context.Context) (map[retainedVolumeKey]*retainedVolumeStats, error) {
+	documentdbs := &dbpreview.DocumentDBList{}
+	if err := c.Reader.List(ctx, documentdbs); err != nil {
+		return nil, err
+	}
+	exists := map[types.NamespacedName]bool{}
+	for _, documentdb := range documentdbs.Items {
+		exists[types.NamespacedName{Name: documentdb.Name, Namespace: documentdb.Namespace}] = true
+	}
+	inventory := map[retainedVolumeKey]*retainedVolumeStats{}
+	add := func(kind string, owner types.NamespacedName, capacity int64, since time.Time) {
+		key := retainedVolumeKey{kind: kind, namespace: owner.Namespace, documentdb: owner.Name, orphaned: !exists[owner]}
+		if inventory[key] == nil {
+			inventory[key] = &retainedVolumeStats{}
+		}
+		inventory[key].add(capacity, since)
+	}
+
+	pvs := &corev1.PersistentVolumeList{}
+	if err := c.Reader.List(ctx, pvs, client.HasLabels{util.LabelCluster, util.LabelNamespace}); err != nil {
+		return nil, err
+	}
+	pvOwners := map[string]types.NamespacedName{}
+	for i := range pvs.Items {
+		pv := &pvs.Items[i]
+		owner := types.NamespacedName{Name: pv.Labels[util.LabelCluster], Namespace: pv.Labels[util.LabelNamespace]}
+		if !util.IsWatchedNamespace(c.WatchNamespaces, owner.Namespace) {
+			continue
+		}
+		pvOwners[pv.Name] = owner
+		if pv.Status.Phase == corev1.VolumeBound || pv.Status.Phase == corev1.VolumePending {
+			continue
+		}
+		since := pv.CreationTimestamp.Time
+		if pv.Status.LastPhaseTransitionTime != nil {
+			since = pv.Status.LastPhaseTransitionTime.Time
+		}
+		capacity := pv.Spec.Capacity[corev1.ResourceStorage]
+		add("PersistentVolume", owner, capacity.Value(), since)
+	}
+
+	pvcs := &corev1.PersistentVolumeClaimList{}
+	if err := c.Reader.List(ctx, pvcs, client.HasLabels{"cnpg.io/cluster"}); err != nil {
+		return nil, err
+	}
+	clusters := map[types.NamespacedName]bool{}
+	for i := range pvcs.Items {
+		pvc := &pvcs.Items[i]
+		owner, ok := pvOwners[pvc.Spec.VolumeName]
+		if !ok {
+			continue
+		}
+		cluster := types.NamespacedName{Name: pvc.Labels["cnpg.io/cluster"], Namespace: pvc.Namespace}
+		if _, checked := clusters[cluster]; !checked {
+			err := c.Reader.Get(ctx, cluster, &cnpgv1.Cluster{})
+			if err != nil && !errors.IsNotFound(err) {
+				return nil, err
+			}
+			clusters[cluster] = err == nil
+		}
+		if clusters[cluster] {
+			continue
+		}
+		capacity := pvc.Status.Capacity[corev1.ResourceStorage]
+		add("PersistentVolumeClaim", owner, capacity.Value(), pvc.CreationTimestamp.Time)
+	}
+	return inventory, nil
+}
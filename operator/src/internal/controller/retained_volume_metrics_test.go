@@ -0,0 +1,112 @@
+// Copyright (c) Microsoft Corporation.
+// Licensed under the MIT License.
+
+package controller
+
+import (
+	"strings"
+	"time"
+
+	cnpgv1 "github.com/cloudnative-pg/cloudnative-pg/api/v1"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	dbpreview "github.com/documentdb/documentdb-operator/api/preview"
+	util "github.com/documentdb/documentdb-operator/internal/utils"
+)
+
+var _ = Describe("Retained volume metrics", func() {
+	var (
+		scheme *runtime.Scheme
+		now    time.Time
+	)
+
+	BeforeEach(func() {
+		scheme = runtime.NewScheme()
+		Expect(corev1.AddToScheme(scheme)).To(Succeed())
+		Expect(cnpgv1.AddToScheme(scheme)).To(Succeed())
+		Expect(dbpreview.AddToScheme(scheme)).To(Succeed())
+		now = time.Date(2026, 10, 1, 12, 0, 0, 0, time.UTC)
+	})
+
+	pv := func(name, documentdb string, phase corev1.PersistentVolumePhase, size string, releasedFor time.Duration) *corev1.PersistentVolume {
+		return &corev1.PersistentVolume{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:   name,
+				Labels: map[string]string{util.LabelCluster: documentdb, util.LabelNamespace: "default"},
+			},
+			Spec: corev1.PersistentVolumeSpec{
+				Capacity: corev1.ResourceList{corev1.ResourceStorage: resource.MustParse(size)},
+			},
+			Status: corev1.PersistentVolumeStatus{
+				Phase:                   phase,
+				LastPhaseTransitionTime: &metav1.Time{Time: now.Add(-releasedFor)},
+			},
+		}
+	}
+	pvc := func(name, cluster, volume string) *corev1.PersistentVolumeClaim {
+		return &corev1.PersistentVolumeClaim{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:              name,
+				Namespace:         "default",
+				Labels:            map[string]string{"cnpg.io/cluster": cluster},
+				CreationTimestamp: metav1.NewTime(now.Add(-time.Hour)),
+			},
+			Spec: corev1.PersistentVolumeClaimSpec{VolumeName: volume},
+			Status: corev1.PersistentVolumeClaimStatus{
+				Capacity: corev1.ResourceList{corev1.ResourceStorage: resource.MustParse("10Gi")},
+			},
+		}
+	}
+	newCollector := func(objects ...client.Object) *RetainedVolumeCollector {
+		return &RetainedVolumeCollector{
+			Reader: fake.NewClientBuilder().WithScheme(scheme).WithObjects(objects...).Build(),
+			now:    func() time.Time { return now },
+		}
+	}
+
+	It("reports the retained PVs and PVCs per DocumentDB", func() {
+		c := newCollector(
+			&dbpreview.DocumentDB{ObjectMeta: metav1.ObjectMeta{Name: "live", Namespace: "default"}},
+			&cnpgv1.Cluster{ObjectMeta: metav1.ObjectMeta{Name: "live", Namespace: "default"}},
+			pv("pv-live-bound", "live", corev1.VolumeBound, "10Gi", 0),
+			pv("pv-live-released", "live", corev1.VolumeReleased, "10Gi", time.Hour),
+			pv("pv-gone-1", "gone", corev1.VolumeReleased, "20Gi", 2*time.Hour),
+			pv("pv-gone-2", "gone", corev1.VolumeAvailable, "30Gi", 3*time.Hour),
+			pv("pv-leftover", "leftover", corev1.VolumeBound, "10Gi", 0),
+			pvc("live-1", "live", "pv-live-bound"),
+			pvc("leftover-1", "leftover", "pv-leftover"),
+			pvc("unrelated-1", "unrelated", "pv-unrelated"),
+		)
+
+		expected := `
+# HELP documentdb_retained_volume_capacity_bytes Total capacity of the retained volumes of a DocumentDB.
+# TYPE documentdb_retained_volume_capacity_bytes gauge
+documentdb_retained_volume_capacity_bytes{documentdb="gone",kind="PersistentVolume",namespace="default",orphaned="true"} 5.36870912e+10
+documentdb_retained_volume_capacity_bytes{documentdb="leftover",kind="PersistentVolumeClaim",namespace="default",orphaned="true"} 1.073741824e+10
+documentdb_retained_volume_capacity_bytes{documentdb="live",kind="PersistentVolume",namespace="default",orphaned="false"} 1.073741824e+10
+# HELP documentdb_retained_volume_oldest_age_seconds Time since the oldest retained volume of a DocumentDB was released, or created for a PVC.
+# TYPE documentdb_retained_volume_oldest_age_seconds gauge
+documentdb_retained_volume_oldest_age_seconds{documentdb="gone",kind="PersistentVolume",namespace="default",orphaned="true"} 10800
+documentdb_retained_volume_oldest_age_seconds{documentdb="leftover",kind="PersistentVolumeClaim",namespace="default",orphaned="true"} 3600
+documentdb_retained_volume_oldest_age_seconds{documentdb="live",kind="PersistentVolume",namespace="default",orphaned="false"} 3600
+`
+		Expect(testutil.CollectAndCompare(c, strings.NewReader(expected),
+			"documentdb_retained_volume_capacity_bytes", "documentdb_retained_volume_oldest_age_seconds")).To(Succeed())
+		Expect(testutil.CollectAndCount(c, "documentdb_retained_volumes")).To(Equal(3))
+	})
+
+	It("skips the volumes of DocumentDBs outside the watched namespaces", func() {
+		c := newCollector(pv("pv-gone", "gone", corev1.VolumeReleased, "10Gi", time.Hour))
+		c.WatchNamespaces = []string{"other"}
+
+		Expect(testutil.CollectAndCount(c)).To(Equal(0))
+	})
+})
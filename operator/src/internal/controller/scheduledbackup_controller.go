@@ -151,5 +151,5 @@ func (r *ScheduledBackupReconciler) SetupWithManager(mgr ctrl.Manager) error {
 
 	return ctrl.NewControllerManagedBy(mgr).
 		For(&dbpreview.ScheduledBackup{}).
-		Complete(r)
+		Complete(trackReconciles("scheduledbackup", r))
 }
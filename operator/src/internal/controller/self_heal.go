@@ -0,0 +1,236 @@
+// Copyright (c) Microsoft Corporation.
+// Licensed under the MIT License.
+
+package controller
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"time"
+
+	cnpgv1 "github.com/cloudnative-pg/cloudnative-pg/api/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/log"
+
+	dbpreview "github.com/documentdb/documentdb-operator/api/preview"
+)
+
+// defaultSelfHealCooldown is the time between two remediations when
+// spec.selfHeal.cooldownMinutes is not set.
+const defaultSelfHealCooldown = 30 * time.Minute
+
+// selfHealBlockingPhases are the CNPG Cluster phases during which CNPG itself
+// restarts, replaces or promotes instances, so that a replica that is not
+// ready is expected rather than broken.
+var selfHealBlockingPhases = []string{
+	cnpgv1.PhaseSwitchover,
+	cnpgv1.PhaseFailOver,
+	cnpgv1.PhaseFirstPrimary,
+	cnpgv1.PhaseCreatingReplica,
+	cnpgv1.PhaseUpgrade,
+	cnpgv1.PhaseMajorUpgrade,
+	cnpgv1.PhaseOnlineUpgrading,
+	cnpgv1.PhaseInplacePrimaryRestart,
+	cnpgv1.PhaseInplaceDeletePrimaryRestart,
+	cnpgv1.PhaseReplicaClusterPromotion,
+}
+
+// selfHealCandidate is a replica that spec.selfHeal would remediate.
+type selfHealCandidate struct {
+	pod            *corev1.Pod
+	action         dbpreview.SelfHealAction
+	unhealthySince time.Time
+}
+
+// reconcileSelfHeal remediates the replica of cluster that has been not ready
+// the longest, once it has been so for longer than spec.selfHeal allows, and
+// saves the remediation in status.selfHeal before it returns. It reports
+// whether the status changed.
+func (r *DocumentDBReconciler) reconcileSelfHeal(ctx context.Context, documentdb *dbpreview.DocumentDB, cluster *cnpgv1.Cluster) (bool, error) {
+	logger := log.FromContext(ctx)
+	status := documentdb.Status.SelfHeal
+	changed := false
+
+	// The remediated replica is healthy again: the next failure starts a new count
+	if status != nil && status.UnhealthySince != nil && slices.Contains(cluster.Status.InstancesStatus[cnpgv1.PodHealthy], status.Instance) {
+		status.UnhealthySince = nil
+		changed = true
+	}
+
+	policy := documentdb.Spec.SelfHeal
+	if policy == nil || (policy.RecreateAfterMinutes == 0 && policy.RecloneAfterMinutes == 0) {
+		return changed, nil
+	}
+
+	pods := &corev1.PodList{}
+	if err := r.List(ctx, pods, client.InNamespace(cluster.Namespace), client.MatchingLabels{
+		"cnpg.io/cluster": cluster.Name,
+		"cnpg.io/podRole": "instance",
+	}); err != nil {
+		return changed, fmt.Errorf("failed to list instance Pods: %w", err)
+	}
+	now := time.Now()
+	candidate := selfHealCandidateOf(policy, status, cluster.Status.CurrentPrimary, pods.Items, now)
+	if candidate == nil {
+		return changed, nil
+	}
+
+	if reason := selfHealBlockedReason(documentdb, cluster); reason != "" {
+		if r.Recorder != nil {
+			r.Recorder.Event(documentdb, corev1.EventTypeNormal, "SelfHealSkipped", fmt.Sprintf(
+				"Not remediating replica %s: %s", candidate.pod.Name, reason))
+		}
+		return changed, nil
+	}
+	cooldown := defaultSelfHealCooldown
+	if policy.CooldownMinutes > 0 {
+		cooldown = time.Duration(policy.CooldownMinutes) * time.Minute
+	}
+	if status != nil && now.Sub(status.Time.Time) < cooldown {
+		logger.Info("Waiting for the self-heal cooldown before remediating a replica",
+			"instance", candidate.pod.Name, "lastRemediation", status.Time)
+		return changed, nil
+	}
+
+	unhealthyFor := now.Sub(candidate.unhealthySince).Round(time.Minute)
+	var message string
+	switch candidate.action {
+	case dbpreview.SelfHealActionReclone:
+		pvcs, err := r.instancePVCs(ctx, cluster, candidate.pod.Name)
+		if err != nil {
+			return changed, err
+		}
+		if i := slices.IndexFunc(pvcs, func(pvc corev1.PersistentVolumeClaim) bool { return isRetentionHeld(&pvc) }); i >= 0 {
+			if r.Recorder != nil {
+				r.Recorder.Event(documentdb, corev1.EventTypeNormal, "SelfHealSkipped", fmt.Sprintf(
+					"Not recloning replica %s: PVC %s is under retention hold", candidate.pod.Name, pvcs[i].Name))
+			}
+			return changed, nil
+		}
+		for i := range pvcs {
+			if err := r.Delete(ctx, &pvcs[i]); client.IgnoreNotFound(err) != nil {
+				return changed, fmt.Errorf("failed to delete PVC %s: %w", pvcs[i].Name, err)
+			}
+		}
+		message = fmt.Sprintf("Deleted the Pod and the PVCs of replica %s, not ready for %s, so that CNPG clones a new replica from the primary",
+			candidate.pod.Name, unhealthyFor)
+	default:
+		message = fmt.Sprintf("Deleted the Pod of replica %s, not ready for %s, so that CNPG recreates it",
+			candidate.pod.Name, unhealthyFor)
+	}
+	if err := r.Delete(ctx, candidate.pod); client.IgnoreNotFound(err) != nil {
+		return changed, fmt.Errorf("failed to delete Pod %s: %w", candidate.pod.Name, err)
+	}
+	logger.Info("Remediated an unhealthy replica", "instance", candidate.pod.Name, "action", candidate.action)
+	if r.Recorder != nil {
+		r.Recorder.Event(documentdb, corev1.EventTypeWarning, "SelfHeal"+string(candidate.action), message)
+	}
+
+	documentdb.Status.SelfHeal = &dbpreview.SelfHealStatus{
+		Instance:       candidate.pod.Name,
+		Action:         candidate.action,
+		Time:           metav1.NewTime(now),
+		UnhealthySince: &metav1.Time{Time: candidate.unhealthySince},
+	}
+	// Saved now rather than with the rest of the status, so that a failed
+	// reconcile does not lose the cooldown and remediate again
+	if err := r.updateStatus(ctx, documentdb); err != nil {
+		return true, fmt.Errorf("failed to save the self-heal remediation: %w", err)
+	}
+	return true, nil
+}
+
+// selfHealCandidateOf returns the replica among pods that has been not ready
+// the longest and is due for a remediation of policy at now, or nil. status
+// is the last remediation: a recreated replica keeps counting from when it
+// first stopped being ready, and is not recreated twice.
+func selfHealCandidateOf(policy *dbpreview.SelfHealConfiguration, status *dbpreview.SelfHealStatus, primary string, pods []corev1.Pod, now time.Time) *selfHealCandidate {
+	var candidate *selfHealCandidate
+	for i := range pods {
+		pod := &pods[i]
+		if pod.Name == primary || pod.DeletionTimestamp != nil {
+			continue
+		}
+		since, notReady := podNotReadySince(pod)
+		if !notReady {
+			continue
+		}
+		remediated := status != nil && status.Instance == pod.Name && status.UnhealthySince != nil
+		if remediated && status.UnhealthySince.Time.Before(since) {
+			since = status.UnhealthySince.Time
+		}
+
+		var action dbpreview.SelfHealAction
+		unhealthyFor := now.Sub(since)
+		switch {
+		case policy.RecloneAfterMinutes > 0 && unhealthyFor >= time.Duration(policy.RecloneAfterMinutes)*time.Minute:
+			action = dbpreview.SelfHealActionReclone
+		case policy.RecreateAfterMinutes > 0 && unhealthyFor >= time.Duration(policy.RecreateAfterMinutes)*time.Minute && !remediated:
+			action = dbpreview.SelfHealActionRecreate
+		default:
+			continue
+		}
+		if candidate == nil || since.Before(candidate.unhealthySince) {
+			candidate = &selfHealCandidate{pod: pod, action: action, unhealthySince: since}
+		}
+	}
+	return candidate
+}
+
+// podNotReadySince reports whether pod is not ready and, if so, since when.
+func podNotReadySince(pod *corev1.Pod) (time.Time, bool) {
+	for _, condition := range pod.Status.Conditions {
+		if condition.Type != corev1.PodReady {
+			continue
+		}
+		if condition.Status == corev1.ConditionTrue {
+			return time.Time{}, false
+		}
+		return condition.LastTransitionTime.Time, true
+	}
+	return pod.CreationTimestamp.Time, true
+}
+
+// selfHealBlockedReason returns why no replica of cluster may be remediated
+// now, or "" when one may.
+func selfHealBlockedReason(documentdb *dbpreview.DocumentDB, cluster *cnpgv1.Cluster) string {
+	primary := cluster.Status.CurrentPrimary
+	switch {
+	case slices.Contains(selfHealBlockingPhases, cluster.Status.Phase):
+		return fmt.Sprintf("the CNPG Cluster is in phase %q", cluster.Status.Phase)
+	case primary == "" || !slices.Contains(cluster.Status.InstancesStatus[cnpgv1.PodHealthy], primary):
+		return "the primary is not healthy"
+	case documentdb.Spec.NodeMaintenanceWindow != nil && documentdb.Spec.NodeMaintenanceWindow.InProgress:
+		return "a node maintenance is in progress"
+	case len(documentdb.Status.InProgressOperations) > 0:
+		return fmt.Sprintf("operation %s is in progress", documentdb.Status.InProgressOperations[0].Type)
+	}
+	return ""
+}
+
+// instancePVCs returns the PVCs of the instance of cluster named instance.
+// Deleted PVCs are only removed once the Pod of the instance is gone.
+func (r *DocumentDBReconciler) instancePVCs(ctx context.Context, cluster *cnpgv1.Cluster, instance string) ([]corev1.PersistentVolumeClaim, error) {
+	pvcs := &corev1.PersistentVolumeClaimList{}
+	if err := r.List(ctx, pvcs, client.InNamespace(cluster.Namespace), client.MatchingLabels{
+		"cnpg.io/cluster":      cluster.Name,
+		"cnpg.io/instanceName": instance,
+	}); err != nil {
+		return nil, fmt.Errorf("failed to list the PVCs of instance %s: %w", instance, err)
+	}
+	return pvcs.Items, nil
+}
+
+// selfHealPending reports whether spec.selfHeal is set and a replica is not
+// healthy, so that it is remediated close to when it becomes due.
+func selfHealPending(documentdb *dbpreview.DocumentDB) bool {
+	if documentdb.Spec.SelfHeal == nil {
+		return false
+	}
+	return slices.ContainsFunc(documentdb.Status.Instances, func(instance dbpreview.InstanceStatus) bool {
+		return instance.Role == dbpreview.InstanceRoleReplica && !instance.Healthy
+	})
+}
@@ -0,0 +1,273 @@
+// Copyright (c) Microsoft Corporation.
+// Licensed under the MIT License.
+
+package controller
+
+import (
+	"context"
+	"time"
+
+	cnpgv1 "github.com/cloudnative-pg/cloudnative-pg/api/v1"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/client-go/tools/record"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	dbpreview "github.com/documentdb/documentdb-operator/api/preview"
+	util "github.com/documentdb/documentdb-operator/internal/utils"
+)
+
+var _ = Describe("Self-heal", func() {
+	const namespace = "default"
+
+	var (
+		ctx        context.Context
+		scheme     *runtime.Scheme
+		recorder   *record.FakeRecorder
+		cluster    *cnpgv1.Cluster
+		documentdb *dbpreview.DocumentDB
+	)
+
+	BeforeEach(func() {
+		ctx = context.Background()
+		scheme = runtime.NewScheme()
+		Expect(corev1.AddToScheme(scheme)).To(Succeed())
+		Expect(dbpreview.AddToScheme(scheme)).To(Succeed())
+		recorder = record.NewFakeRecorder(10)
+		cluster = &cnpgv1.Cluster{
+			ObjectMeta: metav1.ObjectMeta{Name: "db", Namespace: namespace},
+			Status: cnpgv1.ClusterStatus{
+				Phase:          cnpgv1.PhaseWaitingForInstancesToBeActive,
+				CurrentPrimary: "db-1",
+				InstancesStatus: map[cnpgv1.PodStatus][]string{
+					cnpgv1.PodHealthy: {"db-1"},
+					cnpgv1.PodFailed:  {"db-2"},
+				},
+			},
+		}
+		documentdb = &dbpreview.DocumentDB{
+			ObjectMeta: metav1.ObjectMeta{Name: "db", Namespace: namespace},
+			Spec: dbpreview.DocumentDBSpec{
+				SelfHeal: &dbpreview.SelfHealConfiguration{RecreateAfterMinutes: 10, RecloneAfterMinutes: 60},
+			},
+		}
+	})
+
+	instancePod := func(name string, notReadyFor time.Duration) *corev1.Pod {
+		ready := corev1.PodCondition{Type: corev1.PodReady, Status: corev1.ConditionTrue}
+		if notReadyFor > 0 {
+			ready = corev1.PodCondition{
+				Type:               corev1.PodReady,
+				Status:             corev1.ConditionFalse,
+				LastTransitionTime: metav1.NewTime(time.Now().Add(-notReadyFor)),
+			}
+		}
+		return &corev1.Pod{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      name,
+				Namespace: namespace,
+				Labels:    map[string]string{"cnpg.io/cluster": "db", "cnpg.io/podRole": "instance"},
+			},
+			Status: corev1.PodStatus{Conditions: []corev1.PodCondition{ready}},
+		}
+	}
+	instancePVC := func(name, instance string) *corev1.PersistentVolumeClaim {
+		return &corev1.PersistentVolumeClaim{ObjectMeta: metav1.ObjectMeta{
+			Name:      name,
+			Namespace: namespace,
+			Labels:    map[string]string{"cnpg.io/cluster": "db", "cnpg.io/instanceName": instance},
+		}}
+	}
+	newReconciler := func(objects ...client.Object) *DocumentDBReconciler {
+		return &DocumentDBReconciler{
+			Client: fake.NewClientBuilder().WithScheme(scheme).
+				WithObjects(append(objects, documentdb)...).
+				WithStatusSubresource(&dbpreview.DocumentDB{}).
+				Build(),
+			Scheme:   scheme,
+			Recorder: recorder,
+		}
+	}
+	exists := func(r *DocumentDBReconciler, obj client.Object) bool {
+		err := r.Get(ctx, client.ObjectKeyFromObject(obj), obj)
+		if apierrors.IsNotFound(err) {
+			return false
+		}
+		Expect(err).ToNot(HaveOccurred())
+		return true
+	}
+
+	It("recreates a replica that has not been ready for longer than recreateAfterMinutes", func() {
+		replica := instancePod("db-2", 15*time.Minute)
+		r := newReconciler(instancePod("db-1", 0), replica, instancePVC("db-2", "db-2"))
+
+		changed, err := r.reconcileSelfHeal(ctx, documentdb, cluster)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(changed).To(BeTrue())
+		Expect(exists(r, replica)).To(BeFalse())
+		Expect(exists(r, instancePVC("db-2", "db-2"))).To(BeTrue())
+		Expect(documentdb.Status.SelfHeal.Instance).To(Equal("db-2"))
+		Expect(documentdb.Status.SelfHeal.Action).To(Equal(dbpreview.SelfHealActionRecreate))
+		Expect(documentdb.Status.SelfHeal.UnhealthySince).ToNot(BeNil())
+		Expect(recorder.Events).To(Receive(ContainSubstring("Warning SelfHealRecreate Deleted the Pod of replica db-2")))
+	})
+
+	It("saves the remediation before returning", func() {
+		r := newReconciler(instancePod("db-1", 0), instancePod("db-2", 15*time.Minute))
+
+		_, err := r.reconcileSelfHeal(ctx, documentdb, cluster)
+		Expect(err).ToNot(HaveOccurred())
+		stored := &dbpreview.DocumentDB{}
+		Expect(r.Get(ctx, client.ObjectKeyFromObject(documentdb), stored)).To(Succeed())
+		Expect(stored.Status.SelfHeal).ToNot(BeNil())
+		Expect(stored.Status.SelfHeal.Instance).To(Equal("db-2"))
+	})
+
+	It("returns the error of saving the remediation", func() {
+		r := newReconciler(instancePod("db-1", 0), instancePod("db-2", 15*time.Minute))
+		Expect(r.Delete(ctx, documentdb.DeepCopy())).To(Succeed())
+
+		_, err := r.reconcileSelfHeal(ctx, documentdb, cluster)
+		Expect(err).To(MatchError(ContainSubstring("failed to save the self-heal remediation")))
+	})
+
+	It("reclones a recreated replica that is still not ready after recloneAfterMinutes", func() {
+		replica := instancePod("db-2", 5*time.Minute)
+		r := newReconciler(instancePod("db-1", 0), replica,
+			instancePVC("db-2", "db-2"), instancePVC("db-2-wal", "db-2"), instancePVC("db-3", "db-3"))
+		documentdb.Status.SelfHeal = &dbpreview.SelfHealStatus{
+			Instance:       "db-2",
+			Action:         dbpreview.SelfHealActionRecreate,
+			Time:           metav1.NewTime(time.Now().Add(-50 * time.Minute)),
+			UnhealthySince: &metav1.Time{Time: time.Now().Add(-61 * time.Minute)},
+		}
+
+		changed, err := r.reconcileSelfHeal(ctx, documentdb, cluster)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(changed).To(BeTrue())
+		Expect(exists(r, replica)).To(BeFalse())
+		Expect(exists(r, instancePVC("db-2", "db-2"))).To(BeFalse())
+		Expect(exists(r, instancePVC("db-2-wal", "db-2"))).To(BeFalse())
+		Expect(exists(r, instancePVC("db-3", "db-3"))).To(BeTrue())
+		Expect(documentdb.Status.SelfHeal.Action).To(Equal(dbpreview.SelfHealActionReclone))
+		Expect(recorder.Events).To(Receive(ContainSubstring("Warning SelfHealReclone")))
+	})
+
+	It("does not reclone a replica whose PVC is under retention hold", func() {
+		replica := instancePod("db-2", 2*time.Hour)
+		held := instancePVC("db-2", "db-2")
+		held.Annotations = map[string]string{util.RETENTION_HOLD_ANNOTATION: "true"}
+		r := newReconciler(instancePod("db-1", 0), replica, held)
+
+		changed, err := r.reconcileSelfHeal(ctx, documentdb, cluster)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(changed).To(BeFalse())
+		Expect(exists(r, replica)).To(BeTrue())
+		Expect(exists(r, instancePVC("db-2", "db-2"))).To(BeTrue())
+		Expect(recorder.Events).To(Receive(ContainSubstring("SelfHealSkipped Not recloning replica db-2: PVC db-2 is under retention hold")))
+	})
+
+	It("does not recreate the same replica twice", func() {
+		replica := instancePod("db-2", 15*time.Minute)
+		r := newReconciler(instancePod("db-1", 0), replica)
+		documentdb.Spec.SelfHeal.RecloneAfterMinutes = 0
+		documentdb.Status.SelfHeal = &dbpreview.SelfHealStatus{
+			Instance:       "db-2",
+			Action:         dbpreview.SelfHealActionRecreate,
+			Time:           metav1.NewTime(time.Now().Add(-2 * time.Hour)),
+			UnhealthySince: &metav1.Time{Time: time.Now().Add(-3 * time.Hour)},
+		}
+
+		changed, err := r.reconcileSelfHeal(ctx, documentdb, cluster)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(changed).To(BeFalse())
+		Expect(exists(r, replica)).To(BeTrue())
+	})
+
+	It("never remediates the primary", func() {
+		primary := instancePod("db-1", time.Hour)
+		r := newReconciler(primary)
+		cluster.Status.InstancesStatus[cnpgv1.PodHealthy] = []string{"db-1"}
+
+		changed, err := r.reconcileSelfHeal(ctx, documentdb, cluster)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(changed).To(BeFalse())
+		Expect(exists(r, primary)).To(BeTrue())
+	})
+
+	It("skips the remediation while the primary is not healthy", func() {
+		replica := instancePod("db-2", time.Hour)
+		r := newReconciler(replica)
+		cluster.Status.InstancesStatus[cnpgv1.PodHealthy] = nil
+
+		changed, err := r.reconcileSelfHeal(ctx, documentdb, cluster)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(changed).To(BeFalse())
+		Expect(exists(r, replica)).To(BeTrue())
+		Expect(recorder.Events).To(Receive(ContainSubstring("SelfHealSkipped Not remediating replica db-2: the primary is not healthy")))
+	})
+
+	It("skips the remediation while CNPG is switching over", func() {
+		replica := instancePod("db-2", time.Hour)
+		r := newReconciler(replica)
+		cluster.Status.Phase = cnpgv1.PhaseSwitchover
+
+		_, err := r.reconcileSelfHeal(ctx, documentdb, cluster)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(exists(r, replica)).To(BeTrue())
+	})
+
+	It("skips the remediation during a node maintenance", func() {
+		replica := instancePod("db-2", time.Hour)
+		r := newReconciler(replica)
+		documentdb.Spec.NodeMaintenanceWindow = &cnpgv1.NodeMaintenanceWindow{InProgress: true}
+
+		changed, err := r.reconcileSelfHeal(ctx, documentdb, cluster)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(changed).To(BeFalse())
+		Expect(exists(r, replica)).To(BeTrue())
+		Expect(recorder.Events).To(Receive(ContainSubstring("a node maintenance is in progress")))
+	})
+
+	It("waits for the cooldown after the last remediation", func() {
+		replica := instancePod("db-3", 15*time.Minute)
+		r := newReconciler(replica)
+		documentdb.Status.SelfHeal = &dbpreview.SelfHealStatus{
+			Instance: "db-2",
+			Action:   dbpreview.SelfHealActionRecreate,
+			Time:     metav1.NewTime(time.Now().Add(-10 * time.Minute)),
+		}
+
+		_, err := r.reconcileSelfHeal(ctx, documentdb, cluster)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(exists(r, replica)).To(BeTrue())
+
+		documentdb.Spec.SelfHeal.CooldownMinutes = 5
+		changed, err := r.reconcileSelfHeal(ctx, documentdb, cluster)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(changed).To(BeTrue())
+		Expect(exists(r, replica)).To(BeFalse())
+	})
+
+	It("clears the unhealthy time once the remediated replica is healthy", func() {
+		r := newReconciler()
+		documentdb.Spec.SelfHeal = nil
+		documentdb.Status.SelfHeal = &dbpreview.SelfHealStatus{
+			Instance:       "db-1",
+			Action:         dbpreview.SelfHealActionRecreate,
+			Time:           metav1.Now(),
+			UnhealthySince: &metav1.Time{Time: time.Now().Add(-time.Hour)},
+		}
+
+		changed, err := r.reconcileSelfHeal(ctx, documentdb, cluster)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(changed).To(BeTrue())
+		Expect(documentdb.Status.SelfHeal.UnhealthySince).To(BeNil())
+		Expect(documentdb.Status.SelfHeal.Instance).To(Equal("db-1"))
+	})
+})
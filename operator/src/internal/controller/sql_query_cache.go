@@ -0,0 +1,106 @@
+// Copyright (c) Microsoft Corporation.
+// Licensed under the MIT License.
+
+package controller
+
+import (
+	"context"
+	"strings"
+	"sync"
+	"time"
+
+	cnpgv1 "github.com/cloudnative-pg/cloudnative-pg/api/v1"
+)
+
+// sqlQueryCacheTTL is how long the output of a read-only query is reused. The
+// reconciles triggered by a burst of status changes then share one exec.
+const sqlQueryCacheTTL = 10 * time.Second
+
+// sqlQueryCache keeps the output of read-only queries run on the primary of
+// a CNPG cluster. Its entries are keyed by the cluster and its primary, so a
+// failover starts afresh. A nil cache keeps nothing.
+type sqlQueryCache struct {
+	mu      sync.Mutex
+	entries map[string]sqlQueryCacheEntry
+	// now defaults to time.Now.
+	now func() time.Time
+}
+
+type sqlQueryCacheEntry struct {
+	output  string
+	expires time.Time
+}
+
+func newSQLQueryCache() *sqlQueryCache {
+	return &sqlQueryCache{entries: map[string]sqlQueryCacheEntry{}, now: time.Now}
+}
+
+func sqlQueryCachePrefix(cluster *cnpgv1.Cluster) string {
+	return cluster.Namespace + "/" + cluster.Name + "/"
+}
+
+func sqlQueryCacheKey(cluster *cnpgv1.Cluster, query string) string {
+	return sqlQueryCachePrefix(cluster) + cluster.Status.CurrentPrimary + "\x00" + query
+}
+
+func (c *sqlQueryCache) get(key string) (string, bool) {
+	if c == nil {
+		return "", false
+	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	entry, found := c.entries[key]
+	if !found || !c.now().Before(entry.expires) {
+		return "", false
+	}
+	return entry.output, true
+}
+
+// put keeps output under key, and drops the entries that expired.
+func (c *sqlQueryCache) put(key, output string) {
+	if c == nil {
+		return
+	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	now := c.now()
+	for existing, entry := range c.entries {
+		if !now.Before(entry.expires) {
+			delete(c.entries, existing)
+		}
+	}
+	c.entries[key] = sqlQueryCacheEntry{output: output, expires: now.Add(sqlQueryCacheTTL)}
+}
+
+// invalidate drops the entries of cluster, after a statement that changes
+// what its queries return.
+func (c *sqlQueryCache) invalidate(cluster *cnpgv1.Cluster) {
+	if c == nil {
+		return
+	}
+	prefix := sqlQueryCachePrefix(cluster)
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	for key := range c.entries {
+		if strings.HasPrefix(key, prefix) {
+			delete(c.entries, key)
+		}
+	}
+}
+
+// querySQL runs the read-only query on the primary of cluster through
+// SQLExecutor, reusing its output for sqlQueryCacheTTL. Statements that write
+// must go through SQLExecutor, followed by queryCache.invalidate when they
+// change the output of a cached query.
+func (r *DocumentDBReconciler) querySQL(ctx context.Context, cluster *cnpgv1.Cluster, query string) (string, error) {
+	key := sqlQueryCacheKey(cluster, query)
+	if output, found := r.queryCache.get(key); found {
+		return output, nil
+	}
+	output, err := r.SQLExecutor(ctx, cluster, query)
+	if err != nil {
+		return output, err
+	}
+	r.queryCache.put(key, output)
+	return output, nil
+}
@@ -0,0 +1,73 @@
+// Copyright (c) Microsoft Corporation.
+// Licensed under the MIT License.
+
+package controller
+
+import (
+	"context"
+	"time"
+
+	cnpgv1 "github.com/cloudnative-pg/cloudnative-pg/api/v1"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+var _ = Describe("querySQL", func() {
+	var (
+		ctx        context.Context
+		now        time.Time
+		queries    int
+		cluster    *cnpgv1.Cluster
+		reconciler *DocumentDBReconciler
+	)
+
+	BeforeEach(func() {
+		ctx = context.Background()
+		now = time.Now()
+		queries = 0
+		cluster = &cnpgv1.Cluster{
+			ObjectMeta: metav1.ObjectMeta{Name: "db", Namespace: "documentdb-ns"},
+			Status:     cnpgv1.ClusterStatus{CurrentPrimary: "db-1"},
+		}
+		cache := newSQLQueryCache()
+		cache.now = func() time.Time { return now }
+		reconciler = &DocumentDBReconciler{
+			queryCache: cache,
+			SQLExecutor: func(context.Context, *cnpgv1.Cluster, string) (string, error) {
+				queries++
+				return "(1 row)", nil
+			},
+		}
+	})
+
+	It("reuses the output of a query until it expires", func() {
+		for range 3 {
+			Expect(reconciler.querySQL(ctx, cluster, "SELECT 1")).To(Equal("(1 row)"))
+		}
+		Expect(queries).To(Equal(1))
+
+		now = now.Add(sqlQueryCacheTTL)
+		Expect(reconciler.querySQL(ctx, cluster, "SELECT 1")).To(Equal("(1 row)"))
+		Expect(queries).To(Equal(2))
+	})
+
+	It("runs the query again on another primary or after an invalidation", func() {
+		Expect(reconciler.querySQL(ctx, cluster, "SELECT 1")).To(Equal("(1 row)"))
+		cluster.Status.CurrentPrimary = "db-2"
+		Expect(reconciler.querySQL(ctx, cluster, "SELECT 1")).To(Equal("(1 row)"))
+		Expect(queries).To(Equal(2))
+
+		reconciler.queryCache.invalidate(cluster)
+		Expect(reconciler.querySQL(ctx, cluster, "SELECT 1")).To(Equal("(1 row)"))
+		Expect(queries).To(Equal(3))
+	})
+
+	It("runs every query without a cache", func() {
+		reconciler.queryCache = nil
+		for range 2 {
+			Expect(reconciler.querySQL(ctx, cluster, "SELECT 1")).To(Equal("(1 row)"))
+		}
+		Expect(queries).To(Equal(2))
+	})
+})
@@ -0,0 +1,145 @@
+// Copyright (c) Microsoft Corporation.
+// Licensed under the MIT License.
+
+package controller
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"strings"
+
+	cnpgv1 "github.com/cloudnative-pg/cloudnative-pg/api/v1"
+	corev1 "k8s.io/api/core/v1"
+	storagev1 "k8s.io/api/storage/v1"
+	"k8s.io/apimachinery/pkg/api/equality"
+	"k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/resource"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	dbpreview "github.com/documentdb/documentdb-operator/api/preview"
+)
+
+// reconcileStorageExpansion reports in status.storageExpansion the data PVCs
+// of cluster that are smaller than its storage size, and how their expansion
+// progresses. It records an event when a PVC fails to expand and once every
+// PVC is expanded, and returns whether status.storageExpansion changed.
+func (r *DocumentDBReconciler) reconcileStorageExpansion(ctx context.Context, documentdb *dbpreview.DocumentDB, cluster *cnpgv1.Cluster) (bool, error) {
+	size := cluster.Spec.StorageConfiguration.Size
+	desired, err := resource.ParseQuantity(size)
+	if err != nil {
+		return false, nil
+	}
+
+	pvcs := &corev1.PersistentVolumeClaimList{}
+	if err := r.List(ctx, pvcs, client.InNamespace(cluster.Namespace), client.MatchingLabels{
+		"cnpg.io/cluster": cluster.Name,
+		"cnpg.io/pvcRole": "PG_DATA",
+	}); err != nil {
+		return false, fmt.Errorf("failed to list data PVCs: %w", err)
+	}
+	slices.SortFunc(pvcs.Items, func(a, b corev1.PersistentVolumeClaim) int { return strings.Compare(a.Name, b.Name) })
+
+	var status *dbpreview.StorageExpansionStatus
+	for i := range pvcs.Items {
+		pvc := &pvcs.Items[i]
+		capacity := pvc.Status.Capacity[corev1.ResourceStorage]
+		if pvc.Status.Phase != corev1.ClaimBound || pvc.DeletionTimestamp != nil || capacity.Cmp(desired) >= 0 {
+			continue
+		}
+		phase, message, err := r.pvcExpansionPhase(ctx, pvc, desired)
+		if err != nil {
+			return false, err
+		}
+		if status == nil {
+			status = &dbpreview.StorageExpansionStatus{Size: size}
+		}
+		status.PVCs = append(status.PVCs, dbpreview.PVCExpansionStatus{
+			Name:     pvc.Name,
+			Capacity: capacity.String(),
+			Phase:    phase,
+			Message:  message,
+		})
+	}
+
+	previous := documentdb.Status.StorageExpansion
+	if equality.Semantic.DeepEqual(previous, status) {
+		return false, nil
+	}
+	if r.Recorder != nil {
+		failedBefore := pvcExpansionFailures(previous)
+		for _, pvc := range pvcExpansionFailures(status) {
+			if !slices.ContainsFunc(failedBefore, func(before dbpreview.PVCExpansionStatus) bool { return before.Name == pvc.Name }) {
+				r.Recorder.Event(documentdb, corev1.EventTypeWarning, "StorageExpansionFailed", fmt.Sprintf(
+					"PVC %s cannot be expanded to %s: %s", pvc.Name, size, pvc.Message))
+			}
+		}
+		if status == nil && previous != nil {
+			r.Recorder.Event(documentdb, corev1.EventTypeNormal, "StorageExpanded",
+				fmt.Sprintf("Every PVC is expanded to %s", previous.Size))
+		}
+	}
+	documentdb.Status.StorageExpansion = status
+	return true, nil
+}
+
+// pvcExpansionPhase returns the phase of the expansion of pvc to size, from
+// its conditions, and why it failed or what it waits for.
+func (r *DocumentDBReconciler) pvcExpansionPhase(ctx context.Context, pvc *corev1.PersistentVolumeClaim, size resource.Quantity) (dbpreview.PVCExpansionPhase, string, error) {
+	switch resizeStatus := pvc.Status.AllocatedResourceStatuses[corev1.ResourceStorage]; resizeStatus {
+	case corev1.PersistentVolumeClaimControllerResizeInfeasible, corev1.PersistentVolumeClaimNodeResizeInfeasible:
+		return dbpreview.PVCExpansionPhaseFailed, fmt.Sprintf("the storage provider cannot expand the volume (%s)", resizeStatus), nil
+	}
+	for _, condition := range pvc.Status.Conditions {
+		if condition.Status != corev1.ConditionTrue {
+			continue
+		}
+		switch condition.Type {
+		case corev1.PersistentVolumeClaimControllerResizeError, corev1.PersistentVolumeClaimNodeResizeError:
+			return dbpreview.PVCExpansionPhaseFailed, condition.Message, nil
+		case corev1.PersistentVolumeClaimFileSystemResizePending:
+			return dbpreview.PVCExpansionPhaseFileSystemResizePending, condition.Message, nil
+		case corev1.PersistentVolumeClaimResizing:
+			return dbpreview.PVCExpansionPhaseResizing, condition.Message, nil
+		}
+	}
+
+	requested := pvc.Spec.Resources.Requests[corev1.ResourceStorage]
+	if requested.Cmp(size) >= 0 {
+		return dbpreview.PVCExpansionPhaseResizing, "", nil
+	}
+	// The API server rejects the new size CNPG requests when the
+	// StorageClass does not allow volume expansion
+	if name := pvc.Spec.StorageClassName; name != nil && *name != "" {
+		storageClass := &storagev1.StorageClass{}
+		if err := r.Get(ctx, types.NamespacedName{Name: *name}, storageClass); err != nil {
+			if !errors.IsNotFound(err) {
+				return "", "", fmt.Errorf("failed to get StorageClass %s: %w", *name, err)
+			}
+		} else if storageClass.AllowVolumeExpansion == nil || !*storageClass.AllowVolumeExpansion {
+			return dbpreview.PVCExpansionPhaseFailed, fmt.Sprintf("StorageClass %s does not allow volume expansion", *name), nil
+		}
+	}
+	return dbpreview.PVCExpansionPhasePending, "waiting for CNPG to request the new size", nil
+}
+
+// pvcExpansionFailures returns the PVCs of status that failed to expand.
+func pvcExpansionFailures(status *dbpreview.StorageExpansionStatus) []dbpreview.PVCExpansionStatus {
+	if status == nil {
+		return nil
+	}
+	var failures []dbpreview.PVCExpansionStatus
+	for _, pvc := range status.PVCs {
+		if pvc.Phase == dbpreview.PVCExpansionPhaseFailed {
+			failures = append(failures, pvc)
+		}
+	}
+	return failures
+}
+
+// storageExpansionPending reports whether PVCs of documentdb are being
+// expanded, so that their progress is followed.
+func storageExpansionPending(documentdb *dbpreview.DocumentDB) bool {
+	return documentdb.Status.StorageExpansion != nil
+}
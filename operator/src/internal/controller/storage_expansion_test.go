@@ -0,0 +1,155 @@
+// Copyright (c) Microsoft Corporation.
+// Licensed under the MIT License.
+
+package controller
+
+import (
+	"context"
+
+	cnpgv1 "github.com/cloudnative-pg/cloudnative-pg/api/v1"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	corev1 "k8s.io/api/core/v1"
+	storagev1 "k8s.io/api/storage/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/client-go/tools/record"
+	"k8s.io/utils/ptr"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	dbpreview "github.com/documentdb/documentdb-operator/api/preview"
+)
+
+var _ = Describe("Storage expansion", func() {
+	const namespace = "default"
+
+	var (
+		ctx        context.Context
+		scheme     *runtime.Scheme
+		recorder   *record.FakeRecorder
+		cluster    *cnpgv1.Cluster
+		documentdb *dbpreview.DocumentDB
+	)
+
+	BeforeEach(func() {
+		ctx = context.Background()
+		scheme = runtime.NewScheme()
+		Expect(corev1.AddToScheme(scheme)).To(Succeed())
+		Expect(storagev1.AddToScheme(scheme)).To(Succeed())
+		recorder = record.NewFakeRecorder(10)
+		cluster = &cnpgv1.Cluster{
+			ObjectMeta: metav1.ObjectMeta{Name: "db", Namespace: namespace},
+			Spec:       cnpgv1.ClusterSpec{StorageConfiguration: cnpgv1.StorageConfiguration{Size: "20Gi"}},
+		}
+		documentdb = &dbpreview.DocumentDB{ObjectMeta: metav1.ObjectMeta{Name: "db", Namespace: namespace}}
+	})
+
+	dataPVC := func(name, requested, capacity string, conditions ...corev1.PersistentVolumeClaimCondition) *corev1.PersistentVolumeClaim {
+		return &corev1.PersistentVolumeClaim{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      name,
+				Namespace: namespace,
+				Labels:    map[string]string{"cnpg.io/cluster": "db", "cnpg.io/pvcRole": "PG_DATA"},
+			},
+			Spec: corev1.PersistentVolumeClaimSpec{
+				StorageClassName: ptr.To("standard"),
+				Resources: corev1.VolumeResourceRequirements{
+					Requests: corev1.ResourceList{corev1.ResourceStorage: resource.MustParse(requested)},
+				},
+			},
+			Status: corev1.PersistentVolumeClaimStatus{
+				Phase:      corev1.ClaimBound,
+				Capacity:   corev1.ResourceList{corev1.ResourceStorage: resource.MustParse(capacity)},
+				Conditions: conditions,
+			},
+		}
+	}
+	storageClass := func(allowExpansion bool) *storagev1.StorageClass {
+		return &storagev1.StorageClass{
+			ObjectMeta:           metav1.ObjectMeta{Name: "standard"},
+			AllowVolumeExpansion: ptr.To(allowExpansion),
+		}
+	}
+	newReconciler := func(objects ...client.Object) *DocumentDBReconciler {
+		return &DocumentDBReconciler{
+			Client:   fake.NewClientBuilder().WithScheme(scheme).WithObjects(objects...).Build(),
+			Scheme:   scheme,
+			Recorder: recorder,
+		}
+	}
+
+	It("reports the phase of each PVC smaller than the storage size", func() {
+		r := newReconciler(storageClass(true),
+			dataPVC("db-1", "20Gi", "20Gi"),
+			dataPVC("db-2", "20Gi", "10Gi", corev1.PersistentVolumeClaimCondition{
+				Type:    corev1.PersistentVolumeClaimFileSystemResizePending,
+				Status:  corev1.ConditionTrue,
+				Message: "Waiting for user to (re-)start a pod to finish file system resize of volume on node.",
+			}),
+			dataPVC("db-3", "20Gi", "10Gi", corev1.PersistentVolumeClaimCondition{
+				Type:   corev1.PersistentVolumeClaimResizing,
+				Status: corev1.ConditionTrue,
+			}),
+			dataPVC("db-4", "10Gi", "10Gi"),
+		)
+
+		changed, err := r.reconcileStorageExpansion(ctx, documentdb, cluster)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(changed).To(BeTrue())
+		status := documentdb.Status.StorageExpansion
+		Expect(status.Size).To(Equal("20Gi"))
+		Expect(status.PVCs).To(HaveLen(3))
+		Expect(status.PVCs[0].Name).To(Equal("db-2"))
+		Expect(status.PVCs[0].Phase).To(Equal(dbpreview.PVCExpansionPhaseFileSystemResizePending))
+		Expect(status.PVCs[0].Capacity).To(Equal("10Gi"))
+		Expect(status.PVCs[1].Phase).To(Equal(dbpreview.PVCExpansionPhaseResizing))
+		Expect(status.PVCs[2].Phase).To(Equal(dbpreview.PVCExpansionPhasePending))
+		Expect(storageExpansionPending(documentdb)).To(BeTrue())
+	})
+
+	It("reports a StorageClass that does not allow volume expansion once", func() {
+		r := newReconciler(storageClass(false), dataPVC("db-1", "10Gi", "10Gi"))
+
+		_, err := r.reconcileStorageExpansion(ctx, documentdb, cluster)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(documentdb.Status.StorageExpansion.PVCs[0].Phase).To(Equal(dbpreview.PVCExpansionPhaseFailed))
+		Expect(documentdb.Status.StorageExpansion.PVCs[0].Message).To(Equal("StorageClass standard does not allow volume expansion"))
+		Expect(recorder.Events).To(Receive(ContainSubstring(
+			"Warning StorageExpansionFailed PVC db-1 cannot be expanded to 20Gi: StorageClass standard does not allow volume expansion")))
+
+		changed, err := r.reconcileStorageExpansion(ctx, documentdb, cluster)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(changed).To(BeFalse())
+		Expect(recorder.Events).ToNot(Receive())
+	})
+
+	It("reports resize errors of the storage provider", func() {
+		pvc := dataPVC("db-1", "20Gi", "10Gi", corev1.PersistentVolumeClaimCondition{
+			Type:    corev1.PersistentVolumeClaimControllerResizeError,
+			Status:  corev1.ConditionTrue,
+			Message: "quota exceeded",
+		})
+		r := newReconciler(storageClass(true), pvc)
+
+		_, err := r.reconcileStorageExpansion(ctx, documentdb, cluster)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(documentdb.Status.StorageExpansion.PVCs[0].Phase).To(Equal(dbpreview.PVCExpansionPhaseFailed))
+		Expect(documentdb.Status.StorageExpansion.PVCs[0].Message).To(Equal("quota exceeded"))
+	})
+
+	It("clears the status once every PVC is expanded", func() {
+		r := newReconciler(storageClass(true), dataPVC("db-1", "20Gi", "20Gi"))
+		documentdb.Status.StorageExpansion = &dbpreview.StorageExpansionStatus{
+			Size: "20Gi",
+			PVCs: []dbpreview.PVCExpansionStatus{{Name: "db-1", Capacity: "10Gi", Phase: dbpreview.PVCExpansionPhaseResizing}},
+		}
+
+		changed, err := r.reconcileStorageExpansion(ctx, documentdb, cluster)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(changed).To(BeTrue())
+		Expect(documentdb.Status.StorageExpansion).To(BeNil())
+		Expect(recorder.Events).To(Receive(ContainSubstring("Normal StorageExpanded Every PVC is expanded to 20Gi")))
+	})
+})
@@ -0,0 +1,47 @@
+// Copyright (c) Microsoft Corporation.
+// Licensed under the MIT License.
+
+package controller
+
+import (
+	"context"
+	"fmt"
+
+	cnpgv1 "github.com/cloudnative-pg/cloudnative-pg/api/v1"
+
+	dbpreview "github.com/documentdb/documentdb-operator/api/preview"
+)
+
+// ttlJobName is the pg_cron job the DocumentDB extension schedules to delete
+// the documents expired by TTL indexes.
+const ttlJobName = "documentdb_ttl_task"
+
+// ttlSchedule returns the pg_cron schedule of spec.ttl.intervalSeconds, or ""
+// when it is not set.
+func ttlSchedule(config *dbpreview.TTLConfiguration) string {
+	if config == nil || config.IntervalSeconds == 0 {
+		return ""
+	}
+	if config.IntervalSeconds == 60 {
+		return "* * * * *"
+	}
+	return fmt.Sprintf("%d seconds", config.IntervalSeconds)
+}
+
+// reconcileTTLSchedule reschedules the TTL task of the extension on the
+// primary to spec.ttl.intervalSeconds. The statement only alters the job
+// when its schedule differs.
+func (r *DocumentDBReconciler) reconcileTTLSchedule(ctx context.Context, documentdb *dbpreview.DocumentDB, cnpgCluster *cnpgv1.Cluster) error {
+	schedule := ttlSchedule(documentdb.Spec.TTL)
+	if schedule == "" {
+		return nil
+	}
+	// The schedule is built from an integer, so it needs no quoting.
+	alterCommand := fmt.Sprintf(
+		"SELECT cron.alter_job(jobid, schedule := '%[1]s') FROM cron.job WHERE jobname = '%[2]s' AND schedule <> '%[1]s';",
+		schedule, ttlJobName)
+	if _, err := r.SQLExecutor(ctx, cnpgCluster, alterCommand); err != nil {
+		return fmt.Errorf("failed to reschedule the TTL task: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,66 @@
+// Copyright (c) Microsoft Corporation.
+// Licensed under the MIT License.
+
+package controller
+
+import (
+	"context"
+	"fmt"
+
+	cnpgv1 "github.com/cloudnative-pg/cloudnative-pg/api/v1"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	dbpreview "github.com/documentdb/documentdb-operator/api/preview"
+)
+
+var _ = Describe("TTL schedule", func() {
+	var (
+		ctx        context.Context
+		reconciler *DocumentDBReconciler
+		documentdb *dbpreview.DocumentDB
+		executed   []string
+		sqlErr     error
+	)
+
+	BeforeEach(func() {
+		ctx = context.Background()
+		executed = nil
+		sqlErr = nil
+		documentdb = &dbpreview.DocumentDB{}
+		reconciler = &DocumentDBReconciler{
+			SQLExecutor: func(_ context.Context, _ *cnpgv1.Cluster, sql string) (string, error) {
+				executed = append(executed, sql)
+				return "", sqlErr
+			},
+		}
+	})
+
+	DescribeTable("builds the pg_cron schedule",
+		func(config *dbpreview.TTLConfiguration, expected string) {
+			Expect(ttlSchedule(config)).To(Equal(expected))
+		},
+		Entry("without spec.ttl", nil, ""),
+		Entry("without an interval", &dbpreview.TTLConfiguration{BatchSize: 100}, ""),
+		Entry("every few seconds", &dbpreview.TTLConfiguration{IntervalSeconds: 10}, "10 seconds"),
+		Entry("every minute", &dbpreview.TTLConfiguration{IntervalSeconds: 60}, "* * * * *"),
+	)
+
+	It("leaves the task alone without an interval", func() {
+		Expect(reconciler.reconcileTTLSchedule(ctx, documentdb, &cnpgv1.Cluster{})).To(Succeed())
+		Expect(executed).To(BeEmpty())
+	})
+
+	It("reschedules the task when its schedule differs", func() {
+		documentdb.Spec.TTL = &dbpreview.TTLConfiguration{IntervalSeconds: 15}
+		Expect(reconciler.reconcileTTLSchedule(ctx, documentdb, &cnpgv1.Cluster{})).To(Succeed())
+		Expect(executed).To(ConsistOf(
+			"SELECT cron.alter_job(jobid, schedule := '15 seconds') FROM cron.job WHERE jobname = 'documentdb_ttl_task' AND schedule <> '15 seconds';"))
+	})
+
+	It("returns the SQL error", func() {
+		documentdb.Spec.TTL = &dbpreview.TTLConfiguration{IntervalSeconds: 15}
+		sqlErr = fmt.Errorf("connection refused")
+		Expect(reconciler.reconcileTTLSchedule(ctx, documentdb, &cnpgv1.Cluster{})).To(MatchError(ContainSubstring("connection refused")))
+	})
+})
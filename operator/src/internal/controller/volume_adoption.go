@@ -0,0 +1,109 @@
+// Copyright (c) Microsoft Corporation.
+// Licensed under the MIT License.
+
+package controller
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"strings"
+
+	cnpgv1 "github.com/cloudnative-pg/cloudnative-pg/api/v1"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/log"
+
+	dbpreview "github.com/documentdb/documentdb-operator/api/preview"
+	util "github.com/documentdb/documentdb-operator/internal/utils"
+)
+
+// reconcileVolumeAdoption picks, for a DocumentDB of
+// spec.bootstrap.adoptRetainedVolumes whose CNPG Cluster does not exist yet,
+// the retained PV it is recovered from, and records it in
+// status.adoptedVolume. The recovery itself then follows reconcilePVRecovery.
+// It reports whether status.adoptedVolume changed.
+func (r *DocumentDBReconciler) reconcileVolumeAdoption(ctx context.Context, documentdb *dbpreview.DocumentDB, cnpgClusterName string, isPrimary bool) (bool, error) {
+	if documentdb.Spec.Bootstrap == nil || !documentdb.Spec.Bootstrap.AdoptRetainedVolumes || !isPrimary ||
+		documentdb.Status.AdoptedVolume != "" || documentdb.Status.Bootstrap != nil {
+		return false, nil
+	}
+
+	cnpgCluster := &cnpgv1.Cluster{}
+	if err := r.Get(ctx, types.NamespacedName{Name: cnpgClusterName, Namespace: documentdb.Namespace}, cnpgCluster); err == nil {
+		return false, nil
+	} else if !errors.IsNotFound(err) {
+		return false, fmt.Errorf("failed to get CNPG cluster: %w", err)
+	}
+
+	pvs := &corev1.PersistentVolumeList{}
+	if err := r.List(ctx, pvs, client.MatchingLabels{
+		util.LabelCluster:   documentdb.Name,
+		util.LabelNamespace: documentdb.Namespace,
+	}); err != nil {
+		return false, fmt.Errorf("failed to list retained PersistentVolumes: %w", err)
+	}
+	pv := adoptableVolume(pvs.Items)
+	if pv == nil {
+		log.FromContext(ctx).Info("No retained PersistentVolume to adopt, initializing an empty cluster")
+		if r.Recorder != nil {
+			r.Recorder.Event(documentdb, corev1.EventTypeNormal, "NoVolumeAdopted", fmt.Sprintf(
+				"No Released or Available PersistentVolume is labeled for %s/%s; initializing an empty cluster",
+				documentdb.Namespace, documentdb.Name))
+		}
+		return false, nil
+	}
+
+	log.FromContext(ctx).Info("Adopting retained PersistentVolume", "pv", pv.Name, "role", pv.Labels[util.LabelInstanceRole])
+	documentdb.Status.AdoptedVolume = pv.Name
+	if r.Recorder != nil {
+		r.Recorder.Event(documentdb, corev1.EventTypeNormal, "VolumeAdopted",
+			fmt.Sprintf("Recovering from retained PersistentVolume %s", pv.Name))
+	}
+	return true, nil
+}
+
+// adoptableVolume returns the PV among pvs a new cluster is recovered from:
+// a Released or Available data volume, preferably of the last primary, then
+// the most recent. WAL and tablespace volumes cannot be recovered from on
+// their own.
+func adoptableVolume(pvs []corev1.PersistentVolume) *corev1.PersistentVolume {
+	var candidates []*corev1.PersistentVolume
+	for i := range pvs {
+		pv := &pvs[i]
+		if !util.IsPVAvailableForRecovery(pv) || pv.DeletionTimestamp != nil {
+			continue
+		}
+		if claim := formerClaim(pv); strings.HasSuffix(claim, "-wal") || strings.Contains(claim, "-tbs-") {
+			continue
+		}
+		candidates = append(candidates, pv)
+	}
+	if len(candidates) == 0 {
+		return nil
+	}
+	slices.SortFunc(candidates, func(a, b *corev1.PersistentVolume) int {
+		aPrimary, bPrimary := a.Labels[util.LabelInstanceRole] == "primary", b.Labels[util.LabelInstanceRole] == "primary"
+		switch {
+		case aPrimary != bPrimary && aPrimary:
+			return -1
+		case aPrimary != bPrimary:
+			return 1
+		}
+		if c := b.CreationTimestamp.Compare(a.CreationTimestamp.Time); c != 0 {
+			return c
+		}
+		return strings.Compare(a.Name, b.Name)
+	})
+	return candidates[0]
+}
+
+// formerClaim returns the name of the PVC pv was last bound to, if known.
+func formerClaim(pv *corev1.PersistentVolume) string {
+	if pv.Spec.ClaimRef == nil {
+		return ""
+	}
+	return pv.Spec.ClaimRef.Name
+}
@@ -0,0 +1,134 @@
+// Copyright (c) Microsoft Corporation.
+// Licensed under the MIT License.
+
+package controller
+
+import (
+	"context"
+	"time"
+
+	cnpgv1 "github.com/cloudnative-pg/cloudnative-pg/api/v1"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/client-go/tools/record"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	dbpreview "github.com/documentdb/documentdb-operator/api/preview"
+	util "github.com/documentdb/documentdb-operator/internal/utils"
+)
+
+var _ = Describe("Volume adoption", func() {
+	const namespace = "default"
+
+	var (
+		ctx        context.Context
+		scheme     *runtime.Scheme
+		recorder   *record.FakeRecorder
+		documentdb *dbpreview.DocumentDB
+	)
+
+	BeforeEach(func() {
+		ctx = context.Background()
+		scheme = runtime.NewScheme()
+		Expect(corev1.AddToScheme(scheme)).To(Succeed())
+		Expect(cnpgv1.AddToScheme(scheme)).To(Succeed())
+		recorder = record.NewFakeRecorder(10)
+		documentdb = &dbpreview.DocumentDB{
+			ObjectMeta: metav1.ObjectMeta{Name: "db", Namespace: namespace},
+			Spec: dbpreview.DocumentDBSpec{
+				Bootstrap: &dbpreview.BootstrapConfiguration{AdoptRetainedVolumes: true},
+			},
+		}
+	})
+
+	retainedPV := func(name, claim, role string, phase corev1.PersistentVolumePhase, age time.Duration) *corev1.PersistentVolume {
+		pv := &corev1.PersistentVolume{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:              name,
+				CreationTimestamp: metav1.NewTime(time.Now().Add(-age)),
+				Labels:            map[string]string{util.LabelCluster: "db", util.LabelNamespace: namespace},
+			},
+			Spec: corev1.PersistentVolumeSpec{
+				ClaimRef: &corev1.ObjectReference{Name: claim, Namespace: namespace},
+			},
+			Status: corev1.PersistentVolumeStatus{Phase: phase},
+		}
+		if role != "" {
+			pv.Labels[util.LabelInstanceRole] = role
+		}
+		return pv
+	}
+	newReconciler := func(objects ...client.Object) *DocumentDBReconciler {
+		return &DocumentDBReconciler{
+			Client:   fake.NewClientBuilder().WithScheme(scheme).WithObjects(objects...).Build(),
+			Scheme:   scheme,
+			Recorder: recorder,
+		}
+	}
+
+	It("adopts the data volume of the last primary", func() {
+		r := newReconciler(
+			retainedPV("pv-replica", "db-2", "replica", corev1.VolumeReleased, time.Hour),
+			retainedPV("pv-primary", "db-1", "primary", corev1.VolumeReleased, 2*time.Hour),
+			retainedPV("pv-primary-wal", "db-1-wal", "primary", corev1.VolumeReleased, time.Minute),
+		)
+
+		adopted, err := r.reconcileVolumeAdoption(ctx, documentdb, "db", true)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(adopted).To(BeTrue())
+		Expect(documentdb.Status.AdoptedVolume).To(Equal("pv-primary"))
+		Expect(documentdb.IsPVRecoveryConfigured()).To(BeTrue())
+		Expect(recorder.Events).To(Receive(ContainSubstring("Normal VolumeAdopted Recovering from retained PersistentVolume pv-primary")))
+	})
+
+	It("adopts the most recent data volume without a known primary", func() {
+		r := newReconciler(
+			retainedPV("pv-old", "db-1", "", corev1.VolumeReleased, 2*time.Hour),
+			retainedPV("pv-new", "db-2", "", corev1.VolumeReleased, time.Hour),
+			retainedPV("pv-bound", "db-3", "", corev1.VolumeBound, time.Minute),
+		)
+
+		_, err := r.reconcileVolumeAdoption(ctx, documentdb, "db", true)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(documentdb.Status.AdoptedVolume).To(Equal("pv-new"))
+	})
+
+	It("initializes an empty cluster without retained volumes", func() {
+		r := newReconciler(retainedPV("pv-bound", "db-1", "primary", corev1.VolumeBound, time.Hour))
+
+		adopted, err := r.reconcileVolumeAdoption(ctx, documentdb, "db", true)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(adopted).To(BeFalse())
+		Expect(documentdb.Status.AdoptedVolume).To(BeEmpty())
+		Expect(recorder.Events).To(Receive(ContainSubstring("Normal NoVolumeAdopted")))
+	})
+
+	It("does not adopt once the CNPG Cluster exists", func() {
+		r := newReconciler(
+			&cnpgv1.Cluster{ObjectMeta: metav1.ObjectMeta{Name: "db", Namespace: namespace}},
+			retainedPV("pv-primary", "db-1", "primary", corev1.VolumeReleased, time.Hour),
+		)
+
+		adopted, err := r.reconcileVolumeAdoption(ctx, documentdb, "db", true)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(adopted).To(BeFalse())
+		Expect(documentdb.Status.AdoptedVolume).To(BeEmpty())
+	})
+
+	It("does not adopt without adoptRetainedVolumes or outside the primary region", func() {
+		r := newReconciler(retainedPV("pv-primary", "db-1", "primary", corev1.VolumeReleased, time.Hour))
+
+		adopted, err := r.reconcileVolumeAdoption(ctx, documentdb, "db", false)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(adopted).To(BeFalse())
+
+		documentdb.Spec.Bootstrap.AdoptRetainedVolumes = false
+		adopted, err = r.reconcileVolumeAdoption(ctx, documentdb, "db", true)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(adopted).To(BeFalse())
+	})
+})
@@ -0,0 +1,65 @@
+// Copyright (c) Microsoft Corporation.
+// Licensed under the MIT License.
+
+package controller
+
+import (
+	"context"
+	"fmt"
+
+	cnpgv1 "github.com/cloudnative-pg/cloudnative-pg/api/v1"
+	"k8s.io/apimachinery/pkg/api/equality"
+
+	dbpreview "github.com/documentdb/documentdb-operator/api/preview"
+)
+
+// walUsageQuery returns the size of the WAL and the WAL kept for the
+// logical replication slots of change streams. A replica cluster is in
+// recovery, so its position is the last replayed one.
+const walUsageQuery = `SELECT json_build_object(
+  'used', (SELECT COALESCE(sum(size), 0) FROM pg_ls_waldir()),
+  'retained', (SELECT COALESCE(pg_wal_lsn_diff(
+      CASE WHEN pg_is_in_recovery() THEN pg_last_wal_replay_lsn() ELSE pg_current_wal_lsn() END,
+      min(restart_lsn)), 0)::bigint
+    FROM pg_replication_slots WHERE slot_type = 'logical'));`
+
+// walUsage is the result of walUsageQuery, in bytes.
+type walUsage struct {
+	Used     int64 `json:"used"`
+	Retained int64 `json:"retained"`
+}
+
+// reconcileWALStatus reports in status.wal the usage of the WAL volume of
+// the primary when spec.resource.storage.wal is set. It returns whether
+// status.wal changed.
+func (r *DocumentDBReconciler) reconcileWALStatus(ctx context.Context, documentdb *dbpreview.DocumentDB, cluster *cnpgv1.Cluster) (bool, error) {
+	var status *dbpreview.WALStatus
+	if documentdb.Spec.Resource.Storage.WAL != nil {
+		if cluster.Status.CurrentPrimary == "" {
+			return false, nil
+		}
+		output, err := r.querySQL(ctx, cluster, walUsageQuery)
+		if err != nil {
+			return false, fmt.Errorf("failed to query the WAL usage: %w", err)
+		}
+		usage := walUsage{}
+		if err := parseJSONRowFromOutput(output, &usage); err != nil {
+			return false, fmt.Errorf("failed to parse the WAL usage: %w", err)
+		}
+		status = &dbpreview.WALStatus{Used: mebibytes(usage.Used)}
+		if usage.Retained > 0 {
+			status.RetainedByChangeStreams = mebibytes(usage.Retained)
+		}
+	}
+
+	if equality.Semantic.DeepEqual(documentdb.Status.WAL, status) {
+		return false, nil
+	}
+	documentdb.Status.WAL = status
+	return true, nil
+}
+
+// mebibytes formats bytes as a quantity rounded up to the mebibyte.
+func mebibytes(bytes int64) string {
+	return fmt.Sprintf("%dMi", (bytes+mebibyte-1)/mebibyte)
+}
@@ -0,0 +1,77 @@
+// Copyright (c) Microsoft Corporation.
+// Licensed under the MIT License.
+
+package controller
+
+import (
+	"context"
+	"fmt"
+
+	cnpgv1 "github.com/cloudnative-pg/cloudnative-pg/api/v1"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	dbpreview "github.com/documentdb/documentdb-operator/api/preview"
+)
+
+var _ = Describe("WAL status", func() {
+	var (
+		ctx         context.Context
+		reconciler  *DocumentDBReconciler
+		documentdb  *dbpreview.DocumentDB
+		cnpgCluster *cnpgv1.Cluster
+		executed    int
+		output      string
+		sqlErr      error
+	)
+
+	BeforeEach(func() {
+		ctx = context.Background()
+		executed = 0
+		output = " json_build_object \n-------------------\n {\"used\" : 50331648, \"retained\" : 1048577}\n(1 row)\n"
+		sqlErr = nil
+		documentdb = &dbpreview.DocumentDB{}
+		documentdb.Spec.Resource.Storage.WAL = &dbpreview.WALStorageConfiguration{PvcSize: "5Gi"}
+		cnpgCluster = &cnpgv1.Cluster{Status: cnpgv1.ClusterStatus{CurrentPrimary: "db-1"}}
+		reconciler = &DocumentDBReconciler{
+			SQLExecutor: func(_ context.Context, _ *cnpgv1.Cluster, _ string) (string, error) {
+				executed++
+				return output, sqlErr
+			},
+		}
+	})
+
+	It("reports the WAL usage", func() {
+		changed, err := reconciler.reconcileWALStatus(ctx, documentdb, cnpgCluster)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(changed).To(BeTrue())
+		Expect(documentdb.Status.WAL).To(Equal(&dbpreview.WALStatus{Used: "48Mi", RetainedByChangeStreams: "2Mi"}))
+
+		changed, err = reconciler.reconcileWALStatus(ctx, documentdb, cnpgCluster)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(changed).To(BeFalse())
+	})
+
+	It("omits the retained WAL without change streams", func() {
+		output = " json_build_object \n-------------------\n {\"used\" : 16777216, \"retained\" : 0}\n(1 row)\n"
+		_, err := reconciler.reconcileWALStatus(ctx, documentdb, cnpgCluster)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(documentdb.Status.WAL).To(Equal(&dbpreview.WALStatus{Used: "16Mi"}))
+	})
+
+	It("does not query clusters without a WAL volume", func() {
+		documentdb.Spec.Resource.Storage.WAL = nil
+		documentdb.Status.WAL = &dbpreview.WALStatus{Used: "16Mi"}
+		changed, err := reconciler.reconcileWALStatus(ctx, documentdb, cnpgCluster)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(changed).To(BeTrue())
+		Expect(documentdb.Status.WAL).To(BeNil())
+		Expect(executed).To(BeZero())
+	})
+
+	It("returns the SQL error", func() {
+		sqlErr = fmt.Errorf("connection refused")
+		_, err := reconciler.reconcileWALStatus(ctx, documentdb, cnpgCluster)
+		Expect(err).To(MatchError(ContainSubstring("connection refused")))
+	})
+})
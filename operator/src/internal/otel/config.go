@@ -19,6 +19,23 @@ var baseConfigYAML []byte
 
 const defaultPrometheusPort = 8888
 
+const defaultCollectionStatsIntervalMinutes = 5
+
+// collectionStatsDatasource is the datasource of the sqlquery receiver of
+// base_config.yaml.
+const collectionStatsDatasource = "host=localhost port=5432 user=${env:PGUSER} password=${env:PGPASSWORD} dbname=postgres sslmode=disable"
+
+// collectionStatsQuery reads the statistics of the table of each collection.
+// The number of documents is the planner's estimate, which autovacuum keeps
+// up to date, so the query does not scan the collections. Views have no
+// table and are left out.
+const collectionStatsQuery = `SELECT c.database_name, c.collection_name,
+  GREATEST(t.reltuples, 0)::bigint AS documents,
+  pg_table_size(t.oid) AS size_bytes,
+  pg_indexes_size(t.oid) AS index_size_bytes
+FROM documentdb_api_catalog.collections c
+JOIN pg_class t ON t.oid = to_regclass('documentdb_data.documents_' || c.collection_id)`
+
 // collectorConfig represents the OTel Collector configuration structure.
 type collectorConfig struct {
 	Receivers  map[string]any `yaml:"receivers,omitempty"`
@@ -99,6 +116,11 @@ func generateDynamicConfig(clusterName, namespace string, spec *dbpreview.Monito
 	// this sidecar.
 	receiverNames := []string{"sqlquery", "otlp"}
 
+	if stats := spec.CollectionStats; stats != nil {
+		cfg.Receivers = map[string]any{"sqlquery/collections": collectionStatsReceiver(stats)}
+		receiverNames = append(receiverNames, "sqlquery/collections")
+	}
+
 	exporterNames := []string{}
 
 	if spec.Exporter != nil {
@@ -168,6 +190,38 @@ func generateDynamicConfig(clusterName, namespace string, spec *dbpreview.Monito
 	return string(out), nil
 }
 
+// collectionStatsReceiver returns the sqlquery receiver of the per-collection
+// metrics, which runs less often than the health queries.
+func collectionStatsReceiver(stats *dbpreview.CollectionStatsSpec) map[string]any {
+	interval := stats.IntervalMinutes
+	if interval == 0 {
+		interval = defaultCollectionStatsIntervalMinutes
+	}
+	metric := func(name, column, unit string) map[string]any {
+		return map[string]any{
+			"metric_name":       name,
+			"value_column":      column,
+			"value_type":        "int",
+			"data_type":         "gauge",
+			"unit":              unit,
+			"attribute_columns": []string{"database_name", "collection_name"},
+		}
+	}
+	return map[string]any{
+		"driver":              "postgres",
+		"datasource":          collectionStatsDatasource,
+		"collection_interval": fmt.Sprintf("%dm", interval),
+		"queries": []map[string]any{{
+			"sql": collectionStatsQuery,
+			"metrics": []map[string]any{
+				metric("documentdb.collection.documents", "documents", "{document}"),
+				metric("documentdb.collection.size", "size_bytes", "By"),
+				metric("documentdb.collection.index.size", "index_size_bytes", "By"),
+			},
+		}},
+	}
+}
+
 // HashConfigMapData computes a truncated SHA-256 hash of ConfigMap data.
 // The hash is stored as a CNPG plugin parameter so that the operator detects
 // config changes (via parameter diff in SyncCnpgCluster) and triggers a
@@ -181,6 +181,51 @@ var _ = Describe("GenerateConfigMapData", func() {
 		Expect(promCfg["endpoint"]).To(Equal("0.0.0.0:9090"))
 	})
 
+	It("adds the per-collection metrics receiver when collectionStats is set", func() {
+		spec := &dbpreview.MonitoringSpec{
+			Enabled: true,
+			Exporter: &dbpreview.ExporterSpec{
+				Prometheus: &dbpreview.PrometheusExporterSpec{Port: 9090},
+			},
+			CollectionStats: &dbpreview.CollectionStatsSpec{IntervalMinutes: 15},
+		}
+		data, err := GenerateConfigMapData("cluster", "ns", spec)
+		Expect(err).NotTo(HaveOccurred())
+
+		dynCfg := parseCfg(data["dynamic.yaml"])
+		receiver, ok := dynCfg.Receivers["sqlquery/collections"].(map[string]any)
+		Expect(ok).To(BeTrue())
+		Expect(receiver["collection_interval"]).To(Equal("15m"))
+		Expect(data["dynamic.yaml"]).To(ContainSubstring("documentdb.collection.documents"))
+		Expect(data["dynamic.yaml"]).To(ContainSubstring("documentdb_api_catalog.collections"))
+		Expect(dynCfg.Service.Pipelines["metrics"].Receivers).To(ConsistOf("sqlquery", "otlp", "sqlquery/collections"))
+
+		staticCfg := parseCfg(data["static.yaml"])
+		base, ok := staticCfg.Receivers["sqlquery"].(map[string]any)
+		Expect(ok).To(BeTrue())
+		Expect(receiver["datasource"]).To(Equal(base["datasource"]))
+	})
+
+	It("collects the per-collection metrics every 5 minutes by default", func() {
+		spec := &dbpreview.MonitoringSpec{
+			Enabled:         true,
+			CollectionStats: &dbpreview.CollectionStatsSpec{},
+		}
+		data, err := GenerateConfigMapData("cluster", "ns", spec)
+		Expect(err).NotTo(HaveOccurred())
+
+		receiver, ok := parseCfg(data["dynamic.yaml"]).Receivers["sqlquery/collections"].(map[string]any)
+		Expect(ok).To(BeTrue())
+		Expect(receiver["collection_interval"]).To(Equal("5m"))
+	})
+
+	It("omits the per-collection metrics receiver by default", func() {
+		spec := &dbpreview.MonitoringSpec{Enabled: true}
+		data, err := GenerateConfigMapData("cluster", "ns", spec)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(parseCfg(data["dynamic.yaml"]).Receivers).To(BeEmpty())
+	})
+
 	It("includes both OTLP and Prometheus exporters", func() {
 		spec := &dbpreview.MonitoringSpec{
 			Enabled: true,
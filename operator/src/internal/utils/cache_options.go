@@ -0,0 +1,42 @@
+// Copyright (c) Microsoft Corporation.
+// Licensed under the MIT License.
+
+package util
+
+import (
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/selection"
+	"sigs.k8s.io/controller-runtime/pkg/cache"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+// cnpgPodSelector selects the Pods of CNPG clusters: their instances and the
+// Pods of their Jobs.
+var cnpgPodSelector = func() labels.Selector {
+	requirement, err := labels.NewRequirement("cnpg.io/cluster", selection.Exists, nil)
+	if err != nil {
+		panic(err)
+	}
+	return labels.NewSelector().Add(*requirement)
+}()
+
+// CNPGPodsCacheOptions restricts the Pods cached with options to those of CNPG
+// clusters, the only Pods the operator reads, instead of every Pod of the
+// watched namespaces.
+func CNPGPodsCacheOptions(options cache.Options) cache.Options {
+	if options.ByObject == nil {
+		options.ByObject = map[client.Object]cache.ByObject{}
+	}
+	options.ByObject[&corev1.Pod{}] = cache.ByObject{Label: cnpgPodSelector}
+	return options
+}
+
+// UncachedSecretsClientOptions makes the manager client read Secrets from the
+// API server. Only the metadata of Secrets is cached, for the watches of the
+// DocumentDB controller, rather than the content of every Secret.
+func UncachedSecretsClientOptions() client.Options {
+	return client.Options{
+		Cache: &client.CacheOptions{DisableFor: []client.Object{&corev1.Secret{}}},
+	}
+}
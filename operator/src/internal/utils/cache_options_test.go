@@ -0,0 +1,44 @@
+// Copyright (c) Microsoft Corporation.
+// Licensed under the MIT License.
+
+package util
+
+import (
+	"testing"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	"sigs.k8s.io/controller-runtime/pkg/cache"
+)
+
+func TestCNPGPodsCacheOptions(t *testing.T) {
+	for name, options := range map[string]cache.Options{
+		"whole cluster":    {},
+		"watch namespaces": WatchNamespacesCacheOptions([]string{"team-a"}),
+	} {
+		t.Run(name, func(t *testing.T) {
+			var selector labels.Selector
+			configMaps := false
+			for obj, byObject := range CNPGPodsCacheOptions(options).ByObject {
+				switch obj.(type) {
+				case *corev1.Pod:
+					selector = byObject.Label
+				case *corev1.ConfigMap:
+					configMaps = true
+				}
+			}
+			if selector == nil {
+				t.Fatal("expected a label selector for Pods")
+			}
+			if !selector.Matches(labels.Set{"cnpg.io/cluster": "db"}) {
+				t.Error("expected the Pods of CNPG clusters to be cached")
+			}
+			if selector.Matches(labels.Set{"app": "web"}) {
+				t.Error("expected other Pods not to be cached")
+			}
+			if wantConfigMaps := options.ByObject != nil; configMaps != wantConfigMaps {
+				t.Errorf("ConfigMap options kept = %v, want %v", configMaps, wantConfigMaps)
+			}
+		})
+	}
+}
@@ -0,0 +1,71 @@
+// Copyright (c) Microsoft Corporation.
+// Licensed under the MIT License.
+
+package util
+
+import (
+	dbpreview "github.com/documentdb/documentdb-operator/api/preview"
+)
+
+// Capability describes what an optional DocumentDB feature, enabled through
+// spec.featureGates, requires from the cluster.
+type Capability struct {
+	// FeatureGate is the key of the feature in spec.featureGates.
+	FeatureGate string
+
+	// DocumentDBImage and GatewayImage are the image variants that ship the
+	// feature, used when neither spec.image nor a version selects an image.
+	// Empty when the default images ship it.
+	DocumentDBImage string
+	GatewayImage    string
+
+	// Parameters are the PostgreSQL parameters the feature requires. They
+	// take precedence over spec.postgres.parameters.
+	Parameters map[string]string
+}
+
+// capabilities lists the optional features. When several enabled features
+// ship in an image variant, the first one wins.
+var capabilities = []Capability{
+	{
+		FeatureGate: dbpreview.FeatureGateChangeStreams,
+		// TODO: remove these overrides once change stream support is included in the official images.
+		DocumentDBImage: CHANGESTREAM_DOCUMENTDB_IMAGE,
+		GatewayImage:    CHANGESTREAM_GATEWAY_IMAGE,
+		Parameters:      map[string]string{"wal_level": "logical"},
+	},
+	{
+		FeatureGate: dbpreview.FeatureGateVectorSearch,
+		Parameters: map[string]string{
+			"documentdb.enableVectorHNSWIndex": "true",
+			"documentdb.enableVectorPreFilter": "true",
+		},
+	},
+	{
+		FeatureGate: dbpreview.FeatureGateIOUring,
+		Parameters:  map[string]string{"io_method": "io_uring"},
+	},
+}
+
+// EnabledCapabilities returns the optional features enabled for documentdb,
+// in the order of precedence of their image variants.
+func EnabledCapabilities(documentdb *dbpreview.DocumentDB) []Capability {
+	var enabled []Capability
+	for _, capability := range capabilities {
+		if dbpreview.IsFeatureGateEnabled(documentdb, capability.FeatureGate) {
+			enabled = append(enabled, capability)
+		}
+	}
+	return enabled
+}
+
+// capabilityImage returns the first image variant that image selects from
+// the features enabled for documentdb, or "" when none needs one.
+func capabilityImage(documentdb *dbpreview.DocumentDB, image func(Capability) string) string {
+	for _, capability := range EnabledCapabilities(documentdb) {
+		if variant := image(capability); variant != "" {
+			return variant
+		}
+	}
+	return ""
+}
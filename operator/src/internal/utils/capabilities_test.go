@@ -0,0 +1,59 @@
+// Copyright (c) Microsoft Corporation.
+// Licensed under the MIT License.
+
+package util
+
+import (
+	"testing"
+
+	dbpreview "github.com/documentdb/documentdb-operator/api/preview"
+)
+
+func TestEnabledCapabilities(t *testing.T) {
+	tests := []struct {
+		name         string
+		featureGates map[string]bool
+		expected     []string
+	}{
+		{
+			name:     "no feature gates",
+			expected: nil,
+		},
+		{
+			name:         "disabled gates are skipped",
+			featureGates: map[string]bool{dbpreview.FeatureGateVectorSearch: false},
+			expected:     nil,
+		},
+		{
+			name: "enabled gates follow capability order",
+			featureGates: map[string]bool{
+				dbpreview.FeatureGateIOUring:       true,
+				dbpreview.FeatureGateVectorSearch:  true,
+				dbpreview.FeatureGateChangeStreams: true,
+			},
+			expected: []string{
+				dbpreview.FeatureGateChangeStreams,
+				dbpreview.FeatureGateVectorSearch,
+				dbpreview.FeatureGateIOUring,
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			db := &dbpreview.DocumentDB{Spec: dbpreview.DocumentDBSpec{FeatureGates: tt.featureGates}}
+			var result []string
+			for _, capability := range EnabledCapabilities(db) {
+				result = append(result, capability.FeatureGate)
+			}
+			if len(result) != len(tt.expected) {
+				t.Fatalf("EnabledCapabilities() = %v, expected %v", result, tt.expected)
+			}
+			for i := range result {
+				if result[i] != tt.expected[i] {
+					t.Errorf("EnabledCapabilities() = %v, expected %v", result, tt.expected)
+				}
+			}
+		})
+	}
+}
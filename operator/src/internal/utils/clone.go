@@ -0,0 +1,47 @@
+// Copyright (c) Microsoft Corporation.
+// Licensed under the MIT License.
+
+package util
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+)
+
+// CloneBackupName returns the name of the Backup of the source cluster that a
+// DocumentDB is cloned from. The Backup is created in the namespace of the
+// source, so a clone in another namespace prefixes its namespace to keep the
+// names of clones of the same source apart.
+func CloneBackupName(documentdbName, namespace, sourceNamespace string) string {
+	if namespace == sourceNamespace {
+		return fmt.Sprintf("%s-clone", documentdbName)
+	}
+	return fmt.Sprintf("%s-%s-clone", namespace, documentdbName)
+}
+
+// CloneSnapshotName returns the name of the VolumeSnapshot that imports the
+// source data of a cross-namespace clone into the namespace of the clone.
+func CloneSnapshotName(documentdbName string) string {
+	return fmt.Sprintf("%s-clone", documentdbName)
+}
+
+// CloneSnapshotContentName returns the name of the cluster-scoped
+// VolumeSnapshotContent bound to the VolumeSnapshot of CloneSnapshotName.
+func CloneSnapshotContentName(documentdbName, namespace string) string {
+	return fmt.Sprintf("%s-%s-clone", namespace, documentdbName)
+}
+
+// IsCloneAllowed reports whether a DocumentDB with the given annotations can
+// be cloned in namespace, per ALLOW_CLONE_TO_NAMESPACES_ANNOTATION. Clones in
+// the namespace of the source are always allowed.
+func IsCloneAllowed(annotations map[string]string, sourceNamespace, namespace string) bool {
+	if namespace == sourceNamespace {
+		return true
+	}
+	allowed := strings.Split(annotations[ALLOW_CLONE_TO_NAMESPACES_ANNOTATION], ",")
+	for i := range allowed {
+		allowed[i] = strings.TrimSpace(allowed[i])
+	}
+	return slices.Contains(allowed, "*") || slices.Contains(allowed, namespace)
+}
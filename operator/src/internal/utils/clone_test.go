@@ -0,0 +1,64 @@
+// Copyright (c) Microsoft Corporation.
+// Licensed under the MIT License.
+
+package util
+
+import "testing"
+
+func TestCloneBackupName(t *testing.T) {
+	tests := []struct {
+		name            string
+		namespace       string
+		sourceNamespace string
+		expected        string
+	}{
+		{name: "same namespace", namespace: "default", sourceNamespace: "default", expected: "staging-clone"},
+		{name: "other namespace", namespace: "qa", sourceNamespace: "default", expected: "qa-staging-clone"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := CloneBackupName("staging", tt.namespace, tt.sourceNamespace); got != tt.expected {
+				t.Errorf("CloneBackupName() = %q, want %q", got, tt.expected)
+			}
+		})
+	}
+}
+
+func TestIsCloneAllowed(t *testing.T) {
+	tests := []struct {
+		name        string
+		annotations map[string]string
+		namespace   string
+		expected    bool
+	}{
+		{name: "same namespace without annotation", namespace: "prod", expected: true},
+		{name: "other namespace without annotation", namespace: "qa", expected: false},
+		{
+			name:        "listed namespace",
+			annotations: map[string]string{ALLOW_CLONE_TO_NAMESPACES_ANNOTATION: "staging, qa"},
+			namespace:   "qa",
+			expected:    true,
+		},
+		{
+			name:        "unlisted namespace",
+			annotations: map[string]string{ALLOW_CLONE_TO_NAMESPACES_ANNOTATION: "staging"},
+			namespace:   "qa",
+			expected:    false,
+		},
+		{
+			name:        "every namespace",
+			annotations: map[string]string{ALLOW_CLONE_TO_NAMESPACES_ANNOTATION: "*"},
+			namespace:   "qa",
+			expected:    true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := IsCloneAllowed(tt.annotations, "prod", tt.namespace); got != tt.expected {
+				t.Errorf("IsCloneAllowed() = %v, want %v", got, tt.expected)
+			}
+		})
+	}
+}
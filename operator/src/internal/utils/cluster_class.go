@@ -0,0 +1,28 @@
+// Copyright (c) Microsoft Corporation.
+// Licensed under the MIT License.
+
+package util
+
+import (
+	"context"
+	"fmt"
+
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	dbpreview "github.com/documentdb/documentdb-operator/api/preview"
+)
+
+// ResolveClusterClass merges the DocumentDBClusterClass referenced by
+// spec.classRef into the in-memory spec of documentdb. The merged spec is never
+// written back, so the class keeps applying to the fields the user left unset.
+// It is a no-op for a DocumentDB without a classRef.
+func ResolveClusterClass(ctx context.Context, c client.Reader, documentdb *dbpreview.DocumentDB) error {
+	if documentdb.Spec.ClassRef == nil {
+		return nil
+	}
+	class := &dbpreview.DocumentDBClusterClass{}
+	if err := c.Get(ctx, client.ObjectKey{Name: documentdb.Spec.ClassRef.Name}, class); err != nil {
+		return fmt.Errorf("failed to get DocumentDBClusterClass %s: %w", documentdb.Spec.ClassRef.Name, err)
+	}
+	return documentdb.Spec.ApplyClusterClass(class)
+}
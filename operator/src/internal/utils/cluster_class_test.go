@@ -0,0 +1,123 @@
+// Copyright (c) Microsoft Corporation.
+// Licensed under the MIT License.
+
+package util
+
+import (
+	"context"
+	"testing"
+
+	dbpreview "github.com/documentdb/documentdb-operator/api/preview"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+)
+
+func TestResolveClusterClass(t *testing.T) {
+	scheme := runtime.NewScheme()
+	if err := dbpreview.AddToScheme(scheme); err != nil {
+		t.Fatal(err)
+	}
+	class := &dbpreview.DocumentDBClusterClass{
+		ObjectMeta: metav1.ObjectMeta{Name: "standard"},
+		Spec: dbpreview.DocumentDBClusterClassSpec{
+			Sizes: []dbpreview.ClusterClassSize{
+				{Name: "small", PvcSize: "10Gi", Memory: "2Gi", CPU: "1"},
+				{Name: "large", PvcSize: "100Gi", Memory: "16Gi", CPU: "4"},
+			},
+			DefaultSize:      "small",
+			InstancesPerNode: 3,
+			StorageClass:     "premium",
+		},
+	}
+
+	tests := []struct {
+		name    string
+		spec    dbpreview.DocumentDBSpec
+		want    dbpreview.DocumentDBSpec
+		wantErr bool
+	}{
+		{
+			name: "no classRef leaves the spec unchanged",
+			spec: dbpreview.DocumentDBSpec{InstancesPerNode: 1},
+			want: dbpreview.DocumentDBSpec{InstancesPerNode: 1},
+		},
+		{
+			name: "class fills unset fields from its default size",
+			spec: dbpreview.DocumentDBSpec{ClassRef: &dbpreview.ClusterClassReference{Name: "standard"}},
+			want: dbpreview.DocumentDBSpec{
+				ClassRef:         &dbpreview.ClusterClassReference{Name: "standard"},
+				InstancesPerNode: 3,
+				Resource: dbpreview.Resource{
+					Storage: dbpreview.StorageConfiguration{PvcSize: "10Gi", StorageClass: "premium"},
+					Memory:  "2Gi",
+					CPU:     "1",
+				},
+			},
+		},
+		{
+			name: "explicit fields win over the class",
+			spec: dbpreview.DocumentDBSpec{
+				ClassRef:         &dbpreview.ClusterClassReference{Name: "standard", Size: "large"},
+				InstancesPerNode: 1,
+				Resource: dbpreview.Resource{
+					Storage: dbpreview.StorageConfiguration{StorageClass: "standard"},
+					Memory:  "8Gi",
+				},
+			},
+			want: dbpreview.DocumentDBSpec{
+				ClassRef:         &dbpreview.ClusterClassReference{Name: "standard", Size: "large"},
+				InstancesPerNode: 1,
+				Resource: dbpreview.Resource{
+					Storage: dbpreview.StorageConfiguration{PvcSize: "100Gi", StorageClass: "standard"},
+					Memory:  "8Gi",
+					CPU:     "4",
+				},
+			},
+		},
+		{
+			name:    "missing class is an error",
+			spec:    dbpreview.DocumentDBSpec{ClassRef: &dbpreview.ClusterClassReference{Name: "missing"}},
+			wantErr: true,
+		},
+		{
+			name:    "missing size is an error",
+			spec:    dbpreview.DocumentDBSpec{ClassRef: &dbpreview.ClusterClassReference{Name: "standard", Size: "huge"}},
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c := fake.NewClientBuilder().WithScheme(scheme).WithObjects(class.DeepCopy()).Build()
+			documentdb := &dbpreview.DocumentDB{
+				ObjectMeta: metav1.ObjectMeta{Name: "db", Namespace: "team-a"},
+				Spec:       tt.spec,
+			}
+
+			err := ResolveClusterClass(context.Background(), c, documentdb)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("ResolveClusterClass() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if tt.wantErr {
+				return
+			}
+			got := documentdb.Spec
+			if got.InstancesPerNode != tt.want.InstancesPerNode {
+				t.Errorf("instancesPerNode = %d, want %d", got.InstancesPerNode, tt.want.InstancesPerNode)
+			}
+			if got.Resource.Storage.PvcSize != tt.want.Resource.Storage.PvcSize {
+				t.Errorf("pvcSize = %q, want %q", got.Resource.Storage.PvcSize, tt.want.Resource.Storage.PvcSize)
+			}
+			if got.Resource.Storage.StorageClass != tt.want.Resource.Storage.StorageClass {
+				t.Errorf("storageClass = %q, want %q", got.Resource.Storage.StorageClass, tt.want.Resource.Storage.StorageClass)
+			}
+			if got.Resource.Memory != tt.want.Resource.Memory {
+				t.Errorf("memory = %q, want %q", got.Resource.Memory, tt.want.Resource.Memory)
+			}
+			if got.Resource.CPU != tt.want.Resource.CPU {
+				t.Errorf("cpu = %q, want %q", got.Resource.CPU, tt.want.Resource.CPU)
+			}
+		})
+	}
+}
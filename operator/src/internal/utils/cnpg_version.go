@@ -0,0 +1,83 @@
+// Copyright (c) Microsoft Corporation.
+// Licensed under the MIT License.
+
+package util
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/version"
+	"k8s.io/client-go/kubernetes"
+
+	dbpreview "github.com/documentdb/documentdb-operator/api/preview"
+)
+
+// cnpgOperatorSelector selects the CloudNativePG operator Deployment, both in
+// the upstream manifests and in the cloudnative-pg Helm chart.
+const cnpgOperatorSelector = "app.kubernetes.io/name=cloudnative-pg"
+
+// DetectCNPGVersion returns the version of the CloudNativePG operator, read
+// from the image tag of its Deployment in GetCNPGNamespace(). It returns nil,
+// without an error, when no Deployment is found or its image tag is not a
+// version, e.g. a digest.
+func DetectCNPGVersion(ctx context.Context, clientset kubernetes.Interface) (*version.Version, error) {
+	deployments, err := clientset.AppsV1().Deployments(GetCNPGNamespace()).List(ctx, metav1.ListOptions{LabelSelector: cnpgOperatorSelector})
+	if err != nil {
+		return nil, fmt.Errorf("failed to list CloudNativePG operator Deployments: %w", err)
+	}
+	for _, deployment := range deployments.Items {
+		for _, container := range deployment.Spec.Template.Spec.Containers {
+			if container.Name != "manager" {
+				continue
+			}
+			ref := container.Image
+			if strings.Contains(ref, "@") {
+				return nil, nil
+			}
+			idx := strings.LastIndex(ref, ":")
+			if idx <= strings.LastIndex(ref, "/") {
+				return nil, nil
+			}
+			v, err := version.ParseGeneric(ref[idx+1:])
+			if err != nil {
+				return nil, nil
+			}
+			return v, nil
+		}
+	}
+	return nil, nil
+}
+
+// cnpgFeatures lists the DocumentDB settings that rely on a CloudNativePG
+// feature introduced after MinCNPGVersion.
+var cnpgFeatures = []struct {
+	setting    string
+	minVersion string
+	used       func(spec *dbpreview.DocumentDBSpec) bool
+}{
+	{
+		// CNPG 1.28 added the pod and container security context overrides.
+		setting:    "spec.securityContext runAsUser, runAsGroup, fsGroup and readOnlyRootFilesystem",
+		minVersion: "1.28.0",
+		used: func(spec *dbpreview.DocumentDBSpec) bool {
+			sc := spec.SecurityContext
+			return sc != nil && (sc.RunAsUser != nil || sc.RunAsGroup != nil || sc.FSGroup != nil || sc.ReadOnlyRootFilesystem != nil)
+		},
+	},
+}
+
+// UnsupportedCNPGFeatures returns a message for each setting of spec that the
+// CloudNativePG operator at cnpgVersion cannot apply.
+func UnsupportedCNPGFeatures(spec *dbpreview.DocumentDBSpec, cnpgVersion *version.Version) []string {
+	var unsupported []string
+	for _, feature := range cnpgFeatures {
+		if feature.used(spec) && !cnpgVersion.AtLeast(version.MustParseGeneric(feature.minVersion)) {
+			unsupported = append(unsupported, fmt.Sprintf("%s require CloudNativePG %s or later, but version %s is installed",
+				feature.setting, feature.minVersion, cnpgVersion))
+		}
+	}
+	return unsupported
+}
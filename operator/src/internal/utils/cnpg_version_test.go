@@ -0,0 +1,130 @@
+// Copyright (c) Microsoft Corporation.
+// Licensed under the MIT License.
+
+package util
+
+import (
+	"context"
+	"testing"
+
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/util/version"
+	kubefake "k8s.io/client-go/kubernetes/fake"
+	"k8s.io/utils/ptr"
+
+	dbpreview "github.com/documentdb/documentdb-operator/api/preview"
+)
+
+func TestDetectCNPGVersion(t *testing.T) {
+	cnpgDeployment := func(namespace, image string) *appsv1.Deployment {
+		return &appsv1.Deployment{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      "cnpg-controller-manager",
+				Namespace: namespace,
+				Labels:    map[string]string{"app.kubernetes.io/name": "cloudnative-pg"},
+			},
+			Spec: appsv1.DeploymentSpec{Template: corev1.PodTemplateSpec{Spec: corev1.PodSpec{
+				Containers: []corev1.Container{{Name: "manager", Image: image}},
+			}}},
+		}
+	}
+
+	tests := []struct {
+		name             string
+		operatorSettings map[string]string
+		objects          []runtime.Object
+		want             string
+	}{
+		{
+			name:    "version from the image tag",
+			objects: []runtime.Object{cnpgDeployment(DEFAULT_CNPG_NAMESPACE, "ghcr.io/cloudnative-pg/cloudnative-pg:1.29.2")},
+			want:    "1.29.2",
+		},
+		{
+			name:             "configured namespace",
+			operatorSettings: map[string]string{CNPG_NAMESPACE_ENV: "cnpg"},
+			objects:          []runtime.Object{cnpgDeployment("cnpg", "registry.example.com:5000/cloudnative-pg:1.27.1")},
+			want:             "1.27.1",
+		},
+		{
+			name: "no deployment",
+		},
+		{
+			name:    "deployment in another namespace",
+			objects: []runtime.Object{cnpgDeployment("cnpg", "ghcr.io/cloudnative-pg/cloudnative-pg:1.29.2")},
+		},
+		{
+			name:    "image digest",
+			objects: []runtime.Object{cnpgDeployment(DEFAULT_CNPG_NAMESPACE, "ghcr.io/cloudnative-pg/cloudnative-pg@sha256:abcd")},
+		},
+		{
+			name:    "tag that is not a version",
+			objects: []runtime.Object{cnpgDeployment(DEFAULT_CNPG_NAMESPACE, "ghcr.io/cloudnative-pg/cloudnative-pg:latest")},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Cleanup(func() { SetOperatorSettings(nil) })
+			SetOperatorSettings(tt.operatorSettings)
+
+			got, err := DetectCNPGVersion(context.Background(), kubefake.NewSimpleClientset(tt.objects...))
+			if err != nil {
+				t.Fatalf("DetectCNPGVersion() error = %v", err)
+			}
+			if tt.want == "" {
+				if got != nil {
+					t.Errorf("DetectCNPGVersion() = %v, want nil", got)
+				}
+				return
+			}
+			if got == nil || got.String() != tt.want {
+				t.Errorf("DetectCNPGVersion() = %v, want %s", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestUnsupportedCNPGFeatures(t *testing.T) {
+	tests := []struct {
+		name        string
+		spec        dbpreview.DocumentDBSpec
+		cnpgVersion string
+		want        int
+	}{
+		{
+			name:        "no version-gated setting",
+			cnpgVersion: "1.27.0",
+		},
+		{
+			name:        "security context on an older CloudNativePG",
+			spec:        dbpreview.DocumentDBSpec{SecurityContext: &dbpreview.SecurityContextSpec{ReadOnlyRootFilesystem: ptr.To(true)}},
+			cnpgVersion: "1.27.3",
+			want:        1,
+		},
+		{
+			name:        "security context on a recent CloudNativePG",
+			spec:        dbpreview.DocumentDBSpec{SecurityContext: &dbpreview.SecurityContextSpec{ReadOnlyRootFilesystem: ptr.To(true)}},
+			cnpgVersion: "1.28.0",
+		},
+		{
+			name: "seccomp profile only",
+			spec: dbpreview.DocumentDBSpec{SecurityContext: &dbpreview.SecurityContextSpec{
+				SeccompProfile: &corev1.SeccompProfile{Type: corev1.SeccompProfileTypeRuntimeDefault},
+			}},
+			cnpgVersion: "1.27.0",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := UnsupportedCNPGFeatures(&tt.spec, version.MustParseGeneric(tt.cnpgVersion))
+			if len(got) != tt.want {
+				t.Errorf("UnsupportedCNPGFeatures() = %v, want %d messages", got, tt.want)
+			}
+		})
+	}
+}
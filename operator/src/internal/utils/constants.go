@@ -3,11 +3,221 @@
 
 package util
 
+import "time"
+
 const (
+	// POSTGRES_PORT, SIDECAR_PORT and GATEWAY_PORT name the ports of
+	// GetPortFor. SIDECAR_PORT and GATEWAY_PORT are also the legacy
+	// environment variables of SIDECAR_PORT_ENV and GATEWAY_PORT_ENV.
 	POSTGRES_PORT = "POSTGRES_PORT"
 	SIDECAR_PORT  = "SIDECAR_PORT"
 	GATEWAY_PORT  = "GATEWAY_PORT"
 
+	// DEFAULT_POSTGRES_PORT is the port CNPG runs PostgreSQL on. It cannot
+	// be changed.
+	DEFAULT_POSTGRES_PORT = 5432
+
+	// GATEWAY_PORT_ENV overrides the port the gateway listens on and the
+	// DocumentDB Service exposes, for clusters without spec.gateway.port
+	// (default DEFAULT_GATEWAY_PORT).
+	GATEWAY_PORT_ENV     = "DOCUMENTDB_GATEWAY_PORT"
+	DEFAULT_GATEWAY_PORT = 10260
+
+	// SIDECAR_PORT_ENV overrides the port reserved for the sidecar (default
+	// DEFAULT_SIDECAR_PORT). The gateway port may not take it.
+	SIDECAR_PORT_ENV     = "DOCUMENTDB_SIDECAR_PORT"
+	DEFAULT_SIDECAR_PORT = 8445
+
+	// CNPG_STATUS_PORT and CNPG_METRICS_PORT are the ports of the CNPG
+	// instance manager in every instance pod.
+	CNPG_STATUS_PORT  = 8000
+	CNPG_METRICS_PORT = 9187
+
+	// OPERATOR_NAMESPACE_ENV is the namespace the operator runs in (downward API).
+	OPERATOR_NAMESPACE_ENV = "OPERATOR_NAMESPACE"
+
+	// OPERATOR_CONFIG_MAP_ENV overrides the name of the ConfigMap, in the
+	// operator namespace, that holds hot-reloadable operator settings. Its keys
+	// are the environment variable names below and take precedence over them.
+	OPERATOR_CONFIG_MAP_ENV     = "DOCUMENTDB_OPERATOR_CONFIG_MAP"
+	DEFAULT_OPERATOR_CONFIG_MAP = "documentdb-operator-config"
+
+	// OTEL_COLLECTOR_IMAGE_ENV overrides the OpenTelemetry Collector sidecar
+	// image (default DEFAULT_OTEL_COLLECTOR_IMAGE).
+	OTEL_COLLECTOR_IMAGE_ENV = "DOCUMENTDB_OTEL_COLLECTOR_IMAGE"
+
+	// MONGODB_TOOLS_IMAGE_ENV overrides the image with mongodump and
+	// mongorestore that the import and export Jobs run (default
+	// DEFAULT_MONGODB_TOOLS_IMAGE).
+	MONGODB_TOOLS_IMAGE_ENV = "DOCUMENTDB_MONGODB_TOOLS_IMAGE"
+
+	// AWS_CLI_IMAGE_ENV overrides the image that uploads the exports to the
+	// object store (default DEFAULT_AWS_CLI_IMAGE).
+	AWS_CLI_IMAGE_ENV = "DOCUMENTDB_AWS_CLI_IMAGE"
+
+	// DEFAULT_BACKUP_RETENTION_DAYS_ENV overrides the retention period of
+	// backups of clusters without spec.backup (default DEFAULT_BACKUP_RETENTION_DAYS).
+	DEFAULT_BACKUP_RETENTION_DAYS_ENV = "DOCUMENTDB_DEFAULT_BACKUP_RETENTION_DAYS"
+	DEFAULT_BACKUP_RETENTION_DAYS     = 30
+
+	// DEFAULT_STORAGE_CLASS_ENV sets the storage class of clusters that do not
+	// set spec.resource.storage.storageClass. Unset leaves the choice to the
+	// Kubernetes default storage class.
+	DEFAULT_STORAGE_CLASS_ENV = "DOCUMENTDB_DEFAULT_STORAGE_CLASS"
+
+	// DEFAULT_SERVICE_TYPE_ENV sets the type of the Service, LoadBalancer or
+	// ClusterIP, created for clusters that do not set spec.exposeViaService.
+	// Unset creates no Service.
+	DEFAULT_SERVICE_TYPE_ENV = "DOCUMENTDB_DEFAULT_SERVICE_TYPE"
+
+	// NAMESPACE_DEFAULTS_CONFIG_MAP is the name of the optional ConfigMap, in a
+	// DocumentDB's namespace, whose DEFAULT_STORAGE_CLASS_ENV,
+	// DEFAULT_SERVICE_TYPE_ENV and DEFAULT_BACKUP_RETENTION_DAYS_ENV keys take
+	// precedence over the operator settings for the DocumentDBs in that namespace.
+	NAMESPACE_DEFAULTS_CONFIG_MAP = "documentdb-defaults"
+
+	// MAX_CLUSTERS_PER_NAMESPACE_ENV and MAX_STORAGE_PER_NAMESPACE_ENV limit the
+	// number of DocumentDB clusters in a namespace and the total storage, summed
+	// over all their instances, that they request, e.g. "10" and "1Ti". The
+	// validating webhook enforces them. Unset or "0" means no limit.
+	MAX_CLUSTERS_PER_NAMESPACE_ENV = "DOCUMENTDB_MAX_CLUSTERS_PER_NAMESPACE"
+	MAX_STORAGE_PER_NAMESPACE_ENV  = "DOCUMENTDB_MAX_STORAGE_PER_NAMESPACE"
+
+	// MIN_PVC_SIZE_ENV sets the smallest spec.resource.storage.pvcSize, e.g.
+	// "10Gi", that the validating webhook admits for a new cluster. Unset or
+	// "0" means no minimum.
+	MIN_PVC_SIZE_ENV = "DOCUMENTDB_MIN_PVC_SIZE"
+
+	// DELETION_BACKUP_MAX_AGE_ENV, when set to a duration such as "24h", holds
+	// the deletion of clusters with persistentVolumeReclaimPolicy Delete until a
+	// backup has completed within that window. Unset or "0" disables the check.
+	DELETION_BACKUP_MAX_AGE_ENV = "DOCUMENTDB_DELETION_BACKUP_MAX_AGE"
+
+	// DRIFT_CHECK_INTERVAL_ENV sets how often each DocumentDB is re-reconciled to
+	// compare its CNPG Cluster with the desired spec (default
+	// DEFAULT_DRIFT_CHECK_INTERVAL). "0" disables the periodic check; drift is
+	// then only checked when the DocumentDB or the CNPG Cluster status changes.
+	DRIFT_CHECK_INTERVAL_ENV     = "DOCUMENTDB_DRIFT_CHECK_INTERVAL"
+	DEFAULT_DRIFT_CHECK_INTERVAL = 10 * time.Minute
+
+	// DRIFT_RECONCILIATION_ENV selects which drifted CNPG Cluster fields the
+	// operator reverts: DRIFT_RECONCILIATION_TARGETED (default) only the fields
+	// it updates on spec changes, such as images, instances, resources and
+	// storage size; DRIFT_RECONCILIATION_FULL every field it renders.
+	DRIFT_RECONCILIATION_ENV      = "DOCUMENTDB_DRIFT_RECONCILIATION"
+	DRIFT_RECONCILIATION_TARGETED = "Targeted"
+	DRIFT_RECONCILIATION_FULL     = "Full"
+
+	// HEALTH_POLL_INTERVAL_ENV sets how often the operator reads the extension
+	// versions and the replication grants from the primary of each cluster in
+	// the background, so that reconciles use the last result instead of
+	// running psql (default DEFAULT_HEALTH_POLL_INTERVAL). "0" turns the
+	// poller off; reconciles then query the primary themselves.
+	HEALTH_POLL_INTERVAL_ENV     = "DOCUMENTDB_HEALTH_POLL_INTERVAL"
+	DEFAULT_HEALTH_POLL_INTERVAL = time.Minute
+
+	// POD_EXEC_QPS_ENV and POD_EXEC_BURST_ENV limit the rate at which the
+	// operator runs commands such as psql in the database pods, across all
+	// DocumentDB clusters (defaults DEFAULT_POD_EXEC_QPS and
+	// DEFAULT_POD_EXEC_BURST). A QPS of "0" removes the limit.
+	POD_EXEC_QPS_ENV       = "DOCUMENTDB_POD_EXEC_QPS"
+	POD_EXEC_BURST_ENV     = "DOCUMENTDB_POD_EXEC_BURST"
+	DEFAULT_POD_EXEC_QPS   = 5
+	DEFAULT_POD_EXEC_BURST = 10
+
+	// PV_RECOVERY_TIMEOUT_ENV sets how long a recovery from a retained PV may
+	// take before the operator gives up and deletes the temporary PVC holding
+	// the PV (default DEFAULT_PV_RECOVERY_TIMEOUT).
+	PV_RECOVERY_TIMEOUT_ENV     = "DOCUMENTDB_PV_RECOVERY_TIMEOUT"
+	DEFAULT_PV_RECOVERY_TIMEOUT = 2 * time.Hour
+
+	// OPENSHIFT_ENV set to "true" makes the operator render clusters that run
+	// under OpenShift's restricted-v2 SecurityContextConstraints: no fixed UID,
+	// GID or fsGroup on any container, and no Localhost seccomp profile. When
+	// unset, the mode follows whether the operator detected OpenShift.
+	OPENSHIFT_ENV = "DOCUMENTDB_OPENSHIFT"
+
+	// FLEET_HUB_ENV set to "true" makes the operator run on a Fleet hub, where
+	// it places each DocumentDB on the member clusters of its
+	// spec.clusterReplication instead of running a database itself.
+	FLEET_HUB_ENV = "DOCUMENTDB_FLEET_HUB"
+
+	// CNPG_NAMESPACE_ENV is the namespace the CloudNativePG operator runs in
+	// (default DEFAULT_CNPG_NAMESPACE). The preflight checks read its version
+	// from the Deployment there.
+	CNPG_NAMESPACE_ENV     = "DOCUMENTDB_CNPG_NAMESPACE"
+	DEFAULT_CNPG_NAMESPACE = "cnpg-system"
+
+	// RELEASED_PV_RETENTION_ENV, when set to a duration such as "720h", turns
+	// on the deletion of the Released PVs of deleted DocumentDB clusters once
+	// they have been retained that long (see RETAINED_UNTIL_ANNOTATION). Unset
+	// or "0" keeps them forever. RELEASED_PV_CLEANUP_DRY_RUN_ENV set to "true"
+	// only reports the PVs that would be deleted.
+	RELEASED_PV_RETENTION_ENV       = "DOCUMENTDB_RELEASED_PV_RETENTION"
+	RELEASED_PV_CLEANUP_DRY_RUN_ENV = "DOCUMENTDB_RELEASED_PV_CLEANUP_DRY_RUN"
+
+	// UNSUPPORTED_MOUNT_OPTIONS_PROVISIONERS_ENV is a comma-separated list of
+	// storage provisioners that do not support mount options, added to the
+	// local and hostpath provisioners the PV controller already skips.
+	UNSUPPORTED_MOUNT_OPTIONS_PROVISIONERS_ENV = "DOCUMENTDB_UNSUPPORTED_MOUNT_OPTIONS_PROVISIONERS"
+
+	// LOAD_BALANCER_ANNOTATIONS_ENV is a YAML map from an environment (aks,
+	// eks or gke) to the annotations added to its LoadBalancer Services, on
+	// top of the built-in ones or, with replace set, instead of them.
+	LOAD_BALANCER_ANNOTATIONS_ENV = "DOCUMENTDB_LOAD_BALANCER_ANNOTATIONS"
+
+	// SKIP_DELETION_BACKUP_CHECK_ANNOTATION set to "true" on a DocumentDB lets
+	// its deletion proceed without a recent backup.
+	SKIP_DELETION_BACKUP_CHECK_ANNOTATION = "documentdb.io/skip-deletion-backup-check"
+
+	// ADOPT_CLUSTER_ANNOTATION set to "true" on an existing CNPG Cluster lets
+	// the DocumentDB of the same name take it over instead of creating one.
+	ADOPT_CLUSTER_ANNOTATION = "documentdb.io/adopt"
+
+	// RETENTION_HOLD_ANNOTATION set to "true" on the PVC of an instance puts
+	// its volume under a legal or investigation hold: the PV is kept with the
+	// Retain reclaim policy whatever the cluster asks for, and the operator
+	// never deletes the PVC or the PV. The PV controller copies the annotation
+	// onto the PV so that the hold outlives the PVC.
+	RETENTION_HOLD_ANNOTATION = "documentdb.io/retention-hold"
+
+	// RETAINED_UNTIL_ANNOTATION records on a Released PV of a deleted
+	// DocumentDB, as an RFC 3339 timestamp, when the released PV janitor may
+	// delete it. It can be edited to keep the PV longer.
+	RETAINED_UNTIL_ANNOTATION = "documentdb.io/retained-until"
+
+	// DRY_RUN_ANNOTATION set to "true" on a DocumentDB makes the operator render
+	// the CNPG Cluster it would create or patch into a ConfigMap instead of
+	// applying it.
+	DRY_RUN_ANNOTATION = "documentdb.io/dry-run"
+
+	// ALLOW_CLONE_TO_NAMESPACES_ANNOTATION on a DocumentDB lists, comma
+	// separated, the namespaces in which it can be cloned with
+	// spec.bootstrap.clone, or "*" for every namespace. Clones in its own
+	// namespace are always allowed.
+	ALLOW_CLONE_TO_NAMESPACES_ANNOTATION = "documentdb.io/allow-clone-to-namespaces"
+
+	// MIGRATED_TO_ANNOTATION is set by the operator on the source of a
+	// completed spec.migration to the name of the DocumentDB it migrated to,
+	// whose primary the Service of the source then selects.
+	MIGRATED_TO_ANNOTATION = "documentdb.io/migrated-to"
+
+	// MEMBER_SPEC_HASH_ANNOTATION is set by the operator on the DocumentDB it
+	// creates on a member cluster from a kubeconfigSecret, to a hash of the
+	// spec it was last synced to. A DocumentDB without it is never touched.
+	MEMBER_SPEC_HASH_ANNOTATION = "documentdb.io/member-spec-hash"
+
+	// MINOR_UPGRADE_VERSION_ANNOTATION is set by a MinorUpgrade
+	// DocumentDBOpsRequest on the DocumentDB it upgrades. The cluster runs this
+	// version instead of spec.documentDBVersion for as long as it is the newer
+	// of the two, so that the upgrade leaves the spec to its owner.
+	MINOR_UPGRADE_VERSION_ANNOTATION = "documentdb.io/minor-upgrade-version"
+
+	// CREDENTIALS_ROTATED_BY_ANNOTATION is set on the credential Secret to the
+	// UID of the RotateCredentials DocumentDBOpsRequest that wrote its password,
+	// so that a retried rotation sets that password instead of a new one.
+	CREDENTIALS_ROTATED_BY_ANNOTATION = "documentdb.io/credentials-rotated-by"
+
 	// DocumentDB versioning environment variable
 	DOCUMENTDB_VERSION_ENV = "DOCUMENTDB_VERSION"
 
@@ -36,12 +246,18 @@ const (
 	// The operator requires K8s 1.35+ for ImageVolume GA support.
 	MinK8sMinorVersion = 35
 
+	// MinCNPGVersion is the minimum required CloudNativePG operator version,
+	// the first to mount extensions from image volumes.
+	MinCNPGVersion = "1.27.0"
+
 	// DEFAULT_DOCUMENTDB_IMAGE is the extension image used in ImageVolume mode.
 	DEFAULT_DOCUMENTDB_IMAGE = DOCUMENTDB_EXTENSION_IMAGE_REPO + ":0.110.0"
 	// NOTE: Keep in sync with operator/cnpg-plugins/sidecar-injector/internal/config/config.go:applyDefaults()
 	DEFAULT_GATEWAY_IMAGE                 = GATEWAY_IMAGE_REPO + ":0.110.0"
 	DEFAULT_DOCUMENTDB_CREDENTIALS_SECRET = "documentdb-credentials"
 	DEFAULT_OTEL_COLLECTOR_IMAGE          = "otel/opentelemetry-collector-contrib:0.149.0"
+	DEFAULT_MONGODB_TOOLS_IMAGE           = "mongo:8.0"
+	DEFAULT_AWS_CLI_IMAGE                 = "amazon/aws-cli:2.31.0"
 
 	// --- Sidecar resource isolation (memory carve-out) ---
 	// spec.resource.memory is the TOTAL pod envelope. The operator carves the
@@ -95,6 +311,30 @@ const (
 	PLUGIN_PARAM_OTEL_CPU_REQUEST       = "otelCpuRequest"
 	PLUGIN_PARAM_OTEL_CPU_LIMIT         = "otelCpuLimit"
 
+	// PLUGIN_PARAM_GATEWAY_RUN_AS_NAMESPACE_UID set to "true" makes the
+	// sidecar-injector plugin leave the gateway UID/GID unset so that OpenShift
+	// assigns them from the namespace range.
+	PLUGIN_PARAM_GATEWAY_RUN_AS_NAMESPACE_UID = "gatewayRunAsNamespaceUID"
+
+	// PLUGIN_PARAM_GATEWAY_SECURITY_CONTEXT carries the spec.securityContext
+	// overrides of the gateway container as a JSON-encoded SecurityContext.
+	PLUGIN_PARAM_GATEWAY_SECURITY_CONTEXT = "gatewaySecurityContext"
+
+	// PLUGIN_PARAM_GATEWAY_PROBES carries the spec.gateway.probes timings as a
+	// JSON object with optional liveness and readiness probes.
+	PLUGIN_PARAM_GATEWAY_PROBES = "gatewayProbes"
+
+	// PLUGIN_PARAM_GATEWAY_PORT carries the port the gateway listens on and
+	// is probed at, when it is not DEFAULT_GATEWAY_PORT.
+	PLUGIN_PARAM_GATEWAY_PORT = "gatewayPort"
+
+	// PLUGIN_PARAM_GATEWAY_IMAGE_PULL_POLICY and
+	// PLUGIN_PARAM_OTEL_IMAGE_PULL_POLICY carry the pull policy of the
+	// gateway and otel-collector images. The plugin defaults the gateway one
+	// to IfNotPresent.
+	PLUGIN_PARAM_GATEWAY_IMAGE_PULL_POLICY = "gatewayImagePullPolicy"
+	PLUGIN_PARAM_OTEL_IMAGE_PULL_POLICY    = "otelCollectorImagePullPolicy"
+
 	// TODO: remove these constants once change stream support is included in the official images.
 	CHANGESTREAM_DOCUMENTDB_IMAGE_REPOSITORY = "ghcr.io/wentingwu666666/documentdb-kubernetes-operator"
 	CHANGESTREAM_DOCUMENTDB_IMAGE            = CHANGESTREAM_DOCUMENTDB_IMAGE_REPOSITORY + "/documentdb-oss:16-changestream"
@@ -110,6 +350,15 @@ const (
 	LABEL_DOCUMENTDB_COMPONENT     = "documentdb.io/component"
 	FLEET_IN_USE_BY_ANNOTATION     = "networking.fleet.azure.com/service-in-use-by"
 
+	// LABEL_FLEET_PLACEMENT on a Secret or ConfigMap names the DocumentDB it
+	// is copied with to the member clusters, by Fleet on a hub or by the
+	// operator through a kubeconfigSecret.
+	LABEL_FLEET_PLACEMENT = "documentdb.io/placement"
+
+	// EXTERNAL_DNS_HOSTNAME_ANNOTATION asks external-dns to publish a DNS
+	// record for the address of a Service.
+	EXTERNAL_DNS_HOSTNAME_ANNOTATION = "external-dns.alpha.kubernetes.io/hostname"
+
 	DOCUMENTDB_SERVICE_PREFIX = "documentdb-service-"
 
 	DEFAULT_SIDECAR_INJECTOR_PLUGIN = "cnpg-i-sidecar-injector.documentdb.io"
@@ -118,6 +367,19 @@ const (
 
 	CNPG_DEFAULT_STOP_DELAY = 30
 
+	// CNPG_DEFAULT_START_DELAY is CNPG's own default startDelay, in seconds.
+	CNPG_DEFAULT_START_DELAY = 3600
+
+	// CNPG_DEFAULT_SMART_SHUTDOWN_TIMEOUT and CNPG_DEFAULT_SWITCHOVER_DELAY are
+	// CNPG's own defaults for smartShutdownTimeout and switchoverDelay, in
+	// seconds.
+	CNPG_DEFAULT_SMART_SHUTDOWN_TIMEOUT = 180
+	CNPG_DEFAULT_SWITCHOVER_DELAY       = 3600
+
+	// CNPG_MAX_STOP_DELAY is the largest spec.timeouts.stopDelay, in seconds,
+	// that the operator passes to CNPG, CNPG's own default stopDelay.
+	CNPG_MAX_STOP_DELAY = 1800
+
 	CNPG_MAX_CLUSTER_NAME_LENGTH = 50
 
 	// SQL job resource requirements and container security context
@@ -0,0 +1,115 @@
+// Copyright (c) Microsoft Corporation.
+// Licensed under the MIT License.
+
+package util
+
+import (
+	"cmp"
+	"context"
+	"fmt"
+	"strings"
+	"sync"
+
+	corev1 "k8s.io/api/core/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+// The cloud environments of spec.environment, which pick the annotations of
+// the LoadBalancer Services.
+const (
+	ENVIRONMENT_AKS = "aks"
+	ENVIRONMENT_EKS = "eks"
+	ENVIRONMENT_GKE = "gke"
+)
+
+// environmentDetectionNodes bounds the nodes DetectClusterEnvironment looks at.
+const environmentDetectionNodes = 20
+
+// openShiftNodeLabel is set on the nodes of OpenShift clusters.
+const openShiftNodeLabel = "node.openshift.io/os_id"
+
+// cloudProviderIDPrefixes map the scheme of the provider ID of a node to its
+// cloud.
+var cloudProviderIDPrefixes = []struct{ prefix, cloud string }{
+	{"azure://", ENVIRONMENT_AKS},
+	{"aws://", ENVIRONMENT_EKS},
+	{"gce://", ENVIRONMENT_GKE},
+}
+
+// cloudNodeLabels map the labels the managed Kubernetes services set on their
+// nodes to their cloud, for nodes without a provider ID.
+var cloudNodeLabels = []struct{ label, cloud string }{
+	{"kubernetes.azure.com/cluster", ENVIRONMENT_AKS},
+	{"eks.amazonaws.com/nodegroup", ENVIRONMENT_EKS},
+	{"cloud.google.com/gke-nodepool", ENVIRONMENT_GKE},
+}
+
+// ClusterEnvironment is what the operator detected of the Kubernetes cluster
+// it runs in.
+type ClusterEnvironment struct {
+	// Cloud is one of the ENVIRONMENT_ constants, or empty when unknown.
+	Cloud string
+	// OpenShift reports whether the nodes run OpenShift.
+	OpenShift bool
+}
+
+var (
+	detectedEnvironmentMu sync.RWMutex
+	detectedEnvironment   ClusterEnvironment
+)
+
+// DetectClusterEnvironment reads the environment of the Kubernetes cluster
+// from its nodes: the cloud from their provider ID or the labels of the
+// managed Kubernetes services, and OpenShift from the label of its nodes.
+func DetectClusterEnvironment(ctx context.Context, reader client.Reader) (ClusterEnvironment, error) {
+	nodes := &corev1.NodeList{}
+	if err := reader.List(ctx, nodes, client.Limit(environmentDetectionNodes)); err != nil {
+		return ClusterEnvironment{}, fmt.Errorf("failed to list the nodes: %w", err)
+	}
+	var environment ClusterEnvironment
+	for i := range nodes.Items {
+		node := &nodes.Items[i]
+		environment.Cloud = cmp.Or(environment.Cloud, nodeCloud(node))
+		if _, found := node.Labels[openShiftNodeLabel]; found {
+			environment.OpenShift = true
+		}
+	}
+	return environment, nil
+}
+
+// nodeCloud returns the cloud node runs in, or "" when unknown.
+func nodeCloud(node *corev1.Node) string {
+	for _, provider := range cloudProviderIDPrefixes {
+		if strings.HasPrefix(node.Spec.ProviderID, provider.prefix) {
+			return provider.cloud
+		}
+	}
+	for _, label := range cloudNodeLabels {
+		if _, found := node.Labels[label.label]; found {
+			return label.cloud
+		}
+	}
+	return ""
+}
+
+// SetDetectedClusterEnvironment records the environment the operator detected
+// at startup.
+func SetDetectedClusterEnvironment(environment ClusterEnvironment) {
+	detectedEnvironmentMu.Lock()
+	defer detectedEnvironmentMu.Unlock()
+	detectedEnvironment = environment
+}
+
+// DetectedClusterEnvironment returns the environment the operator detected at
+// startup, empty until then.
+func DetectedClusterEnvironment() ClusterEnvironment {
+	detectedEnvironmentMu.RLock()
+	defer detectedEnvironmentMu.RUnlock()
+	return detectedEnvironment
+}
+
+// ResolveEnvironment returns environment, as set in a spec, or the detected
+// cloud when it is not set.
+func ResolveEnvironment(environment string) string {
+	return cmp.Or(environment, DetectedClusterEnvironment().Cloud)
+}
@@ -0,0 +1,111 @@
+// Copyright (c) Microsoft Corporation.
+// Licensed under the MIT License.
+
+package util
+
+import (
+	"context"
+	"testing"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+)
+
+func TestDetectClusterEnvironment(t *testing.T) {
+	scheme := runtime.NewScheme()
+	if err := corev1.AddToScheme(scheme); err != nil {
+		t.Fatal(err)
+	}
+	node := func(name, providerID string, labels map[string]string) *corev1.Node {
+		return &corev1.Node{
+			ObjectMeta: metav1.ObjectMeta{Name: name, Labels: labels},
+			Spec:       corev1.NodeSpec{ProviderID: providerID},
+		}
+	}
+
+	tests := []struct {
+		name     string
+		nodes    []client.Object
+		expected ClusterEnvironment
+	}{
+		{
+			name:     "no nodes",
+			expected: ClusterEnvironment{},
+		},
+		{
+			name:     "AKS from the provider ID",
+			nodes:    []client.Object{node("aks-1", "azure:///subscriptions/sub/resourceGroups/rg/providers/Microsoft.Compute/virtualMachineScaleSets/vmss/virtualMachines/0", nil)},
+			expected: ClusterEnvironment{Cloud: ENVIRONMENT_AKS},
+		},
+		{
+			name:     "EKS from the provider ID",
+			nodes:    []client.Object{node("ip-10-0-0-1", "aws:///us-east-1a/i-0123456789", nil)},
+			expected: ClusterEnvironment{Cloud: ENVIRONMENT_EKS},
+		},
+		{
+			name:     "GKE from a label",
+			nodes:    []client.Object{node("gke-1", "", map[string]string{"cloud.google.com/gke-nodepool": "default-pool"})},
+			expected: ClusterEnvironment{Cloud: ENVIRONMENT_GKE},
+		},
+		{
+			name: "OpenShift on AWS",
+			nodes: []client.Object{
+				node("worker-1", "", map[string]string{openShiftNodeLabel: "rhcos"}),
+				node("worker-2", "aws:///us-east-1a/i-0123456789", map[string]string{openShiftNodeLabel: "rhcos"}),
+			},
+			expected: ClusterEnvironment{Cloud: ENVIRONMENT_EKS, OpenShift: true},
+		},
+		{
+			name:     "unknown cloud",
+			nodes:    []client.Object{node("kind-control-plane", "kind://docker/kind/kind-control-plane", nil)},
+			expected: ClusterEnvironment{},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c := fake.NewClientBuilder().WithScheme(scheme).WithObjects(tt.nodes...).Build()
+			got, err := DetectClusterEnvironment(context.Background(), c)
+			if err != nil {
+				t.Fatalf("DetectClusterEnvironment() error = %v", err)
+			}
+			if got != tt.expected {
+				t.Errorf("DetectClusterEnvironment() = %+v, want %+v", got, tt.expected)
+			}
+		})
+	}
+}
+
+func TestResolveEnvironment(t *testing.T) {
+	t.Cleanup(func() { SetDetectedClusterEnvironment(ClusterEnvironment{}) })
+
+	if got := ResolveEnvironment(""); got != "" {
+		t.Errorf("ResolveEnvironment(\"\") = %q before detection, want \"\"", got)
+	}
+	SetDetectedClusterEnvironment(ClusterEnvironment{Cloud: ENVIRONMENT_GKE})
+	if got := ResolveEnvironment(""); got != ENVIRONMENT_GKE {
+		t.Errorf("ResolveEnvironment(\"\") = %q, want %q", got, ENVIRONMENT_GKE)
+	}
+	if got := ResolveEnvironment(ENVIRONMENT_AKS); got != ENVIRONMENT_AKS {
+		t.Errorf("ResolveEnvironment(%q) = %q, want the spec to win", ENVIRONMENT_AKS, got)
+	}
+}
+
+func TestIsOpenShiftDetected(t *testing.T) {
+	t.Cleanup(func() {
+		SetOperatorSettings(nil)
+		SetDetectedClusterEnvironment(ClusterEnvironment{})
+	})
+	SetDetectedClusterEnvironment(ClusterEnvironment{OpenShift: true})
+
+	SetOperatorSettings(map[string]string{OPENSHIFT_ENV: ""})
+	if !IsOpenShift() {
+		t.Error("IsOpenShift() = false with OpenShift detected and the setting unset, want true")
+	}
+	SetOperatorSettings(map[string]string{OPENSHIFT_ENV: "false"})
+	if IsOpenShift() {
+		t.Error("IsOpenShift() = true with the setting false, want the setting to win")
+	}
+}
@@ -0,0 +1,129 @@
+// Copyright (c) Microsoft Corporation.
+// Licensed under the MIT License.
+
+package util
+
+import (
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+
+	dbpreview "github.com/documentdb/documentdb-operator/api/preview"
+)
+
+// The placement API of Fleet, used through unstructured objects so that the
+// operator does not depend on its Go module.
+var (
+	ClusterResourcePlacementGVK = schema.GroupVersionKind{
+		Group: "placement.kubernetes-fleet.io", Version: "v1beta1", Kind: "ClusterResourcePlacement",
+	}
+	ResourcePlacementGVK = schema.GroupVersionKind{
+		Group: "placement.kubernetes-fleet.io", Version: "v1beta1", Kind: "ResourcePlacement",
+	}
+)
+
+// NamespacePlacementName returns the name of the ClusterResourcePlacement that
+// creates namespace on the member clusters.
+func NamespacePlacementName(namespace string) string {
+	return "documentdb-" + namespace
+}
+
+// NamespacePlacementSpec returns the spec of the ClusterResourcePlacement of
+// namespace. It places the namespace alone on every member cluster: the
+// DocumentDBs in it are placed by their own ResourcePlacement, and removing
+// the namespace from a member would delete their volumes with it.
+func NamespacePlacementSpec(namespace string) map[string]any {
+	return map[string]any{
+		"resourceSelectors": []any{
+			map[string]any{
+				"group":          "",
+				"version":        "v1",
+				"kind":           "Namespace",
+				"name":           namespace,
+				"selectionScope": "NamespaceOnly",
+			},
+		},
+		"policy": map[string]any{
+			"placementType": "PickAll",
+		},
+	}
+}
+
+// DocumentDBPlacementSpec returns the spec of the ResourcePlacement that puts
+// documentdb on the member clusters of its spec.clusterReplication, along with
+// its credential Secret and the Secrets and ConfigMaps labelled
+// LABEL_FLEET_PLACEMENT with its name.
+func DocumentDBPlacementSpec(documentdb *dbpreview.DocumentDB) map[string]any {
+	var clusterNames []any
+	for _, member := range documentdb.Spec.ClusterReplication.ClusterList {
+		clusterNames = append(clusterNames, member.Name)
+	}
+	labelled := map[string]any{
+		"matchLabels": map[string]any{LABEL_FLEET_PLACEMENT: documentdb.Name},
+	}
+	return map[string]any{
+		"resourceSelectors": []any{
+			map[string]any{
+				"group":   dbpreview.GroupVersion.Group,
+				"version": dbpreview.GroupVersion.Version,
+				"kind":    "DocumentDB",
+				"name":    documentdb.Name,
+			},
+			map[string]any{
+				"group":   "",
+				"version": "v1",
+				"kind":    "Secret",
+				"name":    CredentialSecretName(documentdb),
+			},
+			map[string]any{
+				"group":         "",
+				"version":       "v1",
+				"kind":          "Secret",
+				"labelSelector": labelled,
+			},
+			map[string]any{
+				"group":         "",
+				"version":       "v1",
+				"kind":          "ConfigMap",
+				"labelSelector": labelled,
+			},
+		},
+		"policy": map[string]any{
+			"placementType": "PickFixed",
+			"clusterNames":  clusterNames,
+		},
+	}
+}
+
+// PlacementAvailable reports whether a placement has rolled its resources
+// out and found them available on every member cluster it selected, and
+// otherwise returns the message of the condition it is stuck on. The types
+// of the conditions are prefixed with the kind of the placement, e.g.
+// ResourcePlacementAvailable.
+func PlacementAvailable(placement *unstructured.Unstructured) (bool, string) {
+	conditions, _, _ := unstructured.NestedSlice(placement.Object, "status", "conditions")
+	generation := placement.GetGeneration()
+	message := "Waiting for Fleet to roll out the placement"
+	for _, item := range conditions {
+		condition, ok := item.(map[string]any)
+		if !ok {
+			continue
+		}
+		if observed, found, _ := unstructured.NestedInt64(condition, "observedGeneration"); found && observed != generation {
+			continue
+		}
+		status, _, _ := unstructured.NestedString(condition, "status")
+		conditionType, _, _ := unstructured.NestedString(condition, "type")
+		if status != "True" {
+			if text, _, _ := unstructured.NestedString(condition, "message"); text != "" {
+				message = text
+			} else {
+				message = conditionType + " is " + status
+			}
+			return false, message
+		}
+		if conditionType == placement.GetKind()+"Available" {
+			return true, ""
+		}
+	}
+	return false, message
+}
@@ -0,0 +1,94 @@
+// Copyright (c) Microsoft Corporation.
+// Licensed under the MIT License.
+
+package util
+
+import (
+	"testing"
+
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+
+	dbpreview "github.com/documentdb/documentdb-operator/api/preview"
+)
+
+func TestDocumentDBPlacementSpec(t *testing.T) {
+	documentdb := &dbpreview.DocumentDB{}
+	documentdb.Name = "db"
+	documentdb.Spec.ClusterReplication = &dbpreview.ClusterReplication{
+		Primary:     "east",
+		ClusterList: []dbpreview.MemberCluster{{Name: "east"}, {Name: "west"}},
+	}
+	spec := DocumentDBPlacementSpec(documentdb)
+
+	names, _, _ := unstructured.NestedStringSlice(spec, "policy", "clusterNames")
+	if len(names) != 2 || names[0] != "east" || names[1] != "west" {
+		t.Errorf("clusterNames = %v, want [east west]", names)
+	}
+	selectors, _, _ := unstructured.NestedSlice(spec, "resourceSelectors")
+	var credentials string
+	for _, item := range selectors {
+		selector := item.(map[string]any)
+		if selector["kind"] == "Secret" && selector["name"] != nil {
+			credentials = selector["name"].(string)
+		}
+	}
+	if credentials != DEFAULT_DOCUMENTDB_CREDENTIALS_SECRET {
+		t.Errorf("credential Secret = %q, want %q", credentials, DEFAULT_DOCUMENTDB_CREDENTIALS_SECRET)
+	}
+}
+
+func TestPlacementAvailable(t *testing.T) {
+	condition := func(conditionType, status string, generation int64) any {
+		return map[string]any{"type": conditionType, "status": status, "observedGeneration": generation, "message": conditionType + " message"}
+	}
+	tests := []struct {
+		name       string
+		conditions []any
+		available  bool
+		message    string
+	}{
+		{
+			name:    "no status yet",
+			message: "Waiting for Fleet to roll out the placement",
+		},
+		{
+			name: "available",
+			conditions: []any{
+				condition("ResourcePlacementScheduled", "True", 2),
+				condition("ResourcePlacementApplied", "True", 2),
+				condition("ResourcePlacementAvailable", "True", 2),
+			},
+			available: true,
+		},
+		{
+			name: "stuck applying",
+			conditions: []any{
+				condition("ResourcePlacementScheduled", "True", 2),
+				condition("ResourcePlacementApplied", "False", 2),
+			},
+			message: "ResourcePlacementApplied message",
+		},
+		{
+			name: "available for an older generation",
+			conditions: []any{
+				condition("ResourcePlacementScheduled", "True", 2),
+				condition("ResourcePlacementAvailable", "True", 1),
+			},
+			message: "Waiting for Fleet to roll out the placement",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			placement := &unstructured.Unstructured{Object: map[string]any{}}
+			placement.SetGroupVersionKind(ResourcePlacementGVK)
+			placement.SetGeneration(2)
+			if tt.conditions != nil {
+				_ = unstructured.SetNestedSlice(placement.Object, tt.conditions, "status", "conditions")
+			}
+			available, message := PlacementAvailable(placement)
+			if available != tt.available || message != tt.message {
+				t.Errorf("PlacementAvailable() = (%t, %q), want (%t, %q)", available, message, tt.available, tt.message)
+			}
+		})
+	}
+}
@@ -0,0 +1,40 @@
+// Copyright (c) Microsoft Corporation.
+// Licensed under the MIT License.
+
+package util
+
+import (
+	"fmt"
+
+	"k8s.io/utils/ptr"
+
+	dbpreview "github.com/documentdb/documentdb-operator/api/preview"
+)
+
+const (
+	istioExcludeInboundPorts  = "traffic.sidecar.istio.io/excludeInboundPorts"
+	istioExcludeOutboundPorts = "traffic.sidecar.istio.io/excludeOutboundPorts"
+	istioProxyConfig          = "proxy.istio.io/config"
+)
+
+// IstioAnnotations returns the Istio annotations of the database pods, or nil
+// when spec.istio is not set. A proxy.istio.io/config annotation of
+// spec.inheritedMetadata is left as is rather than overwritten.
+func IstioAnnotations(documentdb *dbpreview.DocumentDB) map[string]string {
+	istio := documentdb.Spec.Istio
+	if istio == nil {
+		return nil
+	}
+	annotations := map[string]string{}
+	replication := documentdb.Spec.ClusterReplication
+	meshReplication := replication != nil && replication.CrossCloudNetworkingStrategy == string(Istio)
+	if ptr.Deref(istio.ExcludeDatabasePorts, true) && !meshReplication {
+		annotations[istioExcludeInboundPorts] = fmt.Sprintf("%d,%d", DEFAULT_POSTGRES_PORT, CNPG_STATUS_PORT)
+		annotations[istioExcludeOutboundPorts] = fmt.Sprintf("%d", DEFAULT_POSTGRES_PORT)
+	}
+	_, userProxyConfig := ChildAnnotations(documentdb, nil)[istioProxyConfig]
+	if ptr.Deref(istio.HoldApplicationUntilProxyStarts, true) && !userProxyConfig {
+		annotations[istioProxyConfig] = `{"holdApplicationUntilProxyStarts": true}`
+	}
+	return annotations
+}
@@ -0,0 +1,76 @@
+// Copyright (c) Microsoft Corporation.
+// Licensed under the MIT License.
+
+package util
+
+import (
+	"maps"
+	"testing"
+
+	"k8s.io/utils/ptr"
+
+	dbpreview "github.com/documentdb/documentdb-operator/api/preview"
+)
+
+func TestIstioAnnotations(t *testing.T) {
+	holdApplication := `{"holdApplicationUntilProxyStarts": true}`
+	tests := []struct {
+		name        string
+		spec        dbpreview.DocumentDBSpec
+		expected    map[string]string
+		expectedNil bool
+	}{
+		{
+			name:        "istio not set",
+			expectedNil: true,
+		},
+		{
+			name: "defaults",
+			spec: dbpreview.DocumentDBSpec{Istio: &dbpreview.IstioConfiguration{}},
+			expected: map[string]string{
+				istioExcludeInboundPorts:  "5432,8000",
+				istioExcludeOutboundPorts: "5432",
+				istioProxyConfig:          holdApplication,
+			},
+		},
+		{
+			name: "all disabled",
+			spec: dbpreview.DocumentDBSpec{Istio: &dbpreview.IstioConfiguration{
+				ExcludeDatabasePorts:            ptr.To(false),
+				HoldApplicationUntilProxyStarts: ptr.To(false),
+			}},
+			expected: map[string]string{},
+		},
+		{
+			name: "replication through the mesh keeps the database ports",
+			spec: dbpreview.DocumentDBSpec{
+				Istio:              &dbpreview.IstioConfiguration{},
+				ClusterReplication: &dbpreview.ClusterReplication{CrossCloudNetworkingStrategy: "Istio"},
+			},
+			expected: map[string]string{istioProxyConfig: holdApplication},
+		},
+		{
+			name: "proxy config of inheritedMetadata is kept",
+			spec: dbpreview.DocumentDBSpec{
+				Istio:             &dbpreview.IstioConfiguration{ExcludeDatabasePorts: ptr.To(false)},
+				InheritedMetadata: &dbpreview.InheritedMetadata{Annotations: map[string]string{istioProxyConfig: "{}"}},
+			},
+			expected: map[string]string{},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			annotations := IstioAnnotations(&dbpreview.DocumentDB{Spec: tt.spec})
+			if tt.expectedNil {
+				if annotations != nil {
+					t.Errorf("IstioAnnotations() = %v, expected nil", annotations)
+				}
+				return
+			}
+			if !maps.Equal(annotations, tt.expected) {
+				t.Errorf("IstioAnnotations() = %v, expected %v", annotations, tt.expected)
+			}
+		})
+	}
+}
@@ -0,0 +1,50 @@
+// Copyright (c) Microsoft Corporation.
+// Licensed under the MIT License.
+
+package util
+
+import (
+	"maps"
+
+	dbpreview "github.com/documentdb/documentdb-operator/api/preview"
+)
+
+// ChildLabels returns the labels of an object the operator creates for
+// documentdb: the labels of spec.inheritedMetadata, overlaid with
+// spec.costLabels and then with labels, so that the labels the operator relies
+// on for selection and cleanup can never be overridden by the user. It returns
+// nil when all are empty.
+func ChildLabels(documentdb *dbpreview.DocumentDB, labels map[string]string) map[string]string {
+	var inherited map[string]string
+	if documentdb.Spec.InheritedMetadata != nil {
+		inherited = documentdb.Spec.InheritedMetadata.Labels
+	}
+	return mergeMetadata(inherited, documentdb.Spec.CostLabels, labels)
+}
+
+// ChildAnnotations returns the annotations of an object the operator creates
+// for documentdb: the annotations of spec.inheritedMetadata overlaid with
+// annotations. It returns nil when both are empty.
+func ChildAnnotations(documentdb *dbpreview.DocumentDB, annotations map[string]string) map[string]string {
+	var inherited map[string]string
+	if documentdb.Spec.InheritedMetadata != nil {
+		inherited = documentdb.Spec.InheritedMetadata.Annotations
+	}
+	return mergeMetadata(inherited, annotations)
+}
+
+// mergeMetadata returns a new map holding the entries of all sources, later
+// sources taking precedence, or nil when they are all empty.
+func mergeMetadata(sources ...map[string]string) map[string]string {
+	var merged map[string]string
+	for _, source := range sources {
+		if len(source) == 0 {
+			continue
+		}
+		if merged == nil {
+			merged = map[string]string{}
+		}
+		maps.Copy(merged, source)
+	}
+	return merged
+}
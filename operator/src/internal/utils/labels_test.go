@@ -0,0 +1,76 @@
+// Copyright (c) Microsoft Corporation.
+// Licensed under the MIT License.
+
+package util
+
+import (
+	"reflect"
+	"testing"
+
+	dbpreview "github.com/documentdb/documentdb-operator/api/preview"
+)
+
+func TestChildLabels(t *testing.T) {
+	tests := []struct {
+		name       string
+		inherited  *dbpreview.InheritedMetadata
+		costLabels map[string]string
+		labels     map[string]string
+		expected   map[string]string
+	}{
+		{
+			name: "no labels",
+		},
+		{
+			name:     "operator labels only",
+			labels:   map[string]string{LABEL_DOCUMENTDB_NAME: "db"},
+			expected: map[string]string{LABEL_DOCUMENTDB_NAME: "db"},
+		},
+		{
+			name:       "cost labels win over inherited labels",
+			inherited:  &dbpreview.InheritedMetadata{Labels: map[string]string{"team": "other", "tier": "gold"}},
+			costLabels: map[string]string{"team": "payments"},
+			expected:   map[string]string{"team": "payments", "tier": "gold"},
+		},
+		{
+			name:       "operator labels win over user labels",
+			inherited:  &dbpreview.InheritedMetadata{Labels: map[string]string{LABEL_DOCUMENTDB_NAME: "other"}},
+			costLabels: map[string]string{"team": "payments", LABEL_DOCUMENTDB_NAME: "other"},
+			labels:     map[string]string{LABEL_DOCUMENTDB_NAME: "db"},
+			expected:   map[string]string{"team": "payments", LABEL_DOCUMENTDB_NAME: "db"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			documentdb := &dbpreview.DocumentDB{Spec: dbpreview.DocumentDBSpec{
+				InheritedMetadata: tt.inherited,
+				CostLabels:        tt.costLabels,
+			}}
+			got := ChildLabels(documentdb, tt.labels)
+			if !reflect.DeepEqual(got, tt.expected) {
+				t.Errorf("ChildLabels() = %v, want %v", got, tt.expected)
+			}
+			if _, ok := tt.labels["team"]; ok {
+				t.Error("ChildLabels() modified the labels it was given")
+			}
+		})
+	}
+}
+
+func TestChildAnnotations(t *testing.T) {
+	documentdb := &dbpreview.DocumentDB{Spec: dbpreview.DocumentDBSpec{
+		InheritedMetadata: &dbpreview.InheritedMetadata{
+			Annotations: map[string]string{"sidecar.istio.io/inject": "true", "owner": "user"},
+		},
+	}}
+
+	got := ChildAnnotations(documentdb, map[string]string{"owner": "operator"})
+	expected := map[string]string{"sidecar.istio.io/inject": "true", "owner": "operator"}
+	if !reflect.DeepEqual(got, expected) {
+		t.Errorf("ChildAnnotations() = %v, want %v", got, expected)
+	}
+	if got := ChildAnnotations(&dbpreview.DocumentDB{}, nil); got != nil {
+		t.Errorf("ChildAnnotations() = %v, want nil", got)
+	}
+}
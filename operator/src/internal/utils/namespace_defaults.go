@@ -0,0 +1,87 @@
+// Copyright (c) Microsoft Corporation.
+// Licensed under the MIT License.
+
+package util
+
+import (
+	"context"
+	"fmt"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/log"
+
+	dbpreview "github.com/documentdb/documentdb-operator/api/preview"
+)
+
+// ApplyNamespaceDefaults fills the storage class, the service type and the
+// backup retention of documentdb that neither it nor its DocumentDBClusterClass
+// sets, in memory, from the NAMESPACE_DEFAULTS_CONFIG_MAP ConfigMap of its
+// namespace. It is called once the cluster class is resolved. Storage class
+// and service type fall back to the operator settings of the same keys; the
+// backup retention fallback is applied by the backup controller. Invalid
+// values are logged and ignored.
+//
+// The defaults are read once and recorded in status.namespaceDefaults, which
+// the caller saves; the recorded values are used from then on, so that later
+// changes to the defaults only apply to new DocumentDBs.
+func ApplyNamespaceDefaults(ctx context.Context, c client.Reader, documentdb *dbpreview.DocumentDB) error {
+	if documentdb.Status.NamespaceDefaults == nil {
+		defaults, err := readNamespaceDefaults(ctx, c, documentdb.Namespace)
+		if err != nil {
+			return err
+		}
+		documentdb.Status.NamespaceDefaults = defaults
+	}
+	defaults := documentdb.Status.NamespaceDefaults
+
+	spec := &documentdb.Spec
+	if spec.Resource.Storage.StorageClass == "" {
+		spec.Resource.Storage.StorageClass = defaults.StorageClass
+	}
+	if spec.ExposeViaService.ServiceType == "" {
+		spec.ExposeViaService.ServiceType = defaults.ServiceType
+	}
+	if spec.Backup == nil && defaults.BackupRetentionDays > 0 {
+		spec.Backup = &dbpreview.BackupConfiguration{RetentionDays: defaults.BackupRetentionDays}
+	}
+	return nil
+}
+
+// readNamespaceDefaults returns the defaults of namespace, from its
+// NAMESPACE_DEFAULTS_CONFIG_MAP ConfigMap and the operator settings.
+func readNamespaceDefaults(ctx context.Context, c client.Reader, namespace string) (*dbpreview.NamespaceDefaultsStatus, error) {
+	logger := log.FromContext(ctx)
+
+	configMap := &corev1.ConfigMap{}
+	key := client.ObjectKey{Name: NAMESPACE_DEFAULTS_CONFIG_MAP, Namespace: namespace}
+	if err := c.Get(ctx, key, configMap); err != nil && !errors.IsNotFound(err) {
+		return nil, fmt.Errorf("failed to get namespace defaults ConfigMap: %w", err)
+	}
+	setting := func(name string) string {
+		if value := configMap.Data[name]; value != "" {
+			return value
+		}
+		return GetOperatorSetting(name)
+	}
+
+	defaults := &dbpreview.NamespaceDefaultsStatus{StorageClass: setting(DEFAULT_STORAGE_CLASS_ENV)}
+	switch serviceType := setting(DEFAULT_SERVICE_TYPE_ENV); serviceType {
+	case "":
+	case string(corev1.ServiceTypeLoadBalancer), string(corev1.ServiceTypeClusterIP):
+		defaults.ServiceType = serviceType
+	default:
+		logger.Info("Ignoring invalid default service type", "name", DEFAULT_SERVICE_TYPE_ENV, "value", serviceType)
+	}
+	if value := configMap.Data[DEFAULT_BACKUP_RETENTION_DAYS_ENV]; value != "" {
+		days, err := parseRetentionDays(value)
+		if err != nil {
+			logger.Error(err, "Ignoring invalid namespace default backup retention days",
+				"name", DEFAULT_BACKUP_RETENTION_DAYS_ENV, "value", value)
+		} else {
+			defaults.BackupRetentionDays = days
+		}
+	}
+	return defaults, nil
+}
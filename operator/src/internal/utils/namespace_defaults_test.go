@@ -0,0 +1,158 @@
+// Copyright (c) Microsoft Corporation.
+// Licensed under the MIT License.
+
+package util
+
+import (
+	"context"
+	"testing"
+
+	dbpreview "github.com/documentdb/documentdb-operator/api/preview"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+)
+
+func TestApplyNamespaceDefaults(t *testing.T) {
+	scheme := runtime.NewScheme()
+	if err := corev1.AddToScheme(scheme); err != nil {
+		t.Fatal(err)
+	}
+	if err := dbpreview.AddToScheme(scheme); err != nil {
+		t.Fatal(err)
+	}
+	class := &dbpreview.DocumentDBClusterClass{
+		ObjectMeta: metav1.ObjectMeta{Name: "standard"},
+		Spec: dbpreview.DocumentDBClusterClassSpec{
+			Sizes:        []dbpreview.ClusterClassSize{{Name: "small", PvcSize: "10Gi"}},
+			StorageClass: "premium",
+			Backup:       &dbpreview.BackupConfiguration{RetentionDays: 14},
+		},
+	}
+
+	tests := []struct {
+		name              string
+		namespaceDefaults map[string]string
+		operatorSettings  map[string]string
+		recorded          *dbpreview.NamespaceDefaultsStatus
+		spec              dbpreview.DocumentDBSpec
+		storageClass      string
+		serviceType       string
+		retentionDays     int
+	}{
+		{
+			name: "no defaults leave the spec unchanged",
+		},
+		{
+			name: "namespace defaults fill unset fields",
+			namespaceDefaults: map[string]string{
+				DEFAULT_STORAGE_CLASS_ENV:         "fast",
+				DEFAULT_SERVICE_TYPE_ENV:          "LoadBalancer",
+				DEFAULT_BACKUP_RETENTION_DAYS_ENV: "7",
+			},
+			storageClass:  "fast",
+			serviceType:   "LoadBalancer",
+			retentionDays: 7,
+		},
+		{
+			name: "spec values win over namespace defaults",
+			namespaceDefaults: map[string]string{
+				DEFAULT_STORAGE_CLASS_ENV:         "fast",
+				DEFAULT_SERVICE_TYPE_ENV:          "LoadBalancer",
+				DEFAULT_BACKUP_RETENTION_DAYS_ENV: "7",
+			},
+			spec: dbpreview.DocumentDBSpec{
+				Resource:         dbpreview.Resource{Storage: dbpreview.StorageConfiguration{StorageClass: "standard"}},
+				ExposeViaService: dbpreview.ExposeViaService{ServiceType: "ClusterIP"},
+				Backup:           &dbpreview.BackupConfiguration{RetentionDays: 90},
+			},
+			storageClass:  "standard",
+			serviceType:   "ClusterIP",
+			retentionDays: 90,
+		},
+		{
+			name:              "namespace defaults win over operator settings",
+			namespaceDefaults: map[string]string{DEFAULT_STORAGE_CLASS_ENV: "fast"},
+			operatorSettings: map[string]string{
+				DEFAULT_STORAGE_CLASS_ENV: "slow",
+				DEFAULT_SERVICE_TYPE_ENV:  "ClusterIP",
+			},
+			storageClass: "fast",
+			serviceType:  "ClusterIP",
+		},
+		{
+			name: "cluster class values win over namespace defaults",
+			namespaceDefaults: map[string]string{
+				DEFAULT_STORAGE_CLASS_ENV:         "fast",
+				DEFAULT_SERVICE_TYPE_ENV:          "LoadBalancer",
+				DEFAULT_BACKUP_RETENTION_DAYS_ENV: "7",
+			},
+			spec:          dbpreview.DocumentDBSpec{ClassRef: &dbpreview.ClusterClassReference{Name: "standard"}},
+			storageClass:  "premium",
+			serviceType:   "LoadBalancer",
+			retentionDays: 14,
+		},
+		{
+			name: "recorded defaults win over the current ones",
+			namespaceDefaults: map[string]string{
+				DEFAULT_STORAGE_CLASS_ENV:         "fast",
+				DEFAULT_SERVICE_TYPE_ENV:          "LoadBalancer",
+				DEFAULT_BACKUP_RETENTION_DAYS_ENV: "7",
+			},
+			recorded:    &dbpreview.NamespaceDefaultsStatus{ServiceType: "ClusterIP"},
+			serviceType: "ClusterIP",
+		},
+		{
+			name: "invalid values are ignored",
+			namespaceDefaults: map[string]string{
+				DEFAULT_SERVICE_TYPE_ENV:          "NodePort",
+				DEFAULT_BACKUP_RETENTION_DAYS_ENV: "1000",
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Cleanup(func() { SetOperatorSettings(nil) })
+			SetOperatorSettings(tt.operatorSettings)
+
+			builder := fake.NewClientBuilder().WithScheme(scheme).WithObjects(class)
+			if tt.namespaceDefaults != nil {
+				builder = builder.WithObjects(&corev1.ConfigMap{
+					ObjectMeta: metav1.ObjectMeta{Name: NAMESPACE_DEFAULTS_CONFIG_MAP, Namespace: "team-a"},
+					Data:       tt.namespaceDefaults,
+				})
+			}
+			documentdb := &dbpreview.DocumentDB{
+				ObjectMeta: metav1.ObjectMeta{Name: "db", Namespace: "team-a"},
+				Spec:       tt.spec,
+				Status:     dbpreview.DocumentDBStatus{NamespaceDefaults: tt.recorded.DeepCopy()},
+			}
+
+			c := builder.Build()
+			if err := ResolveClusterClass(context.Background(), c, documentdb); err != nil {
+				t.Fatalf("ResolveClusterClass() error = %v", err)
+			}
+			if err := ApplyNamespaceDefaults(context.Background(), c, documentdb); err != nil {
+				t.Fatalf("ApplyNamespaceDefaults() error = %v", err)
+			}
+			if got := documentdb.Spec.Resource.Storage.StorageClass; got != tt.storageClass {
+				t.Errorf("storage class = %q, want %q", got, tt.storageClass)
+			}
+			if got := documentdb.Spec.ExposeViaService.ServiceType; got != tt.serviceType {
+				t.Errorf("service type = %q, want %q", got, tt.serviceType)
+			}
+			retentionDays := 0
+			if documentdb.Spec.Backup != nil {
+				retentionDays = documentdb.Spec.Backup.RetentionDays
+			}
+			if retentionDays != tt.retentionDays {
+				t.Errorf("retention days = %d, want %d", retentionDays, tt.retentionDays)
+			}
+			if documentdb.Status.NamespaceDefaults == nil {
+				t.Error("namespace defaults are not recorded in the status")
+			}
+		})
+	}
+}
@@ -0,0 +1,448 @@
+// Copyright (c) Microsoft Corporation.
+// Licensed under the MIT License.
+
+package util
+
+import (
+	"context"
+	"fmt"
+	"maps"
+	"os"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/resource"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/apimachinery/pkg/util/validation"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/log"
+	"sigs.k8s.io/yaml"
+)
+
+// Operator-level settings are read from the operator ConfigMap first and fall
+// back to the operator's environment. The ConfigMap uses the same keys as the
+// environment variables (e.g. DOCUMENTDB_VERSION), so any setting that the
+// Helm chart passes as an env var can be changed at runtime by editing the
+// ConfigMap, without restarting the operator.
+var (
+	operatorSettingsMu sync.RWMutex
+	operatorSettings   map[string]string
+)
+
+// GetOperatorSetting returns the value of an operator-level setting. A non-empty
+// value in the operator ConfigMap takes precedence over the environment variable
+// of the same name.
+func GetOperatorSetting(key string) string {
+	operatorSettingsMu.RLock()
+	value := operatorSettings[key]
+	operatorSettingsMu.RUnlock()
+	if value != "" {
+		return value
+	}
+	return os.Getenv(key)
+}
+
+// SetOperatorSettings replaces the ConfigMap-provided settings with data and
+// reports whether the effective settings changed. Passing nil clears them, so
+// every setting falls back to the environment again.
+func SetOperatorSettings(data map[string]string) bool {
+	operatorSettingsMu.Lock()
+	defer operatorSettingsMu.Unlock()
+	if maps.Equal(operatorSettings, data) {
+		return false
+	}
+	operatorSettings = maps.Clone(data)
+	return true
+}
+
+// OperatorNamespace returns the namespace the operator runs in, as injected by
+// the Helm chart through the downward API.
+func OperatorNamespace() string {
+	return os.Getenv(OPERATOR_NAMESPACE_ENV)
+}
+
+// OperatorConfigMapName returns the name of the ConfigMap holding the
+// hot-reloadable operator settings.
+func OperatorConfigMapName() string {
+	if name := os.Getenv(OPERATOR_CONFIG_MAP_ENV); name != "" {
+		return name
+	}
+	return DEFAULT_OPERATOR_CONFIG_MAP
+}
+
+// LoadOperatorSettings reads the operator ConfigMap once and installs its data.
+// A missing ConfigMap is not an error: the operator then runs on its
+// environment alone. It is meant to be called at startup, before the manager
+// cache is available, so that the first reconcile already sees the settings.
+func LoadOperatorSettings(ctx context.Context, c client.Reader, namespace, name string) error {
+	configMap := &corev1.ConfigMap{}
+	if err := c.Get(ctx, types.NamespacedName{Name: name, Namespace: namespace}, configMap); err != nil {
+		if errors.IsNotFound(err) {
+			SetOperatorSettings(nil)
+			return nil
+		}
+		return err
+	}
+	SetOperatorSettings(configMap.Data)
+	return nil
+}
+
+// GetOtelCollectorImage returns the OpenTelemetry Collector image used for the
+// monitoring sidecar.
+func GetOtelCollectorImage() string {
+	if image := GetOperatorSetting(OTEL_COLLECTOR_IMAGE_ENV); image != "" {
+		return image
+	}
+	return DEFAULT_OTEL_COLLECTOR_IMAGE
+}
+
+// GetMongoDBToolsImage returns the image with the MongoDB database tools that
+// the import and export Jobs run.
+func GetMongoDBToolsImage() string {
+	if image := GetOperatorSetting(MONGODB_TOOLS_IMAGE_ENV); image != "" {
+		return image
+	}
+	return DEFAULT_MONGODB_TOOLS_IMAGE
+}
+
+// GetAWSCLIImage returns the image that uploads the exports to the object
+// store.
+func GetAWSCLIImage() string {
+	if image := GetOperatorSetting(AWS_CLI_IMAGE_ENV); image != "" {
+		return image
+	}
+	return DEFAULT_AWS_CLI_IMAGE
+}
+
+// GetDefaultBackupRetentionDays returns the retention period applied to backups
+// of clusters that do not configure spec.backup. Values outside the range
+// accepted by spec.backup.retentionDays are ignored.
+func GetDefaultBackupRetentionDays() int {
+	value := GetOperatorSetting(DEFAULT_BACKUP_RETENTION_DAYS_ENV)
+	if value == "" {
+		return DEFAULT_BACKUP_RETENTION_DAYS
+	}
+	days, err := parseRetentionDays(value)
+	if err != nil {
+		log.FromContext(context.Background()).Error(err, "Invalid default backup retention days, using built-in default",
+			"name", DEFAULT_BACKUP_RETENTION_DAYS_ENV, "value", value)
+		return DEFAULT_BACKUP_RETENTION_DAYS
+	}
+	return days
+}
+
+// parseRetentionDays parses a retention period in days within the range
+// accepted by spec.backup.retentionDays.
+func parseRetentionDays(value string) (int, error) {
+	days, err := strconv.Atoi(value)
+	if err != nil {
+		return 0, err
+	}
+	if days < 1 || days > 365 {
+		return 0, fmt.Errorf("retention days %d out of range 1-365", days)
+	}
+	return days, nil
+}
+
+// GetMaxClustersPerNamespace returns the maximum number of DocumentDB clusters
+// allowed in a namespace. Zero means no limit.
+func GetMaxClustersPerNamespace() int {
+	value := GetOperatorSetting(MAX_CLUSTERS_PER_NAMESPACE_ENV)
+	if value == "" {
+		return 0
+	}
+	maxClusters, err := strconv.Atoi(value)
+	if err != nil || maxClusters < 0 {
+		log.FromContext(context.Background()).Error(err, "Invalid maximum clusters per namespace, disabling the limit",
+			"name", MAX_CLUSTERS_PER_NAMESPACE_ENV, "value", value)
+		return 0
+	}
+	return maxClusters
+}
+
+// GetMaxStoragePerNamespace returns the maximum total storage that the DocumentDB
+// clusters of a namespace may request, or nil when there is no limit.
+func GetMaxStoragePerNamespace() *resource.Quantity {
+	value := GetOperatorSetting(MAX_STORAGE_PER_NAMESPACE_ENV)
+	if value == "" {
+		return nil
+	}
+	maxStorage, err := resource.ParseQuantity(value)
+	if err != nil || maxStorage.Sign() < 0 {
+		log.FromContext(context.Background()).Error(err, "Invalid maximum storage per namespace, disabling the limit",
+			"name", MAX_STORAGE_PER_NAMESPACE_ENV, "value", value)
+		return nil
+	}
+	if maxStorage.IsZero() {
+		return nil
+	}
+	return &maxStorage
+}
+
+// GetMinPVCSize returns the smallest PVC size allowed for a new DocumentDB
+// cluster, or nil when there is no minimum.
+func GetMinPVCSize() *resource.Quantity {
+	value := GetOperatorSetting(MIN_PVC_SIZE_ENV)
+	if value == "" {
+		return nil
+	}
+	minSize, err := resource.ParseQuantity(value)
+	if err != nil || minSize.Sign() < 0 {
+		log.FromContext(context.Background()).Error(err, "Invalid minimum PVC size, disabling the minimum",
+			"name", MIN_PVC_SIZE_ENV, "value", value)
+		return nil
+	}
+	if minSize.IsZero() {
+		return nil
+	}
+	return &minSize
+}
+
+// GetDeletionBackupMaxAge returns how recent a completed backup must be before a
+// cluster whose volumes are deleted with it can be deleted. Zero disables the check.
+func GetDeletionBackupMaxAge() time.Duration {
+	value := GetOperatorSetting(DELETION_BACKUP_MAX_AGE_ENV)
+	if value == "" {
+		return 0
+	}
+	maxAge, err := time.ParseDuration(value)
+	if err != nil || maxAge < 0 {
+		log.FromContext(context.Background()).Error(err, "Invalid deletion backup max age, disabling the check",
+			"name", DELETION_BACKUP_MAX_AGE_ENV, "value", value)
+		return 0
+	}
+	return maxAge
+}
+
+// GetDriftCheckInterval returns how often DocumentDBs are re-reconciled to detect
+// CNPG Cluster drift. Zero disables the periodic check.
+func GetDriftCheckInterval() time.Duration {
+	value := GetOperatorSetting(DRIFT_CHECK_INTERVAL_ENV)
+	if value == "" {
+		return DEFAULT_DRIFT_CHECK_INTERVAL
+	}
+	interval, err := time.ParseDuration(value)
+	if err != nil || interval < 0 {
+		log.FromContext(context.Background()).Error(err, "Invalid drift check interval, using built-in default",
+			"name", DRIFT_CHECK_INTERVAL_ENV, "value", value)
+		return DEFAULT_DRIFT_CHECK_INTERVAL
+	}
+	return interval
+}
+
+// GetHealthPollInterval returns how often the primaries of the clusters are
+// polled in the background. Zero turns the poller off.
+func GetHealthPollInterval() time.Duration {
+	value := GetOperatorSetting(HEALTH_POLL_INTERVAL_ENV)
+	if value == "" {
+		return DEFAULT_HEALTH_POLL_INTERVAL
+	}
+	interval, err := time.ParseDuration(value)
+	if err != nil || interval < 0 {
+		log.FromContext(context.Background()).Error(err, "Invalid health poll interval, using built-in default",
+			"name", HEALTH_POLL_INTERVAL_ENV, "value", value)
+		return DEFAULT_HEALTH_POLL_INTERVAL
+	}
+	return interval
+}
+
+// GetPodExecQPS returns how many commands per second the operator may start in
+// the database pods. Zero or less removes the limit.
+func GetPodExecQPS() float32 {
+	value := GetOperatorSetting(POD_EXEC_QPS_ENV)
+	if value == "" {
+		return DEFAULT_POD_EXEC_QPS
+	}
+	qps, err := strconv.ParseFloat(value, 32)
+	if err != nil {
+		log.FromContext(context.Background()).Error(err, "Invalid pod exec QPS, using built-in default",
+			"name", POD_EXEC_QPS_ENV, "value", value)
+		return DEFAULT_POD_EXEC_QPS
+	}
+	return float32(qps)
+}
+
+// GetPodExecBurst returns how many commands the operator may start in the
+// database pods at once, above the rate of GetPodExecQPS.
+func GetPodExecBurst() int {
+	value := GetOperatorSetting(POD_EXEC_BURST_ENV)
+	if value == "" {
+		return DEFAULT_POD_EXEC_BURST
+	}
+	burst, err := strconv.Atoi(value)
+	if err != nil || burst < 1 {
+		log.FromContext(context.Background()).Error(err, "Invalid pod exec burst, using built-in default",
+			"name", POD_EXEC_BURST_ENV, "value", value)
+		return DEFAULT_POD_EXEC_BURST
+	}
+	return burst
+}
+
+// GetPVRecoveryTimeout returns how long a recovery from a retained PV may take
+// before its temporary PVC is deleted.
+func GetPVRecoveryTimeout() time.Duration {
+	value := GetOperatorSetting(PV_RECOVERY_TIMEOUT_ENV)
+	if value == "" {
+		return DEFAULT_PV_RECOVERY_TIMEOUT
+	}
+	timeout, err := time.ParseDuration(value)
+	if err != nil || timeout <= 0 {
+		log.FromContext(context.Background()).Error(err, "Invalid PV recovery timeout, using built-in default",
+			"name", PV_RECOVERY_TIMEOUT_ENV, "value", value)
+		return DEFAULT_PV_RECOVERY_TIMEOUT
+	}
+	return timeout
+}
+
+// GetReleasedPVRetention returns how long the Released PVs of deleted
+// DocumentDB clusters are kept before they are deleted. Zero keeps them forever.
+func GetReleasedPVRetention() time.Duration {
+	value := GetOperatorSetting(RELEASED_PV_RETENTION_ENV)
+	if value == "" {
+		return 0
+	}
+	retention, err := time.ParseDuration(value)
+	if err != nil || retention < 0 {
+		log.FromContext(context.Background()).Error(err, "Invalid released PV retention, keeping Released PVs",
+			"name", RELEASED_PV_RETENTION_ENV, "value", value)
+		return 0
+	}
+	return retention
+}
+
+// IsReleasedPVCleanupDryRun reports whether expired Released PVs are only
+// reported instead of deleted.
+func IsReleasedPVCleanupDryRun() bool {
+	value := GetOperatorSetting(RELEASED_PV_CLEANUP_DRY_RUN_ENV)
+	if value == "" {
+		return false
+	}
+	dryRun, err := strconv.ParseBool(value)
+	if err != nil {
+		// Err on the side of keeping the volumes
+		log.FromContext(context.Background()).Error(err, "Invalid released PV cleanup dry run, enabling it",
+			"name", RELEASED_PV_CLEANUP_DRY_RUN_ENV, "value", value)
+		return true
+	}
+	return dryRun
+}
+
+// IsFullDriftReconciliation reports whether the operator reverts drift in every
+// CNPG Cluster field it renders rather than only in the targeted ones.
+func IsFullDriftReconciliation() bool {
+	value := GetOperatorSetting(DRIFT_RECONCILIATION_ENV)
+	switch {
+	case value == "" || strings.EqualFold(value, DRIFT_RECONCILIATION_TARGETED):
+		return false
+	case strings.EqualFold(value, DRIFT_RECONCILIATION_FULL):
+		return true
+	}
+	log.FromContext(context.Background()).Info("Invalid drift reconciliation mode, using "+DRIFT_RECONCILIATION_TARGETED,
+		"name", DRIFT_RECONCILIATION_ENV, "value", value)
+	return false
+}
+
+// IsOpenShift reports whether the operator runs in OpenShift mode, in which the
+// clusters it renders must be admissible under the restricted-v2 SCC. Unless
+// set, the mode follows whether the operator detected OpenShift at startup.
+func IsOpenShift() bool {
+	value := GetOperatorSetting(OPENSHIFT_ENV)
+	if value == "" {
+		return DetectedClusterEnvironment().OpenShift
+	}
+	enabled, err := strconv.ParseBool(value)
+	if err != nil {
+		log.FromContext(context.Background()).Error(err, "Invalid OpenShift mode, disabling it",
+			"name", OPENSHIFT_ENV, "value", value)
+		return false
+	}
+	return enabled
+}
+
+// IsFleetHub reports whether the operator runs on a Fleet hub, where it places
+// the DocumentDBs on their member clusters rather than reconciling them.
+func IsFleetHub() bool {
+	value := GetOperatorSetting(FLEET_HUB_ENV)
+	if value == "" {
+		return false
+	}
+	enabled, err := strconv.ParseBool(value)
+	if err != nil {
+		log.FromContext(context.Background()).Error(err, "Invalid Fleet hub mode, disabling it",
+			"name", FLEET_HUB_ENV, "value", value)
+		return false
+	}
+	return enabled
+}
+
+// LoadBalancerAnnotationOverride is the entry of an environment in
+// DOCUMENTDB_LOAD_BALANCER_ANNOTATIONS.
+type LoadBalancerAnnotationOverride struct {
+	// Replace drops the built-in annotations of the environment.
+	Replace bool `json:"replace,omitempty"`
+	// Annotations are added to the LoadBalancer Services, overriding the
+	// built-in annotations of the same name.
+	Annotations map[string]string `json:"annotations,omitempty"`
+}
+
+// GetLoadBalancerAnnotationOverrides returns the overrides of the built-in
+// LoadBalancer annotations, by environment. An invalid setting is ignored as a
+// whole, so that the built-in annotations apply.
+func GetLoadBalancerAnnotationOverrides() map[string]LoadBalancerAnnotationOverride {
+	value := GetOperatorSetting(LOAD_BALANCER_ANNOTATIONS_ENV)
+	if value == "" {
+		return nil
+	}
+	overrides, err := parseLoadBalancerAnnotationOverrides(value)
+	if err != nil {
+		log.FromContext(context.Background()).Error(err, "Invalid LoadBalancer annotations, using the built-in ones",
+			"name", LOAD_BALANCER_ANNOTATIONS_ENV)
+		return nil
+	}
+	return overrides
+}
+
+func parseLoadBalancerAnnotationOverrides(value string) (map[string]LoadBalancerAnnotationOverride, error) {
+	var overrides map[string]LoadBalancerAnnotationOverride
+	if err := yaml.UnmarshalStrict([]byte(value), &overrides); err != nil {
+		return nil, err
+	}
+	for environment, override := range overrides {
+		switch environment {
+		case ENVIRONMENT_AKS, ENVIRONMENT_EKS, ENVIRONMENT_GKE:
+		default:
+			return nil, fmt.Errorf("unknown environment %q, expected %s, %s or %s", environment, ENVIRONMENT_AKS, ENVIRONMENT_EKS, ENVIRONMENT_GKE)
+		}
+		for key := range override.Annotations {
+			if errs := validation.IsQualifiedName(key); len(errs) > 0 {
+				return nil, fmt.Errorf("invalid annotation %q of environment %q: %s", key, environment, strings.Join(errs, "; "))
+			}
+		}
+	}
+	return overrides, nil
+}
+
+// GetUnsupportedMountOptionsProvisioners returns the storage provisioners
+// configured as not supporting mount options, in addition to the built-in
+// ones.
+func GetUnsupportedMountOptionsProvisioners() []string {
+	var provisioners []string
+	for _, provisioner := range strings.Split(GetOperatorSetting(UNSUPPORTED_MOUNT_OPTIONS_PROVISIONERS_ENV), ",") {
+		if provisioner = strings.TrimSpace(provisioner); provisioner != "" {
+			provisioners = append(provisioners, provisioner)
+		}
+	}
+	return provisioners
+}
+
+// GetCNPGNamespace returns the namespace the CloudNativePG operator runs in.
+func GetCNPGNamespace() string {
+	if namespace := GetOperatorSetting(CNPG_NAMESPACE_ENV); namespace != "" {
+		return namespace
+	}
+	return DEFAULT_CNPG_NAMESPACE
+}
@@ -0,0 +1,498 @@
+// Copyright (c) Microsoft Corporation.
+// Licensed under the MIT License.
+
+package util
+
+import (
+	"context"
+	"reflect"
+	"slices"
+	"testing"
+	"time"
+
+	dbpreview "github.com/documentdb/documentdb-operator/api/preview"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+)
+
+func TestGetOperatorSetting(t *testing.T) {
+	t.Cleanup(func() { SetOperatorSettings(nil) })
+
+	t.Setenv(DOCUMENTDB_VERSION_ENV, "0.200.0")
+	if got := GetOperatorSetting(DOCUMENTDB_VERSION_ENV); got != "0.200.0" {
+		t.Errorf("without ConfigMap data got %q, want env value %q", got, "0.200.0")
+	}
+
+	SetOperatorSettings(map[string]string{DOCUMENTDB_VERSION_ENV: "0.300.0"})
+	if got := GetOperatorSetting(DOCUMENTDB_VERSION_ENV); got != "0.300.0" {
+		t.Errorf("ConfigMap value should take precedence, got %q", got)
+	}
+
+	SetOperatorSettings(map[string]string{DOCUMENTDB_VERSION_ENV: ""})
+	if got := GetOperatorSetting(DOCUMENTDB_VERSION_ENV); got != "0.200.0" {
+		t.Errorf("empty ConfigMap value should fall back to env, got %q", got)
+	}
+}
+
+func TestSetOperatorSettingsReportsChanges(t *testing.T) {
+	t.Cleanup(func() { SetOperatorSettings(nil) })
+	SetOperatorSettings(nil)
+
+	data := map[string]string{DOCUMENTDB_VERSION_ENV: "0.300.0"}
+	if !SetOperatorSettings(data) {
+		t.Error("expected change when settings are first installed")
+	}
+	if SetOperatorSettings(map[string]string{DOCUMENTDB_VERSION_ENV: "0.300.0"}) {
+		t.Error("expected no change for identical settings")
+	}
+
+	// Mutating the caller's map must not leak into the stored settings.
+	data[DOCUMENTDB_VERSION_ENV] = "0.400.0"
+	if got := GetOperatorSetting(DOCUMENTDB_VERSION_ENV); got != "0.300.0" {
+		t.Errorf("stored settings were mutated through caller map, got %q", got)
+	}
+
+	if !SetOperatorSettings(nil) {
+		t.Error("expected change when settings are cleared")
+	}
+}
+
+func TestHotReloadedVersionResolvesImages(t *testing.T) {
+	t.Cleanup(func() { SetOperatorSettings(nil) })
+	SetOperatorSettings(map[string]string{DOCUMENTDB_VERSION_ENV: "0.300.0"})
+
+	db := &dbpreview.DocumentDB{}
+	if got, want := GetDocumentDBImageForInstance(db), DOCUMENTDB_EXTENSION_IMAGE_REPO+":0.300.0"; got != want {
+		t.Errorf("GetDocumentDBImageForInstance() = %q, want %q", got, want)
+	}
+	if got, want := GetGatewayImageForDocumentDB(db), GATEWAY_IMAGE_REPO+":0.300.0"; got != want {
+		t.Errorf("GetGatewayImageForDocumentDB() = %q, want %q", got, want)
+	}
+}
+
+func TestOperatorConfigMapName(t *testing.T) {
+	t.Setenv(OPERATOR_CONFIG_MAP_ENV, "")
+	if got := OperatorConfigMapName(); got != DEFAULT_OPERATOR_CONFIG_MAP {
+		t.Errorf("got %q, want default %q", got, DEFAULT_OPERATOR_CONFIG_MAP)
+	}
+	t.Setenv(OPERATOR_CONFIG_MAP_ENV, "custom-config")
+	if got := OperatorConfigMapName(); got != "custom-config" {
+		t.Errorf("got %q, want %q", got, "custom-config")
+	}
+}
+
+func TestLoadOperatorSettings(t *testing.T) {
+	t.Cleanup(func() { SetOperatorSettings(nil) })
+	scheme := runtime.NewScheme()
+	if err := corev1.AddToScheme(scheme); err != nil {
+		t.Fatal(err)
+	}
+
+	t.Run("missing ConfigMap clears settings", func(t *testing.T) {
+		SetOperatorSettings(map[string]string{OTEL_COLLECTOR_IMAGE_ENV: "stale"})
+		c := fake.NewClientBuilder().WithScheme(scheme).Build()
+		if err := LoadOperatorSettings(context.Background(), c, "documentdb-operator", DEFAULT_OPERATOR_CONFIG_MAP); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if got := GetOtelCollectorImage(); got != DEFAULT_OTEL_COLLECTOR_IMAGE {
+			t.Errorf("got %q, want default %q", got, DEFAULT_OTEL_COLLECTOR_IMAGE)
+		}
+	})
+
+	t.Run("existing ConfigMap installs settings", func(t *testing.T) {
+		cm := &corev1.ConfigMap{
+			ObjectMeta: metav1.ObjectMeta{Name: DEFAULT_OPERATOR_CONFIG_MAP, Namespace: "documentdb-operator"},
+			Data:       map[string]string{OTEL_COLLECTOR_IMAGE_ENV: "registry.example.com/otel:1.0"},
+		}
+		c := fake.NewClientBuilder().WithScheme(scheme).WithObjects(cm).Build()
+		if err := LoadOperatorSettings(context.Background(), c, "documentdb-operator", DEFAULT_OPERATOR_CONFIG_MAP); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if got := GetOtelCollectorImage(); got != "registry.example.com/otel:1.0" {
+			t.Errorf("got %q, want ConfigMap image", got)
+		}
+	})
+}
+
+func TestGetDefaultBackupRetentionDays(t *testing.T) {
+	t.Cleanup(func() { SetOperatorSettings(nil) })
+	tests := []struct {
+		name     string
+		value    string
+		expected int
+	}{
+		{name: "unset uses built-in default", value: "", expected: DEFAULT_BACKUP_RETENTION_DAYS},
+		{name: "valid value", value: "7", expected: 7},
+		{name: "non-numeric falls back", value: "seven", expected: DEFAULT_BACKUP_RETENTION_DAYS},
+		{name: "zero falls back", value: "0", expected: DEFAULT_BACKUP_RETENTION_DAYS},
+		{name: "above maximum falls back", value: "366", expected: DEFAULT_BACKUP_RETENTION_DAYS},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			SetOperatorSettings(map[string]string{DEFAULT_BACKUP_RETENTION_DAYS_ENV: tt.value})
+			if got := GetDefaultBackupRetentionDays(); got != tt.expected {
+				t.Errorf("GetDefaultBackupRetentionDays() = %d, want %d", got, tt.expected)
+			}
+		})
+	}
+}
+
+func TestGetMaxClustersPerNamespace(t *testing.T) {
+	t.Cleanup(func() { SetOperatorSettings(nil) })
+	tests := []struct {
+		name     string
+		value    string
+		expected int
+	}{
+		{name: "unset means no limit", value: "", expected: 0},
+		{name: "valid limit", value: "5", expected: 5},
+		{name: "non-numeric disables the limit", value: "five", expected: 0},
+		{name: "negative disables the limit", value: "-1", expected: 0},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			SetOperatorSettings(map[string]string{MAX_CLUSTERS_PER_NAMESPACE_ENV: tt.value})
+			if got := GetMaxClustersPerNamespace(); got != tt.expected {
+				t.Errorf("GetMaxClustersPerNamespace() = %d, want %d", got, tt.expected)
+			}
+		})
+	}
+}
+
+func TestGetMaxStoragePerNamespace(t *testing.T) {
+	t.Cleanup(func() { SetOperatorSettings(nil) })
+	tests := []struct {
+		name     string
+		value    string
+		expected string
+	}{
+		{name: "unset means no limit", value: "", expected: ""},
+		{name: "valid limit", value: "1Ti", expected: "1Ti"},
+		{name: "zero means no limit", value: "0", expected: ""},
+		{name: "invalid quantity disables the limit", value: "a lot", expected: ""},
+		{name: "negative quantity disables the limit", value: "-1Gi", expected: ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			SetOperatorSettings(map[string]string{MAX_STORAGE_PER_NAMESPACE_ENV: tt.value})
+			got := ""
+			if maxStorage := GetMaxStoragePerNamespace(); maxStorage != nil {
+				got = maxStorage.String()
+			}
+			if got != tt.expected {
+				t.Errorf("GetMaxStoragePerNamespace() = %q, want %q", got, tt.expected)
+			}
+		})
+	}
+}
+
+func TestGetMinPVCSize(t *testing.T) {
+	t.Cleanup(func() { SetOperatorSettings(nil) })
+	tests := []struct {
+		name     string
+		value    string
+		expected string
+	}{
+		{name: "unset means no minimum", value: "", expected: ""},
+		{name: "valid minimum", value: "10Gi", expected: "10Gi"},
+		{name: "zero means no minimum", value: "0", expected: ""},
+		{name: "invalid quantity disables the minimum", value: "ten", expected: ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			SetOperatorSettings(map[string]string{MIN_PVC_SIZE_ENV: tt.value})
+			got := ""
+			if minSize := GetMinPVCSize(); minSize != nil {
+				got = minSize.String()
+			}
+			if got != tt.expected {
+				t.Errorf("GetMinPVCSize() = %q, want %q", got, tt.expected)
+			}
+		})
+	}
+}
+
+func TestGetDeletionBackupMaxAge(t *testing.T) {
+	t.Cleanup(func() { SetOperatorSettings(nil) })
+	tests := []struct {
+		name     string
+		value    string
+		expected time.Duration
+	}{
+		{name: "unset disables the check", value: "", expected: 0},
+		{name: "valid duration", value: "24h", expected: 24 * time.Hour},
+		{name: "invalid duration disables the check", value: "a day", expected: 0},
+		{name: "negative duration disables the check", value: "-1h", expected: 0},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			SetOperatorSettings(map[string]string{DELETION_BACKUP_MAX_AGE_ENV: tt.value})
+			if got := GetDeletionBackupMaxAge(); got != tt.expected {
+				t.Errorf("GetDeletionBackupMaxAge() = %s, want %s", got, tt.expected)
+			}
+		})
+	}
+}
+
+func TestGetDriftCheckInterval(t *testing.T) {
+	t.Cleanup(func() { SetOperatorSettings(nil) })
+	tests := []struct {
+		name     string
+		value    string
+		expected time.Duration
+	}{
+		{name: "unset uses the default", value: "", expected: DEFAULT_DRIFT_CHECK_INTERVAL},
+		{name: "valid duration", value: "30m", expected: 30 * time.Minute},
+		{name: "zero disables the check", value: "0", expected: 0},
+		{name: "invalid duration uses the default", value: "often", expected: DEFAULT_DRIFT_CHECK_INTERVAL},
+		{name: "negative duration uses the default", value: "-5m", expected: DEFAULT_DRIFT_CHECK_INTERVAL},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			SetOperatorSettings(map[string]string{DRIFT_CHECK_INTERVAL_ENV: tt.value})
+			if got := GetDriftCheckInterval(); got != tt.expected {
+				t.Errorf("GetDriftCheckInterval() = %s, want %s", got, tt.expected)
+			}
+		})
+	}
+}
+
+func TestGetHealthPollInterval(t *testing.T) {
+	t.Cleanup(func() { SetOperatorSettings(nil) })
+	tests := []struct {
+		name     string
+		value    string
+		expected time.Duration
+	}{
+		{name: "unset uses the default", value: "", expected: DEFAULT_HEALTH_POLL_INTERVAL},
+		{name: "valid duration", value: "30s", expected: 30 * time.Second},
+		{name: "zero turns the poller off", value: "0", expected: 0},
+		{name: "invalid duration uses the default", value: "soon", expected: DEFAULT_HEALTH_POLL_INTERVAL},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			SetOperatorSettings(map[string]string{HEALTH_POLL_INTERVAL_ENV: tt.value})
+			if got := GetHealthPollInterval(); got != tt.expected {
+				t.Errorf("GetHealthPollInterval() = %s, want %s", got, tt.expected)
+			}
+		})
+	}
+}
+
+func TestGetPodExecRate(t *testing.T) {
+	t.Cleanup(func() { SetOperatorSettings(nil) })
+	tests := []struct {
+		name          string
+		qps           string
+		burst         string
+		expectedQPS   float32
+		expectedBurst int
+	}{
+		{name: "unset uses the defaults", expectedQPS: DEFAULT_POD_EXEC_QPS, expectedBurst: DEFAULT_POD_EXEC_BURST},
+		{name: "valid values", qps: "2.5", burst: "4", expectedQPS: 2.5, expectedBurst: 4},
+		{name: "zero QPS removes the limit", qps: "0", expectedQPS: 0, expectedBurst: DEFAULT_POD_EXEC_BURST},
+		{name: "invalid values use the defaults", qps: "fast", burst: "0", expectedQPS: DEFAULT_POD_EXEC_QPS, expectedBurst: DEFAULT_POD_EXEC_BURST},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			SetOperatorSettings(map[string]string{POD_EXEC_QPS_ENV: tt.qps, POD_EXEC_BURST_ENV: tt.burst})
+			if got := GetPodExecQPS(); got != tt.expectedQPS {
+				t.Errorf("GetPodExecQPS() = %v, want %v", got, tt.expectedQPS)
+			}
+			if got := GetPodExecBurst(); got != tt.expectedBurst {
+				t.Errorf("GetPodExecBurst() = %d, want %d", got, tt.expectedBurst)
+			}
+		})
+	}
+}
+
+func TestIsFullDriftReconciliation(t *testing.T) {
+	t.Cleanup(func() { SetOperatorSettings(nil) })
+	tests := []struct {
+		value    string
+		expected bool
+	}{
+		{value: "", expected: false},
+		{value: "Targeted", expected: false},
+		{value: "Full", expected: true},
+		{value: "full", expected: true},
+		{value: "everything", expected: false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.value, func(t *testing.T) {
+			SetOperatorSettings(map[string]string{DRIFT_RECONCILIATION_ENV: tt.value})
+			if got := IsFullDriftReconciliation(); got != tt.expected {
+				t.Errorf("IsFullDriftReconciliation() = %t, want %t", got, tt.expected)
+			}
+		})
+	}
+}
+
+func TestGetPVRecoveryTimeout(t *testing.T) {
+	t.Cleanup(func() { SetOperatorSettings(nil) })
+	tests := []struct {
+		name     string
+		value    string
+		expected time.Duration
+	}{
+		{name: "unset uses the default", value: "", expected: DEFAULT_PV_RECOVERY_TIMEOUT},
+		{name: "valid duration", value: "6h", expected: 6 * time.Hour},
+		{name: "zero uses the default", value: "0", expected: DEFAULT_PV_RECOVERY_TIMEOUT},
+		{name: "invalid duration uses the default", value: "forever", expected: DEFAULT_PV_RECOVERY_TIMEOUT},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			SetOperatorSettings(map[string]string{PV_RECOVERY_TIMEOUT_ENV: tt.value})
+			if got := GetPVRecoveryTimeout(); got != tt.expected {
+				t.Errorf("GetPVRecoveryTimeout() = %s, want %s", got, tt.expected)
+			}
+		})
+	}
+}
+
+func TestGetReleasedPVRetention(t *testing.T) {
+	t.Cleanup(func() { SetOperatorSettings(nil) })
+	tests := []struct {
+		name     string
+		value    string
+		expected time.Duration
+	}{
+		{name: "unset keeps Released PVs", value: "", expected: 0},
+		{name: "valid duration", value: "720h", expected: 720 * time.Hour},
+		{name: "negative duration keeps Released PVs", value: "-1h", expected: 0},
+		{name: "invalid duration keeps Released PVs", value: "a month", expected: 0},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			SetOperatorSettings(map[string]string{RELEASED_PV_RETENTION_ENV: tt.value})
+			if got := GetReleasedPVRetention(); got != tt.expected {
+				t.Errorf("GetReleasedPVRetention() = %s, want %s", got, tt.expected)
+			}
+		})
+	}
+}
+
+func TestIsReleasedPVCleanupDryRun(t *testing.T) {
+	t.Cleanup(func() { SetOperatorSettings(nil) })
+	tests := []struct {
+		value    string
+		expected bool
+	}{
+		{value: "", expected: false},
+		{value: "true", expected: true},
+		{value: "false", expected: false},
+		{value: "maybe", expected: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.value, func(t *testing.T) {
+			SetOperatorSettings(map[string]string{RELEASED_PV_CLEANUP_DRY_RUN_ENV: tt.value})
+			if got := IsReleasedPVCleanupDryRun(); got != tt.expected {
+				t.Errorf("IsReleasedPVCleanupDryRun() = %t, want %t", got, tt.expected)
+			}
+		})
+	}
+}
+
+func TestGetUnsupportedMountOptionsProvisioners(t *testing.T) {
+	t.Cleanup(func() { SetOperatorSettings(nil) })
+	tests := []struct {
+		value    string
+		expected []string
+	}{
+		{value: "", expected: nil},
+		{value: "example.com/nfs", expected: []string{"example.com/nfs"}},
+		{value: " example.com/nfs , csi.example.com,,", expected: []string{"example.com/nfs", "csi.example.com"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.value, func(t *testing.T) {
+			SetOperatorSettings(map[string]string{UNSUPPORTED_MOUNT_OPTIONS_PROVISIONERS_ENV: tt.value})
+			if got := GetUnsupportedMountOptionsProvisioners(); !slices.Equal(got, tt.expected) {
+				t.Errorf("GetUnsupportedMountOptionsProvisioners() = %v, want %v", got, tt.expected)
+			}
+		})
+	}
+}
+
+func TestGetLoadBalancerAnnotationOverrides(t *testing.T) {
+	t.Cleanup(func() { SetOperatorSettings(nil) })
+	tests := []struct {
+		name     string
+		value    string
+		expected map[string]LoadBalancerAnnotationOverride
+	}{
+		{name: "unset"},
+		{
+			name: "extend and replace",
+			value: `
+eks:
+  annotations:
+    service.beta.kubernetes.io/aws-load-balancer-scheme: internal
+aks:
+  replace: true
+  annotations:
+    service.beta.kubernetes.io/azure-load-balancer-internal: "true"
+`,
+			expected: map[string]LoadBalancerAnnotationOverride{
+				ENVIRONMENT_EKS: {Annotations: map[string]string{"service.beta.kubernetes.io/aws-load-balancer-scheme": "internal"}},
+				ENVIRONMENT_AKS: {Replace: true, Annotations: map[string]string{"service.beta.kubernetes.io/azure-load-balancer-internal": "true"}},
+			},
+		},
+		{name: "unknown environment", value: `openstack: {annotations: {a: b}}`},
+		{name: "unknown field", value: `gke: {annotation: {a: b}}`},
+		{name: "invalid annotation", value: `gke: {annotations: {"not an annotation": b}}`},
+		{name: "not YAML", value: `gke: [`},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			SetOperatorSettings(map[string]string{LOAD_BALANCER_ANNOTATIONS_ENV: tt.value})
+			if got := GetLoadBalancerAnnotationOverrides(); !reflect.DeepEqual(got, tt.expected) {
+				t.Errorf("GetLoadBalancerAnnotationOverrides() = %v, want %v", got, tt.expected)
+			}
+		})
+	}
+}
+
+func TestIsOpenShift(t *testing.T) {
+	t.Cleanup(func() { SetOperatorSettings(nil) })
+	tests := []struct {
+		value    string
+		expected bool
+	}{
+		{value: "", expected: false},
+		{value: "true", expected: true},
+		{value: "1", expected: true},
+		{value: "false", expected: false},
+		{value: "yes please", expected: false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.value, func(t *testing.T) {
+			SetOperatorSettings(map[string]string{OPENSHIFT_ENV: tt.value})
+			if got := IsOpenShift(); got != tt.expected {
+				t.Errorf("IsOpenShift() = %t, want %t", got, tt.expected)
+			}
+		})
+	}
+}
+
+func TestIsFleetHub(t *testing.T) {
+	t.Cleanup(func() { SetOperatorSettings(nil) })
+	tests := []struct {
+		value    string
+		expected bool
+	}{
+		{value: "", expected: false},
+		{value: "true", expected: true},
+		{value: "false", expected: false},
+		{value: "hub", expected: false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.value, func(t *testing.T) {
+			SetOperatorSettings(map[string]string{FLEET_HUB_ENV: tt.value})
+			if got := IsFleetHub(); got != tt.expected {
+				t.Errorf("IsFleetHub() = %t, want %t", got, tt.expected)
+			}
+		})
+	}
+}
@@ -0,0 +1,271 @@
+// Copyright (c) Microsoft Corporation.
+// Licensed under the MIT License.
+
+package util
+
+import (
+	"bytes"
+	"context"
+	"crypto/tls"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"net/url"
+	"strings"
+	"sync"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	httpstreamspdy "k8s.io/apimachinery/pkg/util/httpstream/spdy"
+	"k8s.io/client-go/kubernetes"
+	"k8s.io/client-go/kubernetes/scheme"
+	"k8s.io/client-go/rest"
+	"k8s.io/client-go/tools/remotecommand"
+	"k8s.io/client-go/util/flowcontrol"
+)
+
+const (
+	// DefaultPodExecTimeout bounds an attempt of a command whose context has
+	// no deadline of its own.
+	DefaultPodExecTimeout = 5 * time.Minute
+	// DefaultPodExecRetries is the number of times a command that could not
+	// be started is attempted again.
+	DefaultPodExecRetries = 2
+	// DefaultPodExecMaxOutputBytes bounds the standard output and the
+	// standard error of a command.
+	DefaultPodExecMaxOutputBytes = 16 << 20
+)
+
+// podExecRetryDelay is the delay before the first retry of a command, which
+// grows with each retry.
+var podExecRetryDelay = time.Second
+
+// ErrPodExecOutputTooLarge is returned by PodExecRunner.Exec for a command
+// whose output exceeds MaxOutputBytes. The output is returned truncated.
+var ErrPodExecOutputTooLarge = errors.New("command output too large")
+
+// PodExecRunner runs commands in the containers of pods through the exec
+// subresource. It is shared by the controllers that run commands in the
+// database. A command is passed as its arguments, never through a shell.
+type PodExecRunner struct {
+	Config    *rest.Config
+	Clientset kubernetes.Interface
+
+	// Timeout bounds each attempt of a command whose context has no deadline.
+	// Zero leaves it unbounded.
+	Timeout time.Duration
+	// Retries is the number of times a command is attempted again when it
+	// could not be started, e.g. when the API server is unavailable. A
+	// command that started is never run again.
+	Retries int
+	// MaxOutputBytes bounds the standard output and the standard error kept
+	// from a command. Zero keeps them whole.
+	MaxOutputBytes int
+
+	// QPS and Burst limit the rate at which commands are started, across every
+	// controller sharing the runner. A QPS of zero or less leaves it
+	// unlimited. Retries are limited along with the first attempts.
+	QPS   float32
+	Burst int
+
+	limiterMu    sync.Mutex
+	limiter      flowcontrol.RateLimiter
+	limiterQPS   float32
+	limiterBurst int
+
+	// The TLS configuration of the API server is built once and shared by the
+	// connections of the commands. A connection is not: the SPDY round
+	// tripper holds the one it upgraded.
+	tlsOnce   sync.Once
+	tlsConfig *tls.Config
+	tlsErr    error
+
+	// stream runs a single attempt of a command. Defaults to streamSPDY.
+	stream func(ctx context.Context, pod *corev1.Pod, container string, cmd []string, stdin io.Reader, stdout, stderr io.Writer) error
+}
+
+// NewPodExecRunner returns a PodExecRunner with the default limits, whose
+// rate follows the pod exec operator settings.
+func NewPodExecRunner(config *rest.Config, clientset kubernetes.Interface) *PodExecRunner {
+	return &PodExecRunner{
+		Config:         config,
+		Clientset:      clientset,
+		Timeout:        DefaultPodExecTimeout,
+		Retries:        DefaultPodExecRetries,
+		MaxOutputBytes: DefaultPodExecMaxOutputBytes,
+		QPS:            -1,
+	}
+}
+
+// Exec runs cmd in the given container of pod, with stdin as its standard
+// input when it is not nil, and returns its standard output and error.
+func (r *PodExecRunner) Exec(ctx context.Context, pod *corev1.Pod, container string, cmd []string, stdin io.Reader) (string, string, error) {
+	if len(cmd) == 0 || cmd[0] == "" {
+		return "", "", errors.New("no command to run")
+	}
+	for i, arg := range cmd {
+		if strings.ContainsRune(arg, 0) {
+			return "", "", fmt.Errorf("argument %d of %s contains a NUL byte", i, cmd[0])
+		}
+	}
+
+	for attempt := 0; ; attempt++ {
+		if limiter := r.rateLimiter(); limiter != nil {
+			if err := limiter.Wait(ctx); err != nil {
+				return "", "", fmt.Errorf("waiting to run %s: %w", cmd[0], err)
+			}
+		}
+		stdout, stderr, err := r.execOnce(ctx, pod, container, cmd, stdin)
+		if err == nil || attempt >= r.Retries || !podExecNotStarted(err) {
+			return stdout, stderr, err
+		}
+		select {
+		case <-ctx.Done():
+			return stdout, stderr, err
+		case <-time.After(time.Duration(attempt+1) * podExecRetryDelay):
+		}
+	}
+}
+
+// rateLimiter returns the limiter of the commands, nil when they are not
+// limited. A negative QPS reads the rate from the operator settings on each
+// call, so that a change of them applies without a restart.
+func (r *PodExecRunner) rateLimiter() flowcontrol.RateLimiter {
+	qps, burst := r.QPS, r.Burst
+	if qps < 0 {
+		qps, burst = GetPodExecQPS(), GetPodExecBurst()
+	}
+	if qps <= 0 {
+		return nil
+	}
+	if burst < 1 {
+		burst = 1
+	}
+
+	r.limiterMu.Lock()
+	defer r.limiterMu.Unlock()
+	if r.limiter == nil || r.limiterQPS != qps || r.limiterBurst != burst {
+		r.limiter = flowcontrol.NewTokenBucketRateLimiter(qps, burst)
+		r.limiterQPS, r.limiterBurst = qps, burst
+	}
+	return r.limiter
+}
+
+func (r *PodExecRunner) execOnce(ctx context.Context, pod *corev1.Pod, container string, cmd []string, stdin io.Reader) (string, string, error) {
+	if _, found := ctx.Deadline(); !found && r.Timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, r.Timeout)
+		defer cancel()
+	}
+	stream := r.stream
+	if stream == nil {
+		stream = r.streamSPDY
+	}
+
+	stdout := &limitedBuffer{limit: r.MaxOutputBytes}
+	stderr := &limitedBuffer{limit: r.MaxOutputBytes}
+	err := stream(ctx, pod, container, cmd, stdin, stdout, stderr)
+	if err == nil && (stdout.truncated || stderr.truncated) {
+		err = fmt.Errorf("%w: %s wrote more than %d bytes", ErrPodExecOutputTooLarge, cmd[0], r.MaxOutputBytes)
+	}
+	return stdout.String(), stderr.String(), err
+}
+
+func (r *PodExecRunner) streamSPDY(ctx context.Context, pod *corev1.Pod, container string, cmd []string, stdin io.Reader, stdout, stderr io.Writer) error {
+	req := r.Clientset.CoreV1().RESTClient().Post().
+		Resource("pods").
+		Name(pod.Name).
+		Namespace(pod.Namespace).
+		SubResource("exec").
+		VersionedParams(&corev1.PodExecOptions{
+			Container: container,
+			Command:   cmd,
+			Stdin:     stdin != nil,
+			Stdout:    true,
+			Stderr:    true,
+			TTY:       false,
+		}, scheme.ParameterCodec)
+
+	exec, err := r.newExecutor(req.URL())
+	if err != nil {
+		return fmt.Errorf("failed to create executor: %w", err)
+	}
+	return exec.StreamWithContext(ctx, remotecommand.StreamOptions{
+		Stdin:  stdin,
+		Stdout: stdout,
+		Stderr: stderr,
+	})
+}
+
+// newExecutor returns an executor of the exec request at execURL, on a new
+// connection sharing the TLS configuration of the runner.
+func (r *PodExecRunner) newExecutor(execURL *url.URL) (remotecommand.Executor, error) {
+	r.tlsOnce.Do(func() {
+		r.tlsConfig, r.tlsErr = rest.TLSConfigFor(r.Config)
+	})
+	if r.tlsErr != nil {
+		return nil, r.tlsErr
+	}
+	proxy := http.ProxyFromEnvironment
+	if r.Config.Proxy != nil {
+		proxy = r.Config.Proxy
+	}
+	upgrader, err := httpstreamspdy.NewRoundTripperWithConfig(httpstreamspdy.RoundTripperConfig{
+		TLS:        r.tlsConfig,
+		Proxier:    proxy,
+		PingPeriod: 5 * time.Second,
+	})
+	if err != nil {
+		return nil, err
+	}
+	wrapper, err := rest.HTTPWrappersForConfig(r.Config, upgrader)
+	if err != nil {
+		return nil, err
+	}
+	return remotecommand.NewSPDYExecutorForTransports(wrapper, upgrader, "POST", execURL)
+}
+
+// podExecNotStarted reports whether err shows that the command never reached
+// the container: the API server could not be dialled, or it turned the exec
+// request down for a reason that can go away.
+func podExecNotStarted(err error) bool {
+	if errors.Is(err, context.Canceled) || errors.Is(err, context.DeadlineExceeded) {
+		return false
+	}
+	var opErr *net.OpError
+	if errors.As(err, &opErr) && opErr.Op == "dial" {
+		return true
+	}
+	return apierrors.IsServiceUnavailable(err) || apierrors.IsTooManyRequests(err) ||
+		apierrors.IsInternalError(err) || apierrors.IsServerTimeout(err)
+}
+
+// limitedBuffer keeps the first limit bytes written to it, and drops the
+// rest so that the command writing them is not blocked. The buffer is not
+// embedded, so that io.Copy cannot write to it past the limit.
+type limitedBuffer struct {
+	buffer    bytes.Buffer
+	limit     int
+	truncated bool
+}
+
+func (b *limitedBuffer) Write(p []byte) (int, error) {
+	if b.limit <= 0 {
+		return b.buffer.Write(p)
+	}
+	if room := b.limit - b.buffer.Len(); room < len(p) {
+		b.truncated = true
+		if room > 0 {
+			b.buffer.Write(p[:room])
+		}
+		return len(p), nil
+	}
+	return b.buffer.Write(p)
+}
+
+func (b *limitedBuffer) String() string {
+	return b.buffer.String()
+}
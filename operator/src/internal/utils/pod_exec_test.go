@@ -0,0 +1,198 @@
+// Copyright (c) Microsoft Corporation.
+// Licensed under the MIT License.
+
+package util
+
+import (
+	"context"
+	"errors"
+	"io"
+	"net"
+	"strings"
+	"testing"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/client-go/util/exec"
+)
+
+func TestPodExecRunnerExec(t *testing.T) {
+	podExecRetryDelay = 0
+	t.Cleanup(func() { podExecRetryDelay = time.Second })
+
+	dialErr := &net.OpError{Op: "dial", Net: "tcp", Err: errors.New("connection refused")}
+	tests := []struct {
+		name         string
+		cmd          []string
+		results      []error
+		output       string
+		wantAttempts int
+		wantErr      string
+	}{
+		{
+			name:         "succeeds",
+			results:      []error{nil},
+			output:       "ok",
+			wantAttempts: 1,
+		},
+		{
+			name:         "retries a command the API server could not start",
+			results:      []error{dialErr, apierrors.NewServiceUnavailable("busy"), nil},
+			output:       "ok",
+			wantAttempts: 3,
+		},
+		{
+			name:         "gives up after the retries",
+			results:      []error{dialErr, dialErr, dialErr, nil},
+			wantAttempts: 3,
+			wantErr:      "connection refused",
+		},
+		{
+			name:         "never runs a failed command again",
+			results:      []error{exec.CodeExitError{Err: errors.New("exit status 1"), Code: 1}, nil},
+			wantAttempts: 1,
+			wantErr:      "exit status 1",
+		},
+		{
+			name:         "limits the output",
+			results:      []error{nil},
+			output:       strings.Repeat("x", 20),
+			wantAttempts: 1,
+			wantErr:      "command output too large",
+		},
+		{
+			name:    "rejects an argument with a NUL byte",
+			cmd:     []string{"psql", "-c", "SELECT 1\x00; DROP TABLE t"},
+			wantErr: "NUL byte",
+		},
+		{
+			name:    "rejects an empty command",
+			cmd:     []string{},
+			wantErr: "no command",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			attempts := 0
+			runner := &PodExecRunner{
+				Timeout:        DefaultPodExecTimeout,
+				Retries:        2,
+				MaxOutputBytes: 16,
+				stream: func(ctx context.Context, pod *corev1.Pod, container string, cmd []string, stdin io.Reader, stdout, stderr io.Writer) error {
+					if _, found := ctx.Deadline(); !found {
+						t.Error("attempt runs without a deadline")
+					}
+					err := tt.results[attempts]
+					attempts++
+					if err == nil {
+						_, _ = io.WriteString(stdout, tt.output)
+					}
+					return err
+				},
+			}
+			cmd := tt.cmd
+			if cmd == nil {
+				cmd = []string{"psql", "-c", "SELECT 1"}
+			}
+
+			stdout, _, err := runner.Exec(context.Background(), &corev1.Pod{}, "postgres", cmd, nil)
+			if tt.wantErr == "" && err != nil {
+				t.Fatalf("Exec() error = %v", err)
+			}
+			if tt.wantErr != "" && (err == nil || !strings.Contains(err.Error(), tt.wantErr)) {
+				t.Fatalf("Exec() error = %v, want %q", err, tt.wantErr)
+			}
+			if attempts != tt.wantAttempts {
+				t.Errorf("attempts = %d, want %d", attempts, tt.wantAttempts)
+			}
+			if tt.wantErr == "" && stdout != tt.output {
+				t.Errorf("stdout = %q, want %q", stdout, tt.output)
+			}
+			if len(stdout) > runner.MaxOutputBytes {
+				t.Errorf("stdout has %d bytes, want at most %d", len(stdout), runner.MaxOutputBytes)
+			}
+		})
+	}
+}
+
+func TestPodExecRunnerKeepsTheDeadlineOfTheContext(t *testing.T) {
+	var deadline time.Time
+	runner := &PodExecRunner{
+		Timeout: DefaultPodExecTimeout,
+		stream: func(ctx context.Context, pod *corev1.Pod, container string, cmd []string, stdin io.Reader, stdout, stderr io.Writer) error {
+			deadline, _ = ctx.Deadline()
+			return nil
+		},
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 2*time.Hour)
+	defer cancel()
+	if _, _, err := runner.Exec(ctx, &corev1.Pod{}, "postgres", []string{"psql", "-c", "VACUUM FULL;"}, nil); err != nil {
+		t.Fatalf("Exec() error = %v", err)
+	}
+	if remaining := time.Until(deadline); remaining < time.Hour {
+		t.Errorf("the command runs for %s, want the 2h of its context", remaining)
+	}
+}
+
+func TestPodExecRunnerRateLimit(t *testing.T) {
+	t.Cleanup(func() { SetOperatorSettings(nil) })
+	attempts := 0
+	runner := &PodExecRunner{
+		QPS:   0.001,
+		Burst: 1,
+		stream: func(context.Context, *corev1.Pod, string, []string, io.Reader, io.Writer, io.Writer) error {
+			attempts++
+			return nil
+		},
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+	defer cancel()
+	cmd := []string{"psql", "-c", "SELECT 1"}
+
+	if _, _, err := runner.Exec(ctx, &corev1.Pod{}, "postgres", cmd, nil); err != nil {
+		t.Fatalf("Exec() error = %v", err)
+	}
+	if _, _, err := runner.Exec(ctx, &corev1.Pod{}, "postgres", cmd, nil); err == nil {
+		t.Fatal("Exec() past the burst succeeded, want it to wait beyond the deadline")
+	}
+	if attempts != 1 {
+		t.Errorf("attempts = %d, want 1", attempts)
+	}
+
+	// A runner reading the operator settings follows their changes.
+	runner.QPS = -1
+	SetOperatorSettings(map[string]string{POD_EXEC_QPS_ENV: "0"})
+	if limiter := runner.rateLimiter(); limiter != nil {
+		t.Errorf("rateLimiter() = %v with a QPS of 0, want nil", limiter)
+	}
+	SetOperatorSettings(map[string]string{POD_EXEC_QPS_ENV: "20", POD_EXEC_BURST_ENV: "3"})
+	if limiter := runner.rateLimiter(); limiter == nil || limiter.QPS() != 20 {
+		t.Errorf("rateLimiter() = %v, want a limit of 20 QPS", limiter)
+	}
+}
+
+func TestQuoteSQL(t *testing.T) {
+	tests := []struct {
+		name  string
+		quote func(string) string
+		value string
+		want  string
+	}{
+		{name: "identifier", quote: QuoteIdentifier, value: "app", want: `"app"`},
+		{name: "identifier with a quote", quote: QuoteIdentifier, value: `a"b`, want: `"a""b"`},
+		{name: "literal", quote: QuoteLiteral, value: "0.110-0", want: `'0.110-0'`},
+		{name: "literal with a quote", quote: QuoteLiteral, value: "x'; DROP TABLE t; --", want: `'x''; DROP TABLE t; --'`},
+		{name: "literal with a backslash", quote: QuoteLiteral, value: `a\'b`, want: `E'a\\''b'`},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.quote(tt.value); got != tt.want {
+				t.Errorf("quote(%q) = %s, want %s", tt.value, got, tt.want)
+			}
+		})
+	}
+}
@@ -17,6 +17,14 @@ const (
 	// Label for identifying the DocumentDB cluster a PV/PVC belongs to
 	LabelCluster   = "documentdb.io/cluster"
 	LabelNamespace = "documentdb.io/namespace"
+
+	// Annotation marking a temporary PVC whose PV was checked to hold a
+	// PostgreSQL data directory
+	PVRecoveryCheckedAnnotation = "documentdb.io/pv-recovery-checked"
+
+	// Label recording on a PV the role, primary or replica, of the instance
+	// its PVC last belonged to
+	LabelInstanceRole = "documentdb.io/instance-role"
 )
 
 // TempPVCNameForPVRecovery generates the name for a temporary PVC used during PV recovery.
@@ -7,10 +7,12 @@ import (
 	"context"
 	"fmt"
 	"hash/fnv"
+	"strings"
 
 	dbpreview "github.com/documentdb/documentdb-operator/api/preview"
 	corev1 "k8s.io/api/core/v1"
 	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/apimachinery/pkg/util/validation"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 )
 
@@ -49,7 +51,7 @@ func GetReplicationContext(ctx context.Context, client client.Client, documentdb
 	singleClusterReplicationContext := ReplicationContext{
 		state:                        NoReplication,
 		CrossCloudNetworkingStrategy: None,
-		Environment:                  documentdb.Spec.Environment,
+		Environment:                  ResolveEnvironment(documentdb.Spec.Environment),
 		StorageClass:                 documentdb.Spec.Resource.Storage.StorageClass,
 		CNPGClusterName:              documentdb.Name,
 	}
@@ -91,7 +93,7 @@ func GetReplicationContext(ctx context.Context, client client.Client, documentdb
 	if self.StorageClassOverride != "" {
 		storageClass = self.StorageClassOverride
 	}
-	environment := documentdb.Spec.Environment
+	environment := ResolveEnvironment(documentdb.Spec.Environment)
 	if self.EnvironmentOverride != "" {
 		environment = self.EnvironmentOverride
 	}
@@ -251,9 +253,9 @@ func getTopology(ctx context.Context, client client.Client, documentdb dbpreview
 }
 
 func GetFleetMemberName(ctx context.Context, client client.Client) (string, error) {
-	clusterMapName := "cluster-name"
+	clusterMapName := fleetMemberConfigMapName
 	clusterNameConfigMap := &corev1.ConfigMap{}
-	err := client.Get(ctx, types.NamespacedName{Name: clusterMapName, Namespace: "kube-system"}, clusterNameConfigMap)
+	err := client.Get(ctx, types.NamespacedName{Name: clusterMapName, Namespace: fleetMemberConfigMapNamespace}, clusterNameConfigMap)
 	if err != nil {
 		return "", err
 	}
@@ -273,20 +275,103 @@ func (r *ReplicationContext) IsIstioNetworking() bool {
 	return r.CrossCloudNetworkingStrategy == Istio
 }
 
+const (
+	// serviceNameHashLength is the length of the hash ending the service
+	// names of generateServiceName that had to be shortened.
+	serviceNameHashLength = 16
+	// minServiceNameHashLength is the length below which that hash is not
+	// shortened, even when the name then no longer leaves room for the
+	// resource group.
+	minServiceNameHashLength = 8
+)
+
+// generateServiceName returns the name of the service through which the CNPG
+// cluster of targetCluster replicates from the one of sourceCluster. Fleet
+// exports it as <resourceGroup>-<name>, so the name leaves room for the
+// resource group within the 63 characters of a DNS label whenever it can.
+//
+// The name is the DocumentDB name followed by a hash of the pair. A name that
+// does not fit, or is not a valid DNS-1035 label, is made of the DocumentDB
+// name cut short and a hash that also covers it, so that names cut alike stay
+// unique. Names that fit are left as they have always been, so that the
+// services of existing clusters keep their names. The services of the other
+// names are renamed: the ServiceExports and MultiClusterServices under their
+// former names are deleted by CreateServiceImportAndExport, and the Services
+// by CNPG once they leave the managed services of the Cluster.
 func generateServiceName(docdbName, sourceCluster, targetCluster, resourceGroup string) string {
-	length := 63 - len(resourceGroup) - 1 // account for hyphen
+	maxLength := 63 - len(resourceGroup) - 1 // account for hyphen
 	h := fnv.New64a()
 	h.Write([]byte(sourceCluster))
 	h.Write([]byte(targetCluster))
-	hash := h.Sum64()
+	name := fmt.Sprintf("%s-%x", docdbName, h.Sum64())
+	if len(name) <= maxLength && len(validation.IsDNS1035Label(name)) == 0 {
+		return name
+	}
 
-	// Convert hash to hex string
-	hashStr := fmt.Sprintf("%s-%x", docdbName, hash)
+	h = fnv.New64a()
+	for _, part := range []string{docdbName, sourceCluster, targetCluster} {
+		h.Write([]byte(part))
+		h.Write([]byte{0})
+	}
+	hash := fmt.Sprintf("%0*x", serviceNameHashLength, h.Sum64())
 
-	if length >= 0 && length < len(hashStr) {
-		return hashStr[:length]
+	prefix := serviceNamePrefix(docdbName, maxLength-len(hash)-1)
+	if prefix == "" {
+		// A service name starts with a letter, which the hash may not.
+		hashLength := min(max(maxLength-1, minServiceNameHashLength), len(hash))
+		return "s" + hash[:hashLength]
+	}
+	return prefix + "-" + hash
+}
+
+// serviceNamePrefix returns at most maxLength characters of docdbName that
+// can start a DNS-1035 label: lowercase letters, digits and hyphens, starting
+// with a letter and not ending with a hyphen.
+func serviceNamePrefix(docdbName string, maxLength int) string {
+	prefix := strings.Map(func(r rune) rune {
+		if (r >= 'a' && r <= 'z') || (r >= '0' && r <= '9') {
+			return r
+		}
+		return '-'
+	}, strings.ToLower(docdbName))
+	prefix = strings.TrimLeftFunc(prefix, func(r rune) bool { return r < 'a' || r > 'z' })
+	if maxLength < 0 {
+		maxLength = 0
+	}
+	if len(prefix) > maxLength {
+		prefix = prefix[:maxLength]
+	}
+	return strings.TrimRight(prefix, "-")
+}
+
+// ValidateServiceNames checks the names of the replication services of
+// every ordered pair of CNPG clusters of the topology: they must be unique,
+// and leave room for resourceGroup when Fleet exports them.
+func (r ReplicationContext) ValidateServiceNames(docdbName, resourceGroup string) error {
+	clusters := append([]string{r.CNPGClusterName}, r.OtherCNPGClusterNames...)
+	pairs := map[string]string{}
+	var problems []string
+	for _, source := range clusters {
+		for _, target := range clusters {
+			if source == target {
+				continue
+			}
+			name := generateServiceName(docdbName, source, target, resourceGroup)
+			pair := source + "->" + target
+			if other, found := pairs[name]; found {
+				problems = append(problems, fmt.Sprintf("%s and %s share the name %s", other, pair, name))
+				continue
+			}
+			pairs[name] = pair
+			if r.IsAzureFleetNetworking() && len(resourceGroup)+1+len(name) > 63 {
+				problems = append(problems, fmt.Sprintf("the export of %s, %s-%s, is longer than 63 characters", name, resourceGroup, name))
+			}
+		}
+	}
+	if len(problems) > 0 {
+		return fmt.Errorf("invalid replication service names: %s", strings.Join(problems, "; "))
 	}
-	return hashStr
+	return nil
 }
 
 // Generate the CNPG Cluster name using the Documentdb name and a hash of the member cluster
@@ -564,3 +564,53 @@ func TestGenerateCNPGClusterName(t *testing.T) {
 		}
 	})
 }
+
+func TestReplicationContext_ValidateServiceNames(t *testing.T) {
+	tests := []struct {
+		name          string
+		context       ReplicationContext
+		resourceGroup string
+		wantErr       string
+	}{
+		{
+			name: "unique names that fit",
+			context: ReplicationContext{
+				CNPGClusterName:              "self-cluster",
+				OtherCNPGClusterNames:        []string{"cluster-a", "cluster-b"},
+				CrossCloudNetworkingStrategy: AzureFleet,
+			},
+			resourceGroup: "documentdb-ns",
+		},
+		{
+			name: "resource group too long for the Fleet exports",
+			context: ReplicationContext{
+				CNPGClusterName:              "self-cluster",
+				OtherCNPGClusterNames:        []string{"cluster-a"},
+				CrossCloudNetworkingStrategy: AzureFleet,
+			},
+			resourceGroup: strings.Repeat("n", 60),
+			wantErr:       "longer than 63 characters",
+		},
+		{
+			name: "resource group length does not matter without Fleet",
+			context: ReplicationContext{
+				CNPGClusterName:              "self-cluster",
+				OtherCNPGClusterNames:        []string{"cluster-a"},
+				CrossCloudNetworkingStrategy: Istio,
+			},
+			resourceGroup: strings.Repeat("n", 60),
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := tt.context.ValidateServiceNames("mydb", tt.resourceGroup)
+			if tt.wantErr == "" && err != nil {
+				t.Fatalf("ValidateServiceNames() error = %v", err)
+			}
+			if tt.wantErr != "" && (err == nil || !strings.Contains(err.Error(), tt.wantErr)) {
+				t.Fatalf("ValidateServiceNames() error = %v, want %q", err, tt.wantErr)
+			}
+		})
+	}
+}
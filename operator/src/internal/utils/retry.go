@@ -0,0 +1,55 @@
+// Copyright (c) Microsoft Corporation.
+// Licensed under the MIT License.
+
+package util
+
+import (
+	"context"
+
+	"k8s.io/client-go/util/retry"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+// UpdateWithRetry applies mutate to obj and updates it. When the update
+// conflicts with a concurrent write, obj is read again and mutate applied to
+// the fresh copy, so that a 409 does not fail the reconcile. mutate must
+// only change obj and be safe to run more than once. obj is read again with
+// reader, which must not be a cache: a cache lagging behind the conflicting
+// write would return the same stale copy until the retries run out.
+func UpdateWithRetry(ctx context.Context, c client.Client, reader client.Reader, obj client.Object, mutate func() error) error {
+	return writeWithRetry(ctx, reader, obj, mutate, func() error {
+		return c.Update(ctx, obj)
+	})
+}
+
+// PatchWithRetry applies mutate to obj and sends the change as a merge
+// patch guarded by the resourceVersion it was computed from. On a conflict,
+// obj is read again and the patch computed anew, so that the change never
+// overwrites a list, such as the owner references, written concurrently.
+// obj is read again with reader, as in UpdateWithRetry.
+func PatchWithRetry(ctx context.Context, c client.Client, reader client.Reader, obj client.Object, mutate func() error) error {
+	var patch client.Patch
+	return writeWithRetry(ctx, reader, obj, func() error {
+		patch = client.MergeFromWithOptions(obj.DeepCopyObject().(client.Object), client.MergeFromWithOptimisticLock{})
+		return mutate()
+	}, func() error {
+		return c.Patch(ctx, obj, patch)
+	})
+}
+
+func writeWithRetry(ctx context.Context, reader client.Reader, obj client.Object, mutate, write func() error) error {
+	key := client.ObjectKeyFromObject(obj)
+	fresh := true
+	return retry.RetryOnConflict(retry.DefaultRetry, func() error {
+		if !fresh {
+			if err := reader.Get(ctx, key, obj); err != nil {
+				return err
+			}
+		}
+		fresh = false
+		if err := mutate(); err != nil {
+			return err
+		}
+		return write()
+	})
+}
@@ -0,0 +1,126 @@
+// Copyright (c) Microsoft Corporation.
+// Licensed under the MIT License.
+
+package util
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+)
+
+func TestWriteWithRetry(t *testing.T) {
+	tests := []struct {
+		name  string
+		write func(ctx context.Context, c client.Client, reader client.Reader, obj client.Object, mutate func() error) error
+	}{
+		{name: "update", write: UpdateWithRetry},
+		{name: "patch", write: PatchWithRetry},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctx := context.Background()
+			pv := &corev1.PersistentVolume{ObjectMeta: metav1.ObjectMeta{Name: "pv-1", Labels: map[string]string{"a": "1"}}}
+			c := fake.NewClientBuilder().WithObjects(pv).Build()
+
+			stale := &corev1.PersistentVolume{}
+			if err := c.Get(ctx, client.ObjectKeyFromObject(pv), stale); err != nil {
+				t.Fatal(err)
+			}
+			concurrent := stale.DeepCopy()
+			concurrent.Labels["b"] = "2"
+			if err := c.Update(ctx, concurrent); err != nil {
+				t.Fatal(err)
+			}
+
+			attempts := 0
+			err := tt.write(ctx, c, c, stale, func() error {
+				attempts++
+				stale.Labels["c"] = "3"
+				return nil
+			})
+			if err != nil {
+				t.Fatalf("write failed: %v", err)
+			}
+			if attempts != 2 {
+				t.Errorf("mutate ran %d times, want 2", attempts)
+			}
+
+			got := &corev1.PersistentVolume{}
+			if err := c.Get(ctx, client.ObjectKeyFromObject(pv), got); err != nil {
+				t.Fatal(err)
+			}
+			if got.Labels["b"] != "2" || got.Labels["c"] != "3" {
+				t.Errorf("labels = %v, want the concurrent and the retried change", got.Labels)
+			}
+		})
+	}
+}
+
+func TestWriteWithRetryReturnsMutateError(t *testing.T) {
+	pv := &corev1.PersistentVolume{ObjectMeta: metav1.ObjectMeta{Name: "pv-1"}}
+	c := fake.NewClientBuilder().WithObjects(pv).Build()
+	want := errors.New("not allowed")
+
+	if err := UpdateWithRetry(context.Background(), c, c, pv, func() error { return want }); !errors.Is(err, want) {
+		t.Errorf("UpdateWithRetry() = %v, want %v", err, want)
+	}
+}
+
+// laggingCache returns the copy of the objects it was built with, as a cache
+// that has not seen the writes made since.
+type laggingCache struct {
+	client.Reader
+	objects map[client.ObjectKey]client.Object
+}
+
+func (l *laggingCache) Get(_ context.Context, key client.ObjectKey, obj client.Object, _ ...client.GetOption) error {
+	l.objects[key].(*corev1.PersistentVolume).DeepCopyInto(obj.(*corev1.PersistentVolume))
+	return nil
+}
+
+func TestWriteWithRetryReadsAgainWithTheReader(t *testing.T) {
+	ctx := context.Background()
+	pv := &corev1.PersistentVolume{ObjectMeta: metav1.ObjectMeta{Name: "pv-1", Labels: map[string]string{"a": "1"}}}
+	c := fake.NewClientBuilder().WithObjects(pv).Build()
+
+	stale := &corev1.PersistentVolume{}
+	if err := c.Get(ctx, client.ObjectKeyFromObject(pv), stale); err != nil {
+		t.Fatal(err)
+	}
+	cache := &laggingCache{objects: map[client.ObjectKey]client.Object{client.ObjectKeyFromObject(pv): stale.DeepCopy()}}
+	concurrent := stale.DeepCopy()
+	concurrent.Labels["b"] = "2"
+	if err := c.Update(ctx, concurrent); err != nil {
+		t.Fatal(err)
+	}
+
+	label := func(pv *corev1.PersistentVolume) func() error {
+		return func() error {
+			pv.Labels["c"] = "3"
+			return nil
+		}
+	}
+	cached := stale.DeepCopy()
+	if err := UpdateWithRetry(ctx, c, cache, cached, label(cached)); !apierrors.IsConflict(err) {
+		t.Fatalf("UpdateWithRetry() with a lagging cache = %v, want a conflict", err)
+	}
+	if err := UpdateWithRetry(ctx, c, c, stale, label(stale)); err != nil {
+		t.Fatalf("UpdateWithRetry() with the API reader = %v", err)
+	}
+
+	got := &corev1.PersistentVolume{}
+	if err := c.Get(ctx, client.ObjectKeyFromObject(pv), got); err != nil {
+		t.Fatal(err)
+	}
+	if got.Labels["b"] != "2" || got.Labels["c"] != "3" {
+		t.Errorf("labels = %v, want the concurrent and the retried change", got.Labels)
+	}
+}
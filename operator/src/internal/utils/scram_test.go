@@ -0,0 +1,42 @@
+// Copyright (c) Microsoft Corporation.
+// Licensed under the MIT License.
+
+package util
+
+import (
+	"crypto/hmac"
+	"crypto/pbkdf2"
+	"crypto/sha256"
+	"encoding/base64"
+	"regexp"
+	"testing"
+)
+
+func TestSCRAMSHA256Verifier(t *testing.T) {
+	verifier, err := SCRAMSHA256Verifier("s3cret")
+	if err != nil {
+		t.Fatalf("SCRAMSHA256Verifier() error = %v", err)
+	}
+	match := regexp.MustCompile(`^SCRAM-SHA-256\$4096:([^$]+)\$([^:]+):(.+)$`).FindStringSubmatch(verifier)
+	if match == nil {
+		t.Fatalf("SCRAMSHA256Verifier() = %q, not a SCRAM-SHA-256 verifier", verifier)
+	}
+
+	// A client proving the password derives the same stored key
+	salt, _ := base64.StdEncoding.DecodeString(match[1])
+	salted, err := pbkdf2.Key(sha256.New, "s3cret", salt, 4096, sha256.Size)
+	if err != nil {
+		t.Fatal(err)
+	}
+	mac := hmac.New(sha256.New, salted)
+	mac.Write([]byte("Client Key"))
+	storedKey := sha256.Sum256(mac.Sum(nil))
+	if got := base64.StdEncoding.EncodeToString(storedKey[:]); got != match[2] {
+		t.Errorf("stored key = %s, want %s", match[2], got)
+	}
+
+	other, _ := SCRAMSHA256Verifier("s3cret")
+	if other == verifier {
+		t.Error("verifiers of the same password share a salt")
+	}
+}
@@ -0,0 +1,23 @@
+// Copyright (c) Microsoft Corporation.
+// Licensed under the MIT License.
+
+package util
+
+import "strings"
+
+// QuoteIdentifier quotes name for use as a PostgreSQL identifier, e.g. a role
+// or a database name.
+func QuoteIdentifier(name string) string {
+	return `"` + strings.ReplaceAll(name, `"`, `""`) + `"`
+}
+
+// QuoteLiteral quotes value for use as a PostgreSQL string literal. Values
+// with a backslash are written as escape strings, so that they read the same
+// whatever standard_conforming_strings is set to.
+func QuoteLiteral(value string) string {
+	quoted := "'" + strings.ReplaceAll(value, "'", "''") + "'"
+	if strings.Contains(value, `\`) {
+		return "E" + strings.ReplaceAll(quoted, `\`, `\\`)
+	}
+	return quoted
+}
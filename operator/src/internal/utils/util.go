@@ -6,7 +6,11 @@ package util
 import (
 	"context"
 	"fmt"
+	"maps"
+	"net"
+	"net/url"
 	"os"
+	"slices"
 	"strconv"
 	"strings"
 	"time"
@@ -22,6 +26,8 @@ import (
 	"k8s.io/apimachinery/pkg/types"
 	utilerrors "k8s.io/apimachinery/pkg/util/errors"
 	"k8s.io/apimachinery/pkg/util/intstr"
+	"k8s.io/apimachinery/pkg/util/validation"
+	"k8s.io/apimachinery/pkg/util/version"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 	"sigs.k8s.io/controller-runtime/pkg/log"
 
@@ -39,18 +45,19 @@ func GetDocumentDBServiceDefinition(documentdb *dbpreview.DocumentDB, replicatio
 			LABEL_APP:              documentdb.Name,
 			"cnpg.io/instanceRole": "primary", // Service forwards traffic to CNPG primary instance
 		}
+		if target := documentdb.Annotations[MIGRATED_TO_ANNOTATION]; target != "" {
+			selector[LABEL_APP] = target
+		}
 	}
 
-	// Ensure service name doesn't exceed 63 characters (Kubernetes limit)
-	serviceName := DOCUMENTDB_SERVICE_PREFIX + documentdb.Name
-	if len(serviceName) > 63 {
-		serviceName = serviceName[:63]
-	}
+	serviceName := DocumentDBServiceName(documentdb)
+	gatewayPort := GetGatewayPort(documentdb)
 
 	service := &corev1.Service{
 		ObjectMeta: metav1.ObjectMeta{
 			Name:      serviceName,
 			Namespace: namespace,
+			Labels:    ChildLabels(documentdb, map[string]string{LABEL_DOCUMENTDB_NAME: documentdb.Name}),
 			// CRITICAL: Set owner reference so service gets deleted when DocumentDB instance is deleted
 			OwnerReferences: []metav1.OwnerReference{
 				{
@@ -66,24 +73,70 @@ func GetDocumentDBServiceDefinition(documentdb *dbpreview.DocumentDB, replicatio
 		Spec: corev1.ServiceSpec{
 			Selector: selector,
 			Ports: []corev1.ServicePort{
-				{Name: "gateway", Protocol: corev1.ProtocolTCP, Port: GetPortFor(GATEWAY_PORT), TargetPort: intstr.FromInt(int(GetPortFor(GATEWAY_PORT)))},
+				{Name: "gateway", Protocol: corev1.ProtocolTCP, Port: gatewayPort, TargetPort: intstr.FromInt32(gatewayPort)},
 			},
 			Type: serviceType,
 		},
 	}
 
 	// Add environment-specific annotations for LoadBalancer services
+	var annotations map[string]string
 	if serviceType == corev1.ServiceTypeLoadBalancer {
-		service.ObjectMeta.Annotations = getEnvironmentSpecificAnnotations(replicationContext.Environment)
+		annotations = getEnvironmentSpecificAnnotations(replicationContext.Environment)
+	}
+	if hostname := documentdb.Spec.ExposeViaService.Hostname; hostname != "" {
+		annotations = mergeMetadata(annotations, map[string]string{EXTERNAL_DNS_HOSTNAME_ANNOTATION: hostname})
 	}
+	service.ObjectMeta.Annotations = ChildAnnotations(documentdb, annotations)
 
 	return service
 }
 
-// getEnvironmentSpecificAnnotations returns the appropriate service annotations based on the environment
+// DocumentDBServiceName returns the name of the Service exposing the gateway of
+// documentdb.
+func DocumentDBServiceName(documentdb *dbpreview.DocumentDB) string {
+	// Ensure service name doesn't exceed 63 characters (Kubernetes limit)
+	serviceName := DOCUMENTDB_SERVICE_PREFIX + documentdb.Name
+	if len(serviceName) > 63 {
+		serviceName = serviceName[:63]
+	}
+	return serviceName
+}
+
+// getEnvironmentSpecificAnnotations returns the appropriate service annotations based on the environment,
+// with the overrides of DOCUMENTDB_LOAD_BALANCER_ANNOTATIONS applied
 func getEnvironmentSpecificAnnotations(environment string) map[string]string {
+	override, found := GetLoadBalancerAnnotationOverrides()[environment]
+	if !found {
+		return builtInLoadBalancerAnnotations(environment)
+	}
+	annotations := map[string]string{}
+	if !override.Replace {
+		annotations = builtInLoadBalancerAnnotations(environment)
+	}
+	maps.Copy(annotations, override.Annotations)
+	return annotations
+}
+
+// StaleLoadBalancerAnnotations returns the built-in annotations of environment
+// that its override replaced, to remove from the existing LoadBalancer
+// Services.
+func StaleLoadBalancerAnnotations(environment string) []string {
+	annotations := getEnvironmentSpecificAnnotations(environment)
+	var stale []string
+	for key := range builtInLoadBalancerAnnotations(environment) {
+		if _, found := annotations[key]; !found {
+			stale = append(stale, key)
+		}
+	}
+	slices.Sort(stale)
+	return stale
+}
+
+// builtInLoadBalancerAnnotations returns the service annotations the operator sets by default for environment
+func builtInLoadBalancerAnnotations(environment string) map[string]string {
 	switch environment {
-	case "eks":
+	case ENVIRONMENT_EKS:
 		// AWS EKS specific annotations for Network Load Balancer
 		return map[string]string{
 			"service.beta.kubernetes.io/aws-load-balancer-type":                              "nlb",
@@ -91,12 +144,12 @@ func getEnvironmentSpecificAnnotations(environment string) map[string]string {
 			"service.beta.kubernetes.io/aws-load-balancer-cross-zone-load-balancing-enabled": "true",
 			"service.beta.kubernetes.io/aws-load-balancer-nlb-target-type":                   "ip",
 		}
-	case "aks":
+	case ENVIRONMENT_AKS:
 		// Azure AKS specific annotations for Load Balancer
 		return map[string]string{
 			"service.beta.kubernetes.io/azure-load-balancer-external": "true",
 		}
-	case "gke":
+	case ENVIRONMENT_GKE:
 		// Google GKE specific annotations for Load Balancer
 		return map[string]string{
 			"cloud.google.com/load-balancer-type": "External",
@@ -145,7 +198,8 @@ func EnsureServiceIP(ctx context.Context, service *corev1.Service) (string, erro
 }
 
 // UpsertService checks if the Service already exists, and creates it if not.
-func UpsertService(ctx context.Context, c client.Client, service *corev1.Service) (*corev1.Service, error) {
+// The staleAnnotations are removed from an existing Service.
+func UpsertService(ctx context.Context, c client.Client, service *corev1.Service, staleAnnotations ...string) (*corev1.Service, error) {
 	log := log.FromContext(ctx)
 	foundService := &corev1.Service{}
 	err := c.Get(ctx, types.NamespacedName{Name: service.Name, Namespace: service.Namespace}, foundService)
@@ -164,6 +218,14 @@ func UpsertService(ctx context.Context, c client.Client, service *corev1.Service
 			return nil, err
 		}
 	} else {
+		// Carry over the desired labels and annotations, such as the cost labels,
+		// which may have changed since the Service was created.
+		foundService.Labels = mergeMetadata(foundService.Labels, service.Labels)
+		for _, key := range staleAnnotations {
+			delete(foundService.Annotations, key)
+		}
+		foundService.Annotations = mergeMetadata(foundService.Annotations, service.Annotations)
+		foundService.Spec.Ports = mergeServicePorts(foundService.Spec.Ports, service.Spec.Ports)
 		if err := c.Update(ctx, foundService); err != nil {
 			return nil, err
 		}
@@ -171,28 +233,122 @@ func UpsertService(ctx context.Context, c client.Client, service *corev1.Service
 	return foundService, nil
 }
 
+// mergeServicePorts sets the port numbers of desired on the ports of the
+// same name in existing, such as after a change of the gateway port, keeping
+// the node ports Kubernetes allocated, and appends the missing ones.
+func mergeServicePorts(existing, desired []corev1.ServicePort) []corev1.ServicePort {
+	merged := slices.Clone(existing)
+	for _, port := range desired {
+		i := slices.IndexFunc(merged, func(p corev1.ServicePort) bool { return p.Name == port.Name })
+		if i < 0 {
+			merged = append(merged, port)
+			continue
+		}
+		merged[i].Protocol = port.Protocol
+		merged[i].Port = port.Port
+		merged[i].TargetPort = port.TargetPort
+	}
+	return merged
+}
+
+// GetPortFor returns the port name stands for, or 0 for another name.
+// PostgreSQL always runs on DEFAULT_POSTGRES_PORT. The sidecar and gateway
+// ports come from the SIDECAR_PORT_ENV and GATEWAY_PORT_ENV operator settings,
+// then from the legacy SIDECAR_PORT and GATEWAY_PORT environment variables.
+// GetGatewayPort returns the gateway port of a given DocumentDB.
 func GetPortFor(name string) int32 {
 	switch name {
 	case POSTGRES_PORT:
-		return getEnvAsInt32(POSTGRES_PORT, 5432)
+		return DEFAULT_POSTGRES_PORT
 	case SIDECAR_PORT:
-		return getEnvAsInt32(SIDECAR_PORT, 8445)
+		return getPortSetting(SIDECAR_PORT_ENV, SIDECAR_PORT, DEFAULT_SIDECAR_PORT, sidecarPortConflict)
 	case GATEWAY_PORT:
-		return getEnvAsInt32(GATEWAY_PORT, 10260)
+		return getPortSetting(GATEWAY_PORT_ENV, GATEWAY_PORT, DEFAULT_GATEWAY_PORT, GatewayPortConflict)
 	default:
 		return 0
 	}
 }
 
-func getEnvAsInt32(name string, defaultVal int) int32 {
-	if value, exists := os.LookupEnv(name); exists {
-		if intValue, err := strconv.Atoi(value); err == nil {
-			return int32(intValue)
-		} else {
-			log.FromContext(context.Background()).Error(err, "Invalid integer value for environment variable", "name", name, "value", value)
+// GetGatewayPort returns the port the gateway of documentdb listens on and
+// its Service exposes: spec.gateway.port, or the port of the operator
+// settings when it is unset or, as the webhook would have refused it,
+// conflicts with another port of the pod.
+func GetGatewayPort(documentdb *dbpreview.DocumentDB) int32 {
+	if documentdb.Spec.Gateway != nil && documentdb.Spec.Gateway.Port != 0 {
+		port := documentdb.Spec.Gateway.Port
+		if GatewayPortConflict(port) == "" && len(validation.IsValidPortNum(int(port))) == 0 {
+			return port
 		}
+		log.FromContext(context.Background()).Error(nil, "Invalid spec.gateway.port, using the operator default",
+			"documentdb", documentdb.Name, "namespace", documentdb.Namespace, "port", port)
+	}
+	return GetPortFor(GATEWAY_PORT)
+}
+
+// GatewayPortConflict returns what already uses port in a DocumentDB pod,
+// which the gateway therefore cannot listen on, or "" when port is free.
+func GatewayPortConflict(port int32) string {
+	if user := reservedPortUser(port); user != "" {
+		return user
+	}
+	if port == GetPortFor(SIDECAR_PORT) {
+		return "the sidecar"
+	}
+	return ""
+}
+
+// sidecarPortConflict returns what already uses port in a DocumentDB pod,
+// which the sidecar therefore cannot take, or "" when port is free. The
+// default gateway port stays free so that the gateway can fall back to it.
+func sidecarPortConflict(port int32) string {
+	if port == DEFAULT_GATEWAY_PORT {
+		return "the gateway"
+	}
+	return reservedPortUser(port)
+}
+
+// reservedPortUser returns the container of every DocumentDB pod that uses
+// port, or "" when none does.
+func reservedPortUser(port int32) string {
+	switch port {
+	case DEFAULT_POSTGRES_PORT:
+		return "PostgreSQL"
+	case CNPG_STATUS_PORT:
+		return "the status endpoint of the CNPG instance manager"
+	case CNPG_METRICS_PORT:
+		return "the metrics endpoint of the CNPG instance manager"
+	default:
+		return ""
 	}
-	return int32(defaultVal)
+}
+
+// getPortSetting returns the port of the operator setting key, or of the
+// legacy environment variable legacyEnv when the setting is unset. It returns
+// defaultVal when neither is set, or when the port is not a valid port number
+// or conflict reports it is taken, which would render Services and containers
+// the API server rejects or that do not reach the gateway.
+func getPortSetting(key, legacyEnv string, defaultVal int32, conflict func(int32) string) int32 {
+	name, value := key, GetOperatorSetting(key)
+	if value == "" {
+		name, value = legacyEnv, os.Getenv(legacyEnv)
+	}
+	if value == "" {
+		return defaultVal
+	}
+	port, err := strconv.Atoi(value)
+	if err == nil && len(validation.IsValidPortNum(port)) > 0 {
+		err = fmt.Errorf("%d is not a valid port number", port)
+	}
+	if err == nil {
+		if user := conflict(int32(port)); user != "" {
+			err = fmt.Errorf("port %d is used by %s", port, user)
+		}
+	}
+	if err != nil {
+		log.FromContext(context.Background()).Error(err, "Invalid port, using built-in default", "name", name, "value", value)
+		return defaultVal
+	}
+	return int32(port)
 }
 
 // CreateRole creates a Role with the given name in the specified namespace
@@ -359,7 +515,7 @@ func DeleteOwnedResources(ctx context.Context, c client.Client, owner metav1.Obj
 	var mcsList fleetv1alpha1.MultiClusterServiceList
 	if err := c.List(ctx, &mcsList, listInNamespace); err != nil && !errors.IsNotFound(err) {
 		// Ignore if CRD doesn't exist
-		if !isCRDMissing(err) {
+		if !IsCRDMissing(err) {
 			return fmt.Errorf("failed to list MultiClusterServices: %w", err)
 		}
 	} else {
@@ -377,7 +533,7 @@ func DeleteOwnedResources(ctx context.Context, c client.Client, owner metav1.Obj
 	var serviceExportList fleetv1alpha1.ServiceExportList
 	if err := c.List(ctx, &serviceExportList, listInNamespace); err != nil && !errors.IsNotFound(err) {
 		// Ignore if CRD doesn't exist
-		if !isCRDMissing(err) {
+		if !IsCRDMissing(err) {
 			return fmt.Errorf("failed to list ServiceExports: %w", err)
 		}
 	} else {
@@ -398,9 +554,9 @@ func DeleteOwnedResources(ctx context.Context, c client.Client, owner metav1.Obj
 	return nil
 }
 
-// isCRDMissing checks if the error is a "no kind match" error, which occurs when
+// IsCRDMissing checks if the error is a "no kind match" error, which occurs when
 // a CRD is not installed in the cluster
-func isCRDMissing(err error) bool {
+func IsCRDMissing(err error) bool {
 	if err == nil {
 		return false
 	}
@@ -410,38 +566,138 @@ func isCRDMissing(err error) bool {
 // GenerateConnectionString returns a MongoDB connection string for the DocumentDB instance.
 // When trustTLS is true, tlsAllowInvalidCertificates is omitted for strict verification.
 func GenerateConnectionString(documentdb *dbpreview.DocumentDB, serviceIp string, trustTLS bool) string {
-	secretName := documentdb.Spec.DocumentDbCredentialSecret
-	if secretName == "" {
-		secretName = DEFAULT_DOCUMENTDB_CREDENTIALS_SECRET
+	secretName := CredentialSecretName(documentdb)
+	return fmt.Sprintf("mongodb://$(kubectl get secret %s -n %s -o jsonpath='{.data.username}' | base64 -d):$(kubectl get secret %s -n %s -o jsonpath='{.data.password}' | base64 -d)@%s:%d/?%s", secretName, documentdb.Namespace, secretName, documentdb.Namespace, serviceIp, GetGatewayPort(documentdb), connectionStringOptions(trustTLS))
+}
+
+// GenerateConnectionURI returns the MongoDB connection string for the DocumentDB
+// instance with the credentials resolved, for applications to use as is. It
+// takes the same options as GenerateConnectionString, port being the gateway
+// port of GetGatewayPort.
+func GenerateConnectionURI(host string, port int32, username, password string, trustTLS bool) string {
+	uri := url.URL{
+		Scheme:   "mongodb",
+		User:     url.UserPassword(username, password),
+		Host:     net.JoinHostPort(host, strconv.Itoa(int(port))),
+		Path:     "/",
+		RawQuery: connectionStringOptions(trustTLS),
+	}
+	return uri.String()
+}
+
+// GenerateHAConnectionString returns the connection string of
+// GenerateConnectionString for a list of seeds, the DocumentDB Service first,
+// given as host or host:port. With several seeds the driver discovers the
+// primary itself: directConnection is dropped, and reads fall back to the
+// other gateways while the primary is unavailable. A single seed keeps the
+// direct connection of GenerateConnectionString, the Service following the
+// primary on its own.
+func GenerateHAConnectionString(documentdb *dbpreview.DocumentDB, seeds []string, trustTLS bool) string {
+	if len(seeds) == 1 {
+		return GenerateConnectionString(documentdb, seeds[0], trustTLS)
+	}
+	secretName := CredentialSecretName(documentdb)
+	return fmt.Sprintf("mongodb://$(kubectl get secret %s -n %s -o jsonpath='{.data.username}' | base64 -d):$(kubectl get secret %s -n %s -o jsonpath='{.data.password}' | base64 -d)@%s/?%s", secretName, documentdb.Namespace, secretName, documentdb.Namespace, seedList(seeds, GetGatewayPort(documentdb)), haConnectionStringOptions(trustTLS))
+}
+
+// GenerateHAConnectionURI returns the connection string of
+// GenerateHAConnectionString with the credentials resolved.
+func GenerateHAConnectionURI(seeds []string, port int32, username, password string, trustTLS bool) string {
+	if len(seeds) == 1 {
+		return GenerateConnectionURI(seeds[0], port, username, password, trustTLS)
+	}
+	uri := url.URL{
+		Scheme:   "mongodb",
+		User:     url.UserPassword(username, password),
+		Host:     seedList(seeds, port),
+		Path:     "/",
+		RawQuery: haConnectionStringOptions(trustTLS),
+	}
+	return uri.String()
+}
+
+// seedList joins seeds with commas, adding the gateway port to those
+// without one.
+func seedList(seeds []string, gatewayPort int32) string {
+	port := strconv.Itoa(int(gatewayPort))
+	hosts := make([]string, 0, len(seeds))
+	for _, seed := range seeds {
+		if _, _, err := net.SplitHostPort(seed); err != nil {
+			seed = net.JoinHostPort(seed, port)
+		}
+		hosts = append(hosts, seed)
+	}
+	return strings.Join(hosts, ",")
+}
+
+// haConnectionStringOptions returns the query options of the connection
+// strings listing several seeds.
+func haConnectionStringOptions(trustTLS bool) string {
+	options := "authMechanism=SCRAM-SHA-256&tls=true"
+	if !trustTLS {
+		options += "&tlsAllowInvalidCertificates=true"
 	}
-	conn := fmt.Sprintf("mongodb://$(kubectl get secret %s -n %s -o jsonpath='{.data.username}' | base64 -d):$(kubectl get secret %s -n %s -o jsonpath='{.data.password}' | base64 -d)@%s:%d/?directConnection=true&authMechanism=SCRAM-SHA-256&tls=true", secretName, documentdb.Namespace, secretName, documentdb.Namespace, serviceIp, GetPortFor(GATEWAY_PORT))
+	return options + "&replicaSet=rs0&readPreference=primaryPreferred"
+}
+
+// connectionStringOptions returns the query options of the connection strings.
+func connectionStringOptions(trustTLS bool) string {
+	options := "directConnection=true&authMechanism=SCRAM-SHA-256&tls=true"
 	if !trustTLS {
-		conn += "&tlsAllowInvalidCertificates=true"
+		options += "&tlsAllowInvalidCertificates=true"
 	}
-	return conn + "&replicaSet=rs0"
+	return options + "&replicaSet=rs0"
+}
+
+// CredentialSecretName returns the name of the Secret holding the gateway
+// credentials of documentdb.
+func CredentialSecretName(documentdb *dbpreview.DocumentDB) string {
+	if documentdb.Spec.DocumentDbCredentialSecret != "" {
+		return documentdb.Spec.DocumentDbCredentialSecret
+	}
+	return DEFAULT_DOCUMENTDB_CREDENTIALS_SECRET
+}
+
+// DocumentDBVersion returns the DocumentDB version set for documentdb: the
+// version of a minor upgrade (see MINOR_UPGRADE_VERSION_ANNOTATION) while it is
+// newer than spec.documentDBVersion, or env.DOCUMENTDB_VERSION when the spec
+// leaves it unset, and spec.documentDBVersion otherwise.
+func DocumentDBVersion(documentdb *dbpreview.DocumentDB) string {
+	specVersion := documentdb.Spec.DocumentDBVersion
+	target, err := version.ParseSemantic(documentdb.Annotations[MINOR_UPGRADE_VERSION_ANNOTATION])
+	if err != nil {
+		return specVersion
+	}
+	base := specVersion
+	if base == "" {
+		base = GetOperatorSetting(DOCUMENTDB_VERSION_ENV)
+	}
+	if current, err := version.ParseSemantic(base); err == nil && !current.LessThan(target) {
+		return specVersion
+	}
+	return target.String()
 }
 
 // GetGatewayImageForDocumentDB returns the gateway image for a DocumentDB instance.
-// Priority: spec.image.gateway > spec.documentDBVersion > env.DOCUMENTDB_VERSION > default
+// Priority: spec.image.gateway > DocumentDBVersion > env.DOCUMENTDB_VERSION > feature variant > default
 func GetGatewayImageForDocumentDB(documentdb *dbpreview.DocumentDB) string {
 	if documentdb.Spec.Image != nil && documentdb.Spec.Image.Gateway != "" {
 		return documentdb.Spec.Image.Gateway
 	}
 
-	// Use spec-level documentDBVersion if set
-	if documentdb.Spec.DocumentDBVersion != "" {
-		return fmt.Sprintf("%s:%s", GATEWAY_IMAGE_REPO, documentdb.Spec.DocumentDBVersion)
+	// Use spec-level documentDBVersion, or the version of a minor upgrade, if set
+	if documentDBVersion := DocumentDBVersion(documentdb); documentDBVersion != "" {
+		return fmt.Sprintf("%s:%s", GATEWAY_IMAGE_REPO, documentDBVersion)
 	}
 
 	// Use global documentDbVersion if set
-	if version := os.Getenv(DOCUMENTDB_VERSION_ENV); version != "" {
+	if version := GetOperatorSetting(DOCUMENTDB_VERSION_ENV); version != "" {
 		return fmt.Sprintf("%s:%s", GATEWAY_IMAGE_REPO, version)
 	}
 
-	// Use changestream-enabled image when the ChangeStreams feature gate is on.
-	// TODO: remove this override once change stream support is included in the official images.
-	if dbpreview.IsFeatureGateEnabled(documentdb, dbpreview.FeatureGateChangeStreams) {
-		return CHANGESTREAM_GATEWAY_IMAGE
+	// Use the image variant of an enabled feature that needs one.
+	if image := capabilityImage(documentdb, func(c Capability) string { return c.GatewayImage }); image != "" {
+		return image
 	}
 
 	// Fall back to default
@@ -449,43 +705,42 @@ func GetGatewayImageForDocumentDB(documentdb *dbpreview.DocumentDB) string {
 }
 
 // GetDocumentDBImageForInstance returns the documentdb engine image.
-// Priority: spec.image.documentDB > spec.documentDBVersion > env.DOCUMENTDB_VERSION > default
+// Priority: spec.image.documentDB > DocumentDBVersion > env.DOCUMENTDB_VERSION > feature variant > default
 func GetDocumentDBImageForInstance(documentdb *dbpreview.DocumentDB) string {
 	if documentdb.Spec.Image != nil && documentdb.Spec.Image.DocumentDB != "" {
 		return documentdb.Spec.Image.DocumentDB
 	}
 
-	// Use spec-level documentDBVersion if set
-	if documentdb.Spec.DocumentDBVersion != "" {
-		return fmt.Sprintf("%s:%s", DOCUMENTDB_EXTENSION_IMAGE_REPO, documentdb.Spec.DocumentDBVersion)
+	// Use spec-level documentDBVersion, or the version of a minor upgrade, if set
+	if documentDBVersion := DocumentDBVersion(documentdb); documentDBVersion != "" {
+		return fmt.Sprintf("%s:%s", DOCUMENTDB_EXTENSION_IMAGE_REPO, documentDBVersion)
 	}
 
 	// Use global documentDbVersion if set (from DOCUMENTDB_VERSION env var)
-	if version := os.Getenv(DOCUMENTDB_VERSION_ENV); version != "" {
+	if version := GetOperatorSetting(DOCUMENTDB_VERSION_ENV); version != "" {
 		return fmt.Sprintf("%s:%s", DOCUMENTDB_EXTENSION_IMAGE_REPO, version)
 	}
 
-	// Use changestream-enabled image when the ChangeStreams feature gate is on.
-	// TODO: remove this override once change stream support is included in the official images.
-	if dbpreview.IsFeatureGateEnabled(documentdb, dbpreview.FeatureGateChangeStreams) {
-		return CHANGESTREAM_DOCUMENTDB_IMAGE
+	// Use the image variant of an enabled feature that needs one.
+	if image := capabilityImage(documentdb, func(c Capability) string { return c.DocumentDBImage }); image != "" {
+		return image
 	}
 
 	return DEFAULT_DOCUMENTDB_IMAGE
 }
 
-func GenerateServiceName(source, target, resourceGroup string) string {
-	name := fmt.Sprintf("%s-%s", source, target)
-	diff := 63 - len(name) - len(resourceGroup) - 2
-	if diff >= 0 {
-		return name
-	} else {
-		// truncate source and target region names equally if needed
-		truncateBy := (-diff + 1) / 2 // +1 to handle odd numbers
-		sourceLen := len(source) - truncateBy
-		targetLen := len(target) - truncateBy
-		return fmt.Sprintf("%s-%s", source[0:sourceLen], target[0:targetLen])
+// GetImagePullPolicy returns the pull policy of an image of a DocumentDB
+// instance that has an operator setting key.
+// Priority: spec.imagePullPolicy > a valid pull policy in key > "" (the Kubernetes default)
+func GetImagePullPolicy(documentdb *dbpreview.DocumentDB, key string) corev1.PullPolicy {
+	if documentdb.Spec.ImagePullPolicy != "" {
+		return documentdb.Spec.ImagePullPolicy
+	}
+	switch policy := corev1.PullPolicy(GetOperatorSetting(key)); policy {
+	case corev1.PullAlways, corev1.PullNever, corev1.PullIfNotPresent:
+		return policy
 	}
+	return ""
 }
 
 // ExtensionVersionToSemver converts a PostgreSQL extension version string from
@@ -0,0 +1,52 @@
+// Copyright (c) Microsoft Corporation.
+// Licensed under the MIT License.
+
+package util
+
+import (
+	"encoding/json"
+	"fmt"
+
+	dbpreview "github.com/documentdb/documentdb-operator/api/preview"
+)
+
+const (
+	// VeleroExcludeLabel excludes the object it is set on from Velero backups.
+	VeleroExcludeLabel = "velero.io/exclude-from-backup"
+
+	veleroPreHookContainer = "pre.hook.backup.velero.io/container"
+	veleroPreHookCommand   = "pre.hook.backup.velero.io/command"
+	veleroPreHookOnError   = "pre.hook.backup.velero.io/on-error"
+	veleroPreHookTimeout   = "pre.hook.backup.velero.io/timeout"
+
+	defaultVeleroHookTimeoutSeconds = 60
+)
+
+// veleroCheckpointCommand flushes the dirty pages of PostgreSQL before Velero
+// snapshots or copies the volumes, so that the crash recovery of a restored
+// instance has little WAL to replay.
+var veleroCheckpointCommand = []string{"psql", "-U", "postgres", "-d", "postgres", "-c", "CHECKPOINT"}
+
+// VeleroEnabled reports whether spec.backup.velero is set.
+func VeleroEnabled(documentdb *dbpreview.DocumentDB) bool {
+	return documentdb.Spec.Backup != nil && documentdb.Spec.Backup.Velero != nil
+}
+
+// VeleroHookAnnotations returns the Velero pre-backup hook annotations of the
+// database pods, or nil when spec.backup.velero is not set.
+func VeleroHookAnnotations(documentdb *dbpreview.DocumentDB) map[string]string {
+	if !VeleroEnabled(documentdb) {
+		return nil
+	}
+	timeout := documentdb.Spec.Backup.Velero.HookTimeoutSeconds
+	if timeout == 0 {
+		timeout = defaultVeleroHookTimeoutSeconds
+	}
+	command, _ := json.Marshal(veleroCheckpointCommand)
+	return map[string]string{
+		veleroPreHookContainer: "postgres",
+		veleroPreHookCommand:   string(command),
+		veleroPreHookOnError:   "Fail",
+		veleroPreHookTimeout:   fmt.Sprintf("%ds", timeout),
+	}
+}
@@ -0,0 +1,55 @@
+// Copyright (c) Microsoft Corporation.
+// Licensed under the MIT License.
+
+package util
+
+import (
+	"testing"
+
+	dbpreview "github.com/documentdb/documentdb-operator/api/preview"
+)
+
+func TestVeleroHookAnnotations(t *testing.T) {
+	tests := []struct {
+		name            string
+		backup          *dbpreview.BackupConfiguration
+		expectedTimeout string
+	}{
+		{
+			name: "no backup configuration",
+		},
+		{
+			name:   "velero not set",
+			backup: &dbpreview.BackupConfiguration{RetentionDays: 7},
+		},
+		{
+			name:            "default timeout",
+			backup:          &dbpreview.BackupConfiguration{Velero: &dbpreview.VeleroConfiguration{}},
+			expectedTimeout: "60s",
+		},
+		{
+			name:            "custom timeout",
+			backup:          &dbpreview.BackupConfiguration{Velero: &dbpreview.VeleroConfiguration{HookTimeoutSeconds: 300}},
+			expectedTimeout: "300s",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			db := &dbpreview.DocumentDB{Spec: dbpreview.DocumentDBSpec{Backup: tt.backup}}
+			annotations := VeleroHookAnnotations(db)
+			if tt.expectedTimeout == "" {
+				if annotations != nil || VeleroEnabled(db) {
+					t.Errorf("VeleroHookAnnotations() = %v, expected nil", annotations)
+				}
+				return
+			}
+			if got := annotations[veleroPreHookTimeout]; got != tt.expectedTimeout {
+				t.Errorf("timeout = %q, expected %q", got, tt.expectedTimeout)
+			}
+			if got := annotations[veleroPreHookContainer]; got != "postgres" {
+				t.Errorf("container = %q, expected postgres", got)
+			}
+		})
+	}
+}
@@ -0,0 +1,73 @@
+// Copyright (c) Microsoft Corporation.
+// Licensed under the MIT License.
+
+package util
+
+import (
+	"slices"
+	"strings"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/fields"
+	"sigs.k8s.io/controller-runtime/pkg/cache"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+// fleetMemberConfigMapNamespace and fleetMemberConfigMapName locate the ConfigMap
+// read by GetFleetMemberName.
+const (
+	fleetMemberConfigMapNamespace = "kube-system"
+	fleetMemberConfigMapName      = "cluster-name"
+)
+
+// ParseWatchNamespaces splits a comma-separated namespace list as passed to
+// --watch-namespaces, dropping blanks and duplicates. An empty result means the
+// operator watches every namespace.
+func ParseWatchNamespaces(value string) []string {
+	var namespaces []string
+	for ns := range strings.SplitSeq(value, ",") {
+		ns = strings.TrimSpace(ns)
+		if ns != "" && !slices.Contains(namespaces, ns) {
+			namespaces = append(namespaces, ns)
+		}
+	}
+	return namespaces
+}
+
+// WatchNamespacesCacheOptions restricts the manager cache to the given
+// namespaces so that the operator only needs namespaced RBAC there. Cluster-scoped
+// objects (PersistentVolumes, StorageClasses, ...) are not affected.
+//
+// ConfigMaps are additionally cached by name in kube-system, for the fleet
+// member name used by cross-cluster replication. The operator ConfigMap has a
+// cache of its own (see OperatorConfigReconciler).
+func WatchNamespacesCacheOptions(namespaces []string) cache.Options {
+	if len(namespaces) == 0 {
+		return cache.Options{}
+	}
+
+	defaultNamespaces := make(map[string]cache.Config, len(namespaces))
+	configMapNamespaces := make(map[string]cache.Config, len(namespaces)+1)
+	for _, ns := range namespaces {
+		defaultNamespaces[ns] = cache.Config{}
+		configMapNamespaces[ns] = cache.Config{}
+	}
+	if _, ok := configMapNamespaces[fleetMemberConfigMapNamespace]; !ok {
+		configMapNamespaces[fleetMemberConfigMapNamespace] = cache.Config{
+			FieldSelector: fields.OneTermEqualSelector("metadata.name", fleetMemberConfigMapName),
+		}
+	}
+
+	return cache.Options{
+		DefaultNamespaces: defaultNamespaces,
+		ByObject: map[client.Object]cache.ByObject{
+			&corev1.ConfigMap{}: {Namespaces: configMapNamespaces},
+		},
+	}
+}
+
+// IsWatchedNamespace reports whether namespace is within the watch list. An
+// empty watch list covers every namespace.
+func IsWatchedNamespace(watchNamespaces []string, namespace string) bool {
+	return len(watchNamespaces) == 0 || slices.Contains(watchNamespaces, namespace)
+}
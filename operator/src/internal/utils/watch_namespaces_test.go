@@ -0,0 +1,97 @@
+// Copyright (c) Microsoft Corporation.
+// Licensed under the MIT License.
+
+package util
+
+import (
+	"slices"
+	"testing"
+
+	corev1 "k8s.io/api/core/v1"
+)
+
+func TestParseWatchNamespaces(t *testing.T) {
+	tests := []struct {
+		name     string
+		value    string
+		expected []string
+	}{
+		{name: "empty", value: "", expected: nil},
+		{name: "single", value: "team-a", expected: []string{"team-a"}},
+		{name: "trims and drops blanks", value: " team-a, ,team-b ,", expected: []string{"team-a", "team-b"}},
+		{name: "drops duplicates", value: "team-a,team-b,team-a", expected: []string{"team-a", "team-b"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := ParseWatchNamespaces(tt.value); !slices.Equal(got, tt.expected) {
+				t.Errorf("ParseWatchNamespaces(%q) = %v, want %v", tt.value, got, tt.expected)
+			}
+		})
+	}
+}
+
+func TestWatchNamespacesCacheOptions(t *testing.T) {
+	t.Run("no namespaces watches the whole cluster", func(t *testing.T) {
+		opts := WatchNamespacesCacheOptions(nil)
+		if opts.DefaultNamespaces != nil || opts.ByObject != nil {
+			t.Errorf("expected empty cache options, got %+v", opts)
+		}
+	})
+
+	t.Run("restricts namespaced objects and widens ConfigMaps", func(t *testing.T) {
+		opts := WatchNamespacesCacheOptions([]string{"team-a", "team-b"})
+
+		if len(opts.DefaultNamespaces) != 2 {
+			t.Fatalf("expected 2 default namespaces, got %v", opts.DefaultNamespaces)
+		}
+		for _, ns := range []string{"team-a", "team-b"} {
+			if _, ok := opts.DefaultNamespaces[ns]; !ok {
+				t.Errorf("expected %q in default namespaces", ns)
+			}
+		}
+
+		var configMapNamespaces map[string]bool
+		for obj, byObject := range opts.ByObject {
+			if _, ok := obj.(*corev1.ConfigMap); ok {
+				configMapNamespaces = map[string]bool{}
+				for ns, cfg := range byObject.Namespaces {
+					configMapNamespaces[ns] = cfg.FieldSelector != nil
+				}
+			}
+		}
+		expected := map[string]bool{
+			"team-a":      false,
+			"team-b":      false,
+			"kube-system": true, // only the fleet member ConfigMap
+		}
+		if len(configMapNamespaces) != len(expected) {
+			t.Fatalf("ConfigMap namespaces = %v, want %v", configMapNamespaces, expected)
+		}
+		for ns, selected := range expected {
+			if got, ok := configMapNamespaces[ns]; !ok || got != selected {
+				t.Errorf("ConfigMap namespace %q: present=%v fieldSelector=%v, want fieldSelector=%v", ns, ok, got, selected)
+			}
+		}
+	})
+
+	t.Run("watched kube-system is not narrowed", func(t *testing.T) {
+		opts := WatchNamespacesCacheOptions([]string{"kube-system"})
+		for _, byObject := range opts.ByObject {
+			if cfg := byObject.Namespaces["kube-system"]; cfg.FieldSelector != nil {
+				t.Error("expected kube-system ConfigMaps to be fully cached when the namespace is watched")
+			}
+		}
+	})
+}
+
+func TestIsWatchedNamespace(t *testing.T) {
+	if !IsWatchedNamespace(nil, "anything") {
+		t.Error("empty watch list should cover every namespace")
+	}
+	if !IsWatchedNamespace([]string{"team-a"}, "team-a") {
+		t.Error("expected team-a to be watched")
+	}
+	if IsWatchedNamespace([]string{"team-a"}, "team-b") {
+		t.Error("expected team-b not to be watched")
+	}
+}
@@ -4,15 +4,25 @@
 package webhook
 
 import (
+	"cmp"
 	"context"
 	"fmt"
 	"reflect"
+	"regexp"
 	"strconv"
 	"strings"
 
+	cnpgv1 "github.com/cloudnative-pg/cloudnative-pg/api/v1"
+	pgtypes "github.com/cloudnative-pg/machinery/pkg/types"
+	"github.com/robfig/cron"
+	corev1 "k8s.io/api/core/v1"
 	apierrors "k8s.io/apimachinery/pkg/api/errors"
 	"k8s.io/apimachinery/pkg/api/resource"
+	apivalidation "k8s.io/apimachinery/pkg/api/validation"
+	metav1validation "k8s.io/apimachinery/pkg/apis/meta/v1/validation"
 	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/apimachinery/pkg/util/validation"
 	"k8s.io/apimachinery/pkg/util/validation/field"
 	ctrl "sigs.k8s.io/controller-runtime"
 	"sigs.k8s.io/controller-runtime/pkg/client"
@@ -21,11 +31,16 @@ import (
 
 	dbpreview "github.com/documentdb/documentdb-operator/api/preview"
 	"github.com/documentdb/documentdb-operator/internal/cnpg"
+	otelcfg "github.com/documentdb/documentdb-operator/internal/otel"
 	util "github.com/documentdb/documentdb-operator/internal/utils"
 )
 
 var documentdbLog = logf.Log.WithName("documentdb-webhook")
 
+// azureClientIDPattern matches the client ID of an Azure managed identity or
+// application, a GUID.
+var azureClientIDPattern = regexp.MustCompile(`^[0-9a-fA-F]{8}-[0-9a-fA-F]{4}-[0-9a-fA-F]{4}-[0-9a-fA-F]{4}-[0-9a-fA-F]{12}$`)
+
 // DocumentDBValidator validates DocumentDB resources on create and update.
 type DocumentDBValidator struct {
 	client.Client
@@ -47,10 +62,15 @@ func (v *DocumentDBValidator) SetupWebhookWithManager(mgr ctrl.Manager) error {
 // +kubebuilder:webhook:path=/validate-documentdb-io-preview-documentdb,mutating=false,failurePolicy=fail,sideEffects=None,groups=documentdb.io,resources=dbs,verbs=create;update,versions=preview,name=vdocumentdb.kb.io,admissionReviewVersions=v1
 
 // ValidateCreate validates a DocumentDB resource on creation.
-func (v *DocumentDBValidator) ValidateCreate(_ context.Context, documentdb *dbpreview.DocumentDB) (admission.Warnings, error) {
+func (v *DocumentDBValidator) ValidateCreate(ctx context.Context, documentdb *dbpreview.DocumentDB) (admission.Warnings, error) {
 	documentdbLog.Info("Validation for DocumentDB upon creation", "name", documentdb.Name, "namespace", documentdb.Namespace)
 
-	allErrs := v.validate(documentdb)
+	documentdb = v.withDefaults(ctx, documentdb)
+	allErrs := append(
+		v.validate(documentdb),
+		v.validateStorageSize(documentdb, nil)...,
+	)
+	allErrs = append(allErrs, v.validateNamespaceQuota(ctx, documentdb, nil)...)
 	if len(allErrs) == 0 {
 		return nil, nil
 	}
@@ -60,13 +80,17 @@ func (v *DocumentDBValidator) ValidateCreate(_ context.Context, documentdb *dbpr
 }
 
 // ValidateUpdate validates a DocumentDB resource on update.
-func (v *DocumentDBValidator) ValidateUpdate(_ context.Context, oldDB, newDB *dbpreview.DocumentDB) (admission.Warnings, error) {
+func (v *DocumentDBValidator) ValidateUpdate(ctx context.Context, oldDB, newDB *dbpreview.DocumentDB) (admission.Warnings, error) {
 	documentdbLog.Info("Validation for DocumentDB upon update", "name", newDB.Name, "namespace", newDB.Namespace)
 
+	oldDB = v.withDefaults(ctx, oldDB)
+	newDB = v.withDefaults(ctx, newDB)
 	allErrs := append(
 		v.validate(newDB),
 		v.validateChanges(newDB, oldDB)...,
 	)
+	allErrs = append(allErrs, v.validateStorageSize(newDB, oldDB)...)
+	allErrs = append(allErrs, v.validateNamespaceQuota(ctx, newDB, oldDB)...)
 	if len(allErrs) == 0 {
 		return nil, nil
 	}
@@ -80,6 +104,24 @@ func (v *DocumentDBValidator) ValidateDelete(_ context.Context, _ *dbpreview.Doc
 	return nil, nil
 }
 
+// withDefaults returns a copy of db with its DocumentDBClusterClass and the
+// defaults of its namespace applied, so that the values it gets from them are
+// validated as well. When they cannot be resolved db is validated as is; the
+// controller reports the missing class.
+func (v *DocumentDBValidator) withDefaults(ctx context.Context, db *dbpreview.DocumentDB) *dbpreview.DocumentDB {
+	resolved := db.DeepCopy()
+	if err := util.ResolveClusterClass(ctx, v.Client, resolved); err != nil {
+		documentdbLog.Info("Validating DocumentDB without its cluster class", "name", db.Name, "error", err.Error())
+		return db
+	}
+	withNamespaceDefaults := resolved.DeepCopy()
+	if err := util.ApplyNamespaceDefaults(ctx, v.Client, withNamespaceDefaults); err != nil {
+		documentdbLog.Info("Validating DocumentDB without its namespace defaults", "name", db.Name, "error", err.Error())
+		return resolved
+	}
+	return withNamespaceDefaults
+}
+
 // ---------------------------------------------------------------------------
 // Spec-level validations (run on both create and update)
 // ---------------------------------------------------------------------------
@@ -90,6 +132,25 @@ func (v *DocumentDBValidator) validate(db *dbpreview.DocumentDB) (allErrs field.
 	validations := []validationFunc{
 		v.validateSchemaVersionNotExceedsBinary,
 		v.validateResources,
+		v.validateTopology,
+		v.validateTimeouts,
+		v.validateExposeViaService,
+		v.validateAdditionalServices,
+		v.validateProbes,
+		v.validateGatewayPort,
+		v.validateCostLabels,
+		v.validateInheritedMetadata,
+		v.validateServiceAccount,
+		v.validatePlugins,
+		v.validateSecurityContext,
+		v.validateOpenShift,
+		v.validateExport,
+		v.validateClone,
+		v.validateMigration,
+		v.validateRecoveryTarget,
+		v.validateSelfHeal,
+		v.validateChangeStreams,
+		v.validateWALStorage,
 		// Add new spec-level validations here.
 	}
 	for _, fn := range validations {
@@ -106,6 +167,494 @@ func (v *DocumentDBValidator) validateResources(db *dbpreview.DocumentDB) field.
 	return cnpg.ValidateResources(db, cnpg.DefaultSplitConfig())
 }
 
+// validateTopology ensures the node, instance and replication settings can
+// be rendered together: a single node, since sharded clusters are not
+// supported yet, and a clusterReplication whose primary is one of its member
+// clusters. With highAvailability the primary requires acknowledgement from
+// three synchronous standbys, its two local replicas and the other member
+// clusters, so at least one other member must be listed or writes would
+// block forever.
+func (v *DocumentDBValidator) validateTopology(db *dbpreview.DocumentDB) (allErrs field.ErrorList) {
+	if db.Spec.NodeCount > 1 {
+		allErrs = append(allErrs, field.Invalid(field.NewPath("spec", "nodeCount"), db.Spec.NodeCount,
+			"multi-node (sharded) clusters are not supported yet; nodeCount must be 1"))
+	}
+
+	replication := db.Spec.ClusterReplication
+	if replication == nil {
+		return allErrs
+	}
+	path := field.NewPath("spec", "clusterReplication")
+	names := make(map[string]bool, len(replication.ClusterList))
+	for i, member := range replication.ClusterList {
+		if names[member.Name] {
+			allErrs = append(allErrs, field.Duplicate(path.Child("clusterList").Index(i).Child("name"), member.Name))
+		}
+		names[member.Name] = true
+	}
+	if !names[replication.Primary] {
+		allErrs = append(allErrs, field.Invalid(path.Child("primary"), replication.Primary,
+			"primary must be the name of a cluster in clusterList"))
+	}
+	if replication.HighAvailability && len(names) < 2 {
+		allErrs = append(allErrs, field.Invalid(path.Child("highAvailability"), true,
+			"highAvailability requires another cluster in clusterList; the primary alone cannot reach its quorum of three synchronous standbys"))
+	}
+	return allErrs
+}
+
+// validateTimeouts ensures spec.timeouts are within the ranges CNPG accepts,
+// so that an out-of-range value is rejected here rather than when the CNPG
+// Cluster is written.
+func (v *DocumentDBValidator) validateTimeouts(db *dbpreview.DocumentDB) (allErrs field.ErrorList) {
+	path := field.NewPath("spec", "timeouts")
+	stopDelay := db.Spec.Timeouts.StopDelay
+	if stopDelay < 0 || stopDelay > util.CNPG_MAX_STOP_DELAY {
+		allErrs = append(allErrs, field.Invalid(path.Child("stopDelay"), stopDelay,
+			fmt.Sprintf("stopDelay must be between 0 and %d seconds", util.CNPG_MAX_STOP_DELAY)))
+	}
+	if startDelay := db.Spec.Timeouts.StartDelay; startDelay < 0 {
+		allErrs = append(allErrs, field.Invalid(path.Child("startDelay"), startDelay, "startDelay must not be negative"))
+	}
+	// The smart shutdown must leave part of stopDelay to the fast shutdown
+	if smartShutdown := db.Spec.Timeouts.SmartShutdownTimeout; smartShutdown != nil {
+		effectiveStopDelay := int32(util.CNPG_DEFAULT_STOP_DELAY)
+		if stopDelay > 0 {
+			effectiveStopDelay = stopDelay
+		}
+		if *smartShutdown < 0 || *smartShutdown >= effectiveStopDelay {
+			allErrs = append(allErrs, field.Invalid(path.Child("smartShutdownTimeout"), *smartShutdown,
+				fmt.Sprintf("smartShutdownTimeout must be at least 0 and lower than the stopDelay of %d seconds", effectiveStopDelay)))
+		}
+	}
+	if switchoverDelay := db.Spec.Timeouts.SwitchoverDelay; switchoverDelay < 0 {
+		allErrs = append(allErrs, field.Invalid(path.Child("switchoverDelay"), switchoverDelay, "switchoverDelay must not be negative"))
+	}
+	if failoverDelay := db.Spec.Timeouts.FailoverDelay; failoverDelay < 0 {
+		allErrs = append(allErrs, field.Invalid(path.Child("failoverDelay"), failoverDelay, "failoverDelay must not be negative"))
+	}
+	return allErrs
+}
+
+// validateExposeViaService ensures spec.exposeViaService.serviceType is one of
+// the Service types the operator renders, since any other value would be
+// exposed as a ClusterIP Service, and that its hostname is a DNS name.
+func (v *DocumentDBValidator) validateExposeViaService(db *dbpreview.DocumentDB) (allErrs field.ErrorList) {
+	path := field.NewPath("spec", "exposeViaService")
+	switch corev1.ServiceType(db.Spec.ExposeViaService.ServiceType) {
+	case "", corev1.ServiceTypeLoadBalancer, corev1.ServiceTypeClusterIP:
+	default:
+		allErrs = append(allErrs, field.NotSupported(path.Child("serviceType"),
+			db.Spec.ExposeViaService.ServiceType,
+			[]string{string(corev1.ServiceTypeLoadBalancer), string(corev1.ServiceTypeClusterIP)}))
+	}
+	if hostname := db.Spec.ExposeViaService.Hostname; hostname != "" {
+		for _, msg := range validation.IsDNS1123Subdomain(hostname) {
+			allErrs = append(allErrs, field.Invalid(path.Child("hostname"), hostname, msg))
+		}
+	}
+	return allErrs
+}
+
+// validateAdditionalServices ensures spec.additionalServices names each
+// Service once and does not take the name of the Service the operator creates
+// for the gateway. CNPG itself rejects the names of its default Services.
+func (v *DocumentDBValidator) validateAdditionalServices(db *dbpreview.DocumentDB) (allErrs field.ErrorList) {
+	path := field.NewPath("spec", "additionalServices")
+	names := map[string]bool{}
+	for i, service := range db.Spec.AdditionalServices {
+		namePath := path.Index(i).Child("serviceTemplate", "metadata", "name")
+		name := service.ServiceTemplate.ObjectMeta.Name
+		switch {
+		case name == "":
+			allErrs = append(allErrs, field.Required(namePath, ""))
+		case name == util.DocumentDBServiceName(db):
+			allErrs = append(allErrs, field.Forbidden(namePath, fmt.Sprintf("Service %s is created by the operator", name)))
+		case names[name]:
+			allErrs = append(allErrs, field.Duplicate(namePath, name))
+		}
+		names[name] = true
+	}
+	return allErrs
+}
+
+// validateProbes ensures the probe overrides of spec.postgres.probes and
+// spec.gateway.probes are accepted by the pod spec: no negative durations or
+// thresholds, and a success threshold of 1 for liveness and startup probes.
+// Otherwise CNPG would fail to create the pods.
+func (v *DocumentDBValidator) validateProbes(db *dbpreview.DocumentDB) (allErrs field.ErrorList) {
+	if db.Spec.Postgres != nil && db.Spec.Postgres.Probes != nil {
+		probes := db.Spec.Postgres.Probes
+		path := field.NewPath("spec", "postgres", "probes")
+		if probes.Startup != nil {
+			allErrs = append(allErrs, validateProbe(&probes.Startup.Probe, true, path.Child("startup"))...)
+		}
+		if probes.Liveness != nil {
+			allErrs = append(allErrs, validateProbe(&probes.Liveness.Probe, true, path.Child("liveness"))...)
+		}
+		if probes.Readiness != nil {
+			allErrs = append(allErrs, validateProbe(&probes.Readiness.Probe, false, path.Child("readiness"))...)
+		}
+	}
+	if db.Spec.Gateway != nil && db.Spec.Gateway.Probes != nil {
+		probes := db.Spec.Gateway.Probes
+		path := field.NewPath("spec", "gateway", "probes")
+		if probes.Liveness != nil {
+			allErrs = append(allErrs, validateProbe(probes.Liveness, true, path.Child("liveness"))...)
+		}
+		if probes.Readiness != nil {
+			allErrs = append(allErrs, validateProbe(probes.Readiness, false, path.Child("readiness"))...)
+		}
+	}
+	return allErrs
+}
+
+// validateGatewayPort ensures the gateway port, spec.gateway.port or the
+// port of the operator settings, is free in the pods: not a port of
+// PostgreSQL, of the CNPG instance manager or of the sidecar, nor the port of
+// the Prometheus exporter of the OTel Collector. Two containers of a pod
+// cannot listen on the same port.
+func (v *DocumentDBValidator) validateGatewayPort(db *dbpreview.DocumentDB) (allErrs field.ErrorList) {
+	port := util.GetPortFor(util.GATEWAY_PORT)
+	path := field.NewPath("spec", "gateway", "port")
+	if db.Spec.Gateway != nil && db.Spec.Gateway.Port != 0 {
+		port = db.Spec.Gateway.Port
+		if user := util.GatewayPortConflict(port); user != "" {
+			allErrs = append(allErrs, field.Invalid(path, port, fmt.Sprintf("port %d is used by %s", port, user)))
+		}
+	}
+	if db.Spec.Monitoring != nil && db.Spec.Monitoring.Enabled && otelcfg.ResolvePrometheusPort(db.Spec.Monitoring) == port {
+		allErrs = append(allErrs, field.Invalid(field.NewPath("spec", "monitoring", "exporter", "prometheus", "port"), port,
+			fmt.Sprintf("port %d is the gateway port", port)))
+	}
+	return allErrs
+}
+
+func validateProbe(probe *cnpgv1.Probe, singleSuccess bool, path *field.Path) (allErrs field.ErrorList) {
+	for _, setting := range []struct {
+		name  string
+		value int32
+	}{
+		{"initialDelaySeconds", probe.InitialDelaySeconds},
+		{"timeoutSeconds", probe.TimeoutSeconds},
+		{"periodSeconds", probe.PeriodSeconds},
+		{"successThreshold", probe.SuccessThreshold},
+		{"failureThreshold", probe.FailureThreshold},
+	} {
+		if setting.value < 0 {
+			allErrs = append(allErrs, field.Invalid(path.Child(setting.name), setting.value, "must not be negative"))
+		}
+	}
+	if singleSuccess && probe.SuccessThreshold > 1 {
+		allErrs = append(allErrs, field.Invalid(path.Child("successThreshold"), probe.SuccessThreshold,
+			"must be 1 for liveness and startup probes"))
+	}
+	if probe.TerminationGracePeriodSeconds != nil && *probe.TerminationGracePeriodSeconds < 0 {
+		allErrs = append(allErrs, field.Invalid(path.Child("terminationGracePeriodSeconds"),
+			*probe.TerminationGracePeriodSeconds, "must not be negative"))
+	}
+	return allErrs
+}
+
+// validateCostLabels ensures spec.costLabels are valid Kubernetes labels, so
+// that they can be stamped onto the derived objects.
+func (v *DocumentDBValidator) validateCostLabels(db *dbpreview.DocumentDB) field.ErrorList {
+	return metav1validation.ValidateLabels(db.Spec.CostLabels, field.NewPath("spec", "costLabels"))
+}
+
+// validateInheritedMetadata ensures spec.inheritedMetadata holds valid labels
+// and annotations, which CNPG copies onto the pods, PVCs and Services.
+func (v *DocumentDBValidator) validateInheritedMetadata(db *dbpreview.DocumentDB) (allErrs field.ErrorList) {
+	if db.Spec.InheritedMetadata == nil {
+		return nil
+	}
+	path := field.NewPath("spec", "inheritedMetadata")
+	allErrs = append(allErrs, metav1validation.ValidateLabels(db.Spec.InheritedMetadata.Labels, path.Child("labels"))...)
+	allErrs = append(allErrs, apivalidation.ValidateAnnotations(db.Spec.InheritedMetadata.Annotations, path.Child("annotations"))...)
+	return allErrs
+}
+
+// validateServiceAccount ensures spec.serviceAccount holds valid labels and
+// annotations for the ServiceAccount of the cluster, and a workload identity
+// that the annotations do not contradict and that can access the object
+// stores used without a credentials Secret.
+func (v *DocumentDBValidator) validateServiceAccount(db *dbpreview.DocumentDB) (allErrs field.ErrorList) {
+	if db.Spec.ServiceAccount == nil {
+		return nil
+	}
+	path := field.NewPath("spec", "serviceAccount")
+	allErrs = append(allErrs, metav1validation.ValidateLabels(db.Spec.ServiceAccount.Labels, path.Child("labels"))...)
+	allErrs = append(allErrs, apivalidation.ValidateAnnotations(db.Spec.ServiceAccount.Annotations, path.Child("annotations"))...)
+
+	identity := db.Spec.ServiceAccount.WorkloadIdentity
+	if identity == nil {
+		return allErrs
+	}
+	identityPath := path.Child("workloadIdentity")
+	switch identity.Provider {
+	case dbpreview.WorkloadIdentityAWS:
+		if !strings.HasPrefix(identity.Identity, "arn:") {
+			allErrs = append(allErrs, field.Invalid(identityPath.Child("identity"), identity.Identity,
+				"must be the ARN of an IAM role, e.g. arn:aws:iam::123456789012:role/documentdb"))
+		}
+	case dbpreview.WorkloadIdentityAzure:
+		if !azureClientIDPattern.MatchString(identity.Identity) {
+			allErrs = append(allErrs, field.Invalid(identityPath.Child("identity"), identity.Identity,
+				"must be the client ID of a managed identity or application"))
+		}
+	case dbpreview.WorkloadIdentityGCP:
+		if !strings.Contains(identity.Identity, "@") {
+			allErrs = append(allErrs, field.Invalid(identityPath.Child("identity"), identity.Identity,
+				"must be the email of a Google service account"))
+		}
+	}
+	annotation := cnpg.WorkloadIdentityAnnotation(identity.Provider)
+	if value, ok := db.Spec.ServiceAccount.Annotations[annotation]; ok && value != identity.Identity {
+		allErrs = append(allErrs, field.Invalid(path.Child("annotations").Key(annotation), value,
+			"conflicts with spec.serviceAccount.workloadIdentity"))
+	}
+
+	// Without a credentials Secret, an object store is accessed with the
+	// credentials of the provider its URL belongs to
+	if bootstrap := db.Spec.Bootstrap; bootstrap != nil && bootstrap.Recovery != nil && bootstrap.Recovery.ObjectStore != nil &&
+		bootstrap.Recovery.ObjectStore.CredentialsSecret == nil {
+		objectStore := bootstrap.Recovery.ObjectStore
+		provider := dbpreview.WorkloadIdentityAWS
+		switch {
+		case strings.HasPrefix(objectStore.DestinationPath, "gs://"):
+			provider = dbpreview.WorkloadIdentityGCP
+		case strings.HasPrefix(objectStore.DestinationPath, "https://"):
+			provider = dbpreview.WorkloadIdentityAzure
+		}
+		if provider != identity.Provider {
+			allErrs = append(allErrs, field.Invalid(field.NewPath("spec", "bootstrap", "recovery", "objectStore", "destinationPath"),
+				objectStore.DestinationPath, fmt.Sprintf("a %s workload identity cannot access this object store; set credentialsSecret", identity.Provider)))
+		}
+	}
+	if db.Spec.Export != nil && db.Spec.Export.Destination.CredentialsSecret == nil && identity.Provider != dbpreview.WorkloadIdentityAWS {
+		allErrs = append(allErrs, field.Required(field.NewPath("spec", "export", "destination", "credentialsSecret"),
+			fmt.Sprintf("exports to S3 cannot use a %s workload identity", identity.Provider)))
+	}
+	return allErrs
+}
+
+// validatePlugins ensures spec.plugins.additional names each plugin once,
+// and neither the sidecar injector nor the WAL replica plugin, which the
+// operator configures itself, nor makes more than one the WAL archiver.
+func (v *DocumentDBValidator) validatePlugins(db *dbpreview.DocumentDB) (allErrs field.ErrorList) {
+	if db.Spec.Plugins == nil {
+		return nil
+	}
+	reserved := map[string]bool{
+		cmp.Or(db.Spec.Plugins.SidecarInjectorName, util.DEFAULT_SIDECAR_INJECTOR_PLUGIN): true,
+		cmp.Or(db.Spec.Plugins.WalReplicaName, util.DEFAULT_WAL_REPLICA_PLUGIN):           true,
+	}
+	path := field.NewPath("spec", "plugins", "additional")
+	names := map[string]bool{}
+	walArchivers := 0
+	for i, plugin := range db.Spec.Plugins.Additional {
+		namePath := path.Index(i).Child("name")
+		switch {
+		case plugin.Name == "":
+			allErrs = append(allErrs, field.Required(namePath, ""))
+		case reserved[plugin.Name]:
+			allErrs = append(allErrs, field.Forbidden(namePath, fmt.Sprintf("plugin %s is configured by the operator", plugin.Name)))
+		case names[plugin.Name]:
+			allErrs = append(allErrs, field.Duplicate(namePath, plugin.Name))
+		}
+		names[plugin.Name] = true
+		if plugin.IsWALArchiver != nil && *plugin.IsWALArchiver {
+			walArchivers++
+			if walArchivers > 1 {
+				allErrs = append(allErrs, field.Invalid(path.Index(i).Child("isWALArchiver"), true, "only one plugin can be the WAL archiver"))
+			}
+		}
+	}
+	return allErrs
+}
+
+// validateExport ensures spec.export.schedule is a cron expression the
+// export CronJob accepts.
+func (v *DocumentDBValidator) validateExport(db *dbpreview.DocumentDB) field.ErrorList {
+	if db.Spec.Export == nil {
+		return nil
+	}
+	if _, err := cron.ParseStandard(db.Spec.Export.Schedule); err != nil {
+		return field.ErrorList{field.Invalid(field.NewPath("spec", "export", "schedule"), db.Spec.Export.Schedule, err.Error())}
+	}
+	return nil
+}
+
+// validateClone ensures spec.bootstrap.clone does not reference the cluster
+// itself.
+func (v *DocumentDBValidator) validateClone(db *dbpreview.DocumentDB) field.ErrorList {
+	if !db.IsCloneConfigured() {
+		return nil
+	}
+	if db.GetCloneSource() == (types.NamespacedName{Name: db.Name, Namespace: db.Namespace}) {
+		return field.ErrorList{field.Invalid(field.NewPath("spec", "bootstrap", "clone", "sourceRef"),
+			db.Spec.Bootstrap.Clone.SourceRef, "a DocumentDB cannot be cloned from itself")}
+	}
+	return nil
+}
+
+// validateMigration ensures spec.migration does not reference the cluster
+// itself.
+func (v *DocumentDBValidator) validateMigration(db *dbpreview.DocumentDB) field.ErrorList {
+	if db.Spec.Migration == nil || db.Spec.Migration.SourceRef.Name != db.Name {
+		return nil
+	}
+	return field.ErrorList{field.Invalid(field.NewPath("spec", "migration", "sourceRef", "name"),
+		db.Spec.Migration.SourceRef.Name, "a DocumentDB cannot migrate from itself")}
+}
+
+// validateRecoveryTarget ensures spec.bootstrap.recovery.recoveryTarget
+// sets a single target in a format PostgreSQL accepts, and that the targets
+// CNPG cannot locate a base backup for in an object store name the backup.
+func (v *DocumentDBValidator) validateRecoveryTarget(db *dbpreview.DocumentDB) (allErrs field.ErrorList) {
+	if db.Spec.Bootstrap == nil || db.Spec.Bootstrap.Recovery == nil || db.Spec.Bootstrap.Recovery.RecoveryTarget == nil {
+		return nil
+	}
+	target := db.Spec.Bootstrap.Recovery.RecoveryTarget
+	path := field.NewPath("spec", "bootstrap", "recovery", "recoveryTarget")
+
+	targets := 0
+	for _, set := range []bool{target.TargetTime != "", target.TargetName != "", target.TargetXID != "",
+		target.TargetLSN != "", target.TargetImmediate != nil} {
+		if set {
+			targets++
+		}
+	}
+	if targets > 1 {
+		allErrs = append(allErrs, field.Invalid(path, target,
+			"only one of targetTime, targetName, targetXID, targetLSN and targetImmediate can be specified"))
+	}
+	if target.TargetTime != "" {
+		if _, err := pgtypes.ParseTargetTime(nil, target.TargetTime); err != nil {
+			allErrs = append(allErrs, field.Invalid(path.Child("targetTime"), target.TargetTime, err.Error()))
+		}
+	}
+	if target.TargetLSN != "" {
+		if _, err := pgtypes.LSN(target.TargetLSN).Parse(); err != nil {
+			allErrs = append(allErrs, field.Invalid(path.Child("targetLSN"), target.TargetLSN, err.Error()))
+		}
+	}
+	// A Backup names its base backup, but in an object store CNPG picks the
+	// base backup by time or LSN only
+	labelBased := target.TargetName != "" || target.TargetXID != "" || target.TargetImmediate != nil
+	if labelBased && db.Spec.Bootstrap.Recovery.ObjectStore != nil && target.BackupID == "" {
+		allErrs = append(allErrs, field.Required(path.Child("backupID"),
+			"a targetName, targetXID or targetImmediate recovery from an object store needs the ID of the base backup"))
+	}
+	return allErrs
+}
+
+// validateSecurityContext ensures spec.securityContext does not conflict with
+// the process identity of spec.postgres and carries a usable seccomp profile.
+func (v *DocumentDBValidator) validateSecurityContext(db *dbpreview.DocumentDB) (allErrs field.ErrorList) {
+	sc := db.Spec.SecurityContext
+	if sc == nil {
+		return nil
+	}
+	path := field.NewPath("spec", "securityContext")
+	if pg := db.Spec.Postgres; pg != nil {
+		if sc.RunAsUser != nil && pg.UID != nil {
+			allErrs = append(allErrs, field.Forbidden(path.Child("runAsUser"), "cannot be combined with spec.postgres.uid"))
+		}
+		if sc.RunAsGroup != nil && pg.GID != nil {
+			allErrs = append(allErrs, field.Forbidden(path.Child("runAsGroup"), "cannot be combined with spec.postgres.gid"))
+		}
+	}
+	if profile := sc.SeccompProfile; profile != nil {
+		profilePath := path.Child("seccompProfile")
+		switch profile.Type {
+		case corev1.SeccompProfileTypeLocalhost:
+			if profile.LocalhostProfile == nil || *profile.LocalhostProfile == "" {
+				allErrs = append(allErrs, field.Required(profilePath.Child("localhostProfile"),
+					"must be set when the type is Localhost"))
+			}
+		case corev1.SeccompProfileTypeRuntimeDefault, corev1.SeccompProfileTypeUnconfined:
+			if profile.LocalhostProfile != nil {
+				allErrs = append(allErrs, field.Forbidden(profilePath.Child("localhostProfile"),
+					"can only be set when the type is Localhost"))
+			}
+		default:
+			allErrs = append(allErrs, field.NotSupported(profilePath.Child("type"), profile.Type, []corev1.SeccompProfileType{
+				corev1.SeccompProfileTypeRuntimeDefault, corev1.SeccompProfileTypeLocalhost, corev1.SeccompProfileTypeUnconfined,
+			}))
+		}
+	}
+	return allErrs
+}
+
+// validateOpenShift rejects, when the operator runs in OpenShift mode, the
+// settings that the restricted-v2 SCC would refuse to admit: a fixed postgres
+// UID/GID and the IOUring feature gate, whose Localhost seccomp profile is not
+// allowed.
+func (v *DocumentDBValidator) validateOpenShift(db *dbpreview.DocumentDB) (allErrs field.ErrorList) {
+	if !util.IsOpenShift() {
+		return nil
+	}
+	if pg := db.Spec.Postgres; pg != nil {
+		path := field.NewPath("spec", "postgres")
+		if pg.UID != nil {
+			allErrs = append(allErrs, field.Forbidden(path.Child("uid"),
+				"the UID is assigned by OpenShift from the namespace range"))
+		}
+		if pg.GID != nil {
+			allErrs = append(allErrs, field.Forbidden(path.Child("gid"),
+				"the GID is assigned by OpenShift from the namespace range"))
+		}
+	}
+	if dbpreview.IsFeatureGateEnabled(db, dbpreview.FeatureGateIOUring) {
+		allErrs = append(allErrs, field.Forbidden(
+			field.NewPath("spec", "featureGates").Key(dbpreview.FeatureGateIOUring),
+			"the io_uring seccomp profile is not allowed by the restricted-v2 SCC"))
+	}
+	if sc := db.Spec.SecurityContext; sc != nil {
+		path := field.NewPath("spec", "securityContext")
+		ids := []struct {
+			name string
+			id   *int64
+		}{{"runAsUser", sc.RunAsUser}, {"runAsGroup", sc.RunAsGroup}, {"fsGroup", sc.FSGroup}}
+		for _, id := range ids {
+			if id.id != nil {
+				allErrs = append(allErrs, field.Forbidden(path.Child(id.name),
+					"the IDs are assigned by OpenShift from the namespace range"))
+			}
+		}
+		if sc.SeccompProfile != nil && sc.SeccompProfile.Type != corev1.SeccompProfileTypeRuntimeDefault {
+			allErrs = append(allErrs, field.Forbidden(path.Child("seccompProfile"),
+				"only RuntimeDefault is allowed by the restricted-v2 SCC"))
+		}
+	}
+	return allErrs
+}
+
+// validateSelfHeal ensures spec.selfHeal recreates a replica before it
+// reclones it: with a shorter reclone delay the recreation never happens.
+func (v *DocumentDBValidator) validateSelfHeal(db *dbpreview.DocumentDB) field.ErrorList {
+	selfHeal := db.Spec.SelfHeal
+	if selfHeal == nil || selfHeal.RecreateAfterMinutes == 0 || selfHeal.RecloneAfterMinutes == 0 {
+		return nil
+	}
+	if selfHeal.RecloneAfterMinutes <= selfHeal.RecreateAfterMinutes {
+		return field.ErrorList{field.Invalid(field.NewPath("spec", "selfHeal", "recloneAfterMinutes"),
+			selfHeal.RecloneAfterMinutes, "recloneAfterMinutes must be longer than recreateAfterMinutes")}
+	}
+	return nil
+}
+
+// validateChangeStreams ensures spec.changeStreams comes with the
+// ChangeStreams feature gate, without which it would be silently ignored.
+func (v *DocumentDBValidator) validateChangeStreams(db *dbpreview.DocumentDB) field.ErrorList {
+	if db.Spec.ChangeStreams == nil || dbpreview.IsFeatureGateEnabled(db, dbpreview.FeatureGateChangeStreams) {
+		return nil
+	}
+	return field.ErrorList{field.Forbidden(field.NewPath("spec", "changeStreams"),
+		fmt.Sprintf("requires the %s feature gate", dbpreview.FeatureGateChangeStreams))}
+}
+
 // validateSchemaVersionNotExceedsBinary ensures spec.schemaVersion <= binary version.
 func (v *DocumentDBValidator) validateSchemaVersionNotExceedsBinary(db *dbpreview.DocumentDB) field.ErrorList {
 	if db.Spec.SchemaVersion == "" || db.Spec.SchemaVersion == "auto" {
@@ -144,6 +693,96 @@ func (v *DocumentDBValidator) validateSchemaVersionNotExceedsBinary(db *dbprevie
 	return nil
 }
 
+// ---------------------------------------------------------------------------
+// Storage size (run on both create and update)
+// ---------------------------------------------------------------------------
+
+// validateStorageSize ensures spec.resource.storage.pvcSize is a valid
+// quantity of at least the operator-wide minimum. oldDB is nil on create. Once
+// a size is set, validateStorageResize checks its changes and only lets it
+// grow, so that clusters created before the minimum was raised can still be
+// changed.
+func (v *DocumentDBValidator) validateStorageSize(newDB, oldDB *dbpreview.DocumentDB) field.ErrorList {
+	size := newDB.Spec.Resource.Storage.PvcSize
+	// An empty size is left to a cluster class that could not be resolved
+	if size == "" || (oldDB != nil && oldDB.Spec.Resource.Storage.PvcSize != "") {
+		return nil
+	}
+
+	pvcSizePath := field.NewPath("spec", "resource", "storage", "pvcSize")
+	quantity, err := resource.ParseQuantity(size)
+	if err != nil {
+		return field.ErrorList{field.Invalid(pvcSizePath, size,
+			fmt.Sprintf("pvcSize must be a valid resource quantity: %v", err))}
+	}
+	if minSize := util.GetMinPVCSize(); minSize != nil && quantity.Cmp(*minSize) < 0 {
+		return field.ErrorList{field.Invalid(pvcSizePath, size, fmt.Sprintf(
+			"pvcSize must be at least %s, the minimum set by %s", minSize.String(), util.MIN_PVC_SIZE_ENV))}
+	}
+	return nil
+}
+
+// ---------------------------------------------------------------------------
+// Namespace quota (run on both create and update)
+// ---------------------------------------------------------------------------
+
+// validateNamespaceQuota enforces the operator-wide limits on the number of
+// DocumentDB clusters in a namespace and on the total storage they request.
+// oldDB is nil on create. On update only a storage increase is checked, so that
+// clusters in a namespace already above a lowered limit can still be changed.
+func (v *DocumentDBValidator) validateNamespaceQuota(ctx context.Context, newDB, oldDB *dbpreview.DocumentDB) field.ErrorList {
+	maxClusters := util.GetMaxClustersPerNamespace()
+	maxStorage := util.GetMaxStoragePerNamespace()
+	total := requestedStorage(newDB)
+	if oldDB != nil {
+		maxClusters = 0
+		if total.Cmp(requestedStorage(oldDB)) <= 0 {
+			maxStorage = nil
+		}
+	}
+	if maxClusters == 0 && maxStorage == nil {
+		return nil
+	}
+
+	namespacePath := field.NewPath("metadata", "namespace")
+	documentdbs := &dbpreview.DocumentDBList{}
+	if err := v.List(ctx, documentdbs, client.InNamespace(newDB.Namespace)); err != nil {
+		return field.ErrorList{field.InternalError(namespacePath, fmt.Errorf("failed to list DocumentDBs: %w", err))}
+	}
+	others := 0
+	for i := range documentdbs.Items {
+		if documentdbs.Items[i].Name == newDB.Name {
+			continue
+		}
+		others++
+		total.Add(requestedStorage(v.withDefaults(ctx, &documentdbs.Items[i])))
+	}
+
+	var allErrs field.ErrorList
+	if maxClusters > 0 && others >= maxClusters {
+		allErrs = append(allErrs, field.Forbidden(namespacePath, fmt.Sprintf(
+			"namespace %s already has %d DocumentDB clusters, the maximum allowed by %s",
+			newDB.Namespace, others, util.MAX_CLUSTERS_PER_NAMESPACE_ENV)))
+	}
+	if maxStorage != nil && total.Cmp(*maxStorage) > 0 {
+		allErrs = append(allErrs, field.Forbidden(field.NewPath("spec", "resource", "storage", "pvcSize"), fmt.Sprintf(
+			"DocumentDB clusters in namespace %s would request %s of storage, more than the %s allowed by %s",
+			newDB.Namespace, total.String(), maxStorage.String(), util.MAX_STORAGE_PER_NAMESPACE_ENV)))
+	}
+	return allErrs
+}
+
+// requestedStorage returns the storage requested by all instances of db. A
+// missing or invalid pvcSize counts as zero; it is reported by other checks.
+func requestedStorage(db *dbpreview.DocumentDB) resource.Quantity {
+	size, err := resource.ParseQuantity(db.Spec.Resource.Storage.PvcSize)
+	if err != nil {
+		return resource.Quantity{Format: resource.BinarySI}
+	}
+	instances := int64(max(db.Spec.InstancesPerNode, 1))
+	return *resource.NewQuantity(size.Value()*instances, resource.BinarySI)
+}
+
 // ---------------------------------------------------------------------------
 // Update-only validations (compare old and new)
 // ---------------------------------------------------------------------------
@@ -155,6 +794,8 @@ func (v *DocumentDBValidator) validateChanges(newDB, oldDB *dbpreview.DocumentDB
 		v.validateImageRollback,
 		v.validateImmutableFields,
 		v.validateStorageResize,
+		v.validateWALStorageResize,
+		v.validateMigrationChanges,
 	}
 	for _, fn := range validations {
 		allErrs = append(allErrs, fn(newDB, oldDB)...)
@@ -229,15 +870,71 @@ func (v *DocumentDBValidator) validateImmutableFields(newDB, oldDB *dbpreview.Do
 	return allErrs
 }
 
+// validateMigrationChanges ensures spec.migration is only set at creation,
+// when the CNPG Cluster gets the connection to the source, keeps its source,
+// and is not cut back over once cut over. Removing spec.migration is allowed
+// and stops the replication.
+func (v *DocumentDBValidator) validateMigrationChanges(newDB, oldDB *dbpreview.DocumentDB) field.ErrorList {
+	newMigration, oldMigration := newDB.Spec.Migration, oldDB.Spec.Migration
+	if newMigration == nil {
+		return nil
+	}
+	path := field.NewPath("spec", "migration")
+	if oldMigration == nil {
+		return field.ErrorList{field.Forbidden(path, "migration cannot be added after cluster creation")}
+	}
+	var allErrs field.ErrorList
+	if newMigration.SourceRef != oldMigration.SourceRef {
+		allErrs = append(allErrs, field.Forbidden(path.Child("sourceRef"), "migration source cannot be changed"))
+	}
+	if oldMigration.Cutover && !newMigration.Cutover {
+		allErrs = append(allErrs, field.Forbidden(path.Child("cutover"), "cutover cannot be unset once set"))
+	}
+	return allErrs
+}
+
+// validateWALStorage ensures spec.resource.storage.wal.pvcSize is a valid
+// quantity.
+func (v *DocumentDBValidator) validateWALStorage(db *dbpreview.DocumentDB) field.ErrorList {
+	wal := db.Spec.Resource.Storage.WAL
+	if wal == nil {
+		return nil
+	}
+	if _, err := resource.ParseQuantity(wal.PvcSize); err != nil {
+		return field.ErrorList{field.Invalid(field.NewPath("spec", "resource", "storage", "wal", "pvcSize"), wal.PvcSize,
+			fmt.Sprintf("pvcSize must be a valid resource quantity: %v", err))}
+	}
+	return nil
+}
+
 // validateStorageResize ensures PVC size can only grow, never shrink.
 func (v *DocumentDBValidator) validateStorageResize(newDB, oldDB *dbpreview.DocumentDB) field.ErrorList {
-	oldSize := oldDB.Spec.Resource.Storage.PvcSize
-	newSize := newDB.Spec.Resource.Storage.PvcSize
+	return validatePvcSizeGrowth(field.NewPath("spec", "resource", "storage", "pvcSize"),
+		oldDB.Spec.Resource.Storage.PvcSize, newDB.Spec.Resource.Storage.PvcSize)
+}
+
+// validateWALStorageResize ensures the WAL PVC size can only grow. The CRD
+// rejects removing spec.resource.storage.wal.
+func (v *DocumentDBValidator) validateWALStorageResize(newDB, oldDB *dbpreview.DocumentDB) field.ErrorList {
+	oldWAL, newWAL := oldDB.Spec.Resource.Storage.WAL, newDB.Spec.Resource.Storage.WAL
+	if oldWAL == nil || newWAL == nil {
+		return nil
+	}
+	return validatePvcSizeGrowth(field.NewPath("spec", "resource", "storage", "wal", "pvcSize"),
+		oldWAL.PvcSize, newWAL.PvcSize)
+}
+
+// validatePvcSizeGrowth ensures the PVC size at pvcSizePath changes from
+// oldSize to a valid, larger newSize.
+func validatePvcSizeGrowth(pvcSizePath *field.Path, oldSize, newSize string) field.ErrorList {
 	if oldSize == newSize {
 		return nil
 	}
+	// An empty size is left to a cluster class that could not be resolved
+	if oldSize == "" || newSize == "" {
+		return nil
+	}
 
-	pvcSizePath := field.NewPath("spec", "resource", "storage", "pvcSize")
 	var allErrs field.ErrorList
 
 	oldQty, errOld := resource.ParseQuantity(oldSize)
@@ -287,14 +984,14 @@ func isBootstrapEqual(a, b *dbpreview.BootstrapConfiguration) bool {
 // ---------------------------------------------------------------------------
 
 // resolveBinaryVersion extracts the effective binary version from a DocumentDB spec.
-// Priority: image.documentDB tag > documentDBVersion > "" (unknown).
+// Priority: image.documentDB tag > util.DocumentDBVersion > "" (unknown).
 // Digest-only references (e.g., "image@sha256:...") are not parseable as versions
 // and return "".
 func resolveBinaryVersion(db *dbpreview.DocumentDB) string {
 	if ref := specImageDocumentDB(db); ref != "" {
 		// Ignore digest-only references — they don't carry a version tag
 		if strings.Contains(ref, "@sha256:") {
-			return db.Spec.DocumentDBVersion
+			return util.DocumentDBVersion(db)
 		}
 		if tagIdx := strings.LastIndex(ref, ":"); tagIdx >= 0 {
 			tag := ref[tagIdx+1:]
@@ -304,7 +1001,7 @@ func resolveBinaryVersion(db *dbpreview.DocumentDB) string {
 			}
 		}
 	}
-	return db.Spec.DocumentDBVersion
+	return util.DocumentDBVersion(db)
 }
 
 // specImageDocumentDB safely returns spec.image.documentDB or "" when unset.
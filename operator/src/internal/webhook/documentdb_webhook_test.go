@@ -9,15 +9,18 @@ import (
 	cnpgv1 "github.com/cloudnative-pg/cloudnative-pg/api/v1"
 	. "github.com/onsi/ginkgo/v2"
 	. "github.com/onsi/gomega"
+	corev1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/runtime"
 	"k8s.io/client-go/rest"
+	"k8s.io/utils/ptr"
 	ctrl "sigs.k8s.io/controller-runtime"
 	ctrlclient "sigs.k8s.io/controller-runtime/pkg/client"
 	"sigs.k8s.io/controller-runtime/pkg/client/fake"
 	"sigs.k8s.io/controller-runtime/pkg/webhook"
 
 	dbpreview "github.com/documentdb/documentdb-operator/api/preview"
+	util "github.com/documentdb/documentdb-operator/internal/utils"
 )
 
 type fakeWebhookManager struct {
@@ -70,6 +73,15 @@ func newTestDocumentDB(version, schemaVersion, image string) *dbpreview.Document
 	return db
 }
 
+// newTestValidator returns a validator whose client holds objs, for the
+// validations that read the cluster class or the namespace defaults.
+func newTestValidator(objs ...ctrlclient.Object) *DocumentDBValidator {
+	scheme := runtime.NewScheme()
+	Expect(dbpreview.AddToScheme(scheme)).To(Succeed())
+	Expect(corev1.AddToScheme(scheme)).To(Succeed())
+	return &DocumentDBValidator{Client: fake.NewClientBuilder().WithScheme(scheme).WithObjects(objs...).Build()}
+}
+
 var _ = Describe("schema version validation", func() {
 	var v *DocumentDBValidator
 
@@ -242,7 +254,7 @@ var _ = Describe("ValidateCreate admission handler", func() {
 	var v *DocumentDBValidator
 
 	BeforeEach(func() {
-		v = &DocumentDBValidator{}
+		v = newTestValidator()
 	})
 
 	It("allows a valid DocumentDB resource", func() {
@@ -263,7 +275,7 @@ var _ = Describe("ValidateUpdate admission handler", func() {
 	var v *DocumentDBValidator
 
 	BeforeEach(func() {
-		v = &DocumentDBValidator{}
+		v = newTestValidator()
 	})
 
 	It("allows a valid upgrade", func() {
@@ -292,6 +304,36 @@ var _ = Describe("ValidateUpdate admission handler", func() {
 	})
 })
 
+var _ = Describe("namespace defaults", func() {
+	defaults := &corev1.ConfigMap{
+		ObjectMeta: metav1.ObjectMeta{Name: util.NAMESPACE_DEFAULTS_CONFIG_MAP, Namespace: "default"},
+		Data: map[string]string{
+			util.DEFAULT_STORAGE_CLASS_ENV: "fast",
+			util.DEFAULT_SERVICE_TYPE_ENV:  "LoadBalancer",
+		},
+	}
+
+	It("validates a new DocumentDB with the defaults of its namespace", func() {
+		v := newTestValidator(defaults)
+		db := newTestDocumentDB("", "", "")
+
+		resolved := v.withDefaults(context.Background(), db)
+		Expect(resolved.Spec.Resource.Storage.StorageClass).To(Equal("fast"))
+		Expect(resolved.Spec.ExposeViaService.ServiceType).To(Equal("LoadBalancer"))
+		Expect(db.Spec.Resource.Storage.StorageClass).To(BeEmpty())
+	})
+
+	It("validates an existing DocumentDB with the defaults recorded in its status", func() {
+		v := newTestValidator(defaults)
+		db := newTestDocumentDB("", "", "")
+		db.Status.NamespaceDefaults = &dbpreview.NamespaceDefaultsStatus{ServiceType: "ClusterIP"}
+
+		resolved := v.withDefaults(context.Background(), db)
+		Expect(resolved.Spec.Resource.Storage.StorageClass).To(BeEmpty())
+		Expect(resolved.Spec.ExposeViaService.ServiceType).To(Equal("ClusterIP"))
+	})
+})
+
 var _ = Describe("ValidateDelete admission handler", func() {
 	It("always allows deletion", func() {
 		v := &DocumentDBValidator{}
@@ -510,6 +552,88 @@ var _ = Describe("validateStorageResize", func() {
 	})
 })
 
+var _ = Describe("WAL storage validation", func() {
+	v := &DocumentDBValidator{}
+
+	withWAL := func(size string) *dbpreview.DocumentDB {
+		db := newTestDocumentDB("", "", "")
+		db.Spec.Resource.Storage.WAL = &dbpreview.WALStorageConfiguration{PvcSize: size}
+		return db
+	}
+
+	It("allows growing the WAL volume and adding it", func() {
+		Expect(v.validateWALStorageResize(withWAL("10Gi"), withWAL("5Gi"))).To(BeEmpty())
+		Expect(v.validateWALStorageResize(withWAL("10Gi"), newTestDocumentDB("", "", ""))).To(BeEmpty())
+	})
+
+	It("rejects shrinking the WAL volume", func() {
+		errs := v.validateWALStorageResize(withWAL("5Gi"), withWAL("10Gi"))
+		Expect(errs).To(HaveLen(1))
+		Expect(errs[0].Field).To(Equal("spec.resource.storage.wal.pvcSize"))
+		Expect(errs[0].Detail).To(ContainSubstring("shrink"))
+	})
+
+	It("rejects an invalid WAL size", func() {
+		Expect(v.validateWALStorage(withWAL("10Gi"))).To(BeEmpty())
+		errs := v.validateWALStorage(withWAL("lots"))
+		Expect(errs).To(HaveLen(1))
+		Expect(errs[0].Field).To(Equal("spec.resource.storage.wal.pvcSize"))
+	})
+})
+
+var _ = Describe("validateStorageSize", func() {
+	var v *DocumentDBValidator
+
+	BeforeEach(func() {
+		v = newTestValidator()
+		DeferCleanup(func() { util.SetOperatorSettings(nil) })
+	})
+
+	It("rejects a pvcSize that is not a quantity on create", func() {
+		db := newTestDocumentDB("", "", "")
+		db.Spec.Resource.Storage.PvcSize = "ten gigs"
+
+		errs := v.validateStorageSize(db, nil)
+		Expect(errs).To(HaveLen(1))
+		Expect(errs[0].Field).To(Equal("spec.resource.storage.pvcSize"))
+		Expect(errs[0].Detail).To(ContainSubstring("pvcSize must be a valid resource quantity"))
+	})
+
+	It("rejects a pvcSize below the configured minimum on create", func() {
+		util.SetOperatorSettings(map[string]string{util.MIN_PVC_SIZE_ENV: "20Gi"})
+		db := newTestDocumentDB("", "", "")
+
+		errs := v.validateStorageSize(db, nil)
+		Expect(errs).To(HaveLen(1))
+		Expect(errs[0].Detail).To(ContainSubstring("at least 20Gi"))
+
+		db.Spec.Resource.Storage.PvcSize = "20Gi"
+		Expect(v.validateStorageSize(db, nil)).To(BeEmpty())
+	})
+
+	It("leaves sizes that were already set to validateStorageResize", func() {
+		util.SetOperatorSettings(map[string]string{util.MIN_PVC_SIZE_ENV: "20Gi"})
+		oldDB := newTestDocumentDB("", "", "")
+		newDB := newTestDocumentDB("", "", "")
+		newDB.Spec.InstancesPerNode = 2
+
+		Expect(v.validateStorageSize(newDB, oldDB)).To(BeEmpty())
+
+		oldDB.Spec.Resource.Storage.PvcSize = ""
+		Expect(v.validateStorageSize(newDB, oldDB)).To(HaveLen(1))
+	})
+
+	It("rejects a shrinking pvcSize through ValidateUpdate", func() {
+		oldDB := newTestDocumentDB("", "", "")
+		newDB := newTestDocumentDB("", "", "")
+		newDB.Spec.Resource.Storage.PvcSize = "5Gi"
+
+		_, err := v.ValidateUpdate(context.Background(), oldDB, newDB)
+		Expect(err).To(HaveOccurred())
+		Expect(err.Error()).To(ContainSubstring("storage size can only be increased"))
+	})
+})
+
 var _ = Describe("resource envelope validation", func() {
 	var v *DocumentDBValidator
 
@@ -555,3 +679,690 @@ var _ = Describe("resource envelope validation", func() {
 		Expect(v.validateResources(db)).ToNot(BeEmpty())
 	})
 })
+
+var _ = Describe("topology validation", func() {
+	var v *DocumentDBValidator
+
+	BeforeEach(func() { v = &DocumentDBValidator{} })
+
+	replicated := func(primary string, highAvailability bool, members ...string) *dbpreview.DocumentDB {
+		db := newTestDocumentDB("", "", "")
+		db.Spec.ClusterReplication = &dbpreview.ClusterReplication{Primary: primary, HighAvailability: highAvailability}
+		for _, name := range members {
+			db.Spec.ClusterReplication.ClusterList = append(db.Spec.ClusterReplication.ClusterList, dbpreview.MemberCluster{Name: name})
+		}
+		return db
+	}
+
+	It("rejects more than one node", func() {
+		db := newTestDocumentDB("", "", "")
+		db.Spec.NodeCount = 2
+		errs := v.validateTopology(db)
+		Expect(errs).To(HaveLen(1))
+		Expect(errs[0].Field).To(Equal("spec.nodeCount"))
+	})
+
+	It("allows a highly available primary with a replica cluster", func() {
+		Expect(v.validateTopology(replicated("east", true, "east", "west"))).To(BeEmpty())
+	})
+
+	It("rejects a highly available primary without another cluster", func() {
+		errs := v.validateTopology(replicated("east", true, "east"))
+		Expect(errs).To(HaveLen(1))
+		Expect(errs[0].Field).To(Equal("spec.clusterReplication.highAvailability"))
+	})
+
+	It("rejects a primary missing from the cluster list and duplicate members", func() {
+		errs := v.validateTopology(replicated("north", false, "east", "west", "east"))
+		Expect(errs).To(HaveLen(2))
+		Expect(errs[0].Field).To(Equal("spec.clusterReplication.clusterList[2].name"))
+		Expect(errs[1].Field).To(Equal("spec.clusterReplication.primary"))
+	})
+})
+
+var _ = Describe("timeouts validation", func() {
+	var v *DocumentDBValidator
+
+	BeforeEach(func() { v = &DocumentDBValidator{} })
+
+	It("allows a stopDelay up to the CNPG maximum", func() {
+		db := newTestDocumentDB("", "", "")
+		db.Spec.Timeouts.StopDelay = util.CNPG_MAX_STOP_DELAY
+		Expect(v.validateTimeouts(db)).To(BeEmpty())
+	})
+
+	It("rejects a negative or too long stopDelay", func() {
+		db := newTestDocumentDB("", "", "")
+		db.Spec.Timeouts.StopDelay = -1
+		Expect(v.validateTimeouts(db)).To(HaveLen(1))
+
+		db.Spec.Timeouts.StopDelay = util.CNPG_MAX_STOP_DELAY + 1
+		errs := v.validateTimeouts(db)
+		Expect(errs).To(HaveLen(1))
+		Expect(errs[0].Field).To(Equal("spec.timeouts.stopDelay"))
+	})
+
+	It("rejects a negative startDelay", func() {
+		db := newTestDocumentDB("", "", "")
+		db.Spec.Timeouts.StartDelay = -1
+		errs := v.validateTimeouts(db)
+		Expect(errs).To(HaveLen(1))
+		Expect(errs[0].Field).To(Equal("spec.timeouts.startDelay"))
+	})
+
+	It("requires smartShutdownTimeout to be lower than stopDelay", func() {
+		db := newTestDocumentDB("", "", "")
+		db.Spec.Timeouts.SmartShutdownTimeout = ptr.To(int32(0))
+		Expect(v.validateTimeouts(db)).To(BeEmpty())
+
+		db.Spec.Timeouts.SmartShutdownTimeout = ptr.To(int32(util.CNPG_DEFAULT_STOP_DELAY))
+		errs := v.validateTimeouts(db)
+		Expect(errs).To(HaveLen(1))
+		Expect(errs[0].Field).To(Equal("spec.timeouts.smartShutdownTimeout"))
+
+		db.Spec.Timeouts.StopDelay = 300
+		db.Spec.Timeouts.SmartShutdownTimeout = ptr.To(int32(240))
+		Expect(v.validateTimeouts(db)).To(BeEmpty())
+	})
+
+	It("rejects a negative switchoverDelay or failoverDelay", func() {
+		db := newTestDocumentDB("", "", "")
+		db.Spec.Timeouts.SwitchoverDelay = -1
+		db.Spec.Timeouts.FailoverDelay = -1
+		errs := v.validateTimeouts(db)
+		Expect(errs).To(HaveLen(2))
+		Expect(errs[0].Field).To(Equal("spec.timeouts.switchoverDelay"))
+		Expect(errs[1].Field).To(Equal("spec.timeouts.failoverDelay"))
+	})
+})
+
+var _ = Describe("exposeViaService validation", func() {
+	var v *DocumentDBValidator
+
+	BeforeEach(func() { v = &DocumentDBValidator{} })
+
+	It("allows the rendered service types and no service", func() {
+		db := newTestDocumentDB("", "", "")
+		for _, serviceType := range []string{"", "LoadBalancer", "ClusterIP"} {
+			db.Spec.ExposeViaService.ServiceType = serviceType
+			Expect(v.validateExposeViaService(db)).To(BeEmpty())
+		}
+	})
+
+	It("rejects a service type the operator does not render", func() {
+		db := newTestDocumentDB("", "", "")
+		db.Spec.ExposeViaService.ServiceType = "NodePort"
+		errs := v.validateExposeViaService(db)
+		Expect(errs).To(HaveLen(1))
+		Expect(errs[0].Field).To(Equal("spec.exposeViaService.serviceType"))
+	})
+
+	It("allows a DNS name as hostname", func() {
+		db := newTestDocumentDB("", "", "")
+		db.Spec.ExposeViaService = dbpreview.ExposeViaService{ServiceType: "LoadBalancer", Hostname: "orders.db.example.com"}
+		Expect(v.validateExposeViaService(db)).To(BeEmpty())
+	})
+
+	It("rejects a hostname that is not a DNS name", func() {
+		db := newTestDocumentDB("", "", "")
+		db.Spec.ExposeViaService = dbpreview.ExposeViaService{ServiceType: "LoadBalancer", Hostname: "Orders_DB.example.com"}
+		errs := v.validateExposeViaService(db)
+		Expect(errs).NotTo(BeEmpty())
+		Expect(errs[0].Field).To(Equal("spec.exposeViaService.hostname"))
+	})
+})
+
+var _ = Describe("self-heal validation", func() {
+	var v *DocumentDBValidator
+
+	BeforeEach(func() { v = &DocumentDBValidator{} })
+
+	It("allows a reclone delay longer than the recreate delay", func() {
+		db := newTestDocumentDB("", "", "")
+		db.Spec.SelfHeal = &dbpreview.SelfHealConfiguration{RecreateAfterMinutes: 10, RecloneAfterMinutes: 60}
+		Expect(v.validateSelfHeal(db)).To(BeEmpty())
+
+		db.Spec.SelfHeal = &dbpreview.SelfHealConfiguration{RecloneAfterMinutes: 5}
+		Expect(v.validateSelfHeal(db)).To(BeEmpty())
+	})
+
+	It("rejects a reclone delay that is not longer than the recreate delay", func() {
+		db := newTestDocumentDB("", "", "")
+		db.Spec.SelfHeal = &dbpreview.SelfHealConfiguration{RecreateAfterMinutes: 30, RecloneAfterMinutes: 30}
+		errs := v.validateSelfHeal(db)
+		Expect(errs).To(HaveLen(1))
+		Expect(errs[0].Field).To(Equal("spec.selfHeal.recloneAfterMinutes"))
+	})
+})
+
+var _ = Describe("change streams validation", func() {
+	var v *DocumentDBValidator
+
+	BeforeEach(func() { v = &DocumentDBValidator{} })
+
+	It("requires the ChangeStreams feature gate", func() {
+		db := newTestDocumentDB("", "", "")
+		db.Spec.ChangeStreams = &dbpreview.ChangeStreamsConfiguration{MaxCursors: 5}
+		errs := v.validateChangeStreams(db)
+		Expect(errs).To(HaveLen(1))
+		Expect(errs[0].Field).To(Equal("spec.changeStreams"))
+
+		db.Spec.FeatureGates = map[string]bool{dbpreview.FeatureGateChangeStreams: true}
+		Expect(v.validateChangeStreams(db)).To(BeEmpty())
+	})
+})
+
+var _ = Describe("probe validation", func() {
+	var v *DocumentDBValidator
+
+	BeforeEach(func() { v = &DocumentDBValidator{} })
+
+	It("allows probe timings the pod spec accepts", func() {
+		db := newTestDocumentDB("", "", "")
+		db.Spec.Postgres = &dbpreview.PostgresSpec{Probes: &cnpgv1.ProbesConfiguration{
+			Readiness: &cnpgv1.ProbeWithStrategy{Probe: cnpgv1.Probe{SuccessThreshold: 2, FailureThreshold: 10}},
+		}}
+		db.Spec.Gateway = &dbpreview.GatewaySpec{Probes: &dbpreview.GatewayProbes{
+			Liveness: &cnpgv1.Probe{PeriodSeconds: 30, SuccessThreshold: 1},
+		}}
+		Expect(v.validateProbes(db)).To(BeEmpty())
+	})
+
+	It("rejects negative timings and a liveness success threshold above 1", func() {
+		db := newTestDocumentDB("", "", "")
+		db.Spec.Postgres = &dbpreview.PostgresSpec{Probes: &cnpgv1.ProbesConfiguration{
+			Startup: &cnpgv1.ProbeWithStrategy{Probe: cnpgv1.Probe{PeriodSeconds: -1}},
+		}}
+		db.Spec.Gateway = &dbpreview.GatewaySpec{Probes: &dbpreview.GatewayProbes{
+			Liveness: &cnpgv1.Probe{SuccessThreshold: 2},
+		}}
+		errs := v.validateProbes(db)
+		Expect(errs).To(HaveLen(2))
+		Expect(errs[0].Field).To(Equal("spec.postgres.probes.startup.periodSeconds"))
+		Expect(errs[1].Field).To(Equal("spec.gateway.probes.liveness.successThreshold"))
+	})
+})
+
+var _ = Describe("gateway port validation", func() {
+	var v *DocumentDBValidator
+
+	BeforeEach(func() { v = &DocumentDBValidator{} })
+
+	It("allows a free port", func() {
+		db := newTestDocumentDB("", "", "")
+		Expect(v.validateGatewayPort(db)).To(BeEmpty())
+
+		db.Spec.Gateway = &dbpreview.GatewaySpec{Port: 27017}
+		Expect(v.validateGatewayPort(db)).To(BeEmpty())
+	})
+
+	It("rejects a port another container of the pods uses", func() {
+		db := newTestDocumentDB("", "", "")
+		for _, port := range []int32{5432, 8000, 9187, 8445} {
+			db.Spec.Gateway = &dbpreview.GatewaySpec{Port: port}
+			errs := v.validateGatewayPort(db)
+			Expect(errs).To(HaveLen(1))
+			Expect(errs[0].Field).To(Equal("spec.gateway.port"))
+		}
+	})
+
+	It("rejects a Prometheus exporter on the gateway port", func() {
+		db := newTestDocumentDB("", "", "")
+		db.Spec.Gateway = &dbpreview.GatewaySpec{Port: 8888}
+		db.Spec.Monitoring = &dbpreview.MonitoringSpec{
+			Enabled:  true,
+			Exporter: &dbpreview.ExporterSpec{Prometheus: &dbpreview.PrometheusExporterSpec{}},
+		}
+		errs := v.validateGatewayPort(db)
+		Expect(errs).To(HaveLen(1))
+		Expect(errs[0].Field).To(Equal("spec.monitoring.exporter.prometheus.port"))
+	})
+})
+
+var _ = Describe("cost label validation", func() {
+	var v *DocumentDBValidator
+
+	BeforeEach(func() { v = &DocumentDBValidator{} })
+
+	It("allows valid labels", func() {
+		db := newTestDocumentDB("", "", "")
+		db.Spec.CostLabels = map[string]string{"team": "payments", "example.com/cost-center": "cc-42"}
+		Expect(v.validateCostLabels(db)).To(BeEmpty())
+	})
+
+	It("rejects an invalid key and value", func() {
+		db := newTestDocumentDB("", "", "")
+		db.Spec.CostLabels = map[string]string{"bad key": "ok", "team": "not a valid value"}
+		Expect(v.validateCostLabels(db)).To(HaveLen(2))
+	})
+})
+
+var _ = Describe("inherited metadata validation", func() {
+	var v *DocumentDBValidator
+
+	BeforeEach(func() { v = &DocumentDBValidator{} })
+
+	It("allows valid labels and annotations", func() {
+		db := newTestDocumentDB("", "", "")
+		db.Spec.InheritedMetadata = &dbpreview.InheritedMetadata{
+			Labels:      map[string]string{"tier": "gold"},
+			Annotations: map[string]string{"sidecar.istio.io/inject": "true"},
+		}
+		Expect(v.validateInheritedMetadata(db)).To(BeEmpty())
+	})
+
+	It("rejects an invalid label and annotation key", func() {
+		db := newTestDocumentDB("", "", "")
+		db.Spec.InheritedMetadata = &dbpreview.InheritedMetadata{
+			Labels:      map[string]string{"bad key": "gold"},
+			Annotations: map[string]string{"bad key": "true"},
+		}
+		Expect(v.validateInheritedMetadata(db)).To(HaveLen(2))
+	})
+})
+
+var _ = Describe("service account validation", func() {
+	var v *DocumentDBValidator
+
+	BeforeEach(func() { v = &DocumentDBValidator{} })
+
+	It("allows workload identity annotations", func() {
+		db := newTestDocumentDB("", "", "")
+		db.Spec.ServiceAccount = &dbpreview.ServiceAccountSpec{
+			Annotations: map[string]string{"eks.amazonaws.com/role-arn": "arn:aws:iam::123456789012:role/documentdb"},
+			Labels:      map[string]string{"azure.workload.identity/use": "true"},
+		}
+		Expect(v.validateServiceAccount(db)).To(BeEmpty())
+	})
+
+	It("rejects an invalid label value", func() {
+		db := newTestDocumentDB("", "", "")
+		db.Spec.ServiceAccount = &dbpreview.ServiceAccountSpec{Labels: map[string]string{"team": "not valid"}}
+		Expect(v.validateServiceAccount(db)).To(HaveLen(1))
+	})
+
+	It("accepts the identity of each provider", func() {
+		db := newTestDocumentDB("", "", "")
+		for provider, identity := range map[dbpreview.WorkloadIdentityProvider]string{
+			dbpreview.WorkloadIdentityAWS:   "arn:aws:iam::123456789012:role/documentdb",
+			dbpreview.WorkloadIdentityAzure: "00000000-1111-2222-3333-444444444444",
+			dbpreview.WorkloadIdentityGCP:   "documentdb@my-project.iam.gserviceaccount.com",
+		} {
+			db.Spec.ServiceAccount = &dbpreview.ServiceAccountSpec{
+				WorkloadIdentity: &dbpreview.WorkloadIdentitySpec{Provider: provider, Identity: identity},
+			}
+			Expect(v.validateServiceAccount(db)).To(BeEmpty(), string(provider))
+		}
+	})
+
+	It("rejects an identity in the wrong format for its provider", func() {
+		db := newTestDocumentDB("", "", "")
+		db.Spec.ServiceAccount = &dbpreview.ServiceAccountSpec{
+			WorkloadIdentity: &dbpreview.WorkloadIdentitySpec{Provider: dbpreview.WorkloadIdentityAzure, Identity: "documentdb"},
+		}
+		errs := v.validateServiceAccount(db)
+		Expect(errs).To(HaveLen(1))
+		Expect(errs[0].Field).To(Equal("spec.serviceAccount.workloadIdentity.identity"))
+	})
+
+	It("rejects an annotation contradicting the workload identity", func() {
+		db := newTestDocumentDB("", "", "")
+		db.Spec.ServiceAccount = &dbpreview.ServiceAccountSpec{
+			Annotations:      map[string]string{"eks.amazonaws.com/role-arn": "arn:aws:iam::123456789012:role/other"},
+			WorkloadIdentity: &dbpreview.WorkloadIdentitySpec{Provider: dbpreview.WorkloadIdentityAWS, Identity: "arn:aws:iam::123456789012:role/documentdb"},
+		}
+		errs := v.validateServiceAccount(db)
+		Expect(errs).To(HaveLen(1))
+		Expect(errs[0].Field).To(Equal("spec.serviceAccount.annotations[eks.amazonaws.com/role-arn]"))
+	})
+
+	It("rejects object stores the workload identity cannot access", func() {
+		db := newTestDocumentDB("", "", "")
+		db.Spec.ServiceAccount = &dbpreview.ServiceAccountSpec{
+			WorkloadIdentity: &dbpreview.WorkloadIdentitySpec{Provider: dbpreview.WorkloadIdentityGCP, Identity: "documentdb@my-project.iam.gserviceaccount.com"},
+		}
+		db.Spec.Bootstrap = &dbpreview.BootstrapConfiguration{Recovery: &dbpreview.RecoveryConfiguration{
+			ObjectStore: &dbpreview.ObjectStoreRecoveryConfiguration{DestinationPath: "s3://backups", ServerName: "orders"},
+		}}
+		db.Spec.Export = &dbpreview.ExportConfiguration{Schedule: "0 2 * * *", Destination: dbpreview.ExportDestination{DestinationPath: "s3://exports"}}
+		errs := v.validateServiceAccount(db)
+		Expect(errs).To(HaveLen(2))
+		Expect(errs[0].Field).To(Equal("spec.bootstrap.recovery.objectStore.destinationPath"))
+		Expect(errs[1].Field).To(Equal("spec.export.destination.credentialsSecret"))
+
+		db.Spec.Bootstrap.Recovery.ObjectStore.DestinationPath = "gs://backups"
+		db.Spec.Export.Destination.CredentialsSecret = &cnpgv1.LocalObjectReference{Name: "s3-credentials"}
+		Expect(v.validateServiceAccount(db)).To(BeEmpty())
+	})
+})
+
+var _ = Describe("additional services validation", func() {
+	var v *DocumentDBValidator
+
+	BeforeEach(func() { v = &DocumentDBValidator{} })
+
+	service := func(name string) cnpgv1.ManagedService {
+		return cnpgv1.ManagedService{
+			SelectorType:    cnpgv1.ServiceSelectorTypeRO,
+			ServiceTemplate: cnpgv1.ServiceTemplateSpec{ObjectMeta: cnpgv1.Metadata{Name: name}},
+		}
+	}
+
+	It("accepts uniquely named services", func() {
+		db := newTestDocumentDB("", "", "")
+		db.Spec.AdditionalServices = []cnpgv1.ManagedService{service("test-db-reads"), service("test-db-reporting")}
+		Expect(v.validateAdditionalServices(db)).To(BeEmpty())
+	})
+
+	It("rejects missing, duplicate and operator-owned names", func() {
+		db := newTestDocumentDB("", "", "")
+		db.Spec.AdditionalServices = []cnpgv1.ManagedService{
+			service(""),
+			service("test-db-reads"),
+			service("test-db-reads"),
+			service(util.DocumentDBServiceName(db)),
+		}
+		errs := v.validateAdditionalServices(db)
+		Expect(errs).To(HaveLen(3))
+		Expect(errs[0].Field).To(Equal("spec.additionalServices[0].serviceTemplate.metadata.name"))
+		Expect(errs[1].Field).To(Equal("spec.additionalServices[2].serviceTemplate.metadata.name"))
+		Expect(errs[2].Field).To(Equal("spec.additionalServices[3].serviceTemplate.metadata.name"))
+	})
+})
+
+var _ = Describe("plugins validation", func() {
+	var v *DocumentDBValidator
+
+	BeforeEach(func() { v = &DocumentDBValidator{} })
+
+	It("accepts additional plugins", func() {
+		db := newTestDocumentDB("", "", "")
+		db.Spec.Plugins = &dbpreview.PluginsSpec{Additional: []cnpgv1.PluginConfiguration{
+			{Name: "barman-cloud.cloudnative-pg.io", IsWALArchiver: ptr.To(true), Parameters: map[string]string{"barmanObjectName": "store"}},
+			{Name: "pgaudit.example.com"},
+		}}
+		Expect(v.validatePlugins(db)).To(BeEmpty())
+	})
+
+	It("rejects plugins the operator configures, duplicates and a second WAL archiver", func() {
+		db := newTestDocumentDB("", "", "")
+		db.Spec.Plugins = &dbpreview.PluginsSpec{Additional: []cnpgv1.PluginConfiguration{
+			{Name: util.DEFAULT_SIDECAR_INJECTOR_PLUGIN},
+			{Name: "barman-cloud.cloudnative-pg.io", IsWALArchiver: ptr.To(true)},
+			{Name: "barman-cloud.cloudnative-pg.io"},
+			{Name: "other-archiver.example.com", IsWALArchiver: ptr.To(true)},
+		}}
+		errs := v.validatePlugins(db)
+		Expect(errs).To(HaveLen(3))
+		Expect(errs[0].Field).To(Equal("spec.plugins.additional[0].name"))
+		Expect(errs[1].Field).To(Equal("spec.plugins.additional[2].name"))
+		Expect(errs[2].Field).To(Equal("spec.plugins.additional[3].isWALArchiver"))
+	})
+})
+
+var _ = Describe("security context validation", func() {
+	var v *DocumentDBValidator
+
+	BeforeEach(func() { v = &DocumentDBValidator{} })
+
+	It("allows a PodSecurity restricted override", func() {
+		db := newTestDocumentDB("", "", "")
+		uid := int64(1000700000)
+		db.Spec.SecurityContext = &dbpreview.SecurityContextSpec{
+			RunAsUser:      &uid,
+			FSGroup:        &uid,
+			SeccompProfile: &corev1.SeccompProfile{Type: corev1.SeccompProfileTypeRuntimeDefault},
+		}
+		Expect(v.validateSecurityContext(db)).To(BeEmpty())
+	})
+
+	It("rejects an identity that conflicts with spec.postgres", func() {
+		db := newTestDocumentDB("", "", "")
+		uid, gid := int64(1001), int64(1002)
+		db.Spec.Postgres = &dbpreview.PostgresSpec{UID: &uid, GID: &gid}
+		db.Spec.SecurityContext = &dbpreview.SecurityContextSpec{RunAsUser: &uid, RunAsGroup: &gid}
+		Expect(v.validateSecurityContext(db)).To(HaveLen(2))
+	})
+
+	It("requires the profile of a Localhost seccomp profile", func() {
+		db := newTestDocumentDB("", "", "")
+		db.Spec.SecurityContext = &dbpreview.SecurityContextSpec{
+			SeccompProfile: &corev1.SeccompProfile{Type: corev1.SeccompProfileTypeLocalhost},
+		}
+		errs := v.validateSecurityContext(db)
+		Expect(errs).To(HaveLen(1))
+		Expect(errs[0].Field).To(Equal("spec.securityContext.seccompProfile.localhostProfile"))
+	})
+
+	It("rejects fixed IDs and non-default seccomp profiles in OpenShift mode", func() {
+		GinkgoT().Setenv(util.OPENSHIFT_ENV, "true")
+		db := newTestDocumentDB("", "", "")
+		uid := int64(1000700000)
+		db.Spec.SecurityContext = &dbpreview.SecurityContextSpec{
+			RunAsUser:      &uid,
+			FSGroup:        &uid,
+			SeccompProfile: &corev1.SeccompProfile{Type: corev1.SeccompProfileTypeUnconfined},
+		}
+		Expect(v.validateOpenShift(db)).To(HaveLen(3))
+	})
+})
+
+var _ = Describe("OpenShift validation", func() {
+	var v *DocumentDBValidator
+
+	BeforeEach(func() { v = &DocumentDBValidator{} })
+
+	newRestrictedDocumentDB := func() *dbpreview.DocumentDB {
+		db := newTestDocumentDB("", "", "")
+		uid, gid := int64(1001), int64(1002)
+		db.Spec.Postgres = &dbpreview.PostgresSpec{UID: &uid, GID: &gid}
+		db.Spec.FeatureGates = map[string]bool{dbpreview.FeatureGateIOUring: true}
+		return db
+	}
+
+	It("allows a fixed identity and io_uring outside OpenShift", func() {
+		Expect(v.validateOpenShift(newRestrictedDocumentDB())).To(BeEmpty())
+	})
+
+	It("rejects a fixed identity and io_uring in OpenShift mode", func() {
+		GinkgoT().Setenv(util.OPENSHIFT_ENV, "true")
+		errs := v.validateOpenShift(newRestrictedDocumentDB())
+		Expect(errs).To(HaveLen(3))
+		Expect(errs.ToAggregate().Error()).To(ContainSubstring("spec.postgres.uid"))
+		Expect(errs.ToAggregate().Error()).To(ContainSubstring("spec.featureGates[IOUring]"))
+	})
+})
+
+var _ = Describe("namespace quota validation", func() {
+	var ctx context.Context
+
+	BeforeEach(func() {
+		ctx = context.Background()
+		DeferCleanup(func() { util.SetOperatorSettings(nil) })
+	})
+
+	existingDB := func(name, pvcSize string, instances int) *dbpreview.DocumentDB {
+		db := newTestDocumentDB("", "", "")
+		db.Name = name
+		db.Spec.InstancesPerNode = instances
+		db.Spec.Resource.Storage.PvcSize = pvcSize
+		return db
+	}
+
+	It("does not list DocumentDBs when no limit is configured", func() {
+		v := &DocumentDBValidator{}
+		Expect(v.validateNamespaceQuota(ctx, newTestDocumentDB("", "", ""), nil)).To(BeEmpty())
+	})
+
+	It("rejects a cluster beyond the maximum number of clusters", func() {
+		util.SetOperatorSettings(map[string]string{util.MAX_CLUSTERS_PER_NAMESPACE_ENV: "2"})
+		v := newTestValidator(existingDB("db-1", "10Gi", 1), existingDB("db-2", "10Gi", 1))
+
+		errs := v.validateNamespaceQuota(ctx, newTestDocumentDB("", "", ""), nil)
+		Expect(errs).To(HaveLen(1))
+		Expect(errs[0].Detail).To(ContainSubstring("already has 2 DocumentDB clusters"))
+	})
+
+	It("allows a cluster within the maximum number of clusters", func() {
+		util.SetOperatorSettings(map[string]string{util.MAX_CLUSTERS_PER_NAMESPACE_ENV: "2"})
+		v := newTestValidator(existingDB("db-1", "10Gi", 1))
+		Expect(v.validateNamespaceQuota(ctx, newTestDocumentDB("", "", ""), nil)).To(BeEmpty())
+	})
+
+	It("counts the storage of every instance against the storage limit", func() {
+		util.SetOperatorSettings(map[string]string{util.MAX_STORAGE_PER_NAMESPACE_ENV: "100Gi"})
+		v := newTestValidator(existingDB("db-1", "30Gi", 3))
+
+		// 90Gi already requested, plus 10Gi
+		Expect(v.validateNamespaceQuota(ctx, newTestDocumentDB("", "", ""), nil)).To(BeEmpty())
+
+		db := newTestDocumentDB("", "", "")
+		db.Spec.Resource.Storage.PvcSize = "11Gi"
+		errs := v.validateNamespaceQuota(ctx, db, nil)
+		Expect(errs).To(HaveLen(1))
+		Expect(errs[0].Field).To(Equal("spec.resource.storage.pvcSize"))
+	})
+
+	It("only checks storage increases on update", func() {
+		util.SetOperatorSettings(map[string]string{
+			util.MAX_CLUSTERS_PER_NAMESPACE_ENV: "1",
+			util.MAX_STORAGE_PER_NAMESPACE_ENV:  "50Gi",
+		})
+		oldDB := newTestDocumentDB("", "", "")
+		v := newTestValidator(oldDB.DeepCopy(), existingDB("db-1", "60Gi", 1))
+
+		// The namespace is already over both limits, but the cluster does not grow
+		Expect(v.validateNamespaceQuota(ctx, oldDB.DeepCopy(), oldDB)).To(BeEmpty())
+
+		grown := oldDB.DeepCopy()
+		grown.Spec.Resource.Storage.PvcSize = "20Gi"
+		Expect(v.validateNamespaceQuota(ctx, grown, oldDB)).To(HaveLen(1))
+	})
+})
+
+var _ = Describe("export validation", func() {
+	var v *DocumentDBValidator
+
+	BeforeEach(func() { v = &DocumentDBValidator{} })
+
+	It("allows a cron schedule", func() {
+		db := newTestDocumentDB("", "", "")
+		db.Spec.Export = &dbpreview.ExportConfiguration{Schedule: "0 2 * * *"}
+		Expect(v.validateExport(db)).To(BeEmpty())
+	})
+
+	It("rejects an invalid schedule", func() {
+		db := newTestDocumentDB("", "", "")
+		db.Spec.Export = &dbpreview.ExportConfiguration{Schedule: "every night"}
+		Expect(v.validateExport(db)).To(HaveLen(1))
+	})
+})
+
+var _ = Describe("clone validation", func() {
+	var v *DocumentDBValidator
+
+	BeforeEach(func() { v = &DocumentDBValidator{} })
+
+	It("allows a clone of another cluster", func() {
+		db := newTestDocumentDB("", "", "")
+		db.Spec.Bootstrap = &dbpreview.BootstrapConfiguration{
+			Clone: &dbpreview.CloneConfiguration{SourceRef: dbpreview.CloneSourceReference{Name: "source"}},
+		}
+		Expect(v.validateClone(db)).To(BeEmpty())
+	})
+
+	It("rejects a clone of the cluster itself", func() {
+		db := newTestDocumentDB("", "", "")
+		db.Spec.Bootstrap = &dbpreview.BootstrapConfiguration{
+			Clone: &dbpreview.CloneConfiguration{SourceRef: dbpreview.CloneSourceReference{Name: db.Name, Namespace: db.Namespace}},
+		}
+		Expect(v.validateClone(db)).To(HaveLen(1))
+	})
+})
+
+var _ = Describe("recovery target validation", func() {
+	var v *DocumentDBValidator
+
+	BeforeEach(func() { v = &DocumentDBValidator{} })
+
+	withTarget := func(target cnpgv1.RecoveryTarget, objectStore bool) *dbpreview.DocumentDB {
+		db := newTestDocumentDB("", "", "")
+		db.Spec.Bootstrap = &dbpreview.BootstrapConfiguration{
+			Recovery: &dbpreview.RecoveryConfiguration{
+				Backup:         cnpgv1.LocalObjectReference{Name: "nightly"},
+				RecoveryTarget: &target,
+			},
+		}
+		if objectStore {
+			db.Spec.Bootstrap.Recovery.Backup = cnpgv1.LocalObjectReference{}
+			db.Spec.Bootstrap.Recovery.ObjectStore = &dbpreview.ObjectStoreRecoveryConfiguration{DestinationPath: "s3://backups", ServerName: "orders"}
+		}
+		return db
+	}
+
+	It("accepts a single target", func() {
+		Expect(v.validateRecoveryTarget(withTarget(cnpgv1.RecoveryTarget{TargetTime: "2026-05-01T10:00:00Z"}, true))).To(BeEmpty())
+		Expect(v.validateRecoveryTarget(withTarget(cnpgv1.RecoveryTarget{TargetName: "before-upgrade", Exclusive: ptr.To(true)}, false))).To(BeEmpty())
+		Expect(v.validateRecoveryTarget(withTarget(cnpgv1.RecoveryTarget{TargetImmediate: ptr.To(true), BackupID: "20260501T100000"}, true))).To(BeEmpty())
+	})
+
+	It("rejects several targets", func() {
+		errs := v.validateRecoveryTarget(withTarget(cnpgv1.RecoveryTarget{TargetTime: "2026-05-01T10:00:00Z", TargetLSN: "0/3000060"}, false))
+		Expect(errs).To(HaveLen(1))
+		Expect(errs[0].Field).To(Equal("spec.bootstrap.recovery.recoveryTarget"))
+	})
+
+	It("rejects malformed targets", func() {
+		errs := v.validateRecoveryTarget(withTarget(cnpgv1.RecoveryTarget{TargetTime: "yesterday"}, false))
+		Expect(errs).To(HaveLen(1))
+		Expect(errs[0].Field).To(Equal("spec.bootstrap.recovery.recoveryTarget.targetTime"))
+
+		errs = v.validateRecoveryTarget(withTarget(cnpgv1.RecoveryTarget{TargetLSN: "not-an-lsn"}, false))
+		Expect(errs).To(HaveLen(1))
+		Expect(errs[0].Field).To(Equal("spec.bootstrap.recovery.recoveryTarget.targetLSN"))
+	})
+
+	It("requires the backup ID of a named target in an object store", func() {
+		errs := v.validateRecoveryTarget(withTarget(cnpgv1.RecoveryTarget{TargetName: "before-upgrade"}, true))
+		Expect(errs).To(HaveLen(1))
+		Expect(errs[0].Field).To(Equal("spec.bootstrap.recovery.recoveryTarget.backupID"))
+	})
+})
+
+var _ = Describe("migration validation", func() {
+	var v *DocumentDBValidator
+
+	BeforeEach(func() { v = &DocumentDBValidator{} })
+
+	withMigration := func(source string, cutover bool) *dbpreview.DocumentDB {
+		db := newTestDocumentDB("", "", "")
+		db.Spec.Migration = &dbpreview.MigrationConfiguration{
+			SourceRef: cnpgv1.LocalObjectReference{Name: source},
+			Cutover:   cutover,
+		}
+		return db
+	}
+
+	It("rejects a migration from the cluster itself", func() {
+		db := withMigration("", false)
+		db.Spec.Migration.SourceRef.Name = db.Name
+		Expect(v.validateMigration(db)).To(HaveLen(1))
+		Expect(v.validateMigration(withMigration("old", false))).To(BeEmpty())
+	})
+
+	It("allows setting cutover and removing the migration", func() {
+		Expect(v.validateMigrationChanges(withMigration("old", true), withMigration("old", false))).To(BeEmpty())
+		Expect(v.validateMigrationChanges(newTestDocumentDB("", "", ""), withMigration("old", true))).To(BeEmpty())
+	})
+
+	It("rejects adding a migration after creation", func() {
+		errs := v.validateMigrationChanges(withMigration("old", false), newTestDocumentDB("", "", ""))
+		Expect(errs).To(HaveLen(1))
+		Expect(errs[0].Field).To(Equal("spec.migration"))
+	})
+
+	It("rejects changing the source or unsetting cutover", func() {
+		errs := v.validateMigrationChanges(withMigration("other", false), withMigration("old", true))
+		Expect(errs).To(HaveLen(2))
+	})
+})
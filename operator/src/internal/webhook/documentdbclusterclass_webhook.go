@@ -0,0 +1,118 @@
+// Copyright (c) Microsoft Corporation.
+// Licensed under the MIT License.
+
+package webhook
+
+import (
+	"context"
+	"fmt"
+
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/util/validation/field"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	logf "sigs.k8s.io/controller-runtime/pkg/log"
+	"sigs.k8s.io/controller-runtime/pkg/webhook/admission"
+
+	dbpreview "github.com/documentdb/documentdb-operator/api/preview"
+	util "github.com/documentdb/documentdb-operator/internal/utils"
+)
+
+var clusterClassLog = logf.Log.WithName("documentdbclusterclass-webhook")
+
+// DocumentDBClusterClassValidator validates the updates of
+// DocumentDBClusterClass resources. The fields a DocumentDB leaves unset take
+// the values of its class, so an edit of the class changes the DocumentDBs
+// that reference it as an edit of their own spec would, and goes through the
+// same update validations.
+type DocumentDBClusterClassValidator struct {
+	client.Client
+}
+
+var _ admission.Validator[*dbpreview.DocumentDBClusterClass] = &DocumentDBClusterClassValidator{}
+
+// SetupWebhookWithManager registers the validating webhook with the manager.
+func (v *DocumentDBClusterClassValidator) SetupWebhookWithManager(mgr ctrl.Manager) error {
+	v.Client = mgr.GetClient()
+	return ctrl.NewWebhookManagedBy(mgr, &dbpreview.DocumentDBClusterClass{}).
+		WithValidator(v).
+		Complete()
+}
+
+// NOTE: The kubebuilder marker below is used for local development with `make run`.
+// For Helm-based deployments, the authoritative webhook configuration is in
+// operator/documentdb-helm-chart/templates/10_documentdb_webhook.yaml.
+// +kubebuilder:webhook:path=/validate-documentdb-io-preview-documentdbclusterclass,mutating=false,failurePolicy=fail,sideEffects=None,groups=documentdb.io,resources=documentdbclusterclasses,verbs=update,versions=preview,name=vdocumentdbclusterclass.kb.io,admissionReviewVersions=v1
+
+// ValidateCreate is a no-op for DocumentDBClusterClass: no DocumentDB
+// references a class before it exists.
+func (v *DocumentDBClusterClassValidator) ValidateCreate(_ context.Context, _ *dbpreview.DocumentDBClusterClass) (admission.Warnings, error) {
+	return nil, nil
+}
+
+// ValidateUpdate validates the update of a DocumentDBClusterClass against the
+// DocumentDBs that reference it, comparing the spec each of them resolves
+// with the class before and after the update.
+func (v *DocumentDBClusterClassValidator) ValidateUpdate(ctx context.Context, oldClass, newClass *dbpreview.DocumentDBClusterClass) (admission.Warnings, error) {
+	clusterClassLog.Info("Validation for DocumentDBClusterClass upon update", "name", newClass.Name)
+
+	documentdbs := &dbpreview.DocumentDBList{}
+	if err := v.List(ctx, documentdbs); err != nil {
+		return nil, apierrors.NewInternalError(fmt.Errorf("failed to list the DocumentDBs of the class: %w", err))
+	}
+	var allErrs field.ErrorList
+	for i := range documentdbs.Items {
+		db := &documentdbs.Items[i]
+		if db.Spec.ClassRef == nil || db.Spec.ClassRef.Name != newClass.Name {
+			continue
+		}
+		for _, err := range v.validateDocumentDB(ctx, db, oldClass, newClass) {
+			allErrs = append(allErrs, field.Forbidden(field.NewPath("spec"),
+				fmt.Sprintf("DocumentDB %s/%s: %s", db.Namespace, db.Name, err.Error())))
+		}
+	}
+	if len(allErrs) == 0 {
+		return nil, nil
+	}
+	return nil, apierrors.NewInvalid(
+		schema.GroupKind{Group: "documentdb.io", Kind: "DocumentDBClusterClass"},
+		newClass.Name, allErrs)
+}
+
+// ValidateDelete is a no-op for DocumentDBClusterClass; the controller reports
+// the missing class on the DocumentDBs that reference it.
+func (v *DocumentDBClusterClassValidator) ValidateDelete(_ context.Context, _ *dbpreview.DocumentDBClusterClass) (admission.Warnings, error) {
+	return nil, nil
+}
+
+// validateDocumentDB runs the update validations of DocumentDB on db resolved
+// with oldClass and with newClass. A DocumentDB that could not resolve
+// oldClass is not running with it and is not validated.
+func (v *DocumentDBClusterClassValidator) validateDocumentDB(ctx context.Context, db *dbpreview.DocumentDB, oldClass, newClass *dbpreview.DocumentDBClusterClass) field.ErrorList {
+	oldDB, newDB := db.DeepCopy(), db.DeepCopy()
+	if err := oldDB.Spec.ApplyClusterClass(oldClass); err != nil {
+		return nil
+	}
+	if err := newDB.Spec.ApplyClusterClass(newClass); err != nil {
+		return field.ErrorList{field.Invalid(field.NewPath("spec", "classRef"), db.Spec.ClassRef.Size, err.Error())}
+	}
+	// The namespace defaults fill what neither the DocumentDB nor the class sets
+	if err := util.ApplyNamespaceDefaults(ctx, v.Client, oldDB); err == nil {
+		newDB.Status.NamespaceDefaults = oldDB.Status.NamespaceDefaults
+		_ = util.ApplyNamespaceDefaults(ctx, v.Client, newDB)
+	}
+
+	documentdbValidator := &DocumentDBValidator{Client: v.Client}
+	allErrs := append(
+		documentdbValidator.validateImageRollback(newDB, oldDB),
+		documentdbValidator.validateImmutableFields(newDB, oldDB)...,
+	)
+	allErrs = append(allErrs, documentdbValidator.validateStorageResize(newDB, oldDB)...)
+	// The CRD rejects the changes of the storage class set on the DocumentDB
+	if oldStorageClass, newStorageClass := oldDB.Spec.Resource.Storage.StorageClass, newDB.Spec.Resource.Storage.StorageClass; oldStorageClass != newStorageClass {
+		allErrs = append(allErrs, field.Forbidden(field.NewPath("spec", "resource", "storage", "storageClass"),
+			fmt.Sprintf("storage class cannot be changed after cluster creation, from %q to %q", oldStorageClass, newStorageClass)))
+	}
+	return allErrs
+}
@@ -0,0 +1,102 @@
+// Copyright (c) Microsoft Corporation.
+// Licensed under the MIT License.
+
+package webhook
+
+import (
+	"context"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	dbpreview "github.com/documentdb/documentdb-operator/api/preview"
+)
+
+var _ = Describe("DocumentDBClusterClass validation", func() {
+	var (
+		ctx      context.Context
+		oldClass *dbpreview.DocumentDBClusterClass
+		newClass *dbpreview.DocumentDBClusterClass
+		db       *dbpreview.DocumentDB
+	)
+
+	validator := func(objs ...*dbpreview.DocumentDB) *DocumentDBClusterClassValidator {
+		scheme := runtime.NewScheme()
+		Expect(dbpreview.AddToScheme(scheme)).To(Succeed())
+		Expect(corev1.AddToScheme(scheme)).To(Succeed())
+		builder := fake.NewClientBuilder().WithScheme(scheme)
+		for _, obj := range objs {
+			builder = builder.WithObjects(obj)
+		}
+		return &DocumentDBClusterClassValidator{Client: builder.Build()}
+	}
+
+	BeforeEach(func() {
+		ctx = context.Background()
+		oldClass = &dbpreview.DocumentDBClusterClass{
+			ObjectMeta: metav1.ObjectMeta{Name: "standard"},
+			Spec: dbpreview.DocumentDBClusterClassSpec{
+				Sizes:             []dbpreview.ClusterClassSize{{Name: "small", PvcSize: "10Gi"}, {Name: "large", PvcSize: "100Gi"}},
+				DefaultSize:       "small",
+				DocumentDBVersion: "0.112.0",
+				StorageClass:      "premium",
+			},
+		}
+		newClass = oldClass.DeepCopy()
+		db = &dbpreview.DocumentDB{
+			ObjectMeta: metav1.ObjectMeta{Name: "orders", Namespace: "default"},
+			Spec: dbpreview.DocumentDBSpec{
+				NodeCount: 1,
+				ClassRef:  &dbpreview.ClusterClassReference{Name: "standard"},
+			},
+			Status: dbpreview.DocumentDBStatus{SchemaVersion: "0.112.0"},
+		}
+	})
+
+	It("allows an edit that grows the storage and upgrades the clusters", func() {
+		newClass.Spec.Sizes[0].PvcSize = "20Gi"
+		newClass.Spec.DocumentDBVersion = "0.113.0"
+		_, err := validator(db).ValidateUpdate(ctx, oldClass, newClass)
+		Expect(err).ToNot(HaveOccurred())
+	})
+
+	It("rejects an edit that shrinks the storage of a cluster", func() {
+		newClass.Spec.Sizes[0].PvcSize = "5Gi"
+		_, err := validator(db).ValidateUpdate(ctx, oldClass, newClass)
+		Expect(err).To(MatchError(ContainSubstring("DocumentDB default/orders")))
+		Expect(err).To(MatchError(ContainSubstring("pvcSize")))
+	})
+
+	It("rejects an edit that rolls a cluster back below its schema version", func() {
+		newClass.Spec.DocumentDBVersion = "0.110.0"
+		_, err := validator(db).ValidateUpdate(ctx, oldClass, newClass)
+		Expect(err).To(MatchError(ContainSubstring("image rollback blocked")))
+	})
+
+	It("rejects an edit that changes the storage class of a cluster", func() {
+		newClass.Spec.StorageClass = "standard"
+		_, err := validator(db).ValidateUpdate(ctx, oldClass, newClass)
+		Expect(err).To(MatchError(ContainSubstring("storage class cannot be changed")))
+	})
+
+	It("rejects an edit that removes the size of a cluster", func() {
+		db.Spec.ClassRef.Size = "large"
+		newClass.Spec.Sizes = newClass.Spec.Sizes[:1]
+		_, err := validator(db).ValidateUpdate(ctx, oldClass, newClass)
+		Expect(err).To(MatchError(ContainSubstring(`has no size "large"`)))
+	})
+
+	It("ignores the fields a cluster sets itself and the clusters of other classes", func() {
+		db.Spec.Resource.Storage.PvcSize = "50Gi"
+		other := db.DeepCopy()
+		other.Name = "payments"
+		other.Spec.ClassRef.Name = "premium"
+		newClass.Spec.Sizes[0].PvcSize = "5Gi"
+		_, err := validator(db, other).ValidateUpdate(ctx, oldClass, newClass)
+		Expect(err).ToNot(HaveOccurred())
+	})
+})